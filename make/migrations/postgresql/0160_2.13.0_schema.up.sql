@@ -0,0 +1,86 @@
+/*
+Add new columns to the audit_log table to record the details of the mutating API operations
+*/
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS operation_description text;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS is_successful boolean default true;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS payload text;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS client_ip varchar(255);
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS user_agent varchar(512);
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS auth_method varchar(255);
+
+/*
+Add new columns to the notification_policy table to store the encrypted secrets signing the webhook payloads
+*/
+ALTER TABLE notification_policy ADD COLUMN IF NOT EXISTS signing_secret varchar(1024);
+ALTER TABLE notification_policy ADD COLUMN IF NOT EXISTS previous_signing_secret varchar(1024);
+ALTER TABLE notification_policy ADD COLUMN IF NOT EXISTS previous_secret_expires_at timestamp;
+
+/*
+Add new column to the notification_policy table to store the filters narrowing down the events of the webhook policy
+*/
+ALTER TABLE notification_policy ADD COLUMN IF NOT EXISTS filters text;
+
+/*
+Add new column to the replication_policy table to indicate whether the accessories must be replicated before the tags
+*/
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS require_accessories boolean DEFAULT false;
+
+/*
+Add new column to the replication_policy table to indicate whether the destination is reconciled with the source periodically
+*/
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS reconcile boolean DEFAULT false;
+
+/*
+Add new columns to limit the bandwidth and the concurrency of the replication
+*/
+ALTER TABLE replication_policy ADD COLUMN IF NOT EXISTS bandwidth_windows text;
+ALTER TABLE registry ADD COLUMN IF NOT EXISTS speed_kb int DEFAULT 0;
+ALTER TABLE registry ADD COLUMN IF NOT EXISTS bandwidth_windows text;
+ALTER TABLE registry ADD COLUMN IF NOT EXISTS max_concurrent_tasks int DEFAULT 0;
+
+/*
+Add the proxy_cache_prefetch_policy table to store the repositories pre-warmed periodically and the tags pinned in the proxy cache projects
+*/
+CREATE TABLE IF NOT EXISTS proxy_cache_prefetch_policy (
+    id SERIAL PRIMARY KEY NOT NULL,
+    project_id int NOT NULL,
+    enabled boolean DEFAULT true,
+    cron varchar(64),
+    rules text,
+    pinned_tags text,
+    creation_time timestamp default CURRENT_TIMESTAMP,
+    update_time timestamp default CURRENT_TIMESTAMP,
+    UNIQUE (project_id)
+);
+
+/*
+Add the vex_statement table to store the OpenVEX and CycloneDX VEX statements about the artifacts,
+the statements come from the VEX accessories or the documents uploaded via API,
+they are scoped to the project of the accessory or the uploaded document
+*/
+CREATE TABLE IF NOT EXISTS vex_statement (
+    id SERIAL PRIMARY KEY NOT NULL,
+    project_id int NOT NULL,
+    artifact_digest varchar(255) NOT NULL,
+    source varchar(64) NOT NULL,
+    source_digest varchar(255),
+    format varchar(64),
+    document_id varchar(1024),
+    cve_id varchar(255) NOT NULL,
+    package varchar(1024),
+    package_version varchar(1024),
+    purl varchar(2048),
+    status varchar(64) NOT NULL,
+    justification text,
+    creation_time timestamp default CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vex_statement_artifact_digest ON vex_statement (project_id, artifact_digest, cve_id);
+CREATE INDEX IF NOT EXISTS idx_vex_statement_source_digest ON vex_statement (source_digest);
+
+/*
+Add the columns to record the updated_at property of the vulnerability database reported by the scanner adapter
+and when its latest change was detected, the artifacts scanned before the change are rescanned incrementally
+*/
+ALTER TABLE scanner_registration ADD COLUMN IF NOT EXISTS db_updated_at varchar(64);
+ALTER TABLE scanner_registration ADD COLUMN IF NOT EXISTS db_update_detected_at timestamp;
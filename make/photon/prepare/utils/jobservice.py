@@ -19,6 +19,10 @@ def prepare_job_service(config_dict):
     job_log_dir = os.path.join('/data', "job_logs")
     prepare_dir(job_log_dir, uid=DEFAULT_UID, gid=DEFAULT_GID)
 
+    # The OCI image layout bundles are exported by jobservice and read by core
+    bundle_dir = os.path.join('/data', "bundles")
+    prepare_dir(bundle_dir, uid=DEFAULT_UID, gid=DEFAULT_GID)
+
     # Render Jobservice env
     render_jinja(
         job_service_env_template_path,
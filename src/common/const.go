@@ -156,6 +156,12 @@ const (
 
 	// Global notification enable configuration
 	NotificationEnable = "notification_enable"
+	// NotificationQueueLatencyThreshold is the latency in seconds of the jobservice queue to trigger the system event
+	NotificationQueueLatencyThreshold = "notification_queue_latency_threshold"
+	// NotificationStorageFreeThreshold is the percentage of the free registry storage to trigger the system event
+	NotificationStorageFreeThreshold = "notification_storage_free_threshold"
+	// NotificationCVEAllowlistExpiryDays is the days before the expiry of the CVE allowlist items to trigger the system event
+	NotificationCVEAllowlistExpiryDays = "notification_cve_allowlist_expiry_days"
 
 	// Quota setting items for project
 	QuotaPerProjectEnable = "quota_per_project_enable"
@@ -215,10 +221,16 @@ const (
 	AuditLogForwardEndpoint = "audit_log_forward_endpoint"
 	// SkipAuditLogDatabase skip to log audit log in database
 	SkipAuditLogDatabase = "skip_audit_log_database"
+	// AuditLogForwarders is the JSON encoded sinks which the audit logs are forwarded to
+	AuditLogForwarders = "audit_log_forwarders"
 	// MaxAuditRetentionHour allowed in audit log purge
 	MaxAuditRetentionHour = 240000
 	// ScannerSkipUpdatePullTime
 	ScannerSkipUpdatePullTime = "scanner_skip_update_pulltime"
+	// ScannerRescanDailyLimit is the max count of the artifacts rescanned per day after the scanner DB updates
+	ScannerRescanDailyLimit = "scanner_rescan_daily_limit"
+	// ScannerRescanPulledWithinDays limits the rescan to the artifacts pulled in the days
+	ScannerRescanPulledWithinDays = "scanner_rescan_pulled_within_days"
 
 	// SessionTimeout defines the web session timeout
 	SessionTimeout = "session_timeout"
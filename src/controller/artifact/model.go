@@ -33,6 +33,8 @@ type Artifact struct {
 	AdditionLinks map[string]*AdditionLink   `json:"addition_links"` // the resource link for build history(image), values.yaml(chart), dependency(chart), etc
 	Labels        []*model.Label             `json:"labels"`
 	Accessories   []accessoryModel.Accessory `json:"-"`
+	// ScanOverview is only populated when the artifact is decoded from the API response
+	ScanOverview map[string]interface{} `json:"-"`
 }
 
 // UnmarshalJSON to customize the accessories unmarshal
@@ -40,7 +42,8 @@ func (artifact *Artifact) UnmarshalJSON(data []byte) error {
 	type Alias Artifact
 	ali := &struct {
 		*Alias
-		AccessoryItems []interface{} `json:"accessories,omitempty"`
+		AccessoryItems []interface{}          `json:"accessories,omitempty"`
+		ScanOverview   map[string]interface{} `json:"scan_overview,omitempty"`
 	}{
 		Alias: (*Alias)(artifact),
 	}
@@ -49,6 +52,8 @@ func (artifact *Artifact) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	artifact.ScanOverview = ali.ScanOverview
+
 	if len(ali.AccessoryItems) > 0 {
 		for _, item := range ali.AccessoryItems {
 			data, err := json.Marshal(item)
@@ -19,15 +19,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/lib/config"
 	"github.com/goharbor/harbor/src/lib/config/metadata"
 	"github.com/goharbor/harbor/src/lib/config/models"
+	"github.com/goharbor/harbor/src/lib/encrypt"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/audit"
+	"github.com/goharbor/harbor/src/pkg/audit/forwarder"
 	"github.com/goharbor/harbor/src/pkg/user"
 )
 
@@ -89,6 +93,14 @@ func (c *controller) UpdateUserConfigs(ctx context.Context, conf map[string]inte
 	if err != nil {
 		return err
 	}
+	// store the header values of the audit log forwarders encrypted
+	if forwarders, exist := conf[common.AuditLogForwarders]; exist {
+		encrypted, err := forwarder.EncryptHeaders(forwarders.(string), mgr.Get(ctx, common.AuditLogForwarders).GetString(), encrypt.Instance())
+		if err != nil {
+			return err
+		}
+		conf[common.AuditLogForwarders] = encrypted
+	}
 	if err := mgr.UpdateConfig(ctx, conf); err != nil {
 		log.Errorf("failed to upload configurations: %v", err)
 		return fmt.Errorf("failed to validate configuration")
@@ -109,6 +121,16 @@ func (c *controller) updateLogEndpoint(ctx context.Context, cfgs map[string]inte
 		}
 		audit.LogMgr.Init(ctx, auditEP)
 	}
+	// restart the audit log forwarders when the sinks updated
+	if _, ok := cfgs[common.AuditLogForwarders]; ok {
+		cfgs, err := forwarder.LoadConfigs(config.AuditLogForwarders(ctx), encrypt.Instance())
+		if err != nil {
+			return errors.BadRequestError(err)
+		}
+		if err = forwarder.Mgr.Init(ctx, cfgs); err != nil {
+			return errors.BadRequestError(fmt.Errorf("failed to initialize the audit log forwarders: %v", err))
+		}
+	}
 	return nil
 }
 
@@ -134,6 +156,12 @@ func (c *controller) validateCfg(ctx context.Context, cfgs map[string]interface{
 		return errors.BadRequestError(err)
 	}
 
+	// verify the audit log forwarders
+	if forwarders, exist := cfgs[common.AuditLogForwarders]; exist {
+		if _, err = forwarder.ParseConfigs(forwarders.(string)); err != nil {
+			return errors.BadRequestError(err)
+		}
+	}
 	// verify the skip audit log related cfgs
 	if err = verifySkipAuditLogCfg(ctx, cfgs, mgr); err != nil {
 		return err
@@ -149,6 +177,7 @@ func (c *controller) validateCfg(ctx context.Context, cfgs map[string]interface{
 func verifySkipAuditLogCfg(ctx context.Context, cfgs map[string]interface{}, mgr config.Manager) error {
 	updated := false
 	endPoint := mgr.Get(ctx, common.AuditLogForwardEndpoint).GetString()
+	forwarders := mgr.Get(ctx, common.AuditLogForwarders).GetString()
 	skipAuditDB := mgr.Get(ctx, common.SkipAuditLogDatabase).GetBool()
 
 	if skip, exist := cfgs[common.SkipAuditLogDatabase]; exist {
@@ -159,10 +188,14 @@ func verifySkipAuditLogCfg(ctx context.Context, cfgs map[string]interface{}, mgr
 		endPoint = endpoint.(string)
 		updated = true
 	}
+	if sinks, exist := cfgs[common.AuditLogForwarders]; exist {
+		forwarders = sinks.(string)
+		updated = true
+	}
 
 	if updated {
-		if skipAuditDB && len(endPoint) == 0 {
-			return errors.BadRequestError(errors.New("audit log forward endpoint should be configured before enable skip audit log in database"))
+		if skipAuditDB && len(endPoint) == 0 && len(strings.TrimSpace(forwarders)) == 0 {
+			return errors.BadRequestError(errors.New("audit log forward endpoint or forwarders should be configured before enable skip audit log in database"))
 		}
 	}
 	return nil
@@ -248,6 +281,14 @@ func (c *controller) ConvertForGet(ctx context.Context, cfg map[string]interface
 			}
 			val = string(valByte)
 		}
+		// never return the header values of the audit log forwarders for external api call
+		if item.Name == common.AuditLogForwarders && !internal {
+			redacted, err := forwarder.RedactHeaders(utils.GetStrValueOfAnyType(val))
+			if err != nil {
+				return nil, err
+			}
+			val = redacted
+		}
 		result[item.Name] = &models.Value{
 			Val:      val,
 			Editable: !readOnlyForAll,
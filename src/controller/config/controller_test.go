@@ -29,6 +29,8 @@ func Test_verifySkipAuditLogCfg(t *testing.T) {
 	cfgManager := &testCfg.Manager{}
 	cfgManager.On("Get", mock.Anything, common.AuditLogForwardEndpoint).
 		Return(&metadata.ConfigureValue{Name: common.AuditLogForwardEndpoint, Value: ""})
+	cfgManager.On("Get", mock.Anything, common.AuditLogForwarders).
+		Return(&metadata.ConfigureValue{Name: common.AuditLogForwarders, Value: ""})
 	cfgManager.On("Get", mock.Anything, common.SkipAuditLogDatabase).
 		Return(&metadata.ConfigureValue{Name: common.SkipAuditLogDatabase, Value: "true"})
 	type args struct {
@@ -45,6 +47,10 @@ func Test_verifySkipAuditLogCfg(t *testing.T) {
 			cfgs: map[string]interface{}{common.AuditLogForwardEndpoint: "harbor-log:15041",
 				common.SkipAuditLogDatabase: true},
 			mgr: cfgManager}, wantErr: false},
+		{name: "forwarders configured", args: args{ctx: context.TODO(),
+			cfgs: map[string]interface{}{common.AuditLogForwarders: `[{"name":"siem","type":"syslog","endpoint":"siem:514"}]`,
+				common.SkipAuditLogDatabase: true},
+			mgr: cfgManager}, wantErr: false},
 		{name: "no forward endpoint config", args: args{ctx: context.TODO(),
 			cfgs: map[string]interface{}{common.SkipAuditLogDatabase: true},
 			mgr:  cfgManager}, wantErr: true},
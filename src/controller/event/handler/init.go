@@ -25,10 +25,12 @@ import (
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/artifact"
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/quota"
 	"github.com/goharbor/harbor/src/controller/event/handler/webhook/scan"
+	"github.com/goharbor/harbor/src/controller/event/handler/webhook/system"
 	"github.com/goharbor/harbor/src/controller/event/metadata"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/pkg/notification"
 	"github.com/goharbor/harbor/src/pkg/notifier"
+	n_event "github.com/goharbor/harbor/src/pkg/notifier/event"
 	"github.com/goharbor/harbor/src/pkg/task"
 )
 
@@ -44,12 +46,22 @@ func init() {
 	_ = notifier.Subscribe(event.TopicScanningCompleted, &scan.Handler{})
 	_ = notifier.Subscribe(event.TopicReplication, &artifact.ReplicationHandler{})
 	_ = notifier.Subscribe(event.TopicTagRetention, &artifact.RetentionHandler{})
+	_ = notifier.Subscribe(event.TopicCreateProject, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicDeleteProject, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicGCCompleted, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicPurgeAuditCompleted, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicScanAllCompleted, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicJobQueueLatency, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicStorageLow, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicScannerHealthChanged, &system.Handler{})
+	_ = notifier.Subscribe(event.TopicCVEAllowlistExpiring, &system.Handler{})
 
 	// replication
 	_ = notifier.Subscribe(event.TopicPushArtifact, &replication.Handler{})
 	_ = notifier.Subscribe(event.TopicDeleteArtifact, &replication.Handler{})
 	_ = notifier.Subscribe(event.TopicCreateTag, &replication.Handler{})
 	_ = notifier.Subscribe(event.TopicDeleteTag, &replication.Handler{})
+	_ = notifier.Subscribe(event.TopicArtifactLabeled, &replication.Handler{})
 
 	// p2p preheat
 	_ = notifier.Subscribe(event.TopicPushArtifact, &p2p.Handler{})
@@ -67,6 +79,7 @@ func init() {
 	_ = notifier.Subscribe(event.TopicDeleteTag, &auditlog.Handler{})
 	_ = notifier.Subscribe(event.TopicCreateRobot, &auditlog.Handler{})
 	_ = notifier.Subscribe(event.TopicDeleteRobot, &auditlog.Handler{})
+	_ = notifier.Subscribe(event.TopicAPIOperation, &auditlog.Handler{})
 
 	// internal
 	_ = notifier.Subscribe(event.TopicPullArtifact, &internal.ArtifactEventHandler{})
@@ -81,4 +94,25 @@ func init() {
 		})
 		return nil
 	})
+	// the execution status is refreshed asynchronously out of the API requests, so publish the events directly
+	for _, vendorType := range metadata.SystemJobVendorTypes() {
+		_ = task.RegisterExecutionStatusChangePostFunc(vendorType, publishSystemJobEvent)
+	}
+}
+
+func publishSystemJobEvent(ctx context.Context, executionID int64, status string) error {
+	if !job.Status(status).Final() {
+		return nil
+	}
+	exec, err := task.ExecMgr.Get(ctx, executionID)
+	if err != nil {
+		return err
+	}
+	n_event.BuildAndPublish(ctx, &metadata.SystemJobMetaData{
+		ExecutionID: exec.ID,
+		VendorType:  exec.VendorType,
+		Trigger:     exec.Trigger,
+		Status:      status,
+	})
+	return nil
 }
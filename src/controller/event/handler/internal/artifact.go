@@ -40,6 +40,7 @@ import (
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
 	"github.com/goharbor/harbor/src/pkg/scan/sbom"
 	"github.com/goharbor/harbor/src/pkg/task"
+	"github.com/goharbor/harbor/src/pkg/vex"
 )
 
 const (
@@ -324,6 +325,12 @@ func (a *ArtifactEventHandler) onDelete(ctx context.Context, event *event.Artifa
 		log.Errorf("failed to delete scan reports of artifact %v, error: %v", unrefDigests, err)
 	}
 
+	// clean up the VEX statements of the project about the artifacts and the ones coming from the deleted VEX accessories,
+	// the statements are kept if the digests are still referenced by other artifacts of the project
+	if err := vex.Mgr.DeleteByDigests(ctx, event.Artifact.ProjectID, digests...); err != nil {
+		log.Errorf("failed to delete VEX statements of artifact %v, error: %v", digests, err)
+	}
+
 	// delete sbom_report when the subject artifact is deleted
 	if err := sbom.Mgr.DeleteByArtifactID(ctx, event.Artifact.ID); err != nil {
 		log.Errorf("failed to delete sbom reports of artifact ID %v, error: %v", event.Artifact.ID, err)
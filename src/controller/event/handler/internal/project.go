@@ -19,6 +19,7 @@ import (
 
 	"github.com/goharbor/harbor/src/controller/event"
 	"github.com/goharbor/harbor/src/controller/immutable"
+	"github.com/goharbor/harbor/src/controller/proxy/prefetch"
 	"github.com/goharbor/harbor/src/controller/retention"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/member"
@@ -49,6 +50,9 @@ func (a *ProjectEventHandler) onProjectDelete(ctx context.Context, event *event.
 	if err := member.Mgr.DeleteMemberByProjectID(ctx, event.ProjectID); err != nil {
 		log.Errorf("failed to delete project member, error %v", err)
 	}
+	if err := prefetch.Ctl.DeletePolicyByProject(ctx, event.ProjectID); err != nil {
+		log.Errorf("failed to delete proxy cache prefetch policy, error %v", err)
+	}
 	return nil
 }
 
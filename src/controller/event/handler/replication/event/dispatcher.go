@@ -0,0 +1,350 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/goharbor/harbor/src/controller/event/operator"
+	"github.com/goharbor/harbor/src/controller/replication"
+	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/gtask"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+var (
+	// the events of the same policy and repository received within the window are coalesced
+	coalesceWindow = 3 * time.Second
+	// the interval to check whether the execution is done
+	checkInterval = 5 * time.Second
+	// the max duration to wait the execution to be done before starting the next one
+	maxWaitDuration = 6 * time.Hour
+	// the expiration of the lock of the queue, it is renewed periodically until the queue is drained
+	lockTTL = time.Minute
+	// the interval to resume the queues left by the crashed or restarted core instances
+	resumeInterval = 5 * time.Minute
+
+	dispatcherInstance = newDispatcher(newRedisQueue(), getPolicy, startExecution, waitExecution)
+)
+
+func init() {
+	gtask.DefaultPool().AddTask(dispatcherInstance.resume, resumeInterval)
+}
+
+type getPolicyFunc func(ctx context.Context, id int64) (*repctlmodel.Policy, error)
+
+type startFunc func(ctx context.Context, policy *repctlmodel.Policy, resource *model.Resource, operator string) (int64, error)
+
+type waitFunc func(ctx context.Context, executionID int64) error
+
+// dispatcher replicates the events of the same policy and repository in order: the events are queued,
+// the consecutive events of the same kind are coalesced into one execution and the next execution
+// isn't started until the previous one is done.
+// The queues are shared by all the core instances, the queue is processed by the instance holding its lock
+// and the queues left by the crashed or restarted instances are resumed periodically
+type dispatcher struct {
+	queue eventQueue
+	// holder identifies the core instance holding the locks of the queues
+	holder    string
+	getPolicy getPolicyFunc
+	start     startFunc
+	wait      waitFunc
+}
+
+// queuedEvent is the event stored in the queue, the policy is fetched again when processing the event
+type queuedEvent struct {
+	PolicyID int64  `json:"policy_id"`
+	Event    *Event `json:"event"`
+
+	policy *repctlmodel.Policy
+}
+
+// batch is the coalesced events which are replicated by one execution
+type batch struct {
+	policy   *repctlmodel.Policy
+	resource *model.Resource
+	operator string
+}
+
+func newDispatcher(queue eventQueue, getPolicy getPolicyFunc, start startFunc, wait waitFunc) *dispatcher {
+	return &dispatcher{
+		queue:     queue,
+		holder:    uuid.New().String(),
+		getPolicy: getPolicy,
+		start:     start,
+		wait:      wait,
+	}
+}
+
+func (d *dispatcher) dispatch(policy *repctlmodel.Policy, event *Event) {
+	key := fmt.Sprintf("%d:%s", policy.ID, event.Resource.Metadata.Repository.Name)
+	data, err := json.Marshal(&queuedEvent{PolicyID: policy.ID, Event: event})
+	if err != nil {
+		log.Errorf("failed to marshal the event of policy %d for %s: %v", policy.ID, key, err)
+		return
+	}
+	ctx := context.Background()
+	if err := d.queue.push(ctx, key, data); err != nil {
+		log.Errorf("failed to queue the event of policy %d for %s: %v", policy.ID, key, err)
+		return
+	}
+	d.tryProcess(ctx, key)
+}
+
+// resume processes the queues whose holders are gone
+func (d *dispatcher) resume(ctx context.Context) {
+	keys, err := d.queue.keys(ctx)
+	if err != nil {
+		log.Errorf("failed to list the queues of the replication events: %v", err)
+		return
+	}
+	for _, key := range keys {
+		d.tryProcess(ctx, key)
+	}
+}
+
+// tryProcess processes the queue if no core instance is processing it
+func (d *dispatcher) tryProcess(ctx context.Context, key string) {
+	locked, err := d.queue.lock(ctx, key, d.holder, lockTTL)
+	if err != nil {
+		log.Errorf("failed to lock the queue of the replication events for %s: %v", key, err)
+		return
+	}
+	if locked {
+		go d.process(key)
+	}
+}
+
+// process replicates the queued events of the key until the queue is empty
+func (d *dispatcher) process(key string) {
+	ctx := context.Background()
+	// renew the lock until the queue is drained
+	done, ttl := make(chan struct{}), lockTTL
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := d.queue.renew(ctx, key, d.holder, ttl); err != nil {
+					log.Warningf("failed to renew the lock of the queue for %s: %v", key, err)
+				}
+			}
+		}
+	}()
+
+	// the execution started by the previous holder may still be running
+	if id, err := d.queue.running(ctx, key); err != nil {
+		log.Errorf("failed to get the running replication execution for %s: %v", key, err)
+	} else if id > 0 {
+		d.waitRunning(ctx, key, id)
+	}
+
+	for {
+		// wait for the subsequent events to coalesce them
+		time.Sleep(coalesceWindow)
+		items, err := d.queue.pop(ctx, key)
+		if err != nil {
+			log.Errorf("failed to pop the replication events for %s: %v", key, err)
+			return
+		}
+		if len(items) == 0 {
+			// the lock is kept if any event is queued after the pop
+			released, err := d.queue.unlockIfEmpty(ctx, key, d.holder)
+			if err != nil {
+				log.Errorf("failed to unlock the queue of the replication events for %s: %v", key, err)
+				return
+			}
+			if released {
+				return
+			}
+			continue
+		}
+
+		for _, b := range coalesce(d.resolve(ctx, key, items)) {
+			id, err := d.start(ctx, b.policy, b.resource, b.operator)
+			if err != nil {
+				log.Errorf("failed to start the replication of policy %d for %s: %v", b.policy.ID, key, err)
+				continue
+			}
+			log.Debugf("the replication execution %d started for %s", id, key)
+			if err := d.queue.setRunning(ctx, key, id, maxWaitDuration); err != nil {
+				log.Warningf("failed to record the running replication execution %d for %s: %v", id, key, err)
+			}
+			d.waitRunning(ctx, key, id)
+		}
+	}
+}
+
+func (d *dispatcher) waitRunning(ctx context.Context, key string, id int64) {
+	if err := d.wait(ctx, id); err != nil {
+		log.Errorf("failed to wait the replication execution %d to be done: %v", id, err)
+	}
+	if err := d.queue.setRunning(ctx, key, 0, 0); err != nil {
+		log.Warningf("failed to clear the running replication execution %d for %s: %v", id, key, err)
+	}
+}
+
+// resolve decodes the queued events and fetches their latest policies, the events of the deleted or
+// disabled policies are dropped
+func (d *dispatcher) resolve(ctx context.Context, key string, items [][]byte) []*queuedEvent {
+	var (
+		events   []*queuedEvent
+		policies = map[int64]*repctlmodel.Policy{}
+	)
+	for _, item := range items {
+		e := &queuedEvent{}
+		if err := json.Unmarshal(item, e); err != nil || e.Event == nil || e.Event.Resource == nil || e.Event.Resource.Metadata == nil {
+			log.Errorf("invalid replication event for %s is dropped: %v", key, err)
+			continue
+		}
+		policy, exist := policies[e.PolicyID]
+		if !exist {
+			p, err := d.getPolicy(ctx, e.PolicyID)
+			if err != nil {
+				log.Errorf("failed to get the replication policy %d for %s: %v", e.PolicyID, key, err)
+			} else if p.Enabled {
+				policy = p
+			}
+			policies[e.PolicyID] = policy
+		}
+		if policy == nil {
+			continue
+		}
+		e.policy = policy
+		events = append(events, e)
+	}
+	return events
+}
+
+// kindOf returns the kind of the event, the events of the same kind can be coalesced
+func kindOf(event *Event) string {
+	// the labeled artifacts are replicated as the pushed ones
+	if event.Type == EventTypeArtifactLabel {
+		return EventTypeArtifactPush
+	}
+	return event.Type
+}
+
+// coalesce merges the consecutive events of the same kind and operator into one batch
+func coalesce(events []*queuedEvent) []*batch {
+	var (
+		batches  []*batch
+		lastKind string
+	)
+	for _, e := range events {
+		kind := kindOf(e.Event)
+		if len(batches) > 0 && kind == lastKind && e.Event.Operator == batches[len(batches)-1].operator {
+			last := batches[len(batches)-1]
+			// use the latest policy and repository
+			last.policy = e.policy
+			last.resource.Metadata.Repository = e.Event.Resource.Metadata.Repository
+			last.resource.Metadata.Artifacts = mergeArtifacts(last.resource.Metadata.Artifacts, e.Event.Resource.Metadata.Artifacts)
+			continue
+		}
+		resource := *e.Event.Resource
+		metadata := *resource.Metadata
+		metadata.Artifacts = mergeArtifacts(nil, metadata.Artifacts)
+		resource.Metadata = &metadata
+		batches = append(batches, &batch{
+			policy:   e.policy,
+			resource: &resource,
+			operator: e.Event.Operator,
+		})
+		lastKind = kind
+	}
+	return batches
+}
+
+// mergeArtifacts merges the artifacts with the same digest, the tags are merged and the labels are replaced by the latest ones
+func mergeArtifacts(artifacts []*model.Artifact, others []*model.Artifact) []*model.Artifact {
+	for _, other := range others {
+		merged := false
+		for _, art := range artifacts {
+			if art.Digest != other.Digest {
+				continue
+			}
+			for _, tag := range other.Tags {
+				if !contains(art.Tags, tag) {
+					art.Tags = append(art.Tags, tag)
+				}
+			}
+			art.Labels = other.Labels
+			merged = true
+			break
+		}
+		if !merged {
+			art := *other
+			art.Tags = append([]string{}, other.Tags...)
+			artifacts = append(artifacts, &art)
+		}
+	}
+	return artifacts
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func getPolicy(ctx context.Context, id int64) (*repctlmodel.Policy, error) {
+	return replication.Ctl.GetPolicy(orm.NewContext(ctx, orm.Crt.Create()), id)
+}
+
+func startExecution(ctx context.Context, policy *repctlmodel.Policy, resource *model.Resource, op string) (int64, error) {
+	ctx = orm.NewContext(ctx, orm.Crt.Create())
+	if op != "" {
+		ctx = context.WithValue(ctx, operator.ContextKey{}, op)
+	}
+	return replication.Ctl.Start(ctx, policy, resource, task.ExecutionTriggerEvent)
+}
+
+// waitExecution waits until the execution is done
+func waitExecution(ctx context.Context, executionID int64) error {
+	ctx = orm.NewContext(ctx, orm.Crt.Create())
+	timeout := time.After(maxWaitDuration)
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timeout:
+			return fmt.Errorf("timeout to wait the execution %d to be done", executionID)
+		case <-ticker.C:
+			execution, err := replication.Ctl.GetExecution(ctx, executionID)
+			if err != nil {
+				return err
+			}
+			if job.Status(execution.Status).Final() {
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func newTestEvent(eventType, operator, digest string, tags ...string) *Event {
+	return &Event{
+		Type: eventType,
+		Resource: &model.Resource{
+			Type: model.ResourceTypeArtifact,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Artifacts: []*model.Artifact{
+					{
+						Digest: digest,
+						Tags:   tags,
+					},
+				},
+			},
+			Deleted: eventType != EventTypeArtifactPush && eventType != EventTypeArtifactLabel,
+		},
+		Operator: operator,
+	}
+}
+
+// fakeQueue keeps the queues in memory
+type fakeQueue struct {
+	sync.Mutex
+	queues     map[string][][]byte
+	locks      map[string]string
+	executions map[string]int64
+}
+
+func newFakeQueue() *fakeQueue {
+	return &fakeQueue{
+		queues:     map[string][][]byte{},
+		locks:      map[string]string{},
+		executions: map[string]int64{},
+	}
+}
+
+func (f *fakeQueue) push(_ context.Context, key string, data []byte) error {
+	f.Lock()
+	defer f.Unlock()
+	f.queues[key] = append(f.queues[key], data)
+	return nil
+}
+
+func (f *fakeQueue) pop(_ context.Context, key string) ([][]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+	items := f.queues[key]
+	f.queues[key] = nil
+	return items, nil
+}
+
+func (f *fakeQueue) keys(_ context.Context) ([]string, error) {
+	f.Lock()
+	defer f.Unlock()
+	var keys []string
+	for key := range f.queues {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (f *fakeQueue) lock(_ context.Context, key, holder string, _ time.Duration) (bool, error) {
+	f.Lock()
+	defer f.Unlock()
+	if _, exist := f.locks[key]; exist {
+		return false, nil
+	}
+	f.locks[key] = holder
+	return true, nil
+}
+
+func (f *fakeQueue) renew(_ context.Context, _, _ string, _ time.Duration) error {
+	return nil
+}
+
+func (f *fakeQueue) unlockIfEmpty(_ context.Context, key, holder string) (bool, error) {
+	f.Lock()
+	defer f.Unlock()
+	if f.locks[key] != holder {
+		return true, nil
+	}
+	if len(f.queues[key]) > 0 {
+		return false, nil
+	}
+	delete(f.locks, key)
+	delete(f.queues, key)
+	return true, nil
+}
+
+func (f *fakeQueue) setRunning(_ context.Context, key string, executionID int64, _ time.Duration) error {
+	f.Lock()
+	defer f.Unlock()
+	if executionID <= 0 {
+		delete(f.executions, key)
+	} else {
+		f.executions[key] = executionID
+	}
+	return nil
+}
+
+func (f *fakeQueue) running(_ context.Context, key string) (int64, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.executions[key], nil
+}
+
+func TestCoalesce(t *testing.T) {
+	policy := &repctlmodel.Policy{ID: 1}
+	events := []*queuedEvent{
+		{policy: policy, Event: newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "v1")},
+		{policy: policy, Event: newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "latest")},
+		{policy: policy, Event: newTestEvent(EventTypeArtifactLabel, "admin", "sha256:2", "v2")},
+		{policy: policy, Event: newTestEvent(EventTypeTagDelete, "admin", "sha256:1", "latest")},
+		{policy: policy, Event: newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "latest")},
+		{policy: policy, Event: newTestEvent(EventTypeArtifactPush, "user", "sha256:3", "v3")},
+	}
+	batches := coalesce(events)
+	assert.Len(t, batches, 4)
+
+	// the pushed and labeled artifacts are merged
+	artifacts := batches[0].resource.Metadata.Artifacts
+	assert.Len(t, artifacts, 2)
+	assert.Equal(t, "sha256:1", artifacts[0].Digest)
+	assert.Equal(t, []string{"v1", "latest"}, artifacts[0].Tags)
+	assert.Equal(t, "sha256:2", artifacts[1].Digest)
+
+	assert.True(t, batches[1].resource.Deleted)
+	assert.Equal(t, []string{"latest"}, batches[1].resource.Metadata.Artifacts[0].Tags)
+	assert.Equal(t, "admin", batches[2].operator)
+	assert.Equal(t, "user", batches[3].operator)
+
+	// the original events aren't changed
+	assert.Equal(t, []string{"v1"}, events[0].Event.Resource.Metadata.Artifacts[0].Tags)
+}
+
+func TestDispatch(t *testing.T) {
+	window := coalesceWindow
+	coalesceWindow = 10 * time.Millisecond
+	defer func() {
+		coalesceWindow = window
+	}()
+
+	var (
+		lock     sync.Mutex
+		started  []string
+		running  int
+		parallel bool
+		waited   []int64
+		done     = make(chan struct{}, 10)
+	)
+	start := func(_ context.Context, _ *repctlmodel.Policy, resource *model.Resource, _ string) (int64, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		running++
+		if running > 1 {
+			parallel = true
+		}
+		kind := EventTypeArtifactPush
+		if resource.Deleted {
+			kind = EventTypeTagDelete
+		}
+		started = append(started, kind)
+		return int64(len(started)), nil
+	}
+	wait := func(_ context.Context, id int64) error {
+		time.Sleep(20 * time.Millisecond)
+		lock.Lock()
+		running--
+		waited = append(waited, id)
+		lock.Unlock()
+		done <- struct{}{}
+		return nil
+	}
+	getPolicy := func(_ context.Context, id int64) (*repctlmodel.Policy, error) {
+		return &repctlmodel.Policy{ID: id, Enabled: id == 1}, nil
+	}
+	queue := newFakeQueue()
+	// the execution started by the previous holder is waited first
+	queue.executions["1:library/hello-world"] = 100
+	d := newDispatcher(queue, getPolicy, start, wait)
+	// another core instance shares the same queues
+	another := newDispatcher(queue, getPolicy, start, wait)
+	policy := &repctlmodel.Policy{ID: 1}
+	d.dispatch(policy, newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "v1"))
+	another.dispatch(policy, newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "v2"))
+	d.dispatch(policy, newTestEvent(EventTypeTagDelete, "admin", "sha256:1", "v1"))
+	// the events of the disabled policy are dropped
+	d.dispatch(&repctlmodel.Policy{ID: 2}, newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "v1"))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout to wait the executions")
+		}
+	}
+	lock.Lock()
+	assert.Equal(t, []string{EventTypeArtifactPush, EventTypeTagDelete}, started)
+	assert.Equal(t, []int64{100, 1, 2}, waited)
+	assert.False(t, parallel)
+	lock.Unlock()
+
+	// the queues are unlocked and removed after all events are processed
+	assert.Eventually(t, func() bool {
+		queue.Lock()
+		defer queue.Unlock()
+		return len(queue.queues) == 0 && len(queue.locks) == 0 && len(queue.executions) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the queue left by the crashed instance is resumed
+	data, err := json.Marshal(&queuedEvent{PolicyID: 1, Event: newTestEvent(EventTypeArtifactPush, "admin", "sha256:1", "v1")})
+	require.NoError(t, err)
+	require.NoError(t, queue.push(context.Background(), "1:library/hello-world", data))
+	another.resume(context.Background())
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout to wait the resumed execution")
+	}
+	lock.Lock()
+	assert.Equal(t, []string{EventTypeArtifactPush, EventTypeTagDelete, EventTypeArtifactPush}, started)
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		queue.Lock()
+		defer queue.Unlock()
+		return len(queue.locks) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -21,6 +21,7 @@ const (
 	EventTypeArtifactPush   = "artifact_push"
 	EventTypeArtifactDelete = "artifact_delete"
 	EventTypeTagDelete      = "tag_delete"
+	EventTypeArtifactLabel  = "artifact_label"
 )
 
 // Event is the model that defines the image pull/push event
@@ -19,19 +19,18 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/goharbor/harbor/src/controller/event/operator"
 	"github.com/goharbor/harbor/src/controller/replication"
 	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg/filter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
-	"github.com/goharbor/harbor/src/pkg/task"
 )
 
 // Handle ...
 func Handle(ctx context.Context, event *Event) error {
 	if event == nil || event.Resource == nil ||
 		event.Resource.Metadata == nil ||
+		event.Resource.Metadata.Repository == nil ||
 		len(event.Resource.Metadata.Artifacts) == 0 {
 		return errors.New("invalid event")
 	}
@@ -39,7 +38,10 @@ func Handle(ctx context.Context, event *Event) error {
 	var err error
 	switch event.Type {
 	case EventTypeArtifactPush, EventTypeTagDelete, EventTypeArtifactDelete:
-		policies, err = getRelatedPolicies(ctx, event.Resource)
+		policies, err = getRelatedPolicies(ctx, event.Resource, false)
+	case EventTypeArtifactLabel:
+		// the labeled artifacts are replicated only by the policies filtering on labels
+		policies, err = getRelatedPolicies(ctx, event.Resource, true)
 	default:
 		return fmt.Errorf("unsupported event type %s", event.Type)
 	}
@@ -52,21 +54,14 @@ func Handle(ctx context.Context, event *Event) error {
 		return nil
 	}
 
-	if event.Operator != "" {
-		ctx = context.WithValue(ctx, operator.ContextKey{}, event.Operator)
-	}
-
+	// the events are replicated in order per policy and repository
 	for _, policy := range policies {
-		id, err := replication.Ctl.Start(ctx, policy, event.Resource, task.ExecutionTriggerEvent)
-		if err != nil {
-			return err
-		}
-		log.Debugf("%s event received, the replication execution %d started", event.Type, id)
+		dispatcherInstance.dispatch(policy, event)
 	}
 	return nil
 }
 
-func getRelatedPolicies(ctx context.Context, resource *model.Resource) ([]*repctlmodel.Policy, error) {
+func getRelatedPolicies(ctx context.Context, resource *model.Resource, labelFilterOnly bool) ([]*repctlmodel.Policy, error) {
 	policies, err := replication.Ctl.ListPolicies(ctx, nil)
 	if err != nil {
 		return nil, err
@@ -94,6 +89,10 @@ func getRelatedPolicies(ctx context.Context, resource *model.Resource) ([]*repct
 		if resource.Deleted && !policy.ReplicateDeletion {
 			continue
 		}
+		// doesn't filter on labels
+		if labelFilterOnly && !hasLabelFilter(policy) {
+			continue
+		}
 
 		resources, err := filter.DoFilterResources([]*model.Resource{resource}, policy.Filters)
 		if err != nil {
@@ -108,3 +107,12 @@ func getRelatedPolicies(ctx context.Context, resource *model.Resource) ([]*repct
 	}
 	return result, nil
 }
+
+func hasLabelFilter(policy *repctlmodel.Policy) bool {
+	for _, f := range policy.Filters {
+		if f.Type == model.FilterTypeLabel {
+			return true
+		}
+	}
+	return false
+}
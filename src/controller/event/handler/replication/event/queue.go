@@ -0,0 +1,180 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	libredis "github.com/goharbor/harbor/src/lib/redis"
+)
+
+// eventQueue stores the queues of the replication events keyed by "<policy ID>:<repository>",
+// the queue is processed by the core instance holding its lock
+type eventQueue interface {
+	// push appends the event to the queue of the key
+	push(ctx context.Context, key string, data []byte) error
+	// pop removes and returns all the events in the queue of the key
+	pop(ctx context.Context, key string) ([][]byte, error)
+	// keys returns the keys of the queues which aren't drained
+	keys(ctx context.Context) ([]string, error)
+	// lock the queue of the key for the holder if it isn't locked, returns whether the lock is acquired
+	lock(ctx context.Context, key, holder string, ttl time.Duration) (bool, error)
+	// renew the lock of the queue held by the holder
+	renew(ctx context.Context, key, holder string, ttl time.Duration) error
+	// unlockIfEmpty releases the lock held by the holder only if the queue is empty, returns whether the holder
+	// should stop processing the queue
+	unlockIfEmpty(ctx context.Context, key, holder string) (bool, error)
+	// setRunning records the execution running for the queue, 0 clears the record
+	setRunning(ctx context.Context, key string, executionID int64, ttl time.Duration) error
+	// running returns the execution running for the queue, 0 if no execution is running
+	running(ctx context.Context, key string) (int64, error)
+}
+
+const (
+	queuesKey      = "replication:events:queues"
+	queueKeyPrefix = "replication:events:"
+)
+
+var (
+	renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// the lock is released and the key is removed from the queue set only if the queue is empty,
+	// the holder stops processing as well when the lock is held by others
+	unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 1
+end
+if redis.call('LLEN', KEYS[2]) > 0 then
+  return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('SREM', KEYS[3], ARGV[2])
+return 1
+`)
+)
+
+func newRedisQueue() eventQueue {
+	return &redisQueue{}
+}
+
+type redisQueue struct{}
+
+func queueKey(key string) string {
+	return queueKeyPrefix + key
+}
+
+func lockKey(key string) string {
+	return queueKeyPrefix + key + ":lock"
+}
+
+func runningKey(key string) string {
+	return queueKeyPrefix + key + ":execution"
+}
+
+func (r *redisQueue) push(ctx context.Context, key string, data []byte) error {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return err
+	}
+	_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, queueKey(key), data)
+		pipe.SAdd(ctx, queuesKey, key)
+		return nil
+	})
+	return err
+}
+
+func (r *redisQueue) pop(ctx context.Context, key string) ([][]byte, error) {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return nil, err
+	}
+	var items *redis.StringSliceCmd
+	if _, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		items = pipe.LRange(ctx, queueKey(key), 0, -1)
+		pipe.Del(ctx, queueKey(key))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	var result [][]byte
+	for _, item := range items.Val() {
+		result = append(result, []byte(item))
+	}
+	return result, nil
+}
+
+func (r *redisQueue) keys(ctx context.Context) ([]string, error) {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.SMembers(ctx, queuesKey).Result()
+}
+
+func (r *redisQueue) lock(ctx context.Context, key, holder string, ttl time.Duration) (bool, error) {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return false, err
+	}
+	return client.SetNX(ctx, lockKey(key), holder, ttl).Result()
+}
+
+func (r *redisQueue) renew(ctx context.Context, key, holder string, ttl time.Duration) error {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return err
+	}
+	return renewScript.Run(ctx, client, []string{lockKey(key)}, holder, ttl.Milliseconds()).Err()
+}
+
+func (r *redisQueue) unlockIfEmpty(ctx context.Context, key, holder string) (bool, error) {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return false, err
+	}
+	return unlockScript.Run(ctx, client, []string{lockKey(key), queueKey(key), queuesKey}, holder, key).Bool()
+}
+
+func (r *redisQueue) setRunning(ctx context.Context, key string, executionID int64, ttl time.Duration) error {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return err
+	}
+	if executionID <= 0 {
+		return client.Del(ctx, runningKey(key)).Err()
+	}
+	return client.Set(ctx, runningKey(key), executionID, ttl).Err()
+}
+
+func (r *redisQueue) running(ctx context.Context, key string) (int64, error) {
+	client, err := libredis.GetHarborClient()
+	if err != nil {
+		return 0, err
+	}
+	id, err := client.Get(ctx, runningKey(key)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return id, err
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"strconv"
 
+	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/event"
 	repevent "github.com/goharbor/harbor/src/controller/event/handler/replication/event"
 	"github.com/goharbor/harbor/src/controller/project"
@@ -55,6 +56,10 @@ func (r *Handler) Handle(ctx context.Context, value interface{}) error {
 	if ok {
 		return r.handleDeleteTag(ctx, deleteTagEvent)
 	}
+	labeledEvent, ok := value.(*event.ArtifactLabeledEvent)
+	if ok {
+		return r.handleArtifactLabeled(ctx, labeledEvent)
+	}
 	return nil
 }
 
@@ -219,3 +224,47 @@ func (r *Handler) handleDeleteTag(ctx context.Context, event *event.DeleteTagEve
 	}
 	return repevent.Handle(ctx, e)
 }
+
+func (r *Handler) handleArtifactLabeled(ctx context.Context, event *event.ArtifactLabeledEvent) error {
+	art, err := artifact.Ctl.Get(ctx, event.ArtifactID, &artifact.Option{
+		WithTag:   true,
+		WithLabel: true,
+	})
+	if err != nil {
+		log.Errorf("failed to get artifact %d, error: %v", event.ArtifactID, err)
+		return err
+	}
+	prj, err := project.Ctl.Get(orm.Context(), art.ProjectID, project.Metadata(true))
+	if err != nil {
+		log.Errorf("failed to get project: %d, error: %v", art.ProjectID, err)
+		return err
+	}
+	var tags []string
+	for _, tag := range art.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	e := &repevent.Event{
+		Type: repevent.EventTypeArtifactLabel,
+		Resource: &model.Resource{
+			Type: model.ResourceTypeArtifact,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: art.RepositoryName,
+					Metadata: map[string]interface{}{
+						"public": strconv.FormatBool(prj.IsPublic()),
+					},
+				},
+				Artifacts: []*model.Artifact{
+					{
+						Type:   art.Type,
+						Digest: art.Digest,
+						Tags:   tags,
+						Labels: abstractLabelNames(art.Labels),
+					}},
+			},
+		},
+		Operator: event.Operator,
+	}
+	return repevent.Handle(ctx, e)
+}
@@ -0,0 +1,142 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"strings"
+
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/lib/selector/selectors/doublestar"
+	labelSelector "github.com/goharbor/harbor/src/lib/selector/selectors/label"
+	"github.com/goharbor/harbor/src/pkg/label"
+	"github.com/goharbor/harbor/src/pkg/label/model"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+// FilterSubject is the artifact an event is about, it's matched against the filters of the webhook policies
+type FilterSubject struct {
+	// Repository is the repository name without the project name
+	Repository string
+	Tags       []string
+	// ArtifactID is used to load the labels of the artifact when the labels are required by the filters
+	ArtifactID int64
+	// Severity is the overall severity of the vulnerability scan, it's empty when the event isn't a vulnerability scanning event
+	Severity vuln.Severity
+}
+
+// labelLister lists the labels of the artifact, it's replaced in the tests
+var labelLister = func(ctx context.Context, artifactID int64) ([]*model.Label, error) {
+	return label.Mgr.ListByArtifact(ctx, artifactID)
+}
+
+// FilterPolicies returns the policies whose event filters match the subject. All the policies are returned for
+// the nil subject as the filters only apply to the events about an artifact.
+func FilterPolicies(ctx context.Context, policies []*policy_model.Policy, subject *FilterSubject) ([]*policy_model.Policy, error) {
+	if subject == nil {
+		return policies, nil
+	}
+
+	candidate := &selector.Candidate{
+		Repository: subject.Repository,
+		Tags:       subject.Tags,
+	}
+	labelsLoaded := false
+	var matched []*policy_model.Policy
+	for _, ply := range policies {
+		filters := ply.Filters
+		if filters.IsEmpty() {
+			matched = append(matched, ply)
+			continue
+		}
+		if len(filters.Labels) > 0 && !labelsLoaded {
+			labels, err := loadLabels(ctx, subject.ArtifactID)
+			if err != nil {
+				return nil, err
+			}
+			candidate.Labels = labels
+			labelsLoaded = true
+		}
+		ok, err := matchFilters(filters, candidate, subject.Severity)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, ply)
+			continue
+		}
+		log.Debugf("the event of %s%v is filtered out by the webhook policy %d", subject.Repository, subject.Tags, ply.ID)
+	}
+	return matched, nil
+}
+
+func loadLabels(ctx context.Context, artifactID int64) ([]string, error) {
+	if artifactID == 0 {
+		return nil, nil
+	}
+	labels, err := labelLister(ctx, artifactID)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names, nil
+}
+
+func matchFilters(filters *policy_model.EventFilters, candidate *selector.Candidate, severity vuln.Severity) (bool, error) {
+	if len(filters.Repositories) > 0 {
+		ok, err := matchAnyPattern(doublestar.RepoMatches, filters.Repositories, candidate)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(filters.Tags) > 0 {
+		ok, err := matchAnyPattern(doublestar.Matches, filters.Tags, candidate)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(filters.Labels) > 0 {
+		selected, err := labelSelector.New(labelSelector.With, strings.Join(filters.Labels, ","), "").Select([]*selector.Candidate{candidate})
+		if err != nil || len(selected) == 0 {
+			return false, err
+		}
+	}
+	if len(filters.Severity) > 0 && len(severity) > 0 {
+		if severity.Code() < vuln.Severity(filters.Severity).Code() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchAnyPattern returns whether the candidate is selected by any of the doublestar patterns,
+// the untagged candidate never matches the tag patterns
+func matchAnyPattern(decoration string, patterns []string, candidate *selector.Candidate) (bool, error) {
+	for _, pattern := range patterns {
+		selected, err := doublestar.New(decoration, pattern, `{"untagged":false}`).Select([]*selector.Candidate{candidate})
+		if err != nil {
+			return false, err
+		}
+		if len(selected) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/label/model"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+func TestFilterPolicies(t *testing.T) {
+	lister := labelLister
+	defer func() { labelLister = lister }()
+	listed := 0
+	labelLister = func(_ context.Context, artifactID int64) ([]*model.Label, error) {
+		listed++
+		if artifactID == 1 {
+			return []*model.Label{{Name: "prod"}, {Name: "team-a"}}, nil
+		}
+		return nil, nil
+	}
+
+	all := &policy_model.Policy{ID: 1}
+	repo := &policy_model.Policy{ID: 2, Filters: &policy_model.EventFilters{Repositories: []string{"app/**", "tools"}}}
+	tag := &policy_model.Policy{ID: 3, Filters: &policy_model.EventFilters{Tags: []string{"v*"}}}
+	labels := &policy_model.Policy{ID: 4, Filters: &policy_model.EventFilters{Labels: []string{"prod", "team-a"}}}
+	severity := &policy_model.Policy{ID: 5, Filters: &policy_model.EventFilters{Severity: "High"}}
+	policies := []*policy_model.Policy{all, repo, tag, labels, severity}
+
+	tests := []struct {
+		name    string
+		subject *FilterSubject
+		want    []*policy_model.Policy
+	}{
+		{
+			name: "nil subject",
+			want: policies,
+		},
+		{
+			name:    "repository and tag",
+			subject: &FilterSubject{Repository: "app/frontend", Tags: []string{"latest", "v1.0"}},
+			want:    []*policy_model.Policy{all, repo, tag, severity},
+		},
+		{
+			name:    "untagged",
+			subject: &FilterSubject{Repository: "tools"},
+			want:    []*policy_model.Policy{all, repo, severity},
+		},
+		{
+			name:    "labels",
+			subject: &FilterSubject{Repository: "nginx", Tags: []string{"latest"}, ArtifactID: 1},
+			want:    []*policy_model.Policy{all, labels, severity},
+		},
+		{
+			name:    "severity below the threshold",
+			subject: &FilterSubject{Repository: "nginx", Severity: vuln.Medium},
+			want:    []*policy_model.Policy{all},
+		},
+		{
+			name:    "severity above the threshold",
+			subject: &FilterSubject{Repository: "nginx", Severity: vuln.Critical},
+			want:    []*policy_model.Policy{all, severity},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FilterPolicies(context.TODO(), policies, tt.subject)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	// the labels are only loaded for the subject with the artifact ID
+	assert.Equal(t, 1, listed)
+}
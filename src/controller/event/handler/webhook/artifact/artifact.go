@@ -83,6 +83,20 @@ func (a *Handler) handle(ctx context.Context, event *event.ArtifactEvent) error
 		return nil
 	}
 
+	policies, err = util.FilterPolicies(ctx, policies, &util.FilterSubject{
+		Repository: util.GetNameFromImgRepoFullName(event.Repository),
+		Tags:       event.Tags,
+		ArtifactID: event.Artifact.ID,
+	})
+	if err != nil {
+		log.Errorf("failed to filter policies for %s event: %v", event.EventType, err)
+		return err
+	}
+	if len(policies) == 0 {
+		log.Debugf("the %s event is filtered out by all the policies: %v", event.EventType, event)
+		return nil
+	}
+
 	payload, err := a.constructArtifactPayload(ctx, event, prj)
 	if err != nil {
 		return err
@@ -63,6 +63,20 @@ func (qp *Handler) Handle(ctx context.Context, value interface{}) error {
 		return nil
 	}
 
+	subject := &util.FilterSubject{Repository: util.GetNameFromImgRepoFullName(quotaEvent.RepoName)}
+	if quotaEvent.Resource != nil && len(quotaEvent.Resource.Tag) > 0 {
+		subject.Tags = []string{quotaEvent.Resource.Tag}
+	}
+	policies, err = util.FilterPolicies(ctx, policies, subject)
+	if err != nil {
+		log.Errorf("failed to filter policies for %s event: %v", quotaEvent.EventType, err)
+		return err
+	}
+	if len(policies) == 0 {
+		log.Debugf("the %s event is filtered out by all the policies: %v", quotaEvent.EventType, quotaEvent)
+		return nil
+	}
+
 	payload, err := constructQuotaPayload(quotaEvent)
 	if err != nil {
 		return err
@@ -30,6 +30,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/notifier/model"
 	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
 // Handler preprocess scan artifact event
@@ -69,11 +70,25 @@ func (si *Handler) Handle(ctx context.Context, value interface{}) error {
 		return errors.Wrap(err, "scan preprocess handler")
 	}
 
-	payload, err := constructScanImagePayload(ctx, e, prj)
+	art, err := artifact.Ctl.GetByReference(ctx, e.Artifact.Repository, e.Artifact.Digest, nil)
 	if err != nil {
 		return errors.Wrap(err, "scan preprocess handler")
 	}
 
+	payload, err := constructScanImagePayload(ctx, e, prj, art)
+	if err != nil {
+		return errors.Wrap(err, "scan preprocess handler")
+	}
+
+	policies, err = util.FilterPolicies(ctx, policies, filterSubject(e, art, payload))
+	if err != nil {
+		return errors.Wrap(err, "scan preprocess handler")
+	}
+	if len(policies) == 0 {
+		log.Debugf("The %s event of %s@%s is filtered out by all the policies", e.EventType, e.Artifact.Repository, e.Artifact.Digest)
+		return nil
+	}
+
 	err = util.SendHookWithPolicies(ctx, policies, payload, e.EventType)
 	if err != nil {
 		return errors.Wrap(err, "scan preprocess handler")
@@ -87,7 +102,32 @@ func (si *Handler) IsStateful() bool {
 	return false
 }
 
-func constructScanImagePayload(ctx context.Context, event *event.ScanImageEvent, project *proModels.Project) (*model.Payload, error) {
+// filterSubject builds the subject matched against the event filters of the policies, the severity
+// only applies to the completed vulnerability scanning, the failed and stopped ones have no severity
+func filterSubject(e *event.ScanImageEvent, art *artifact.Artifact, payload *model.Payload) *util.FilterSubject {
+	subject := &util.FilterSubject{
+		Repository: util.GetNameFromImgRepoFullName(e.Artifact.Repository),
+		ArtifactID: art.ID,
+	}
+	if len(e.Artifact.Tag) > 0 {
+		subject.Tags = []string{e.Artifact.Tag}
+	}
+	if e.ScanType != v1.ScanTypeVulnerability || e.EventType != event.TopicScanningCompleted {
+		return subject
+	}
+
+	subject.Severity = vuln.None
+	for _, res := range payload.EventData.Resources {
+		for _, s := range res.ScanOverview {
+			if sum, ok := s.(*vuln.NativeReportSummary); ok && sum.Severity.Code() > subject.Severity.Code() {
+				subject.Severity = sum.Severity
+			}
+		}
+	}
+	return subject
+}
+
+func constructScanImagePayload(ctx context.Context, event *event.ScanImageEvent, project *proModels.Project, art *artifact.Artifact) (*model.Payload, error) {
 	repoType := proModels.ProjectPrivate
 	if project.IsPublic() {
 		repoType = proModels.ProjectPublic
@@ -122,10 +162,6 @@ func constructScanImagePayload(ctx context.Context, event *event.ScanImageEvent,
 		return nil, errors.Wrap(err, "construct scan payload")
 	}
 
-	art, err := artifact.Ctl.GetByReference(ctx, event.Artifact.Repository, event.Artifact.Digest, nil)
-	if err != nil {
-		return nil, err
-	}
 	// Wait for reasonable time to make sure the report is ready
 	// Interval=500ms and total time = 5s
 	// If the report is still not ready in the total time, then failed at then
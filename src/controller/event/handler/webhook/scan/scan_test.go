@@ -38,6 +38,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/report"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	artifacttesting "github.com/goharbor/harbor/src/testing/controller/artifact"
 	scantesting "github.com/goharbor/harbor/src/testing/controller/scan"
 	"github.com/goharbor/harbor/src/testing/mock"
@@ -161,3 +162,30 @@ func (m *MockHTTPHandler) Handle(ctx context.Context, value interface{}) error {
 func (m *MockHTTPHandler) IsStateful() bool {
 	return false
 }
+
+func TestFilterSubject(t *testing.T) {
+	art := &artifact.Artifact{}
+	art.ID = 1
+	payload := &model.Payload{EventData: &model.EventData{Resources: []*model.Resource{
+		{ScanOverview: map[string]interface{}{v1.MimeTypeNativeReport: &vuln.NativeReportSummary{Severity: vuln.High}}},
+	}}}
+	e := &event.ScanImageEvent{
+		EventType: event.TopicScanningCompleted,
+		ScanType:  v1.ScanTypeVulnerability,
+		Artifact:  &v1.Artifact{Repository: "library/busybox", Tag: "latest"},
+	}
+	subject := filterSubject(e, art, payload)
+	require.Equal(t, "busybox", subject.Repository)
+	require.Equal(t, []string{"latest"}, subject.Tags)
+	require.Equal(t, vuln.High, subject.Severity)
+
+	// the failed and stopped scanning have no severity to match the threshold
+	for _, eventType := range []string{event.TopicScanningFailed, event.TopicScanningStopped} {
+		e.EventType = eventType
+		require.Empty(t, filterSubject(e, art, payload).Severity)
+	}
+
+	e.EventType = event.TopicScanningCompleted
+	e.ScanType = v1.ScanTypeSbom
+	require.Empty(t, filterSubject(e, art, payload).Severity)
+}
@@ -0,0 +1,146 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/handler/util"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	notifyModel "github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+// Handler preprocess the project lifecycle events and the system events which are only
+// subscribed by the system level webhook policies
+type Handler struct {
+}
+
+// Name ...
+func (s *Handler) Name() string {
+	return "SystemWebhook"
+}
+
+// Handle ...
+func (s *Handler) Handle(ctx context.Context, value interface{}) error {
+	payload, err := constructSystemPayload(value)
+	if err != nil {
+		return err
+	}
+
+	policies, err := notification.PolicyMgr.GetRelatedPolices(ctx, policy_model.SystemProjectID, payload.Type)
+	if err != nil {
+		log.Errorf("failed to find policy for %s event: %v", payload.Type, err)
+		return err
+	}
+	if len(policies) == 0 {
+		log.Debugf("cannot find policy for %s event: %v", payload.Type, value)
+		return nil
+	}
+
+	return util.SendHookWithPolicies(ctx, policies, payload, payload.Type)
+}
+
+// IsStateful ...
+func (s *Handler) IsStateful() bool {
+	return false
+}
+
+func constructSystemPayload(value interface{}) (*notifyModel.Payload, error) {
+	var (
+		payload = &notifyModel.Payload{EventData: &notifyModel.EventData{}}
+		custom  map[string]string
+		occurAt time.Time
+	)
+	switch e := value.(type) {
+	case *event.CreateProjectEvent:
+		payload.Type, payload.Operator, occurAt = e.EventType, e.Operator, e.OccurAt
+		custom = map[string]string{
+			"project_id": strconv.FormatInt(e.ProjectID, 10),
+			"project":    e.Project,
+			"Details":    fmt.Sprintf("project %s is created", e.Project),
+		}
+	case *event.DeleteProjectEvent:
+		payload.Type, payload.Operator, occurAt = e.EventType, e.Operator, e.OccurAt
+		custom = map[string]string{
+			"project_id": strconv.FormatInt(e.ProjectID, 10),
+			"project":    e.Project,
+			"Details":    fmt.Sprintf("project %s is deleted", e.Project),
+		}
+	case *event.SystemJobEvent:
+		payload.Type, occurAt = e.EventType, e.OccurAt
+		custom = map[string]string{
+			"execution_id": strconv.FormatInt(e.ExecutionID, 10),
+			"vendor_type":  e.VendorType,
+			"trigger":      e.Trigger,
+			"status":       e.Status,
+			"Details":      fmt.Sprintf("the %s execution %d finished with status %s", e.VendorType, e.ExecutionID, e.Status),
+		}
+	case *event.JobQueueLatencyEvent:
+		payload.Type, occurAt = e.EventType, e.OccurAt
+		custom = map[string]string{
+			"job_type":  e.JobType,
+			"count":     strconv.FormatInt(e.Count, 10),
+			"latency":   strconv.FormatInt(e.Latency, 10),
+			"threshold": strconv.FormatInt(e.Threshold, 10),
+			"Details": fmt.Sprintf("the latency of the %s queue with %d pending jobs is %ds, exceeds the threshold %ds",
+				e.JobType, e.Count, e.Latency, e.Threshold),
+		}
+	case *event.StorageLowEvent:
+		payload.Type, occurAt = e.EventType, e.OccurAt
+		custom = map[string]string{
+			"total":     strconv.FormatUint(e.Total, 10),
+			"free":      strconv.FormatUint(e.Free, 10),
+			"threshold": strconv.FormatInt(e.Threshold, 10),
+			"Details": fmt.Sprintf("the free space %d of the registry storage %d is less than %d%%",
+				e.Free, e.Total, e.Threshold),
+		}
+	case *event.CVEAllowlistExpiringEvent:
+		payload.Type, occurAt = e.EventType, e.OccurAt
+		custom = map[string]string{
+			"project_id": strconv.FormatInt(e.ProjectID, 10),
+			"cve_id":     e.CVEID,
+			"repository": e.Repository,
+			"package":    e.Package,
+			"approver":   e.Approver,
+			"added_by":   e.AddedBy,
+			"expires_at": strconv.FormatInt(e.ExpiresAt, 10),
+			"Details": fmt.Sprintf("the allowlist item of %s expires at %s",
+				e.CVEID, time.Unix(e.ExpiresAt, 0).UTC().Format(time.RFC3339)),
+		}
+	case *event.ScannerHealthChangedEvent:
+		payload.Type, occurAt = e.EventType, e.OccurAt
+		custom = map[string]string{
+			"scanner_id": e.UUID,
+			"scanner":    e.Name,
+			"url":        e.URL,
+			"health":     e.Health,
+			"previous":   e.Previous,
+			"Details":    fmt.Sprintf("the health of the scanner %s changes from %s to %s", e.Name, e.Previous, e.Health),
+		}
+	default:
+		return nil, errors.Errorf("invalid system event type %T", value)
+	}
+
+	payload.OccurAt = occurAt.Unix()
+	payload.EventData.Custom = custom
+	return payload, nil
+}
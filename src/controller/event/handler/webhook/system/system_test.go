@@ -0,0 +1,246 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/pkg/allowlist/models"
+	jm "github.com/goharbor/harbor/src/pkg/jobmonitor"
+	n_event "github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
+	"github.com/goharbor/harbor/src/pkg/systeminfo/imagestorage"
+)
+
+func TestConstructSystemPayload(t *testing.T) {
+	now := time.Now()
+	payload, err := constructSystemPayload(&event.SystemJobEvent{
+		EventType:   event.TopicGCCompleted,
+		ExecutionID: 1,
+		VendorType:  "GARBAGE_COLLECTION",
+		Trigger:     "SCHEDULE",
+		Status:      "Success",
+		OccurAt:     now,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, event.TopicGCCompleted, payload.Type)
+	assert.Equal(t, now.Unix(), payload.OccurAt)
+	assert.Equal(t, "1", payload.EventData.Custom["execution_id"])
+	assert.Equal(t, "Success", payload.EventData.Custom["status"])
+	assert.Nil(t, payload.EventData.Repository)
+
+	payload, err = constructSystemPayload(&event.CreateProjectEvent{
+		EventType: event.TopicCreateProject,
+		ProjectID: 2,
+		Project:   "library",
+		Operator:  "admin",
+		OccurAt:   now,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "admin", payload.Operator)
+	assert.Equal(t, "library", payload.EventData.Custom["project"])
+
+	_, err = constructSystemPayload(&event.QuotaEvent{})
+	assert.Error(t, err)
+}
+
+func TestCheckQueueLatency(t *testing.T) {
+	stubRedis(t)
+	defer func(l func(context.Context) ([]*jm.Queue, error), p func(context.Context, ...n_event.Metadata)) {
+		listQueues, publish = l, p
+	}(listQueues, publish)
+
+	listQueues = func(context.Context) ([]*jm.Queue, error) {
+		return []*jm.Queue{
+			{JobType: "GARBAGE_COLLECTION", Count: 1, Latency: 700},
+			{JobType: "REPLICATION", Count: 1, Latency: 10},
+			{JobType: "IMAGE_SCAN", Count: 0, Latency: 900},
+			{JobType: "WEBHOOK", Count: 5, Latency: 900, Paused: true},
+		}, nil
+	}
+	var published []n_event.Metadata
+	publish = func(_ context.Context, m ...n_event.Metadata) {
+		published = append(published, m...)
+	}
+	checkQueueLatency(context.TODO(), 600)
+	require.Len(t, published, 1)
+	assert.Equal(t, &metadata.JobQueueLatencyMetaData{JobType: "GARBAGE_COLLECTION", Count: 1, Latency: 700, Threshold: 600}, published[0])
+}
+
+func TestCheckStorage(t *testing.T) {
+	stubRedis(t)
+	defer func(g func(context.Context) (*imagestorage.Capacity, error), p func(context.Context, ...n_event.Metadata)) {
+		getCapacity, publish = g, p
+	}(getCapacity, publish)
+
+	var published []n_event.Metadata
+	publish = func(_ context.Context, m ...n_event.Metadata) {
+		published = append(published, m...)
+	}
+	getCapacity = func(context.Context) (*imagestorage.Capacity, error) {
+		return &imagestorage.Capacity{Total: 1000, Free: 200}, nil
+	}
+	checkStorage(context.TODO(), 10)
+	assert.Empty(t, published)
+
+	getCapacity = func(context.Context) (*imagestorage.Capacity, error) {
+		return &imagestorage.Capacity{Total: 1000, Free: 50}, nil
+	}
+	checkStorage(context.TODO(), 10)
+	require.Len(t, published, 1)
+	assert.Equal(t, &metadata.StorageLowMetaData{Total: 1000, Free: 50, Threshold: 10}, published[0])
+}
+
+func TestCheckCVEAllowlists(t *testing.T) {
+	stubRedis(t)
+	defer func(l func(context.Context) ([]*models.CVEAllowlist, error), p func(context.Context, ...n_event.Metadata)) {
+		listAllowlists, publish = l, p
+	}(listAllowlists, publish)
+
+	past := time.Now().Add(-time.Hour).Unix()
+	soon := time.Now().Add(24 * time.Hour).Unix()
+	later := time.Now().Add(30 * 24 * time.Hour).Unix()
+	listAllowlists = func(context.Context) ([]*models.CVEAllowlist, error) {
+		return []*models.CVEAllowlist{
+			{
+				ProjectID: 0,
+				Items: []models.CVEAllowlistItem{
+					{CVEID: "CVE-2024-0001"},
+					{CVEID: "CVE-2024-0002", ExpiresAt: &past},
+					{CVEID: "CVE-2024-0003", ExpiresAt: &later},
+				},
+			},
+			{
+				ProjectID: 1,
+				Items: []models.CVEAllowlistItem{
+					{CVEID: "CVE-2024-0004", ExpiresAt: &soon, Repository: "library/*", AddedBy: "admin"},
+				},
+			},
+		}, nil
+	}
+	var published []n_event.Metadata
+	publish = func(_ context.Context, m ...n_event.Metadata) {
+		published = append(published, m...)
+	}
+	checkCVEAllowlists(context.TODO(), 7)
+	require.Len(t, published, 1)
+	md, ok := published[0].(*metadata.CVEAllowlistExpiringMetaData)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), md.ProjectID)
+	assert.Equal(t, "CVE-2024-0004", md.Item.CVEID)
+
+	evt := &n_event.Event{}
+	require.NoError(t, md.Resolve(evt))
+	payload, err := constructSystemPayload(evt.Data)
+	require.NoError(t, err)
+	assert.Equal(t, event.TopicCVEAllowlistExpiring, payload.Type)
+	assert.Equal(t, "library/*", payload.EventData.Custom["repository"])
+	assert.Equal(t, "admin", payload.EventData.Custom["added_by"])
+}
+
+// stubRedis replaces the redis shared by the core instances with a map
+func stubRedis(t *testing.T) {
+	s, w := setIfAbsent, swap
+	t.Cleanup(func() {
+		setIfAbsent, swap = s, w
+	})
+	values := map[string]string{}
+	setIfAbsent = func(_ context.Context, key string, value interface{}, _ time.Duration) (bool, error) {
+		if _, ok := values[key]; ok {
+			return false, nil
+		}
+		values[key] = fmt.Sprint(value)
+		return true, nil
+	}
+	swap = func(_ context.Context, key string, value string, _ time.Duration) (string, error) {
+		old := values[key]
+		values[key] = value
+		return old, nil
+	}
+}
+
+func TestCoolDown(t *testing.T) {
+	stubRedis(t)
+	assert.True(t, coolDown(context.TODO(), "key", time.Hour))
+	assert.False(t, coolDown(context.TODO(), "key", time.Hour))
+	assert.True(t, coolDown(context.TODO(), "another", time.Hour))
+
+	setIfAbsent = func(context.Context, string, interface{}, time.Duration) (bool, error) {
+		return false, fmt.Errorf("redis unavailable")
+	}
+	assert.False(t, coolDown(context.TODO(), "key", time.Hour))
+}
+
+func TestCheckScannerHealth(t *testing.T) {
+	stubRedis(t)
+	defer func(l func(context.Context) ([]*scanner.Registration, error), pg func(context.Context, *scanner.Registration) error,
+		p func(context.Context, ...n_event.Metadata)) {
+		listScanners, pingScanner, publish = l, pg, p
+	}(listScanners, pingScanner, publish)
+
+	listScanners = func(context.Context) ([]*scanner.Registration, error) {
+		return []*scanner.Registration{
+			{UUID: "1", Name: "trivy", URL: "http://trivy:8080"},
+			{UUID: "2", Name: "another", URL: "http://another:8080"},
+		}, nil
+	}
+	down := map[string]bool{}
+	pingScanner = func(_ context.Context, r *scanner.Registration) error {
+		if down[r.UUID] {
+			return fmt.Errorf("scanner %s is down", r.Name)
+		}
+		return nil
+	}
+	var published []n_event.Metadata
+	publish = func(_ context.Context, m ...n_event.Metadata) {
+		published = append(published, m...)
+	}
+
+	// the first check only records the health
+	checkScannerHealth(context.TODO())
+	assert.Empty(t, published)
+
+	down["1"] = true
+	checkScannerHealth(context.TODO())
+	require.Len(t, published, 1)
+	assert.Equal(t, &metadata.ScannerHealthChangedMetaData{
+		UUID: "1", Name: "trivy", URL: "http://trivy:8080", Health: scannerUnhealthy, Previous: scannerHealthy,
+	}, published[0])
+
+	// unchanged
+	checkScannerHealth(context.TODO())
+	assert.Len(t, published, 1)
+
+	down["1"] = false
+	checkScannerHealth(context.TODO())
+	require.Len(t, published, 2)
+
+	evt := &n_event.Event{}
+	require.NoError(t, published[1].Resolve(evt))
+	payload, err := constructSystemPayload(evt.Data)
+	require.NoError(t, err)
+	assert.Equal(t, event.TopicScannerHealthChanged, payload.Type)
+	assert.Equal(t, "trivy", payload.EventData.Custom["scanner"])
+	assert.Equal(t, scannerHealthy, payload.EventData.Custom["health"])
+	assert.Equal(t, scannerUnhealthy, payload.EventData.Custom["previous"])
+}
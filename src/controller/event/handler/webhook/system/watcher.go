@@ -0,0 +1,233 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/jobmonitor"
+	sc "github.com/goharbor/harbor/src/controller/scanner"
+	"github.com/goharbor/harbor/src/controller/systeminfo"
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/gtask"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/q"
+	libredis "github.com/goharbor/harbor/src/lib/redis"
+	"github.com/goharbor/harbor/src/pkg/allowlist"
+	"github.com/goharbor/harbor/src/pkg/allowlist/models"
+	jm "github.com/goharbor/harbor/src/pkg/jobmonitor"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
+	"github.com/goharbor/harbor/src/pkg/systeminfo/imagestorage"
+)
+
+const (
+	// watchInterval is the interval to check the status of the jobservice queues and the registry storage
+	watchInterval = 5 * time.Minute
+	// alertCooldown is the period the same system event isn't published again, the cooldown is shared
+	// by all the core instances via the redis
+	alertCooldown = time.Hour
+	// scannerHealthExpiration is the expiration of the recorded health status of the scanners
+	scannerHealthExpiration = 24 * time.Hour
+
+	scannerHealthy   = "healthy"
+	scannerUnhealthy = "unhealthy"
+)
+
+var (
+	listQueues = func(ctx context.Context) ([]*jm.Queue, error) {
+		return jobmonitor.Ctl.ListQueues(ctx)
+	}
+	getCapacity = func(ctx context.Context) (*imagestorage.Capacity, error) {
+		return systeminfo.Ctl.GetCapacity(ctx)
+	}
+	listAllowlists = func(ctx context.Context) ([]*models.CVEAllowlist, error) {
+		return allowlist.NewDefaultManager().List(ctx)
+	}
+	listScanners = func(ctx context.Context) ([]*scanner.Registration, error) {
+		return sc.DefaultController.ListRegistrations(ctx, q.New(q.KeyWords{"disabled": false}))
+	}
+	pingScanner = func(ctx context.Context, r *scanner.Registration) error {
+		_, err := sc.DefaultController.Ping(ctx, r)
+		return err
+	}
+	publish = event.BuildAndPublish
+
+	// setIfAbsent sets the value of the key only if the key doesn't exist and returns whether it's set
+	setIfAbsent = func(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return false, err
+		}
+		return client.SetNX(ctx, key, value, expiration).Result()
+	}
+	// swap sets the value of the key and returns the old value atomically, the old value is empty if the key doesn't exist
+	swap = func(ctx context.Context, key string, value string, expiration time.Duration) (string, error) {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return "", err
+		}
+		var old *redis.StringCmd
+		if _, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			old = pipe.GetSet(ctx, key, value)
+			pipe.Expire(ctx, key, expiration)
+			return nil
+		}); err != nil && err != redis.Nil {
+			return "", err
+		}
+		return old.Val(), nil
+	}
+)
+
+func init() {
+	gtask.DefaultPool().AddTask(watch, watchInterval)
+}
+
+// watch publishes the system events when the latency of the jobservice queues or the free space
+// of the registry storage breaches the thresholds, the health of the scanners changes, or the CVE
+// allowlist items are approaching their expiry
+func watch(ctx context.Context) {
+	if !config.NotificationEnable(ctx) {
+		return
+	}
+	if threshold := config.NotificationQueueLatencyThreshold(ctx); threshold > 0 {
+		checkQueueLatency(ctx, threshold)
+	}
+	if threshold := config.NotificationStorageFreeThreshold(ctx); threshold > 0 {
+		checkStorage(ctx, threshold)
+	}
+	checkScannerHealth(ctx)
+	if days := config.NotificationCVEAllowlistExpiryDays(ctx); days > 0 {
+		checkCVEAllowlists(ctx, days)
+	}
+}
+
+func checkQueueLatency(ctx context.Context, threshold int64) {
+	queues, err := listQueues(ctx)
+	if err != nil {
+		log.Warningf("failed to list the jobservice queues to check the latency: %v", err)
+		return
+	}
+	for _, queue := range queues {
+		if queue.Paused || queue.Count == 0 || queue.Latency < threshold {
+			continue
+		}
+		if !coolDown(ctx, fmt.Sprintf("system_event:queue_latency:%s", queue.JobType), alertCooldown) {
+			continue
+		}
+		publish(ctx, &metadata.JobQueueLatencyMetaData{
+			JobType:   queue.JobType,
+			Count:     queue.Count,
+			Latency:   queue.Latency,
+			Threshold: threshold,
+		})
+	}
+}
+
+func checkStorage(ctx context.Context, threshold int64) {
+	capacity, err := getCapacity(ctx)
+	if err != nil {
+		log.Debugf("failed to get the capacity of the registry storage: %v", err)
+		return
+	}
+	if capacity == nil || capacity.Total == 0 || capacity.Free*100 >= capacity.Total*uint64(threshold) {
+		return
+	}
+	if !coolDown(ctx, "system_event:storage_low", alertCooldown) {
+		return
+	}
+	publish(ctx, &metadata.StorageLowMetaData{
+		Total:     capacity.Total,
+		Free:      capacity.Free,
+		Threshold: threshold,
+	})
+}
+
+// checkScannerHealth pings the enabled scanners and publishes the event when the health of the scanner
+// differs from the one recorded by the previous check of any core instance
+func checkScannerHealth(ctx context.Context) {
+	scanners, err := listScanners(ctx)
+	if err != nil {
+		log.Warningf("failed to list the scanners to check the health: %v", err)
+		return
+	}
+	for _, r := range scanners {
+		health := scannerHealthy
+		if err := pingScanner(ctx, r); err != nil {
+			log.Debugf("failed to ping the scanner %s: %v", r.Name, err)
+			health = scannerUnhealthy
+		}
+		previous, err := swap(ctx, fmt.Sprintf("system_event:scanner_health:%s", r.UUID), health, scannerHealthExpiration)
+		if err != nil {
+			log.Warningf("failed to record the health of the scanner %s: %v", r.Name, err)
+			continue
+		}
+		// nothing to compare with for the first check
+		if len(previous) == 0 || previous == health {
+			continue
+		}
+		publish(ctx, &metadata.ScannerHealthChangedMetaData{
+			UUID:     r.UUID,
+			Name:     r.Name,
+			URL:      r.URL,
+			Health:   health,
+			Previous: previous,
+		})
+	}
+}
+
+// checkCVEAllowlists publishes the events of the unexpired CVE allowlist items which expire in the days,
+// the event of each item is published once before its expiry
+func checkCVEAllowlists(ctx context.Context, days int64) {
+	lists, err := listAllowlists(ctx)
+	if err != nil {
+		log.Warningf("failed to list the CVE allowlists to check the expiry: %v", err)
+		return
+	}
+	window := time.Duration(days) * 24 * time.Hour
+	deadline := time.Now().Add(window).Unix()
+	for _, l := range lists {
+		for _, it := range l.Items {
+			if it.ExpiresAt == nil || it.IsExpired() || *it.ExpiresAt > deadline {
+				continue
+			}
+			key := fmt.Sprintf("system_event:cve_allowlist_expiring:%d:%s:%s:%s:%d",
+				l.ProjectID, it.CVEID, it.Repository, it.Package, *it.ExpiresAt)
+			if !coolDown(ctx, key, window) {
+				continue
+			}
+			publish(ctx, &metadata.CVEAllowlistExpiringMetaData{
+				ProjectID: l.ProjectID,
+				Item:      it,
+			})
+		}
+	}
+}
+
+// coolDown returns whether the event identified by the key can be published, and starts the cooldown of the event if so,
+// only one of the core instances checking the same event at the same time can start the cooldown
+func coolDown(ctx context.Context, key string, cooldown time.Duration) bool {
+	ok, err := setIfAbsent(ctx, key, time.Now().Unix(), cooldown)
+	if err != nil {
+		log.Warningf("failed to start the cooldown of the system event %s: %v", key, err)
+		return false
+	}
+	return ok
+}
@@ -0,0 +1,69 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/security"
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+// APIOperationMetadata is the metadata from which the API operation event can be resolved
+type APIOperationMetadata struct {
+	Ctx          context.Context
+	ProjectID    int64
+	ResourceType string
+	Resource     string
+	Operation    string
+	Description  string
+	Succeeded    bool
+	Payload      string
+	ClientIP     string
+	UserAgent    string
+	AuthMethod   string
+	// Username overrides the operator populated from the security context,
+	// it is used when the request isn't authenticated yet, e.g. login
+	Username string
+}
+
+// Resolve to the event from the metadata
+func (a *APIOperationMetadata) Resolve(event *event.Event) error {
+	data := &event2.APIOperationEvent{
+		EventType:    event2.TopicAPIOperation,
+		ProjectID:    a.ProjectID,
+		ResourceType: a.ResourceType,
+		Resource:     a.Resource,
+		Operation:    a.Operation,
+		Description:  a.Description,
+		Succeeded:    a.Succeeded,
+		Payload:      a.Payload,
+		ClientIP:     a.ClientIP,
+		UserAgent:    a.UserAgent,
+		AuthMethod:   a.AuthMethod,
+		Operator:     a.Username,
+		OccurAt:      time.Now(),
+	}
+	if len(data.Operator) == 0 {
+		if cx, exist := security.FromContext(a.Ctx); exist {
+			data.Operator = cx.GetUsername()
+		}
+	}
+	event.Topic = event2.TopicAPIOperation
+	event.Data = data
+	return nil
+}
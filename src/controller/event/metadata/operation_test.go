@@ -0,0 +1,65 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+type apiOperationEventTestSuite struct {
+	suite.Suite
+}
+
+func (a *apiOperationEventTestSuite) TestResolve() {
+	e := &event.Event{}
+	metadata := &APIOperationMetadata{
+		Ctx:          context.Background(),
+		ProjectID:    1,
+		ResourceType: "member",
+		Resource:     "3",
+		Operation:    "update",
+		Succeeded:    true,
+		ClientIP:     "10.0.0.1",
+		UserAgent:    "curl",
+		AuthMethod:   "basic",
+		Username:     "admin",
+	}
+	err := metadata.Resolve(e)
+	a.Require().Nil(err)
+	a.Equal(event2.TopicAPIOperation, e.Topic)
+	a.Require().NotNil(e.Data)
+	data, ok := e.Data.(*event2.APIOperationEvent)
+	a.Require().True(ok)
+	a.Equal("admin", data.Operator)
+	a.Equal("member", data.ResourceType)
+
+	al, err := data.ResolveToAuditLog()
+	a.Require().Nil(err)
+	a.Equal(int64(1), al.ProjectID)
+	a.True(al.IsSuccessful)
+	a.Equal("10.0.0.1", al.ClientIP)
+	a.Equal("curl", al.UserAgent)
+	a.Equal("basic", al.AuthMethod)
+}
+
+func TestAPIOperationEventTestSuite(t *testing.T) {
+	suite.Run(t, &apiOperationEventTestSuite{})
+}
@@ -0,0 +1,155 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"time"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/allowlist/models"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+// systemJobTopics maps the vendor types of the system jobs to the topics of their completion events
+var systemJobTopics = map[string]string{
+	job.GarbageCollectionVendorType: event2.TopicGCCompleted,
+	job.PurgeAuditVendorType:        event2.TopicPurgeAuditCompleted,
+	job.ScanAllVendorType:           event2.TopicScanAllCompleted,
+}
+
+// SystemJobVendorTypes returns the vendor types of the system jobs whose completion events are published
+func SystemJobVendorTypes() []string {
+	return []string{job.GarbageCollectionVendorType, job.PurgeAuditVendorType, job.ScanAllVendorType}
+}
+
+// SystemJobMetaData is the metadata from which the system job completion event can be resolved
+type SystemJobMetaData struct {
+	ExecutionID int64
+	VendorType  string
+	Trigger     string
+	Status      string
+}
+
+// Resolve to the event from the metadata
+func (s *SystemJobMetaData) Resolve(evt *event.Event) error {
+	topic, ok := systemJobTopics[s.VendorType]
+	if !ok {
+		return fmt.Errorf("unsupported system job vendor type %s", s.VendorType)
+	}
+	evt.Topic = topic
+	evt.Data = &event2.SystemJobEvent{
+		EventType:   topic,
+		ExecutionID: s.ExecutionID,
+		VendorType:  s.VendorType,
+		Trigger:     s.Trigger,
+		Status:      s.Status,
+		OccurAt:     time.Now(),
+	}
+	return nil
+}
+
+// JobQueueLatencyMetaData is the metadata from which the job queue latency event can be resolved
+type JobQueueLatencyMetaData struct {
+	JobType   string
+	Count     int64
+	Latency   int64
+	Threshold int64
+}
+
+// Resolve to the event from the metadata
+func (j *JobQueueLatencyMetaData) Resolve(evt *event.Event) error {
+	evt.Topic = event2.TopicJobQueueLatency
+	evt.Data = &event2.JobQueueLatencyEvent{
+		EventType: event2.TopicJobQueueLatency,
+		JobType:   j.JobType,
+		Count:     j.Count,
+		Latency:   j.Latency,
+		Threshold: j.Threshold,
+		OccurAt:   time.Now(),
+	}
+	return nil
+}
+
+// StorageLowMetaData is the metadata from which the storage low event can be resolved
+type StorageLowMetaData struct {
+	Total     uint64
+	Free      uint64
+	Threshold int64
+}
+
+// Resolve to the event from the metadata
+func (s *StorageLowMetaData) Resolve(evt *event.Event) error {
+	evt.Topic = event2.TopicStorageLow
+	evt.Data = &event2.StorageLowEvent{
+		EventType: event2.TopicStorageLow,
+		Total:     s.Total,
+		Free:      s.Free,
+		Threshold: s.Threshold,
+		OccurAt:   time.Now(),
+	}
+	return nil
+}
+
+// ScannerHealthChangedMetaData is the metadata from which the scanner health changed event can be resolved
+type ScannerHealthChangedMetaData struct {
+	UUID     string
+	Name     string
+	URL      string
+	Health   string
+	Previous string
+}
+
+// Resolve to the event from the metadata
+func (s *ScannerHealthChangedMetaData) Resolve(evt *event.Event) error {
+	evt.Topic = event2.TopicScannerHealthChanged
+	evt.Data = &event2.ScannerHealthChangedEvent{
+		EventType: event2.TopicScannerHealthChanged,
+		UUID:      s.UUID,
+		Name:      s.Name,
+		URL:       s.URL,
+		Health:    s.Health,
+		Previous:  s.Previous,
+		OccurAt:   time.Now(),
+	}
+	return nil
+}
+
+// CVEAllowlistExpiringMetaData is the metadata from which the CVE allowlist expiring event can be resolved
+type CVEAllowlistExpiringMetaData struct {
+	ProjectID int64
+	Item      models.CVEAllowlistItem
+}
+
+// Resolve to the event from the metadata
+func (c *CVEAllowlistExpiringMetaData) Resolve(evt *event.Event) error {
+	if c.Item.ExpiresAt == nil {
+		return fmt.Errorf("the allowlist item of %s doesn't expire", c.Item.CVEID)
+	}
+	evt.Topic = event2.TopicCVEAllowlistExpiring
+	evt.Data = &event2.CVEAllowlistExpiringEvent{
+		EventType:  event2.TopicCVEAllowlistExpiring,
+		ProjectID:  c.ProjectID,
+		CVEID:      c.Item.CVEID,
+		Repository: c.Item.Repository,
+		Package:    c.Item.Package,
+		Approver:   c.Item.Approver,
+		AddedBy:    c.Item.AddedBy,
+		ExpiresAt:  *c.Item.ExpiresAt,
+		OccurAt:    time.Now(),
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	event2 "github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+)
+
+type systemEventTestSuite struct {
+	suite.Suite
+}
+
+func (s *systemEventTestSuite) TestResolveOfSystemJobMetaData() {
+	e := &event.Event{}
+	metadata := &SystemJobMetaData{
+		ExecutionID: 1,
+		VendorType:  job.GarbageCollectionVendorType,
+		Trigger:     "MANUAL",
+		Status:      job.SuccessStatus.String(),
+	}
+	s.Require().Nil(metadata.Resolve(e))
+	s.Equal(event2.TopicGCCompleted, e.Topic)
+	data, ok := e.Data.(*event2.SystemJobEvent)
+	s.Require().True(ok)
+	s.Equal(int64(1), data.ExecutionID)
+	s.Equal(event2.TopicGCCompleted, data.EventType)
+	s.Equal("Success", data.Status)
+
+	metadata.VendorType = job.ReplicationVendorType
+	s.NotNil(metadata.Resolve(&event.Event{}))
+}
+
+func (s *systemEventTestSuite) TestResolveOfJobQueueLatencyMetaData() {
+	e := &event.Event{}
+	metadata := &JobQueueLatencyMetaData{JobType: "GARBAGE_COLLECTION", Count: 3, Latency: 700, Threshold: 600}
+	s.Require().Nil(metadata.Resolve(e))
+	s.Equal(event2.TopicJobQueueLatency, e.Topic)
+	data, ok := e.Data.(*event2.JobQueueLatencyEvent)
+	s.Require().True(ok)
+	s.Equal("GARBAGE_COLLECTION", data.JobType)
+	s.Equal(int64(700), data.Latency)
+}
+
+func (s *systemEventTestSuite) TestResolveOfStorageLowMetaData() {
+	e := &event.Event{}
+	metadata := &StorageLowMetaData{Total: 100, Free: 5, Threshold: 10}
+	s.Require().Nil(metadata.Resolve(e))
+	s.Equal(event2.TopicStorageLow, e.Topic)
+	data, ok := e.Data.(*event2.StorageLowEvent)
+	s.Require().True(ok)
+	s.Equal(uint64(5), data.Free)
+}
+
+func (s *systemEventTestSuite) TestResolveOfScannerHealthChangedMetaData() {
+	e := &event.Event{}
+	metadata := &ScannerHealthChangedMetaData{UUID: "1", Name: "trivy", Health: "unhealthy", Previous: "healthy"}
+	s.Require().Nil(metadata.Resolve(e))
+	s.Equal(event2.TopicScannerHealthChanged, e.Topic)
+	data, ok := e.Data.(*event2.ScannerHealthChangedEvent)
+	s.Require().True(ok)
+	s.Equal("trivy", data.Name)
+	s.Equal("unhealthy", data.Health)
+	s.Equal("healthy", data.Previous)
+}
+
+func TestSystemEventTestSuite(t *testing.T) {
+	suite.Run(t, &systemEventTestSuite{})
+}
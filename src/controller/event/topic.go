@@ -50,6 +50,16 @@ const (
 	TopicTagRetention    = "TAG_RETENTION"
 	TopicCreateRobot     = "CREATE_ROBOT"
 	TopicDeleteRobot     = "DELETE_ROBOT"
+	// TopicAPIOperation is topic for the mutating API calls which are recorded in the audit log
+	TopicAPIOperation = "API_OPERATION"
+	// the system events are not related to any project, they are only subscribed by the system level webhook policies
+	TopicGCCompleted          = "GC_COMPLETED"
+	TopicPurgeAuditCompleted  = "PURGE_AUDIT_COMPLETED"
+	TopicScanAllCompleted     = "SCAN_ALL_COMPLETED"
+	TopicJobQueueLatency      = "JOB_QUEUE_LATENCY_BREACHED"
+	TopicStorageLow           = "STORAGE_LOW"
+	TopicScannerHealthChanged = "SCANNER_HEALTH_CHANGED"
+	TopicCVEAllowlistExpiring = "CVE_ALLOWLIST_EXPIRING"
 )
 
 // CreateProjectEvent is the creating project event
@@ -67,6 +77,7 @@ func (c *CreateProjectEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    c.ProjectID,
 		OpTime:       c.OccurAt,
 		Operation:    rbac.ActionCreate.String(),
+		IsSuccessful: true,
 		Username:     c.Operator,
 		ResourceType: "project",
 		Resource:     c.Project}
@@ -93,6 +104,7 @@ func (d *DeleteProjectEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    d.ProjectID,
 		OpTime:       d.OccurAt,
 		Operation:    rbac.ActionDelete.String(),
+		IsSuccessful: true,
 		Username:     d.Operator,
 		ResourceType: "project",
 		Resource:     d.Project}
@@ -119,6 +131,7 @@ func (d *DeleteRepositoryEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    d.ProjectID,
 		OpTime:       d.OccurAt,
 		Operation:    rbac.ActionDelete.String(),
+		IsSuccessful: true,
 		Username:     d.Operator,
 		ResourceType: "repository",
 		Resource:     d.Repository,
@@ -158,6 +171,7 @@ func (p *PushArtifactEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    p.Artifact.ProjectID,
 		OpTime:       p.OccurAt,
 		Operation:    rbac.ActionCreate.String(),
+		IsSuccessful: true,
 		Username:     p.Operator,
 		ResourceType: "artifact"}
 
@@ -187,6 +201,7 @@ func (p *PullArtifactEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    p.Artifact.ProjectID,
 		OpTime:       p.OccurAt,
 		Operation:    rbac.ActionPull.String(),
+		IsSuccessful: true,
 		Username:     p.Operator,
 		ResourceType: "artifact"}
 
@@ -223,6 +238,7 @@ func (d *DeleteArtifactEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    d.Artifact.ProjectID,
 		OpTime:       d.OccurAt,
 		Operation:    rbac.ActionDelete.String(),
+		IsSuccessful: true,
 		Username:     d.Operator,
 		ResourceType: "artifact",
 		Resource:     fmt.Sprintf("%s@%s", d.Artifact.RepositoryName, d.Artifact.Digest)}
@@ -249,6 +265,7 @@ func (c *CreateTagEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    c.AttachedArtifact.ProjectID,
 		OpTime:       c.OccurAt,
 		Operation:    rbac.ActionCreate.String(),
+		IsSuccessful: true,
 		Username:     c.Operator,
 		ResourceType: "tag",
 		Resource:     fmt.Sprintf("%s:%s", c.Repository, c.Tag)}
@@ -277,6 +294,7 @@ func (d *DeleteTagEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    d.AttachedArtifact.ProjectID,
 		OpTime:       d.OccurAt,
 		Operation:    rbac.ActionDelete.String(),
+		IsSuccessful: true,
 		Username:     d.Operator,
 		ResourceType: "tag",
 		Resource:     fmt.Sprintf("%s:%s", d.Repository, d.Tag)}
@@ -387,6 +405,7 @@ func (c *CreateRobotEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    c.Robot.ProjectID,
 		OpTime:       c.OccurAt,
 		Operation:    rbac.ActionCreate.String(),
+		IsSuccessful: true,
 		Username:     c.Operator,
 		ResourceType: "robot",
 		Resource:     c.Robot.Name}
@@ -412,6 +431,7 @@ func (c *DeleteRobotEvent) ResolveToAuditLog() (*model.AuditLog, error) {
 		ProjectID:    c.Robot.ProjectID,
 		OpTime:       c.OccurAt,
 		Operation:    rbac.ActionDelete.String(),
+		IsSuccessful: true,
 		Username:     c.Operator,
 		ResourceType: "robot",
 		Resource:     c.Robot.Name}
@@ -422,3 +442,127 @@ func (c *DeleteRobotEvent) String() string {
 	return fmt.Sprintf("Name-%s Operator-%s OccurAt-%s",
 		c.Robot.Name, c.Operator, c.OccurAt.Format("2006-01-02 15:04:05"))
 }
+
+// APIOperationEvent is the event of a mutating API call against a resource
+type APIOperationEvent struct {
+	EventType    string
+	ProjectID    int64
+	ResourceType string
+	Resource     string
+	Operation    string
+	Description  string
+	Succeeded    bool
+	Payload      string
+	ClientIP     string
+	UserAgent    string
+	AuthMethod   string
+	Operator     string
+	OccurAt      time.Time
+}
+
+// ResolveToAuditLog ...
+func (a *APIOperationEvent) ResolveToAuditLog() (*model.AuditLog, error) {
+	auditLog := &model.AuditLog{
+		ProjectID:            a.ProjectID,
+		OpTime:               a.OccurAt,
+		Operation:            a.Operation,
+		OperationDescription: a.Description,
+		IsSuccessful:         a.Succeeded,
+		Username:             a.Operator,
+		ResourceType:         a.ResourceType,
+		Resource:             a.Resource,
+		Payload:              a.Payload,
+		ClientIP:             a.ClientIP,
+		UserAgent:            a.UserAgent,
+		AuthMethod:           a.AuthMethod}
+	return auditLog, nil
+}
+
+func (a *APIOperationEvent) String() string {
+	return fmt.Sprintf("ResourceType-%s Resource-%s Operation-%s Succeeded-%t Operator-%s OccurAt-%s",
+		a.ResourceType, a.Resource, a.Operation, a.Succeeded, a.Operator, a.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// SystemJobEvent is the event of the finished system job execution, e.g. GC, audit log purge or scan all
+type SystemJobEvent struct {
+	EventType   string
+	ExecutionID int64
+	VendorType  string
+	Trigger     string
+	Status      string
+	OccurAt     time.Time
+}
+
+func (s *SystemJobEvent) String() string {
+	return fmt.Sprintf("ExecutionID-%d VendorType-%s Trigger-%s Status-%s OccurAt-%s",
+		s.ExecutionID, s.VendorType, s.Trigger, s.Status, s.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// JobQueueLatencyEvent is the event of the latency of the jobservice queue exceeding the threshold
+type JobQueueLatencyEvent struct {
+	EventType string
+	JobType   string
+	Count     int64
+	// Latency and Threshold are in seconds
+	Latency   int64
+	Threshold int64
+	OccurAt   time.Time
+}
+
+func (j *JobQueueLatencyEvent) String() string {
+	return fmt.Sprintf("JobType-%s Count-%d Latency-%ds Threshold-%ds OccurAt-%s",
+		j.JobType, j.Count, j.Latency, j.Threshold, j.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// StorageLowEvent is the event of the free space of the registry storage falling below the threshold
+type StorageLowEvent struct {
+	EventType string
+	// Total and Free are in bytes
+	Total uint64
+	Free  uint64
+	// Threshold is the percentage of the free space
+	Threshold int64
+	OccurAt   time.Time
+}
+
+func (s *StorageLowEvent) String() string {
+	return fmt.Sprintf("Total-%d Free-%d Threshold-%d%% OccurAt-%s",
+		s.Total, s.Free, s.Threshold, s.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// ScannerHealthChangedEvent is the event of the health status of the enabled scanner changing
+type ScannerHealthChangedEvent struct {
+	EventType string
+	UUID      string
+	Name      string
+	URL       string
+	// Health and Previous are the current and the previous health status, healthy or unhealthy
+	Health   string
+	Previous string
+	OccurAt  time.Time
+}
+
+func (s *ScannerHealthChangedEvent) String() string {
+	return fmt.Sprintf("UUID-%s Name-%s Health-%s Previous-%s OccurAt-%s",
+		s.UUID, s.Name, s.Health, s.Previous, s.OccurAt.Format("2006-01-02 15:04:05"))
+}
+
+// CVEAllowlistExpiringEvent is the event of the CVE allowlist item approaching its expiry
+type CVEAllowlistExpiringEvent struct {
+	EventType string
+	// ProjectID is 0 for the item of the system level allowlist
+	ProjectID  int64
+	CVEID      string
+	Repository string
+	Package    string
+	Approver   string
+	AddedBy    string
+	ExpiresAt  int64
+	OccurAt    time.Time
+}
+
+func (c *CVEAllowlistExpiringEvent) String() string {
+	return fmt.Sprintf("ProjectID-%d CVEID-%s Repository-%s Package-%s ExpiresAt-%s OccurAt-%s",
+		c.ProjectID, c.CVEID, c.Repository, c.Package, time.Unix(c.ExpiresAt, 0).Format("2006-01-02 15:04:05"),
+		c.OccurAt.Format("2006-01-02 15:04:05"))
+}
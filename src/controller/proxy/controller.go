@@ -34,7 +34,10 @@ import (
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/pkg"
+	pkgartifact "github.com/goharbor/harbor/src/pkg/artifact"
 	proModels "github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
 	model_tag "github.com/goharbor/harbor/src/pkg/tag/model/tag"
 )
 
@@ -45,6 +48,10 @@ const (
 	sleepIntervalSec    = 20
 	// keep manifest list in cache for one week
 	manifestListCacheInterval = 7 * 24 * 60 * 60 * time.Second
+	// keep the expired tag to digest resolution in cache for one week to serve it while revalidating
+	staleFreshnessInterval = 7 * 24 * 60 * 60 * time.Second
+	// UpstreamAttr is the key of the artifact extra attribute which records the upstream registry served the artifact
+	UpstreamAttr = "proxy_cache_upstream"
 )
 
 var (
@@ -57,8 +64,11 @@ var (
 type Controller interface {
 	// UseLocalBlob check if the blob should use local copy
 	UseLocalBlob(ctx context.Context, art lib.ArtifactInfo) bool
-	// UseLocalManifest check manifest should use local copy
-	UseLocalManifest(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface) (bool, *ManifestList, error)
+	// UseLocalManifest check manifest should use local copy, the options carry the freshness policies of the tag
+	UseLocalManifest(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface, opts ...Option) (bool, *ManifestList, error)
+	// LocalManifestExist checks whether the manifest exists in local, it's used to serve the stale local copy
+	// when the upstream registry is unavailable
+	LocalManifestExist(ctx context.Context, art lib.ArtifactInfo) (bool, error)
 	// ProxyBlob proxy the blob request to the remote server, p is the proxy project
 	// art is the ArtifactInfo which includes the digest of the blob
 	ProxyBlob(ctx context.Context, p *proModels.Project, art lib.ArtifactInfo) (int64, io.ReadCloser, error)
@@ -74,6 +84,7 @@ type Controller interface {
 type controller struct {
 	blobCtl         blob.Controller
 	artifactCtl     artifact.Controller
+	artMgr          pkgartifact.Manager
 	local           localInterface
 	cache           cache.Cache
 	handlerRegistry map[string]ManifestCacheHandler
@@ -88,6 +99,7 @@ func ControllerInstance() Controller {
 		ctl = &controller{
 			blobCtl:         blob.Ctl,
 			artifactCtl:     artifact.Ctl,
+			artMgr:          pkg.ArtifactMgr,
 			local:           newLocalHelper(),
 			cache:           cache.Default(),
 			handlerRegistry: NewCacheHandlerRegistry(l),
@@ -153,7 +165,7 @@ type ManifestList struct {
 // the return error should be nil when it is not found in local and need to delegate to remote registry
 // the return error should be NotFoundError when it is not found in remote registry
 // the error will be captured by framework and return 404 to client
-func (c *controller) UseLocalManifest(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface) (bool, *ManifestList, error) {
+func (c *controller) UseLocalManifest(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface, opts ...Option) (bool, *ManifestList, error) {
 	a, err := c.local.GetManifest(ctx, art)
 	if err != nil {
 		return false, nil, err
@@ -163,16 +175,27 @@ func (c *controller) UseLocalManifest(ctx context.Context, art lib.ArtifactInfo,
 		return true, nil, nil
 	}
 
-	remoteRepo := getRemoteRepo(art)
-	exist, desc, err := remote.ManifestExist(remoteRepo, getReference(art)) // HEAD
-	if err != nil {
-		if errors.IsRateLimitError(err) && a != nil { // if rate limit, use local if it exists, otherwise return error
-			return true, nil, nil
+	options := NewOptions(opts...)
+	var desc *distribution.Descriptor
+	// Use the cached tag to digest resolution instead of consulting the upstream registry when it's fresh
+	if f := c.getFreshness(ctx, art, remote, options); f != nil {
+		desc = &distribution.Descriptor{Digest: digest.Digest(f.Digest)}
+	} else {
+		remoteRepo := getRemoteRepo(art)
+		exist, d, err := remote.ManifestExist(remoteRepo, getReference(art)) // HEAD
+		if err != nil {
+			// if rate limit, use local if it exists, otherwise return error,
+			// the error is returned to the caller to serve the local copy with warning when StaleIfError is enabled
+			if errors.IsRateLimitError(err) && a != nil && !options.StaleIfError {
+				return true, nil, nil
+			}
+			return false, nil, err
 		}
-		return false, nil, err
-	}
-	if !exist || desc == nil {
-		return false, nil, errors.NotFoundError(fmt.Errorf("repo %v, tag %v not found", art.Repository, art.Tag))
+		if !exist || d == nil {
+			return false, nil, errors.NotFoundError(fmt.Errorf("repo %v, tag %v not found", art.Repository, art.Tag))
+		}
+		desc = d
+		c.saveFreshness(ctx, art, string(desc.Digest), options)
 	}
 
 	var content []byte
@@ -202,6 +225,87 @@ func (c *controller) UseLocalManifest(ctx context.Context, art lib.ArtifactInfo,
 	return true, &ManifestList{content, string(desc.Digest), contentType}, nil
 }
 
+func (c *controller) LocalManifestExist(ctx context.Context, art lib.ArtifactInfo) (bool, error) {
+	a, err := c.local.GetManifest(ctx, art)
+	if err != nil {
+		return false, err
+	}
+	return a != nil, nil
+}
+
+// freshness is the tag to digest resolution of the upstream registry
+type freshness struct {
+	Digest    string    `json:"digest"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+func freshnessKey(repo, tag string) string {
+	// actual redis key format is cache:freshness:<repo name>:<tag>
+	return "freshness:" + repo + ":" + tag
+}
+
+// getFreshness returns the cached tag to digest resolution when it's fresh, or when it's expired but
+// could be served while revalidating it in background, otherwise returns nil
+func (c *controller) getFreshness(ctx context.Context, art lib.ArtifactInfo, remote RemoteInterface, options *Options) *freshness {
+	if c.cache == nil || options.TTL <= 0 || len(art.Tag) == 0 || len(art.Digest) > 0 {
+		return nil
+	}
+	f := &freshness{}
+	if err := c.cache.Fetch(ctx, freshnessKey(art.Repository, art.Tag), f); err != nil {
+		if !errors.Is(err, cache.ErrNotFound) {
+			log.Errorf("failed to get the tag to digest resolution from cache, error: %v", err)
+		}
+		return nil
+	}
+	if time.Since(f.CheckedAt) < options.TTL {
+		return f
+	}
+	if options.StaleWhileRevalidate {
+		go c.revalidate(art, remote, options)
+		return f
+	}
+	return nil
+}
+
+// saveFreshness caches the tag to digest resolution when the TTL is set
+func (c *controller) saveFreshness(ctx context.Context, art lib.ArtifactInfo, dig string, options *Options) {
+	if c.cache == nil || options.TTL <= 0 || len(art.Tag) == 0 || len(art.Digest) > 0 {
+		return
+	}
+	expiration := options.TTL
+	if options.StaleWhileRevalidate {
+		expiration += staleFreshnessInterval
+	}
+	f := &freshness{Digest: dig, CheckedAt: time.Now()}
+	if err := c.cache.Save(ctx, freshnessKey(art.Repository, art.Tag), f, expiration); err != nil {
+		log.Errorf("failed to save the tag to digest resolution to cache, error: %v", err)
+	}
+}
+
+// revalidate checks the tag against the upstream registry and refreshes the tag to digest resolution
+func (c *controller) revalidate(art lib.ArtifactInfo, remote RemoteInterface, options *Options) {
+	key := freshnessKey(art.Repository, art.Tag)
+	if !inflightChecker.addRequest(key) {
+		return
+	}
+	defer inflightChecker.removeRequest(key)
+
+	ctx := context.Background()
+	exist, desc, err := remote.ManifestExist(getRemoteRepo(art), art.Tag)
+	if err != nil {
+		log.Warningf("failed to revalidate %s:%s against the upstream registry, error: %v", art.Repository, art.Tag, err)
+		return
+	}
+	if !exist || desc == nil {
+		// drop the resolution to consult the upstream registry for the next pull
+		if err = c.cache.Delete(ctx, key); err != nil {
+			log.Errorf("failed to delete the tag to digest resolution from cache, error: %v", err)
+		}
+		return
+	}
+	c.saveFreshness(ctx, art, string(desc.Digest), options)
+}
+
 func manifestListKey(repo string, art lib.ArtifactInfo) string {
 	// actual redis key format is cache:manifestlist:<repo name>:<tag> or cache:manifestlist:<repo name>:sha256:xxxx
 	return "manifestlist:" + repo + ":" + getReference(art)
@@ -223,6 +327,10 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 	if err != nil {
 		return man, err
 	}
+	var servedBy *model.Registry
+	if reporter, ok := remote.(UpstreamReporter); ok {
+		servedBy = reporter.ServedBy()
+	}
 
 	// Push manifest in background
 	go func(operator string) {
@@ -248,6 +356,7 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 			}
 		}
 		if a != nil {
+			c.recordUpstream(bCtx, a, servedBy)
 			SendPullEvent(bCtx, a, art.Tag, operator)
 		}
 	}(operator.FromContext(ctx))
@@ -255,6 +364,29 @@ func (c *controller) ProxyManifest(ctx context.Context, art lib.ArtifactInfo, re
 	return man, nil
 }
 
+// recordUpstream records the upstream registry served the artifact in the extra attributes of the artifact
+func (c *controller) recordUpstream(ctx context.Context, a *artifact.Artifact, registry *model.Registry) {
+	if c.artMgr == nil || registry == nil {
+		return
+	}
+	if recorded, ok := a.ExtraAttrs[UpstreamAttr].(map[string]interface{}); ok {
+		if id, ok := recorded["registry_id"].(float64); ok && int64(id) == registry.ID {
+			return
+		}
+	}
+	if a.ExtraAttrs == nil {
+		a.ExtraAttrs = map[string]interface{}{}
+	}
+	a.ExtraAttrs[UpstreamAttr] = map[string]interface{}{
+		"registry_id":   registry.ID,
+		"registry_name": registry.Name,
+		"url":           registry.URL,
+	}
+	if err := c.artMgr.Update(ctx, &a.Artifact, "ExtraAttrs"); err != nil {
+		log.Errorf("failed to record the upstream registry of artifact %s@%s, error %v", a.RepositoryName, a.Digest, err)
+	}
+}
+
 func (c *controller) HeadManifest(_ context.Context, art lib.ArtifactInfo, remote RemoteInterface) (bool, *distribution.Descriptor, error) {
 	remoteRepo := getRemoteRepo(art)
 	ref := getReference(art)
@@ -264,7 +396,7 @@ func (c *controller) HeadManifest(_ context.Context, art lib.ArtifactInfo, remot
 func (c *controller) ProxyBlob(ctx context.Context, p *proModels.Project, art lib.ArtifactInfo) (int64, io.ReadCloser, error) {
 	remoteRepo := getRemoteRepo(art)
 	log.Debugf("The blob doesn't exist, proxy the request to the target server, url:%v", remoteRepo)
-	rHelper, err := NewRemoteHelper(ctx, p.RegistryID, WithSpeed(p.ProxyCacheSpeed()))
+	rHelper, err := NewRemoteHelperWithUpstreams(ctx, p.ProxyCacheUpstreamRegistryIDs(), WithSpeed(p.ProxyCacheSpeed()))
 	if err != nil {
 		return 0, nil, err
 	}
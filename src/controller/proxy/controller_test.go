@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/docker/distribution"
 	"github.com/opencontainers/go-digest"
@@ -27,10 +28,14 @@ import (
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/blob"
 	"github.com/goharbor/harbor/src/lib"
-	_ "github.com/goharbor/harbor/src/lib/cache"
+	"github.com/goharbor/harbor/src/lib/cache"
+	_ "github.com/goharbor/harbor/src/lib/cache/memory"
 	"github.com/goharbor/harbor/src/lib/errors"
+	pkgartifact "github.com/goharbor/harbor/src/pkg/artifact"
 	proModels "github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
 	testproxy "github.com/goharbor/harbor/src/testing/controller/proxy"
+	testartifact "github.com/goharbor/harbor/src/testing/pkg/artifact"
 )
 
 type localInterfaceMock struct {
@@ -157,6 +162,114 @@ func (p *proxyControllerTestSuite) TestUseLocalManifestWithTag_False() {
 	p.Assert().False(result)
 }
 
+func (p *proxyControllerTestSuite) withCache() cache.Cache {
+	c, err := cache.New(cache.Memory)
+	p.Require().Nil(err)
+	p.ctr.(*controller).cache = c
+	return c
+}
+
+func (p *proxyControllerTestSuite) TestUseLocalManifestWithTTL_Fresh() {
+	ctx := context.Background()
+	dig := "sha256:1a9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
+	art := lib.ArtifactInfo{Repository: "library/hello-world", Tag: "latest"}
+	c := p.withCache()
+	p.Require().Nil(c.Save(ctx, freshnessKey(art.Repository, art.Tag), &freshness{Digest: dig, CheckedAt: time.Now()}))
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(&artifact.Artifact{Artifact: pkgartifact.Artifact{Digest: dig}}, nil)
+
+	// the upstream registry isn't consulted as the resolution is fresh
+	result, _, err := p.ctr.UseLocalManifest(ctx, art, p.remote, WithTTL(time.Minute))
+	p.Assert().Nil(err)
+	p.Assert().True(result)
+	p.remote.AssertNotCalled(p.T(), "ManifestExist", mock.Anything, mock.Anything)
+}
+
+func (p *proxyControllerTestSuite) TestUseLocalManifestWithTTL_Expired() {
+	ctx := context.Background()
+	dig := "sha256:1a9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
+	newDig := "sha256:4c9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
+	art := lib.ArtifactInfo{Repository: "library/hello-world", Tag: "latest"}
+	c := p.withCache()
+	p.Require().Nil(c.Save(ctx, freshnessKey(art.Repository, art.Tag), &freshness{Digest: dig, CheckedAt: time.Now().Add(-time.Hour)}))
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(&artifact.Artifact{Artifact: pkgartifact.Artifact{Digest: dig}}, nil)
+	p.remote.On("ManifestExist", mock.Anything, mock.Anything).Return(true, &distribution.Descriptor{Digest: digest.Digest(newDig)}, nil)
+
+	// the tag is updated in the upstream registry
+	result, _, err := p.ctr.UseLocalManifest(ctx, art, p.remote, WithTTL(time.Minute))
+	p.Assert().Nil(err)
+	p.Assert().False(result)
+	f := &freshness{}
+	p.Require().Nil(c.Fetch(ctx, freshnessKey(art.Repository, art.Tag), f))
+	p.Assert().Equal(newDig, f.Digest)
+}
+
+func (p *proxyControllerTestSuite) TestUseLocalManifestWithStaleWhileRevalidate() {
+	ctx := context.Background()
+	dig := "sha256:1a9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
+	newDig := "sha256:4c9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
+	art := lib.ArtifactInfo{Repository: "library/hello-world", Tag: "latest"}
+	c := p.withCache()
+	p.Require().Nil(c.Save(ctx, freshnessKey(art.Repository, art.Tag), &freshness{Digest: dig, CheckedAt: time.Now().Add(-time.Hour)}))
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(&artifact.Artifact{Artifact: pkgartifact.Artifact{Digest: dig}}, nil)
+	p.remote.On("ManifestExist", mock.Anything, mock.Anything).Return(true, &distribution.Descriptor{Digest: digest.Digest(newDig)}, nil)
+
+	// the stale local copy is served and revalidated in background
+	result, _, err := p.ctr.UseLocalManifest(ctx, art, p.remote, WithTTL(time.Minute), WithStaleWhileRevalidate(true))
+	p.Assert().Nil(err)
+	p.Assert().True(result)
+	p.Assert().Eventually(func() bool {
+		f := &freshness{}
+		return c.Fetch(ctx, freshnessKey(art.Repository, art.Tag), f) == nil && f.Digest == newDig
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (p *proxyControllerTestSuite) TestUseLocalManifest_429WithStaleIfError() {
+	ctx := context.Background()
+	art := lib.ArtifactInfo{Repository: "library/hello-world", Tag: "latest"}
+	p.remote.On("ManifestExist", mock.Anything, mock.Anything).Return(false, nil, errors.New("too many requests").WithCode(errors.RateLimitCode))
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(&artifact.Artifact{}, nil)
+	// the error is returned for the caller to serve the local copy with warning
+	_, _, err := p.ctr.UseLocalManifest(ctx, art, p.remote, WithStaleIfError(true))
+	p.Assert().True(errors.IsRateLimitError(err))
+}
+
+func (p *proxyControllerTestSuite) TestLocalManifestExist() {
+	ctx := context.Background()
+	art := lib.ArtifactInfo{Repository: "library/hello-world", Tag: "latest"}
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	exist, err := p.ctr.LocalManifestExist(ctx, art)
+	p.Assert().Nil(err)
+	p.Assert().False(exist)
+
+	p.local.On("GetManifest", mock.Anything, mock.Anything).Return(&artifact.Artifact{}, nil).Once()
+	exist, err = p.ctr.LocalManifestExist(ctx, art)
+	p.Assert().Nil(err)
+	p.Assert().True(exist)
+}
+
+func (p *proxyControllerTestSuite) TestRecordUpstream() {
+	ctx := context.Background()
+	artMgr := &testartifact.Manager{}
+	ctr := &controller{artMgr: artMgr}
+	registry := &model.Registry{ID: 2, Name: "hub", URL: "https://hub.docker.com"}
+	a := &artifact.Artifact{Artifact: pkgartifact.Artifact{ID: 1, ExtraAttrs: map[string]interface{}{}}}
+
+	artMgr.On("Update", mock.Anything, mock.Anything, "ExtraAttrs").Return(nil).Once()
+	ctr.recordUpstream(ctx, a, registry)
+	p.Assert().Equal(map[string]interface{}{
+		"registry_id":   int64(2),
+		"registry_name": "hub",
+		"url":           "https://hub.docker.com",
+	}, a.ExtraAttrs[UpstreamAttr])
+
+	// not updated when the upstream is already recorded
+	a.ExtraAttrs[UpstreamAttr] = map[string]interface{}{"registry_id": float64(2)}
+	ctr.recordUpstream(ctx, a, registry)
+	// not updated when the upstream is unknown
+	ctr.recordUpstream(ctx, a, nil)
+	artMgr.AssertExpectations(p.T())
+}
+
 func (p *proxyControllerTestSuite) TestUseLocalBlob_True() {
 	ctx := context.Background()
 	dig := "sha256:1a9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
@@ -119,7 +119,18 @@ func (m *ManifestListCache) updateManifestList(ctx context.Context, repo string,
 				existMans = append(existMans, ma)
 			}
 		}
-		return manifestlist.FromDescriptors(existMans)
+		// keep the original manifest list when all the manifests are ready, so that the digest
+		// and the annotations of the OCI index are kept
+		if len(existMans) == len(v.Manifests) {
+			return manifest, nil
+		}
+		// the OCI index of the non-image artifacts, e.g. helm chart and WASM module, shouldn't be
+		// converted to the docker manifest list
+		mediaType, _, err := v.Payload()
+		if err != nil {
+			return nil, err
+		}
+		return manifestlist.FromDescriptorsWithMediaType(existMans, mediaType)
 	}
 	return nil, fmt.Errorf("current manifest list type is unknown, manifest type[%T], content [%+v]", manifest, manifest)
 }
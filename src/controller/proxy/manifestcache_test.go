@@ -24,6 +24,9 @@ import (
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/goharbor/harbor/src/controller/artifact"
@@ -151,6 +154,42 @@ func (suite *CacheTestSuite) TestPushManifestList() {
 	suite.Require().Nil(err)
 }
 
+func TestUpdateManifestListOCIIndex(t *testing.T) {
+	ctx := context.Background()
+	wasmDig := "sha256:1a9ec845ee94c202b2d5da74a24f0ed2058318bfa9879fa541efaecba272e86b"
+	chartDig := "sha256:92c7f9c92844bbbb5d0a101b22f7c2a7949e40f8ea90c8b3bc396879d95e899a"
+	index := &manifestlist.DeserializedManifestList{
+		ManifestList: manifestlist.ManifestList{
+			Versioned: manifest.Versioned{
+				SchemaVersion: 2,
+				MediaType:     v1.MediaTypeImageIndex,
+			},
+			Manifests: []manifestlist.ManifestDescriptor{
+				{Descriptor: distribution.Descriptor{Digest: digest.Digest(wasmDig), Size: 525, MediaType: v1.MediaTypeImageManifest}},
+				{Descriptor: distribution.Descriptor{Digest: digest.Digest(chartDig), Size: 538, MediaType: v1.MediaTypeImageManifest}},
+			},
+		},
+	}
+	local := &localInterfaceMock{}
+	local.On("GetManifest", ctx, lib.ArtifactInfo{Repository: "library/app", Digest: wasmDig}).Return(&artifact.Artifact{}, nil)
+	local.On("GetManifest", ctx, mock.Anything).Return(nil, nil).Once()
+	handler := &ManifestListCache{local: local}
+
+	// the trimmed index is still an OCI index
+	newMan, err := handler.updateManifestList(ctx, "library/app", index)
+	require.Nil(t, err)
+	assert.Len(t, newMan.References(), 1)
+	mediaType, _, err := newMan.Payload()
+	require.Nil(t, err)
+	assert.Equal(t, v1.MediaTypeImageIndex, mediaType)
+
+	// the original index is kept when all the manifests are ready
+	local.On("GetManifest", ctx, mock.Anything).Return(&artifact.Artifact{}, nil)
+	newMan, err = handler.updateManifestList(ctx, "library/app", index)
+	require.Nil(t, err)
+	assert.Equal(t, index, newMan)
+}
+
 func TestCacheTestSuite(t *testing.T) {
 	suite.Run(t, &CacheTestSuite{})
 }
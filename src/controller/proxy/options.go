@@ -14,11 +14,21 @@
 
 package proxy
 
+import "time"
+
 type Option func(*Options)
 
 type Options struct {
 	// Speed is the data transfer speed for proxy cache from Harbor to upstream registry, no limit by default.
 	Speed int32
+	// TTL is the freshness lifetime of the tag to digest resolution, the upstream registry isn't
+	// consulted for the tag within the TTL. Zero means always consulting the upstream registry.
+	TTL time.Duration
+	// StaleWhileRevalidate serves the local copy whose tag to digest resolution is expired,
+	// and revalidates it against the upstream registry in background.
+	StaleWhileRevalidate bool
+	// StaleIfError serves the local copy when the upstream registry is unavailable or rate limiting.
+	StaleIfError bool
 }
 
 func NewOptions(opts ...Option) *Options {
@@ -35,3 +45,21 @@ func WithSpeed(speed int32) Option {
 		o.Speed = speed
 	}
 }
+
+func WithTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = ttl
+	}
+}
+
+func WithStaleWhileRevalidate(swr bool) Option {
+	return func(o *Options) {
+		o.StaleWhileRevalidate = swr
+	}
+}
+
+func WithStaleIfError(sie bool) Option {
+	return func(o *Options) {
+		o.StaleIfError = sie
+	}
+}
@@ -16,6 +16,7 @@ package proxy
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -30,4 +31,10 @@ func TestNewOptions(t *testing.T) {
 	withSpeed := WithSpeed(1024)
 	o = NewOptions(withSpeed)
 	assert.Equal(t, int32(1024), o.Speed)
+
+	// with freshness policies
+	o = NewOptions(WithTTL(time.Minute), WithStaleWhileRevalidate(true), WithStaleIfError(true))
+	assert.Equal(t, time.Minute, o.TTL)
+	assert.True(t, o.StaleWhileRevalidate)
+	assert.True(t, o.StaleIfError)
 }
@@ -0,0 +1,205 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	prefetchjob "github.com/goharbor/harbor/src/jobservice/job/impl/prefetch"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg"
+	"github.com/goharbor/harbor/src/pkg/project"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+	"github.com/goharbor/harbor/src/pkg/scheduler"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+const (
+	// SchedulerCallback is the name of the callback function of the prefetch schedule
+	SchedulerCallback = "PROXY_CACHE_PREFETCH_CALLBACK"
+)
+
+// Ctl is a global prefetch controller instance
+var Ctl = NewController()
+
+func init() {
+	err := scheduler.RegisterCallbackFunc(SchedulerCallback, prefetchCallback)
+	if err != nil {
+		log.Fatalf("failed to register the callback function for proxy cache prefetch, %v", err)
+	}
+}
+
+func prefetchCallback(ctx context.Context, param string) error {
+	params := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(param), &params); err != nil {
+		return fmt.Errorf("failed to unmarshal the param: %v", err)
+	}
+	var policyID int64
+	if id, ok := params["policy_id"].(float64); ok {
+		policyID = int64(id)
+	}
+	policy, err := Ctl.GetPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	_, err = Ctl.Start(ctx, policy, task.ExecutionTriggerSchedule)
+	return err
+}
+
+// Controller manages the prefetch policies of the proxy cache projects and launches the prefetch jobs
+type Controller interface {
+	// GetPolicy gets the prefetch policy by ID
+	GetPolicy(ctx context.Context, id int64) (*model.Policy, error)
+	// GetPolicyByProject gets the prefetch policy of the proxy cache project
+	GetPolicyByProject(ctx context.Context, projectID int64) (*model.Policy, error)
+	// SetPolicy creates or replaces the prefetch policy of the proxy cache project and schedules it
+	SetPolicy(ctx context.Context, policy *model.Policy) (int64, error)
+	// DeletePolicyByProject deletes the prefetch policy of the project with its schedule and executions,
+	// nothing is done if the project has no policy
+	DeletePolicyByProject(ctx context.Context, projectID int64) error
+	// Start the prefetch of the policy
+	Start(ctx context.Context, policy *model.Policy, trigger string) (int64, error)
+}
+
+// NewController creates an instance of the default prefetch controller
+func NewController() Controller {
+	return &controller{
+		policyMgr: prefetch.Mgr,
+		proMgr:    pkg.ProjectMgr,
+		scheduler: scheduler.Sched,
+		execMgr:   task.ExecMgr,
+		taskMgr:   task.Mgr,
+	}
+}
+
+type controller struct {
+	policyMgr prefetch.Manager
+	proMgr    project.Manager
+	scheduler scheduler.Scheduler
+	execMgr   task.ExecutionManager
+	taskMgr   task.Manager
+}
+
+func (c *controller) GetPolicy(ctx context.Context, id int64) (*model.Policy, error) {
+	return c.policyMgr.Get(ctx, id)
+}
+
+func (c *controller) GetPolicyByProject(ctx context.Context, projectID int64) (*model.Policy, error) {
+	return c.policyMgr.GetByProject(ctx, projectID)
+}
+
+func (c *controller) SetPolicy(ctx context.Context, policy *model.Policy) (int64, error) {
+	if err := policy.Validate(); err != nil {
+		return 0, err
+	}
+	p, err := c.proMgr.Get(ctx, policy.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	if !p.IsProxy() {
+		return 0, errors.BadRequestError(nil).WithMessagef("the project %s isn't a proxy cache project", p.Name)
+	}
+
+	existing, err := c.policyMgr.GetByProject(ctx, policy.ProjectID)
+	if err != nil && !errors.IsNotFoundErr(err) {
+		return 0, err
+	}
+	if existing == nil {
+		if policy.ID, err = c.policyMgr.Create(ctx, policy); err != nil {
+			return 0, err
+		}
+	} else {
+		if err = c.scheduler.UnScheduleByVendor(ctx, job.ProxyCachePrefetchVendorType, existing.ID); err != nil {
+			return 0, err
+		}
+		policy.ID = existing.ID
+		if err = c.policyMgr.Update(ctx, policy, "Enabled", "Cron", "RulesStr", "PinnedStr", "UpdateTime"); err != nil {
+			return 0, err
+		}
+	}
+
+	if policy.Enabled && len(policy.Cron) > 0 && len(policy.Rules) > 0 {
+		cbParams := map[string]interface{}{
+			"policy_id": policy.ID,
+		}
+		if _, err = c.scheduler.Schedule(ctx, job.ProxyCachePrefetchVendorType, policy.ID, "", policy.Cron,
+			SchedulerCallback, cbParams, map[string]interface{}{}); err != nil {
+			return 0, err
+		}
+	}
+	return policy.ID, nil
+}
+
+func (c *controller) DeletePolicyByProject(ctx context.Context, projectID int64) error {
+	policy, err := c.policyMgr.GetByProject(ctx, projectID)
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return nil
+		}
+		return err
+	}
+	if err = c.execMgr.DeleteByVendor(ctx, job.ProxyCachePrefetchVendorType, policy.ID); err != nil {
+		return err
+	}
+	if err = c.scheduler.UnScheduleByVendor(ctx, job.ProxyCachePrefetchVendorType, policy.ID); err != nil {
+		return err
+	}
+	return c.policyMgr.Delete(ctx, policy.ID)
+}
+
+func (c *controller) Start(ctx context.Context, policy *model.Policy, trigger string) (int64, error) {
+	if !policy.Enabled {
+		return 0, errors.PreconditionFailedError(nil).WithMessagef("the prefetch policy %d is disabled", policy.ID)
+	}
+	if len(policy.Rules) == 0 {
+		return 0, errors.BadRequestError(nil).WithMessagef("the prefetch policy %d has no rule", policy.ID)
+	}
+	p, err := c.proMgr.Get(ctx, policy.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	rules, err := json.Marshal(policy.Rules)
+	if err != nil {
+		return 0, err
+	}
+	params := map[string]interface{}{
+		prefetchjob.ParamProjectName: p.Name,
+		prefetchjob.ParamRules:       string(rules),
+	}
+
+	execID, err := c.execMgr.Create(ctx, job.ProxyCachePrefetchVendorType, policy.ID, trigger, params)
+	if err != nil {
+		return 0, err
+	}
+	_, err = c.taskMgr.Create(ctx, execID, &task.Job{
+		Name: job.ProxyCachePrefetchVendorType,
+		Metadata: &job.Metadata{
+			JobKind: job.KindGeneric,
+		},
+		Parameters: params,
+	})
+	if err != nil {
+		if e := c.execMgr.MarkError(ctx, execID, err.Error()); e != nil {
+			log.Errorf("failed to mark the status of the prefetch execution %d to error: %v", execID, e)
+		}
+		return 0, err
+	}
+	return execID, nil
+}
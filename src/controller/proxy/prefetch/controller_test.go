@@ -0,0 +1,153 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	prefetchjob "github.com/goharbor/harbor/src/jobservice/job/impl/prefetch"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+	"github.com/goharbor/harbor/src/pkg/task"
+	"github.com/goharbor/harbor/src/testing/mock"
+	testingProject "github.com/goharbor/harbor/src/testing/pkg/project"
+	testingPrefetch "github.com/goharbor/harbor/src/testing/pkg/proxy/prefetch"
+	testingScheduler "github.com/goharbor/harbor/src/testing/pkg/scheduler"
+	testingTask "github.com/goharbor/harbor/src/testing/pkg/task"
+)
+
+type controllerTestSuite struct {
+	suite.Suite
+	policyMgr *testingPrefetch.Manager
+	proMgr    *testingProject.Manager
+	scheduler *testingScheduler.Scheduler
+	execMgr   *testingTask.ExecutionManager
+	taskMgr   *testingTask.Manager
+	ctl       *controller
+}
+
+func (c *controllerTestSuite) SetupTest() {
+	c.policyMgr = &testingPrefetch.Manager{}
+	c.proMgr = &testingProject.Manager{}
+	c.scheduler = &testingScheduler.Scheduler{}
+	c.execMgr = &testingTask.ExecutionManager{}
+	c.taskMgr = &testingTask.Manager{}
+	c.ctl = &controller{
+		policyMgr: c.policyMgr,
+		proMgr:    c.proMgr,
+		scheduler: c.scheduler,
+		execMgr:   c.execMgr,
+		taskMgr:   c.taskMgr,
+	}
+}
+
+func (c *controllerTestSuite) TestSetPolicy() {
+	proxyProject := &models.Project{ProjectID: 1, Name: "dockerhub", RegistryID: 1}
+	c.proMgr.On("Get", mock.Anything, int64(1)).Return(proxyProject, nil)
+
+	// create
+	c.policyMgr.On("GetByProject", mock.Anything, int64(1)).Return(nil, errors.NotFoundError(nil)).Once()
+	c.policyMgr.On("Create", mock.Anything, mock.Anything).Return(int64(10), nil).Once()
+	c.scheduler.On("Schedule", mock.Anything, job.ProxyCachePrefetchVendorType, int64(10), "", "0 0 * * * *",
+		SchedulerCallback, mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+	policy := &model.Policy{
+		ProjectID: 1,
+		Enabled:   true,
+		Cron:      "0 0 * * * *",
+		Rules:     []*model.Rule{{Repository: "library/nginx"}},
+	}
+	id, err := c.ctl.SetPolicy(context.Background(), policy)
+	c.Require().Nil(err)
+	c.Equal(int64(10), id)
+
+	// update and disable the schedule
+	c.policyMgr.On("GetByProject", mock.Anything, int64(1)).Return(&model.Policy{ID: 10, ProjectID: 1}, nil).Once()
+	c.scheduler.On("UnScheduleByVendor", mock.Anything, job.ProxyCachePrefetchVendorType, int64(10)).Return(nil).Once()
+	c.policyMgr.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything).Return(nil).Once()
+	policy = &model.Policy{
+		ProjectID:  1,
+		Enabled:    false,
+		Cron:       "0 0 * * * *",
+		Rules:      []*model.Rule{{Repository: "library/nginx"}},
+		PinnedTags: []*model.PinnedTag{{Repository: "library/nginx", Tag: "stable"}},
+	}
+	id, err = c.ctl.SetPolicy(context.Background(), policy)
+	c.Require().Nil(err)
+	c.Equal(int64(10), id)
+
+	c.policyMgr.AssertExpectations(c.T())
+	c.scheduler.AssertExpectations(c.T())
+}
+
+func (c *controllerTestSuite) TestSetPolicyOfNonProxyProject() {
+	c.proMgr.On("Get", mock.Anything, int64(2)).Return(&models.Project{ProjectID: 2, Name: "library"}, nil)
+	_, err := c.ctl.SetPolicy(context.Background(), &model.Policy{ProjectID: 2})
+	c.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// invalid policy
+	_, err = c.ctl.SetPolicy(context.Background(), &model.Policy{ProjectID: 2, Cron: "invalid"})
+	c.True(errors.IsErr(err, errors.BadRequestCode))
+}
+
+func (c *controllerTestSuite) TestDeletePolicyByProject() {
+	c.policyMgr.On("GetByProject", mock.Anything, int64(1)).Return(nil, errors.NotFoundError(nil)).Once()
+	c.Nil(c.ctl.DeletePolicyByProject(context.Background(), 1))
+
+	c.policyMgr.On("GetByProject", mock.Anything, int64(1)).Return(&model.Policy{ID: 10, ProjectID: 1}, nil).Once()
+	c.execMgr.On("DeleteByVendor", mock.Anything, job.ProxyCachePrefetchVendorType, int64(10)).Return(nil).Once()
+	c.scheduler.On("UnScheduleByVendor", mock.Anything, job.ProxyCachePrefetchVendorType, int64(10)).Return(nil).Once()
+	c.policyMgr.On("Delete", mock.Anything, int64(10)).Return(nil).Once()
+	c.Nil(c.ctl.DeletePolicyByProject(context.Background(), 1))
+
+	c.policyMgr.AssertExpectations(c.T())
+	c.execMgr.AssertExpectations(c.T())
+	c.scheduler.AssertExpectations(c.T())
+}
+
+func (c *controllerTestSuite) TestStart() {
+	// disabled policy
+	_, err := c.ctl.Start(context.Background(), &model.Policy{ID: 10, ProjectID: 1}, task.ExecutionTriggerManual)
+	c.True(errors.IsErr(err, errors.PreconditionCode))
+
+	policy := &model.Policy{
+		ID:        10,
+		ProjectID: 1,
+		Enabled:   true,
+		Rules:     []*model.Rule{{Repository: "library/nginx", Tag: "1.*"}},
+	}
+	c.proMgr.On("Get", mock.Anything, int64(1)).Return(&models.Project{ProjectID: 1, Name: "dockerhub", RegistryID: 1}, nil)
+	params := map[string]interface{}{
+		prefetchjob.ParamProjectName: "dockerhub",
+		prefetchjob.ParamRules:       `[{"repository":"library/nginx","tag":"1.*"}]`,
+	}
+	c.execMgr.On("Create", mock.Anything, job.ProxyCachePrefetchVendorType, int64(10), task.ExecutionTriggerManual,
+		params).Return(int64(1), nil)
+	c.taskMgr.On("Create", mock.Anything, int64(1), mock.Anything).Return(int64(1), nil)
+	id, err := c.ctl.Start(context.Background(), policy, task.ExecutionTriggerManual)
+	c.Require().Nil(err)
+	c.Equal(int64(1), id)
+	c.execMgr.AssertExpectations(c.T())
+	c.taskMgr.AssertExpectations(c.T())
+}
+
+func TestController(t *testing.T) {
+	suite.Run(t, &controllerTestSuite{})
+}
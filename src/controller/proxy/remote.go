@@ -18,10 +18,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
 
 	"github.com/docker/distribution"
 
 	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
@@ -39,18 +43,41 @@ type RemoteInterface interface {
 	ListTags(repo string) ([]string, error)
 }
 
-// remoteHelper defines operations related to remote repository under proxy
+// UpstreamReporter is implemented by the remote which is able to report the upstream registry
+// that served the last manifest
+type UpstreamReporter interface {
+	// ServedBy returns the upstream registry served the last manifest, nil if no manifest is served yet
+	ServedBy() *model.Registry
+}
+
+// upstream is one of the upstream registries of the proxy cache project
+type upstream struct {
+	registry *model.Registry
+	adapter  adapter.ArtifactRegistry
+}
+
+// remoteHelper defines operations related to remote repository under proxy,
+// the upstream registries are tried in order until one of them serves the request
 type remoteHelper struct {
-	regID       int64
-	registry    adapter.ArtifactRegistry
+	regIDs      []int64
+	upstreams   []*upstream
 	registryMgr reg.Manager
 	opts        *Options
+	lock        sync.RWMutex
+	servedBy    *model.Registry
 }
 
 // NewRemoteHelper create a remote interface
 func NewRemoteHelper(ctx context.Context, regID int64, opts ...Option) (RemoteInterface, error) {
+	return NewRemoteHelperWithUpstreams(ctx, []int64{regID}, opts...)
+}
+
+// NewRemoteHelperWithUpstreams create a remote interface which falls back to the next upstream
+// registry in the order of regIDs when the current one fails to serve the request.
+// The unhealthy upstream registries are skipped, error is returned if none of them is usable
+func NewRemoteHelperWithUpstreams(ctx context.Context, regIDs []int64, opts ...Option) (RemoteInterface, error) {
 	r := &remoteHelper{
-		regID:       regID,
+		regIDs:      regIDs,
 		registryMgr: reg.Mgr,
 		opts:        NewOptions(opts...),
 	}
@@ -61,33 +88,89 @@ func NewRemoteHelper(ctx context.Context, regID int64, opts ...Option) (RemoteIn
 }
 
 func (r *remoteHelper) init(ctx context.Context) error {
-	if r.registry != nil {
+	if len(r.upstreams) > 0 {
 		return nil
 	}
-	reg, err := r.registryMgr.Get(ctx, r.regID)
+	if len(r.regIDs) == 0 {
+		return fmt.Errorf("no upstream registry is specified")
+	}
+	var firstErr error
+	for _, regID := range r.regIDs {
+		u, err := r.newUpstream(ctx, regID)
+		if err != nil {
+			log.Warningf("skip the upstream registry %d: %v", regID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		r.upstreams = append(r.upstreams, u)
+	}
+	if len(r.upstreams) == 0 {
+		return firstErr
+	}
+	return nil
+}
+
+func (r *remoteHelper) newUpstream(ctx context.Context, regID int64) (*upstream, error) {
+	reg, err := r.registryMgr.Get(ctx, regID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if reg == nil {
-		return fmt.Errorf("failed to get registry, registryID: %v", r.regID)
+		return nil, fmt.Errorf("failed to get registry, registryID: %v", regID)
 	}
 	if reg.Status != model.Healthy {
-		return fmt.Errorf("current registry is unhealthy, regID:%v, Name:%v, Status: %v", reg.ID, reg.Name, reg.Status)
+		return nil, fmt.Errorf("current registry is unhealthy, regID:%v, Name:%v, Status: %v", reg.ID, reg.Name, reg.Status)
 	}
 	factory, err := adapter.GetFactory(reg.Type)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	adp, err := factory.Create(reg)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	r.registry = adp.(adapter.ArtifactRegistry)
-	return nil
+	return &upstream{registry: reg, adapter: adp.(adapter.ArtifactRegistry)}, nil
+}
+
+// repository returns the repository name in the upstream registry, the official images of Docker Hub
+// are under the "library" namespace which is omitted when the Docker Hub isn't the preferred upstream
+func (u *upstream) repository(repo string) string {
+	if u.registry.Type == model.RegistryTypeDockerHub && !strings.Contains(repo, "/") {
+		return "library/" + repo
+	}
+	return repo
+}
+
+// try calls f against the upstream registries in order until one of them succeeds.
+// When all of them fail, the first error other than not found is returned so that the
+// caller is able to handle the unavailability (e.g. rate limiting) of the preferred upstream
+func (r *remoteHelper) try(f func(u *upstream) error) error {
+	var result error
+	for _, u := range r.upstreams {
+		err := f(u)
+		if err == nil {
+			return nil
+		}
+		log.Debugf("failed to request the upstream registry %s, try the next one: %v", u.registry.Name, err)
+		if result == nil || (errors.IsNotFoundErr(result) && !errors.IsNotFoundErr(err)) {
+			result = err
+		}
+	}
+	return result
 }
 
 func (r *remoteHelper) BlobReader(repo, dig string) (int64, io.ReadCloser, error) {
-	sz, bReader, err := r.registry.PullBlob(repo, dig)
+	var (
+		sz      int64
+		bReader io.ReadCloser
+	)
+	err := r.try(func(u *upstream) error {
+		var err error
+		sz, bReader, err = u.adapter.PullBlob(u.repository(repo), dig)
+		return err
+	})
 	if err != nil {
 		return 0, nil, err
 	}
@@ -98,13 +181,58 @@ func (r *remoteHelper) BlobReader(repo, dig string) (int64, io.ReadCloser, error
 }
 
 func (r *remoteHelper) Manifest(repo string, ref string) (distribution.Manifest, string, error) {
-	return r.registry.PullManifest(repo, ref)
+	var (
+		man distribution.Manifest
+		dig string
+	)
+	err := r.try(func(u *upstream) error {
+		var err error
+		man, dig, err = u.adapter.PullManifest(u.repository(repo), ref)
+		if err == nil {
+			r.lock.Lock()
+			r.servedBy = u.registry
+			r.lock.Unlock()
+		}
+		return err
+	})
+	return man, dig, err
 }
 
 func (r *remoteHelper) ManifestExist(repo string, ref string) (bool, *distribution.Descriptor, error) {
-	return r.registry.ManifestExist(repo, ref)
+	var desc *distribution.Descriptor
+	err := r.try(func(u *upstream) error {
+		exist, d, err := u.adapter.ManifestExist(u.repository(repo), ref)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.NotFoundError(nil).WithMessagef("manifest %s:%s not found in %s", repo, ref, u.registry.Name)
+		}
+		desc = d
+		return nil
+	})
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	return true, desc, nil
 }
 
 func (r *remoteHelper) ListTags(repo string) ([]string, error) {
-	return r.registry.ListTags(repo)
+	var tags []string
+	err := r.try(func(u *upstream) error {
+		var err error
+		tags, err = u.adapter.ListTags(u.repository(repo))
+		return err
+	})
+	return tags, err
+}
+
+// ServedBy returns the upstream registry served the last manifest
+func (r *remoteHelper) ServedBy() *model.Registry {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.servedBy
 }
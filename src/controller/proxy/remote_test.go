@@ -0,0 +1,191 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/testing/mock"
+	testingreg "github.com/goharbor/harbor/src/testing/pkg/reg"
+)
+
+type fakeArtifactRegistry struct {
+	adapter.ArtifactRegistry
+	manifests map[string]distribution.Manifest
+	blobs     map[string]string
+	tags      []string
+	err       error
+}
+
+func (f *fakeArtifactRegistry) PullManifest(repository, reference string, _ ...string) (distribution.Manifest, string, error) {
+	if f.err != nil {
+		return nil, "", f.err
+	}
+	man, ok := f.manifests[repository+":"+reference]
+	if !ok {
+		return nil, "", errors.NotFoundError(nil).WithMessagef("manifest %s:%s not found", repository, reference)
+	}
+	return man, reference, nil
+}
+
+func (f *fakeArtifactRegistry) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	if f.err != nil {
+		return false, nil, f.err
+	}
+	if _, ok := f.manifests[repository+":"+reference]; !ok {
+		return false, nil, nil
+	}
+	return true, &distribution.Descriptor{Digest: digest.Digest("sha256:" + reference)}, nil
+}
+
+func (f *fakeArtifactRegistry) PullBlob(repository, dig string) (int64, io.ReadCloser, error) {
+	if f.err != nil {
+		return 0, nil, f.err
+	}
+	blob, ok := f.blobs[repository+"@"+dig]
+	if !ok {
+		return 0, nil, errors.NotFoundError(nil).WithMessagef("blob %s@%s not found", repository, dig)
+	}
+	return int64(len(blob)), io.NopCloser(strings.NewReader(blob)), nil
+}
+
+func (f *fakeArtifactRegistry) ListTags(_ string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.tags, nil
+}
+
+type remoteHelperTestSuite struct {
+	suite.Suite
+	mirror  *fakeArtifactRegistry
+	hub     *fakeArtifactRegistry
+	mirrorR *model.Registry
+	hubR    *model.Registry
+	remote  *remoteHelper
+}
+
+func (r *remoteHelperTestSuite) SetupTest() {
+	r.mirror = &fakeArtifactRegistry{manifests: map[string]distribution.Manifest{}, blobs: map[string]string{}}
+	r.hub = &fakeArtifactRegistry{manifests: map[string]distribution.Manifest{}, blobs: map[string]string{}}
+	r.mirrorR = &model.Registry{ID: 1, Name: "mirror", Type: model.RegistryTypeHarbor}
+	r.hubR = &model.Registry{ID: 2, Name: "hub", Type: model.RegistryTypeDockerHub}
+	r.remote = &remoteHelper{
+		upstreams: []*upstream{
+			{registry: r.mirrorR, adapter: r.mirror},
+			{registry: r.hubR, adapter: r.hub},
+		},
+		opts: NewOptions(),
+	}
+}
+
+func (r *remoteHelperTestSuite) TestInit() {
+	regMgr := &testingreg.Manager{}
+	regMgr.On("Get", mock.Anything, int64(1)).Return(&model.Registry{ID: 1, Name: "mirror", Status: model.Unhealthy}, nil)
+	regMgr.On("Get", mock.Anything, int64(2)).Return(nil, errors.NotFoundError(nil))
+	remote := &remoteHelper{regIDs: []int64{1, 2}, registryMgr: regMgr}
+	// none of the upstream registries is usable
+	r.Error(remote.init(context.TODO()))
+
+	remote = &remoteHelper{registryMgr: regMgr}
+	r.Error(remote.init(context.TODO()))
+}
+
+func (r *remoteHelperTestSuite) TestManifest() {
+	r.mirror.manifests["app:v1"] = &fakeManifest{}
+	r.hub.manifests["library/alpine:latest"] = &fakeManifest{}
+
+	// served by the preferred upstream
+	_, _, err := r.remote.Manifest("app", "v1")
+	r.Require().Nil(err)
+	r.Equal(r.mirrorR, r.remote.ServedBy())
+
+	// fall back to the next upstream, the official images of Docker Hub are under the library namespace
+	_, _, err = r.remote.Manifest("alpine", "latest")
+	r.Require().Nil(err)
+	r.Equal(r.hubR, r.remote.ServedBy())
+
+	// not found in any upstream
+	_, _, err = r.remote.Manifest("app", "v2")
+	r.True(errors.IsNotFoundErr(err))
+
+	// the error other than not found is returned
+	r.mirror.err = errors.New("too many requests").WithCode(errors.RateLimitCode)
+	_, _, err = r.remote.Manifest("app", "v2")
+	r.True(errors.IsRateLimitError(err))
+}
+
+func (r *remoteHelperTestSuite) TestManifestExist() {
+	r.hub.manifests["library/alpine:latest"] = &fakeManifest{}
+
+	exist, desc, err := r.remote.ManifestExist("alpine", "latest")
+	r.Require().Nil(err)
+	r.True(exist)
+	r.NotNil(desc)
+
+	exist, _, err = r.remote.ManifestExist("alpine", "3.20")
+	r.Require().Nil(err)
+	r.False(exist)
+
+	r.mirror.err = errors.New("too many requests").WithCode(errors.RateLimitCode)
+	_, _, err = r.remote.ManifestExist("alpine", "3.20")
+	r.True(errors.IsRateLimitError(err))
+}
+
+func (r *remoteHelperTestSuite) TestBlobReader() {
+	r.mirror.err = errors.New("connection refused")
+	r.hub.blobs["library/app@sha256:abc"] = "content"
+
+	size, reader, err := r.remote.BlobReader("app", "sha256:abc")
+	r.Require().Nil(err)
+	defer reader.Close()
+	r.Equal(int64(7), size)
+	data, err := io.ReadAll(reader)
+	r.Require().Nil(err)
+	r.Equal("content", string(data))
+}
+
+func (r *remoteHelperTestSuite) TestListTags() {
+	r.mirror.err = errors.New("connection refused")
+	r.hub.tags = []string{"v1", "v2"}
+
+	tags, err := r.remote.ListTags("app")
+	r.Require().Nil(err)
+	r.Equal([]string{"v1", "v2"}, tags)
+}
+
+type fakeManifest struct{}
+
+func (f *fakeManifest) References() []distribution.Descriptor {
+	return nil
+}
+
+func (f *fakeManifest) Payload() (string, []byte, error) {
+	return "", nil, nil
+}
+
+func TestRemoteHelperTestSuite(t *testing.T) {
+	suite.Run(t, &remoteHelperTestSuite{})
+}
@@ -19,13 +19,13 @@ import (
 	"math/rand"
 	"time"
 
-	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg"
 	"github.com/goharbor/harbor/src/pkg/project"
 	"github.com/goharbor/harbor/src/pkg/reg"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/pkg/replication"
 )
@@ -89,12 +89,22 @@ func (c *controller) validate(ctx context.Context, registry *model.Registry) err
 	if len(registry.Name) > 64 {
 		return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the max length of name is 64")
 	}
-	url, err := lib.ValidateHTTPURL(registry.URL)
+	url, err := adp.ValidateURL(registry.Type, registry.URL)
 	if err != nil {
 		return err
 	}
 	registry.URL = url
 
+	if registry.Speed < 0 {
+		return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the speed cannot be negative")
+	}
+	if registry.MaxConcurrentTasks < 0 {
+		return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the max concurrent tasks cannot be negative")
+	}
+	if err := model.ValidateBandwidthWindows(registry.BandwidthWindows); err != nil {
+		return err
+	}
+
 	healthy, err := c.IsHealthy(ctx, registry)
 	if err != nil {
 		return err
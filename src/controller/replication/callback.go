@@ -0,0 +1,46 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/goharbor/harbor/src/controller/replication/transfer"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+func init() {
+	if err := task.RegisterCheckInProcessor(job.ReplicationVendorType, progressCheckIn); err != nil {
+		log.Fatalf("failed to register the checkin processor for the replication job, error %v", err)
+	}
+}
+
+// progressCheckIn records the transfer progress checked in by the replication job in the task
+func progressCheckIn(ctx context.Context, t *task.Task, sc *job.StatusChange) error {
+	progress := &transfer.Progress{}
+	if err := json.Unmarshal([]byte(sc.CheckIn), progress); err != nil {
+		log.Errorf("failed to resolve checkin of replication task %d: %v", t.ID, err)
+		return err
+	}
+	if t.ExtraAttrs == nil {
+		t.ExtraAttrs = map[string]interface{}{}
+	}
+	t.ExtraAttrs["copied_blobs"] = progress.CopiedBlobs
+	t.ExtraAttrs["copied_bytes"] = progress.CopiedBytes
+	return task.Mgr.UpdateExtraAttrs(ctx, t.ID, t.ExtraAttrs)
+}
@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/task"
+	testingTask "github.com/goharbor/harbor/src/testing/pkg/task"
+)
+
+type callbackTestSuite struct {
+	suite.Suite
+	originalTaskMgr task.Manager
+	taskMgr         *testingTask.Manager
+}
+
+func (c *callbackTestSuite) SetupTest() {
+	c.originalTaskMgr = task.Mgr
+	c.taskMgr = &testingTask.Manager{}
+	task.Mgr = c.taskMgr
+}
+
+func (c *callbackTestSuite) TearDownTest() {
+	task.Mgr = c.originalTaskMgr
+}
+
+func (c *callbackTestSuite) TestProgressCheckIn() {
+	t := &task.Task{
+		ID: 1,
+		ExtraAttrs: map[string]interface{}{
+			"operation": "copy",
+		},
+	}
+	c.taskMgr.On("UpdateExtraAttrs", mock.Anything, int64(1), map[string]interface{}{
+		"operation":    "copy",
+		"copied_blobs": int64(2),
+		"copied_bytes": int64(1024),
+	}).Return(nil)
+	err := progressCheckIn(context.Background(), t, &job.StatusChange{
+		CheckIn: `{"copied_blobs":2,"copied_bytes":1024}`,
+	})
+	c.Require().Nil(err)
+	c.taskMgr.AssertExpectations(c.T())
+
+	// invalid check in data
+	err = progressCheckIn(context.Background(), t, &job.StatusChange{
+		CheckIn: "invalid",
+	})
+	c.NotNil(err)
+}
+
+func TestCallbackTestSuite(t *testing.T) {
+	suite.Run(t, &callbackTestSuite{})
+}
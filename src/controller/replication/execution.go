@@ -16,6 +16,7 @@ package replication
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -36,6 +37,11 @@ import (
 	"github.com/goharbor/harbor/src/pkg/task"
 )
 
+const (
+	dryRunAttr       = "dry_run"
+	dryRunReportAttr = "dry_run_report"
+)
+
 // Ctl is a global replication controller instance
 var Ctl = NewController()
 
@@ -55,6 +61,11 @@ type Controller interface {
 	DeletePolicy(ctx context.Context, id int64) (err error)
 	// Start the replication according to the policy
 	Start(ctx context.Context, policy *replicationmodel.Policy, resource *model.Resource, trigger string) (executionID int64, err error)
+	// StartDryRun starts an execution that reports what the replication of the policy will do
+	// without copying anything, the report is persisted with the execution when it is done
+	StartDryRun(ctx context.Context, policy *replicationmodel.Policy) (executionID int64, err error)
+	// GetDryRunReport gets the report of the specific dry run execution
+	GetDryRunReport(ctx context.Context, executionID int64) (report *flow.DryRunReport, err error)
 	// Stop the replication specified by the execution ID
 	Stop(ctx context.Context, executionID int64) (err error)
 	// ExecutionCount returns the total count of executions according to the query
@@ -99,7 +110,6 @@ type controller struct {
 }
 
 func (c *controller) Start(ctx context.Context, policy *replicationmodel.Policy, resource *model.Resource, trigger string) (int64, error) {
-	logger := log.GetLogger(ctx)
 	if !policy.Enabled {
 		return 0, errors.New(nil).WithCode(errors.PreconditionCode).
 			WithMessagef("the policy %d is disabled", policy.ID)
@@ -114,6 +124,75 @@ func (c *controller) Start(ctx context.Context, policy *replicationmodel.Policy,
 		return 0, err
 	}
 	// start the replication flow in background
+	c.runInBackground(ctx, id, func(ctx context.Context) error {
+		return c.flowCtl.Start(ctx, id, policy, resource)
+	})
+	return id, nil
+}
+
+func (c *controller) StartDryRun(ctx context.Context, policy *replicationmodel.Policy) (int64, error) {
+	// create an execution record with the "dry_run" flag, no task will be created for it
+	extra := map[string]interface{}{
+		dryRunAttr: true,
+	}
+	if op := operator.FromContext(ctx); op != "" {
+		extra["operator"] = op
+	}
+	id, err := c.execMgr.Create(ctx, job.ReplicationVendorType, policy.ID, task.ExecutionTriggerManual, extra)
+	if err != nil {
+		return 0, err
+	}
+	// run the dry run in background and persist the report with the execution
+	c.runInBackground(ctx, id, func(ctx context.Context) error {
+		report, err := c.flowCtl.DryRun(ctx, policy)
+		if err != nil {
+			return err
+		}
+		extra[dryRunReportAttr] = report
+		if err = c.execMgr.UpdateExtraAttrs(ctx, id, extra); err != nil {
+			return err
+		}
+		return c.execMgr.MarkDone(ctx, id, fmt.Sprintf("%d resources to create, %d to overwrite, %d to skip, %d to delete",
+			report.Create, report.Overwrite, report.Skip, report.Delete))
+	})
+	return id, nil
+}
+
+func (c *controller) GetDryRunReport(ctx context.Context, executionID int64) (*flow.DryRunReport, error) {
+	execs, err := c.execMgr.List(ctx, &q.Query{
+		Keywords: map[string]interface{}{
+			"ID":         executionID,
+			"VendorType": job.ReplicationVendorType,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(execs) == 0 || !isDryRun(execs[0]) {
+		return nil, errors.New(nil).WithCode(errors.NotFoundCode).
+			WithMessagef("replication dry run execution %d not found", executionID)
+	}
+	value, exist := execs[0].ExtraAttrs[dryRunReportAttr]
+	if !exist {
+		return nil, errors.New(nil).WithCode(errors.PreconditionCode).
+			WithMessagef("no report for the replication dry run execution %d, status: %s", executionID, execs[0].Status)
+	}
+	// the report is decoded as a map from the extra attributes, convert it back
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	report := &flow.DryRunReport{}
+	if err = json.Unmarshal(data, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// runInBackground runs the process of the execution inside a goroutine and marks
+// the execution as error if the process fails
+func (c *controller) runInBackground(ctx context.Context, id int64, process func(ctx context.Context) error) {
+	logger := log.GetLogger(ctx)
 	// as the process runs inside a goroutine, the transaction in the outer ctx
 	// may be submitted already when the process starts, so create an new context
 	// with orm populated to the goroutine
@@ -141,14 +220,13 @@ func (c *controller) Start(ctx context.Context, policy *replicationmodel.Policy,
 			return
 		}
 
-		err := c.flowCtl.Start(ctx, id, policy, resource)
+		err := process(ctx)
 		if err == nil {
 			// no err, return directly
 			return
 		}
 		c.markError(ctx, id, err)
 	}()
-	return id, nil
 }
 
 func (c *controller) markError(ctx context.Context, executionID int64, err error) {
@@ -279,10 +357,16 @@ func convertExecution(exec *task.Execution) *Execution {
 	if operator, ok := exec.ExtraAttrs["operator"].(string); ok {
 		replicationExec.Operator = operator
 	}
+	replicationExec.DryRun = isDryRun(exec)
 
 	return replicationExec
 }
 
+func isDryRun(exec *task.Execution) bool {
+	dryRun, _ := exec.ExtraAttrs[dryRunAttr].(bool)
+	return dryRun
+}
+
 func convertTask(task *task.Task) *Task {
 	return &Task{
 		ID:                  task.ID,
@@ -295,6 +379,8 @@ func convertTask(task *task.Task) *Task {
 		DestinationResource: task.GetStringFromExtraAttrs("destination_resource"),
 		References:          task.GetStringFromExtraAttrs("references"),
 		Operation:           task.GetStringFromExtraAttrs("operation"),
+		CopiedBlobs:         int64(task.GetNumFromExtraAttrs("copied_blobs")),
+		CopiedBytes:         int64(task.GetNumFromExtraAttrs("copied_bytes")),
 		JobID:               task.JobID,
 		CreationTime:        task.CreationTime,
 		StartTime:           task.StartTime,
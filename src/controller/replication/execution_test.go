@@ -22,9 +22,11 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/goharbor/harbor/src/controller/replication/flow"
 	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg/task"
 	"github.com/goharbor/harbor/src/pkg/task/dao"
 	"github.com/goharbor/harbor/src/testing/lib/orm"
@@ -103,6 +105,77 @@ func (r *replicationTestSuite) TestStart() {
 	r.ormCreator.AssertExpectations(r.T())
 }
 
+func (r *replicationTestSuite) TestStartDryRun() {
+	r.execMgr.On("Create", mock.Anything, job.ReplicationVendorType, int64(1), task.ExecutionTriggerManual,
+		map[string]interface{}{"dry_run": true}).Return(int64(1), nil)
+	r.execMgr.On("Get", mock.Anything, mock.Anything).Return(&task.Execution{}, nil)
+	r.flowCtl.On("DryRun", mock.Anything, mock.Anything).Return(&flow.DryRunReport{Create: 1}, nil)
+	var extra map[string]interface{}
+	r.execMgr.On("UpdateExtraAttrs", mock.Anything, int64(1), mock.Anything).Run(func(args mock.Arguments) {
+		extra = args.Get(2).(map[string]interface{})
+	}).Return(nil)
+	r.execMgr.On("MarkDone", mock.Anything, int64(1), mock.Anything).Return(nil)
+	r.ormCreator.On("Create").Return(nil)
+	id, err := r.ctl.StartDryRun(context.Background(), &repctlmodel.Policy{ID: 1})
+	r.Require().Nil(err)
+	r.Equal(int64(1), id)
+	time.Sleep(1 * time.Second) // wait the functions called in the goroutine
+	r.execMgr.AssertExpectations(r.T())
+	r.flowCtl.AssertExpectations(r.T())
+	r.ormCreator.AssertExpectations(r.T())
+	r.Equal(true, extra["dry_run"])
+	r.Equal(&flow.DryRunReport{Create: 1}, extra["dry_run_report"])
+}
+
+func (r *replicationTestSuite) TestGetDryRunReport() {
+	// not a dry run execution
+	r.execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{
+			ID:         1,
+			VendorType: job.ReplicationVendorType,
+		},
+	}, nil).Once()
+	_, err := r.ctl.GetDryRunReport(nil, 1)
+	r.Require().NotNil(err)
+	r.True(errors.IsNotFoundErr(err))
+
+	// the dry run is still running
+	r.execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{
+			ID:         1,
+			VendorType: job.ReplicationVendorType,
+			Status:     job.RunningStatus.String(),
+			ExtraAttrs: map[string]interface{}{"dry_run": true},
+		},
+	}, nil).Once()
+	_, err = r.ctl.GetDryRunReport(nil, 1)
+	r.Require().NotNil(err)
+	r.True(errors.IsErr(err, errors.PreconditionCode))
+
+	// the report is persisted
+	r.execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{
+			ID:         1,
+			VendorType: job.ReplicationVendorType,
+			Status:     job.SuccessStatus.String(),
+			ExtraAttrs: map[string]interface{}{
+				"dry_run": true,
+				"dry_run_report": map[string]interface{}{
+					"create": float64(1),
+					"items": []interface{}{
+						map[string]interface{}{"action": "create"},
+					},
+				},
+			},
+		},
+	}, nil).Once()
+	report, err := r.ctl.GetDryRunReport(nil, 1)
+	r.Require().Nil(err)
+	r.Equal(1, report.Create)
+	r.Require().Len(report.Items, 1)
+	r.execMgr.AssertExpectations(r.T())
+}
+
 func (r *replicationTestSuite) TestStop() {
 	r.execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
 		{
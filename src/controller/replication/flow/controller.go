@@ -29,6 +29,8 @@ type Flow interface {
 // Controller controls the replication flow
 type Controller interface {
 	Start(ctx context.Context, executionID int64, policy *repctlmodel.Policy, resource *model.Resource) (err error)
+	// DryRun reports what the replication of the policy will do without changing anything
+	DryRun(ctx context.Context, policy *repctlmodel.Policy) (report *DryRunReport, err error)
 }
 
 // NewController returns an instance of the default flow controller
@@ -50,3 +52,7 @@ func (c *controller) Start(ctx context.Context, executionID int64, policy *repct
 	}
 	return NewCopyFlow(executionID, policy, resources...).Run(ctx)
 }
+
+func (c *controller) DryRun(ctx context.Context, policy *repctlmodel.Policy) (*DryRunReport, error) {
+	return DryRun(ctx, policy)
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/pkg/task"
 )
@@ -92,7 +94,14 @@ func (c *copyFlow) Run(ctx context.Context) error {
 		return err
 	}
 
-	return c.createTasks(ctx, srcResources, dstResources, c.policy.Speed, c.policy.CopyByChunk)
+	// the full replication of the reconciliation policy also deletes the tags which don't exist on the source
+	if c.policy.Reconcile && c.policy.ReplicateDeletion && len(c.resources) == 0 {
+		srcDeletions, dstDeletions := reconcile(ctx, c.policy, srcAdapter, dstAdapter, srcResources, dstResources)
+		srcResources = append(srcResources, srcDeletions...)
+		dstResources = append(dstResources, dstDeletions...)
+	}
+
+	return c.createTasks(ctx, srcResources, dstResources, c.policy.Speed, c.policy.CopyByChunk, c.policy.RequireAccessories)
 }
 
 func (c *copyFlow) isExecutionStopped(ctx context.Context) (bool, error) {
@@ -103,7 +112,7 @@ func (c *copyFlow) isExecutionStopped(ctx context.Context) (bool, error) {
 	return execution.Status == job.StoppedStatus.String(), nil
 }
 
-func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources []*model.Resource, speed int32, copyByChunk bool) error {
+func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources []*model.Resource, speed int32, copyByChunk, requireAccessories bool) error {
 	var taskCnt int
 	defer func() {
 		// if no task be created, mark execution done.
@@ -114,6 +123,11 @@ func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources [
 		}
 	}()
 
+	windows, err := json.Marshal(c.policy.BandwidthWindows)
+	if err != nil {
+		return err
+	}
+
 	for i, srcResource := range srcResources {
 		dstResource := dstResources[i]
 		// if dest resource should be skipped, ignore replicate.
@@ -137,15 +151,22 @@ func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources [
 				JobKind: job.KindGeneric,
 			},
 			Parameters: map[string]interface{}{
-				"src_resource":  string(src),
-				"dst_resource":  string(dest),
-				"speed":         speed,
-				"copy_by_chunk": copyByChunk,
+				"src_resource":        string(src),
+				"dst_resource":        string(dest),
+				"speed":               speed,
+				"copy_by_chunk":       copyByChunk,
+				"require_accessories": requireAccessories,
+				"bandwidth_windows":   string(windows),
 			},
 		}
 
+		operation := "copy"
+		if dstResource.Deleted {
+			operation = "tag deletion"
+		}
+
 		if _, err = c.taskMgr.Create(ctx, c.executionID, job, map[string]interface{}{
-			"operation":            "copy",
+			"operation":            operation,
 			"resource_type":        string(srcResource.Type),
 			"source_resource":      getResourceName(srcResource),
 			"destination_resource": getResourceName(dstResource),
@@ -157,3 +178,98 @@ func (c *copyFlow) createTasks(ctx context.Context, srcResources, dstResources [
 	}
 	return nil
 }
+
+// reconcile compares the tags of the repositories on the source and destination registries, returns the resources
+// for deleting the tags which exist on the destination but not on the source. Only the tags matching the tag filters
+// of the policy are deleted and the repositories whose tags cannot be listed are skipped
+func reconcile(ctx context.Context, policy *repctlmodel.Policy, srcAdapter, dstAdapter adp.Adapter, srcResources, dstResources []*model.Resource) ([]*model.Resource, []*model.Resource) {
+	logger := log.GetLogger(ctx)
+	srcRegistry, ok := srcAdapter.(adp.ArtifactRegistry)
+	if !ok {
+		return nil, nil
+	}
+	dstRegistry, ok := dstAdapter.(adp.ArtifactRegistry)
+	if !ok {
+		return nil, nil
+	}
+	var tagFilters []*model.Filter
+	for _, f := range policy.Filters {
+		if f.Type == model.FilterTypeTag {
+			tagFilters = append(tagFilters, f)
+		}
+	}
+
+	var srcDeletions, dstDeletions []*model.Resource
+	reconciled := map[string]struct{}{}
+	for i, srcResource := range srcResources {
+		dstResource := dstResources[i]
+		if dstResource.Skip || srcResource.Type != model.ResourceTypeArtifact {
+			continue
+		}
+		srcRepository := srcResource.Metadata.Repository.Name
+		dstRepository := dstResource.Metadata.Repository.Name
+		if _, exist := reconciled[dstRepository]; exist {
+			continue
+		}
+		reconciled[dstRepository] = struct{}{}
+
+		dstTags, err := dstRegistry.ListTags(dstRepository)
+		if err != nil {
+			logger.Warningf("failed to list the tags of %s on the destination registry, skip the reconciliation: %v", dstRepository, err)
+			continue
+		}
+		if len(dstTags) == 0 {
+			continue
+		}
+		// only reconcile the tags in the scope of the policy
+		artifacts, err := filter.DoFilterArtifacts([]*model.Artifact{{Tags: dstTags}}, tagFilters)
+		if err != nil {
+			logger.Warningf("failed to filter the tags of %s on the destination registry, skip the reconciliation: %v", dstRepository, err)
+			continue
+		}
+		srcTags, err := srcRegistry.ListTags(srcRepository)
+		if err != nil {
+			logger.Warningf("failed to list the tags of %s on the source registry, skip the reconciliation: %v", srcRepository, err)
+			continue
+		}
+		existing := map[string]struct{}{}
+		for _, tag := range srcTags {
+			existing[tag] = struct{}{}
+		}
+		var stale []string
+		for _, art := range artifacts {
+			for _, tag := range art.Tags {
+				if _, exist := existing[tag]; !exist {
+					stale = append(stale, tag)
+				}
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		logger.Infof("the tags %v of %s don't exist on the source registry, delete them", stale, dstRepository)
+
+		srcDeletions = append(srcDeletions, staleTagsResource(srcResource, stale))
+		dstDeletions = append(dstDeletions, staleTagsResource(dstResource, stale))
+	}
+	return srcDeletions, dstDeletions
+}
+
+// staleTagsResource returns the resource for deleting the tags
+func staleTagsResource(resource *model.Resource, tags []string) *model.Resource {
+	return &model.Resource{
+		Type: resource.Type,
+		Metadata: &model.ResourceMetadata{
+			Repository: resource.Metadata.Repository,
+			Artifacts: []*model.Artifact{
+				{
+					Tags: tags,
+				},
+			},
+		},
+		Registry:     resource.Registry,
+		ExtendedInfo: resource.ExtendedInfo,
+		Deleted:      true,
+		IsDeleteTag:  true,
+	}
+}
@@ -80,6 +80,85 @@ func (c *copyFlowTestSuite) TestRun() {
 	c.Require().Nil(err)
 }
 
+func (c *copyFlowTestSuite) TestRunWithReconciliation() {
+	srcAdapter := &mockAdapter{}
+	srcFactory := &mockFactory{}
+	srcFactory.On("AdapterPattern").Return(nil)
+	srcFactory.On("Create", mock.Anything).Return(srcAdapter, nil)
+	adapter.RegisterFactory("TEST_FOR_RECONCILE_SRC", srcFactory)
+	dstAdapter := &mockAdapter{}
+	dstFactory := &mockFactory{}
+	dstFactory.On("AdapterPattern").Return(nil)
+	dstFactory.On("Create", mock.Anything).Return(dstAdapter, nil)
+	adapter.RegisterFactory("TEST_FOR_RECONCILE_DST", dstFactory)
+
+	srcAdapter.On("FetchArtifacts", mock.Anything).Return([]*model.Resource{
+		{
+			Type: model.ResourceTypeArtifact,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Artifacts: []*model.Artifact{
+					{
+						Digest: "sha256:418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180",
+						Tags:   []string{"v1"},
+					},
+				},
+			},
+		},
+	}, nil)
+	srcAdapter.On("ListTags", "library/hello-world").Return([]string{"v1", "dev"}, nil)
+	dstAdapter.On("Info").Return(&model.RegistryInfo{
+		SupportedResourceTypes: []string{
+			model.ResourceTypeArtifact,
+		},
+	}, nil)
+	dstAdapter.On("PrepareForPush", mock.Anything).Return(nil)
+	// "v2" doesn't exist on the source, "dev" doesn't match the tag filter
+	dstAdapter.On("ListTags", "library/hello-world").Return([]string{"v1", "v2", "dev"}, nil)
+
+	execMgr := &testingTask.ExecutionManager{}
+	execMgr.On("Get", mock.Anything, mock.Anything).Return(&task.Execution{
+		Status: job.RunningStatus.String(),
+	}, nil)
+
+	taskMgr := &testingTask.Manager{}
+	taskMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(attrs map[string]interface{}) bool {
+		return attrs["operation"] == "copy"
+	})).Return(int64(1), nil).Once()
+	taskMgr.On("Create", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(attrs map[string]interface{}) bool {
+		return attrs["operation"] == "tag deletion" && attrs["references"] == "v2"
+	})).Return(int64(2), nil).Once()
+	policy := &repctlmodel.Policy{
+		SrcRegistry: &model.Registry{
+			ID:   1,
+			Type: "TEST_FOR_RECONCILE_SRC",
+		},
+		DestRegistry: &model.Registry{
+			ID:   2,
+			Type: "TEST_FOR_RECONCILE_DST",
+		},
+		Filters: []*model.Filter{
+			{
+				Type:  model.FilterTypeTag,
+				Value: "v*",
+			},
+		},
+		ReplicateDeletion: true,
+		Reconcile:         true,
+	}
+	flow := &copyFlow{
+		executionID:  1,
+		policy:       policy,
+		executionMgr: execMgr,
+		taskMgr:      taskMgr,
+	}
+	err := flow.Run(context.Background())
+	c.Require().Nil(err)
+	taskMgr.AssertExpectations(c.T())
+}
+
 func TestCopyFlowTestSuite(t *testing.T) {
 	suite.Run(t, &copyFlowTestSuite{})
 }
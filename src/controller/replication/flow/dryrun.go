@@ -0,0 +1,264 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+// the actions of the artifacts in the dry run report
+const (
+	DryRunActionCreate    = "create"
+	DryRunActionOverwrite = "overwrite"
+	DryRunActionSkip      = "skip"
+	DryRunActionDelete    = "delete"
+)
+
+// DryRunReport is the report of the dry run, it describes what the replication of the policy will do
+type DryRunReport struct {
+	Items []*DryRunItem `json:"items"`
+	// the count of artifacts per action
+	Create    int `json:"create"`
+	Overwrite int `json:"overwrite"`
+	Skip      int `json:"skip"`
+	Delete    int `json:"delete"`
+	// the estimated bytes to transfer, the blobs shared by the artifacts are counted only once
+	// and the blobs which already exist on the destination aren't counted
+	EstimatedBytes int64 `json:"estimated_bytes"`
+}
+
+// DryRunItem is the dry run result of an artifact
+type DryRunItem struct {
+	SourceRepository      string `json:"source_repository"`
+	DestinationRepository string `json:"destination_repository"`
+	// the tag or digest of the artifact
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+	Action    string `json:"action"`
+	// the reason why the artifact is skipped
+	Reason string `json:"reason,omitempty"`
+	// the estimated bytes to transfer for the artifact
+	Bytes int64 `json:"bytes"`
+}
+
+func (r *DryRunReport) add(item *DryRunItem) {
+	r.Items = append(r.Items, item)
+	switch item.Action {
+	case DryRunActionCreate:
+		r.Create++
+	case DryRunActionOverwrite:
+		r.Overwrite++
+	case DryRunActionSkip:
+		r.Skip++
+	case DryRunActionDelete:
+		r.Delete++
+	}
+	r.EstimatedBytes += item.Bytes
+}
+
+// DryRun runs the fetch and filter stages of the replication flow and checks the existence of the artifacts
+// on the destination registry without changing anything on both registries
+func DryRun(ctx context.Context, policy *repctlmodel.Policy) (*DryRunReport, error) {
+	srcAdapter, dstAdapter, err := initialize(policy)
+	if err != nil {
+		return nil, err
+	}
+	srcRegistry, ok := srcAdapter.(adp.ArtifactRegistry)
+	if !ok {
+		return nil, fmt.Errorf("the adapter of source registry doesn't implement the ArtifactRegistry interface")
+	}
+	dstRegistry, ok := dstAdapter.(adp.ArtifactRegistry)
+	if !ok {
+		return nil, fmt.Errorf("the adapter of destination registry doesn't implement the ArtifactRegistry interface")
+	}
+	srcResources, err := fetchResources(srcAdapter, policy)
+	if err != nil {
+		return nil, err
+	}
+	srcResources = assembleSourceResources(srcResources, policy)
+	info, err := dstAdapter.Info()
+	if err != nil {
+		return nil, err
+	}
+	dstResources, err := assembleDestinationResources(srcResources, policy, info.SupportedRepositoryPathComponentType)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dryRun{
+		src:      srcRegistry,
+		dst:      dstRegistry,
+		override: policy.Override,
+		blobs:    map[string]struct{}{},
+		report:   &DryRunReport{},
+	}
+	for i, srcResource := range srcResources {
+		dstResource := dstResources[i]
+		srcRepo, srcRefs := references(srcResource)
+		dstRepo, dstRefs := references(dstResource)
+		for j := range srcRefs {
+			item := &DryRunItem{
+				SourceRepository:      srcRepo,
+				DestinationRepository: dstRepo,
+				Reference:             dstRefs[j],
+			}
+			if dstResource.Skip {
+				item.Action = DryRunActionSkip
+				item.Reason = "skipped because of the limitation of the destination"
+			} else {
+				d.check(item, srcRefs[j])
+			}
+			d.report.add(item)
+		}
+	}
+
+	// the tags which don't exist on the source are deleted by the reconciliation
+	if policy.Reconcile && policy.ReplicateDeletion {
+		_, dstDeletions := reconcile(ctx, policy, srcAdapter, dstAdapter, srcResources, dstResources)
+		for _, res := range dstDeletions {
+			for _, art := range res.Metadata.Artifacts {
+				for _, tag := range art.Tags {
+					d.report.add(&DryRunItem{
+						DestinationRepository: res.Metadata.Repository.Name,
+						Reference:             tag,
+						Action:                DryRunActionDelete,
+					})
+				}
+			}
+		}
+	}
+	return d.report, nil
+}
+
+type dryRun struct {
+	src      adp.ArtifactRegistry
+	dst      adp.ArtifactRegistry
+	override bool
+	// the blobs which are already counted in the estimated bytes
+	blobs  map[string]struct{}
+	report *DryRunReport
+}
+
+// check fills the action of the artifact by the same rules as the copy of the transfer
+func (d *dryRun) check(item *DryRunItem, srcRef string) {
+	manifest, digest, err := d.src.PullManifest(item.SourceRepository, srcRef)
+	if err != nil {
+		item.Action = DryRunActionSkip
+		item.Reason = fmt.Sprintf("failed to pull the manifest from the source registry: %v", err)
+		return
+	}
+	item.Digest = digest
+
+	exist, desc, err := d.dst.ManifestExist(item.DestinationRepository, item.Reference)
+	if err != nil {
+		item.Action = DryRunActionSkip
+		item.Reason = fmt.Sprintf("failed to check the existence of the manifest on the destination registry: %v", err)
+		return
+	}
+	item.Action = DryRunActionCreate
+	if exist {
+		if desc != nil && string(desc.Digest) == digest {
+			item.Action = DryRunActionSkip
+			item.Reason = "the artifact already exists on the destination registry"
+			return
+		}
+		if !d.override {
+			item.Action = DryRunActionSkip
+			item.Reason = "the artifact with the same name exists on the destination registry and the override is disabled"
+			return
+		}
+		item.Action = DryRunActionOverwrite
+	}
+
+	bytes, err := d.estimate(item.SourceRepository, item.DestinationRepository, manifest)
+	if err != nil {
+		log.Warningf("failed to estimate the size of %s:%s: %v", item.SourceRepository, srcRef, err)
+	}
+	item.Bytes = bytes
+}
+
+// estimate returns the bytes of the blobs which don't exist on the destination registry and aren't counted yet
+func (d *dryRun) estimate(srcRepo, dstRepo string, manifest distribution.Manifest) (int64, error) {
+	var total int64
+	for _, content := range manifest.References() {
+		dgt := content.Digest.String()
+		switch content.MediaType {
+		case v1.MediaTypeImageIndex, manifestlist.MediaTypeManifestList,
+			v1.MediaTypeImageManifest, schema2.MediaTypeManifest,
+			schema1.MediaTypeSignedManifest, schema1.MediaTypeManifest:
+			exist, _, err := d.dst.ManifestExist(dstRepo, dgt)
+			if err != nil {
+				return total, err
+			}
+			if exist {
+				continue
+			}
+			child, _, err := d.src.PullManifest(srcRepo, dgt)
+			if err != nil {
+				return total, err
+			}
+			size, err := d.estimate(srcRepo, dstRepo, child)
+			total += size
+			if err != nil {
+				return total, err
+			}
+		case schema2.MediaTypeForeignLayer:
+			// the foreign layers aren't copied
+		default:
+			if _, counted := d.blobs[dgt]; counted {
+				continue
+			}
+			exist, err := d.dst.BlobExist(dstRepo, dgt)
+			if err != nil {
+				return total, err
+			}
+			d.blobs[dgt] = struct{}{}
+			if !exist {
+				total += content.Size
+			}
+		}
+	}
+	return total, nil
+}
+
+// references returns the repository and the references of the resource in the same way as the transfer
+func references(resource *model.Resource) (string, []string) {
+	var refs []string
+	for _, artifact := range resource.Metadata.Artifacts {
+		if len(artifact.Tags) > 0 {
+			refs = append(refs, artifact.Tags...)
+			continue
+		}
+		if len(artifact.Digest) > 0 {
+			refs = append(refs, artifact.Digest)
+		}
+	}
+	if len(refs) == 0 {
+		refs = resource.Metadata.Vtags
+	}
+	return resource.Metadata.Repository.Name, refs
+}
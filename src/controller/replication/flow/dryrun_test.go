@@ -0,0 +1,150 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repctlmodel "github.com/goharbor/harbor/src/controller/replication/model"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+const (
+	configDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+	sharedDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000002"
+	layerDigest  = "sha256:0000000000000000000000000000000000000000000000000000000000000003"
+	existDigest  = "sha256:0000000000000000000000000000000000000000000000000000000000000004"
+)
+
+type dryRunTestSuite struct {
+	suite.Suite
+}
+
+func (d *dryRunTestSuite) manifest(layers ...distribution.Descriptor) distribution.Manifest {
+	manifest, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config: distribution.Descriptor{
+			MediaType: schema2.MediaTypeImageConfig,
+			Digest:    digest.Digest(configDigest),
+			Size:      1,
+		},
+		Layers: layers,
+	})
+	d.Require().Nil(err)
+	return manifest
+}
+
+func (d *dryRunTestSuite) TestDryRun() {
+	srcAdapter := &mockAdapter{}
+	srcFactory := &mockFactory{}
+	srcFactory.On("AdapterPattern").Return(nil)
+	srcFactory.On("Create", mock.Anything).Return(srcAdapter, nil)
+	adapter.RegisterFactory("TEST_FOR_DRY_RUN_SRC", srcFactory)
+	dstAdapter := &mockAdapter{}
+	dstFactory := &mockFactory{}
+	dstFactory.On("AdapterPattern").Return(nil)
+	dstFactory.On("Create", mock.Anything).Return(dstAdapter, nil)
+	adapter.RegisterFactory("TEST_FOR_DRY_RUN_DST", dstFactory)
+
+	shared := distribution.Descriptor{MediaType: schema2.MediaTypeLayer, Digest: digest.Digest(sharedDigest), Size: 100}
+	layer := distribution.Descriptor{MediaType: schema2.MediaTypeLayer, Digest: digest.Digest(layerDigest), Size: 10}
+	exist := distribution.Descriptor{MediaType: schema2.MediaTypeLayer, Digest: digest.Digest(existDigest), Size: 1000}
+
+	srcAdapter.On("FetchArtifacts", mock.Anything).Return([]*model.Resource{
+		{
+			Type: model.ResourceTypeArtifact,
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{
+					Name: "library/hello-world",
+				},
+				Artifacts: []*model.Artifact{
+					{Tags: []string{"new1", "new2"}},
+					{Tags: []string{"same", "changed"}},
+				},
+			},
+		},
+	}, nil)
+	srcAdapter.On("PullManifest", "library/hello-world", "new1").Return(d.manifest(shared, exist), "sha256:new1", nil)
+	srcAdapter.On("PullManifest", "library/hello-world", "new2").Return(d.manifest(shared, layer), "sha256:new2", nil)
+	srcAdapter.On("PullManifest", "library/hello-world", "same").Return(d.manifest(shared), "sha256:same", nil)
+	srcAdapter.On("PullManifest", "library/hello-world", "changed").Return(d.manifest(layer), "sha256:changed", nil)
+	srcAdapter.On("ListTags", "library/hello-world").Return([]string{"new1", "new2", "same", "changed"}, nil)
+
+	dstAdapter.On("Info").Return(&model.RegistryInfo{
+		SupportedResourceTypes: []string{
+			model.ResourceTypeArtifact,
+		},
+	}, nil)
+	dstAdapter.On("ManifestExist", "library/hello-world", "new1").Return(false, nil, nil)
+	dstAdapter.On("ManifestExist", "library/hello-world", "new2").Return(false, nil, nil)
+	dstAdapter.On("ManifestExist", "library/hello-world", "same").Return(true, &distribution.Descriptor{Digest: "sha256:same"}, nil)
+	dstAdapter.On("ManifestExist", "library/hello-world", "changed").Return(true, &distribution.Descriptor{Digest: "sha256:old"}, nil)
+	dstAdapter.On("BlobExist", "library/hello-world", configDigest).Return(true, nil)
+	dstAdapter.On("BlobExist", "library/hello-world", existDigest).Return(true, nil)
+	dstAdapter.On("BlobExist", "library/hello-world", sharedDigest).Return(false, nil)
+	dstAdapter.On("BlobExist", "library/hello-world", layerDigest).Return(false, nil)
+	dstAdapter.On("ListTags", "library/hello-world").Return([]string{"same", "changed", "stale"}, nil)
+
+	policy := &repctlmodel.Policy{
+		SrcRegistry: &model.Registry{
+			ID:   1,
+			Type: "TEST_FOR_DRY_RUN_SRC",
+		},
+		DestRegistry: &model.Registry{
+			ID:   2,
+			Type: "TEST_FOR_DRY_RUN_DST",
+		},
+		Override:          false,
+		ReplicateDeletion: true,
+		Reconcile:         true,
+	}
+	report, err := DryRun(context.Background(), policy)
+	d.Require().Nil(err)
+	d.Equal(2, report.Create)
+	d.Equal(0, report.Overwrite)
+	d.Equal(2, report.Skip)
+	d.Equal(1, report.Delete)
+	// the shared layer is counted once and the existing blobs aren't counted
+	d.Equal(int64(110), report.EstimatedBytes)
+	d.Require().Len(report.Items, 5)
+	d.Equal(int64(100), report.Items[0].Bytes)
+	d.Equal(int64(10), report.Items[1].Bytes)
+	d.Equal("stale", report.Items[4].Reference)
+	d.Equal(DryRunActionDelete, report.Items[4].Action)
+
+	// overwrite the changed artifact
+	policy.Override = true
+	policy.Reconcile = false
+	report, err = DryRun(context.Background(), policy)
+	d.Require().Nil(err)
+	d.Equal(2, report.Create)
+	d.Equal(1, report.Overwrite)
+	d.Equal(1, report.Skip)
+	d.Equal(0, report.Delete)
+	d.Equal(DryRunActionOverwrite, report.Items[3].Action)
+	d.Equal("sha256:changed", report.Items[3].Digest)
+}
+
+func TestDryRunTestSuite(t *testing.T) {
+	suite.Run(t, &dryRunTestSuite{})
+}
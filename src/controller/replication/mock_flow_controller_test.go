@@ -7,6 +7,7 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	flow "github.com/goharbor/harbor/src/controller/replication/flow"
 	model "github.com/goharbor/harbor/src/controller/replication/model"
 
 	regmodel "github.com/goharbor/harbor/src/pkg/reg/model"
@@ -17,6 +18,36 @@ type flowController struct {
 	mock.Mock
 }
 
+// DryRun provides a mock function with given fields: ctx, policy
+func (_m *flowController) DryRun(ctx context.Context, policy *model.Policy) (*flow.DryRunReport, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DryRun")
+	}
+
+	var r0 *flow.DryRunReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy) (*flow.DryRunReport, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy) *flow.DryRunReport); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.DryRunReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Policy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields: ctx, executionID, policy, resource
 func (_m *flowController) Start(ctx context.Context, executionID int64, policy *model.Policy, resource *regmodel.Resource) error {
 	ret := _m.Called(ctx, executionID, policy, resource)
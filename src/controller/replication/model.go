@@ -29,6 +29,7 @@ type Execution struct {
 	Metrics       *dao.Metrics
 	Trigger       string
 	Operator      string
+	DryRun        bool
 	StartTime     time.Time
 	EndTime       time.Time
 }
@@ -45,6 +46,8 @@ type Task struct {
 	DestinationResource string
 	References          string
 	Operation           string
+	CopiedBlobs         int64
+	CopiedBytes         int64
 	JobID               string
 	CreationTime        time.Time
 	StartTime           time.Time
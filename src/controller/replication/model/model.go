@@ -47,6 +47,10 @@ type Policy struct {
 	UpdateTime                time.Time       `json:"update_time"`
 	Speed                     int32           `json:"speed"`
 	CopyByChunk               bool            `json:"copy_by_chunk"`
+	RequireAccessories        bool            `json:"require_accessories"`
+	Reconcile                 bool            `json:"reconcile"`
+	// BandwidthWindows overrides the Speed of each task during the time ranges of the day
+	BandwidthWindows []*model.BandwidthWindow `json:"bandwidth_windows"`
 }
 
 // IsScheduledTrigger returns true when the policy is scheduled trigger and enabled
@@ -60,6 +64,25 @@ func (p *Policy) IsScheduledTrigger() bool {
 	return p.Trigger.Type == model.TriggerTypeScheduled
 }
 
+// IsReconcileScheduled returns true when the policy is event based trigger and enabled and the reconciliation
+// is scheduled to repair the drift between the source and destination
+func (p *Policy) IsReconcileScheduled() bool {
+	if !p.Enabled || !p.Reconcile {
+		return false
+	}
+	if p.Trigger == nil || p.Trigger.Type != model.TriggerTypeEventBased {
+		return false
+	}
+	return p.Trigger.Settings != nil && len(p.Trigger.Settings.Cron) > 0
+}
+
+// IsRelay returns true when neither the source registry nor the destination registry is the local Harbor,
+// the artifacts of the relay policy are streamed through the local instance without being persisted
+func (p *Policy) IsRelay() bool {
+	return p.SrcRegistry != nil && p.SrcRegistry.ID != 0 &&
+		p.DestRegistry != nil && p.DestRegistry.ID != 0
+}
+
 // Validate the policy
 func (p *Policy) Validate() error {
 	if len(p.Name) == 0 {
@@ -73,11 +96,15 @@ func (p *Policy) Validate() error {
 		dstRegistryID = p.DestRegistry.ID
 	}
 
-	// one of the source registry and destination registry must be Harbor itself
-	if srcRegistryID != 0 && dstRegistryID != 0 ||
-		srcRegistryID == 0 && dstRegistryID == 0 {
+	// at least one of the source registry and destination registry must be the remote registry,
+	// when both are remote registries, the policy relays the artifacts between them
+	if srcRegistryID == 0 && dstRegistryID == 0 {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessage("src_registry and dest_registry cannot be both empty")
+	}
+	if srcRegistryID == dstRegistryID {
 		return errors.New(nil).WithCode(errors.BadRequestCode).
-			WithMessage("either src_registry or dest_registry should be empty and the other one shouldn't be empty")
+			WithMessage("src_registry and dest_registry cannot be the same registry")
 	}
 
 	// valid the filters
@@ -87,6 +114,11 @@ func (p *Policy) Validate() error {
 		}
 	}
 
+	// valid the bandwidth windows
+	if err := model.ValidateBandwidthWindows(p.BandwidthWindows); err != nil {
+		return err
+	}
+
 	// valid the destination namespace
 	if len(p.DestNamespace) > 0 {
 		if !lib.RepositoryNameRe.MatchString(p.DestNamespace) {
@@ -98,22 +130,30 @@ func (p *Policy) Validate() error {
 	// valid trigger
 	if p.Trigger != nil {
 		switch p.Trigger.Type {
-		case model.TriggerTypeManual, model.TriggerTypeEventBased:
+		case model.TriggerTypeManual:
+		case model.TriggerTypeEventBased:
+			// the events are produced only by the local Harbor
+			if p.IsRelay() {
+				return errors.New(nil).WithCode(errors.BadRequestCode).
+					WithMessagef("the trigger type %s isn't supported by the relay policy", model.TriggerTypeEventBased)
+			}
+			// the cron of the event based trigger schedules the reconciliation
+			if p.Trigger.Settings != nil && len(p.Trigger.Settings.Cron) > 0 {
+				if !p.Reconcile {
+					return errors.New(nil).WithCode(errors.BadRequestCode).
+						WithMessagef("the cron string is only allowed for the %s trigger when the reconciliation is enabled", model.TriggerTypeEventBased)
+				}
+				if err := validateCron(p.Trigger.Settings.Cron); err != nil {
+					return err
+				}
+			}
 		case model.TriggerTypeScheduled:
 			if p.Trigger.Settings == nil || len(p.Trigger.Settings.Cron) == 0 {
 				return errors.New(nil).WithCode(errors.BadRequestCode).
 					WithMessagef("the cron string cannot be empty when the trigger type is %s", model.TriggerTypeScheduled)
 			}
-			if _, err := utils.CronParser().Parse(p.Trigger.Settings.Cron); err != nil {
-				return errors.New(nil).WithCode(errors.BadRequestCode).
-					WithMessagef("invalid cron string for scheduled trigger: %s", p.Trigger.Settings.Cron)
-			}
-			cronParts := strings.Split(p.Trigger.Settings.Cron, " ")
-			if cronParts[0] != "0" {
-				return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the 1st field (indicating Seconds of time) of the cron setting must be 0")
-			}
-			if cronParts[1] == "*" {
-				return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("* is not allowed for the Minutes field of the cron setting of replication policy")
+			if err := validateCron(p.Trigger.Settings.Cron); err != nil {
+				return err
 			}
 		default:
 			return errors.New(nil).WithCode(errors.BadRequestCode).
@@ -123,6 +163,21 @@ func (p *Policy) Validate() error {
 	return nil
 }
 
+func validateCron(cron string) error {
+	if _, err := utils.CronParser().Parse(cron); err != nil {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessagef("invalid cron string for scheduled trigger: %s", cron)
+	}
+	cronParts := strings.Split(cron, " ")
+	if cronParts[0] != "0" {
+		return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the 1st field (indicating Seconds of time) of the cron setting must be 0")
+	}
+	if cronParts[1] == "*" {
+		return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("* is not allowed for the Minutes field of the cron setting of replication policy")
+	}
+	return nil
+}
+
 // From converts the pkg model into the Policy
 func (p *Policy) From(policy *replicationmodel.Policy) error {
 	if policy == nil {
@@ -141,6 +196,13 @@ func (p *Policy) From(policy *replicationmodel.Policy) error {
 	p.UpdateTime = policy.UpdateTime
 	p.Speed = policy.Speed
 	p.CopyByChunk = policy.CopyByChunk
+	p.RequireAccessories = policy.RequireAccessories
+	p.Reconcile = policy.Reconcile
+	if len(policy.BandwidthWindows) > 0 {
+		if err := json.Unmarshal([]byte(policy.BandwidthWindows), &p.BandwidthWindows); err != nil {
+			return err
+		}
+	}
 
 	if policy.SrcRegistryID > 0 {
 		p.SrcRegistry = &model.Registry{
@@ -186,6 +248,8 @@ func (p *Policy) To() (*replicationmodel.Policy, error) {
 		UpdateTime:                p.UpdateTime,
 		Speed:                     p.Speed,
 		CopyByChunk:               p.CopyByChunk,
+		RequireAccessories:        p.RequireAccessories,
+		Reconcile:                 p.Reconcile,
 	}
 	if p.SrcRegistry != nil {
 		policy.SrcRegistryID = p.SrcRegistry.ID
@@ -202,6 +266,14 @@ func (p *Policy) To() (*replicationmodel.Policy, error) {
 		policy.Trigger = string(trigger)
 	}
 
+	if len(p.BandwidthWindows) > 0 {
+		windows, err := json.Marshal(p.BandwidthWindows)
+		if err != nil {
+			return nil, err
+		}
+		policy.BandwidthWindows = string(windows)
+	}
+
 	if len(p.Filters) > 0 {
 		filters, err := json.Marshal(p.Filters)
 		if err != nil {
@@ -61,6 +61,35 @@ func TestIsScheduledTrigger(t *testing.T) {
 	assert.True(b)
 }
 
+func TestIsReconcileScheduled(t *testing.T) {
+	assert := assert.New(t)
+	// reconciliation isn't enabled
+	policy := &Policy{
+		Trigger: &model.Trigger{
+			Type: model.TriggerTypeEventBased,
+			Settings: &model.TriggerSettings{
+				Cron: "0 0 * * * *",
+			},
+		},
+		Enabled: true,
+	}
+	assert.False(policy.IsReconcileScheduled())
+
+	// reconciliation is enabled
+	policy.Reconcile = true
+	assert.True(policy.IsReconcileScheduled())
+
+	// no cron
+	policy.Trigger.Settings = nil
+	assert.False(policy.IsReconcileScheduled())
+
+	// isn't event based trigger
+	policy.Trigger = &model.Trigger{
+		Type: model.TriggerTypeManual,
+	}
+	assert.False(policy.IsReconcileScheduled())
+}
+
 func TestValidate(t *testing.T) {
 	assert := assert.New(t)
 
@@ -76,7 +105,36 @@ func TestValidate(t *testing.T) {
 	err = policy.Validate()
 	assert.True(errors.IsErr(err, errors.BadRequestCode))
 
-	// source registry and destination registry both not empty
+	// source registry and destination registry are the same registry
+	policy = &Policy{
+		Name: "policy01",
+		SrcRegistry: &model.Registry{
+			ID: 1,
+		},
+		DestRegistry: &model.Registry{
+			ID: 1,
+		},
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// relay policy with event based trigger
+	policy = &Policy{
+		Name: "policy01",
+		SrcRegistry: &model.Registry{
+			ID: 1,
+		},
+		DestRegistry: &model.Registry{
+			ID: 2,
+		},
+		Trigger: &model.Trigger{
+			Type: model.TriggerTypeEventBased,
+		},
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// relay policy
 	policy = &Policy{
 		Name: "policy01",
 		SrcRegistry: &model.Registry{
@@ -85,10 +143,54 @@ func TestValidate(t *testing.T) {
 		DestRegistry: &model.Registry{
 			ID: 2,
 		},
+		Trigger: &model.Trigger{
+			Type: model.TriggerTypeManual,
+		},
+	}
+	err = policy.Validate()
+	assert.Nil(err)
+	assert.True(policy.IsRelay())
+
+	// invalid bandwidth window
+	policy = &Policy{
+		Name: "policy01",
+		DestRegistry: &model.Registry{
+			ID: 1,
+		},
+		BandwidthWindows: []*model.BandwidthWindow{
+			{Start: "22:00", End: "24:30"},
+		},
+	}
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// event based trigger with cron but without reconciliation
+	policy = &Policy{
+		Name: "policy01",
+		DestRegistry: &model.Registry{
+			ID: 1,
+		},
+		Trigger: &model.Trigger{
+			Type: model.TriggerTypeEventBased,
+			Settings: &model.TriggerSettings{
+				Cron: "0 0 * * * *",
+			},
+		},
 	}
 	err = policy.Validate()
 	assert.True(errors.IsErr(err, errors.BadRequestCode))
 
+	// event based trigger with invalid reconciliation cron
+	policy.Reconcile = true
+	policy.Trigger.Settings.Cron = "0 * * * * *"
+	err = policy.Validate()
+	assert.True(errors.IsErr(err, errors.BadRequestCode))
+
+	// event based trigger with reconciliation
+	policy.Trigger.Settings.Cron = "0 0 * * * *"
+	err = policy.Validate()
+	assert.Nil(err)
+
 	// invalid filter
 	policy = &Policy{
 		Name: "policy01",
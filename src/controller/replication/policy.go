@@ -86,12 +86,12 @@ func (c *controller) populateRegistry(ctx context.Context, p *pkgmodel.Policy) (
 	if err != nil {
 		return nil, err
 	}
+	// the ID of the local Harbor is 0, it is used when the registry isn't specified
 	var srcRegistryID, destRegistryID int64
-	if policy.SrcRegistry != nil && policy.SrcRegistry.ID != 0 {
+	if policy.SrcRegistry != nil {
 		srcRegistryID = policy.SrcRegistry.ID
-		destRegistryID = 0
-	} else {
-		srcRegistryID = 0
+	}
+	if policy.DestRegistry != nil {
 		destRegistryID = policy.DestRegistry.ID
 	}
 	srcRegistry, err := c.regMgr.Get(ctx, srcRegistryID)
@@ -132,7 +132,7 @@ func (c *controller) CreatePolicy(ctx context.Context, policy *model.Policy) (in
 		return 0, err
 	}
 	// create schedule if needed
-	if policy.IsScheduledTrigger() {
+	if policy.IsScheduledTrigger() || policy.IsReconcileScheduled() {
 		cbParams := map[string]interface{}{
 			"policy_id": id,
 			// the operator of schedule job is harbor-jobservice
@@ -164,7 +164,7 @@ func (c *controller) UpdatePolicy(ctx context.Context, policy *model.Policy, pro
 		return err
 	}
 	// create schedule if needed
-	if policy.IsScheduledTrigger() {
+	if policy.IsScheduledTrigger() || policy.IsReconcileScheduled() {
 		cbParams := map[string]interface{}{
 			"policy_id": policy.ID,
 			// the operator of schedule job is harbor-jobservice
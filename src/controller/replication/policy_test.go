@@ -64,6 +64,27 @@ func (r *replicationTestSuite) TestGetPolicy() {
 	r.regMgr.AssertExpectations(r.T())
 }
 
+func (r *replicationTestSuite) TestGetRelayPolicy() {
+	mock.OnAnything(r.repMgr, "Get").Return(&replicationmodel.Policy{
+		ID:             1,
+		SrcRegistryID:  1,
+		DestRegistryID: 2,
+	}, nil)
+	r.regMgr.On("Get", mock.Anything, int64(1)).Return(&model.Registry{
+		ID: 1,
+	}, nil)
+	r.regMgr.On("Get", mock.Anything, int64(2)).Return(&model.Registry{
+		ID: 2,
+	}, nil)
+	policy, err := r.ctl.GetPolicy(nil, 1)
+	r.Require().Nil(err)
+	r.True(policy.IsRelay())
+	r.Equal(int64(1), policy.SrcRegistry.ID)
+	r.Equal(int64(2), policy.DestRegistry.ID)
+	r.repMgr.AssertExpectations(r.T())
+	r.regMgr.AssertExpectations(r.T())
+}
+
 func (r *replicationTestSuite) TestCreatePolicy() {
 	mock.OnAnything(r.repMgr, "Create").Return(int64(1), nil)
 	mock.OnAnything(r.regMgr, "Get").Return(&model.Registry{
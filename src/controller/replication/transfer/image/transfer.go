@@ -16,6 +16,7 @@ package image
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strconv"
@@ -26,11 +27,13 @@ import (
 	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema1"
 	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 
 	common_http "github.com/goharbor/harbor/src/common/http"
 	trans "github.com/goharbor/harbor/src/controller/replication/transfer"
 	"github.com/goharbor/harbor/src/lib"
+	liberrors "github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
@@ -85,6 +88,10 @@ type transfer struct {
 	isStopped trans.StopFunc
 	src       adapter.ArtifactRegistry
 	dst       adapter.ArtifactRegistry
+	progress  trans.Progress
+	report    trans.ProgressFunc
+	// the source and destination registries whose bandwidth limits are shared with other tasks
+	registries []*model.Registry
 }
 
 func (t *transfer) Transfer(src *model.Resource, dst *model.Resource, opts *trans.Options) error {
@@ -130,6 +137,8 @@ func (t *transfer) convert(resource *model.Resource) *repository {
 }
 
 func (t *transfer) initialize(src *model.Resource, dst *model.Resource) error {
+	t.registries = []*model.Registry{src.Registry, dst.Registry}
+
 	// create client for source registry
 	srcReg, err := createRegistry(src.Registry)
 	if err != nil {
@@ -185,6 +194,10 @@ func (t *transfer) copy(src *repository, dst *repository, override bool, opts *t
 	if opts.Speed > 0 {
 		t.logger.Infof("limit network speed at %d kb/s", opts.Speed)
 	}
+	for _, window := range opts.BandwidthWindows {
+		t.logger.Infof("limit network speed at %d kb/s between %s and %s", window.Speed, window.Start, window.End)
+	}
+	t.report = opts.Progress
 
 	var err error
 	for i := range src.tags {
@@ -226,7 +239,8 @@ func (t *transfer) copyArtifact(srcRepo, srcRef, dstRepo, dstRef string, overrid
 		if digest == digest2 {
 			t.logger.Infof("the artifact %s:%s already exists on the destination registry, skip",
 				dstRepo, dstRef)
-			return nil
+			// the referrers may be attached after the artifact is copied
+			return t.copyReferrersOf(srcRepo, digest, dstRepo, opts)
 		}
 		// the same name artifact exists, but not allowed to override
 		if !override {
@@ -246,16 +260,102 @@ func (t *transfer) copyArtifact(srcRepo, srcRef, dstRepo, dstRef string, overrid
 		}
 	}
 
+	// the accessories are required to be present before the tag is pushed,
+	// push the manifest by digest first to make the referrers refer to it
+	if opts.RequireAccessories && dstRef != digest {
+		if err := t.pushManifest(manifest, dstRepo, digest); err != nil {
+			return err
+		}
+		if err := t.copyReferrers(srcRepo, digest, dstRepo, opts); err != nil {
+			return err
+		}
+	}
+
 	// push the manifest to the destination registry
 	if err := t.pushManifest(manifest, dstRepo, dstRef); err != nil {
 		return err
 	}
 
+	if !opts.RequireAccessories || dstRef == digest {
+		if err := t.copyReferrersOf(srcRepo, digest, dstRepo, opts); err != nil {
+			return err
+		}
+	}
+
 	t.logger.Infof("copy %s:%s(source registry) to %s:%s(destination registry) completed",
 		srcRepo, srcRef, dstRepo, dstRef)
 	return nil
 }
 
+// copyReferrersOf copies the referrers of the artifact, the failures are ignored unless the accessories are required
+func (t *transfer) copyReferrersOf(srcRepo, digest, dstRepo string, opts *trans.Options) error {
+	err := t.copyReferrers(srcRepo, digest, dstRepo, opts)
+	if err == nil || err == errStopped || opts.RequireAccessories {
+		return err
+	}
+	t.logger.Warningf("failed to copy the referrers of artifact %s@%s, ignore: %v", srcRepo, digest, err)
+	return nil
+}
+
+// copyReferrers copies the referrers(signatures, SBOMs, etc.) of the artifact from the source registry to the destination,
+// the referrers are copied after the subject artifact as they refer to it
+func (t *transfer) copyReferrers(srcRepo, digest, dstRepo string, opts *trans.Options) error {
+	references, err := t.listReferrers(srcRepo, digest)
+	if err != nil {
+		return err
+	}
+	for _, reference := range references {
+		// the referrers are copied with the same references, as the tag scheme relies on the tag names
+		if err := t.copyArtifact(srcRepo, reference, dstRepo, reference, true, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listReferrers lists the references of the referrers of the artifact on the source registry.
+// The referrers API is used if the source registry supports it, otherwise the referrers are looked up
+// with the tag scheme(the OCI referrers tag and the cosign signature, attestation and SBOM tags)
+func (t *transfer) listReferrers(repository, dgt string) ([]string, error) {
+	if t.shouldStop() {
+		return nil, errStopped
+	}
+	var references []string
+	if lister, ok := t.src.(adapter.ReferrerRegistry); ok {
+		descriptors, err := lister.ListReferrers(repository, dgt)
+		if err == nil {
+			for _, desc := range descriptors {
+				references = append(references, desc.Digest.String())
+			}
+			return references, nil
+		}
+		if !liberrors.IsNotFoundErr(err) {
+			t.logger.Errorf("failed to list the referrers of artifact %s@%s: %v", repository, dgt, err)
+			return nil, err
+		}
+		t.logger.Debugf("the referrers API isn't supported by the source registry, fallback to the tag scheme")
+	}
+
+	d, err := digest.Parse(dgt)
+	if err != nil {
+		// not a digest, no referrers
+		return nil, nil
+	}
+	tag := fmt.Sprintf("%s-%s", d.Algorithm(), d.Encoded())
+	for _, reference := range []string{tag, tag + ".sig", tag + ".att", tag + ".sbom"} {
+		exist, _, err := t.src.ManifestExist(repository, reference)
+		if err != nil {
+			t.logger.Errorf("failed to check the existence of the manifest of artifact %s:%s on the source registry: %v",
+				repository, reference, err)
+			return nil, err
+		}
+		if exist {
+			references = append(references, reference)
+		}
+	}
+	return references, nil
+}
+
 // copy the content from source registry to destination according to its media type
 func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo string, opts *trans.Options) error {
 	digest := content.Digest.String()
@@ -275,12 +375,14 @@ func (t *transfer) copyContent(content distribution.Descriptor, srcRepo, dstRepo
 	// the media type of the layer or config can be "application/octet-stream",
 	// schema1.MediaTypeManifestLayer, schema2.MediaTypeLayer, schema2.MediaTypeImageConfig
 	default:
+		// the speed may change during the replication according to the bandwidth windows
+		speed := opts.SpeedAt(time.Now())
 		if opts.CopyByChunk {
 			// copy by chunk
-			return t.copyChunkWithRetry(srcRepo, dstRepo, digest, content.Size, opts.Speed)
+			return t.copyChunkWithRetry(srcRepo, dstRepo, digest, content.Size, speed)
 		}
 		// copy by blob
-		return t.copyBlobWithRetry(srcRepo, dstRepo, digest, content.Size, opts.Speed)
+		return t.copyBlobWithRetry(srcRepo, dstRepo, digest, content.Size, speed)
 	}
 }
 
@@ -383,6 +485,7 @@ func (t *transfer) copyBlobByMonolithic(srcRepo, dstRepo, digest string, sizeFro
 	if speed > 0 {
 		data = lib.NewReader(data, speed)
 	}
+	data = trans.Throttle(data, t.registries...)
 	defer data.Close()
 	// get size 0 from PullBlob, use size from distribution.Descriptor instead.
 	if size == 0 {
@@ -396,6 +499,7 @@ func (t *transfer) copyBlobByMonolithic(srcRepo, dstRepo, digest string, sizeFro
 		t.logger.Errorf("failed to pushing the blob %s, size %d: %v", digest, size, err)
 		return err
 	}
+	t.recordProgress(1, size)
 
 	return nil
 }
@@ -438,6 +542,7 @@ func (t *transfer) copyBlobByChunk(srcRepo, dstRepo, digest string, sizeFromDesc
 		if speed > 0 {
 			data = lib.NewReader(data, speed)
 		}
+		data = trans.Throttle(data, t.registries...)
 		// failureEnd will only be used for adjusting content range when issue happened during push the chunk.
 		var failureEnd int64
 		*location, failureEnd, err = t.dst.PushBlobChunk(dstRepo, digest, sizeFromDescriptor, data, *start, *end, *location)
@@ -453,13 +558,25 @@ func (t *transfer) copyBlobByChunk(srcRepo, dstRepo, digest string, sizeFromDesc
 		t.logger.Infof("copy the blob chunk: %d-%d/%d completed", *start, *end, sizeFromDescriptor)
 		// if the end equals (blobSize-1), that means it is last chunk, return if this is the last chunk
 		if *end == endRange {
+			t.recordProgress(1, *end-*start+1)
 			break
 		}
+		t.recordProgress(0, *end-*start+1)
 	}
 
 	return nil
 }
 
+// recordProgress records the transferred blobs and bytes and reports the progress
+func (t *transfer) recordProgress(blobs, bytes int64) {
+	t.progress.CopiedBlobs += blobs
+	t.progress.CopiedBytes += bytes
+	if t.report != nil {
+		progress := t.progress
+		t.report(&progress)
+	}
+}
+
 func (t *transfer) pullManifest(repository, reference string) (
 	distribution.Manifest, string, error) {
 	if t.shouldStop() {
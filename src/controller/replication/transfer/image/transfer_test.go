@@ -17,11 +17,13 @@ package image
 import (
 	"bytes"
 	"io"
+	"strings"
 	"testing"
 
 	"github.com/docker/distribution"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -113,6 +115,61 @@ func (f *fakeRegistry) ListTags(repository string) (tags []string, err error) {
 	return nil, nil
 }
 
+const (
+	subjectDigest  = "sha256:c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7"
+	referrerDigest = "sha256:d7c3c3d618b1055459f1414225e9e04bbbb192843c97562e0cdf2f543b648cd8"
+)
+
+// referrerRegistry supports the referrers API
+type referrerRegistry struct {
+	fakeRegistry
+	pushed []string
+}
+
+func (f *referrerRegistry) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	return false, nil, nil
+}
+
+func (f *referrerRegistry) PullManifest(repository, reference string, accepttedMediaTypes ...string) (distribution.Manifest, string, error) {
+	mani, _, err := f.fakeRegistry.PullManifest(repository, reference, accepttedMediaTypes...)
+	if reference == referrerDigest {
+		return mani, referrerDigest, err
+	}
+	return mani, subjectDigest, err
+}
+
+func (f *referrerRegistry) PushManifest(repository, reference, mediaType string, payload []byte) (string, error) {
+	f.pushed = append(f.pushed, reference)
+	return "", nil
+}
+
+func (f *referrerRegistry) ListReferrers(repository, dgt string) ([]v1.Descriptor, error) {
+	if dgt == subjectDigest {
+		return []v1.Descriptor{{Digest: digest.Digest(referrerDigest)}}, nil
+	}
+	return nil, nil
+}
+
+// tagSchemeRegistry attaches the referrers with the tag scheme
+type tagSchemeRegistry struct {
+	fakeRegistry
+}
+
+func (f *tagSchemeRegistry) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	if repository == "source" && reference == "sha256-c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7.sig" {
+		return true, &distribution.Descriptor{Digest: digest.Digest(referrerDigest)}, nil
+	}
+	return false, nil, nil
+}
+
+func (f *tagSchemeRegistry) PullManifest(repository, reference string, accepttedMediaTypes ...string) (distribution.Manifest, string, error) {
+	mani, _, err := f.fakeRegistry.PullManifest(repository, reference, accepttedMediaTypes...)
+	if strings.HasSuffix(reference, ".sig") {
+		return mani, referrerDigest, err
+	}
+	return mani, subjectDigest, err
+}
+
 func TestFactory(t *testing.T) {
 	tr, err := factory(nil, nil)
 	require.Nil(t, err)
@@ -154,8 +211,14 @@ func TestCopy(t *testing.T) {
 		repository: "destination",
 		tags:       []string{"b1", "b2"},
 	}
-	err := tr.copy(src, dst, true, trans.NewOptions())
+	var progress *trans.Progress
+	err := tr.copy(src, dst, true, trans.NewOptions(trans.WithProgress(func(p *trans.Progress) {
+		progress = p
+	})))
 	require.Nil(t, err)
+	require.NotNil(t, progress)
+	assert.Equal(t, int64(4), progress.CopiedBlobs)
+	assert.Equal(t, int64(4), progress.CopiedBytes)
 }
 
 func TestCopyByChunk(t *testing.T) {
@@ -179,6 +242,40 @@ func TestCopyByChunk(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestCopyReferrers(t *testing.T) {
+	stopFunc := func() bool { return false }
+	dst := &referrerRegistry{}
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: stopFunc,
+		src:       &referrerRegistry{},
+		dst:       dst,
+	}
+	err := tr.copyArtifact("source", "v1", "destination", "v1", true, trans.NewOptions())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"v1", referrerDigest}, dst.pushed)
+
+	// the accessories are required before the tag is pushed
+	dst.pushed = nil
+	err = tr.copyArtifact("source", "v1", "destination", "v1", true, trans.NewOptions(trans.WithRequireAccessories(true)))
+	require.Nil(t, err)
+	assert.Equal(t, []string{subjectDigest, referrerDigest, "v1"}, dst.pushed)
+}
+
+func TestCopyReferrersWithTagScheme(t *testing.T) {
+	stopFunc := func() bool { return false }
+	dst := &referrerRegistry{}
+	tr := &transfer{
+		logger:    log.DefaultLogger(),
+		isStopped: stopFunc,
+		src:       &tagSchemeRegistry{},
+		dst:       dst,
+	}
+	err := tr.copyArtifact("source", "v1", "destination", "v1", true, trans.NewOptions())
+	require.Nil(t, err)
+	assert.Equal(t, []string{"v1", "sha256-c6b2b2c507a0944348e0303114d8d93aaaa081732b86451d9bce1f432a537bc7.sig"}, dst.pushed)
+}
+
 func TestDelete(t *testing.T) {
 	stopFunc := func() bool { return false }
 	tr := &transfer{
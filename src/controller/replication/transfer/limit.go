@@ -0,0 +1,252 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transfer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	libredis "github.com/goharbor/harbor/src/lib/redis"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+// ErrNoSlot is returned when the max concurrent tasks of the registry is reached
+var ErrNoSlot = errors.New("the max concurrent tasks of the registry is reached")
+
+// the token bucket of the registry is shared by the replication tasks running in the same jobservice process,
+// its rate is the share of the speed of the registry in proportion to the slots occupied by the process, so the
+// tasks of all the jobservice instances transferring with the registry don't exceed the speed in total
+var (
+	limitLock sync.Mutex
+	// the token buckets of the registries, keyed by the registry ID
+	limiters = map[int64]*rate.Limiter{}
+	// the count of the slots occupied by the process, keyed by the registry ID
+	localSlots = map[int64]int{}
+)
+
+// the slots of the registries are shared by all the jobservice instances via the redis, each slot is
+// a member of the sorted set of the registry scored by its expiration, the slot of the crashed task
+// is released once it expires. The slots are occupied by the tasks of the registries either limiting the
+// max concurrent tasks or the speed, the max of the slots is unlimited for the latter
+var (
+	// slotTTL is the expiration of the slot, it is renewed periodically until the slot is released
+	slotTTL = 2 * time.Minute
+
+	acquireScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+  return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[4])
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+return 1
+`)
+
+	// acquireSlot occupies a slot of the registry for the holder if the count of the unexpired slots is less
+	// than the max or the max is 0, and returns whether the slot is occupied
+	acquireSlot = func(ctx context.Context, registryID int64, holder string, max int, ttl time.Duration) (bool, error) {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return false, err
+		}
+		now := time.Now()
+		return acquireScript.Run(ctx, client, []string{slotKey(registryID)}, now.UnixMilli(), max,
+			now.Add(ttl).UnixMilli(), holder, ttl.Milliseconds()).Bool()
+	}
+	// renewSlot extends the expiration of the slot occupied by the holder
+	renewSlot = func(ctx context.Context, registryID int64, holder string, ttl time.Duration) error {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return err
+		}
+		key := slotKey(registryID)
+		_, err = client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZAddXX(ctx, key, &redis.Z{Score: float64(time.Now().Add(ttl).UnixMilli()), Member: holder})
+			pipe.PExpire(ctx, key, ttl)
+			return nil
+		})
+		return err
+	}
+	// countSlots returns the count of the unexpired slots of the registry occupied by all the jobservice instances
+	countSlots = func(ctx context.Context, registryID int64) (int64, error) {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return 0, err
+		}
+		return client.ZCount(ctx, slotKey(registryID), fmt.Sprintf("%d", time.Now().UnixMilli()), "+inf").Result()
+	}
+	// releaseSlot releases the slot occupied by the holder
+	releaseSlot = func(ctx context.Context, registryID int64, holder string) error {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return err
+		}
+		return client.ZRem(ctx, slotKey(registryID), holder).Err()
+	}
+)
+
+func slotKey(registryID int64) string {
+	return fmt.Sprintf("replication:registry:%d:slots", registryID)
+}
+
+// Throttle limits the reader by the token buckets of the registries, the rate of the bucket is updated
+// according to the speed and the bandwidth windows of the registry and the slots occupied by this process
+// each time the function is called. The local Harbor (ID is 0) isn't limited
+func Throttle(reader io.ReadCloser, registries ...*model.Registry) io.ReadCloser {
+	now := time.Now()
+	for _, registry := range registries {
+		if registry == nil || registry.ID == 0 {
+			continue
+		}
+		limit := rate.Inf
+		if speed := model.SpeedAt(registry.BandwidthWindows, registry.Speed, now); speed > 0 {
+			limit = rate.Limit(float64(speed*lib.KBRATE) * speedShare(registry.ID))
+		}
+		limitLock.Lock()
+		limiter, exist := limiters[registry.ID]
+		if !exist {
+			if limit == rate.Inf {
+				limitLock.Unlock()
+				continue
+			}
+			limiter = rate.NewLimiter(limit, 1000*1024)
+			limiters[registry.ID] = limiter
+		}
+		limitLock.Unlock()
+		limiter.SetLimitAt(now, limit)
+		reader = lib.NewReaderWithLimiter(reader, limiter)
+	}
+	return reader
+}
+
+// speedShare returns the share of the speed of the registry for this process, which is the ratio of the slots
+// occupied by this process to the ones occupied by all the jobservice instances
+func speedShare(registryID int64) float64 {
+	limitLock.Lock()
+	local := localSlots[registryID]
+	limitLock.Unlock()
+	// the transfer not occupying the slot still takes a share
+	if local == 0 {
+		local = 1
+	}
+	total, err := countSlots(context.Background(), registryID)
+	if err != nil {
+		log.Warningf("failed to count the slots of the registry %d, the speed isn't shared: %v", registryID, err)
+		return 1
+	}
+	if total < int64(local) {
+		total = int64(local)
+	}
+	return float64(local) / float64(total)
+}
+
+// throttled returns whether the speed of the registry is limited
+func throttled(registry *model.Registry) bool {
+	return registry.Speed > 0 || len(registry.BandwidthWindows) > 0
+}
+
+// AcquireSlots occupies a slot of each registry if the count of its running tasks is less than its max
+// concurrent tasks, the slot is also occupied without the max if the speed of the registry is limited. It doesn't wait for the slots: ErrNoSlot is returned if any registry has no free slot,
+// and the slots acquired already are released. The registries are acquired in the order of ID. The returned
+// function must be called to release the slots when the task is done, the slots are renewed until then
+func AcquireSlots(ctx context.Context, registries ...*model.Registry) (func(), error) {
+	var limited []*model.Registry
+	for _, registry := range registries {
+		if registry == nil || registry.ID == 0 || (registry.MaxConcurrentTasks <= 0 && !throttled(registry)) {
+			continue
+		}
+		limited = append(limited, registry)
+	}
+	sort.Slice(limited, func(i, j int) bool {
+		return limited[i].ID < limited[j].ID
+	})
+
+	holder, ttl := uuid.New().String(), slotTTL
+	var acquired []int64
+	release := func() {
+		limitLock.Lock()
+		for _, id := range acquired {
+			if localSlots[id]--; localSlots[id] <= 0 {
+				delete(localSlots, id)
+			}
+		}
+		limitLock.Unlock()
+		for _, id := range acquired {
+			// use a new context as the task context may be canceled already
+			if err := releaseSlot(context.Background(), id, holder); err != nil {
+				log.Errorf("failed to release the slot of the registry %d: %v", id, err)
+			}
+		}
+	}
+	for i, registry := range limited {
+		// the same registry is both source and destination
+		if i > 0 && limited[i-1].ID == registry.ID {
+			continue
+		}
+		ok, err := acquireSlot(ctx, registry.ID, holder, max(registry.MaxConcurrentTasks, 0), ttl)
+		if err != nil {
+			release()
+			return nil, errors.Wrapf(err, "failed to acquire the slot of the registry %d", registry.ID)
+		}
+		if !ok {
+			release()
+			return nil, errors.Wrapf(ErrNoSlot, "registry %d", registry.ID)
+		}
+		acquired = append(acquired, registry.ID)
+		limitLock.Lock()
+		localSlots[registry.ID]++
+		limitLock.Unlock()
+	}
+	if len(acquired) == 0 {
+		return func() {}, nil
+	}
+
+	// renew the slots periodically until they are released
+	done, renew := make(chan struct{}), renewSlot
+	go func() {
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				for _, id := range acquired {
+					if err := renew(context.Background(), id, holder, ttl); err != nil {
+						log.Warningf("failed to renew the slot of the registry %d: %v", id, err)
+					}
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			release()
+		})
+	}, nil
+}
@@ -0,0 +1,162 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestThrottle(t *testing.T) {
+	count := countSlots
+	defer func() {
+		countSlots = count
+	}()
+	total := int64(0)
+	countSlots = func(_ context.Context, _ int64) (int64, error) {
+		return total, nil
+	}
+
+	reader := io.NopCloser(strings.NewReader("data"))
+	// the local Harbor and the unlimited registries aren't throttled
+	assert.Equal(t, reader, Throttle(reader, nil, &model.Registry{ID: 0, Speed: 1}, &model.Registry{ID: 100}))
+
+	registry := &model.Registry{ID: 101, Speed: 1024}
+	throttled := Throttle(reader, registry)
+	assert.NotEqual(t, reader, throttled)
+	data, err := io.ReadAll(throttled)
+	require.Nil(t, err)
+	assert.Equal(t, "data", string(data))
+
+	// the same token bucket is shared and updated by the bandwidth windows
+	limiter := limiters[registry.ID]
+	registry.BandwidthWindows = []*model.BandwidthWindow{{Start: "00:00", End: "23:59", Speed: 2048}}
+	Throttle(reader, registry)
+	assert.Equal(t, limiter, limiters[registry.ID])
+	assert.InDelta(t, float64(2048*128), float64(limiter.Limit()), 1)
+
+	// the speed is split by the slots occupied by all the jobservice instances
+	limitLock.Lock()
+	localSlots[registry.ID] = 1
+	limitLock.Unlock()
+	defer func() {
+		limitLock.Lock()
+		delete(localSlots, registry.ID)
+		limitLock.Unlock()
+	}()
+	total = 4
+	Throttle(reader, registry)
+	assert.InDelta(t, float64(2048*128/4), float64(limiter.Limit()), 1)
+}
+
+func TestAcquireSlots(t *testing.T) {
+	acquire, renew, rel, ttl := acquireSlot, renewSlot, releaseSlot, slotTTL
+	defer func() {
+		acquireSlot, renewSlot, releaseSlot, slotTTL = acquire, renew, rel, ttl
+	}()
+	// fake the slots stored in the redis
+	var lock sync.Mutex
+	slots := map[int64]map[string]bool{}
+	renewed := map[int64]int{}
+	acquireSlot = func(_ context.Context, registryID int64, holder string, max int, _ time.Duration) (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if max > 0 && len(slots[registryID]) >= max {
+			return false, nil
+		}
+		if slots[registryID] == nil {
+			slots[registryID] = map[string]bool{}
+		}
+		slots[registryID][holder] = true
+		return true, nil
+	}
+	renewSlot = func(_ context.Context, registryID int64, _ string, _ time.Duration) error {
+		lock.Lock()
+		defer lock.Unlock()
+		renewed[registryID]++
+		return nil
+	}
+	releaseSlot = func(_ context.Context, registryID int64, holder string) error {
+		lock.Lock()
+		defer lock.Unlock()
+		delete(slots[registryID], holder)
+		return nil
+	}
+	slotTTL = 30 * time.Millisecond
+
+	// the local Harbor and the unlimited registries don't occupy slots
+	release, err := AcquireSlots(context.Background(), nil, &model.Registry{ID: 0, MaxConcurrentTasks: 1}, &model.Registry{ID: 200})
+	require.Nil(t, err)
+	release()
+	assert.Empty(t, slots)
+
+	src := &model.Registry{ID: 201, MaxConcurrentTasks: 2}
+	dst := &model.Registry{ID: 202, MaxConcurrentTasks: 1}
+	release1, err := AcquireSlots(context.Background(), src, dst)
+	require.Nil(t, err)
+	assert.Len(t, slots[src.ID], 1)
+	assert.Len(t, slots[dst.ID], 1)
+
+	// the slot of the destination is occupied, fail fast and release the slot of the source
+	_, err = AcquireSlots(context.Background(), dst, src)
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrNoSlot))
+	assert.Len(t, slots[src.ID], 1)
+
+	// the slots are renewed until released
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return renewed[src.ID] > 0 && renewed[dst.ID] > 0
+	}, time.Second, 10*time.Millisecond)
+	release1()
+	// releasing twice is harmless
+	release1()
+	assert.Empty(t, slots[src.ID])
+	assert.Empty(t, slots[dst.ID])
+
+	// the same registry as both source and destination occupies only one slot
+	release2, err := AcquireSlots(context.Background(), dst, dst)
+	require.Nil(t, err)
+	assert.Len(t, slots[dst.ID], 1)
+	release2()
+
+	// the registry limiting the speed occupies the slots without the max to share the speed
+	throttled := &model.Registry{ID: 203, Speed: 1024}
+	release3, err := AcquireSlots(context.Background(), throttled)
+	require.Nil(t, err)
+	release4, err := AcquireSlots(context.Background(), throttled)
+	require.Nil(t, err)
+	assert.Len(t, slots[throttled.ID], 2)
+	limitLock.Lock()
+	assert.Equal(t, 2, localSlots[throttled.ID])
+	limitLock.Unlock()
+	release3()
+	release4()
+	assert.Empty(t, slots[throttled.ID])
+	limitLock.Lock()
+	assert.NotContains(t, localSlots, throttled.ID)
+	limitLock.Unlock()
+}
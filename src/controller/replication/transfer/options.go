@@ -14,13 +14,26 @@
 
 package transfer
 
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
 type Option func(*Options)
 
 type Options struct {
 	// Speed is the data transfer speed for replication, no limit by default.
 	Speed int32
+	// BandwidthWindows overrides the Speed during the time ranges of the day.
+	BandwidthWindows []*model.BandwidthWindow
 	// CopyByChunk defines whether need to copy the artifact blob by chunk, copy by whole blob by default.
 	CopyByChunk bool
+	// RequireAccessories defines whether the accessories(signatures, SBOMs, etc.) of the artifact must be copied
+	// before the tag of the artifact is pushed, the tag is pushed first and the failures of the accessories are ignored by default.
+	RequireAccessories bool
+	// Progress is called when the blob data is transferred to report the progress, no report by default.
+	Progress ProgressFunc
 }
 
 func NewOptions(opts ...Option) *Options {
@@ -38,8 +51,31 @@ func WithSpeed(speed int32) Option {
 	}
 }
 
+func WithBandwidthWindows(windows []*model.BandwidthWindow) Option {
+	return func(o *Options) {
+		o.BandwidthWindows = windows
+	}
+}
+
+// SpeedAt returns the speed at the time according to the bandwidth windows
+func (o *Options) SpeedAt(t time.Time) int32 {
+	return model.SpeedAt(o.BandwidthWindows, o.Speed, t)
+}
+
 func WithCopyByChunk(copyByChunk bool) Option {
 	return func(o *Options) {
 		o.CopyByChunk = copyByChunk
 	}
 }
+
+func WithRequireAccessories(requireAccessories bool) Option {
+	return func(o *Options) {
+		o.RequireAccessories = requireAccessories
+	}
+}
+
+func WithProgress(progress ProgressFunc) Option {
+	return func(o *Options) {
+		o.Progress = progress
+	}
+}
@@ -16,8 +16,11 @@ package transfer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/goharbor/harbor/src/pkg/reg/model"
 )
 
 func TestNewOptions(t *testing.T) {
@@ -35,3 +38,11 @@ func TestNewOptions(t *testing.T) {
 	assert.Equal(t, int32(1024), o.Speed)
 	assert.Equal(t, true, o.CopyByChunk)
 }
+
+func TestSpeedAt(t *testing.T) {
+	o := NewOptions(WithSpeed(1024), WithBandwidthWindows([]*model.BandwidthWindow{
+		{Start: "01:00", End: "05:00", Speed: 0},
+	}))
+	assert.Equal(t, int32(0), o.SpeedAt(time.Date(2024, 1, 1, 2, 0, 0, 0, time.Local)))
+	assert.Equal(t, int32(1024), o.SpeedAt(time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)))
+}
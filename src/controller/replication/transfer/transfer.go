@@ -61,6 +61,19 @@ type Logger interface {
 // process is stopped
 type StopFunc func() bool
 
+// Progress is the progress of the transfer process
+type Progress struct {
+	// CopiedBlobs is the count of the blobs transferred to the destination registry,
+	// the blobs already existing on or mounted by the destination registry aren't included
+	CopiedBlobs int64 `json:"copied_blobs"`
+	// CopiedBytes is the size of the data transferred to the destination registry
+	CopiedBytes int64 `json:"copied_bytes"`
+}
+
+// ProgressFunc is a function used to report the progress
+// of the transfer process
+type ProgressFunc func(*Progress)
+
 // RegisterFactory registers one transfer factory to the registry
 func RegisterFactory(name string, factory Factory) error {
 	if len(name) == 0 {
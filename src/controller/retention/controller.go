@@ -16,22 +16,30 @@ package retention
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/goharbor/harbor/src/common/secret"
+	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/event/operator"
+	"github.com/goharbor/harbor/src/controller/scan"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/lib/retry"
+	"github.com/goharbor/harbor/src/lib/selector"
 	"github.com/goharbor/harbor/src/pkg"
+	immumodel "github.com/goharbor/harbor/src/pkg/immutable/model"
 	"github.com/goharbor/harbor/src/pkg/project"
 	"github.com/goharbor/harbor/src/pkg/repository"
 	"github.com/goharbor/harbor/src/pkg/retention"
+	"github.com/goharbor/harbor/src/pkg/retention/dep"
 	"github.com/goharbor/harbor/src/pkg/retention/policy"
+	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
 	"github.com/goharbor/harbor/src/pkg/scheduler"
 	"github.com/goharbor/harbor/src/pkg/task"
 )
@@ -65,6 +73,10 @@ type Controller interface {
 	GetRetentionExecTask(ctx context.Context, taskID int64) (*retention.Task, error)
 	// DeleteRetentionByProject delete retetion rule by project id
 	DeleteRetentionByProject(ctx context.Context, projectID int64) error
+	// SimulateRetention simulates the retention policy against the current artifacts without launching any job
+	SimulateRetention(ctx context.Context, p *policy.Metadata, query *q.Query) (int64, []*retention.SimulationReport, error)
+	// SimulateImmutable simulates the immutable rules of the project against the current artifacts
+	SimulateImmutable(ctx context.Context, projectID int64, rules []*immumodel.Metadata, query *q.Query) (int64, []*retention.SimulationReport, error)
 }
 
 var (
@@ -81,6 +93,7 @@ type defaultController struct {
 	projectManager project.Manager
 	repositoryMgr  repository.Manager
 	scheduler      scheduler.Scheduler
+	simulator      retention.Simulator
 	wp             *lib.WorkerPool
 }
 
@@ -448,6 +461,51 @@ func (r *defaultController) DeleteRetentionByProject(ctx context.Context, projec
 	return nil
 }
 
+// SimulateRetention simulates the retention policy
+func (r *defaultController) SimulateRetention(ctx context.Context, p *policy.Metadata, query *q.Query) (int64, []*retention.SimulationReport, error) {
+	return r.simulator.SimulateRetention(ctx, p, query)
+}
+
+// SimulateImmutable simulates the immutable rules of the project
+func (r *defaultController) SimulateImmutable(ctx context.Context, projectID int64, rules []*immumodel.Metadata, query *q.Query) (int64, []*retention.SimulationReport, error) {
+	return r.simulator.SimulateImmutable(ctx, projectID, rules, query)
+}
+
+// loadCandidates loads the retention candidates of the repository from the artifacts in the database
+func loadCandidates(ctx context.Context, repository *selector.Repository) ([]*selector.Candidate, error) {
+	if repository.Kind != selector.Image {
+		return nil, fmt.Errorf("unsupported repository kind: %s", repository.Kind)
+	}
+	artifacts, err := artifact.Ctl.List(ctx, q.New(q.KeyWords{
+		"RepositoryName": fmt.Sprintf("%s/%s", repository.Namespace, repository.Name),
+		"base":           "*",
+	}), &artifact.Option{
+		WithTag:       true,
+		WithLabel:     true,
+		WithAccessory: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, art := range artifacts {
+		overview, err := scan.DefaultController.GetSummary(ctx, art, v1.ScanTypeVulnerability,
+			[]string{v1.MimeTypeNativeReport, v1.MimeTypeGenericVulnerabilityReport})
+		if err != nil {
+			log.Warningf("failed to get the scan summary of artifact %s@%s: %v", art.RepositoryName, art.Digest, err)
+			continue
+		}
+		// normalize the summaries as the ones returned by the API
+		data, err := json.Marshal(overview)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, &art.ScanOverview); err != nil {
+			return nil, err
+		}
+	}
+	return dep.ToCandidates(repository, artifacts)
+}
+
 // NewController ...
 func NewController() Controller {
 	retentionMgr := retention.NewManager()
@@ -460,6 +518,7 @@ func NewController() Controller {
 		projectManager: pkg.ProjectMgr,
 		repositoryMgr:  pkg.RepositoryMgr,
 		scheduler:      scheduler.Sched,
+		simulator:      retention.NewSimulator(pkg.ProjectMgr, pkg.RepositoryMgr, loadCandidates),
 		wp:             lib.NewWorkerPool(10),
 	}
 }
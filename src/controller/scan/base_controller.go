@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +28,7 @@ import (
 
 	ar "github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/event/operator"
+	"github.com/goharbor/harbor/src/controller/project"
 	"github.com/goharbor/harbor/src/controller/robot"
 	sc "github.com/goharbor/harbor/src/controller/scanner"
 	"github.com/goharbor/harbor/src/controller/tag"
@@ -39,6 +41,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/lib/retry"
 	"github.com/goharbor/harbor/src/pkg/accessory"
+	accessoryModel "github.com/goharbor/harbor/src/pkg/accessory/model"
 	allowlist "github.com/goharbor/harbor/src/pkg/allowlist/models"
 	"github.com/goharbor/harbor/src/pkg/permission/types"
 	"github.com/goharbor/harbor/src/pkg/robot/model"
@@ -50,6 +53,7 @@ import (
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
 	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/goharbor/harbor/src/pkg/task"
+	"github.com/goharbor/harbor/src/pkg/vex"
 )
 
 var (
@@ -64,8 +68,9 @@ const (
 	configRegistryEndpoint = "registryEndpoint"
 	configCoreInternalAddr = "coreInternalAddr"
 
-	artfiactKey     = "artifact"
-	registrationKey = "registration"
+	artfiactKey      = "artifact"
+	registrationKey  = "registration"
+	registrationsKey = "registrations"
 
 	artifactIDKey       = "artifact_id"
 	artifactTagKey      = "artifact_tag"
@@ -102,6 +107,8 @@ type basicController struct {
 	ar ar.Controller
 	// Accessory manager
 	acc accessory.Manager
+	// Project controller
+	proCtl project.Controller
 	// Scanner controller
 	sc sc.Controller
 	// Robot account controller
@@ -122,6 +129,8 @@ type basicController struct {
 	reportConverter postprocessors.NativeScanReportConverter
 	// cache stores the stop scan all marks
 	cache cacheGetter
+	// VEX statement manager
+	vexMgr vex.Manager
 }
 
 // NewController news a scan API controller
@@ -133,6 +142,8 @@ func NewController() Controller {
 		ar: ar.Ctl,
 		// Refer to the default accessory manager
 		acc: accessory.Mgr,
+		// Refer to the default project controller
+		proCtl: project.Ctl,
 		// Refer to the default scanner controller
 		sc: sc.DefaultController,
 		// Refer to the default robot account controller
@@ -170,6 +181,8 @@ func NewController() Controller {
 		cache: func() cache.Cache {
 			return cache.Default()
 		},
+		// Refer to the default VEX statement manager
+		vexMgr: vex.Mgr,
 	}
 }
 
@@ -236,81 +249,83 @@ func (bc *basicController) Scan(ctx context.Context, artifact *ar.Artifact, opti
 		return errors.New("nil artifact to scan")
 	}
 
-	r, err := bc.sc.GetRegistrationByProject(ctx, artifact.ProjectID)
-	if err != nil {
-		return errors.Wrap(err, "scan controller: scan")
-	}
-
-	// In case it does not exist
-	if r == nil {
-		return errors.PreconditionFailedError(nil).WithMessagef("no available scanner for project: %d", artifact.ProjectID)
-	}
-
-	// Check if it is disabled
-	if r.Disabled {
-		return errors.PreconditionFailedError(nil).WithMessagef("scanner %s is deactivated", r.Name)
-	}
-
-	artifacts, scannable, err := bc.collectScanningArtifacts(ctx, r, artifact)
-	if err != nil {
-		return err
-	}
 	// Parse options
 	opts, err := parseOptions(options...)
 	if err != nil {
 		return errors.Wrap(err, "scan controller: scan")
 	}
 
-	if !scannable {
-		if opts.FromEvent {
-			// skip to return err for event related scan
-			return nil
-		}
-		return errors.BadRequestError(nil).WithMessagef("the configured scanner %s does not support scanning artifact with mime type %s", r.Name, artifact.ManifestMediaType)
+	registrations, err := bc.getScanRegistrations(ctx, artifact.ProjectID, opts.GetScanType())
+	if err != nil {
+		return err
 	}
 
 	var (
 		errs                []error
 		launchScanJobParams []*launchScanJobParam
+		placeholders        int
+		scanned             []*scanner.Registration
 	)
 	handler := sca.GetScanHandler(opts.GetScanType())
-	for _, art := range artifacts {
-		reports, err := handler.MakePlaceHolder(ctx, art, r)
+	for _, r := range registrations {
+		artifacts, scannable, err := bc.collectScanningArtifacts(ctx, r, artifact)
 		if err != nil {
-			if errors.IsConflictErr(err) {
-				errs = append(errs, err)
-			} else {
-				return err
-			}
+			return err
 		}
 
-		var tag string
-		if art.Digest == artifact.Digest {
-			tag = opts.Tag
+		if !scannable {
+			// skip the scanner which doesn't support the artifact, the others may support it
+			continue
 		}
+		scanned = append(scanned, r)
 
-		if tag == "" {
-			latestTag, err := bc.getLatestTagOfArtifact(ctx, art.ID)
+		for _, art := range artifacts {
+			placeholders++
+			reports, err := handler.MakePlaceHolder(ctx, art, r)
 			if err != nil {
-				return err
+				if errors.IsConflictErr(err) {
+					errs = append(errs, err)
+				} else {
+					return err
+				}
+			}
+
+			var tag string
+			if art.Digest == artifact.Digest {
+				tag = opts.Tag
+			}
+
+			if tag == "" {
+				latestTag, err := bc.getLatestTagOfArtifact(ctx, art.ID)
+				if err != nil {
+					return err
+				}
+
+				tag = latestTag
 			}
 
-			tag = latestTag
+			if len(reports) > 0 {
+				launchScanJobParams = append(launchScanJobParams, &launchScanJobParam{
+					Registration: r,
+					Artifact:     art,
+					Tag:          tag,
+					Reports:      reports,
+					Type:         opts.GetScanType(),
+				})
+			}
 		}
+	}
 
-		if len(reports) > 0 {
-			launchScanJobParams = append(launchScanJobParams, &launchScanJobParam{
-				Registration: r,
-				Artifact:     art,
-				Tag:          tag,
-				Reports:      reports,
-				Type:         opts.GetScanType(),
-			})
+	if len(scanned) == 0 {
+		if opts.FromEvent {
+			// skip to return err for event related scan
+			return nil
 		}
+		return errors.BadRequestError(nil).WithMessagef("the configured scanner %s does not support scanning artifact with mime type %s", registrationNames(registrations), artifact.ManifestMediaType)
 	}
 
 	// all report placeholder conflicted
-	if len(errs) == len(artifacts) {
+	if len(errs) == placeholders {
 		return errs[0]
 	}
 
@@ -323,13 +338,23 @@ func (bc *basicController) Scan(ctx context.Context, artifact *ar.Artifact, opti
 				"digest":          artifact.Digest,
 			},
 			registrationKey: map[string]interface{}{
-				"id":   r.ID,
-				"name": r.Name,
+				"id":   scanned[0].ID,
+				"name": scanned[0].Name,
 			},
 			enabledCapabilities: map[string]interface{}{
 				"type": opts.GetScanType(),
 			},
 		}
+		if len(scanned) > 1 {
+			regs := make([]interface{}, len(scanned))
+			for i, r := range scanned {
+				regs[i] = map[string]interface{}{
+					"id":   r.ID,
+					"name": r.Name,
+				}
+			}
+			extraAttrs[registrationsKey] = regs
+		}
 		if op := operator.FromContext(ctx); op != "" {
 			extraAttrs["operator"] = op
 		}
@@ -363,6 +388,46 @@ func (bc *basicController) Scan(ctx context.Context, artifact *ar.Artifact, opti
 	return nil
 }
 
+// getScanRegistrations returns the enabled scanner registrations of the project to scan the artifacts,
+// the vulnerability scan fans out to all the scanners of the project while the others use the primary scanner only
+func (bc *basicController) getScanRegistrations(ctx context.Context, projectID int64, scanType string) ([]*scanner.Registration, error) {
+	registrations, err := bc.sc.GetRegistrationsByProject(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "scan controller: scan")
+	}
+
+	// In case it does not exist
+	if len(registrations) == 0 {
+		return nil, errors.PreconditionFailedError(nil).WithMessagef("no available scanner for project: %d", projectID)
+	}
+
+	if scanType != v1.ScanTypeVulnerability {
+		registrations = registrations[:1]
+	}
+
+	var enabled []*scanner.Registration
+	for _, r := range registrations {
+		if !r.Disabled {
+			enabled = append(enabled, r)
+		}
+	}
+
+	// Check if all of them are disabled
+	if len(enabled) == 0 {
+		return nil, errors.PreconditionFailedError(nil).WithMessagef("scanner %s is deactivated", registrationNames(registrations))
+	}
+
+	return enabled, nil
+}
+
+func registrationNames(registrations []*scanner.Registration) string {
+	names := make([]string, len(registrations))
+	for i, r := range registrations {
+		names[i] = r.Name
+	}
+	return strings.Join(names, ", ")
+}
+
 // Stop scan job of a given artifact
 func (bc *basicController) Stop(ctx context.Context, artifact *ar.Artifact, capType string) error {
 	if artifact == nil {
@@ -459,6 +524,17 @@ func (bc *basicController) isScanAllStopped(ctx context.Context, execID int64) b
 func (bc *basicController) startScanAll(ctx context.Context, executionID int64) error {
 	batchSize := 50
 
+	// with cancel function to signal downstream worker
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	_, err := bc.scanArtifacts(ctx, executionID, ar.Iterator(ctx, batchSize, nil, nil))
+	return err
+}
+
+// scanArtifacts scans the artifacts with the execution, records the summary in the execution and
+// returns the count of the artifacts submitted to the job service
+func (bc *basicController) scanArtifacts(ctx context.Context, executionID int64, artifacts <-chan *ar.Artifact) (int, error) {
 	summary := struct {
 		TotalCount        int `json:"total_count"`
 		SubmitCount       int `json:"submit_count"`
@@ -467,13 +543,10 @@ func (bc *basicController) startScanAll(ctx context.Context, executionID int64)
 		UnsupportCount    int `json:"unsupport_count"`
 		UnknowCount       int `json:"unknow_count"`
 	}{}
-	// with cancel function to signal downstream worker
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
 
-	for artifact := range ar.Iterator(ctx, batchSize, nil, nil) {
+	for artifact := range artifacts {
 		if bc.isScanAllStopped(ctx, executionID) {
-			return errScanAllStopped
+			return summary.SubmitCount, errScanAllStopped
 		}
 
 		summary.TotalCount++
@@ -506,7 +579,7 @@ func (bc *basicController) startScanAll(ctx context.Context, executionID int64)
 
 	exec, err := bc.execMgr.Get(ctx, executionID)
 	if err != nil {
-		return err
+		return summary.SubmitCount, err
 	}
 
 	extraAttrs := exec.ExtraAttrs
@@ -518,18 +591,18 @@ func (bc *basicController) startScanAll(ctx context.Context, executionID int64)
 
 	if err := bc.execMgr.UpdateExtraAttrs(ctx, executionID, extraAttrs); err != nil {
 		log.Errorf("failed to set the summary info for the scan all execution, error: %v", err)
-		return err
+		return summary.SubmitCount, err
 	}
 
 	if summary.SubmitCount > 0 { // at least one artifact submitted to the job service
-		return nil
+		return summary.SubmitCount, nil
 	}
 
 	// not artifact found
 	if summary.TotalCount == 0 {
 		if err := bc.execMgr.MarkDone(ctx, executionID, "no artifact found"); err != nil {
 			log.Errorf("failed to mark the execution %d to be done, error: %v", executionID, err)
-			return err
+			return 0, err
 		}
 	} else if summary.PreconditionCount+summary.UnknowCount == 0 { // not scan job submitted and no failed
 		message := fmt.Sprintf("%d artifact(s) found", summary.TotalCount)
@@ -546,7 +619,7 @@ func (bc *basicController) startScanAll(ctx context.Context, executionID int64)
 
 		if err := bc.execMgr.MarkDone(ctx, executionID, message); err != nil {
 			log.Errorf("failed to mark the execution %d to be done, error: %v", executionID, err)
-			return err
+			return 0, err
 		}
 	} else { // not scan job submitted and failed
 		message := fmt.Sprintf("%d artifact(s) found", summary.TotalCount)
@@ -562,11 +635,11 @@ func (bc *basicController) startScanAll(ctx context.Context, executionID int64)
 		message = fmt.Sprintf("%s, but no scan job submitted to the job service", message)
 		if err := bc.execMgr.MarkError(ctx, executionID, message); err != nil {
 			log.Errorf("failed to mark the execution %d to be error, error: %v", executionID, err)
-			return err
+			return 0, err
 		}
 	}
 
-	return nil
+	return 0, nil
 }
 
 // GetReport ...
@@ -575,24 +648,69 @@ func (bc *basicController) GetReport(ctx context.Context, artifact *ar.Artifact,
 		return nil, errors.New("no way to get report for nil artifact")
 	}
 
-	mimes := make([]string, 0)
-	mimes = append(mimes, mimeTypes...)
-	if len(mimes) == 0 {
-		// Retrieve native  and the new generic format as default
-		mimes = append(mimes, v1.MimeTypeNativeReport, v1.MimeTypeGenericVulnerabilityReport)
-	}
-
 	// Get current scanner settings
-	r, err := bc.sc.GetRegistrationByProject(ctx, artifact.ProjectID)
+	registrations, err := bc.sc.GetRegistrationsByProject(ctx, artifact.ProjectID)
 	if err != nil {
 		return nil, errors.Wrap(err, "scan controller: get report")
 	}
 
-	if r == nil {
+	if len(registrations) == 0 {
 		return nil, errors.NotFoundError(nil).WithMessagef("no scanner registration configured for project: %d", artifact.ProjectID)
 	}
 
-	artifacts, scannable, err := bc.collectScanningArtifacts(ctx, r, artifact)
+	var (
+		reports   []*scan.Report
+		scannable bool
+	)
+	// the reports of the primary scanner come first, so they are preferred when merging the reports
+	for _, r := range registrations {
+		rps, ok, err := bc.getReportsOfRegistration(ctx, r, artifact, mimeTypes)
+		if err != nil {
+			return nil, err
+		}
+		scannable = scannable || ok
+		reports = append(reports, rps...)
+	}
+
+	if !scannable {
+		return nil, errors.NotFoundError(nil).WithMessagef("report not found for %s@%s", artifact.RepositoryName, artifact.Digest)
+	}
+
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	if err := bc.assembleReports(ctx, reports...); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// GetScannerReport ...
+func (bc *basicController) GetScannerReport(ctx context.Context, artifact *ar.Artifact, registrationUUID string, mimeTypes []string) ([]*scan.Report, error) {
+	if artifact == nil {
+		return nil, errors.New("no way to get report for nil artifact")
+	}
+
+	registrations, err := bc.sc.GetRegistrationsByProject(ctx, artifact.ProjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "scan controller: get report")
+	}
+
+	var registration *scanner.Registration
+	for _, r := range registrations {
+		if r.UUID == registrationUUID {
+			registration = r
+			break
+		}
+	}
+
+	if registration == nil {
+		return nil, errors.NotFoundError(nil).WithMessagef("scanner registration %s not configured for project: %d", registrationUUID, artifact.ProjectID)
+	}
+
+	reports, scannable, err := bc.getReportsOfRegistration(ctx, registration, artifact, mimeTypes)
 	if err != nil {
 		return nil, err
 	}
@@ -601,6 +719,36 @@ func (bc *basicController) GetReport(ctx context.Context, artifact *ar.Artifact,
 		return nil, errors.NotFoundError(nil).WithMessagef("report not found for %s@%s", artifact.RepositoryName, artifact.Digest)
 	}
 
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	if err := bc.assembleReports(ctx, reports...); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// getReportsOfRegistration returns the reports of the artifact generated by the scanner registration,
+// the reports are empty if the artifact or any of its children isn't scanned by the scanner
+func (bc *basicController) getReportsOfRegistration(ctx context.Context, r *scanner.Registration, artifact *ar.Artifact, mimeTypes []string) ([]*scan.Report, bool, error) {
+	mimes := make([]string, 0)
+	mimes = append(mimes, mimeTypes...)
+	if len(mimes) == 0 {
+		// Retrieve native  and the new generic format as default
+		mimes = append(mimes, v1.MimeTypeNativeReport, v1.MimeTypeGenericVulnerabilityReport)
+	}
+
+	artifacts, scannable, err := bc.collectScanningArtifacts(ctx, r, artifact)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !scannable {
+		return nil, false, nil
+	}
+
 	groupReports := make([][]*scan.Report, len(artifacts))
 
 	var wg sync.WaitGroup
@@ -628,19 +776,11 @@ func (bc *basicController) GetReport(ctx context.Context, artifact *ar.Artifact,
 		} else {
 			// NOTE: If the artifact is OCI image, this happened when the artifact is not scanned,
 			// but its children artifacts may scanned so return empty report
-			return nil, nil
+			return nil, true, nil
 		}
 	}
 
-	if len(reports) == 0 {
-		return nil, nil
-	}
-
-	if err := bc.assembleReports(ctx, reports...); err != nil {
-		return nil, err
-	}
-
-	return reports, nil
+	return reports, true, nil
 }
 
 // GetSummary ...
@@ -654,18 +794,20 @@ func (bc *basicController) GetScanLog(ctx context.Context, artifact *ar.Artifact
 	if len(uuid) == 0 {
 		return nil, errors.New("empty uuid to get scan log")
 	}
-	r, err := bc.sc.GetRegistrationByProject(ctx, artifact.ProjectID)
+	registrations, err := bc.sc.GetRegistrationsByProject(ctx, artifact.ProjectID)
 	if err != nil {
 		return nil, err
 	}
 
-	artifacts, _, err := bc.collectScanningArtifacts(ctx, r, artifact)
-	if err != nil {
-		return nil, err
-	}
 	artifactMap := map[int64]interface{}{}
-	for _, a := range artifacts {
-		artifactMap[a.ID] = struct{}{}
+	for _, r := range registrations {
+		artifacts, _, err := bc.collectScanningArtifacts(ctx, r, artifact)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range artifacts {
+			artifactMap[a.ID] = struct{}{}
+		}
 	}
 	reportUUIDs := vuln.ParseReportIDs(uuid)
 	tasks, err := bc.listScanTasks(ctx, reportUUIDs)
@@ -819,12 +961,27 @@ func (bc *basicController) GetVulnerable(ctx context.Context, artifact *ar.Artif
 	}
 
 	if vuls := rp.GetVulnerabilityItemList().Items(); len(vuls) > 0 {
+		digests := []string{artifact.Digest}
+		for _, r := range reports {
+			if !slices.Contains(digests, r.Digest) {
+				digests = append(digests, r.Digest)
+			}
+		}
+		statements, err := bc.vexMgr.ListByDigests(ctx, artifact.ProjectID, digests...)
+		if err != nil {
+			return nil, err
+		}
+		vuls, suppressed := vex.Filter(statements, vuls)
+		for _, v := range suppressed {
+			vulnerable.CVESuppressed = append(vulnerable.CVESuppressed, v.ID)
+		}
+
 		vulnerable.VulnerabilitiesCount = len(vuls)
 
 		var severity vuln.Severity
 
 		for _, v := range vuls {
-			if !allowlistIsExpired && allowlist.Contains(v.ID) {
+			if !allowlistIsExpired && allowlist.Allows(v.ID, artifact.RepositoryName, v.Package) {
 				// Append the by passed CVEs specified in the allowlist
 				vulnerable.CVEBypassed = append(vulnerable.CVEBypassed, v.ID)
 
@@ -939,6 +1096,15 @@ func (bc *basicController) launchScanJob(ctx context.Context, param *launchScanJ
 		},
 	}
 
+	// submit the stored SBOM to the scanner consuming it, so the artifact isn't pulled again to match the vulnerabilities
+	if opts.GetScanType() == v1.ScanTypeVulnerability {
+		sbom, err := bc.getSBOM(ctx, param.Registration, param.Artifact)
+		if err != nil {
+			log.G(ctx).Warningf("failed to get the SBOM of artifact %s@%s, scan the artifact instead, error: %v", param.Artifact.RepositoryName, param.Artifact.Digest, err)
+		}
+		scanReq.SBOM = sbom
+	}
+
 	rJSON, err := param.Registration.ToJSON()
 	if err != nil {
 		return errors.Wrap(err, "scan controller: launch scan job")
@@ -1099,6 +1265,66 @@ func (bc *basicController) getLatestTagOfArtifact(ctx context.Context, artifactI
 	return tags[0].Name, nil
 }
 
+// getSBOM returns the SBOM accessory of the artifact in the format consumed by the scanner,
+// nil if the scanner doesn't consume the SBOM or no such SBOM attached to the artifact.
+// The SBOM generated by harbor is always preferred, the SBOMs pushed by the users are only
+// submitted when the project of the artifact opts in, as their content isn't verified by harbor
+func (bc *basicController) getSBOM(ctx context.Context, r *scanner.Registration, art *ar.Artifact) (*v1.Artifact, error) {
+	mimeTypes := r.GetSBOMMimeTypes()
+	if len(mimeTypes) == 0 {
+		return nil, nil
+	}
+
+	accs, err := bc.acc.List(ctx, q.New(q.KeyWords{"SubjectArtifactID": art.ID}))
+	if err != nil {
+		return nil, err
+	}
+	var externals []accessoryModel.AccessoryData
+	for _, acc := range accs {
+		data := acc.GetData()
+		switch data.Type {
+		case accessoryModel.TypeHarborSBOM:
+			// the SBOM generated by harbor is in SPDX format
+			if slices.Contains(mimeTypes, v1.MimeTypeSPDX) {
+				return toSBOMArtifact(art, data, v1.MimeTypeSPDX), nil
+			}
+		case accessoryModel.TypeSBOM:
+			externals = append(externals, data)
+		}
+	}
+	if len(externals) == 0 {
+		return nil, nil
+	}
+
+	p, err := bc.proCtl.Get(ctx, art.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.ScanExternalSBOM() {
+		return nil, nil
+	}
+	for _, data := range externals {
+		a, err := bc.ar.Get(ctx, data.ArtifactID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(mimeTypes, a.ArtifactType) {
+			return toSBOMArtifact(art, data, a.ArtifactType), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func toSBOMArtifact(art *ar.Artifact, data accessoryModel.AccessoryData, mimeType string) *v1.Artifact {
+	return &v1.Artifact{
+		Repository: art.RepositoryName,
+		Digest:     data.Digest,
+		MimeType:   mimeType,
+		Size:       data.Size,
+	}
+}
+
 func (bc *basicController) isAccessory(ctx context.Context, art *ar.Artifact) (bool, error) {
 	ac, err := bc.acc.List(ctx, q.New(q.KeyWords{"ArtifactID": art.Artifact.ID, "digest": art.Artifact.Digest}))
 	if err != nil {
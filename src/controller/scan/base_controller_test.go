@@ -30,23 +30,31 @@ import (
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/robot"
+	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/cache"
 	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
 	accessoryModel "github.com/goharbor/harbor/src/pkg/accessory/model"
+	accsbom "github.com/goharbor/harbor/src/pkg/accessory/model/sbom"
+	allowlist "github.com/goharbor/harbor/src/pkg/allowlist/models"
 	art "github.com/goharbor/harbor/src/pkg/artifact"
 	_ "github.com/goharbor/harbor/src/pkg/config/db"
 	_ "github.com/goharbor/harbor/src/pkg/config/inmemory"
 	"github.com/goharbor/harbor/src/pkg/permission/types"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
 	"github.com/goharbor/harbor/src/pkg/robot/model"
 	sca "github.com/goharbor/harbor/src/pkg/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
+	"github.com/goharbor/harbor/src/pkg/scan/report"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
 	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/goharbor/harbor/src/pkg/task"
+	vexModel "github.com/goharbor/harbor/src/pkg/vex/model"
 	artifacttesting "github.com/goharbor/harbor/src/testing/controller/artifact"
+	projecttesting "github.com/goharbor/harbor/src/testing/controller/project"
 	robottesting "github.com/goharbor/harbor/src/testing/controller/robot"
 	scannertesting "github.com/goharbor/harbor/src/testing/controller/scanner"
 	tagtesting "github.com/goharbor/harbor/src/testing/controller/tag"
@@ -58,6 +66,7 @@ import (
 	postprocessorstesting "github.com/goharbor/harbor/src/testing/pkg/scan/postprocessors"
 	reporttesting "github.com/goharbor/harbor/src/testing/pkg/scan/report"
 	tasktesting "github.com/goharbor/harbor/src/testing/pkg/task"
+	vextesting "github.com/goharbor/harbor/src/testing/pkg/vex"
 )
 
 // ControllerTestSuite is the test suite for scan controller.
@@ -150,7 +159,7 @@ func (suite *ControllerTestSuite) SetupSuite() {
 	}
 
 	sc := &scannertesting.Controller{}
-	sc.On("GetRegistrationByProject", mock.Anything, suite.artifact.ProjectID).Return(suite.registration, nil)
+	sc.On("GetRegistrationsByProject", mock.Anything, suite.artifact.ProjectID).Return([]*scanner.Registration{suite.registration}, nil)
 	sc.On("Ping", suite.registration).Return(m, nil)
 
 	mgr := &reporttesting.Manager{}
@@ -681,3 +690,280 @@ func (suite *ControllerTestSuite) makeExtraAttrs(artifactID int64, reportUUIDs .
 
 	return extraAttrs
 }
+
+func TestGetReportOfMultipleScanners(t *testing.T) {
+	meta := &v1.ScannerAdapterMetadata{
+		Capabilities: []*v1.ScannerCapability{{
+			Type:              v1.ScanTypeVulnerability,
+			ConsumesMimeTypes: []string{v1.MimeTypeDockerArtifact},
+			ProducesMimeTypes: []string{v1.MimeTypeNativeReport},
+		}},
+	}
+	trivy := &scanner.Registration{ID: 1, UUID: "trivy", Name: "Trivy", Metadata: meta}
+	another := &scanner.Registration{ID: 2, UUID: "another", Name: "Another", Metadata: meta}
+
+	a := &artifact.Artifact{Artifact: art.Artifact{ID: 1, ProjectID: 1, Type: "IMAGE", Digest: "digest-code", ManifestMediaType: v1.MimeTypeDockerArtifact}}
+
+	makeReport := func(uuid, registrationUUID string, severity vuln.Severity) *scan.Report {
+		data, _ := json.Marshal(vuln.Report{
+			Severity: severity,
+			Vulnerabilities: []*vuln.VulnerabilityItem{
+				{ID: "CVE-2024-0001", Package: "openssl", Version: "3.0.1", Severity: severity},
+				{ID: "CVE-2024-" + uuid, Package: "zlib", Version: "1.2.11", Severity: vuln.Low},
+			},
+		})
+		return &scan.Report{UUID: uuid, Digest: a.Digest, RegistrationUUID: registrationUUID, MimeType: v1.MimeTypeNativeReport, Report: string(data)}
+	}
+
+	sc := &scannertesting.Controller{}
+	sc.On("GetRegistrationsByProject", mock.Anything, a.ProjectID).Return([]*scanner.Registration{trivy, another}, nil)
+
+	ar := &artifacttesting.Controller{}
+	mock.OnAnything(ar, "HasUnscannableLayer").Return(false, nil)
+	mock.OnAnything(ar, "Walk").Return(nil).Run(func(args mock.Arguments) {
+		walkFn := args.Get(2).(func(*artifact.Artifact) error)
+		walkFn(a)
+	})
+	acc := &accessorytesting.Manager{}
+	mock.OnAnything(acc, "List").Return([]accessoryModel.Accessory{}, nil)
+
+	mgr := &reporttesting.Manager{}
+	mgr.On("GetBy", mock.Anything, a.Digest, "trivy", mock.Anything).Return([]*scan.Report{makeReport("0001", "trivy", vuln.Medium)}, nil)
+	mgr.On("GetBy", mock.Anything, a.Digest, "another", mock.Anything).Return([]*scan.Report{makeReport("0002", "another", vuln.Critical)}, nil)
+
+	taskMgr := &tasktesting.Manager{}
+	mock.OnAnything(taskMgr, "ListScanTasksByReportUUID").Return(nil, nil)
+	converter := &postprocessorstesting.NativeScanReportConverter{}
+	mock.OnAnything(converter, "FromRelationalSchema").Return(func(_ context.Context, _, _, report string) (string, error) {
+		return report, nil
+	})
+
+	c := &basicController{
+		manager:         mgr,
+		ar:              ar,
+		acc:             acc,
+		sc:              sc,
+		taskMgr:         taskMgr,
+		reportConverter: converter,
+		cloneCtx:        func(ctx context.Context) context.Context { return ctx },
+	}
+
+	// the reports of all the scanners are merged with the worst severity
+	reports, err := c.GetReport(context.TODO(), a, []string{v1.MimeTypeNativeReport})
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "trivy", reports[0].RegistrationUUID)
+	raw, err := report.Reports(reports).ResolveData(v1.MimeTypeNativeReport)
+	require.NoError(t, err)
+	items := raw.(*vuln.Report).GetVulnerabilityItemList().Items()
+	require.Len(t, items, 3)
+	assert.Equal(t, vuln.Critical, items[0].Severity)
+
+	// the report of each scanner is retrievable
+	reports, err = c.GetScannerReport(context.TODO(), a, "another", []string{v1.MimeTypeNativeReport})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "another", reports[0].RegistrationUUID)
+
+	_, err = c.GetScannerReport(context.TODO(), a, "unknown", []string{v1.MimeTypeNativeReport})
+	assert.True(t, errors.IsNotFoundErr(err))
+
+	// the disabled scanners are skipped, and the other scan types use the primary scanner only
+	another.Disabled = true
+	registrations, err := c.getScanRegistrations(context.TODO(), a.ProjectID, v1.ScanTypeVulnerability)
+	require.NoError(t, err)
+	assert.Equal(t, []*scanner.Registration{trivy}, registrations)
+	trivy.Disabled = true
+	_, err = c.getScanRegistrations(context.TODO(), a.ProjectID, v1.ScanTypeVulnerability)
+	assert.True(t, errors.IsErr(err, errors.PreconditionCode))
+	another.Disabled = false
+	_, err = c.getScanRegistrations(context.TODO(), a.ProjectID, v1.ScanTypeSbom)
+	assert.True(t, errors.IsErr(err, errors.PreconditionCode))
+}
+
+func TestGetVulnerableWithVEX(t *testing.T) {
+	meta := &v1.ScannerAdapterMetadata{
+		Capabilities: []*v1.ScannerCapability{{
+			Type:              v1.ScanTypeVulnerability,
+			ConsumesMimeTypes: []string{v1.MimeTypeDockerArtifact},
+			ProducesMimeTypes: []string{v1.MimeTypeNativeReport},
+		}},
+	}
+	trivy := &scanner.Registration{ID: 1, UUID: "trivy", Name: "Trivy", Metadata: meta}
+	a := &artifact.Artifact{Artifact: art.Artifact{ID: 1, ProjectID: 1, Type: "IMAGE", Digest: "digest-code", ManifestMediaType: v1.MimeTypeDockerArtifact}}
+
+	data, _ := json.Marshal(vuln.Report{
+		Severity: vuln.Critical,
+		Vulnerabilities: []*vuln.VulnerabilityItem{
+			{ID: "CVE-2024-0001", Package: "openssl", Version: "3.0.1", Severity: vuln.Critical},
+			{ID: "CVE-2024-0001", Package: "libssl", Version: "3.0.1", Severity: vuln.High},
+			{ID: "CVE-2024-0002", Package: "zlib", Version: "1.2.11", Severity: vuln.Medium},
+			{ID: "CVE-2024-0003", Package: "curl", Version: "8.0.0", Severity: vuln.Low},
+		},
+	})
+	rp := &scan.Report{UUID: "uuid", Digest: a.Digest, RegistrationUUID: trivy.UUID, MimeType: v1.MimeTypeNativeReport, Report: string(data)}
+
+	sc := &scannertesting.Controller{}
+	sc.On("GetRegistrationsByProject", mock.Anything, a.ProjectID).Return([]*scanner.Registration{trivy}, nil)
+	ar := &artifacttesting.Controller{}
+	mock.OnAnything(ar, "HasUnscannableLayer").Return(false, nil)
+	mock.OnAnything(ar, "Walk").Return(nil).Run(func(args mock.Arguments) {
+		walkFn := args.Get(2).(func(*artifact.Artifact) error)
+		walkFn(a)
+	})
+	acc := &accessorytesting.Manager{}
+	mock.OnAnything(acc, "List").Return([]accessoryModel.Accessory{}, nil)
+	mgr := &reporttesting.Manager{}
+	mgr.On("GetBy", mock.Anything, a.Digest, trivy.UUID, mock.Anything).Return([]*scan.Report{rp}, nil)
+	taskMgr := &tasktesting.Manager{}
+	mock.OnAnything(taskMgr, "ListScanTasksByReportUUID").Return([]*task.Task{{
+		Status:     job.SuccessStatus.String(),
+		ExtraAttrs: map[string]interface{}{reportUUIDsKey: []interface{}{rp.UUID}},
+	}}, nil)
+	converter := &postprocessorstesting.NativeScanReportConverter{}
+	mock.OnAnything(converter, "FromRelationalSchema").Return(func(_ context.Context, _, _, report string) (string, error) {
+		return report, nil
+	})
+	vexMgr := &vextesting.Manager{}
+	vexMgr.On("ListByDigests", mock.Anything, a.ProjectID, a.Digest).Return([]*vexModel.Statement{
+		// scoped to the openssl package only
+		{ArtifactDigest: a.Digest, CVEID: "CVE-2024-0001", Package: "openssl", Status: vexModel.StatusNotAffected},
+		{ArtifactDigest: a.Digest, CVEID: "CVE-2024-0002", Status: vexModel.StatusFixed},
+		// the affected statement doesn't suppress the vulnerability
+		{ArtifactDigest: a.Digest, CVEID: "CVE-2024-0003", Status: vexModel.StatusAffected},
+	}, nil)
+
+	c := &basicController{
+		manager:         mgr,
+		ar:              ar,
+		acc:             acc,
+		sc:              sc,
+		taskMgr:         taskMgr,
+		reportConverter: converter,
+		cloneCtx:        func(ctx context.Context) context.Context { return ctx },
+		vexMgr:          vexMgr,
+	}
+
+	vulnerable, err := c.GetVulnerable(context.TODO(), a, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, vulnerable.VulnerabilitiesCount)
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0002"}, vulnerable.CVESuppressed)
+	require.NotNil(t, vulnerable.Severity)
+	assert.Equal(t, vuln.High, *vulnerable.Severity)
+
+	// the scoped allowlist items only bypass the CVEs of the matched repositories and packages
+	a.RepositoryName = "library/app"
+	set := allowlist.CVESet{}
+	set.AddItem(allowlist.CVEAllowlistItem{CVEID: "CVE-2024-0001", Repository: "library/*", Package: "libssl"})
+	set.AddItem(allowlist.CVEAllowlistItem{CVEID: "CVE-2024-0003", Repository: "dev/*"})
+	vulnerable, err = c.GetVulnerable(context.TODO(), a, set, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, vulnerable.VulnerabilitiesCount)
+	assert.Equal(t, []string{"CVE-2024-0001"}, vulnerable.CVEBypassed)
+	require.NotNil(t, vulnerable.Severity)
+	assert.Equal(t, vuln.Low, *vulnerable.Severity)
+}
+
+func TestRescanOnDBUpdate(t *testing.T) {
+	trivy := &scanner.Registration{ID: 1, UUID: "trivy", Name: "Trivy", DBUpdatedAt: "2024-01-02T00:00:00Z"}
+	another := &scanner.Registration{ID: 2, UUID: "another", Name: "Another"}
+	detectedAt := time.Now()
+	pulledSince := detectedAt.AddDate(0, 0, -7)
+
+	execMgr := &tasktesting.ExecutionManager{}
+	sc := &scannertesting.Controller{}
+	mgr := &reporttesting.Manager{}
+	ar := &artifacttesting.Controller{}
+	c := &basicController{
+		manager: mgr,
+		ar:      ar,
+		sc:      sc,
+		execMgr: execMgr,
+	}
+
+	// the daily limit is reached by the rescans of today
+	execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{ID: 1, ExtraAttrs: map[string]interface{}{"summary": map[string]interface{}{"submit_count": float64(6)}}},
+		{ID: 2, ExtraAttrs: map[string]interface{}{"summary": map[string]interface{}{"submit_count": float64(4)}}},
+	}, nil).Once()
+	count, err := c.RescanOnDBUpdate(context.TODO(), 10, pulledSince)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// only the stale artifacts of the scanner whose database updated are rescanned within the remaining budget
+	execMgr.On("List", mock.Anything, mock.Anything).Return([]*task.Execution{
+		{ID: 1, ExtraAttrs: map[string]interface{}{"summary": map[string]interface{}{"submit_count": float64(6)}}},
+	}, nil).Once()
+	sc.On("ListRegistrations", mock.Anything, mock.Anything).Return([]*scanner.Registration{trivy, another}, nil).Once()
+	sc.On("PollDBUpdate", mock.Anything, trivy).Return(detectedAt, nil).Once()
+	sc.On("PollDBUpdate", mock.Anything, another).Return(time.Time{}, nil).Once()
+	mgr.On("ListStaleArtifactIDs", mock.Anything, "trivy", detectedAt, pulledSince, 4).Return([]int64{100}, nil).Once()
+	execMgr.On("Create", mock.Anything, "SCAN_DB_UPDATE", int64(0), "EVENT", mock.Anything).Return(int64(3), nil).Once()
+	ar.On("Get", mock.Anything, int64(100), mock.Anything).Return(nil, errors.NotFoundError(nil)).Once()
+	execMgr.On("Get", mock.Anything, int64(3)).Return(&task.Execution{ID: 3}, nil).Once()
+	execMgr.On("UpdateExtraAttrs", mock.Anything, int64(3), mock.Anything).Return(nil).Once()
+	execMgr.On("MarkDone", mock.Anything, int64(3), mock.Anything).Return(nil).Once()
+	count, err = c.RescanOnDBUpdate(context.TODO(), 10, pulledSince)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	execMgr.AssertExpectations(t)
+	sc.AssertExpectations(t)
+	mgr.AssertExpectations(t)
+	ar.AssertExpectations(t)
+}
+
+func TestGetSBOM(t *testing.T) {
+	r := &scanner.Registration{UUID: "trivy", Metadata: &v1.ScannerAdapterMetadata{
+		Capabilities: []*v1.ScannerCapability{{
+			Type:              v1.ScanTypeVulnerability,
+			ConsumesMimeTypes: []string{v1.MimeTypeDockerArtifact, v1.MimeTypeCycloneDX},
+			ProducesMimeTypes: []string{v1.MimeTypeNativeReport},
+		}},
+	}}
+	a := &artifact.Artifact{Artifact: art.Artifact{ID: 1, ProjectID: 1, RepositoryName: "library/photon", Digest: "digest-code"}}
+
+	harborSBOM := accsbom.New(accessoryModel.AccessoryData{ArtifactID: 2, Type: accessoryModel.TypeHarborSBOM, Digest: "harbor-sbom", Size: 10})
+	cyclonedx := accsbom.NewSBOM(accessoryModel.AccessoryData{ArtifactID: 3, Type: accessoryModel.TypeSBOM, Digest: "cyclonedx", Size: 20})
+
+	acc := &accessorytesting.Manager{}
+	acc.On("List", mock.Anything, q.New(q.KeyWords{"SubjectArtifactID": a.ID})).Return([]accessoryModel.Accessory{harborSBOM, cyclonedx}, nil)
+	ar := &artifacttesting.Controller{}
+	ar.On("Get", mock.Anything, int64(3), mock.Anything).Return(&artifact.Artifact{Artifact: art.Artifact{ID: 3, ArtifactType: v1.MimeTypeCycloneDX}}, nil)
+	proCtl := &projecttesting.Controller{}
+	proCtl.On("Get", mock.Anything, a.ProjectID).Return(&proModels.Project{ProjectID: 1}, nil).Once()
+	proCtl.On("Get", mock.Anything, a.ProjectID).Return(&proModels.Project{ProjectID: 1, Metadata: map[string]string{
+		proModels.ProMetaScanExternalSBOM: "true",
+	}}, nil).Once()
+	c := &basicController{acc: acc, ar: ar, proCtl: proCtl}
+
+	// the SPDX SBOM generated by harbor isn't consumed by the scanner, and the project doesn't opt in the SBOMs pushed by the users
+	sbom, err := c.getSBOM(context.TODO(), r, a)
+	require.NoError(t, err)
+	assert.Nil(t, sbom)
+
+	// the project opts in, the CycloneDX SBOM is submitted instead
+	sbom, err = c.getSBOM(context.TODO(), r, a)
+	require.NoError(t, err)
+	require.NotNil(t, sbom)
+	assert.Equal(t, "library/photon", sbom.Repository)
+	assert.Equal(t, "cyclonedx", sbom.Digest)
+	assert.Equal(t, v1.MimeTypeCycloneDX, sbom.MimeType)
+	assert.Equal(t, int64(20), sbom.Size)
+
+	// the SBOM generated by harbor is preferred once the scanner consumes it
+	r.Metadata.Capabilities[0].ConsumesMimeTypes = []string{v1.MimeTypeCycloneDX, v1.MimeTypeSPDX}
+	sbom, err = c.getSBOM(context.TODO(), r, a)
+	require.NoError(t, err)
+	require.NotNil(t, sbom)
+	assert.Equal(t, "harbor-sbom", sbom.Digest)
+	assert.Equal(t, v1.MimeTypeSPDX, sbom.MimeType)
+
+	// the scanner doesn't consume any SBOM
+	r.Metadata.Capabilities[0].ConsumesMimeTypes = []string{v1.MimeTypeDockerArtifact}
+	sbom, err = c.getSBOM(context.TODO(), r, a)
+	require.NoError(t, err)
+	assert.Nil(t, sbom)
+	acc.AssertNumberOfCalls(t, "List", 3)
+	proCtl.AssertExpectations(t)
+}
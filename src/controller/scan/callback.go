@@ -53,6 +53,11 @@ func init() {
 		log.Fatalf("failed to register the task status change post for the scan all job, error %v", err)
 	}
 
+	// NOTE: the vendor type of execution for the scan job trigger by the vulnerability database updates is VendorTypeScanDBUpdate
+	if err := task.RegisterTaskStatusChangePostFunc(job.ScanDBUpdateVendorType, scanTaskStatusChange); err != nil {
+		log.Fatalf("failed to register the task status change post for the rescan job, error %v", err)
+	}
+
 	if err := task.RegisterTaskStatusChangePostFunc(job.ImageScanJobVendorType, scanTaskStatusChange); err != nil {
 		log.Fatalf("failed to register the task status change post for the scan job, error %v", err)
 	}
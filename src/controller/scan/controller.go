@@ -16,6 +16,7 @@ package scan
 
 import (
 	"context"
+	"time"
 
 	"github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/jobservice/job"
@@ -30,6 +31,8 @@ type Vulnerable struct {
 	ScanStatus           string
 	Severity             *vuln.Severity
 	CVEBypassed          []string
+	// CVESuppressed are the CVEs declared as not_affected or fixed by the VEX statements of the artifact
+	CVESuppressed []string
 }
 
 // IsScanSuccess returns true when the artifact scanned success
@@ -61,7 +64,9 @@ type Controller interface {
 	//     error  : non nil error if any errors occurred
 	Stop(ctx context.Context, artifact *artifact.Artifact, capType string) error
 
-	// GetReport gets the reports for the given artifact identified by the digest
+	// GetReport gets the reports for the given artifact identified by the digest, the reports of all the
+	// scanners of the project are returned with the ones of the primary scanner first, they are merged
+	// together with the worst severity of each vulnerability when resolving the data
 	//
 	//   Arguments:
 	//     ctx context.Context : the context for this method
@@ -73,6 +78,19 @@ type Controller interface {
 	//     error          : non nil error if any errors occurred
 	GetReport(ctx context.Context, artifact *artifact.Artifact, mimeTypes []string) ([]*scan.Report, error)
 
+	// GetScannerReport gets the reports for the given artifact generated by the specified scanner of the project
+	//
+	//   Arguments:
+	//     ctx context.Context : the context for this method
+	//     artifact *v1.Artifact   : the scanned artifact
+	//     registrationUUID string : the UUID of the scanner registration
+	//     mimeTypes []string      : the mime types of the reports
+	//
+	//   Returns:
+	//     []*scan.Report : scan results of the scanner
+	//     error          : non nil error if any errors occurred
+	GetScannerReport(ctx context.Context, artifact *artifact.Artifact, registrationUUID string, mimeTypes []string) ([]*scan.Report, error)
+
 	// GetSummary gets the summaries of the reports with given types.
 	//
 	//   Arguments:
@@ -122,11 +140,25 @@ type Controller interface {
 	//   Arguments:
 	//     ctx context.Context : the context for this method
 	//     artifact *artifact.Artifact : artifact to be scanned
-	//     allowlist map[string]struct{} : the set of CVE id of the items in the allowlist
+	//     allowlist allowlist.CVESet : the items of the allowlist indexed by the CVE id
 	//     allowlistIsExpired bool : whether the allowlist is expired
 	//
 	//   Returns
 	//      *Vulnerable : the vulnerable
 	//     error        : non nil error if any errors occurred
 	GetVulnerable(ctx context.Context, artifact *artifact.Artifact, allowlist allowlist.CVESet, allowlistIsExpired bool) (*Vulnerable, error)
+
+	// RescanOnDBUpdate polls the vulnerability database updates of the scanners and rescans the artifacts
+	// whose reports are generated before the updates, the artifacts of the popular repositories and the
+	// recently pulled ones are rescanned first
+	//
+	//   Arguments:
+	//     ctx context.Context   : the context for this method
+	//     dailyLimit int        : the max count of the artifacts rescanned per day
+	//     pulledSince time.Time : [optional] only rescan the artifacts pulled since the time
+	//
+	//   Returns:
+	//     int   : the count of the artifacts submitted to rescan
+	//     error : non nil error if any errors occurred
+	RescanOnDBUpdate(ctx context.Context, dailyLimit int, pulledSince time.Time) (int, error)
 }
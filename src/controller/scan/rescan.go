@@ -0,0 +1,183 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scan
+
+import (
+	"context"
+	"time"
+
+	ar "github.com/goharbor/harbor/src/controller/artifact"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/gtask"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/q"
+	libredis "github.com/goharbor/harbor/src/lib/redis"
+	"github.com/goharbor/harbor/src/pkg/task"
+)
+
+const (
+	// rescanInterval is the interval to poll the vulnerability database updates of the scanners
+	rescanInterval = 30 * time.Minute
+	// rescanLockKey makes sure only one core instance rescans in each interval
+	rescanLockKey = "scan:rescan_on_db_update:lock"
+	// dbUpdatedAtKey is the extra attribute of the rescan execution recording the updated_at property of the database
+	dbUpdatedAtKey = "db_updated_at"
+)
+
+var (
+	// setIfAbsent sets the value of the key only if the key doesn't exist and returns whether it's set
+	setIfAbsent = func(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return false, err
+		}
+		return client.SetNX(ctx, key, value, expiration).Result()
+	}
+)
+
+func init() {
+	gtask.DefaultPool().AddTask(rescanOnDBUpdate, rescanInterval)
+}
+
+func rescanOnDBUpdate(ctx context.Context) {
+	limit := config.ScannerRescanDailyLimit(ctx)
+	if limit <= 0 {
+		return
+	}
+	locked, err := setIfAbsent(ctx, rescanLockKey, time.Now().Unix(), rescanInterval/2)
+	if err != nil {
+		log.Warningf("failed to acquire the lock of the rescan: %v", err)
+		return
+	}
+	if !locked {
+		return
+	}
+
+	var pulledSince time.Time
+	if days := config.ScannerRescanPulledWithinDays(ctx); days > 0 {
+		pulledSince = time.Now().AddDate(0, 0, -int(days))
+	}
+	if _, err := scanCtl.RescanOnDBUpdate(ctx, int(limit), pulledSince); err != nil {
+		log.Errorf("failed to rescan the artifacts on the vulnerability database updates: %v", err)
+	}
+}
+
+// RescanOnDBUpdate ...
+func (bc *basicController) RescanOnDBUpdate(ctx context.Context, dailyLimit int, pulledSince time.Time) (int, error) {
+	rescanned, err := bc.rescannedToday(ctx)
+	if err != nil {
+		return 0, err
+	}
+	budget := dailyLimit - rescanned
+	if budget <= 0 {
+		log.Debugf("the daily limit %d of the rescan is reached", dailyLimit)
+		return 0, nil
+	}
+
+	registrations, err := bc.sc.ListRegistrations(ctx, q.New(q.KeyWords{"disabled": false}))
+	if err != nil {
+		return 0, err
+	}
+
+	submitted := 0
+	for _, r := range registrations {
+		if submitted >= budget {
+			break
+		}
+
+		detectedAt, err := bc.sc.PollDBUpdate(ctx, r)
+		if err != nil {
+			log.Warningf("failed to poll the vulnerability database update of the scanner %s: %v", r.Name, err)
+			continue
+		}
+		if detectedAt.IsZero() {
+			continue
+		}
+
+		ids, err := bc.manager.ListStaleArtifactIDs(ctx, r.UUID, detectedAt, pulledSince, budget-submitted)
+		if err != nil {
+			return submitted, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		extraAttrs := map[string]interface{}{
+			registrationKey: map[string]interface{}{
+				"id":   r.ID,
+				"name": r.Name,
+			},
+			dbUpdatedAtKey: r.DBUpdatedAt,
+		}
+		executionID, err := bc.execMgr.Create(ctx, job.ScanDBUpdateVendorType, 0, task.ExecutionTriggerEvent, extraAttrs)
+		if err != nil {
+			return submitted, err
+		}
+
+		log.Infof("rescan %d artifact(s) as the vulnerability database of the scanner %s updated at %s", len(ids), r.Name, r.DBUpdatedAt)
+		count, err := bc.scanArtifacts(ctx, executionID, bc.iterateArtifacts(ctx, ids))
+		submitted += count
+		if err != nil {
+			return submitted, err
+		}
+	}
+
+	return submitted, nil
+}
+
+// rescannedToday returns the count of the artifacts rescanned today on the vulnerability database updates
+func (bc *basicController) rescannedToday(ctx context.Context) (int, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	executions, err := bc.execMgr.List(ctx, q.New(q.KeyWords{
+		"vendor_type": job.ScanDBUpdateVendorType,
+		"start_time":  &q.Range{Min: startOfDay},
+	}))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, exec := range executions {
+		if summary, ok := exec.ExtraAttrs["summary"].(map[string]interface{}); ok {
+			if n, ok := summary["submit_count"].(float64); ok {
+				count += int(n)
+			}
+		}
+	}
+	return count, nil
+}
+
+// iterateArtifacts returns the channel of the artifacts with the IDs, the ones not found are skipped
+func (bc *basicController) iterateArtifacts(ctx context.Context, ids []int64) <-chan *ar.Artifact {
+	ch := make(chan *ar.Artifact)
+	go func() {
+		defer close(ch)
+		for _, id := range ids {
+			art, err := bc.ar.Get(ctx, id, nil)
+			if err != nil {
+				log.Warningf("failed to get the artifact %d to rescan: %v", id, err)
+				continue
+			}
+			select {
+			case ch <- art:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -17,6 +17,8 @@ package scanner
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,8 +37,10 @@ import (
 
 const (
 	proScannerMetaKey = "projectScanner"
-	statusUnhealthy   = "unhealthy"
-	statusHealthy     = "healthy"
+	// the comma separated UUIDs of the additional scanners of the project besides the "projectScanner"
+	proScannersMetaKey = "projectScanners"
+	statusUnhealthy    = "unhealthy"
+	statusHealthy      = "healthy"
 	// RetrieveCapFailMsg the message indicate failed to retrieve the scanner capabilities
 	RetrieveCapFailMsg = "failed to retrieve scanner capabilities, error %v"
 )
@@ -281,24 +285,119 @@ func (bc *basicController) GetRegistrationByProject(ctx context.Context, project
 	opts := newOptions(options...)
 
 	if opts.Ping {
-		// Get metadata of the configured registration
-		meta, err := bc.Ping(ctx, registration)
+		bc.ping(ctx, registration)
+	}
+
+	return registration, nil
+}
+
+// ping gets the metadata of the registration and fills in its health status
+func (bc *basicController) ping(ctx context.Context, registration *scanner.Registration) {
+	meta, err := bc.Ping(ctx, registration)
+	if err != nil {
+		// Not blocked, just logged it
+		log.Error(errors.Wrap(err, "api controller: get project scanner"))
+		registration.Health = statusUnhealthy
+		return
+	}
+
+	registration.Health = statusHealthy
+	// Fill in some metadata
+	registration.Adapter = meta.Scanner.Name
+	registration.Vendor = meta.Scanner.Vendor
+	registration.Version = meta.Scanner.Version
+
+	registration.Metadata = meta
+}
+
+// SetRegistrationsByProject ...
+func (bc *basicController) SetRegistrationsByProject(ctx context.Context, projectID int64, registrationIDs []string) error {
+	if len(registrationIDs) == 0 {
+		return errors.New("missing scanner UUID")
+	}
+
+	var additional []string
+	for _, id := range registrationIDs {
+		if len(id) == 0 {
+			return errors.New("missing scanner UUID")
+		}
+		if !bc.RegistrationExists(ctx, id) {
+			return errors.NotFoundError(nil).WithMessagef("scanner %s not found", id)
+		}
+		if id != registrationIDs[0] && !slices.Contains(additional, id) {
+			additional = append(additional, id)
+		}
+	}
+
+	if err := bc.SetRegistrationByProject(ctx, projectID, registrationIDs[0]); err != nil {
+		return err
+	}
+
+	m, err := bc.proMetaMgr.Get(ctx, projectID, proScannersMetaKey)
+	if err != nil {
+		return errors.Wrap(err, "api controller: set project scanners")
+	}
+
+	value := strings.Join(additional, ",")
+	switch {
+	case len(m) > 0 && len(value) == 0:
+		err = bc.proMetaMgr.Delete(ctx, projectID, proScannersMetaKey)
+	case len(m) > 0 && m[proScannersMetaKey] != value:
+		err = bc.proMetaMgr.Update(ctx, projectID, map[string]string{proScannersMetaKey: value})
+	case len(m) == 0 && len(value) > 0:
+		err = bc.proMetaMgr.Add(ctx, projectID, map[string]string{proScannersMetaKey: value})
+	}
+	if err != nil {
+		return errors.Wrap(err, "api controller: set project scanners")
+	}
+
+	return nil
+}
+
+// GetRegistrationsByProject ...
+func (bc *basicController) GetRegistrationsByProject(ctx context.Context, projectID int64, options ...Option) ([]*scanner.Registration, error) {
+	primary, err := bc.GetRegistrationByProject(ctx, projectID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// No scanner configured
+	if primary == nil {
+		return nil, nil
+	}
+
+	registrations := []*scanner.Registration{primary}
+
+	m, err := bc.proMetaMgr.Get(ctx, projectID, proScannersMetaKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "api controller: get project scanners")
+	}
+
+	opts := newOptions(options...)
+	for _, id := range strings.Split(m[proScannersMetaKey], ",") {
+		if len(id) == 0 || id == primary.UUID {
+			continue
+		}
+
+		registration, err := bc.manager.Get(ctx, id)
 		if err != nil {
-			// Not blocked, just logged it
-			log.Error(errors.Wrap(err, "api controller: get project scanner"))
-			registration.Health = statusUnhealthy
-		} else {
-			registration.Health = statusHealthy
-			// Fill in some metadata
-			registration.Adapter = meta.Scanner.Name
-			registration.Vendor = meta.Scanner.Vendor
-			registration.Version = meta.Scanner.Version
-
-			registration.Metadata = meta
+			return nil, errors.Wrap(err, "api controller: get project scanners")
+		}
+
+		if registration == nil {
+			// Might be deleted by the admin, skip it
+			log.G(ctx).Warningf("the scanner %s of project %d not found, skip it", id, projectID)
+			continue
 		}
+
+		if opts.Ping {
+			bc.ping(ctx, registration)
+		}
+
+		registrations = append(registrations, registration)
 	}
 
-	return registration, nil
+	return registrations, nil
 }
 
 // Ping ...
@@ -349,6 +448,37 @@ func (bc *basicController) GetMetadata(ctx context.Context, registrationUUID str
 	return bc.Ping(ctx, r)
 }
 
+// PollDBUpdate ...
+func (bc *basicController) PollDBUpdate(ctx context.Context, registration *scanner.Registration) (time.Time, error) {
+	if registration == nil {
+		return time.Time{}, errors.New("nil registration to poll")
+	}
+
+	// always get the metadata from the adapter rather than the cache to catch the update in time
+	meta, err := bc.getScannerAdapterMetadata(registration)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "scanner controller: poll db update")
+	}
+
+	updatedAt := meta.Properties[v1.PropertyDBUpdatedAt]
+	if len(updatedAt) == 0 || updatedAt == registration.DBUpdatedAt {
+		return registration.DBUpdateDetectedAt, nil
+	}
+
+	cols := []string{"db_updated_at"}
+	// the first polled value is taken as the baseline, the reports generated before aren't considered stale
+	if len(registration.DBUpdatedAt) > 0 {
+		registration.DBUpdateDetectedAt = time.Now()
+		cols = append(cols, "db_update_detected_at")
+	}
+	registration.DBUpdatedAt = updatedAt
+	if err := bc.manager.Update(ctx, registration, cols...); err != nil {
+		return time.Time{}, errors.Wrap(err, "scanner controller: poll db update")
+	}
+
+	return registration.DBUpdateDetectedAt, nil
+}
+
 func (bc *basicController) getScannerAdapterMetadata(registration *scanner.Registration) (*v1.ScannerAdapterMetadata, error) {
 	client, err := registration.Client(bc.clientPool)
 	if err != nil {
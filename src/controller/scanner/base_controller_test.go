@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
@@ -231,6 +232,54 @@ func (suite *ControllerTestSuite) TestGetRegistrationByProject() {
 	assert.Equal(suite.T(), "forUT", r.Name)
 }
 
+// TestSetRegistrationsByProject tests SetRegistrationsByProject
+func (suite *ControllerTestSuite) TestSetRegistrationsByProject() {
+	var pid int64 = 1
+	suite.mMgr.On("Get", mock.Anything, "uuid").Return(suite.sample, nil)
+	suite.mMgr.On("Get", mock.Anything, "uuid2").Return(suite.sample, nil)
+	suite.mMgr.On("Get", mock.Anything, "uuid3").Return(nil, nil)
+
+	// missing scanner
+	err := suite.c.SetRegistrationsByProject(context.TODO(), pid, nil)
+	suite.Error(err)
+	err = suite.c.SetRegistrationsByProject(context.TODO(), pid, []string{"uuid", "uuid3"})
+	suite.True(errors.IsNotFoundErr(err))
+
+	// set the primary and additional scanners
+	suite.mMeta.On("Get", mock.Anything, pid, proScannerMetaKey).Return(map[string]string{proScannerMetaKey: "uuid"}, nil)
+	suite.mMeta.On("Get", mock.Anything, pid, proScannersMetaKey).Return(map[string]string{}, nil).Once()
+	suite.mMeta.On("Add", mock.Anything, pid, map[string]string{proScannersMetaKey: "uuid2"}).Return(nil).Once()
+	err = suite.c.SetRegistrationsByProject(context.TODO(), pid, []string{"uuid", "uuid2", "uuid2", "uuid"})
+	suite.NoError(err)
+
+	// remove the additional scanners
+	suite.mMeta.On("Get", mock.Anything, pid, proScannersMetaKey).Return(map[string]string{proScannersMetaKey: "uuid2"}, nil).Once()
+	suite.mMeta.On("Delete", mock.Anything, pid, proScannersMetaKey).Return(nil).Once()
+	err = suite.c.SetRegistrationsByProject(context.TODO(), pid, []string{"uuid"})
+	suite.NoError(err)
+
+	suite.mMeta.AssertExpectations(suite.T())
+}
+
+// TestGetRegistrationsByProject tests GetRegistrationsByProject
+func (suite *ControllerTestSuite) TestGetRegistrationsByProject() {
+	var pid int64 = 1
+	suite.sample.UUID = "uuid"
+	another := &scanner.Registration{UUID: "uuid2", Name: "another"}
+
+	suite.mMeta.On("Get", mock.Anything, pid, proScannerMetaKey).Return(map[string]string{proScannerMetaKey: "uuid"}, nil)
+	suite.mMeta.On("Get", mock.Anything, pid, proScannersMetaKey).Return(map[string]string{proScannersMetaKey: "uuid2,uuid,uuid3"}, nil)
+	suite.mMgr.On("Get", mock.Anything, "uuid").Return(suite.sample, nil)
+	suite.mMgr.On("Get", mock.Anything, "uuid2").Return(another, nil)
+	suite.mMgr.On("Get", mock.Anything, "uuid3").Return(nil, nil)
+
+	registrations, err := suite.c.GetRegistrationsByProject(context.TODO(), pid)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), registrations, 2)
+	suite.Equal("forUT", registrations[0].Name)
+	suite.Equal("another", registrations[1].Name)
+}
+
 // TestGetRegistrationByProjectWhenPingError tests GetRegistrationByProject
 func (suite *ControllerTestSuite) TestGetRegistrationByProjectWhenPingError() {
 	m := make(map[string]string, 1)
@@ -310,3 +359,37 @@ func (suite *ControllerTestSuite) TestGetMetadata() {
 	suite.NotNil(meta)
 	suite.Equal(1, len(meta.Capabilities))
 }
+
+// TestPollDBUpdate ...
+func (suite *ControllerTestSuite) TestPollDBUpdate() {
+	updatedAt := "2024-01-01T00:00:00Z"
+	mc := &v1testing.Client{}
+	mc.On("GetMetadata").Return(func() (*v1.ScannerAdapterMetadata, error) {
+		return &v1.ScannerAdapterMetadata{Properties: v1.ScannerProperties{v1.PropertyDBUpdatedAt: updatedAt}}, nil
+	})
+	mcp := &v1testing.ClientPool{}
+	mocktesting.OnAnything(mcp, "Get").Return(mc, nil)
+	suite.c.clientPool = mcp
+
+	suite.sample.UUID = "uuid"
+	suite.mMgr.On("Update", mock.Anything, suite.sample, "db_updated_at").Return(nil).Once()
+	suite.mMgr.On("Update", mock.Anything, suite.sample, "db_updated_at", "db_update_detected_at").Return(nil).Once()
+
+	// the first polled value is the baseline
+	detectedAt, err := suite.c.PollDBUpdate(context.TODO(), suite.sample)
+	suite.Require().NoError(err)
+	suite.True(detectedAt.IsZero())
+	suite.Equal(updatedAt, suite.sample.DBUpdatedAt)
+
+	// no change
+	detectedAt, err = suite.c.PollDBUpdate(context.TODO(), suite.sample)
+	suite.Require().NoError(err)
+	suite.True(detectedAt.IsZero())
+
+	updatedAt = "2024-01-02T00:00:00Z"
+	detectedAt, err = suite.c.PollDBUpdate(context.TODO(), suite.sample)
+	suite.Require().NoError(err)
+	suite.False(detectedAt.IsZero())
+	suite.Equal(detectedAt, suite.sample.DBUpdateDetectedAt)
+	suite.mMgr.AssertExpectations(suite.T())
+}
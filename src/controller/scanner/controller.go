@@ -16,6 +16,7 @@ package scanner
 
 import (
 	"context"
+	"time"
 
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
@@ -131,6 +132,31 @@ type Controller interface {
 	//     error                 : non nil error if any errors occurred
 	GetRegistrationByProject(ctx context.Context, projectID int64, options ...Option) (*scanner.Registration, error)
 
+	// SetRegistrationsByProject sets the scanners for the given project, the first one is set as the
+	// primary scanner of the project and the others are the additional scanners which scan the artifacts
+	// of the project side by side with the primary one.
+	//
+	//  Arguments:
+	//    ctx context.Context : the context.Context for this method
+	//    projectID int64  : the ID of the given project
+	//    scannerIDs []string : the UUIDs of the scanners
+	//
+	//  Returns:
+	//    error : non nil error if any errors occurred
+	SetRegistrationsByProject(ctx context.Context, projectID int64, scannerIDs []string) error
+
+	// GetRegistrationsByProject returns the scanner registrations of the given project, the primary one
+	// returned by GetRegistrationByProject comes first and followed by the additional ones.
+	//
+	//   Arguments:
+	//     ctx context.Context : the context.Context for this method
+	//     projectID int64 : the ID of the given project
+	//
+	//   Returns:
+	//     []*scanner.Registration : the scanner registrations, empty if no system registrations set
+	//     error                   : non nil error if any errors occurred
+	GetRegistrationsByProject(ctx context.Context, projectID int64, options ...Option) ([]*scanner.Registration, error)
+
 	// Ping pings Scanner Adapter to test EndpointURL and Authorization settings.
 	// The implementation is supposed to call the GetMetadata method on scanner.Client.
 	// Returns `nil` if connection succeeded, a non `nil` error otherwise.
@@ -157,4 +183,17 @@ type Controller interface {
 
 	// RetrieveCap retrieve scanner capabilities
 	RetrieveCap(ctx context.Context, r *scanner.Registration) error
+
+	// PollDBUpdate polls the adapter metadata of the given scanner and records the change of the
+	// updated_at property of its vulnerability database.
+	//
+	//  Arguments:
+	//    ctx context.Context : the context for this method
+	//    registration *scanner.Registration : the scanner registration to poll
+	//
+	//  Returns:
+	//    time.Time : the time the latest change was detected, zero if no change has been detected
+	//                since the property was polled the first time or the adapter doesn't report it
+	//    error     : non nil error if any errors occurred
+	PollDBUpdate(ctx context.Context, registration *scanner.Registration) (time.Time, error)
 }
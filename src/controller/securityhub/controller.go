@@ -17,6 +17,7 @@ package securityhub
 import (
 	"context"
 
+	sc "github.com/goharbor/harbor/src/controller/scanner"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/scan/scanner"
 	"github.com/goharbor/harbor/src/pkg/securityhub"
@@ -63,13 +64,14 @@ type Controller interface {
 	// SecuritySummary returns the security summary of the specified project.
 	SecuritySummary(ctx context.Context, projectID int64, options ...Option) (*secHubModel.Summary, error)
 	// ListVuls list vulnerabilities by query
-	ListVuls(ctx context.Context, scannerUUID string, projectID int64, withTag bool, query *q.Query) ([]*secHubModel.VulnerabilityItem, error)
+	ListVuls(ctx context.Context, projectID int64, withTag bool, query *q.Query) ([]*secHubModel.VulnerabilityItem, error)
 	// CountVuls get all vulnerability count by query
-	CountVuls(ctx context.Context, scannerUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error)
+	CountVuls(ctx context.Context, projectID int64, tuneCount bool, query *q.Query) (int64, error)
 }
 
 type controller struct {
 	scannerMgr scanner.Manager
+	scannerCtl sc.Controller
 	secHubMgr  securityhub.Manager
 	tagMgr     tag.Manager
 }
@@ -78,18 +80,41 @@ type controller struct {
 func NewController() Controller {
 	return &controller{
 		scannerMgr: scanner.Mgr,
+		scannerCtl: sc.DefaultController,
 		secHubMgr:  securityhub.Mgr,
 		tagMgr:     tag.Mgr,
 	}
 }
 
+// scannerUUIDs returns the UUIDs of the scanners whose reports are included, they are the scanners
+// of the project, or all the enabled scanners when no project is specified
+func (c *controller) scannerUUIDs(ctx context.Context, projectID int64) ([]string, error) {
+	var (
+		registrations []*sc.Registration
+		err           error
+	)
+	if projectID > 0 {
+		registrations, err = c.scannerCtl.GetRegistrationsByProject(ctx, projectID)
+	} else {
+		registrations, err = c.scannerMgr.List(ctx, q.New(q.KeyWords{"disabled": false}))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var uuids []string
+	for _, registration := range registrations {
+		uuids = append(uuids, registration.UUID)
+	}
+	return uuids, nil
+}
+
 func (c *controller) SecuritySummary(ctx context.Context, projectID int64, options ...Option) (*secHubModel.Summary, error) {
 	opts := newOptions(options...)
-	scannerUUID, err := c.scannerMgr.DefaultScannerUUID(ctx)
-	if len(scannerUUID) == 0 || err != nil {
+	scannerUUIDs, err := c.scannerUUIDs(ctx, projectID)
+	if len(scannerUUIDs) == 0 || err != nil {
 		return &secHubModel.Summary{}, nil
 	}
-	sum, err := c.secHubMgr.Summary(ctx, scannerUUID, projectID, nil)
+	sum, err := c.secHubMgr.Summary(ctx, scannerUUIDs, projectID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -97,18 +122,18 @@ func (c *controller) SecuritySummary(ctx context.Context, projectID int64, optio
 	if err != nil {
 		return nil, err
 	}
-	sum.ScannedCnt, err = c.secHubMgr.ScannedArtifactsCount(ctx, scannerUUID, projectID, nil)
+	sum.ScannedCnt, err = c.secHubMgr.ScannedArtifactsCount(ctx, scannerUUIDs, projectID, nil)
 	if err != nil {
 		return nil, err
 	}
 	if opts.WithCVE {
-		sum.DangerousCVEs, err = c.secHubMgr.DangerousCVEs(ctx, scannerUUID, projectID, nil)
+		sum.DangerousCVEs, err = c.secHubMgr.DangerousCVEs(ctx, scannerUUIDs, projectID, nil)
 		if err != nil {
 			return nil, err
 		}
 	}
 	if opts.WithArtifact {
-		sum.DangerousArtifacts, err = c.secHubMgr.DangerousArtifacts(ctx, scannerUUID, projectID, nil)
+		sum.DangerousArtifacts, err = c.secHubMgr.DangerousArtifacts(ctx, scannerUUIDs, projectID, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -117,19 +142,23 @@ func (c *controller) SecuritySummary(ctx context.Context, projectID int64, optio
 }
 
 func (c *controller) scannedArtifactCount(ctx context.Context, projectID int64) (int64, error) {
-	scannerUUID, err := c.scannerMgr.DefaultScannerUUID(ctx)
+	scannerUUIDs, err := c.scannerUUIDs(ctx, projectID)
 	if err != nil {
 		return 0, err
 	}
-	return c.secHubMgr.ScannedArtifactsCount(ctx, scannerUUID, projectID, nil)
+	return c.secHubMgr.ScannedArtifactsCount(ctx, scannerUUIDs, projectID, nil)
 }
 
 func (c *controller) totalArtifactCount(ctx context.Context, projectID int64) (int64, error) {
 	return c.secHubMgr.TotalArtifactsCount(ctx, projectID)
 }
 
-func (c *controller) ListVuls(ctx context.Context, scannerUUID string, projectID int64, withTag bool, query *q.Query) ([]*secHubModel.VulnerabilityItem, error) {
-	vuls, err := c.secHubMgr.ListVuls(ctx, scannerUUID, projectID, query)
+func (c *controller) ListVuls(ctx context.Context, projectID int64, withTag bool, query *q.Query) ([]*secHubModel.VulnerabilityItem, error) {
+	scannerUUIDs, err := c.scannerUUIDs(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	vuls, err := c.secHubMgr.ListVuls(ctx, scannerUUIDs, projectID, query)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +200,10 @@ func (c *controller) attachTags(ctx context.Context, vuls []*secHubModel.Vulnera
 	return vuls, nil
 }
 
-func (c *controller) CountVuls(ctx context.Context, scannerUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
-	return c.secHubMgr.TotalVuls(ctx, scannerUUID, projectID, tuneCount, query)
+func (c *controller) CountVuls(ctx context.Context, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
+	scannerUUIDs, err := c.scannerUUIDs(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	return c.secHubMgr.TotalVuls(ctx, scannerUUIDs, projectID, tuneCount, query)
 }
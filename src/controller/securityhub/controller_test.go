@@ -20,10 +20,13 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scan"
+	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
 	"github.com/goharbor/harbor/src/pkg/securityhub/model"
 	"github.com/goharbor/harbor/src/pkg/tag/model/tag"
 	htesting "github.com/goharbor/harbor/src/testing"
+	scannerCtlMock "github.com/goharbor/harbor/src/testing/controller/scanner"
 	"github.com/goharbor/harbor/src/testing/mock"
 	scannerMock "github.com/goharbor/harbor/src/testing/pkg/scan/scanner"
 	securityMock "github.com/goharbor/harbor/src/testing/pkg/securityhub"
@@ -43,6 +46,7 @@ type ControllerTestSuite struct {
 	htesting.Suite
 	c          *controller
 	scannerMgr *scannerMock.Manager
+	scannerCtl *scannerCtlMock.Controller
 	secHubMgr  *securityMock.Manager
 	tagMgr     *tagMock.Manager
 }
@@ -56,11 +60,13 @@ func TestController(t *testing.T) {
 func (suite *ControllerTestSuite) SetupTest() {
 	suite.secHubMgr = &securityMock.Manager{}
 	suite.scannerMgr = &scannerMock.Manager{}
+	suite.scannerCtl = &scannerCtlMock.Controller{}
 	suite.tagMgr = &tagMock.Manager{}
 
 	suite.c = &controller{
 		secHubMgr:  suite.secHubMgr,
 		scannerMgr: suite.scannerMgr,
+		scannerCtl: suite.scannerCtl,
 		tagMgr:     suite.tagMgr,
 	}
 }
@@ -75,7 +81,7 @@ func (suite *ControllerTestSuite) TestSecuritySummary() {
 	mock.OnAnything(suite.secHubMgr, "TotalArtifactsCount").Return(int64(1234), nil)
 	mock.OnAnything(suite.secHubMgr, "ScannedArtifactsCount").Return(int64(1000), nil)
 	mock.OnAnything(suite.secHubMgr, "Summary").Return(sum, nil).Twice()
-	mock.OnAnything(suite.scannerMgr, "DefaultScannerUUID").Return("ruuid", nil)
+	mock.OnAnything(suite.scannerMgr, "List").Return([]*scanner.Registration{{UUID: "ruuid"}}, nil)
 	summary, err := suite.c.SecuritySummary(ctx, 0, WithArtifact(false), WithCVE(false))
 	suite.NoError(err)
 	suite.NotNil(summary)
@@ -120,7 +126,7 @@ func (suite *ControllerTestSuite) TestSecuritySummary() {
 // TestSecuritySummaryError tests the security summary with error
 func (suite *ControllerTestSuite) TestSecuritySummaryError() {
 	ctx := suite.Context()
-	mock.OnAnything(suite.scannerMgr, "DefaultScannerUUID").Return("ruuid", nil)
+	mock.OnAnything(suite.scannerMgr, "List").Return([]*scanner.Registration{{UUID: "ruuid"}}, nil)
 	mock.OnAnything(suite.secHubMgr, "TotalArtifactsCount").Return(int64(0), errors.New("project not found")).Once()
 	mock.OnAnything(suite.secHubMgr, "ScannedArtifactsCount").Return(int64(1000), nil)
 	mock.OnAnything(suite.secHubMgr, "Summary").Return(nil, errors.New("invalid project")).Once()
@@ -137,7 +143,7 @@ func (suite *ControllerTestSuite) TestSecuritySummaryError() {
 
 func (suite *ControllerTestSuite) TestScannedArtifact() {
 	ctx := suite.Context()
-	mock.OnAnything(suite.scannerMgr, "DefaultScannerUUID").Return("ruuid", nil)
+	mock.OnAnything(suite.scannerMgr, "List").Return([]*scanner.Registration{{UUID: "ruuid"}}, nil)
 	mock.OnAnything(suite.secHubMgr, "ScannedArtifactsCount").Return(int64(1000), nil)
 	scanned, err := suite.c.scannedArtifactCount(ctx, 0)
 	suite.NoError(err)
@@ -180,9 +186,10 @@ func (suite *ControllerTestSuite) TestListVuls() {
 	tagList := []*tag.Tag{
 		{ArtifactID: int64(1), Name: "latest"},
 	}
-	mock.OnAnything(suite.c.secHubMgr, "ListVuls").Return(vulItems, nil)
+	mock.OnAnything(suite.scannerMgr, "List").Return([]*scanner.Registration{{UUID: "ruuid"}, {UUID: "uuid2"}}, nil)
+	suite.secHubMgr.On("ListVuls", ctx, []string{"ruuid", "uuid2"}, int64(0), (*q.Query)(nil)).Return(vulItems, nil)
 	mock.OnAnything(suite.c.tagMgr, "List").Return(tagList, nil).Once()
-	vulResult, err := suite.c.ListVuls(ctx, "", 0, true, nil)
+	vulResult, err := suite.c.ListVuls(ctx, 0, true, nil)
 	suite.NoError(err)
 	suite.Equal(1, len(vulResult))
 	suite.Equal(int64(1), vulResult[0].ArtifactID)
@@ -190,8 +197,25 @@ func (suite *ControllerTestSuite) TestListVuls() {
 
 func (suite *ControllerTestSuite) TestCountVuls() {
 	ctx := suite.Context()
+	mock.OnAnything(suite.scannerMgr, "List").Return([]*scanner.Registration{{UUID: "ruuid"}}, nil)
 	mock.OnAnything(suite.c.secHubMgr, "TotalVuls").Return(int64(10), nil)
-	count, err := suite.c.CountVuls(ctx, "", 0, true, nil)
+	count, err := suite.c.CountVuls(ctx, 0, true, nil)
 	suite.NoError(err)
 	suite.Equal(int64(10), count)
 }
+
+// TestScannerUUIDs tests the scanners whose reports are included
+func (suite *ControllerTestSuite) TestScannerUUIDs() {
+	ctx := suite.Context()
+	// all the enabled scanners
+	suite.scannerMgr.On("List", ctx, q.New(q.KeyWords{"disabled": false})).Return([]*scanner.Registration{{UUID: "ruuid"}, {UUID: "uuid2"}}, nil)
+	uuids, err := suite.c.scannerUUIDs(ctx, 0)
+	suite.NoError(err)
+	suite.Equal([]string{"ruuid", "uuid2"}, uuids)
+
+	// the scanners of the project
+	suite.scannerCtl.On("GetRegistrationsByProject", ctx, int64(1)).Return([]*scanner.Registration{{UUID: "uuid2"}}, nil)
+	uuids, err = suite.c.scannerUUIDs(ctx, 1)
+	suite.NoError(err)
+	suite.Equal([]string{"uuid2"}, uuids)
+}
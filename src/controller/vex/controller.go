@@ -0,0 +1,148 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vex
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/registry"
+	"github.com/goharbor/harbor/src/pkg/vex"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+)
+
+// maxDocumentSize is the max size of the VEX document read from the accessory
+const maxDocumentSize = 10 * 1024 * 1024
+
+// Ctl is a global VEX controller instance
+var Ctl = NewController()
+
+// Controller manages the VEX statements of the artifacts
+type Controller interface {
+	// Upload parses the VEX document uploaded via API and stores its statements about the artifact of the project,
+	// the statements of the same document uploaded before are replaced
+	Upload(ctx context.Context, projectID int64, artifactDigest string, document []byte) ([]*model.Statement, error)
+	// List the VEX statements of the project about the artifact
+	List(ctx context.Context, projectID int64, artifactDigest string) ([]*model.Statement, error)
+	// Delete the VEX statements of the project about the artifact uploaded via API
+	Delete(ctx context.Context, projectID int64, artifactDigest string) error
+	// Ingest reads the VEX documents in the layers of the accessory artifact and
+	// stores the statements about the subject artifact in the project of the accessory
+	Ingest(ctx context.Context, projectID int64, repository, accessoryDigest, subjectDigest string) error
+}
+
+// NewController creates an instance of the default VEX controller
+func NewController() Controller {
+	return &controller{
+		vexMgr: vex.Mgr,
+		regCli: registry.Cli,
+	}
+}
+
+type controller struct {
+	vexMgr vex.Manager
+	regCli registry.Client
+}
+
+func (c *controller) Upload(ctx context.Context, projectID int64, artifactDigest string, document []byte) ([]*model.Statement, error) {
+	statements, err := vex.Parse(document, artifactDigest)
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, errors.BadRequestError(nil).WithMessagef("the VEX document has no statement about the artifact %s", artifactDigest)
+	}
+	if err := c.replace(ctx, projectID, artifactDigest, model.SourceAPI, digest.FromBytes(document).String(), statements); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+func (c *controller) List(ctx context.Context, projectID int64, artifactDigest string) ([]*model.Statement, error) {
+	return c.vexMgr.ListByDigests(ctx, projectID, artifactDigest)
+}
+
+func (c *controller) Delete(ctx context.Context, projectID int64, artifactDigest string) error {
+	_, err := c.vexMgr.DeleteBy(ctx, q.New(q.KeyWords{
+		"project_id":      projectID,
+		"artifact_digest": artifactDigest,
+		"source":          model.SourceAPI,
+	}))
+	return err
+}
+
+func (c *controller) Ingest(ctx context.Context, projectID int64, repository, accessoryDigest, subjectDigest string) error {
+	man, _, err := c.regCli.PullManifest(repository, accessoryDigest)
+	if err != nil {
+		return errors.Wrap(err, "failed to pull manifest")
+	}
+	_, payload, err := man.Payload()
+	if err != nil {
+		return errors.Wrap(err, "failed to get payload")
+	}
+	manifest := &ocispec.Manifest{}
+	if err := json.Unmarshal(payload, manifest); err != nil {
+		return err
+	}
+	var statements []*model.Statement
+	for _, layer := range manifest.Layers {
+		if layer.Size > maxDocumentSize {
+			return errors.Errorf("the VEX document %s exceeds the size limit", layer.Digest)
+		}
+		_, blob, err := c.regCli.PullBlob(repository, layer.Digest.String())
+		if err != nil {
+			return errors.Wrap(err, "failed to pull the blob")
+		}
+		document, err := io.ReadAll(io.LimitReader(blob, maxDocumentSize))
+		blob.Close()
+		if err != nil {
+			return err
+		}
+		stmts, err := vex.Parse(document, subjectDigest)
+		if err != nil {
+			return err
+		}
+		statements = append(statements, stmts...)
+	}
+	return c.replace(ctx, projectID, subjectDigest, model.SourceAccessory, accessoryDigest, statements)
+}
+
+// replace the statements coming from the same source document
+func (c *controller) replace(ctx context.Context, projectID int64, artifactDigest, source, sourceDigest string, statements []*model.Statement) error {
+	for _, s := range statements {
+		s.ProjectID = projectID
+		s.ArtifactDigest = artifactDigest
+		s.Source = source
+		s.SourceDigest = sourceDigest
+	}
+	return orm.WithTransaction(func(ctx context.Context) error {
+		if _, err := c.vexMgr.DeleteBy(ctx, q.New(q.KeyWords{
+			"project_id":      projectID,
+			"artifact_digest": artifactDigest,
+			"source":          source,
+			"source_digest":   sourceDigest,
+		})); err != nil {
+			return err
+		}
+		return c.vexMgr.Create(ctx, statements...)
+	})(orm.SetTransactionOpNameToContext(ctx, "tx-replace-vex-statements"))
+}
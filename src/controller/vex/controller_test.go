@@ -0,0 +1,126 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vex
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+	ormtesting "github.com/goharbor/harbor/src/testing/lib/orm"
+	"github.com/goharbor/harbor/src/testing/mock"
+	"github.com/goharbor/harbor/src/testing/pkg/registry"
+	vextesting "github.com/goharbor/harbor/src/testing/pkg/vex"
+)
+
+const (
+	subjectDigest   = "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"
+	accessoryDigest = "sha256:ee1d00c5250b5a886b09be2d5f9506add35dfb557f1ef37a7e4b8f0138f32956"
+	openVEXDocument = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/1",
+  "statements": [
+    {"vulnerability": {"name": "CVE-2023-0001"}, "products": [{"@id": "pkg:deb/debian/openssl@3.0.11"}], "status": "not_affected"}
+  ]
+}`
+)
+
+type controllerTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	ctl    *controller
+	vexMgr *vextesting.Manager
+	regCli *registry.Client
+}
+
+func (c *controllerTestSuite) SetupTest() {
+	c.ctx = orm.NewContext(context.Background(), &ormtesting.FakeOrmer{})
+	c.vexMgr = &vextesting.Manager{}
+	c.regCli = &registry.Client{}
+	c.ctl = &controller{
+		vexMgr: c.vexMgr,
+		regCli: c.regCli,
+	}
+}
+
+// sourceOf matches the query deleting the statements of the source document
+func sourceOf(source, sourceDigest string) interface{} {
+	return testifymock.MatchedBy(func(query *q.Query) bool {
+		return query.Keywords["project_id"] == int64(1) &&
+			query.Keywords["artifact_digest"] == subjectDigest &&
+			query.Keywords["source"] == source &&
+			(len(sourceDigest) == 0 || query.Keywords["source_digest"] == sourceDigest)
+	})
+}
+
+func (c *controllerTestSuite) TestUpload() {
+	c.vexMgr.On("DeleteBy", mock.Anything, sourceOf(model.SourceAPI, "")).Return(int64(1), nil).Once()
+	c.vexMgr.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+	statements, err := c.ctl.Upload(c.ctx, 1, subjectDigest, []byte(openVEXDocument))
+	c.Require().NoError(err)
+	c.Require().Len(statements, 1)
+	c.Equal(int64(1), statements[0].ProjectID)
+	c.Equal(subjectDigest, statements[0].ArtifactDigest)
+	c.Equal(model.SourceAPI, statements[0].Source)
+	c.NotEmpty(statements[0].SourceDigest)
+	c.Equal("openssl", statements[0].Package)
+	c.vexMgr.AssertExpectations(c.T())
+
+	// the document about another artifact
+	_, err = c.ctl.Upload(c.ctx, 1, subjectDigest, []byte(`{"statements": [{"vulnerability": {"name": "CVE-2023-0001"},
+		"products": [{"@id": "pkg:oci/nginx@`+accessoryDigest+`"}], "status": "not_affected"}]}`))
+	c.True(errors.IsErr(err, errors.BadRequestCode))
+}
+
+func (c *controllerTestSuite) TestDelete() {
+	c.vexMgr.On("DeleteBy", mock.Anything, sourceOf(model.SourceAPI, "")).Return(int64(1), nil).Once()
+	c.NoError(c.ctl.Delete(c.ctx, 1, subjectDigest))
+	c.vexMgr.AssertExpectations(c.T())
+}
+
+func (c *controllerTestSuite) TestIngest() {
+	manifest := `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.oci.image.manifest.v1+json",
+  "artifactType": "application/vnd.openvex+json",
+  "config": {"mediaType": "application/vnd.oci.empty.v1+json", "digest": "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", "size": 2},
+  "layers": [{"mediaType": "application/vnd.openvex+json", "digest": "sha256:abc", "size": 321}]
+}`
+	mani, _, err := distribution.UnmarshalManifest(v1.MediaTypeImageManifest, []byte(manifest))
+	c.Require().NoError(err)
+	c.regCli.On("PullManifest", "library/nginx", accessoryDigest).Return(mani, accessoryDigest, nil).Once()
+	c.regCli.On("PullBlob", "library/nginx", "sha256:abc").Return(int64(321), io.NopCloser(strings.NewReader(openVEXDocument)), nil).Once()
+	c.vexMgr.On("DeleteBy", mock.Anything, sourceOf(model.SourceAccessory, accessoryDigest)).Return(int64(0), nil).Once()
+	c.vexMgr.On("Create", mock.Anything, testifymock.MatchedBy(func(s *model.Statement) bool {
+		return s.ProjectID == 1 && s.SourceDigest == accessoryDigest && s.ArtifactDigest == subjectDigest && s.CVEID == "CVE-2023-0001"
+	})).Return(nil).Once()
+	c.Require().NoError(c.ctl.Ingest(c.ctx, 1, "library/nginx", accessoryDigest, subjectDigest))
+	c.regCli.AssertExpectations(c.T())
+	c.vexMgr.AssertExpectations(c.T())
+}
+
+func TestController(t *testing.T) {
+	suite.Run(t, &controllerTestSuite{})
+}
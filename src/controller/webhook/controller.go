@@ -16,13 +16,19 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"time"
 
+	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/notification/policy"
 	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
+	"github.com/goharbor/harbor/src/pkg/notifier/formats"
+	notifierModel "github.com/goharbor/harbor/src/pkg/notifier/model"
 	"github.com/goharbor/harbor/src/pkg/task"
 )
 
@@ -30,10 +36,30 @@ var (
 	// Ctl is a global webhook controller instance
 	Ctl = NewController()
 
-	// webhookJobVendors represents webhook(http) or slack.
-	webhookJobVendors = q.NewOrList([]interface{}{job.WebhookJobVendorType, job.SlackJobVendorType})
+	// webhookJobVendors represents the jobs sending the notifications, e.g. webhook(http), slack or email.
+	webhookJobVendors = newWebhookJobVendors()
+
+	// redeliverableVendorTypes are the vendor types of the jobs posting the formatted payloads with
+	// the signature, their executions can be re-delivered
+	redeliverableVendorTypes = map[string]bool{
+		job.WebhookJobVendorType:    true,
+		job.TeamsJobVendorType:      true,
+		job.GoogleChatJobVendorType: true,
+		job.CustomJobVendorType:     true,
+	}
 )
 
+// signingSecretLength is the length of the generated signing secret
+const signingSecretLength = 32
+
+func newWebhookJobVendors() *q.OrList {
+	var vendors []interface{}
+	for _, vt := range notifierModel.NotificationVendorTypes() {
+		vendors = append(vendors, vt)
+	}
+	return q.NewOrList(vendors)
+}
+
 type Controller interface {
 	// CreatePolicy creates webhook policy
 	CreatePolicy(ctx context.Context, policy *model.Policy) (int64, error)
@@ -65,6 +91,15 @@ type Controller interface {
 
 	// GetLastTriggerTime gets policy last trigger time group by event type
 	GetLastTriggerTime(ctx context.Context, eventType string, policyID int64) (time.Time, error)
+
+	// RotateSigningSecret generates a new secret to sign the payloads of the webhook policy and returns it,
+	// the replaced secret keeps signing the payloads during the overlap
+	RotateSigningSecret(ctx context.Context, policyID int64, overlap time.Duration) (string, error)
+	// DisableSigning removes the signing secrets of the webhook policy
+	DisableSigning(ctx context.Context, policyID int64) error
+	// RedeliverExecution re-delivers the payload of the webhook execution with a fresh signature
+	// and returns the ID of the new execution
+	RedeliverExecution(ctx context.Context, policyID int64, execID int64) (int64, error)
 }
 
 type controller struct {
@@ -102,13 +137,11 @@ func (c *controller) UpdatePolicy(ctx context.Context, policy *model.Policy) err
 }
 
 func (c *controller) DeletePolicy(ctx context.Context, policyID int64) error {
-	// delete executions under the webhook policy,
-	// there are two vendor types(webhook & slack) needs to be deleted.
-	if err := c.execMgr.DeleteByVendor(ctx, job.WebhookJobVendorType, policyID); err != nil {
-		return errors.Wrapf(err, "failed to delete executions for webhook of policy %d", policyID)
-	}
-	if err := c.execMgr.DeleteByVendor(ctx, job.SlackJobVendorType, policyID); err != nil {
-		return errors.Wrapf(err, "failed to delete executions for slack of policy %d", policyID)
+	// delete executions of all the notification vendor types under the webhook policy
+	for _, vendorType := range notifierModel.NotificationVendorTypes() {
+		if err := c.execMgr.DeleteByVendor(ctx, vendorType, policyID); err != nil {
+			return errors.Wrapf(err, "failed to delete executions for %s of policy %d", vendorType, policyID)
+		}
 	}
 
 	return c.policyMgr.Delete(ctx, policyID)
@@ -193,3 +226,131 @@ func (c *controller) GetLastTriggerTime(ctx context.Context, eventType string, p
 
 	return time.Time{}, nil
 }
+
+func (c *controller) RotateSigningSecret(ctx context.Context, policyID int64, overlap time.Duration) (string, error) {
+	secret := utils.GenerateRandomStringWithLen(signingSecretLength)
+	if err := c.policyMgr.RotateSigningSecret(ctx, policyID, secret, overlap); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (c *controller) DisableSigning(ctx context.Context, policyID int64) error {
+	return c.policyMgr.RotateSigningSecret(ctx, policyID, "", 0)
+}
+
+func (c *controller) RedeliverExecution(ctx context.Context, policyID int64, execID int64) (int64, error) {
+	exec, err := c.execMgr.Get(ctx, execID)
+	if err != nil {
+		return 0, err
+	}
+	if exec.VendorID != policyID || len(notifierModel.NotifyTypeOf(exec.VendorType)) == 0 {
+		return 0, errors.NotFoundError(nil).WithMessagef("webhook execution %d not found in policy %d", execID, policyID)
+	}
+	if !redeliverableVendorTypes[exec.VendorType] {
+		return 0, errors.BadRequestError(nil).WithMessagef("the execution of %s can not be re-delivered", exec.VendorType)
+	}
+
+	payload := extraAttrString(exec, "payload")
+	address := extraAttrString(exec, "address")
+	if len(payload) == 0 {
+		return 0, errors.BadRequestError(nil).WithMessagef("the execution %d doesn't record the payload to re-deliver", execID)
+	}
+
+	ply, err := c.policyMgr.Get(ctx, policyID)
+	if err != nil {
+		return 0, err
+	}
+	target, err := redeliveryTarget(ply, exec, address)
+	if err != nil {
+		return 0, err
+	}
+	address = target.Address
+
+	header := http.Header{}
+	header.Set("Content-Type", formats.ContentType(target, []byte(payload)))
+	if len(target.AuthHeader) > 0 {
+		header.Set("Authorization", target.AuthHeader)
+	}
+	header.Set(signature.HeaderRedelivery, "true")
+	// the payload is signed with the latest secrets right before sending, only the policy
+	// is passed to the job which gets the signatures from the core
+	secrets, err := c.policyMgr.GetSigningSecrets(ctx, policyID)
+	if err != nil {
+		return 0, err
+	}
+	deliveryID := extraAttrString(exec, "delivery_id")
+	headerBytes, err := json.Marshal(header)
+	if err != nil {
+		return 0, err
+	}
+
+	extraAttrs := map[string]interface{}{
+		"event_type":    extraAttrString(exec, "event_type"),
+		"payload":       payload,
+		"address":       address,
+		"redelivery_of": execID,
+	}
+	if len(deliveryID) > 0 {
+		extraAttrs["delivery_id"] = deliveryID
+	}
+	params := map[string]interface{}{
+		"payload":          payload,
+		"address":          address,
+		"header":           string(headerBytes),
+		"skip_cert_verify": target.SkipCertVerify,
+		"delivery_id":      deliveryID,
+	}
+	if len(secrets) > 0 {
+		params["signing_policy_id"] = policyID
+	}
+	id, err := c.execMgr.Create(ctx, exec.VendorType, policyID, task.ExecutionTriggerManual, extraAttrs)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = c.taskMgr.Create(ctx, id, &task.Job{
+		Name: exec.VendorType,
+		Metadata: &job.Metadata{
+			JobKind: job.KindGeneric,
+		},
+		Parameters: params,
+	}); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// redeliveryTarget returns the target of the policy to re-deliver to, the target must still
+// exist in the policy to get its latest credential. The executions created before the address
+// is recorded are re-delivered to the only target of the notify type in the policy
+func redeliveryTarget(ply *model.Policy, exec *task.Execution, address string) (*model.EventTarget, error) {
+	notifyType := notifierModel.NotifyTypeOf(exec.VendorType)
+	var target *model.EventTarget
+	for i, t := range ply.Targets {
+		if t.Type != notifyType {
+			continue
+		}
+		if len(address) > 0 {
+			if t.Address == address {
+				return &ply.Targets[i], nil
+			}
+			continue
+		}
+		if target != nil {
+			return nil, errors.BadRequestError(nil).WithMessagef("the execution %d doesn't record the address and policy %d has multiple %s targets", exec.ID, ply.ID, notifyType)
+		}
+		target = &ply.Targets[i]
+	}
+	if target == nil {
+		return nil, errors.NotFoundError(nil).WithMessagef("the target of the execution %d is removed from policy %d", exec.ID, ply.ID)
+	}
+	return target, nil
+}
+
+// extraAttrString returns the string value of the extra attribute of the execution
+func extraAttrString(exec *task.Execution, key string) string {
+	if v, ok := exec.ExtraAttrs[key].(string); ok {
+		return v
+	}
+	return ""
+}
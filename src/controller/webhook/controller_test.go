@@ -16,12 +16,15 @@ package webhook
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 	task_model "github.com/goharbor/harbor/src/pkg/task"
 	"github.com/goharbor/harbor/src/testing/mock"
 	"github.com/goharbor/harbor/src/testing/pkg/notification/policy"
@@ -164,3 +167,108 @@ func (c *controllerTestSuite) TestGetLastTriggerTime() {
 	c.NoError(err)
 	c.Equal(now, time)
 }
+
+func (c *controllerTestSuite) TestRotateSigningSecret() {
+	c.policyMgr.On("RotateSigningSecret", mock.Anything, int64(1), mock.Anything, time.Hour).Return(nil)
+	secret, err := c.ctl.RotateSigningSecret(context.TODO(), 1, time.Hour)
+	c.NoError(err)
+	c.Len(secret, signingSecretLength)
+	c.policyMgr.AssertCalled(c.T(), "RotateSigningSecret", mock.Anything, int64(1), secret, time.Hour)
+}
+
+func (c *controllerTestSuite) TestDisableSigning() {
+	c.policyMgr.On("RotateSigningSecret", mock.Anything, int64(1), "", time.Duration(0)).Return(nil)
+	c.NoError(c.ctl.DisableSigning(context.TODO(), 1))
+	c.policyMgr.AssertExpectations(c.T())
+}
+
+func (c *controllerTestSuite) TestRedeliverExecution() {
+	// execution of another policy
+	c.execMgr.On("Get", mock.Anything, int64(1)).Return(&task_model.Execution{ID: 1, VendorType: "WEBHOOK", VendorID: 2}, nil).Once()
+	_, err := c.ctl.RedeliverExecution(context.TODO(), 1, 1)
+	c.Error(err)
+
+	// slack can not be re-delivered
+	c.execMgr.On("Get", mock.Anything, int64(1)).Return(&task_model.Execution{ID: 1, VendorType: "SLACK", VendorID: 1}, nil).Once()
+	_, err = c.ctl.RedeliverExecution(context.TODO(), 1, 1)
+	c.Error(err)
+
+	c.execMgr.On("Get", mock.Anything, int64(1)).Return(&task_model.Execution{
+		ID:         1,
+		VendorType: "WEBHOOK",
+		VendorID:   1,
+		ExtraAttrs: map[string]interface{}{
+			"event_type":  "PUSH_ARTIFACT",
+			"payload":     `{"type":"PUSH_ARTIFACT"}`,
+			"address":     "http://127.0.0.1/hook",
+			"delivery_id": "delivery-1",
+		},
+	}, nil)
+	c.policyMgr.On("Get", mock.Anything, int64(1)).Return(&model.Policy{ID: 1, Targets: []model.EventTarget{
+		{Type: "http", Address: "http://127.0.0.1/hook", AuthHeader: "token", SkipCertVerify: true},
+	}}, nil)
+	c.policyMgr.On("GetSigningSecrets", mock.Anything, int64(1)).Return([]string{"secret"}, nil)
+	var attrs map[string]interface{}
+	c.execMgr.On("Create", mock.Anything, "WEBHOOK", int64(1), task_model.ExecutionTriggerManual, mock.Anything).Run(func(args mock.Arguments) {
+		attrs = args.Get(4).(map[string]interface{})
+	}).Return(int64(2), nil)
+	var job *task_model.Job
+	c.taskMgr.On("Create", mock.Anything, int64(2), mock.Anything).Run(func(args mock.Arguments) {
+		job = args.Get(2).(*task_model.Job)
+	}).Return(int64(3), nil)
+	id, err := c.ctl.RedeliverExecution(context.TODO(), 1, 1)
+	c.Require().NoError(err)
+	c.Equal(int64(2), id)
+	c.Equal(int64(1), attrs["redelivery_of"])
+	c.Equal("delivery-1", attrs["delivery_id"])
+	c.Require().NotNil(job)
+	c.Equal("WEBHOOK", job.Name)
+	c.Equal(`{"type":"PUSH_ARTIFACT"}`, job.Parameters["payload"])
+	c.Equal(true, job.Parameters["skip_cert_verify"])
+	header := http.Header{}
+	c.Require().NoError(json.Unmarshal([]byte(job.Parameters["header"].(string)), &header))
+	c.Equal("token", header.Get("Authorization"))
+	c.Equal("true", header.Get(signature.HeaderRedelivery))
+	c.Equal("delivery-1", job.Parameters["delivery_id"])
+	c.Equal(int64(1), job.Parameters["signing_policy_id"])
+	c.NotContains(job.Parameters, "signing_secrets")
+
+	// the execution created before the address is recorded
+	c.execMgr.On("Get", mock.Anything, int64(4)).Return(&task_model.Execution{
+		ID:         4,
+		VendorType: "WEBHOOK",
+		VendorID:   1,
+		ExtraAttrs: map[string]interface{}{
+			"event_type": "PUSH_ARTIFACT",
+			"payload":    `{"type":"PUSH_ARTIFACT"}`,
+		},
+	}, nil)
+	_, err = c.ctl.RedeliverExecution(context.TODO(), 1, 4)
+	c.Require().NoError(err)
+	c.Equal("http://127.0.0.1/hook", attrs["address"])
+	c.Equal("http://127.0.0.1/hook", job.Parameters["address"])
+}
+
+func (c *controllerTestSuite) TestRedeliveryTarget() {
+	ply := &model.Policy{ID: 1, Targets: []model.EventTarget{
+		{Type: "http", Address: "http://127.0.0.1/a"},
+		{Type: "http", Address: "http://127.0.0.1/b"},
+		{Type: "slack", Address: "http://127.0.0.1/c"},
+	}}
+	exec := &task_model.Execution{ID: 1, VendorType: "WEBHOOK"}
+
+	target, err := redeliveryTarget(ply, exec, "http://127.0.0.1/b")
+	c.Require().NoError(err)
+	c.Equal("http://127.0.0.1/b", target.Address)
+
+	_, err = redeliveryTarget(ply, exec, "http://127.0.0.1/c")
+	c.True(errors.IsNotFoundErr(err))
+
+	// the address isn't recorded and the target is ambiguous
+	_, err = redeliveryTarget(ply, exec, "")
+	c.True(errors.IsErr(err, errors.BadRequestCode))
+
+	target, err = redeliveryTarget(ply, &task_model.Execution{ID: 2, VendorType: "SLACK"}, "")
+	c.Require().NoError(err)
+	c.Equal("http://127.0.0.1/c", target.Address)
+}
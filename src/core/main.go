@@ -49,6 +49,7 @@ import (
 	_ "github.com/goharbor/harbor/src/lib/cache/memory" // memory cache
 	_ "github.com/goharbor/harbor/src/lib/cache/redis"  // redis cache
 	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/encrypt"
 	"github.com/goharbor/harbor/src/lib/gtask"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/metric"
@@ -62,7 +63,9 @@ import (
 	_ "github.com/goharbor/harbor/src/pkg/accessory/model/nydus"
 	_ "github.com/goharbor/harbor/src/pkg/accessory/model/sbom"
 	_ "github.com/goharbor/harbor/src/pkg/accessory/model/subject"
+	_ "github.com/goharbor/harbor/src/pkg/accessory/model/vex"
 	"github.com/goharbor/harbor/src/pkg/audit"
+	"github.com/goharbor/harbor/src/pkg/audit/forwarder"
 	dbCfg "github.com/goharbor/harbor/src/pkg/config/db"
 	_ "github.com/goharbor/harbor/src/pkg/config/inmemory"
 	"github.com/goharbor/harbor/src/pkg/notification"
@@ -232,12 +235,17 @@ func main() {
 
 	closing := make(chan struct{})
 	done := make(chan struct{})
-	go gracefulShutdown(closing, done, shutdownTracerProvider)
+	go gracefulShutdown(closing, done, shutdownTracerProvider, forwarder.Mgr.Close)
 	// Start health checker for registries
 	go registry.Ctl.StartRegularHealthCheck(orm.Context(), closing, done)
 	// Init audit log
 	auditEP := config.AuditLogForwardEndpoint(ctx)
 	audit.LogMgr.Init(ctx, auditEP)
+	if cfgs, err := forwarder.LoadConfigs(config.AuditLogForwarders(ctx), encrypt.Instance()); err != nil {
+		log.Errorf("failed to parse the audit log forwarders: %v", err)
+	} else if err = forwarder.Mgr.Init(ctx, cfgs); err != nil {
+		log.Errorf("failed to initialize the audit log forwarders: %v", err)
+	}
 
 	log.Info("initializing notification...")
 	notification.Init()
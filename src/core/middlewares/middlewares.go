@@ -23,6 +23,7 @@ import (
 	"github.com/goharbor/harbor/src/pkg/distribution"
 	"github.com/goharbor/harbor/src/server/middleware"
 	"github.com/goharbor/harbor/src/server/middleware/artifactinfo"
+	"github.com/goharbor/harbor/src/server/middleware/audit"
 	"github.com/goharbor/harbor/src/server/middleware/csrf"
 	"github.com/goharbor/harbor/src/server/middleware/log"
 	"github.com/goharbor/harbor/src/server/middleware/mergeslash"
@@ -74,6 +75,7 @@ var (
 		middleware.MethodAndPathSkipper(http.MethodPost, match("^/service/notifications/jobs/replication/task/"+numericRegexp.String())),
 		middleware.MethodAndPathSkipper(http.MethodPost, match("^/service/notifications/jobs/retention/task/"+numericRegexp.String())),
 		middleware.MethodAndPathSkipper(http.MethodPost, match("^/service/notifications/jobs/schedules/"+numericRegexp.String())),
+		middleware.MethodAndPathSkipper(http.MethodPost, match("^/service/webhook/policies/"+numericRegexp.String()+"/signature")),
 		// Harbor doesn't handle the POST request to /service/token. beego framework return 405 for the POST request
 		// some client, such as containerd, may send the POST request to /service/token and depends on 405/404/401/400 return code to determine continue or not
 		// the read only middleware returns 403 before the beego framework, so skip this request to make the client continue
@@ -99,6 +101,7 @@ func MiddleWares() []web.MiddleWare {
 		artifactinfo.Middleware(),
 		security.Middleware(pingSkipper),
 		security.UnauthorizedMiddleware(),
+		audit.Middleware(pingSkipper),
 		readonly.Middleware(readonlySkippers...),
 	}
 }
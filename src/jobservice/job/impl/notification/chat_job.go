@@ -0,0 +1,34 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+// The jobs below post the payload rendered by the notifier to the target address in the same
+// way as the WebhookJob, they are registered with dedicated names so that the executions of the
+// different notify types can be told apart.
+
+// TeamsJob sends the notification to Microsoft Teams.
+type TeamsJob struct {
+	WebhookJob
+}
+
+// GoogleChatJob sends the notification to Google Chat.
+type GoogleChatJob struct {
+	WebhookJob
+}
+
+// CustomJob sends the notification rendered by the custom template.
+type CustomJob struct {
+	WebhookJob
+}
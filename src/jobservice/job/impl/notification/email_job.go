@@ -0,0 +1,196 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/common/utils/email"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+// emailTimeout is the timeout in seconds to talk with the SMTP server
+const emailTimeout = 60
+
+// EmailJob implements the job interface, which send notification by email.
+// The settings of the SMTP server are read from the configurations of Harbor
+// rather than the job parameters to avoid persisting the credentials in the job.
+type EmailJob struct {
+	logger logger.Interface
+	ctx    job.Context
+}
+
+// smtpSetting wraps the settings of the SMTP server
+type smtpSetting struct {
+	host     string
+	port     int
+	username string
+	password string
+	identity string
+	from     string
+	ssl      bool
+	insecure bool
+}
+
+// MaxFails returns that how many times this job can fail.
+func (ej *EmailJob) MaxFails() (result uint) {
+	// Default max fails count is 3
+	result = 3
+	if maxFails, exist := os.LookupEnv(maxFails); exist {
+		mf, err := strconv.ParseUint(maxFails, 10, 32)
+		if err != nil {
+			logger.Warningf("Fetch email job maxFails error: %s", err.Error())
+			return result
+		}
+		result = uint(mf)
+	}
+	return result
+}
+
+// MaxCurrency is implementation of same method in Interface.
+func (ej *EmailJob) MaxCurrency() uint {
+	return 0
+}
+
+// ShouldRetry ...
+func (ej *EmailJob) ShouldRetry() bool {
+	return true
+}
+
+// Validate implements the interface in job/Interface
+func (ej *EmailJob) Validate(params job.Parameters) error {
+	if params == nil {
+		// Params are required
+		return errors.New("missing parameter of email job")
+	}
+
+	for _, key := range []string{"payload", "address", "subject"} {
+		value, ok := params[key]
+		if !ok {
+			return errors.Errorf("missing job parameter '%s'", key)
+		}
+		if _, ok = value.(string); !ok {
+			return errors.Errorf("malformed job parameter '%s', expecting string but got %s", key, reflect.TypeOf(value).String())
+		}
+	}
+
+	if len(splitRecipients(params["address"].(string))) == 0 {
+		return errors.New("no recipient specified in job parameter 'address'")
+	}
+	return nil
+}
+
+// Run implements the interface in job/Interface
+func (ej *EmailJob) Run(ctx job.Context, params job.Parameters) error {
+	ej.ctx = ctx
+	ej.logger = ctx.GetLogger()
+
+	ej.logger.Info("start to run email job")
+
+	err := ej.execute(params)
+	if err != nil {
+		ej.logger.Errorf("exit email job, error: %s", err)
+	} else {
+		ej.logger.Info("success to run email job")
+	}
+	return err
+}
+
+// execute email job
+func (ej *EmailJob) execute(params map[string]interface{}) error {
+	setting, err := ej.smtpSetting()
+	if err != nil {
+		return err
+	}
+
+	recipients := splitRecipients(params["address"].(string))
+	subject := params["subject"].(string)
+	payload := params["payload"].(string)
+
+	addr := net.JoinHostPort(setting.host, strconv.Itoa(setting.port))
+	ej.logger.Infof("send email to %v via SMTP server %s, subject: %s", recipients, addr, subject)
+
+	return email.Send(addr, setting.identity, setting.username, setting.password, emailTimeout,
+		setting.ssl, setting.insecure, setting.from, recipients, subject, payload)
+}
+
+// smtpSetting reads the settings of the SMTP server from the job context
+func (ej *EmailJob) smtpSetting() (*smtpSetting, error) {
+	setting := &smtpSetting{
+		host:     ej.getString(common.EmailHost),
+		username: ej.getString(common.EmailUsername),
+		password: ej.getString(common.EmailPassword),
+		identity: ej.getString(common.EmailIdentity),
+		from:     ej.getString(common.EmailFrom),
+		ssl:      ej.getBool(common.EmailSSL),
+		insecure: ej.getBool(common.EmailInsecure),
+		port:     25,
+	}
+	if len(setting.host) == 0 {
+		return nil, errors.New("the SMTP server is not configured")
+	}
+	if len(setting.from) == 0 {
+		return nil, errors.New("the sender of the email is not configured")
+	}
+	if v, ok := ej.ctx.Get(common.EmailPort); ok {
+		port, err := strconv.Atoi(fmt.Sprint(v))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid SMTP server port %v", v)
+		}
+		setting.port = port
+	}
+	return setting, nil
+}
+
+func (ej *EmailJob) getString(key string) string {
+	if v, ok := ej.ctx.Get(key); ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func (ej *EmailJob) getBool(key string) bool {
+	v, ok := ej.ctx.Get(key)
+	if !ok {
+		return false
+	}
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		parsed, _ := strconv.ParseBool(b)
+		return parsed
+	}
+	return false
+}
+
+// splitRecipients splits the comma separated recipients
+func splitRecipients(address string) []string {
+	var recipients []string
+	for _, r := range strings.Split(address, ",") {
+		if r = strings.TrimSpace(r); len(r) > 0 {
+			recipients = append(recipients, r)
+		}
+	}
+	return recipients
+}
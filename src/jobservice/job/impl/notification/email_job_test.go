@@ -0,0 +1,157 @@
+package notification
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/common"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
+	"github.com/goharbor/harbor/src/testing/mock"
+)
+
+func TestEmailJobMaxFails(t *testing.T) {
+	ej := &EmailJob{}
+	t.Run("default max fails", func(t *testing.T) {
+		assert.Equal(t, uint(3), ej.MaxFails())
+	})
+
+	t.Run("user defined max fails", func(t *testing.T) {
+		t.Setenv(maxFails, "15")
+		assert.Equal(t, uint(15), ej.MaxFails())
+	})
+}
+
+func TestEmailJobShouldRetry(t *testing.T) {
+	ej := &EmailJob{}
+	assert.True(t, ej.ShouldRetry())
+}
+
+func TestEmailJobValidate(t *testing.T) {
+	ej := &EmailJob{}
+	assert.NotNil(t, ej.Validate(nil))
+
+	jp := job.Parameters{
+		"address": "dev@example.com, ops@example.com",
+		"subject": "[Harbor] Scanning failed",
+		"payload": "<p>email payload</p>",
+	}
+	assert.Nil(t, ej.Validate(jp))
+
+	jp["address"] = " , "
+	assert.NotNil(t, ej.Validate(jp))
+
+	delete(jp, "subject")
+	assert.NotNil(t, ej.Validate(jp))
+}
+
+// fakeSMTPServer accepts one mail and records the recipients and the data
+type fakeSMTPServer struct {
+	listener   net.Listener
+	recipients []string
+	data       []string
+	done       chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	s := &fakeSMTPServer{listener: l, done: make(chan struct{})}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(msg string) { _, _ = conn.Write([]byte(msg + "\r\n")) }
+	reply("220 localhost ESMTP")
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if inData {
+			if line == "." {
+				inData = false
+				reply("250 OK")
+				continue
+			}
+			s.data = append(s.data, line)
+			continue
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "DATA"):
+			inData = true
+			reply("354 go ahead")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestEmailJobRun(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.listener.Close()
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	require.Nil(t, err)
+	portNum, _ := strconv.Atoi(port)
+
+	ctx := &mockjobservice.MockJobContext{}
+	ctx.On("GetLogger").Return(&mockjobservice.MockJobLogger{})
+	ctx.On("Get", common.EmailHost).Return(host, true)
+	ctx.On("Get", common.EmailPort).Return(portNum, true)
+	ctx.On("Get", common.EmailFrom).Return("harbor@example.com", true)
+	ctx.On("Get", mock.Anything).Return(nil, false)
+
+	ej := &EmailJob{}
+	params := job.Parameters{
+		"address": "dev@example.com,ops@example.com",
+		"subject": "[Harbor] Scanning failed",
+		"payload": "<p>email payload</p>",
+	}
+	require.Nil(t, ej.Run(ctx, params))
+	<-server.done
+
+	assert.Equal(t, []string{"dev@example.com", "ops@example.com"}, server.recipients)
+	data := strings.Join(server.data, "\n")
+	assert.Contains(t, data, "Subject: [Harbor] Scanning failed")
+	assert.Contains(t, data, "<p>email payload</p>")
+}
+
+func TestEmailJobRunWithoutSMTPServer(t *testing.T) {
+	ctx := &mockjobservice.MockJobContext{}
+	ctx.On("GetLogger").Return(&mockjobservice.MockJobLogger{})
+	ctx.On("Get", mock.Anything).Return(nil, false)
+
+	ej := &EmailJob{}
+	params := job.Parameters{
+		"address": "dev@example.com",
+		"subject": "subject",
+		"payload": "payload",
+	}
+	assert.NotNil(t, ej.Run(ctx, params))
+}
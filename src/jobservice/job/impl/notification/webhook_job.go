@@ -21,10 +21,15 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 )
 
 // WebhookJob implements the job interface, which send notification by http or https.
@@ -32,6 +37,8 @@ type WebhookJob struct {
 	client *http.Client
 	logger logger.Interface
 	ctx    job.Context
+	// signer gets the headers carrying the signatures of the payload from the core
+	signer func(policyID int64, payload []byte) (http.Header, error)
 }
 
 // MaxFails returns that how many times this job can fail, get this value from ctx.
@@ -85,6 +92,10 @@ func (wj *WebhookJob) Run(ctx job.Context, params job.Parameters) error {
 func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error {
 	wj.logger = ctx.GetLogger()
 	wj.ctx = ctx
+	if wj.signer == nil {
+		coreClient := core.New(config.GetCoreURL(), httpHelper.clients[secure], auth.NewSecretAuthorizer(config.GetAuthSecret()))
+		wj.signer = coreClient.SignWebhookPayload
+	}
 
 	// default use secure transport
 	wj.client = httpHelper.clients[secure]
@@ -97,6 +108,24 @@ func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error
 	return nil
 }
 
+// deliveryID returns the ID of the delivery in the parameters
+func deliveryID(params map[string]interface{}) string {
+	id, _ := params["delivery_id"].(string)
+	return id
+}
+
+// signingPolicyID returns the ID of the policy whose secrets sign the payload, 0 if the signing is disabled
+func signingPolicyID(params map[string]interface{}) int64 {
+	switch v := params["signing_policy_id"].(type) {
+	case int64:
+		return v
+	case float64:
+		// the job parameters are decoded from JSON
+		return int64(v)
+	}
+	return 0
+}
+
 // execute webhook job
 func (wj *WebhookJob) execute(_ job.Context, params map[string]interface{}) error {
 	payload := params["payload"].(string)
@@ -114,6 +143,17 @@ func (wj *WebhookJob) execute(_ job.Context, params map[string]interface{}) erro
 		}
 		req.Header = header
 	}
+	signature.Apply(req.Header, deliveryID(params), nil, []byte(payload), time.Now())
+	// sign right before sending, so the timestamp is fresh for the retried and delayed jobs
+	if policyID := signingPolicyID(params); policyID > 0 {
+		header, err := wj.signer(policyID, []byte(payload))
+		if err != nil {
+			return errors.Wrap(err, "error to sign the payload")
+		}
+		for k := range header {
+			req.Header.Set(k, header.Get(k))
+		}
+	}
 
 	wj.logger.Infof("send request to remote endpoint, body: %s", payload)
 
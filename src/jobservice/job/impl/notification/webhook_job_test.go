@@ -1,13 +1,16 @@
 package notification
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
 )
 
@@ -82,3 +85,57 @@ func TestRun(t *testing.T) {
 	// test incorrect webhook response
 	assert.NotNil(t, rep.Run(ctx, paramsWrong))
 }
+
+func TestRunSign(t *testing.T) {
+	ctx := &mockjobservice.MockJobContext{}
+	logger := &mockjobservice.MockJobLogger{}
+
+	ctx.On("GetLogger").Return(logger)
+
+	var signedBy int64
+	rep := &WebhookJob{
+		// the payload is signed by the core with the secrets of the policy
+		signer: func(policyID int64, payload []byte) (http.Header, error) {
+			signedBy = policyID
+			header := http.Header{}
+			signature.Apply(header, "", []string{"new", "old"}, payload, time.Now())
+			return header, nil
+		},
+	}
+
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			assert.Equal(t, "delivery-1", r.Header.Get(signature.HeaderDelivery))
+			assert.Equal(t, "true", r.Header.Get(signature.HeaderRedelivery))
+			// signed at sending with all the secrets
+			assert.Nil(t, signature.Verify(r.Header, "new", body, time.Minute, time.Now()))
+			assert.Nil(t, signature.Verify(r.Header, "old", body, time.Minute, time.Now()))
+		}))
+	defer ts.Close()
+	params := map[string]interface{}{
+		"skip_cert_verify": true,
+		"payload":          `{"key": "value"}`,
+		"address":          ts.URL,
+		"header":           `{"X-Harbor-Redelivery": ["true"]}`,
+		"delivery_id":      "delivery-1",
+		// the job parameters are decoded from JSON
+		"signing_policy_id": float64(1),
+	}
+	assert.Nil(t, rep.Run(ctx, params))
+	assert.Equal(t, int64(1), signedBy)
+
+	// failed to sign
+	rep.signer = func(_ int64, _ []byte) (http.Header, error) {
+		return nil, errors.New("core unavailable")
+	}
+	assert.NotNil(t, rep.Run(ctx, params))
+}
+
+func TestSigningPolicyID(t *testing.T) {
+	assert.Equal(t, int64(0), signingPolicyID(map[string]interface{}{}))
+	assert.Equal(t, int64(1), signingPolicyID(map[string]interface{}{"signing_policy_id": int64(1)}))
+	assert.Equal(t, int64(2), signingPolicyID(map[string]interface{}{"signing_policy_id": float64(2)}))
+	assert.Equal(t, int64(0), signingPolicyID(map[string]interface{}{"signing_policy_id": "1"}))
+}
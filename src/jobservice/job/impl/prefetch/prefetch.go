@@ -0,0 +1,221 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	"github.com/goharbor/harbor/src/jobservice/config"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+	"github.com/goharbor/harbor/src/pkg/registry"
+)
+
+const (
+	// ParamProjectName is the job parameter of the proxy cache project name
+	ParamProjectName = "project_name"
+	// ParamRules is the job parameter of the prefetch rules encoded in JSON
+	ParamRules = "rules"
+)
+
+// the registry client pulls the content through the proxy cache of the core, which caches them on the fly
+var newRegistryClient = func() registry.Client {
+	return registry.NewClientWithAuthorizer(config.GetCoreURL(), auth.NewSecretAuthorizer(config.GetAuthSecret()), true)
+}
+
+// Job resolves the repositories and tags selected by the prefetch rules against the upstream registry
+// of the proxy cache project and pulls the manifests, including all the children of the manifest lists,
+// and the blobs of them, so that they are all cached before being requested by the clients
+type Job struct {
+	client  registry.Client
+	logger  logger.Interface
+	project string
+	rules   []*model.Rule
+	// the manifests and blobs already pulled in the current run
+	pulled map[string]struct{}
+}
+
+// MaxFails is implementation of same method in Interface.
+func (j *Job) MaxFails() uint {
+	return 1
+}
+
+// MaxCurrency is implementation of same method in Interface.
+func (j *Job) MaxCurrency() uint {
+	return 0
+}
+
+// ShouldRetry ...
+func (j *Job) ShouldRetry() bool {
+	return false
+}
+
+// Validate is implementation of same method in Interface.
+func (j *Job) Validate(params job.Parameters) error {
+	_, _, err := parseParams(params)
+	return err
+}
+
+func parseParams(params job.Parameters) (string, []*model.Rule, error) {
+	project, ok := params[ParamProjectName].(string)
+	if !ok || len(project) == 0 {
+		return "", nil, errors.Errorf("missing the parameter %s", ParamProjectName)
+	}
+	str, ok := params[ParamRules].(string)
+	if !ok {
+		return "", nil, errors.Errorf("missing the parameter %s", ParamRules)
+	}
+	rules := []*model.Rule{}
+	if err := json.Unmarshal([]byte(str), &rules); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to parse the parameter %s", ParamRules)
+	}
+	return project, rules, nil
+}
+
+// Run the prefetch logic here.
+func (j *Job) Run(ctx job.Context, params job.Parameters) error {
+	project, rules, err := parseParams(params)
+	if err != nil {
+		return err
+	}
+	j.logger = ctx.GetLogger()
+	j.project = project
+	j.rules = rules
+	j.pulled = map[string]struct{}{}
+	if j.client == nil {
+		j.client = newRegistryClient()
+	}
+
+	var failed int
+	for _, rule := range j.rules {
+		repositories, _ := util.IsSpecificPath(rule.Repository)
+		for _, repository := range repositories {
+			tags, err := j.listTags(repository, rule.Tag)
+			if err != nil {
+				j.logger.Errorf("failed to list the tags of %s: %v", repository, err)
+				failed++
+				continue
+			}
+			for _, tag := range tags {
+				if shouldStop(ctx) {
+					j.logger.Info("received the stop signal, stop the prefetch job")
+					return nil
+				}
+				j.logger.Infof("prefetching %s:%s", repository, tag)
+				if err = j.fetchManifest(repository, tag); err != nil {
+					j.logger.Errorf("failed to prefetch %s:%s: %v", repository, tag, err)
+					failed++
+				}
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d repositories or tags failed to be prefetched", failed)
+	}
+	j.logger.Infof("prefetched %d manifests and blobs", len(j.pulled))
+	return nil
+}
+
+// listTags lists the tags of the upstream repository which match the tag pattern,
+// the tag list of the repository under the proxy cache project is resolved against the upstream registry
+func (j *Job) listTags(repository, pattern string) ([]string, error) {
+	tags, err := j.client.ListTags(j.localRepository(repository))
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, tag := range tags {
+		matched, err := util.Match(pattern, tag)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			result = append(result, tag)
+		}
+	}
+	return result, nil
+}
+
+// fetchManifest pulls the manifest and the manifests and blobs referenced by it recursively
+func (j *Job) fetchManifest(repository, reference string) error {
+	manifest, digest, err := j.client.PullManifest(j.localRepository(repository), reference)
+	if err != nil {
+		return err
+	}
+	if len(digest) > 0 {
+		j.pulled[digest] = struct{}{}
+	}
+	for _, ref := range manifest.References() {
+		if _, exist := j.pulled[ref.Digest.String()]; exist {
+			continue
+		}
+		if isManifest(ref) {
+			if err = j.fetchManifest(repository, ref.Digest.String()); err != nil {
+				return err
+			}
+			continue
+		}
+		// the foreign layers aren't distributable
+		if ref.MediaType == schema2.MediaTypeForeignLayer || len(ref.URLs) > 0 {
+			continue
+		}
+		if err = j.fetchBlob(repository, ref.Digest.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchBlob pulls the blob and drains it to make sure the proxy cache stores the whole blob
+func (j *Job) fetchBlob(repository, digest string) error {
+	_, blob, err := j.client.PullBlob(j.localRepository(repository), digest)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+	if _, err = io.Copy(io.Discard, blob); err != nil {
+		return err
+	}
+	j.pulled[digest] = struct{}{}
+	return nil
+}
+
+func (j *Job) localRepository(repository string) string {
+	return fmt.Sprintf("%s/%s", j.project, repository)
+}
+
+func isManifest(desc distribution.Descriptor) bool {
+	switch desc.MediaType {
+	case schema2.MediaTypeManifest, manifestlist.MediaTypeManifestList, v1.MediaTypeImageManifest, v1.MediaTypeImageIndex:
+		return true
+	}
+	return false
+}
+
+func shouldStop(ctx job.Context) bool {
+	opCmd, exit := ctx.OPCommand()
+	return exit && opCmd.IsStop()
+}
@@ -0,0 +1,133 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	mockjobservice "github.com/goharbor/harbor/src/testing/jobservice"
+	"github.com/goharbor/harbor/src/testing/pkg/registry"
+)
+
+const (
+	indexManifest = `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+  "manifests": [
+    {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "size": 100, "digest": "sha256:1111111111111111111111111111111111111111111111111111111111111111", "platform": {"architecture": "amd64", "os": "linux"}},
+    {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "size": 100, "digest": "sha256:2222222222222222222222222222222222222222222222222222222222222222", "platform": {"architecture": "arm64", "os": "linux"}}
+  ]
+}`
+	amd64Manifest = `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+  "config": {"mediaType": "application/vnd.docker.container.image.v1+json", "size": 10, "digest": "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+  "layers": [
+    {"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "size": 10, "digest": "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"},
+    {"mediaType": "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip", "size": 10, "digest": "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd", "urls": ["https://example.com/layer"]}
+  ]
+}`
+	arm64Manifest = `{
+  "schemaVersion": 2,
+  "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+  "config": {"mediaType": "application/vnd.docker.container.image.v1+json", "size": 10, "digest": "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+  "layers": [
+    {"mediaType": "application/vnd.docker.image.rootfs.diff.tar.gzip", "size": 10, "digest": "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"}
+  ]
+}`
+)
+
+type prefetchJobTestSuite struct {
+	suite.Suite
+}
+
+func (p *prefetchJobTestSuite) unmarshal(mediaType, payload string) distribution.Manifest {
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, []byte(payload))
+	p.Require().Nil(err)
+	return manifest
+}
+
+func (p *prefetchJobTestSuite) TestValidate() {
+	j := &Job{}
+	p.NotNil(j.Validate(job.Parameters{}))
+	p.NotNil(j.Validate(job.Parameters{ParamProjectName: "dockerhub"}))
+	p.NotNil(j.Validate(job.Parameters{ParamProjectName: "dockerhub", ParamRules: "invalid"}))
+	p.Nil(j.Validate(job.Parameters{ParamProjectName: "dockerhub", ParamRules: `[{"repository":"library/nginx"}]`}))
+}
+
+func (p *prefetchJobTestSuite) TestRun() {
+	ctx := &mockjobservice.MockJobContext{}
+	ctx.On("GetLogger").Return(&mockjobservice.MockJobLogger{})
+	ctx.On("OPCommand").Return(job.NilCommand, true)
+
+	client := &registry.Client{}
+	client.On("ListTags", "dockerhub/library/nginx").Return([]string{"1.25", "1.26", "latest"}, nil)
+	client.On("ListTags", "dockerhub/library/redis").Return([]string{"7"}, nil)
+	client.On("PullManifest", "dockerhub/library/nginx", "1.25").
+		Return(p.unmarshal(manifestlist.MediaTypeManifestList, indexManifest), "sha256:index", nil)
+	client.On("PullManifest", "dockerhub/library/nginx", "1.26").
+		Return(p.unmarshal(schema2.MediaTypeManifest, arm64Manifest), "sha256:2222222222222222222222222222222222222222222222222222222222222222", nil)
+	client.On("PullManifest", "dockerhub/library/nginx", "sha256:1111111111111111111111111111111111111111111111111111111111111111").
+		Return(p.unmarshal(schema2.MediaTypeManifest, amd64Manifest), "sha256:1111111111111111111111111111111111111111111111111111111111111111", nil).Once()
+	client.On("PullManifest", "dockerhub/library/nginx", "sha256:2222222222222222222222222222222222222222222222222222222222222222").
+		Return(p.unmarshal(schema2.MediaTypeManifest, arm64Manifest), "sha256:2222222222222222222222222222222222222222222222222222222222222222", nil).Once()
+	client.On("PullBlob", "dockerhub/library/nginx", mock.Anything).Return(
+		func(_, _ string) int64 { return 4 },
+		func(_, _ string) io.ReadCloser { return io.NopCloser(strings.NewReader("blob")) },
+		nil)
+
+	j := &Job{client: client}
+	err := j.Run(ctx, job.Parameters{
+		ParamProjectName: "dockerhub",
+		ParamRules:       `[{"repository":"library/{nginx,redis}","tag":"1.*"}]`,
+	})
+	p.Require().Nil(err)
+	client.AssertNotCalled(p.T(), "PullManifest", "dockerhub/library/nginx", "latest")
+	client.AssertNotCalled(p.T(), "PullManifest", "dockerhub/library/redis", mock.Anything)
+	// the foreign layer isn't pulled and the shared layer is pulled only once
+	client.AssertNotCalled(p.T(), "PullBlob", mock.Anything, "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")
+	client.AssertNumberOfCalls(p.T(), "PullBlob", 3)
+	client.AssertExpectations(p.T())
+}
+
+func (p *prefetchJobTestSuite) TestRunStopped() {
+	ctx := &mockjobservice.MockJobContext{}
+	ctx.On("GetLogger").Return(&mockjobservice.MockJobLogger{})
+	ctx.On("OPCommand").Return(job.StopCommand, true)
+
+	client := &registry.Client{}
+	client.On("ListTags", "dockerhub/library/nginx").Return([]string{"latest"}, nil)
+
+	j := &Job{client: client}
+	err := j.Run(ctx, job.Parameters{
+		ParamProjectName: "dockerhub",
+		ParamRules:       `[{"repository":"library/nginx"}]`,
+	})
+	p.Require().Nil(err)
+	client.AssertNotCalled(p.T(), "PullManifest", mock.Anything, mock.Anything)
+}
+
+func TestPrefetchJob(t *testing.T) {
+	suite.Run(t, &prefetchJobTestSuite{})
+}
@@ -16,7 +16,9 @@ package replication
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/goharbor/harbor/src/controller/replication/transfer"
 	// import chart transfer
@@ -29,10 +31,14 @@ import (
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/awsecr"
 	// import azurecr adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/azurecr"
+	// import cloudsmith adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/cloudsmith"
 	// import dockerhub adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/dockerhub"
 	// import dtr adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/dtr"
+	// import gitea adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/gitea"
 	// import githubcr adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/githubcr"
 	// import gitlab adapter
@@ -47,6 +53,10 @@ import (
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/jfrog"
 	// import native adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	// import nexus adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/nexus"
+	// import oci layout adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/ocilayout"
 	// import quay adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/quay"
 	// import tencentcr adapter
@@ -56,6 +66,9 @@ import (
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 )
 
+// the interval to check in the progress of the replication
+var progressCheckInInterval = 10 * time.Second
+
 // Replication implements the job interface
 type Replication struct{}
 
@@ -109,7 +122,48 @@ func (r *Replication) Run(ctx job.Context, params job.Parameters) error {
 		return err
 	}
 
-	return trans.Transfer(src, dst, opts)
+	// occupy the slots of the registries, the job is retried later without consuming
+	// its fails if the max concurrent tasks of any registry is reached
+	release, err := transfer.AcquireSlots(ctx.SystemContext(), src.Registry, dst.Registry)
+	if err != nil {
+		if errors.Is(err, transfer.ErrNoSlot) {
+			logger.Infof("%v, retry later", err)
+			return job.ErrRetryLater
+		}
+		logger.Errorf("failed to acquire the slots of the registries: %v", err)
+		return err
+	}
+	defer release()
+
+	// check in the progress periodically and when the transfer is done,
+	// the progress is recorded in the task by the core
+	var (
+		progress    *transfer.Progress
+		lastCheckIn time.Time
+	)
+	checkIn := func() {
+		data, err := json.Marshal(progress)
+		if err != nil {
+			logger.Warningf("failed to marshal the progress: %v", err)
+			return
+		}
+		if err = ctx.Checkin(string(data)); err != nil {
+			logger.Warningf("failed to check in the progress: %v", err)
+		}
+	}
+	opts.Progress = func(p *transfer.Progress) {
+		progress = p
+		if time.Since(lastCheckIn) >= progressCheckInInterval {
+			checkIn()
+			lastCheckIn = time.Now()
+		}
+	}
+
+	err = trans.Transfer(src, dst, opts)
+	if progress != nil {
+		checkIn()
+	}
+	return err
 }
 
 func parseParams(params map[string]interface{}) (*model.Resource, *model.Resource, *transfer.Options, error) {
@@ -151,9 +205,26 @@ func parseParams(params map[string]interface{}) (*model.Resource, *model.Resourc
 		}
 	}
 
+	var requireAccessories bool
+	value, exist = params["require_accessories"]
+	if exist {
+		if boolVal, ok := value.(bool); ok {
+			requireAccessories = boolVal
+		}
+	}
+
+	var windows []*model.BandwidthWindow
+	if _, exist = params["bandwidth_windows"]; exist {
+		if err := parseParam(params, "bandwidth_windows", &windows); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
 	opts := transfer.NewOptions(
 		transfer.WithSpeed(speed),
+		transfer.WithBandwidthWindows(windows),
 		transfer.WithCopyByChunk(copyByChunk),
+		transfer.WithRequireAccessories(requireAccessories),
 	)
 	return src, dst, opts, nil
 }
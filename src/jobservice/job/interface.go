@@ -14,6 +14,12 @@
 
 package job
 
+import "errors"
+
+// ErrRetryLater is returned by the job which can't run for now, e.g. the shared resource it needs is occupied.
+// The job is put back into the retry queue with a delay and the retry doesn't consume its fails
+var ErrRetryLater = errors.New("the job can't run for now, retry later")
+
 // Interface defines the related injection and run entry methods.
 type Interface interface {
 	// Declare how many times the job can be retried if failed.
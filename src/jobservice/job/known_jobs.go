@@ -32,6 +32,14 @@ const (
 	WebhookJobVendorType = "WEBHOOK"
 	// SlackJobVendorType : the name of the slack job in job service
 	SlackJobVendorType = "SLACK"
+	// EmailJobVendorType : the name of the email job in job service
+	EmailJobVendorType = "EMAIL"
+	// TeamsJobVendorType : the name of the Microsoft Teams job in job service
+	TeamsJobVendorType = "TEAMS"
+	// GoogleChatJobVendorType : the name of the Google Chat job in job service
+	GoogleChatJobVendorType = "GOOGLE_CHAT"
+	// CustomJobVendorType : the name of the job sending the payload rendered by the custom template in job service
+	CustomJobVendorType = "CUSTOM_WEBHOOK"
 	// RetentionVendorType : the name of the retention job
 	RetentionVendorType = "RETENTION"
 	// P2PPreheatVendorType : the name of the P2P preheat job
@@ -46,8 +54,12 @@ const (
 	ExecSweepVendorType = "EXECUTION_SWEEP"
 	// ScanAllVendorType: the name of the scan all job
 	ScanAllVendorType = "SCAN_ALL"
+	// ScanDBUpdateVendorType: the name of the rescan triggered by the vulnerability database updates of the scanners
+	ScanDBUpdateVendorType = "SCAN_DB_UPDATE"
 	// AuditLogsGDPRCompliantVendorType : the name of the job which makes audit logs table GDPR-compliant
 	AuditLogsGDPRCompliantVendorType = "AUDIT_LOGS_GDPR_COMPLIANT"
+	// ProxyCachePrefetchVendorType : the name of the job which pre-warms the proxy cache projects
+	ProxyCachePrefetchVendorType = "PROXY_CACHE_PREFETCH"
 )
 
 var (
@@ -56,16 +68,22 @@ var (
 		ImageScanJobVendorType:          1,
 		SBOMJobVendorType:               1,
 		ScanAllVendorType:               1,
+		ScanDBUpdateVendorType:          50,
 		PurgeAuditVendorType:            10,
 		ExecSweepVendorType:             10,
 		GarbageCollectionVendorType:     50,
 		SlackJobVendorType:              50,
+		EmailJobVendorType:              50,
+		TeamsJobVendorType:              50,
+		GoogleChatJobVendorType:         50,
+		CustomJobVendorType:             50,
 		WebhookJobVendorType:            50,
 		ReplicationVendorType:           50,
 		ScanDataExportVendorType:        50,
 		SystemArtifactCleanupVendorType: 50,
 		P2PPreheatVendorType:            50,
 		RetentionVendorType:             50,
+		ProxyCachePrefetchVendorType:    50,
 	}
 )
 
@@ -118,6 +118,17 @@ func (rj *RedisJob) Run(j *work.Job) (err error) {
 	defer func() {
 		// Switch job status based on the returned error.
 		// The err happened here should not override the job run error, just log it.
+		if errors.Is(err, job.ErrRetryLater) {
+			// The job isn't failed but delayed, reset it to pending to wait for the retry
+			// instead of reporting the final error status
+			logger.Infof("Job '%s:%s' will be retried later: %s", j.Name, j.ID, err)
+			if er := tracker.Reset(); er != nil {
+				logger.Errorf("Error occurred when resetting the status of job %s:%s to pending: %s", j.Name, j.ID, er)
+			}
+
+			return
+		}
+
 		if err != nil {
 			// log error
 			logger.Errorf("Job '%s:%s' exit with error: %s", j.Name, j.ID, err)
@@ -235,7 +246,7 @@ func (rj *RedisJob) Run(j *work.Job) (err error) {
 	}
 
 	// Handle retry
-	rj.retry(runningJob, j)
+	rj.retry(runningJob, j, err)
 	// Handle periodic job execution
 	if _, yes := isPeriodicJobExecution(j); yes {
 		if er := tracker.PeriodicExecutionDone(); er != nil {
@@ -247,13 +258,18 @@ func (rj *RedisJob) Run(j *work.Job) (err error) {
 	return
 }
 
-func (rj *RedisJob) retry(j job.Interface, wj *work.Job) {
+func (rj *RedisJob) retry(j job.Interface, wj *work.Job, err error) {
 	if !j.ShouldRetry() {
 		// Cancel retry immediately
 		// Make it big enough to avoid retrying
 		wj.Fails = 10000000000
 		return
 	}
+	// The job asks to be retried later, the fail increased by the worker is deducted
+	// to keep the fails unconsumed
+	if errors.Is(err, job.ErrRetryLater) {
+		wj.Fails--
+	}
 }
 
 func isPeriodicJobExecution(j *work.Job) (string, bool) {
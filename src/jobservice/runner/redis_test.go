@@ -34,6 +34,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/lcm"
 	"github.com/goharbor/harbor/src/jobservice/logger/backend"
 	"github.com/goharbor/harbor/src/jobservice/tests"
+	"github.com/goharbor/harbor/src/lib/errors"
 )
 
 // RedisRunnerTestSuite tests functions of redis runner
@@ -55,6 +56,20 @@ func TestRedisRunnerTestSuite(t *testing.T) {
 	suite.Run(t, new(RedisRunnerTestSuite))
 }
 
+// TestRetry tests the fails handling of the failed job
+func TestRetry(t *testing.T) {
+	rj := &RedisJob{}
+
+	// the fails of the job asking to retry later isn't consumed
+	wj := &work.Job{Fails: 1}
+	rj.retry(&fakeRetryJob{}, wj, errors.Wrap(job.ErrRetryLater, "run error"))
+	assert.Equal(t, int64(0), wj.Fails)
+
+	wj = &work.Job{Fails: 1}
+	rj.retry(&fakeRetryJob{}, wj, errors.New("run error"))
+	assert.Equal(t, int64(1), wj.Fails)
+}
+
 // SetupSuite prepares test suite
 func (suite *RedisRunnerTestSuite) SetupSuite() {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -190,6 +205,26 @@ func (suite *RedisRunnerTestSuite) TestJobWrapperStopped() {
 	require.NoError(suite.T(), err)
 }
 
+// TestJobWrapperRetryLater tests the job asking to be retried later isn't marked as failed
+func (suite *RedisRunnerTestSuite) TestJobWrapperRetryLater() {
+	j := &work.Job{
+		ID:         "FAKE-j",
+		Name:       "fakeRetryLaterJob",
+		EnqueuedAt: time.Now().Add(5 * time.Minute).Unix(),
+		Fails:      1,
+	}
+
+	redisJob := NewRedisJob((*fakeRetryLaterJob)(nil), suite.envContext, suite.lcmCtl)
+	err := redisJob.Run(j)
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), errors.Is(err, job.ErrRetryLater))
+	assert.Equal(suite.T(), int64(0), j.Fails)
+
+	t, err := suite.lcmCtl.Track("FAKE-j")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), job.PendingStatus.String(), t.Job().Info.Status)
+}
+
 type fakeParentJob struct {
 }
 
@@ -237,3 +272,19 @@ func (j *fakePanicJob) Validate(params job.Parameters) error {
 func (j *fakePanicJob) Run(ctx job.Context, params job.Parameters) error {
 	panic("for testing")
 }
+
+type fakeRetryJob struct {
+	fakeParentJob
+}
+
+func (j *fakeRetryJob) ShouldRetry() bool {
+	return true
+}
+
+type fakeRetryLaterJob struct {
+	fakeRetryJob
+}
+
+func (j *fakeRetryLaterJob) Run(ctx job.Context, params job.Parameters) error {
+	return job.ErrRetryLater
+}
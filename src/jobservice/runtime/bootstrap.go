@@ -39,6 +39,7 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/job/impl/gc"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/legacy"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/notification"
+	"github.com/goharbor/harbor/src/jobservice/job/impl/prefetch"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/purge"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/replication"
 	"github.com/goharbor/harbor/src/jobservice/job/impl/sample"
@@ -324,6 +325,10 @@ func (bs *Bootstrap) loadAndRunRedisWorkerPool(
 			scheduler.JobNameScheduler:      (*scheduler.PeriodicJob)(nil),
 			job.WebhookJobVendorType:        (*notification.WebhookJob)(nil),
 			job.SlackJobVendorType:          (*notification.SlackJob)(nil),
+			job.EmailJobVendorType:          (*notification.EmailJob)(nil),
+			job.TeamsJobVendorType:          (*notification.TeamsJob)(nil),
+			job.GoogleChatJobVendorType:     (*notification.GoogleChatJob)(nil),
+			job.CustomJobVendorType:         (*notification.CustomJob)(nil),
 			job.P2PPreheatVendorType:        (*preheat.Job)(nil),
 			job.ScanDataExportVendorType:    (*scandataexport.ScanDataExport)(nil),
 			// In v2.2 we migrate the scheduled replication, garbage collection and scan all to
@@ -335,6 +340,7 @@ func (bs *Bootstrap) loadAndRunRedisWorkerPool(
 			job.SystemArtifactCleanupVendorType:  (*systemartifact.Cleanup)(nil),
 			job.ExecSweepVendorType:              (*task.SweepJob)(nil),
 			job.AuditLogsGDPRCompliantVendorType: (*gdpr.AuditLogsDataMasking)(nil),
+			job.ProxyCachePrefetchVendorType:     (*prefetch.Job)(nil),
 		}); err != nil {
 		// exit
 		return nil, err
@@ -57,6 +57,7 @@ const (
 	BasicGroup = "basic"
 	TrivyGroup = "trivy"
 	GDPRGroup  = "gdpr"
+	EmailGroup = "email"
 )
 
 var (
@@ -154,6 +155,18 @@ var (
 		{Name: common.RobotNamePrefix, Scope: UserScope, Group: BasicGroup, EnvKey: "ROBOT_NAME_PREFIX", DefaultValue: "robot$", ItemType: &StringType{}, Editable: true, Description: `The robot account name prefix`},
 		{Name: common.RobotScannerNamePrefix, Scope: SystemScope, Group: BasicGroup, EnvKey: "ROBOT_SCANNER_NAME_PREFIX", DefaultValue: "scanner", ItemType: &StringType{}, Editable: true, Description: `The scanner robot account name prefix`},
 		{Name: common.NotificationEnable, Scope: UserScope, Group: BasicGroup, EnvKey: "NOTIFICATION_ENABLE", DefaultValue: "true", ItemType: &BoolType{}, Editable: true, Description: `Enable notification`},
+		{Name: common.NotificationQueueLatencyThreshold, Scope: UserScope, Group: BasicGroup, EnvKey: "NOTIFICATION_QUEUE_LATENCY_THRESHOLD", DefaultValue: "600", ItemType: &Int64Type{}, Editable: true, Description: `The latency in seconds of the jobservice queue to trigger the JOB_QUEUE_LATENCY_BREACHED event, 0 disables the event`},
+		{Name: common.NotificationStorageFreeThreshold, Scope: UserScope, Group: BasicGroup, EnvKey: "NOTIFICATION_STORAGE_FREE_THRESHOLD", DefaultValue: "10", ItemType: &Int64Type{}, Editable: true, Description: `The percentage of the free registry storage to trigger the STORAGE_LOW event, 0 disables the event`},
+		{Name: common.NotificationCVEAllowlistExpiryDays, Scope: UserScope, Group: BasicGroup, EnvKey: "NOTIFICATION_CVE_ALLOWLIST_EXPIRY_DAYS", DefaultValue: "7", ItemType: &Int64Type{}, Editable: true, Description: `The days before the expiry of the CVE allowlist items to trigger the CVE_ALLOWLIST_EXPIRING event, 0 disables the event`},
+
+		{Name: common.EmailHost, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_HOST", DefaultValue: "", ItemType: &StringType{}, Editable: true, Description: `The host of the SMTP server to send the email notifications`},
+		{Name: common.EmailPort, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_PORT", DefaultValue: "25", ItemType: &PortType{}, Editable: true, Description: `The port of the SMTP server`},
+		{Name: common.EmailUsername, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_USERNAME", DefaultValue: "", ItemType: &StringType{}, Editable: true, Description: `The username to authenticate against the SMTP server`},
+		{Name: common.EmailPassword, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_PASSWORD", DefaultValue: "", ItemType: &PasswordType{}, Editable: true, Description: `The password to authenticate against the SMTP server`},
+		{Name: common.EmailFrom, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_FROM", DefaultValue: "", ItemType: &StringType{}, Editable: true, Description: `The sender address of the email notifications`},
+		{Name: common.EmailSSL, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_SSL", DefaultValue: "false", ItemType: &BoolType{}, Editable: true, Description: `Whether connect to the SMTP server via TLS, STARTTLS is used when it is disabled and supported by the server`},
+		{Name: common.EmailIdentity, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_IDENTITY", DefaultValue: "", ItemType: &StringType{}, Editable: true, Description: `The identity used for the PLAIN authentication`},
+		{Name: common.EmailInsecure, Scope: UserScope, Group: EmailGroup, EnvKey: "EMAIL_INSECURE", DefaultValue: "false", ItemType: &BoolType{}, Editable: true, Description: `Whether skip the verification of the SMTP server's certificate`},
 
 		{Name: common.MetricEnable, Scope: SystemScope, Group: BasicGroup, EnvKey: "METRIC_ENABLE", DefaultValue: "false", ItemType: &BoolType{}, Editable: true},
 		{Name: common.MetricPort, Scope: SystemScope, Group: BasicGroup, EnvKey: "METRIC_PORT", DefaultValue: "9090", ItemType: &PortType{}, Editable: true},
@@ -189,8 +202,11 @@ var (
 		{Name: common.GDPRAuditLogs, Scope: SystemScope, Group: GDPRGroup, EnvKey: "GDPR_AUDIT_LOGS", DefaultValue: "false", ItemType: &BoolType{}, Editable: false, Description: `The flag indicates if an audit logs of a deleted user should be GDPR compliant.`},
 
 		{Name: common.AuditLogForwardEndpoint, Scope: UserScope, Group: BasicGroup, EnvKey: "AUDIT_LOG_FORWARD_ENDPOINT", DefaultValue: "", ItemType: &StringType{}, Editable: false, Description: `The endpoint to forward the audit log.`},
+		{Name: common.AuditLogForwarders, Scope: UserScope, Group: BasicGroup, EnvKey: "AUDIT_LOG_FORWARDERS", DefaultValue: "", ItemType: &StringType{}, Editable: false, Description: `The JSON encoded sinks to forward the audit log, e.g. syslog, http and file, with the disk or redis retry buffer.`},
 		{Name: common.SkipAuditLogDatabase, Scope: UserScope, Group: BasicGroup, EnvKey: "SKIP_LOG_AUDIT_DATABASE", DefaultValue: "false", ItemType: &BoolType{}, Editable: false, Description: `The option to skip audit log in database`},
 		{Name: common.ScannerSkipUpdatePullTime, Scope: UserScope, Group: BasicGroup, EnvKey: "SCANNER_SKIP_UPDATE_PULL_TIME", DefaultValue: "false", ItemType: &BoolType{}, Editable: false, Description: `The option to skip update pull time for scanner`},
+		{Name: common.ScannerRescanDailyLimit, Scope: UserScope, Group: BasicGroup, EnvKey: "SCANNER_RESCAN_DAILY_LIMIT", DefaultValue: "0", ItemType: &Int64Type{}, Editable: true, Description: `The max count of the artifacts rescanned per day after the vulnerability database of the scanner updates, 0 disables the automatic rescan`},
+		{Name: common.ScannerRescanPulledWithinDays, Scope: UserScope, Group: BasicGroup, EnvKey: "SCANNER_RESCAN_PULLED_WITHIN_DAYS", DefaultValue: "0", ItemType: &Int64Type{}, Editable: true, Description: `Only rescan the artifacts pulled in the days, 0 rescans the artifacts regardless of the pull time`},
 
 		{Name: common.SessionTimeout, Scope: UserScope, Group: BasicGroup, EnvKey: "SESSION_TIMEOUT", DefaultValue: "60", ItemType: &Int64Type{}, Editable: true, Description: `The session timeout in minutes`},
 
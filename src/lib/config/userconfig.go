@@ -196,6 +196,21 @@ func NotificationEnable(ctx context.Context) bool {
 	return DefaultMgr().Get(ctx, common.NotificationEnable).GetBool()
 }
 
+// NotificationQueueLatencyThreshold returns the latency in seconds of the jobservice queue to trigger the system event
+func NotificationQueueLatencyThreshold(ctx context.Context) int64 {
+	return DefaultMgr().Get(ctx, common.NotificationQueueLatencyThreshold).GetInt64()
+}
+
+// NotificationStorageFreeThreshold returns the percentage of the free registry storage to trigger the system event
+func NotificationStorageFreeThreshold(ctx context.Context) int64 {
+	return DefaultMgr().Get(ctx, common.NotificationStorageFreeThreshold).GetInt64()
+}
+
+// NotificationCVEAllowlistExpiryDays returns the days before the expiry of the CVE allowlist items to trigger the system event
+func NotificationCVEAllowlistExpiryDays(ctx context.Context) int64 {
+	return DefaultMgr().Get(ctx, common.NotificationCVEAllowlistExpiryDays).GetInt64()
+}
+
 // QuotaPerProjectEnable returns a bool to indicates if quota per project enabled in harbor
 func QuotaPerProjectEnable(ctx context.Context) bool {
 	return DefaultMgr().Get(ctx, common.QuotaPerProjectEnable).GetBool()
@@ -247,6 +262,11 @@ func AuditLogForwardEndpoint(ctx context.Context) string {
 	return DefaultMgr().Get(ctx, common.AuditLogForwardEndpoint).GetString()
 }
 
+// AuditLogForwarders returns the JSON encoded sinks which the audit logs are forwarded to
+func AuditLogForwarders(ctx context.Context) string {
+	return DefaultMgr().Get(ctx, common.AuditLogForwarders).GetString()
+}
+
 // SkipAuditLogDatabase returns the audit log forward endpoint
 func SkipAuditLogDatabase(ctx context.Context) bool {
 	return DefaultMgr().Get(ctx, common.SkipAuditLogDatabase).GetBool()
@@ -257,6 +277,16 @@ func ScannerSkipUpdatePullTime(ctx context.Context) bool {
 	return DefaultMgr().Get(ctx, common.ScannerSkipUpdatePullTime).GetBool()
 }
 
+// ScannerRescanDailyLimit returns the max count of the artifacts rescanned per day after the scanner DB updates
+func ScannerRescanDailyLimit(ctx context.Context) int64 {
+	return DefaultMgr().Get(ctx, common.ScannerRescanDailyLimit).GetInt64()
+}
+
+// ScannerRescanPulledWithinDays returns the days in which the artifacts pulled are rescanned after the scanner DB updates
+func ScannerRescanPulledWithinDays(ctx context.Context) int64 {
+	return DefaultMgr().Get(ctx, common.ScannerRescanPulledWithinDays).GetInt64()
+}
+
 // BannerMessage returns the customized banner message
 func BannerMessage(ctx context.Context) string {
 	return DefaultMgr().Get(ctx, common.BannerMessage).GetString()
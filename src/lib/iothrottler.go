@@ -42,6 +42,14 @@ func NewReader(r io.ReadCloser, kb int32) io.ReadCloser {
 	}
 }
 
+// NewReaderWithLimiter returns a Reader that is rate limited by the limiter, the limiter can be shared by multiple readers
+func NewReaderWithLimiter(r io.ReadCloser, limiter *rate.Limiter) io.ReadCloser {
+	return &reader{
+		reader:  r,
+		limiter: limiter,
+	}
+}
+
 func (r *reader) Read(buf []byte) (int, error) {
 	n, err := r.reader.Read(buf)
 	if n <= 0 {
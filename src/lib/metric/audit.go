@@ -0,0 +1,67 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metric
+
+import (
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// AuditLogForwardTotal used to collect the count of the forwarded audit logs by sink and result
+	AuditLogForwardTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: os.Getenv(NamespaceEnvKey),
+			Subsystem: os.Getenv(SubsystemEnvKey),
+			Name:      "audit_log_forward_total",
+			Help:      "The total number of the audit logs forwarded to the sinks",
+		},
+		[]string{"sink", "result"},
+	)
+
+	// AuditLogForwardDropped used to collect the count of the audit logs dropped as the buffer is full
+	AuditLogForwardDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: os.Getenv(NamespaceEnvKey),
+			Subsystem: os.Getenv(SubsystemEnvKey),
+			Name:      "audit_log_forward_dropped_total",
+			Help:      "The total number of the audit logs dropped as the retry buffer of the sink is full",
+		},
+		[]string{"sink"},
+	)
+
+	// AuditLogForwardQueueLength used to collect the count of the audit logs waiting in memory
+	AuditLogForwardQueueLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: os.Getenv(NamespaceEnvKey),
+			Subsystem: os.Getenv(SubsystemEnvKey),
+			Name:      "audit_log_forward_queue_length",
+			Help:      "The number of the audit logs waiting in the in-memory queue of the sink",
+		},
+		[]string{"sink"},
+	)
+
+	// AuditLogForwardBufferBytes used to collect the size of the audit logs in the retry buffer
+	AuditLogForwardBufferBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: os.Getenv(NamespaceEnvKey),
+			Subsystem: os.Getenv(SubsystemEnvKey),
+			Name:      "audit_log_forward_buffer_bytes",
+			Help:      "The size in bytes of the audit logs in the on-disk retry buffer of the sink",
+		},
+		[]string{"sink"},
+	)
+)
@@ -26,6 +26,10 @@ func RegisterCollectors() {
 		TotalInFlightGauge,
 		TotalReqCnt,
 		TotalReqDurSummary,
+		AuditLogForwardTotal,
+		AuditLogForwardDropped,
+		AuditLogForwardQueueLength,
+		AuditLogForwardBufferBytes,
 	}...)
 }
 
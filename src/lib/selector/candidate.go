@@ -89,6 +89,17 @@ type Candidate struct {
 	// Signatures of the above Tags
 	// This is not technical correct, just for keeping compatibilities with the original definition.
 	Signatures map[string]bool `json:"signatures"`
+	// Size of the candidate in bytes
+	Size int64 `json:"size"`
+	// Digests of the child artifacts referenced by the candidate if it's an index
+	References []string `json:"references"`
+	// Referenced by other artifacts, e.g. the candidate is a child of a manifest list
+	Referenced bool `json:"referenced"`
+	// The latest pulled time of the child artifacts in seconds, the children are
+	// usually pulled by digest after the client resolves the index
+	ReferencePulledTime int64 `json:"reference_pulled_time_second"`
+	// Status of the latest vulnerability scan, empty if the candidate is never scanned
+	ScanStatus string `json:"scan_status"`
 }
 
 // Hash code based on the candidate info for differentiation
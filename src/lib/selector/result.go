@@ -19,6 +19,11 @@ type Result struct {
 	Target *Candidate `json:"target"`
 	// nil error means success
 	Error error `json:"error"`
+	// Action performed to the target, e.g. "delete" or "archive"
+	Action string `json:"action,omitempty"`
+	// IDs of the rules which selected the target, empty if the target is removed
+	// because it isn't retained by any rule
+	Rules []int `json:"rules,omitempty"`
 }
 
 // ImmutableError ...
@@ -28,3 +33,11 @@ type ImmutableError struct {
 func (e *ImmutableError) Error() string {
 	return "Immutable tag"
 }
+
+// PinnedError ...
+type PinnedError struct {
+}
+
+func (e *PinnedError) Error() string {
+	return "Pinned tag"
+}
@@ -79,6 +79,12 @@ const (
 
 	// TypeHarborSBOM identifies sbom.harbor
 	TypeHarborSBOM = "sbom.harbor"
+
+	// TypeSBOM identifies the SPDX or CycloneDX SBOM produced outside of Harbor and attached to the artifact
+	TypeSBOM = "sbom"
+
+	// TypeVEX identifies the OpenVEX or CycloneDX VEX document attached to the artifact
+	TypeVEX = "vex"
 )
 
 // AccessoryData ...
@@ -41,6 +41,29 @@ func New(data model.AccessoryData) model.Accessory {
 	}}
 }
 
+// SBOM is the SPDX or CycloneDX sbom accessory produced outside of harbor
+type SBOM struct {
+	base.Default
+}
+
+// Kind gives the reference type of accessory.
+func (s *SBOM) Kind() string {
+	return model.RefHard
+}
+
+// IsHard ...
+func (s *SBOM) IsHard() bool {
+	return true
+}
+
+// NewSBOM returns the sbom accessory produced outside of harbor
+func NewSBOM(data model.AccessoryData) model.Accessory {
+	return &SBOM{base.Default{
+		Data: data,
+	}}
+}
+
 func init() {
 	model.Register(model.TypeHarborSBOM, New)
+	model.Register(model.TypeSBOM, NewSBOM)
 }
@@ -82,6 +82,19 @@ func (suite *SBOMTestSuite) TestDisplay() {
 	suite.False(suite.accessory.Display())
 }
 
+func (suite *SBOMTestSuite) TestExternalSBOM() {
+	acc, err := model.New(model.TypeSBOM, model.AccessoryData{
+		ArtifactID:        2,
+		SubArtifactDigest: suite.subDigest,
+		Digest:            suite.digest,
+	})
+	suite.Require().NoError(err)
+	suite.IsType(&SBOM{}, acc)
+	suite.Equal(model.TypeSBOM, acc.GetData().Type)
+	suite.True(acc.IsHard())
+	suite.False(acc.Display())
+}
+
 func TestSBOMTestSuite(t *testing.T) {
 	suite.Run(t, new(SBOMTestSuite))
 }
@@ -35,6 +35,8 @@ type DAO interface {
 	// QueryByProjectID returns the CVE allowlist of the project based on the project ID in parameter.  The project ID should be 0
 	// for system level CVE allowlist
 	QueryByProjectID(ctx context.Context, pid int64) (*models.CVEAllowlist, error)
+	// List returns the CVE allowlists of all the projects and the system level one
+	List(ctx context.Context) ([]*models.CVEAllowlist, error)
 }
 
 // New ...
@@ -87,3 +89,22 @@ func (d *dao) QueryByProjectID(ctx context.Context, pid int64) (*models.CVEAllow
 	r[0].Items = items
 	return &r[0], nil
 }
+
+func (d *dao) List(ctx context.Context) ([]*models.CVEAllowlist, error) {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var r []*models.CVEAllowlist
+	if _, err = ormer.QueryTable(&models.CVEAllowlist{}).All(&r); err != nil {
+		return nil, fmt.Errorf("failed to list CVE allowlists, error: %v", err)
+	}
+	for _, l := range r {
+		l.Items = []models.CVEAllowlistItem{}
+		if err := json.Unmarshal([]byte(l.ItemsText), &l.Items); err != nil {
+			log.Errorf("Failed to decode item list, err: %v, text: %s", err, l.ItemsText)
+			return nil, err
+		}
+	}
+	return r, nil
+}
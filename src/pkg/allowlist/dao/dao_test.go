@@ -51,6 +51,15 @@ func (s *testSuite) TestSetAndGet() {
 	_, err = s.dao.Set(s.Context(), in3)
 	s.Nil(err)
 
+	all, err := s.dao.List(s.Context())
+	s.Nil(err)
+	s.Len(all, 2)
+	for _, l := range all {
+		if l.ProjectID == 0 {
+			s.Equal(sysCVEs, l.Items)
+		}
+	}
+
 }
 
 func TestDaoTestSuite(t *testing.T) {
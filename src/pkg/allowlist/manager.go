@@ -35,6 +35,8 @@ type Manager interface {
 	SetSys(ctx context.Context, list models.CVEAllowlist) error
 	// GetSys gets system level allowlist
 	GetSys(ctx context.Context) (*models.CVEAllowlist, error)
+	// List lists the allowlists of all the projects and the system level one
+	List(ctx context.Context) ([]*models.CVEAllowlist, error)
 }
 
 type defaultManager struct {
@@ -90,6 +92,11 @@ func (d *defaultManager) GetSys(ctx context.Context) (*models.CVEAllowlist, erro
 	return d.Get(ctx, 0)
 }
 
+// List lists the allowlists of all the projects and the system level one
+func (d *defaultManager) List(ctx context.Context) ([]*models.CVEAllowlist, error) {
+	return d.dao.List(ctx)
+}
+
 // NewDefaultManager return a new instance of defaultManager
 func NewDefaultManager() Manager {
 	return &defaultManager{dao: dao.New()}
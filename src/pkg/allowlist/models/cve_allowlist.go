@@ -16,6 +16,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/goharbor/harbor/src/pkg/reg/util"
 )
 
 // CVEAllowlist defines the data model for a CVE allowlist
@@ -32,6 +34,46 @@ type CVEAllowlist struct {
 // CVEAllowlistItem defines one item in the CVE allowlist
 type CVEAllowlistItem struct {
 	CVEID string `json:"cve_id"`
+	// ExpiresAt is the expiry of the item itself, the item never expires when it's nil,
+	// it takes effect along with the expiry of the whole allowlist
+	ExpiresAt     *int64 `json:"expires_at,omitempty"`
+	Justification string `json:"justification,omitempty"`
+	Approver      string `json:"approver,omitempty"`
+	// Repository is the doublestar pattern of the repositories the item applies to, e.g. "library/**", empty for all repositories
+	Repository string `json:"repository,omitempty"`
+	// Package is the name of the vulnerable package the item applies to, empty for all packages
+	Package string `json:"package,omitempty"`
+	// AddedBy and AddedAt record who added the item and when
+	AddedBy string `json:"added_by,omitempty"`
+	AddedAt *int64 `json:"added_at,omitempty"`
+}
+
+// IsExpired returns whether the item is expired
+func (c *CVEAllowlistItem) IsExpired() bool {
+	if c.ExpiresAt == nil {
+		return false
+	}
+	return time.Now().Unix() >= *c.ExpiresAt
+}
+
+// IsScoped returns whether the item only applies to some repositories or packages
+func (c *CVEAllowlistItem) IsScoped() bool {
+	return c.Repository != "" || c.Package != ""
+}
+
+// Matches returns whether the item applies to the package in the repository
+func (c *CVEAllowlistItem) Matches(repository, pkg string) bool {
+	if c.Repository != "" {
+		if ok, err := util.Match(c.Repository, repository); err != nil || !ok {
+			return false
+		}
+	}
+	return c.Package == "" || c.Package == pkg
+}
+
+// SameScope returns whether the item has the same CVE ID and scope with the other one
+func (c *CVEAllowlistItem) SameScope(other *CVEAllowlistItem) bool {
+	return c.CVEID == other.CVEID && c.Repository == other.Repository && c.Package == other.Package
 }
 
 // TableName ...
@@ -39,11 +81,14 @@ func (c *CVEAllowlist) TableName() string {
 	return "cve_allowlist"
 }
 
-// CVESet returns the set of CVE id of the items in the allowlist to help filter the vulnerability list
+// CVESet returns the set of the unexpired items in the allowlist to help filter the vulnerability list
 func (c *CVEAllowlist) CVESet() CVESet {
 	r := CVESet{}
 	for _, it := range c.Items {
-		r[it.CVEID] = struct{}{}
+		if it.IsExpired() {
+			continue
+		}
+		r.AddItem(it)
 	}
 	return r
 }
@@ -56,27 +101,67 @@ func (c *CVEAllowlist) IsExpired() bool {
 	return time.Now().Unix() >= *c.ExpiresAt
 }
 
-// CVESet defines the CVE allowlist with a hash set way for easy query.
-type CVESet map[string]struct{}
+// InheritAddedInfo keeps who added the items and when from the items of the same scope in the previous allowlist,
+// and records the operator and the current time as the added info of the new items
+func (c *CVEAllowlist) InheritAddedInfo(prev *CVEAllowlist, operator string) {
+	now := time.Now().Unix()
+	for i := range c.Items {
+		it := &c.Items[i]
+		it.AddedBy, it.AddedAt = operator, &now
+		if prev == nil {
+			continue
+		}
+		for _, p := range prev.Items {
+			if p.SameScope(it) {
+				it.AddedBy, it.AddedAt = p.AddedBy, p.AddedAt
+				break
+			}
+		}
+	}
+}
+
+// CVESet defines the CVE allowlist with a hash map way for easy query, the key is the CVE ID
+// and the value is the items of the CVE with different scopes.
+type CVESet map[string][]CVEAllowlistItem
 
-// Add add cve to the set
+// Add add cve which applies to all the repositories and packages to the set
 func (cs CVESet) Add(cve string) {
-	cs[cve] = struct{}{}
+	cs.AddItem(CVEAllowlistItem{CVEID: cve})
 }
 
-// Contains checks whether the specified CVE is in the set or not.
+// AddItem adds the allowlist item to the set
+func (cs CVESet) AddItem(item CVEAllowlistItem) {
+	cs[item.CVEID] = append(cs[item.CVEID], item)
+}
+
+// Contains checks whether the specified CVE is in the set and applies to all the repositories and packages.
 func (cs CVESet) Contains(cve string) bool {
-	_, ok := cs[cve]
+	for _, it := range cs[cve] {
+		if !it.IsScoped() && !it.IsExpired() {
+			return true
+		}
+	}
+	return false
+}
 
-	return ok
+// Allows checks whether the specified CVE of the package in the repository is allowed by an unexpired item in the set.
+func (cs CVESet) Allows(cve, repository, pkg string) bool {
+	for _, it := range cs[cve] {
+		if !it.IsExpired() && it.Matches(repository, pkg) {
+			return true
+		}
+	}
+	return false
 }
 
 // NewCVESet returns CVESet from cveSets
 func NewCVESet(cveSets ...CVESet) CVESet {
 	s := CVESet{}
 	for _, cveSet := range cveSets {
-		for cve := range cveSet {
-			s.Add(cve)
+		for _, items := range cveSet {
+			for _, it := range items {
+				s.AddItem(it)
+			}
 		}
 	}
 
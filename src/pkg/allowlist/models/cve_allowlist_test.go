@@ -59,8 +59,8 @@ func TestCVEAllowlist_All(t *testing.T) {
 				ExpiresAt: &future,
 			},
 			cveset: CVESet{
-				"CVE-1999-0067":    {},
-				"CVE-2016-7654321": {},
+				"CVE-1999-0067":    {{CVEID: "CVE-1999-0067"}},
+				"CVE-2016-7654321": {{CVEID: "CVE-2016-7654321"}},
 			},
 			expired: false,
 		},
@@ -70,3 +70,63 @@ func TestCVEAllowlist_All(t *testing.T) {
 		assert.Equal(t, c.cveset, c.input.CVESet())
 	}
 }
+
+func TestCVESet_Allows(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Unix()
+	future := time.Now().Add(time.Hour).Unix()
+	l := CVEAllowlist{
+		Items: []CVEAllowlistItem{
+			{CVEID: "CVE-2024-0001"},
+			{CVEID: "CVE-2024-0002", ExpiresAt: &past},
+			{CVEID: "CVE-2024-0003", ExpiresAt: &future, Repository: "library/*"},
+			{CVEID: "CVE-2024-0003", Repository: "dev/app", Package: "openssl"},
+			{CVEID: "CVE-2024-0004", Repository: "{dev,prod}/**"},
+		},
+	}
+	set := l.CVESet()
+	assert.Len(t, set, 3)
+
+	assert.True(t, set.Contains("CVE-2024-0001"))
+	assert.False(t, set.Contains("CVE-2024-0002"))
+	assert.False(t, set.Contains("CVE-2024-0003"))
+
+	assert.True(t, set.Allows("CVE-2024-0001", "any/repo", "any"))
+	assert.False(t, set.Allows("CVE-2024-0002", "any/repo", "any"))
+	assert.True(t, set.Allows("CVE-2024-0003", "library/nginx", "curl"))
+	assert.False(t, set.Allows("CVE-2024-0003", "dev/nginx", "curl"))
+	assert.True(t, set.Allows("CVE-2024-0003", "dev/app", "openssl"))
+	assert.False(t, set.Allows("CVE-2024-0003", "dev/app", "curl"))
+	assert.False(t, set.Allows("CVE-2024-0003", "library/team/nginx", "curl"))
+	assert.True(t, set.Allows("CVE-2024-0004", "dev/team/app", "curl"))
+	assert.True(t, set.Allows("CVE-2024-0004", "prod/app", "curl"))
+	assert.False(t, set.Allows("CVE-2024-0004", "library/app", "curl"))
+
+	merged := NewCVESet(set, CVESet{"CVE-2024-0003": {{CVEID: "CVE-2024-0003"}}})
+	assert.Len(t, merged["CVE-2024-0003"], 3)
+	assert.True(t, merged.Contains("CVE-2024-0003"))
+}
+
+func TestCVEAllowlist_InheritAddedInfo(t *testing.T) {
+	added := int64(1573254000)
+	prev := &CVEAllowlist{
+		Items: []CVEAllowlistItem{
+			{CVEID: "CVE-2024-0001", AddedBy: "alice", AddedAt: &added},
+			{CVEID: "CVE-2024-0002", Repository: "library/*", AddedBy: "alice", AddedAt: &added},
+		},
+	}
+	l := &CVEAllowlist{
+		Items: []CVEAllowlistItem{
+			// the added info provided by the user is ignored
+			{CVEID: "CVE-2024-0001", AddedBy: "mallory"},
+			{CVEID: "CVE-2024-0002", Repository: "dev/*"},
+		},
+	}
+	l.InheritAddedInfo(prev, "bob")
+	assert.Equal(t, "alice", l.Items[0].AddedBy)
+	assert.Equal(t, added, *l.Items[0].AddedAt)
+	assert.Equal(t, "bob", l.Items[1].AddedBy)
+	assert.NotNil(t, l.Items[1].AddedAt)
+
+	l.InheritAddedInfo(nil, "carol")
+	assert.Equal(t, "carol", l.Items[0].AddedBy)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	models2 "github.com/goharbor/harbor/src/pkg/allowlist/models"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
 )
 
 type invalidErr struct {
@@ -41,14 +42,19 @@ func IsInvalidErr(err error) bool {
 	return ok
 }
 
-// Validate help validates the CVE allowlist, to ensure the CVE ID is valid and there's no duplication
+// Validate help validates the CVE allowlist, to ensure the repository patterns are valid and there's no duplication,
+// the same CVE ID can appear more than once with different repository or package scopes
 func Validate(wl models2.CVEAllowlist) error {
-	m := map[string]struct{}{}
-	for _, it := range wl.Items {
-		if _, ok := m[it.CVEID]; ok {
-			return &invalidErr{fmt.Sprintf("duplicate CVE ID in allowlist: %s", it.CVEID)}
+	for i, it := range wl.Items {
+		// the syntax error is only reported when the matching reaches it, so match the pattern against itself
+		if _, err := util.Match(it.Repository, it.Repository); err != nil {
+			return &invalidErr{fmt.Sprintf("invalid repository pattern %s of CVE ID %s in allowlist", it.Repository, it.CVEID)}
+		}
+		for _, prev := range wl.Items[:i] {
+			if prev.SameScope(&it) {
+				return &invalidErr{fmt.Sprintf("duplicate CVE ID in allowlist: %s", it.CVEID)}
+			}
 		}
-		m[it.CVEID] = struct{}{}
 	}
 	return nil
 }
@@ -93,6 +93,49 @@ func TestValidate(t *testing.T) {
 			},
 			noError: false,
 		},
+		{
+			l: models2.CVEAllowlist{
+				Items: []models2.CVEAllowlistItem{
+					{CVEID: "CVE-2014-456132"},
+					{CVEID: "CVE-2014-456132", Repository: "library/*"},
+					{CVEID: "CVE-2014-456132", Repository: "library/*", Package: "openssl"},
+				},
+			},
+			noError: true,
+		},
+		{
+			l: models2.CVEAllowlist{
+				Items: []models2.CVEAllowlistItem{
+					{CVEID: "CVE-2014-456132", Repository: "library/*"},
+					{CVEID: "CVE-2014-456132", Repository: "library/*", Justification: "not reachable"},
+				},
+			},
+			noError: false,
+		},
+		{
+			l: models2.CVEAllowlist{
+				Items: []models2.CVEAllowlistItem{
+					{CVEID: "CVE-2014-456132", Repository: "library/["},
+				},
+			},
+			noError: false,
+		},
+		{
+			l: models2.CVEAllowlist{
+				Items: []models2.CVEAllowlistItem{
+					{CVEID: "CVE-2014-456132", Repository: "{library,dev}/**"},
+				},
+			},
+			noError: true,
+		},
+		{
+			l: models2.CVEAllowlist{
+				Items: []models2.CVEAllowlistItem{
+					{CVEID: "CVE-2014-456132", Repository: "{library,dev/**"},
+				},
+			},
+			noError: false,
+		},
 	}
 	for n, c := range cases {
 		t.Logf("Executing TestValidate case: %d\n", n)
@@ -55,6 +55,9 @@ var allowedMaps = map[string]interface{}{
 	strings.ToLower(rbac.ActionPull.String()):   struct{}{},
 	strings.ToLower(rbac.ActionCreate.String()): struct{}{},
 	strings.ToLower(rbac.ActionDelete.String()): struct{}{},
+	strings.ToLower(rbac.ActionUpdate.String()): struct{}{},
+	"login":  struct{}{},
+	"logout": struct{}{},
 }
 
 type dao struct{}
@@ -0,0 +1,254 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+const (
+	activeSegment = "active.jsonl"
+	segmentPrefix = "segment-"
+	segmentSuffix = ".jsonl"
+)
+
+var errBufferFull = errors.New("the audit log buffer is full")
+
+// buffer keeps the audit logs which cannot be delivered for retrying
+type buffer interface {
+	// Append the audit logs into the buffer
+	Append(logs []*model.AuditLog) error
+	// Size returns the total bytes of the buffered audit logs
+	Size() int64
+	// Drain delivers the buffered audit logs by the send function in batches from the oldest,
+	// it stops at the first failure and keeps the undelivered logs in the buffer
+	Drain(batchSize int, send func(logs []*model.AuditLog) error) (int, error)
+}
+
+var (
+	// diskBuffers shares the buffer of the same directory between the sinks, e.g. the sink being stopped
+	// flushes its logs while the new one of the same name starts, so the appending is serialized by one mutex
+	diskBuffers  = map[string]*diskBuffer{}
+	diskBuffersL sync.Mutex
+)
+
+// openDiskBuffer returns the disk buffer of the directory, it is created if not exist
+func openDiskBuffer(dir string, maxSize int64) (*diskBuffer, error) {
+	dir = filepath.Clean(dir)
+	diskBuffersL.Lock()
+	defer diskBuffersL.Unlock()
+	if b, exist := diskBuffers[dir]; exist {
+		b.mu.Lock()
+		b.maxSize = maxSize
+		b.mu.Unlock()
+		return b, nil
+	}
+	b, err := newDiskBuffer(dir, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	diskBuffers[dir] = b
+	return b, nil
+}
+
+// diskBuffer persists the audit logs which cannot be delivered as JSON lines, the logs are appended
+// into the active segment which is sealed when draining, so the delivery keeps the original order
+type diskBuffer struct {
+	dir     string
+	maxSize int64
+	mu      sync.Mutex
+}
+
+func newDiskBuffer(dir string, maxSize int64) (*diskBuffer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &diskBuffer{dir: dir, maxSize: maxSize}, nil
+}
+
+// Append the audit logs into the buffer
+func (b *diskBuffer) Append(logs []*model.AuditLog) error {
+	data := []byte{}
+	for _, l := range logs {
+		line, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.maxSize > 0 && b.size()+int64(len(data)) > b.maxSize {
+		return errBufferFull
+	}
+	file, err := os.OpenFile(filepath.Join(b.dir, activeSegment), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Size returns the total bytes of the buffered audit logs
+func (b *diskBuffer) Size() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size()
+}
+
+func (b *diskBuffer) size() int64 {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return 0
+	}
+	var total int64
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !info.IsDir() {
+			total += info.Size()
+		}
+	}
+	return total
+}
+
+// seal renames the active segment so that it can be drained without blocking the appending
+func (b *diskBuffer) seal() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	active := filepath.Join(b.dir, activeSegment)
+	info, err := os.Stat(active)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+	sealed := filepath.Join(b.dir, fmt.Sprintf("%s%020d%s", segmentPrefix, time.Now().UnixNano(), segmentSuffix))
+	return os.Rename(active, sealed)
+}
+
+func (b *diskBuffer) segments() ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, segmentPrefix) && strings.HasSuffix(name, segmentSuffix) {
+			segments = append(segments, filepath.Join(b.dir, name))
+		}
+	}
+	sort.Strings(segments)
+	return segments, nil
+}
+
+// Drain delivers the buffered audit logs by the send function in batches from the oldest,
+// it stops at the first failure and keeps the undelivered logs in the buffer
+func (b *diskBuffer) Drain(batchSize int, send func(logs []*model.AuditLog) error) (int, error) {
+	if err := b.seal(); err != nil {
+		return 0, err
+	}
+	segments, err := b.segments()
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, segment := range segments {
+		n, err := b.drainSegment(segment, batchSize, send)
+		delivered += n
+		if err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+func (b *diskBuffer) drainSegment(segment string, batchSize int, send func(logs []*model.AuditLog) error) (int, error) {
+	lines, err := readLines(segment)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for start := 0; start < len(lines); start += batchSize {
+		end := start + batchSize
+		if end > len(lines) {
+			end = len(lines)
+		}
+		var logs []*model.AuditLog
+		for _, line := range lines[start:end] {
+			l := &model.AuditLog{}
+			if err := json.Unmarshal([]byte(line), l); err != nil {
+				log.Warningf("skip the corrupted audit log in the buffer %s: %v", segment, err)
+				continue
+			}
+			logs = append(logs, l)
+		}
+		if len(logs) > 0 {
+			if err := send(logs); err != nil {
+				// keep the undelivered logs only to avoid the duplicated delivery
+				if e := writeLines(segment, lines[start:]); e != nil {
+					log.Errorf("failed to rewrite the audit log buffer %s: %v", segment, e)
+				}
+				return delivered, err
+			}
+		}
+		delivered += len(logs)
+	}
+	return delivered, os.Remove(segment)
+}
+
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func writeLines(path string, lines []string) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,160 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+const (
+	redisBufferKeyPrefix = "audit_log_forward:buffer:"
+	// redisDrainLockTTL is the expiration of the lock held while draining, so the buffered logs
+	// aren't delivered repeatedly by the core instances sharing the redis
+	redisDrainLockTTL = 5 * time.Minute
+	redisTimeout      = 30 * time.Second
+)
+
+var errDraining = errors.New("the audit log buffer is being drained by another instance")
+
+// redisBuffer keeps the audit logs which cannot be delivered as JSON lines in a redis list,
+// the total bytes are counted in a separate key for the size limit and the metrics
+type redisBuffer struct {
+	client  *redis.Client
+	key     string
+	maxSize int64
+}
+
+func newRedisBuffer(client *redis.Client, name string, maxSize int64) *redisBuffer {
+	return &redisBuffer{
+		client:  client,
+		key:     redisBufferKeyPrefix + name,
+		maxSize: maxSize,
+	}
+}
+
+func (b *redisBuffer) sizeKey() string {
+	return b.key + ":size"
+}
+
+func (b *redisBuffer) lockKey() string {
+	return b.key + ":lock"
+}
+
+// Append the audit logs into the buffer
+func (b *redisBuffer) Append(logs []*model.AuditLog) error {
+	var (
+		lines []interface{}
+		bytes int64
+	)
+	for _, l := range logs {
+		line, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, line)
+		bytes += int64(len(line))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	if b.maxSize > 0 && b.size(ctx)+bytes > b.maxSize {
+		return errBufferFull
+	}
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, b.key, lines...)
+		pipe.IncrBy(ctx, b.sizeKey(), bytes)
+		return nil
+	})
+	return err
+}
+
+// Size returns the total bytes of the buffered audit logs
+func (b *redisBuffer) Size() int64 {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return b.size(ctx)
+}
+
+func (b *redisBuffer) size(ctx context.Context) int64 {
+	size, err := b.client.Get(ctx, b.sizeKey()).Int64()
+	if err != nil && err != redis.Nil {
+		log.Warningf("failed to get the size of the audit log buffer %s: %v", b.key, err)
+	}
+	return size
+}
+
+// Drain delivers the buffered audit logs by the send function in batches from the oldest,
+// it stops at the first failure and keeps the undelivered logs in the buffer
+func (b *redisBuffer) Drain(batchSize int, send func(logs []*model.AuditLog) error) (int, error) {
+	ctx := context.Background()
+	locked, err := b.client.SetNX(ctx, b.lockKey(), time.Now().Unix(), redisDrainLockTTL).Result()
+	if err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, errDraining
+	}
+	defer b.client.Del(ctx, b.lockKey())
+
+	delivered := 0
+	for {
+		lines, err := b.client.LRange(ctx, b.key, 0, int64(batchSize)-1).Result()
+		if err != nil {
+			return delivered, err
+		}
+		if len(lines) == 0 {
+			return delivered, nil
+		}
+
+		var (
+			logs  []*model.AuditLog
+			bytes int64
+		)
+		for _, line := range lines {
+			bytes += int64(len(line))
+			l := &model.AuditLog{}
+			if err := json.Unmarshal([]byte(line), l); err != nil {
+				log.Warningf("skip the corrupted audit log in the buffer %s: %v", b.key, err)
+				continue
+			}
+			logs = append(logs, l)
+		}
+		if len(logs) > 0 {
+			if err := send(logs); err != nil {
+				return delivered, err
+			}
+		}
+		// only this instance removes the logs from the head of the list while holding the lock
+		if _, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.LTrim(ctx, b.key, int64(len(lines)), -1)
+			pipe.DecrBy(ctx, b.sizeKey(), bytes)
+			return nil
+		}); err != nil {
+			return delivered, err
+		}
+		delivered += len(logs)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+type bufferTestSuite struct {
+	suite.Suite
+	buffer *diskBuffer
+}
+
+func (b *bufferTestSuite) SetupTest() {
+	buffer, err := newDiskBuffer(b.T().TempDir(), 0)
+	b.Require().Nil(err)
+	b.buffer = buffer
+}
+
+func (b *bufferTestSuite) TestDrain() {
+	b.Require().Nil(b.buffer.Append([]*model.AuditLog{{ID: 1}, {ID: 2}, {ID: 3}}))
+	b.True(b.buffer.Size() > 0)
+
+	// the first batch is delivered and the second one fails
+	var delivered []int64
+	n, err := b.buffer.Drain(2, func(logs []*model.AuditLog) error {
+		if len(delivered) > 0 {
+			return errors.New("failure")
+		}
+		for _, l := range logs {
+			delivered = append(delivered, l.ID)
+		}
+		return nil
+	})
+	b.NotNil(err)
+	b.Equal(2, n)
+
+	// appended after the failure
+	b.Require().Nil(b.buffer.Append([]*model.AuditLog{{ID: 4}}))
+	n, err = b.buffer.Drain(2, func(logs []*model.AuditLog) error {
+		for _, l := range logs {
+			delivered = append(delivered, l.ID)
+		}
+		return nil
+	})
+	b.Nil(err)
+	b.Equal(2, n)
+	b.Equal([]int64{1, 2, 3, 4}, delivered)
+	b.Equal(int64(0), b.buffer.Size())
+}
+
+func (b *bufferTestSuite) TestFull() {
+	buffer, err := newDiskBuffer(b.T().TempDir(), 10)
+	b.Require().Nil(err)
+	b.Equal(errBufferFull, buffer.Append([]*model.AuditLog{{ID: 1}}))
+}
+
+func (b *bufferTestSuite) TestOpenDiskBuffer() {
+	dir := b.T().TempDir()
+	b1, err := openDiskBuffer(dir, 0)
+	b.Require().Nil(err)
+	// the buffer of the same directory is shared to serialize the appending
+	b2, err := openDiskBuffer(dir+"/", 10)
+	b.Require().Nil(err)
+	b.Same(b1, b2)
+	b.Equal(int64(10), b1.maxSize)
+}
+
+func (b *bufferTestSuite) TestRedisBuffer() {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+	if err := client.Ping(context.TODO()).Err(); err != nil {
+		b.T().Skipf("redis is unavailable: %v", err)
+	}
+	buffer := newRedisBuffer(client, fmt.Sprintf("test-%d", time.Now().UnixNano()), 0)
+	defer client.Del(context.TODO(), buffer.key, buffer.sizeKey())
+
+	b.Require().Nil(buffer.Append([]*model.AuditLog{{ID: 1}, {ID: 2}, {ID: 3}}))
+	b.True(buffer.Size() > 0)
+
+	var delivered []int64
+	n, err := buffer.Drain(2, func(logs []*model.AuditLog) error {
+		if len(delivered) > 0 {
+			return errors.New("failure")
+		}
+		for _, l := range logs {
+			delivered = append(delivered, l.ID)
+		}
+		return nil
+	})
+	b.NotNil(err)
+	b.Equal(2, n)
+
+	// the lock is held by another instance
+	b.Require().Nil(client.SetNX(context.TODO(), buffer.lockKey(), 1, time.Minute).Err())
+	_, err = buffer.Drain(2, func(_ []*model.AuditLog) error { return nil })
+	b.Equal(errDraining, err)
+	client.Del(context.TODO(), buffer.lockKey())
+
+	n, err = buffer.Drain(2, func(logs []*model.AuditLog) error {
+		for _, l := range logs {
+			delivered = append(delivered, l.ID)
+		}
+		return nil
+	})
+	b.Nil(err)
+	b.Equal(1, n)
+	b.Equal([]int64{1, 2, 3}, delivered)
+	b.Equal(int64(0), buffer.Size())
+}
+
+func TestBufferTestSuite(t *testing.T) {
+	suite.Run(t, &bufferTestSuite{})
+}
@@ -0,0 +1,182 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+// const definitions
+const (
+	TypeSyslog = "syslog"
+	TypeHTTP   = "http"
+	TypeFile   = "file"
+
+	BufferTypeDisk  = "disk"
+	BufferTypeRedis = "redis"
+)
+
+// Forwarder forwards the audit logs to a sink
+type Forwarder interface {
+	// Forward sends the audit logs to the sink, the logs are considered as
+	// delivered only when no error returned
+	Forward(ctx context.Context, logs []*model.AuditLog) error
+	// Close releases the resources held by the forwarder
+	Close() error
+}
+
+// Factory creates a forwarder according to the configuration
+type Factory func(cfg *Config) (Forwarder, error)
+
+var (
+	factories = map[string]Factory{}
+	lock      sync.RWMutex
+)
+
+// RegisterFactory registers the forwarder factory for the specified sink type
+func RegisterFactory(t string, factory Factory) error {
+	if len(t) == 0 {
+		return errors.New("invalid forwarder type")
+	}
+	if factory == nil {
+		return errors.New("empty forwarder factory")
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if _, exist := factories[t]; exist {
+		return fmt.Errorf("forwarder factory for %s already exists", t)
+	}
+	factories[t] = factory
+	return nil
+}
+
+func getFactory(t string) (Factory, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+	factory, exist := factories[t]
+	if !exist {
+		return nil, fmt.Errorf("forwarder factory for %s not found", t)
+	}
+	return factory, nil
+}
+
+// Config is the configuration of one audit log sink
+type Config struct {
+	// Name identifies the sink, it is used as the label of the metrics and the name of the buffer directory
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Endpoint is "host:port" for syslog, the URL for http and the file path for file
+	Endpoint string `json:"endpoint"`
+	// Protocol is one of "tcp", "udp" and "tls", only for syslog
+	Protocol string `json:"protocol,omitempty"`
+	// Insecure skips the verification of the server certificate for the TLS connections
+	Insecure bool              `json:"insecure,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	// MaxSizeMB and MaxBackups control the rotation of the file sink
+	MaxSizeMB  int `json:"max_size_mb,omitempty"`
+	MaxBackups int `json:"max_backups,omitempty"`
+	// BufferType is the type of the retry buffer, "disk" by default or "redis"
+	BufferType string `json:"buffer_type,omitempty"`
+	// BufferDir is the directory to store the audit logs which cannot be delivered for the disk buffer,
+	// it is under the data volume by default to survive restarts
+	BufferDir string `json:"buffer_dir,omitempty"`
+	// BufferMaxSizeMB is the max size of the retry buffer, the new logs are dropped when exceeded
+	BufferMaxSizeMB int     `json:"buffer_max_size_mb,omitempty"`
+	Filter          *Filter `json:"filter,omitempty"`
+}
+
+// Validate the configuration
+func (c *Config) Validate() error {
+	if len(c.Name) == 0 {
+		return errors.New("the name of the audit log forwarder is required")
+	}
+	if strings.ContainsAny(c.Name, `/\`) {
+		return fmt.Errorf("invalid name of the audit log forwarder: %s", c.Name)
+	}
+	if len(c.Endpoint) == 0 {
+		return fmt.Errorf("the endpoint of the audit log forwarder %s is required", c.Name)
+	}
+	if _, err := getFactory(c.Type); err != nil {
+		return err
+	}
+	switch c.BufferType {
+	case "", BufferTypeDisk, BufferTypeRedis:
+	default:
+		return fmt.Errorf("unsupported buffer type %s of the audit log forwarder %s", c.BufferType, c.Name)
+	}
+	if c.Type == TypeSyslog {
+		switch c.Protocol {
+		case "", "tcp", "udp", "tls":
+		default:
+			return fmt.Errorf("unsupported syslog protocol %s of the audit log forwarder %s", c.Protocol, c.Name)
+		}
+	}
+	return nil
+}
+
+// Filter selects the audit logs to be forwarded, empty field matches all
+type Filter struct {
+	ResourceTypes []string `json:"resource_types,omitempty"`
+	Operations    []string `json:"operations,omitempty"`
+}
+
+// Match returns whether the audit log is selected by the filter
+func (f *Filter) Match(log *model.AuditLog) bool {
+	if f == nil {
+		return true
+	}
+	return matchAny(f.ResourceTypes, log.ResourceType) && matchAny(f.Operations, log.Operation)
+}
+
+func matchAny(candidates []string, value string) bool {
+	if len(candidates) == 0 {
+		return true
+	}
+	for _, c := range candidates {
+		if strings.EqualFold(c, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseConfigs parses and validates the JSON encoded sink configurations
+func ParseConfigs(str string) ([]*Config, error) {
+	if len(strings.TrimSpace(str)) == 0 {
+		return nil, nil
+	}
+	var cfgs []*Config
+	if err := json.Unmarshal([]byte(str), &cfgs); err != nil {
+		return nil, errors.Wrap(err, "invalid audit log forwarders")
+	}
+	names := map[string]struct{}{}
+	for _, cfg := range cfgs {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+		if _, exist := names[cfg.Name]; exist {
+			return nil, fmt.Errorf("duplicated audit log forwarder name: %s", cfg.Name)
+		}
+		names[cfg.Name] = struct{}{}
+	}
+	return cfgs, nil
+}
@@ -0,0 +1,203 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/encrypt"
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+type forwarderTestSuite struct {
+	suite.Suite
+}
+
+func (f *forwarderTestSuite) TestParseConfigs() {
+	cfgs, err := ParseConfigs("")
+	f.Require().Nil(err)
+	f.Empty(cfgs)
+
+	cfgs, err = ParseConfigs(`[{"name":"siem","type":"syslog","endpoint":"siem:514","protocol":"tls","filter":{"resource_types":["configuration"]}},
+		{"name":"collector","type":"http","endpoint":"https://collector/audit"}]`)
+	f.Require().Nil(err)
+	f.Require().Len(cfgs, 2)
+	f.Equal("tls", cfgs[0].Protocol)
+	f.Equal([]string{"configuration"}, cfgs[0].Filter.ResourceTypes)
+
+	// invalid JSON
+	_, err = ParseConfigs(`{`)
+	f.NotNil(err)
+	// unknown type
+	_, err = ParseConfigs(`[{"name":"a","type":"kafka","endpoint":"kafka:9092"}]`)
+	f.NotNil(err)
+	// unsupported protocol
+	_, err = ParseConfigs(`[{"name":"a","type":"syslog","endpoint":"siem:514","protocol":"http"}]`)
+	f.NotNil(err)
+	// duplicated names
+	_, err = ParseConfigs(`[{"name":"a","type":"file","endpoint":"/a"},{"name":"a","type":"file","endpoint":"/b"}]`)
+	f.NotNil(err)
+	// invalid name
+	_, err = ParseConfigs(`[{"name":"../a","type":"file","endpoint":"/a"}]`)
+	f.NotNil(err)
+	// unsupported buffer type
+	_, err = ParseConfigs(`[{"name":"a","type":"file","endpoint":"/a","buffer_type":"memory"}]`)
+	f.NotNil(err)
+}
+
+func (f *forwarderTestSuite) TestHeaders() {
+	encryptor := encrypt.NewAESEncryptor(&encrypt.PresetKeyProvider{Key: "naa4JtarA1Zsc3uY"})
+
+	// the values are encrypted
+	stored, err := EncryptHeaders(`[{"name":"collector","type":"http","endpoint":"https://collector/audit","headers":{"Authorization":"Bearer a"}}]`, "", encryptor)
+	f.Require().Nil(err)
+	f.NotContains(stored, "Bearer a")
+	cfgs, err := LoadConfigs(stored, encryptor)
+	f.Require().Nil(err)
+	f.Equal("Bearer a", cfgs[0].Headers["Authorization"])
+
+	// the values are redacted
+	redacted, err := RedactHeaders(stored)
+	f.Require().Nil(err)
+	f.Contains(redacted, `"Authorization":"******"`)
+
+	// the redacted values keep the stored ones
+	updated, err := EncryptHeaders(`[{"name":"collector","type":"http","endpoint":"https://collector/v2","headers":{"Authorization":"******","X-Tenant":"t"}}]`, stored, encryptor)
+	f.Require().Nil(err)
+	cfgs, err = LoadConfigs(updated, encryptor)
+	f.Require().Nil(err)
+	f.Equal(map[string]string{"Authorization": "Bearer a", "X-Tenant": "t"}, cfgs[0].Headers)
+
+	// no stored value for the redacted one
+	_, err = EncryptHeaders(`[{"name":"other","type":"http","endpoint":"https://other","headers":{"Authorization":"******"}}]`, stored, encryptor)
+	f.NotNil(err)
+
+	// the values stored in plain text are kept
+	cfgs, err = LoadConfigs(`[{"name":"collector","type":"http","endpoint":"https://collector/audit","headers":{"Authorization":"Bearer a"}}]`, encryptor)
+	f.Require().Nil(err)
+	f.Equal("Bearer a", cfgs[0].Headers["Authorization"])
+}
+
+func (f *forwarderTestSuite) TestFilter() {
+	var filter *Filter
+	f.True(filter.Match(&model.AuditLog{ResourceType: "artifact", Operation: "pull"}))
+
+	filter = &Filter{ResourceTypes: []string{"artifact"}, Operations: []string{"create", "DELETE"}}
+	f.True(filter.Match(&model.AuditLog{ResourceType: "artifact", Operation: "delete"}))
+	f.False(filter.Match(&model.AuditLog{ResourceType: "artifact", Operation: "pull"}))
+	f.False(filter.Match(&model.AuditLog{ResourceType: "member", Operation: "create"}))
+}
+
+func (f *forwarderTestSuite) TestSyslog() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	f.Require().Nil(err)
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		// octet counting framing: "LEN SP MSG"
+		length, _ := reader.ReadString(' ')
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		msg := make([]byte, n)
+		_, _ = io.ReadFull(reader, msg)
+		received <- string(msg)
+	}()
+
+	fwd, err := newSyslogForwarder(&Config{Name: "syslog", Type: TypeSyslog, Endpoint: listener.Addr().String()})
+	f.Require().Nil(err)
+	defer fwd.Close()
+	err = fwd.Forward(context.TODO(), []*model.AuditLog{{Operation: "create", ResourceType: "project", Resource: "library",
+		OpTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}})
+	f.Require().Nil(err)
+
+	select {
+	case msg := <-received:
+		f.True(strings.HasPrefix(msg, "<14>1 2024-01-01T00:00:00Z "))
+		f.Contains(msg, " harbor-audit ")
+		f.Contains(msg, ` create - {"id":0`)
+	case <-time.After(5 * time.Second):
+		f.Fail("timeout to receive the syslog message")
+	}
+}
+
+func (f *forwarderTestSuite) TestHTTP() {
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.Equal("application/x-ndjson", r.Header.Get("Content-Type"))
+		f.Equal("token", r.Header.Get("Authorization"))
+		data, _ := io.ReadAll(r.Body)
+		lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	fwd, err := newHTTPForwarder(&Config{Name: "http", Type: TypeHTTP, Endpoint: server.URL, Headers: map[string]string{"Authorization": "token"}})
+	f.Require().Nil(err)
+	defer fwd.Close()
+	err = fwd.Forward(context.TODO(), []*model.AuditLog{{ID: 1}, {ID: 2}})
+	f.Require().Nil(err)
+	f.Require().Len(lines, 2)
+	l := &model.AuditLog{}
+	f.Require().Nil(json.Unmarshal([]byte(lines[1]), l))
+	f.Equal(int64(2), l.ID)
+
+	// non 2xx status code
+	fwd, err = newHTTPForwarder(&Config{Name: "http", Type: TypeHTTP, Endpoint: server.URL + "/404"})
+	f.Require().Nil(err)
+	server.Config.Handler = http.NotFoundHandler()
+	f.NotNil(fwd.Forward(context.TODO(), []*model.AuditLog{{ID: 1}}))
+}
+
+func (f *forwarderTestSuite) TestFileRotation() {
+	path := filepath.Join(f.T().TempDir(), "audit", "audit.log")
+	fwd, err := newFileForwarder(&Config{Name: "file", Type: TypeFile, Endpoint: path, MaxBackups: 2})
+	f.Require().Nil(err)
+	defer fwd.Close()
+	// make the file rotated for every log
+	fwd.(*fileForwarder).maxSize = 1
+	for i := 1; i <= 4; i++ {
+		f.Require().Nil(fwd.Forward(context.TODO(), []*model.AuditLog{{ID: int64(i)}}))
+	}
+	for suffix, id := range map[string]string{"": `"id":4`, ".1": `"id":3`, ".2": `"id":2`} {
+		data, err := os.ReadFile(path + suffix)
+		f.Require().Nil(err)
+		f.Contains(string(data), id)
+	}
+	_, err = os.Stat(path + ".3")
+	f.True(os.IsNotExist(err))
+}
+
+func TestForwarderTestSuite(t *testing.T) {
+	suite.Run(t, &forwarderTestSuite{})
+}
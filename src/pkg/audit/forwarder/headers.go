@@ -0,0 +1,111 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/encrypt"
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+// RedactedHeaderValue replaces the header values returned by the API,
+// sending it back keeps the stored value of the header
+const RedactedHeaderValue = "******"
+
+// LoadConfigs parses the stored sink configurations and decrypts the header values
+func LoadConfigs(str string, encryptor encrypt.Encryptor) ([]*Config, error) {
+	cfgs, err := ParseConfigs(str)
+	if err != nil {
+		return nil, err
+	}
+	for _, cfg := range cfgs {
+		for k, v := range cfg.Headers {
+			// the values stored before the encryption is introduced are kept as they are
+			if !strings.HasPrefix(v, utils.EncryptHeaderV1) {
+				continue
+			}
+			if cfg.Headers[k], err = encryptor.Decrypt(v); err != nil {
+				return nil, errors.Wrapf(err, "failed to decrypt the header %s of the audit log forwarder %s", k, cfg.Name)
+			}
+		}
+	}
+	return cfgs, nil
+}
+
+// EncryptHeaders encrypts the header values of the JSON encoded sink configurations to store,
+// the redacted values are replaced with the stored ones of the same sink and header
+func EncryptHeaders(str, stored string, encryptor encrypt.Encryptor) (string, error) {
+	cfgs, err := ParseConfigs(str)
+	if err != nil || len(cfgs) == 0 {
+		return str, err
+	}
+	var storedCfgs []*Config
+	if len(strings.TrimSpace(stored)) > 0 {
+		if err = json.Unmarshal([]byte(stored), &storedCfgs); err != nil {
+			return "", errors.Wrap(err, "invalid stored audit log forwarders")
+		}
+	}
+	storedHeaders := map[string]map[string]string{}
+	for _, cfg := range storedCfgs {
+		storedHeaders[cfg.Name] = cfg.Headers
+	}
+	for _, cfg := range cfgs {
+		for k, v := range cfg.Headers {
+			if v == RedactedHeaderValue {
+				value, exist := storedHeaders[cfg.Name][k]
+				if !exist {
+					return "", errors.BadRequestError(nil).WithMessagef("no stored value for the header %s of the audit log forwarder %s", k, cfg.Name)
+				}
+				v = value
+			}
+			if strings.HasPrefix(v, utils.EncryptHeaderV1) {
+				cfg.Headers[k] = v
+				continue
+			}
+			if cfg.Headers[k], err = encryptor.Encrypt(v); err != nil {
+				return "", errors.Wrapf(err, "failed to encrypt the header %s of the audit log forwarder %s", k, cfg.Name)
+			}
+		}
+	}
+	data, err := json.Marshal(cfgs)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// RedactHeaders replaces the header values of the JSON encoded sink configurations with the placeholder
+func RedactHeaders(str string) (string, error) {
+	if len(strings.TrimSpace(str)) == 0 {
+		return str, nil
+	}
+	var cfgs []*Config
+	if err := json.Unmarshal([]byte(str), &cfgs); err != nil {
+		return "", errors.Wrap(err, "invalid audit log forwarders")
+	}
+	for _, cfg := range cfgs {
+		for k := range cfg.Headers {
+			cfg.Headers[k] = RedactedHeaderValue
+		}
+	}
+	data, err := json.Marshal(cfgs)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,85 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+func init() {
+	_ = RegisterFactory(TypeHTTP, newHTTPForwarder)
+}
+
+// httpForwarder posts the audit logs as JSON lines to the HTTP(S) endpoint
+type httpForwarder struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPForwarder(cfg *Config) (Forwarder, error) {
+	return &httpForwarder{
+		url:     cfg.Endpoint,
+		headers: cfg.Headers,
+		client: &http.Client{
+			Transport: commonhttp.GetHTTPTransport(commonhttp.WithInsecure(cfg.Insecure)),
+			Timeout:   30 * time.Second,
+		},
+	}, nil
+}
+
+// Forward ...
+func (h *httpForwarder) Forward(ctx context.Context, logs []*model.AuditLog) error {
+	body := &bytes.Buffer{}
+	encoder := json.NewEncoder(body)
+	for _, log := range logs {
+		if err := encoder.Encode(log); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to forward the audit logs to %s, status code: %d, body: %s", h.url, resp.StatusCode, string(data))
+	}
+	return nil
+}
+
+// Close ...
+func (h *httpForwarder) Close() error {
+	h.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,293 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/metric"
+	libredis "github.com/goharbor/harbor/src/lib/redis"
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+const (
+	queueSize              = 1024
+	batchSize              = 100
+	flushInterval          = time.Second
+	retryInterval          = 30 * time.Second
+	defaultBufferMaxSizeMB = 512
+	// defaultBufferDir is under the data volume mounted into core, so the buffered logs survive restarts
+	defaultBufferDir = "/data/audit_log_buffer"
+)
+
+// Mgr is the global audit log forwarder manager
+var Mgr = NewManager()
+
+// Manager manages the sinks which the audit logs are forwarded to
+type Manager interface {
+	// Init stops the existing sinks and starts the ones in the configurations
+	Init(ctx context.Context, cfgs []*Config) error
+	// Forward dispatches the audit log to the matched sinks asynchronously
+	Forward(ctx context.Context, log *model.AuditLog)
+	// Enabled returns whether there is any sink configured
+	Enabled() bool
+	// Close stops all the sinks, the queued audit logs are flushed or buffered before returning
+	Close()
+}
+
+// NewManager returns a default implementation of Manager
+func NewManager() Manager {
+	return &manager{}
+}
+
+type manager struct {
+	sinks []*sink
+	mu    sync.RWMutex
+}
+
+func (m *manager) Init(_ context.Context, cfgs []*Config) error {
+	var sinks []*sink
+	for _, cfg := range cfgs {
+		s, err := newSink(cfg)
+		if err != nil {
+			for _, created := range sinks {
+				_ = created.forwarder.Close()
+			}
+			return err
+		}
+		sinks = append(sinks, s)
+	}
+
+	m.mu.Lock()
+	old := m.sinks
+	m.sinks = sinks
+	m.mu.Unlock()
+
+	for _, s := range old {
+		s.stop()
+	}
+	for _, s := range sinks {
+		go s.run()
+	}
+	return nil
+}
+
+func (m *manager) Forward(_ context.Context, l *model.AuditLog) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.sinks {
+		if s.filter.Match(l) {
+			s.enqueue(l)
+		}
+	}
+}
+
+func (m *manager) Enabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.sinks) > 0
+}
+
+func (m *manager) Close() {
+	m.mu.Lock()
+	sinks := m.sinks
+	m.sinks = nil
+	m.mu.Unlock()
+	for _, s := range sinks {
+		s.stop()
+	}
+}
+
+// sink delivers the audit logs in batches, the logs are spilled into the on-disk buffer
+// when the in-memory queue is full or the delivery fails and retried periodically
+type sink struct {
+	name      string
+	filter    *Filter
+	forwarder Forwarder
+	buffer    buffer
+	queue     chan *model.AuditLog
+	// pending indicates there are logs in the buffer, the new logs are buffered too to keep the order
+	pending atomic.Bool
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func newSink(cfg *Config) (*sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	factory, err := getFactory(cfg.Type)
+	if err != nil {
+		return nil, err
+	}
+	forwarder, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	buffer, err := newBuffer(cfg)
+	if err != nil {
+		_ = forwarder.Close()
+		return nil, err
+	}
+	s := &sink{
+		name:      cfg.Name,
+		filter:    cfg.Filter,
+		forwarder: forwarder,
+		buffer:    buffer,
+		queue:     make(chan *model.AuditLog, queueSize),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+	s.pending.Store(buffer.Size() > 0)
+	return s, nil
+}
+
+func newBuffer(cfg *Config) (buffer, error) {
+	maxSizeMB := cfg.BufferMaxSizeMB
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultBufferMaxSizeMB
+	}
+	maxSize := int64(maxSizeMB) * 1024 * 1024
+	if cfg.BufferType == BufferTypeRedis {
+		client, err := libredis.GetHarborClient()
+		if err != nil {
+			return nil, err
+		}
+		return newRedisBuffer(client, cfg.Name, maxSize), nil
+	}
+	dir := cfg.BufferDir
+	if len(dir) == 0 {
+		dir = defaultBufferDir
+	}
+	return openDiskBuffer(filepath.Join(dir, cfg.Name), maxSize)
+}
+
+func (s *sink) enqueue(l *model.AuditLog) {
+	select {
+	case s.queue <- l:
+		metric.AuditLogForwardQueueLength.WithLabelValues(s.name).Set(float64(len(s.queue)))
+	default:
+		// backpressure: spill to the disk rather than blocking the API requests
+		s.spill([]*model.AuditLog{l})
+	}
+}
+
+func (s *sink) spill(logs []*model.AuditLog) {
+	if err := s.buffer.Append(logs); err != nil {
+		log.Errorf("failed to buffer %d audit logs for the sink %s, drop them: %v", len(logs), s.name, err)
+		metric.AuditLogForwardDropped.WithLabelValues(s.name).Add(float64(len(logs)))
+		return
+	}
+	s.pending.Store(true)
+	metric.AuditLogForwardBufferBytes.WithLabelValues(s.name).Set(float64(s.buffer.Size()))
+}
+
+func (s *sink) send(logs []*model.AuditLog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := s.forwarder.Forward(ctx, logs); err != nil {
+		metric.AuditLogForwardTotal.WithLabelValues(s.name, "failure").Add(float64(len(logs)))
+		return err
+	}
+	metric.AuditLogForwardTotal.WithLabelValues(s.name, "success").Add(float64(len(logs)))
+	return nil
+}
+
+func (s *sink) flush(batch []*model.AuditLog) {
+	if len(batch) == 0 {
+		return
+	}
+	if s.pending.Load() {
+		s.spill(batch)
+		return
+	}
+	if err := s.send(batch); err != nil {
+		log.Warningf("failed to forward %d audit logs to the sink %s, buffer them for retrying: %v", len(batch), s.name, err)
+		s.spill(batch)
+	}
+}
+
+func (s *sink) retry() {
+	if !s.pending.Load() {
+		return
+	}
+	n, err := s.buffer.Drain(batchSize, s.send)
+	if err == errDraining {
+		return
+	}
+	if err != nil {
+		log.Warningf("failed to forward the buffered audit logs to the sink %s, %d delivered: %v", s.name, n, err)
+	} else {
+		// the logs spilled during draining are kept in the new active segment
+		s.pending.Store(s.buffer.Size() > 0)
+	}
+	metric.AuditLogForwardBufferBytes.WithLabelValues(s.name).Set(float64(s.buffer.Size()))
+}
+
+func (s *sink) run() {
+	defer close(s.stopped)
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+	retryTicker := time.NewTicker(retryInterval)
+	defer retryTicker.Stop()
+
+	// the sink of the same name being replaced may flush its logs into the buffer before stopping
+	s.pending.Store(s.buffer.Size() > 0)
+	s.retry()
+	var batch []*model.AuditLog
+	for {
+		select {
+		case l := <-s.queue:
+			metric.AuditLogForwardQueueLength.WithLabelValues(s.name).Set(float64(len(s.queue)))
+			batch = append(batch, l)
+			if len(batch) >= batchSize {
+				s.flush(batch)
+				batch = nil
+			}
+		case <-flushTicker.C:
+			s.flush(batch)
+			batch = nil
+		case <-retryTicker.C:
+			s.retry()
+		case <-s.done:
+			// keep the queued logs in the buffer to deliver them after restarting
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+			}
+			s.flush(batch)
+			if err := s.forwarder.Close(); err != nil {
+				log.Warningf("failed to close the audit log forwarder %s: %v", s.name, err)
+			}
+			return
+		}
+	}
+}
+
+func (s *sink) stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	select {
+	case <-s.stopped:
+	case <-time.After(time.Minute):
+		log.Warningf("timeout to stop the audit log sink %s", s.name)
+	}
+}
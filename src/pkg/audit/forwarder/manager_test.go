@@ -0,0 +1,159 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+const typeFake = "fake"
+
+// fakeForwarder records the forwarded logs and fails when broken
+type fakeForwarder struct {
+	mu     sync.Mutex
+	broken bool
+	logs   []*model.AuditLog
+}
+
+func (f *fakeForwarder) Forward(_ context.Context, logs []*model.AuditLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.broken {
+		return errors.New("broken")
+	}
+	f.logs = append(f.logs, logs...)
+	return nil
+}
+
+func (f *fakeForwarder) Close() error {
+	return nil
+}
+
+func (f *fakeForwarder) setBroken(broken bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.broken = broken
+}
+
+func (f *fakeForwarder) ids() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []int64
+	for _, l := range f.logs {
+		ids = append(ids, l.ID)
+	}
+	return ids
+}
+
+var fake = &fakeForwarder{}
+
+func init() {
+	_ = RegisterFactory(typeFake, func(_ *Config) (Forwarder, error) {
+		return fake, nil
+	})
+}
+
+type managerTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (m *managerTestSuite) SetupTest() {
+	fake.logs = nil
+	fake.setBroken(false)
+	m.dir = m.T().TempDir()
+}
+
+func (m *managerTestSuite) TestForwardWithFilter() {
+	mgr := NewManager()
+	err := mgr.Init(context.TODO(), []*Config{{Name: "fake", Type: typeFake, Endpoint: "fake", BufferDir: m.dir,
+		Filter: &Filter{Operations: []string{"delete"}}}})
+	m.Require().Nil(err)
+	m.True(mgr.Enabled())
+
+	mgr.Forward(context.TODO(), &model.AuditLog{ID: 1, Operation: "delete"})
+	mgr.Forward(context.TODO(), &model.AuditLog{ID: 2, Operation: "pull"})
+	// flushed when closing
+	mgr.Close()
+	m.False(mgr.Enabled())
+	m.Equal([]int64{1}, fake.ids())
+}
+
+func (m *managerTestSuite) TestRetryFromBuffer() {
+	fake.setBroken(true)
+	s, err := newSink(&Config{Name: "fake", Type: typeFake, Endpoint: "fake", BufferDir: m.dir})
+	m.Require().Nil(err)
+
+	s.flush([]*model.AuditLog{{ID: 1}, {ID: 2}})
+	m.True(s.pending.Load())
+	m.Empty(fake.ids())
+	// new logs are buffered to keep the order while there are pending ones
+	fake.setBroken(false)
+	s.flush([]*model.AuditLog{{ID: 3}})
+	m.Empty(fake.ids())
+
+	s.retry()
+	m.False(s.pending.Load())
+	m.Equal([]int64{1, 2, 3}, fake.ids())
+	m.Equal(int64(0), s.buffer.Size())
+}
+
+func (m *managerTestSuite) TestBufferSurvivesRestart() {
+	fake.setBroken(true)
+	s, err := newSink(&Config{Name: "fake", Type: typeFake, Endpoint: "fake", BufferDir: m.dir})
+	m.Require().Nil(err)
+	s.flush([]*model.AuditLog{{ID: 1}})
+
+	fake.setBroken(false)
+	mgr := NewManager()
+	m.Require().Nil(mgr.Init(context.TODO(), []*Config{{Name: "fake", Type: typeFake, Endpoint: "fake", BufferDir: m.dir}}))
+	defer mgr.Close()
+	m.Eventually(func() bool {
+		return len(fake.ids()) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func (m *managerTestSuite) TestReplaceSink() {
+	cfg := &Config{Name: "fake", Type: typeFake, Endpoint: "fake", BufferDir: m.dir}
+	old, err := newSink(cfg)
+	m.Require().Nil(err)
+	s, err := newSink(cfg)
+	m.Require().Nil(err)
+	// the sinks of the same name share the buffer
+	m.Same(old.buffer, s.buffer)
+
+	// the old sink flushes its logs into the buffer after the new one is created
+	fake.setBroken(true)
+	old.flush([]*model.AuditLog{{ID: 1}})
+	m.False(s.pending.Load())
+
+	// the new sink delivers the buffered logs once started
+	fake.setBroken(false)
+	go s.run()
+	s.stop()
+	m.Equal([]int64{1}, fake.ids())
+}
+
+func TestManagerTestSuite(t *testing.T) {
+	suite.Run(t, &managerTestSuite{})
+}
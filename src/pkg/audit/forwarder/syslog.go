@@ -0,0 +1,134 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forwarder
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/pkg/audit/model"
+)
+
+const (
+	// facility user(1) and severity informational(6)
+	syslogPriority = 1*8 + 6
+	syslogAppName  = "harbor-audit"
+	dialTimeout    = 10 * time.Second
+)
+
+func init() {
+	_ = RegisterFactory(TypeSyslog, newSyslogForwarder)
+}
+
+// syslogForwarder sends the audit logs as RFC5424 messages, the messages sent over
+// the stream transports are framed by octet counting as described in RFC6587
+type syslogForwarder struct {
+	protocol string
+	address  string
+	insecure bool
+	hostname string
+	conn     net.Conn
+	mu       sync.Mutex
+}
+
+func newSyslogForwarder(cfg *Config) (Forwarder, error) {
+	protocol := cfg.Protocol
+	if len(protocol) == 0 {
+		protocol = "tcp"
+	}
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) == 0 {
+		hostname = "-"
+	}
+	return &syslogForwarder{
+		protocol: protocol,
+		address:  cfg.Endpoint,
+		insecure: cfg.Insecure,
+		hostname: hostname,
+	}, nil
+}
+
+func (s *syslogForwarder) dial() (net.Conn, error) {
+	if s.protocol == "tls" {
+		dialer := &net.Dialer{Timeout: dialTimeout}
+		return tls.DialWithDialer(dialer, "tcp", s.address, &tls.Config{
+			InsecureSkipVerify: s.insecure, // #nosec G402
+		})
+	}
+	return net.DialTimeout(s.protocol, s.address, dialTimeout)
+}
+
+// Forward ...
+func (s *syslogForwarder) Forward(_ context.Context, logs []*model.AuditLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	for _, log := range logs {
+		msg, err := formatRFC5424(log, s.hostname)
+		if err != nil {
+			return err
+		}
+		if s.protocol != "udp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+		if _, err = s.conn.Write([]byte(msg)); err != nil {
+			// reconnect in the next round
+			_ = s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// Close ...
+func (s *syslogForwarder) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// formatRFC5424 formats the audit log as "<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG"
+// with the JSON encoded audit log as the message
+func formatRFC5424(log *model.AuditLog, hostname string) (string, error) {
+	data, err := json.Marshal(log)
+	if err != nil {
+		return "", err
+	}
+	msgID := log.Operation
+	if len(msgID) == 0 {
+		msgID = "-"
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s - %s", syslogPriority,
+		log.OpTime.UTC().Format(time.RFC3339Nano), hostname, syslogAppName, os.Getpid(), msgID, data), nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/config"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/audit/dao"
+	"github.com/goharbor/harbor/src/pkg/audit/forwarder"
 	"github.com/goharbor/harbor/src/pkg/audit/model"
 )
 
@@ -81,6 +82,9 @@ func (m *manager) Create(ctx context.Context, audit *model.AuditLog) (int64, err
 			WithField("time", audit.OpTime).WithField("resourceType", audit.ResourceType).
 			Infof("action:%s, resource:%s", audit.Operation, audit.Resource)
 	}
+	if forwarder.Mgr.Enabled() {
+		forwarder.Mgr.Forward(ctx, audit)
+	}
 	if config.SkipAuditLogDatabase(ctx) {
 		return 0, nil
 	}
@@ -26,13 +26,20 @@ func init() {
 
 // AuditLog ...
 type AuditLog struct {
-	ID           int64     `orm:"pk;auto;column(id)" json:"id"`
-	ProjectID    int64     `orm:"column(project_id)" json:"project_id"`
-	Operation    string    `orm:"column(operation)" json:"operation"`
-	ResourceType string    `orm:"column(resource_type)"  json:"resource_type"`
-	Resource     string    `orm:"column(resource)" json:"resource"`
-	Username     string    `orm:"column(username)"  json:"username"`
-	OpTime       time.Time `orm:"column(op_time)" json:"op_time" sort:"default:desc"`
+	ID                   int64     `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID            int64     `orm:"column(project_id)" json:"project_id"`
+	Operation            string    `orm:"column(operation)" json:"operation"`
+	OperationDescription string    `orm:"column(operation_description)" json:"operation_description"`
+	IsSuccessful         bool      `orm:"column(is_successful)" json:"is_successful"`
+	ResourceType         string    `orm:"column(resource_type)"  json:"resource_type"`
+	Resource             string    `orm:"column(resource)" json:"resource"`
+	Username             string    `orm:"column(username)"  json:"username"`
+	OpTime               time.Time `orm:"column(op_time)" json:"op_time" sort:"default:desc"`
+	// Payload is the JSON encoded before/after diff of the changed resource
+	Payload    string `orm:"column(payload)" json:"payload"`
+	ClientIP   string `orm:"column(client_ip)" json:"client_ip"`
+	UserAgent  string `orm:"column(user_agent)" json:"user_agent"`
+	AuthMethod string `orm:"column(auth_method)" json:"auth_method"`
 }
 
 // TableName for audit log
@@ -28,15 +28,31 @@ import (
 // and we should expand it when needed
 type Client interface {
 	ArtifactClient
+	WebhookClient
 }
 
 // ArtifactClient defines the methods that an image client should implement
 type ArtifactClient interface {
-	ListAllArtifacts(project, repository string) ([]*modelsv2.Artifact, error)
+	ListAllArtifacts(project, repository string, option *ListArtifactsOption) ([]*modelsv2.Artifact, error)
 	DeleteArtifact(project, repository, digest string) error
+	CopyArtifact(project, repository, from string) error
 	DeleteArtifactRepository(project, repository string) error
 }
 
+// WebhookClient defines the methods that a webhook client should implement
+type WebhookClient interface {
+	SignWebhookPayload(policyID int64, payload []byte) (http.Header, error)
+}
+
+// ListArtifactsOption specifies the extra information to list with the artifacts, only the tagged
+// artifacts and the untagged ones not referenced by the indexes are listed when it's nil
+type ListArtifactsOption struct {
+	// WithChildren lists the child artifacts referenced by the indexes as well
+	WithChildren     bool
+	WithAccessory    bool
+	WithScanOverview bool
+}
+
 // New returns an instance of the client which is a default implement for Client
 func New(url string, httpclient *http.Client, modifiers ...modifier.Modifier) Client {
 	return &client{
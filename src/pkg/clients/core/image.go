@@ -16,14 +16,32 @@ package core
 
 import (
 	"fmt"
+	neturl "net/url"
 
 	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/lib/encode/repository"
 )
 
-func (c *client) ListAllArtifacts(project, repo string) ([]*modelsv2.Artifact, error) {
+// ListAllArtifacts lists the artifacts under the repository, the untagged child artifacts referenced by the
+// indexes, the accessories and the vulnerability scan overview are only listed when the option requires
+func (c *client) ListAllArtifacts(project, repo string, option *ListArtifactsOption) ([]*modelsv2.Artifact, error) {
 	repo = repository.Encode(repo)
+	query := neturl.Values{}
+	if option != nil {
+		if option.WithChildren {
+			query.Set("q", "base=*")
+		}
+		if option.WithAccessory {
+			query.Set("with_accessory", "true")
+		}
+		if option.WithScanOverview {
+			query.Set("with_scan_overview", "true")
+		}
+	}
 	url := c.buildURL(fmt.Sprintf("/api/v2.0/projects/%s/repositories/%s/artifacts", project, repo))
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
 	var arts []*modelsv2.Artifact
 	if err := c.httpclient.GetAndIteratePagination(url, &arts); err != nil {
 		return nil, err
@@ -37,6 +55,13 @@ func (c *client) DeleteArtifact(project, repo, digest string) error {
 	return c.httpclient.Delete(url)
 }
 
+// CopyArtifact copies the artifact specified by "from" (project/repository@digest) into the repository
+func (c *client) CopyArtifact(project, repo, from string) error {
+	repo = repository.Encode(repo)
+	url := c.buildURL(fmt.Sprintf("/api/v2.0/projects/%s/repositories/%s/artifacts?from=%s", project, repo, neturl.QueryEscape(from)))
+	return c.httpclient.Post(url)
+}
+
 func (c *client) DeleteArtifactRepository(project, repo string) error {
 	repo = repository.Encode(repo)
 	url := c.buildURL(fmt.Sprintf("/api/repositories/%s/%s", project, repo))
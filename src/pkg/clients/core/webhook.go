@@ -0,0 +1,62 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	chttp "github.com/goharbor/harbor/src/common/http"
+)
+
+// WebhookSignaturePath is the path of the internal API which signs the webhook payloads with the secrets of the policy
+const WebhookSignaturePath = "/service/webhook/policies/%d/signature"
+
+// SignWebhookPayload returns the headers carrying the timestamp and the signatures of the payload,
+// the payload is signed by the core so that the signing secrets are never passed to the jobservice
+func (c *client) SignWebhookPayload(policyID int64, payload []byte) (http.Header, error) {
+	data, err := json.Marshal(map[string]string{"payload": string(payload)})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.buildURL(fmt.Sprintf(WebhookSignaturePath, policyID)), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &chttp.Error{
+			Code:    resp.StatusCode,
+			Message: string(data),
+		}
+	}
+	header := http.Header{}
+	if err = json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
@@ -28,12 +28,16 @@ import (
 // Matcher ...
 type Matcher struct {
 	rules []*model.Metadata
+	// the rules are provided rather than loaded from the project
+	preset bool
 }
 
 // Match ...
 func (rm *Matcher) Match(ctx context.Context, pid int64, c iselector.Candidate) (bool, error) {
-	if err := rm.getImmutableRules(ctx, pid); err != nil {
-		return false, err
+	if !rm.preset {
+		if err := rm.getImmutableRules(ctx, pid); err != nil {
+			return false, err
+		}
 	}
 
 	cands := []*iselector.Candidate{&c}
@@ -101,3 +105,12 @@ func (rm *Matcher) getImmutableRules(ctx context.Context, pid int64) error {
 func NewRuleMatcher() match.ImmutableTagMatcher {
 	return &Matcher{}
 }
+
+// NewRuleMatcherWithRules returns a matcher matching against the provided rules
+// instead of the ones stored for the project, e.g. the unsaved rules
+func NewRuleMatcherWithRules(rules []*model.Metadata) match.ImmutableTagMatcher {
+	return &Matcher{
+		rules:  rules,
+		preset: true,
+	}
+}
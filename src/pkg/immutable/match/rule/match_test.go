@@ -147,6 +147,54 @@ func (s *MatchTestSuite) TestImmuMatch() {
 	s.require.Nil(err)
 }
 
+// TestImmuMatchWithRules tests matching against the provided rules
+func (s *MatchTestSuite) TestImmuMatchWithRules() {
+	match := NewRuleMatcherWithRules([]*model.Metadata{
+		{
+			ProjectID: 2,
+			Priority:  1,
+			Action:    "immutable",
+			Template:  "immutable_template",
+			TagSelectors: []*model.Selector{
+				{
+					Kind:       "doublestar",
+					Decoration: "matches",
+					Pattern:    "v**",
+				},
+			},
+			ScopeSelectors: map[string][]*model.Selector{
+				"repository": {
+					{
+						Kind:       "doublestar",
+						Decoration: "repoMatches",
+						Pattern:    "**",
+					},
+				},
+			},
+		},
+	})
+
+	isMatch, err := match.Match(orm.Context(), 2, selector.Candidate{
+		NamespaceID: 2,
+		Namespace:   "unsaved",
+		Repository:  "nginx",
+		Tags:        []string{"v1.0"},
+		Kind:        selector.Image,
+	})
+	s.require.Nil(err)
+	s.require.True(isMatch)
+
+	isMatch, err = match.Match(orm.Context(), 2, selector.Candidate{
+		NamespaceID: 2,
+		Namespace:   "unsaved",
+		Repository:  "nginx",
+		Tags:        []string{"latest"},
+		Kind:        selector.Image,
+	})
+	s.require.Nil(err)
+	s.require.False(isMatch)
+}
+
 // TearDownSuite clears env for test suite
 func (s *MatchTestSuite) TearDownSuite() {
 	err := s.ctr.DeleteImmutableRule(orm.Context(), s.ruleID)
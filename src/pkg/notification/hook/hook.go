@@ -46,14 +46,7 @@ func NewHookManager() *DefaultManager {
 
 // StartHook create a webhook job record in database, and submit it to jobservice
 func (hm *DefaultManager) StartHook(ctx context.Context, event *model.HookEvent, data *models.JobData) error {
-	var vendorType string
-	switch event.Target.Type {
-	case model.NotifyTypeHTTP:
-		vendorType = job.WebhookJobVendorType
-	case model.NotifyTypeSlack:
-		vendorType = job.SlackJobVendorType
-	}
-
+	vendorType := model.VendorTypeOf(event.Target.Type)
 	if len(vendorType) == 0 {
 		return errors.Errorf("invalid event target type: %s", event.Target.Type)
 	}
@@ -61,6 +54,10 @@ func (hm *DefaultManager) StartHook(ctx context.Context, event *model.HookEvent,
 	extraAttrs := map[string]interface{}{
 		"event_type": event.EventType,
 		"payload":    data.Parameters["payload"],
+		"address":    event.Target.Address,
+	}
+	if len(event.DeliveryID) > 0 {
+		extraAttrs["delivery_id"] = event.DeliveryID
 	}
 	// create execution firstly, then create task.
 	execID, err := hm.execMgr.Create(ctx, vendorType, event.PolicyID, task.ExecutionTriggerEvent, extraAttrs)
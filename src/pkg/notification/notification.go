@@ -58,6 +58,10 @@ var (
 	// supportedEventTypes is a slice to store supported event type, eg. pushImage, pullImage etc
 	supportedEventTypes []EventType
 
+	// supportedSystemEventTypes is a slice to store the event types supported by the system level policies,
+	// it contains the project event types and the global ones, eg. createProject, gcCompleted etc
+	supportedSystemEventTypes []EventType
+
 	// supportedNotifyTypes is a slice to store notification type, eg. HTTP, Email etc
 	supportedNotifyTypes []NotifyType
 
@@ -79,6 +83,7 @@ func Init() {
 
 func initSupportedNotifyType() {
 	supportedEventTypes = make([]EventType, 0)
+	supportedSystemEventTypes = make([]EventType, 0)
 	supportedNotifyTypes = make([]NotifyType, 0)
 
 	eventTypes := []string{
@@ -97,7 +102,24 @@ func initSupportedNotifyType() {
 		supportedEventTypes = append(supportedEventTypes, EventType(eventType))
 	}
 
-	notifyTypes := []string{notifier_model.NotifyTypeHTTP, notifier_model.NotifyTypeSlack}
+	systemEventTypes := []string{
+		event.TopicCreateProject,
+		event.TopicDeleteProject,
+		event.TopicGCCompleted,
+		event.TopicPurgeAuditCompleted,
+		event.TopicScanAllCompleted,
+		event.TopicJobQueueLatency,
+		event.TopicStorageLow,
+		event.TopicScannerHealthChanged,
+		event.TopicCVEAllowlistExpiring,
+	}
+	supportedSystemEventTypes = append(supportedSystemEventTypes, supportedEventTypes...)
+	for _, eventType := range systemEventTypes {
+		supportedSystemEventTypes = append(supportedSystemEventTypes, EventType(eventType))
+	}
+
+	notifyTypes := []string{notifier_model.NotifyTypeHTTP, notifier_model.NotifyTypeSlack, notifier_model.NotifyTypeEmail,
+		notifier_model.NotifyTypeTeams, notifier_model.NotifyTypeGoogleChat, notifier_model.NotifyTypeCustom}
 	for _, notifyType := range notifyTypes {
 		supportedNotifyTypes = append(supportedNotifyTypes, NotifyType(notifyType))
 	}
@@ -153,6 +175,10 @@ func GetSupportedEventTypes() []EventType {
 	return supportedEventTypes
 }
 
+func GetSupportedSystemEventTypes() []EventType {
+	return supportedSystemEventTypes
+}
+
 func GetSupportedNotifyTypes() []NotifyType {
 	return supportedNotifyTypes
 }
@@ -29,8 +29,8 @@ type DAO interface {
 	// Create ...
 	Create(ctx context.Context, n *model.Policy) (int64, error)
 
-	// Update ...
-	Update(ctx context.Context, n *model.Policy) error
+	// Update updates the specified properties of the policy, all the properties are updated if no one specified
+	Update(ctx context.Context, n *model.Policy, props ...string) error
 
 	// Get ...
 	Get(ctx context.Context, id int64) (*model.Policy, error)
@@ -92,7 +92,7 @@ func (d *dao) Create(ctx context.Context, policy *model.Policy) (int64, error) {
 }
 
 // Update ...
-func (d *dao) Update(ctx context.Context, policy *model.Policy) error {
+func (d *dao) Update(ctx context.Context, policy *model.Policy, props ...string) error {
 	if policy == nil {
 		return errors.New("nil policy")
 	}
@@ -101,7 +101,7 @@ func (d *dao) Update(ctx context.Context, policy *model.Policy) error {
 	if err != nil {
 		return err
 	}
-	n, err := ormer.Update(policy)
+	n, err := ormer.Update(policy, props...)
 	if n == 0 {
 		if e := orm.AsConflictError(err, "notification policy named %s already exists", policy.Name); e != nil {
 			err = e
@@ -19,6 +19,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/config"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/notification/policy/dao"
 	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
@@ -45,18 +48,32 @@ type Manager interface {
 	Delete(ctx context.Context, policyID int64) error
 	// GetRelatedPolices get event type related policies in project
 	GetRelatedPolices(ctx context.Context, projectID int64, eventType string) ([]*model.Policy, error)
+	// RotateSigningSecret replaces the signing secret of the policy with the provided one, the replaced
+	// secret keeps signing the payloads during the overlap. The signing is disabled if the secret is empty
+	RotateSigningSecret(ctx context.Context, policyID int64, secret string, overlap time.Duration) error
+	// GetSigningSecrets returns the decrypted secrets which sign the payloads of the policy currently,
+	// the latest one comes first
+	GetSigningSecrets(ctx context.Context, policyID int64) ([]string, error)
 }
 
+// updatableColumns are the columns updated by Update, the signing secrets are excluded as they
+// are only changed by RotateSigningSecret
+var updatableColumns = []string{"Name", "Description", "ProjectID", "TargetsDB", "EventTypesDB", "FiltersDB",
+	"Creator", "CreationTime", "UpdateTime", "Enabled"}
+
 var _ Manager = &manager{}
 
 type manager struct {
 	dao dao.DAO
+	// secretKey returns the key to encrypt the signing secrets
+	secretKey func() (string, error)
 }
 
 // NewManager ...
 func NewManager() Manager {
 	return &manager{
-		dao: dao.New(),
+		dao:       dao.New(),
+		secretKey: config.SecretKey,
 	}
 }
 
@@ -119,7 +136,7 @@ func (m *manager) Update(ctx context.Context, policy *model.Policy) error {
 	if err != nil {
 		return err
 	}
-	return m.dao.Update(ctx, policy)
+	return m.dao.Update(ctx, policy, updatableColumns...)
 }
 
 // Delete the specified notification policy
@@ -127,9 +144,14 @@ func (m *manager) Delete(ctx context.Context, policyID int64) error {
 	return m.dao.Delete(ctx, policyID)
 }
 
-// GetRelatedPolices get policies including event type in project
+// GetRelatedPolices get policies including event type in project, the system level policies
+// subscribing to the events across all the projects are included
 func (m *manager) GetRelatedPolices(ctx context.Context, projectID int64, eventType string) ([]*model.Policy, error) {
-	policies, err := m.List(ctx, q.New(q.KeyWords{"project_id": projectID}))
+	projectIDs := []interface{}{model.SystemProjectID}
+	if projectID != model.SystemProjectID {
+		projectIDs = append(projectIDs, projectID)
+	}
+	policies, err := m.List(ctx, q.New(q.KeyWords{"project_id": q.NewOrList(projectIDs)}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get notification policies with projectID %d: %v", projectID, err)
 	}
@@ -150,3 +172,59 @@ func (m *manager) GetRelatedPolices(ctx context.Context, projectID int64, eventT
 
 	return result, nil
 }
+
+// RotateSigningSecret replaces the signing secret of the policy
+func (m *manager) RotateSigningSecret(ctx context.Context, policyID int64, secret string, overlap time.Duration) error {
+	policy, err := m.dao.Get(ctx, policyID)
+	if err != nil {
+		return err
+	}
+
+	policy.PreviousSigningSecret = ""
+	policy.PreviousSecretExpiresAt = time.Time{}
+	if len(secret) > 0 && len(policy.SigningSecret) > 0 && overlap > 0 {
+		policy.PreviousSigningSecret = policy.SigningSecret
+		policy.PreviousSecretExpiresAt = time.Now().Add(overlap)
+	}
+	policy.SigningSecret = ""
+	if len(secret) > 0 {
+		key, err := m.secretKey()
+		if err != nil {
+			return err
+		}
+		if policy.SigningSecret, err = utils.ReversibleEncrypt(secret, key); err != nil {
+			return err
+		}
+	}
+	policy.UpdateTime = time.Now()
+	return m.dao.Update(ctx, policy, "SigningSecret", "PreviousSigningSecret", "PreviousSecretExpiresAt", "UpdateTime")
+}
+
+// GetSigningSecrets returns the secrets signing the payloads of the policy
+func (m *manager) GetSigningSecrets(ctx context.Context, policyID int64) ([]string, error) {
+	policy, err := m.dao.Get(ctx, policyID)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := []string{policy.SigningSecret}
+	if time.Now().Before(policy.PreviousSecretExpiresAt) {
+		encrypted = append(encrypted, policy.PreviousSigningSecret)
+	}
+
+	var secrets []string
+	for _, e := range encrypted {
+		if len(e) == 0 {
+			continue
+		}
+		key, err := m.secretKey()
+		if err != nil {
+			return nil, err
+		}
+		secret, err := utils.ReversibleDecrypt(e, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt the signing secret of the policy %d", policyID)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
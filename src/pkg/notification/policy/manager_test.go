@@ -3,9 +3,11 @@ package policy
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
 	"github.com/goharbor/harbor/src/testing/mock"
 	"github.com/goharbor/harbor/src/testing/pkg/notification/policy/dao"
@@ -21,6 +23,9 @@ func (m *managerTestSuite) SetupTest() {
 	m.dao = &dao.DAO{}
 	m.mgr = &manager{
 		dao: m.dao,
+		secretKey: func() (string, error) {
+			return "0123456789abcdef", nil
+		},
 	}
 }
 
@@ -39,7 +44,11 @@ func (m *managerTestSuite) TestDelete() {
 }
 
 func (m *managerTestSuite) TestUpdate() {
-	m.dao.On("Update", mock.Anything, mock.Anything).Return(nil)
+	args := []interface{}{mock.Anything, mock.Anything}
+	for _, column := range updatableColumns {
+		args = append(args, column)
+	}
+	m.dao.On("Update", args...).Return(nil)
 	err := m.mgr.Update(context.Background(), &model.Policy{})
 	m.Nil(err)
 	m.dao.AssertExpectations(m.T())
@@ -67,6 +76,46 @@ func (m *managerTestSuite) TestList() {
 	m.Equal(1, len(rpers))
 	m.dao.AssertExpectations(m.T())
 }
+func (m *managerTestSuite) TestRotateSigningSecret() {
+	policy := &model.Policy{ID: 1}
+	m.dao.On("Get", mock.Anything, int64(1)).Return(policy, nil)
+	m.dao.On("Update", mock.Anything, policy, "SigningSecret", "PreviousSigningSecret", "PreviousSecretExpiresAt", "UpdateTime").Return(nil)
+
+	// enable the signing
+	m.Require().Nil(m.mgr.RotateSigningSecret(context.Background(), 1, "first", time.Hour))
+	m.NotEmpty(policy.SigningSecret)
+	m.NotEqual("first", policy.SigningSecret)
+	m.Empty(policy.PreviousSigningSecret)
+	secrets, err := m.mgr.GetSigningSecrets(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Equal([]string{"first"}, secrets)
+
+	// rotate with the overlap
+	m.Require().Nil(m.mgr.RotateSigningSecret(context.Background(), 1, "second", time.Hour))
+	secrets, err = m.mgr.GetSigningSecrets(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Equal([]string{"second", "first"}, secrets)
+
+	// the previous secret expires
+	policy.PreviousSecretExpiresAt = time.Now().Add(-time.Second)
+	secrets, err = m.mgr.GetSigningSecrets(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Equal([]string{"second"}, secrets)
+
+	// rotate without the overlap
+	m.Require().Nil(m.mgr.RotateSigningSecret(context.Background(), 1, "third", 0))
+	secrets, err = m.mgr.GetSigningSecrets(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Equal([]string{"third"}, secrets)
+
+	// disable the signing
+	m.Require().Nil(m.mgr.RotateSigningSecret(context.Background(), 1, "", time.Hour))
+	secrets, err = m.mgr.GetSigningSecrets(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Empty(secrets)
+	m.Empty(policy.PreviousSigningSecret)
+}
+
 func (m *managerTestSuite) TestGetRelatedPolices() {
 	m.dao.On("List", mock.Anything, mock.Anything).Return([]*model.Policy{
 		{
@@ -88,6 +137,9 @@ func (m *managerTestSuite) TestGetRelatedPolices() {
 	m.Nil(err)
 	m.Equal(2, len(rpers))
 	m.dao.AssertExpectations(m.T())
+	// the system level policies are included
+	query := m.dao.Calls[0].Arguments.Get(1).(*q.Query)
+	m.Equal(&q.OrList{Values: []interface{}{model.SystemProjectID, int64(1)}}, query.Keywords["project_id"])
 }
 
 func TestManager(t *testing.T) {
@@ -25,20 +25,37 @@ func init() {
 	orm.RegisterModel(&Policy{})
 }
 
+// SystemProjectID is the project ID of the system level policies which are owned by the system admin
+// and subscribe to the events across all the projects and the system events
+const SystemProjectID int64 = 0
+
 // Policy ...
+// The signing secrets are encrypted, the previous signing secret replaced by the latest rotation
+// keeps signing the payloads until PreviousSecretExpiresAt.
 type Policy struct {
-	ID           int64         `orm:"pk;auto;column(id)" json:"id"`
-	Name         string        `orm:"column(name)" json:"name"`
-	Description  string        `orm:"column(description)" json:"description"`
-	ProjectID    int64         `orm:"column(project_id)" json:"project_id"`
-	TargetsDB    string        `orm:"column(targets)" json:"-"`
-	Targets      []EventTarget `orm:"-" json:"targets"`
-	EventTypesDB string        `orm:"column(event_types)" json:"-"`
-	EventTypes   []string      `orm:"-" json:"event_types"`
-	Creator      string        `orm:"column(creator)" json:"creator"`
-	CreationTime time.Time     `orm:"column(creation_time);auto_now_add" json:"creation_time" sort:"default:desc"`
-	UpdateTime   time.Time     `orm:"column(update_time);auto_now_add" json:"update_time"`
-	Enabled      bool          `orm:"column(enabled)" json:"enabled"`
+	ID                      int64         `orm:"pk;auto;column(id)" json:"id"`
+	Name                    string        `orm:"column(name)" json:"name"`
+	Description             string        `orm:"column(description)" json:"description"`
+	ProjectID               int64         `orm:"column(project_id)" json:"project_id"`
+	TargetsDB               string        `orm:"column(targets)" json:"-"`
+	Targets                 []EventTarget `orm:"-" json:"targets"`
+	EventTypesDB            string        `orm:"column(event_types)" json:"-"`
+	EventTypes              []string      `orm:"-" json:"event_types"`
+	FiltersDB               string        `orm:"column(filters)" json:"-"`
+	Filters                 *EventFilters `orm:"-" json:"filters,omitempty"`
+	Creator                 string        `orm:"column(creator)" json:"creator"`
+	CreationTime            time.Time     `orm:"column(creation_time);auto_now_add" json:"creation_time" sort:"default:desc"`
+	UpdateTime              time.Time     `orm:"column(update_time);auto_now_add" json:"update_time"`
+	Enabled                 bool          `orm:"column(enabled)" json:"enabled"`
+	SigningSecret           string        `orm:"column(signing_secret)" json:"-"`
+	PreviousSigningSecret   string        `orm:"column(previous_signing_secret)" json:"-"`
+	PreviousSecretExpiresAt time.Time     `orm:"column(previous_secret_expires_at);null" json:"-"`
+	SigningEnabled          bool          `orm:"-" json:"signing_enabled"`
+}
+
+// IsSystem returns whether the policy is a system level policy
+func (w *Policy) IsSystem() bool {
+	return w.ProjectID == SystemProjectID
 }
 
 // TableName set table name for ORM.
@@ -62,6 +79,14 @@ func (w *Policy) ConvertToDBModel() error {
 		}
 		w.EventTypesDB = string(eventTypes)
 	}
+	w.FiltersDB = ""
+	if !w.Filters.IsEmpty() {
+		filters, err := json.Marshal(w.Filters)
+		if err != nil {
+			return err
+		}
+		w.FiltersDB = string(filters)
+	}
 
 	return nil
 }
@@ -86,6 +111,16 @@ func (w *Policy) ConvertFromDBModel() error {
 	}
 	w.EventTypes = types
 
+	w.Filters = nil
+	if len(w.FiltersDB) != 0 {
+		filters := &EventFilters{}
+		if err := json.Unmarshal([]byte(w.FiltersDB), filters); err != nil {
+			return err
+		}
+		w.Filters = filters
+	}
+	w.SigningEnabled = len(w.SigningSecret) > 0
+
 	return nil
 }
 
@@ -96,4 +131,21 @@ type EventTarget struct {
 	AuthHeader     string `json:"auth_header,omitempty"`
 	SkipCertVerify bool   `json:"skip_cert_verify"`
 	PayloadFormat  string `json:"payload_format,omitempty"`
+	Template       string `json:"template,omitempty"`
+}
+
+// EventFilters narrows down the artifact events a policy is triggered by, the empty filters match all the events.
+// Repositories and Tags are doublestar patterns, the repository patterns match the repository name without
+// the project name. The artifact must have all the Labels, and Severity is the minimum overall severity of the
+// vulnerability scan, it only applies to the scanning events.
+type EventFilters struct {
+	Repositories []string `json:"repositories,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	Severity     string   `json:"severity,omitempty"`
+}
+
+// IsEmpty returns whether no filter is set
+func (f *EventFilters) IsEmpty() bool {
+	return f == nil || (len(f.Repositories) == 0 && len(f.Tags) == 0 && len(f.Labels) == 0 && len(f.Severity) == 0)
 }
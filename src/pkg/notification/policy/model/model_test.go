@@ -44,6 +44,18 @@ func TestPolicy_ConvertFromDBModel(t *testing.T) {
 				EventTypes: []string{"pushImage", "pullImage", "deleteImage"},
 			},
 		},
+		{
+			name: "ConvertFromDBModel with filters",
+			policy: &Policy{
+				EventTypesDB: "[\"SCANNING_COMPLETED\"]",
+				FiltersDB:    "{\"repositories\":[\"app/**\"],\"severity\":\"High\"}",
+			},
+			want: &Policy{
+				Targets:    []EventTarget{},
+				EventTypes: []string{"SCANNING_COMPLETED"},
+				Filters:    &EventFilters{Repositories: []string{"app/**"}, Severity: "High"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -56,6 +68,7 @@ func TestPolicy_ConvertFromDBModel(t *testing.T) {
 			require.Nil(t, err)
 			assert.Equal(t, tt.want.Targets, tt.policy.Targets)
 			assert.Equal(t, tt.want.EventTypes, tt.policy.EventTypes)
+			assert.Equal(t, tt.want.Filters, tt.policy.Filters)
 		})
 	}
 }
@@ -84,6 +97,27 @@ func TestPolicy_ConvertToDBModel(t *testing.T) {
 				EventTypesDB: "[\"pushImage\",\"pullImage\",\"deleteImage\"]",
 			},
 		},
+		{
+			name: "ConvertToDBModel with filters",
+			policy: &Policy{
+				EventTypes: []string{"pushImage"},
+				Filters:    &EventFilters{Tags: []string{"v*"}, Labels: []string{"prod"}},
+			},
+			want: &Policy{
+				EventTypesDB: "[\"pushImage\"]",
+				FiltersDB:    "{\"tags\":[\"v*\"],\"labels\":[\"prod\"]}",
+			},
+		},
+		{
+			name: "ConvertToDBModel with empty filters",
+			policy: &Policy{
+				EventTypes: []string{"pushImage"},
+				Filters:    &EventFilters{},
+			},
+			want: &Policy{
+				EventTypesDB: "[\"pushImage\"]",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +130,7 @@ func TestPolicy_ConvertToDBModel(t *testing.T) {
 			require.Nil(t, err)
 			assert.Equal(t, tt.want.TargetsDB, tt.policy.TargetsDB)
 			assert.Equal(t, tt.want.EventTypesDB, tt.policy.EventTypesDB)
+			assert.Equal(t, tt.want.FiltersDB, tt.policy.FiltersDB)
 		})
 	}
 }
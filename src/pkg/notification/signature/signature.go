@@ -0,0 +1,101 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package signature signs the webhook payloads so that the receivers can verify that the
+// payloads are sent by Harbor and not replayed.
+//
+// The signature is the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed by the signing
+// secret of the policy, it is sent in the header X-Harbor-Signature as "sha256=<signature>".
+// During the overlap window of the secret rotation, the signatures of both the new and the
+// previous secrets are sent and separated by comma, the receivers should accept the payload if
+// any of them matches.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+const (
+	// HeaderSignature is the header carrying the signatures of the payload
+	HeaderSignature = "X-Harbor-Signature"
+	// HeaderTimestamp is the header carrying the unix timestamp when the payload is signed
+	HeaderTimestamp = "X-Harbor-Timestamp"
+	// HeaderDelivery is the header carrying the unique ID of the delivery, it keeps unchanged
+	// when the delivery is retried or re-delivered so that the receivers can deduplicate
+	HeaderDelivery = "X-Harbor-Delivery"
+	// HeaderRedelivery is the header indicating that the payload is re-delivered manually
+	HeaderRedelivery = "X-Harbor-Redelivery"
+
+	algorithm = "sha256"
+)
+
+// Sign returns the signature of the body signed at the timestamp with the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Apply sets the delivery ID, and the timestamp and signatures headers when any secret is provided
+func Apply(header http.Header, deliveryID string, secrets []string, body []byte, now time.Time) {
+	if len(deliveryID) > 0 {
+		header.Set(HeaderDelivery, deliveryID)
+	}
+	if len(secrets) == 0 {
+		return
+	}
+	timestamp := now.Unix()
+	var signatures []string
+	for _, secret := range secrets {
+		signatures = append(signatures, algorithm+"="+Sign(secret, timestamp, body))
+	}
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	header.Set(HeaderSignature, strings.Join(signatures, ","))
+}
+
+// Verify verifies the signatures in the header with the secret, the payload signed earlier
+// than the tolerance is rejected to prevent the replay attack
+func Verify(header http.Header, secret string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts := header.Get(HeaderTimestamp)
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid timestamp %q", ts)
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if now.Sub(signedAt) > tolerance || signedAt.Sub(now) > tolerance {
+		return errors.Errorf("the timestamp %s is out of the tolerance %s", signedAt.UTC().Format(time.RFC3339), tolerance)
+	}
+
+	expected := []byte(Sign(secret, timestamp, body))
+	for _, s := range strings.Split(header.Get(HeaderSignature), ",") {
+		alg, sig, found := strings.Cut(strings.TrimSpace(s), "=")
+		if !found || alg != algorithm {
+			continue
+		}
+		if hmac.Equal([]byte(sig), expected) {
+			return nil
+		}
+	}
+	return errors.New("no matching signature")
+}
@@ -0,0 +1,65 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package signature
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"type":"PUSH_ARTIFACT"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "f8f66f4d74bc140850e79163d7e925974350e285fbf3de25c0a9b647d5aab70d",
+		Sign("secret", 1700000000, []byte(`{"type":"PUSH_ARTIFACT"}`)))
+}
+
+func TestApply(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"PUSH_ARTIFACT"}`)
+
+	header := http.Header{}
+	Apply(header, "delivery-id", nil, body, now)
+	assert.Equal(t, "delivery-id", header.Get(HeaderDelivery))
+	assert.Empty(t, header.Get(HeaderSignature))
+	assert.Empty(t, header.Get(HeaderTimestamp))
+
+	header = http.Header{}
+	Apply(header, "delivery-id", []string{"new", "old"}, body, now)
+	assert.Equal(t, "1700000000", header.Get(HeaderTimestamp))
+	assert.Equal(t, "sha256="+Sign("new", 1700000000, body)+",sha256="+Sign("old", 1700000000, body), header.Get(HeaderSignature))
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"PUSH_ARTIFACT"}`)
+	header := http.Header{}
+	Apply(header, "delivery-id", []string{"new", "old"}, body, now)
+
+	// both the new and old secrets are accepted during the overlap window
+	assert.NoError(t, Verify(header, "new", body, 5*time.Minute, now))
+	assert.NoError(t, Verify(header, "old", body, 5*time.Minute, now.Add(time.Minute)))
+	// the wrong secret
+	assert.Error(t, Verify(header, "wrong", body, 5*time.Minute, now))
+	// the tampered body
+	assert.Error(t, Verify(header, "new", []byte(`{"type":"DELETE_ARTIFACT"}`), 5*time.Minute, now))
+	// the replayed payload
+	assert.Error(t, Verify(header, "new", body, 5*time.Minute, now.Add(10*time.Minute)))
+	// the missing timestamp
+	header.Del(HeaderTimestamp)
+	assert.Error(t, Verify(header, "new", body, 5*time.Minute, now))
+}
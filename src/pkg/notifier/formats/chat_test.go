@@ -0,0 +1,93 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package formats
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+func newPushEvent() *model.HookEvent {
+	return &model.HookEvent{
+		Payload: &model.Payload{
+			Type:     "PUSH_ARTIFACT",
+			OccurAt:  1678082303,
+			Operator: "admin",
+			EventData: &model.EventData{
+				Resources: []*model.Resource{
+					{Digest: "sha256:dde8e930", Tag: "latest", ResourceURL: "harbor.dev/library/busybox:latest"},
+				},
+				Repository: &model.Repository{RepoFullName: "library/busybox"},
+			},
+		},
+	}
+}
+
+func TestTeams_Format(t *testing.T) {
+	teams := &Teams{}
+	_, _, err := teams.Format(context.TODO(), nil)
+	assert.Error(t, err)
+
+	header, payload, err := teams.Format(context.TODO(), newPushEvent())
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+
+	var message struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string `json:"type"`
+				Body []struct {
+					Type  string  `json:"type"`
+					Text  string  `json:"text"`
+					Facts []*fact `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &message))
+	assert.Equal(t, "message", message.Type)
+	require.Len(t, message.Attachments, 1)
+	assert.Equal(t, "application/vnd.microsoft.card.adaptive", message.Attachments[0].ContentType)
+	assert.Equal(t, "AdaptiveCard", message.Attachments[0].Content.Type)
+	body := message.Attachments[0].Content.Body
+	require.Len(t, body, 2)
+	assert.Equal(t, "Harbor webhook event: PUSH_ARTIFACT", body[0].Text)
+	assert.Contains(t, body[1].Facts, &fact{Title: "Repository", Value: "library/busybox"})
+	assert.Contains(t, body[1].Facts, &fact{Title: "Artifact", Value: "harbor.dev/library/busybox:latest"})
+}
+
+func TestGoogleChat_Format(t *testing.T) {
+	chat := &GoogleChat{}
+	_, _, err := chat.Format(context.TODO(), &model.HookEvent{})
+	assert.Error(t, err)
+
+	header, payload, err := chat.Format(context.TODO(), newPushEvent())
+	require.NoError(t, err)
+	assert.Equal(t, "application/json; charset=UTF-8", header.Get("Content-Type"))
+
+	message := map[string]string{}
+	require.NoError(t, json.Unmarshal(payload, &message))
+	assert.Contains(t, message["text"], "*Harbor webhook event: PUSH_ARTIFACT*")
+	assert.Contains(t, message["text"], "*Occur at:* 2023-03-06T05:58:23Z")
+	assert.Contains(t, message["text"], "*Repository:* library/busybox")
+}
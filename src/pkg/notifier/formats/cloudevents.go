@@ -27,6 +27,7 @@ import (
 	"github.com/goharbor/harbor/src/controller/event"
 	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
 	"github.com/goharbor/harbor/src/pkg/notifier/model"
 )
 
@@ -61,6 +62,16 @@ var (
 		event.TopicScanningCompleted: eventType("scan.completed"),
 		event.TopicScanningStopped:   eventType("scan.stopped"),
 		event.TopicTagRetention:      eventType("tag_retention.finished"),
+		// the events of the system level policies
+		event.TopicCreateProject:        eventType("project.created"),
+		event.TopicDeleteProject:        eventType("project.deleted"),
+		event.TopicGCCompleted:          eventType("gc.completed"),
+		event.TopicPurgeAuditCompleted:  eventType("audit_log_purge.completed"),
+		event.TopicScanAllCompleted:     eventType("scan_all.completed"),
+		event.TopicJobQueueLatency:      eventType("jobservice.queue_latency.breached"),
+		event.TopicStorageLow:           eventType("storage.low"),
+		event.TopicScannerHealthChanged: eventType("scanner.health_changed"),
+		event.TopicCVEAllowlistExpiring: eventType("cve_allowlist.expiring"),
 	}
 )
 
@@ -140,5 +151,8 @@ func (ce *CloudEvents) Format(ctx context.Context, he *model.HookEvent) (http.He
 
 // source builds the source for CloudEvents.
 func source(projectID, policyID int64) string {
+	if projectID == policy_model.SystemProjectID {
+		return fmt.Sprintf("/system/webhook/policies/%d", policyID)
+	}
 	return fmt.Sprintf("/projects/%d/webhook/policies/%d", projectID, policyID)
 }
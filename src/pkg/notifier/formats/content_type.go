@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formats
+
+import (
+	"encoding/json"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+const (
+	contentTypeJSON         = "application/json"
+	contentTypeJSONWithUTF8 = "application/json; charset=UTF-8"
+	contentTypeTextWithUTF8 = "text/plain; charset=utf-8"
+)
+
+// ContentType returns the content type of the payload formatted for the target,
+// it is used to re-deliver the formatted payload without formatting the event again.
+func ContentType(target *policy_model.EventTarget, payload []byte) string {
+	switch {
+	case target.Type == model.NotifyTypeGoogleChat:
+		return contentTypeJSONWithUTF8
+	case target.Type == model.NotifyTypeCustom:
+		return customContentType(payload)
+	case target.PayloadFormat == CloudEventsFormat:
+		return cloudevents.ApplicationCloudEventsJSON
+	default:
+		return contentTypeJSON
+	}
+}
+
+// customContentType returns the content type of the payload rendered by the custom template
+func customContentType(payload []byte) string {
+	if json.Valid(payload) {
+		return contentTypeJSON
+	}
+	return contentTypeTextWithUTF8
+}
@@ -0,0 +1,56 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+// GoogleChat formats the event to the text message accepted by the incoming webhooks of Google Chat.
+type GoogleChat struct{}
+
+// Format implements the interface Formatter.
+/*
+{
+   "text":"*Harbor webhook event: PUSH_ARTIFACT*\n*Event type:* PUSH_ARTIFACT\n*Operator:* admin"
+}
+*/
+func (g *GoogleChat) Format(_ context.Context, he *model.HookEvent) (http.Header, []byte, error) {
+	if he == nil || he.Payload == nil {
+		return nil, nil, errors.Errorf("HookEvent and its payload should not be nil")
+	}
+
+	lines := []string{fmt.Sprintf("*Harbor webhook event: %s*", he.Payload.Type)}
+	for _, f := range summarize(he.Payload) {
+		lines = append(lines, fmt.Sprintf("*%s:* %s", f.Title, f.Value))
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": strings.Join(lines, "\n")})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error to marshal google chat message")
+	}
+
+	header := http.Header{
+		"Content-Type": []string{contentTypeJSONWithUTF8},
+	}
+	return header, payload, nil
+}
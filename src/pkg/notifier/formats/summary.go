@@ -0,0 +1,68 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+// fact is a key/value pair summarizing the event for the chat targets
+type fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// summarize extracts the facts of the event payload which are rendered by the chat targets such as Teams and Google Chat
+func summarize(payload *model.Payload) []*fact {
+	facts := []*fact{
+		{Title: "Event type", Value: payload.Type},
+		{Title: "Occur at", Value: time.Unix(payload.OccurAt, 0).UTC().Format(time.RFC3339)},
+		{Title: "Operator", Value: payload.Operator},
+	}
+	data := payload.EventData
+	if data == nil {
+		return facts
+	}
+	if data.Repository != nil && len(data.Repository.RepoFullName) > 0 {
+		facts = append(facts, &fact{Title: "Repository", Value: data.Repository.RepoFullName})
+	}
+	for _, res := range data.Resources {
+		value := res.ResourceURL
+		if len(value) == 0 {
+			value = strings.TrimSuffix(res.Tag+"@"+res.Digest, "@")
+		}
+		facts = append(facts, &fact{Title: "Artifact", Value: value})
+	}
+	if data.Scan != nil && len(data.Scan.ScanType) > 0 {
+		facts = append(facts, &fact{Title: "Scan type", Value: data.Scan.ScanType})
+	}
+	if data.Replication != nil {
+		facts = append(facts, &fact{Title: "Replication status", Value: data.Replication.JobStatus},
+			&fact{Title: "Succeeded artifacts", Value: fmt.Sprint(len(data.Replication.SuccessfulArtifact))},
+			&fact{Title: "Failed artifacts", Value: fmt.Sprint(len(data.Replication.FailedArtifact))})
+	}
+	if data.Retention != nil {
+		facts = append(facts, &fact{Title: "Retention status", Value: data.Retention.Status},
+			&fact{Title: "Retained/Total", Value: fmt.Sprintf("%d/%d", data.Retention.Retained, data.Retention.Total)})
+	}
+	if details, ok := data.Custom["Details"]; ok {
+		facts = append(facts, &fact{Title: "Details", Value: details})
+	}
+	return facts
+}
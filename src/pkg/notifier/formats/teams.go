@@ -0,0 +1,92 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formats
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+// Teams formats the event to the message with an Adaptive Card attached which is accepted by
+// the incoming webhooks and workflows of Microsoft Teams.
+type Teams struct{}
+
+// Format implements the interface Formatter.
+/*
+{
+   "type":"message",
+   "attachments":[
+      {
+         "contentType":"application/vnd.microsoft.card.adaptive",
+         "content":{
+            "$schema":"http://adaptivecards.io/schemas/adaptive-card.json",
+            "type":"AdaptiveCard",
+            "version":"1.4",
+            "body":[
+               {"type":"TextBlock","text":"Harbor webhook event: PUSH_ARTIFACT","size":"Medium","weight":"Bolder","wrap":true},
+               {"type":"FactSet","facts":[{"title":"Event type","value":"PUSH_ARTIFACT"}]}
+            ]
+         }
+      }
+   ]
+}
+*/
+func (t *Teams) Format(_ context.Context, he *model.HookEvent) (http.Header, []byte, error) {
+	if he == nil || he.Payload == nil {
+		return nil, nil, errors.Errorf("HookEvent and its payload should not be nil")
+	}
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []interface{}{
+			map[string]interface{}{
+				"type":   "TextBlock",
+				"text":   "Harbor webhook event: " + he.Payload.Type,
+				"size":   "Medium",
+				"weight": "Bolder",
+				"wrap":   true,
+			},
+			map[string]interface{}{
+				"type":  "FactSet",
+				"facts": summarize(he.Payload),
+			},
+		},
+	}
+	message := map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content":     card,
+			},
+		},
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error to marshal teams message")
+	}
+
+	header := http.Header{
+		"Content-Type": []string{"application/json"},
+	}
+	return header, payload, nil
+}
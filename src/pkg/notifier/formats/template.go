@@ -0,0 +1,287 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package formats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+const (
+	// maxTemplateSize is the max size of the custom template
+	maxTemplateSize = 64 * 1024
+	// maxRenderedSize is the max size of the payload rendered by the custom template
+	maxRenderedSize = 1024 * 1024
+	// renderTimeout is the timeout to render the custom template
+	renderTimeout = 5 * time.Second
+	// maxRenderSteps is the max count of the loop iterations and the template calls while rendering
+	maxRenderSteps = 100000
+
+	// iterFunc and tickFunc are injected into the parsed template to sandbox the execution,
+	// iterFunc guards the value ranged over and tickFunc is called on every loop iteration and template call
+	iterFunc = "__iter"
+	tickFunc = "__tick"
+)
+
+var (
+	errRenderedTooLarge = errors.New("the rendered payload exceeds the size limit")
+	errRenderTimeout    = errors.New("timeout to render template")
+	errRenderTooLong    = errors.New("the template exceeds the execution steps limit")
+
+	// templateFuncs is the sandboxed function set available in the custom template,
+	// none of them has side effects or access to the environment
+	templateFuncs = template.FuncMap{
+		"toJSON": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"quote": func(s string) string {
+			data, _ := json.Marshal(s)
+			return string(data)
+		},
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"trim":      strings.TrimSpace,
+		"replace":   strings.ReplaceAll,
+		"join":      strings.Join,
+		"split":     strings.Split,
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+		"default": func(def, v interface{}) interface{} {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+		"formatTime": func(layout string, unix int64) string {
+			return time.Unix(unix, 0).UTC().Format(layout)
+		},
+		// replaced by the sandbox of each execution, only for parsing
+		iterFunc: iter,
+		tickFunc: func() string { return "" },
+	}
+
+	// sandboxNodes provides the nodes injected into the parsed template
+	sandboxNodes = template.Must(template.New("sandbox").Funcs(templateFuncs).Parse("{{" + tickFunc + "}}{{range " + iterFunc + " .}}{{end}}"))
+)
+
+// iter only allows ranging over the collections, which are bounded by the size of the event,
+// the integers, channels and functions are rejected as the iterations may exhaust the resources
+func iter(v interface{}) (interface{}, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil, nil
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Invalid, reflect.Array, reflect.Slice, reflect.Map:
+		return v, nil
+	default:
+		return nil, errors.Errorf("ranging over %s is not allowed in the template", val.Kind())
+	}
+}
+
+// sandbox limits the steps and the time of an execution of the template, the template
+// aborts at the next loop iteration or template call once exceeded
+type sandbox struct {
+	deadline time.Time
+	steps    int
+}
+
+func (s *sandbox) tick() (string, error) {
+	s.steps++
+	if s.steps > maxRenderSteps {
+		return "", errRenderTooLong
+	}
+	if time.Now().After(s.deadline) {
+		return "", errRenderTimeout
+	}
+	return "", nil
+}
+
+func (s *sandbox) funcs() template.FuncMap {
+	return template.FuncMap{
+		iterFunc: iter,
+		tickFunc: s.tick,
+	}
+}
+
+// Template renders the event with the Go text/template defined in the target of the policy,
+// the template is executed against the model.HookEvent with the sandboxed function set.
+type Template struct{}
+
+// ParseTemplate parses and validates the custom template
+func ParseTemplate(text string) (*template.Template, error) {
+	if len(strings.TrimSpace(text)) == 0 {
+		return nil, errors.New("the template is empty")
+	}
+	if len(text) > maxTemplateSize {
+		return nil, errors.Errorf("the size of the template exceeds the limit %d", maxTemplateSize)
+	}
+	tpl, err := template.New("custom").Option("missingkey=zero").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid template")
+	}
+	for _, t := range tpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil {
+			continue
+		}
+		if err := checkNode(t.Tree.Root); err != nil {
+			return nil, err
+		}
+		// every template call ticks, so the recursive templates are bounded too
+		sandboxNode(t.Tree.Root)
+		t.Tree.Root.Nodes = append([]parse.Node{tickNode()}, t.Tree.Root.Nodes...)
+	}
+	return tpl, nil
+}
+
+func tickNode() parse.Node {
+	return sandboxNodes.Tree.Root.Nodes[0].Copy()
+}
+
+// sandboxNode rewrites "range pipeline" to "range __iter (pipeline)" and injects "__tick" into the loop body
+func sandboxNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			sandboxNode(child)
+		}
+	case *parse.RangeNode:
+		sandboxNode(n.List)
+		sandboxNode(n.ElseList)
+		inner := n.Pipe.Copy().(*parse.PipeNode)
+		inner.Decl = nil
+		inner.IsAssign = false
+		cmd := sandboxNodes.Tree.Root.Nodes[1].(*parse.RangeNode).Pipe.Cmds[0].Copy().(*parse.CommandNode)
+		cmd.Args[1] = inner
+		n.Pipe.Cmds = []*parse.CommandNode{cmd}
+		if n.List != nil {
+			n.List.Nodes = append([]parse.Node{tickNode()}, n.List.Nodes...)
+		}
+	case *parse.IfNode:
+		sandboxNode(n.List)
+		sandboxNode(n.ElseList)
+	case *parse.WithNode:
+		sandboxNode(n.List)
+		sandboxNode(n.ElseList)
+	}
+}
+
+// checkNode rejects the constructs which may exhaust the resources, e.g. ranging over an integer
+func checkNode(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNode(child); err != nil {
+				return err
+			}
+		}
+	case *parse.RangeNode:
+		for _, cmd := range n.Pipe.Cmds {
+			for _, arg := range cmd.Args {
+				if _, ok := arg.(*parse.NumberNode); ok {
+					return errors.New("ranging over an integer is not allowed in the template")
+				}
+			}
+		}
+		if err := checkNode(n.List); err != nil {
+			return err
+		}
+		return checkNode(n.ElseList)
+	case *parse.IfNode:
+		if err := checkNode(n.List); err != nil {
+			return err
+		}
+		return checkNode(n.ElseList)
+	case *parse.WithNode:
+		if err := checkNode(n.List); err != nil {
+			return err
+		}
+		return checkNode(n.ElseList)
+	}
+	return nil
+}
+
+// limitedBuffer is a buffer returning error when the written data exceeds the limit or the deadline passed
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	deadline time.Time
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if l.Len()+len(p) > l.limit {
+		return 0, errRenderedTooLarge
+	}
+	if time.Now().After(l.deadline) {
+		return 0, errRenderTimeout
+	}
+	return l.Buffer.Write(p)
+}
+
+// Format implements the interface Formatter.
+func (t *Template) Format(ctx context.Context, he *model.HookEvent) (http.Header, []byte, error) {
+	if he == nil || he.Target == nil {
+		return nil, nil, errors.Errorf("HookEvent and its target should not be nil")
+	}
+
+	tpl, err := ParseTemplate(he.Target.Template)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the credential of the target is never exposed to the template
+	target := *he.Target
+	target.AuthHeader = ""
+	data := *he
+	data.Target = &target
+
+	// the execution runs in the caller goroutine and aborts by the sandbox, so it never outlives the timeout
+	deadline := time.Now().Add(renderTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	sb := &sandbox{deadline: deadline}
+	buf := &limitedBuffer{limit: maxRenderedSize, deadline: deadline}
+	if err := tpl.Funcs(sb.funcs()).Execute(buf, &data); err != nil {
+		return nil, nil, errors.Wrap(err, "error to render template")
+	}
+
+	payload := buf.Bytes()
+	header := http.Header{
+		"Content-Type": []string{customContentType(payload)},
+	}
+	return header, payload, nil
+}
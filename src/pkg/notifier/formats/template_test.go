@@ -0,0 +1,113 @@
+//  Copyright Project Harbor Authors
+//
+//  Licensed under the Apache License, Version 2.0 (the "License");
+//  you may not use this file except in compliance with the License.
+//  You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+//  Unless required by applicable law or agreed to in writing, software
+//  distributed under the License is distributed on an "AS IS" BASIS,
+//  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//  See the License for the specific language governing permissions and
+//  limitations under the License.
+
+package formats
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{name: "empty", text: " ", wantErr: true},
+		{name: "too large", text: strings.Repeat("a", maxTemplateSize+1), wantErr: true},
+		{name: "syntax error", text: "{{.Payload.Type", wantErr: true},
+		{name: "unknown function", text: `{{exec "ls"}}`, wantErr: true},
+		{name: "range over integer", text: "{{range 1000000000}}{{end}}", wantErr: true},
+		{name: "nested range over integer", text: "{{if .Payload}}{{range 10}}x{{end}}{{end}}", wantErr: true},
+		{name: "valid", text: `{"text": {{quote .Payload.Type}}, "repo": {{with .Payload.EventData.Repository}}{{quote .RepoFullName}}{{end}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTemplate_Format(t *testing.T) {
+	tpl := &Template{}
+	_, _, err := tpl.Format(context.TODO(), nil)
+	assert.Error(t, err)
+
+	he := newPushEvent()
+	he.Target = &policy_model.EventTarget{
+		Type:       "custom",
+		AuthHeader: "Bearer secret",
+		Template: `{"event": {{quote (lower .Payload.Type)}}, "at": {{quote (formatTime "2006-01-02" .Payload.OccurAt)}}, ` +
+			`"tags": [{{range $i, $r := .Payload.EventData.Resources}}{{if $i}},{{end}}{{quote $r.Tag}}{{end}}], "auth": {{quote .Target.AuthHeader}}}`,
+	}
+	header, payload, err := tpl.Format(context.TODO(), he)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.JSONEq(t, `{"event": "push_artifact", "at": "2023-03-06", "tags": ["latest"], "auth": ""}`, string(payload))
+	// the original event is not modified
+	assert.Equal(t, "Bearer secret", he.Target.AuthHeader)
+
+	he.Target.Template = "{{.Payload.Operator}} pushed {{.Payload.EventData.Repository.RepoFullName}}"
+	header, payload, err = tpl.Format(context.TODO(), he)
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", header.Get("Content-Type"))
+	assert.Equal(t, "admin pushed library/busybox", string(payload))
+
+	// the rendered payload exceeds the limit
+	he.Target.Template = `{{printf "%02000000d" 0}}`
+	_, _, err = tpl.Format(context.TODO(), he)
+	assert.Error(t, err)
+
+	// the range with the else branch and the break is kept
+	he.Target.Template = `{{range .Payload.EventData.Resources}}{{.Tag}}{{break}}{{else}}none{{end}}`
+	_, payload, err = tpl.Format(context.TODO(), he)
+	require.NoError(t, err)
+	assert.Equal(t, "latest", string(payload))
+}
+
+func TestTemplate_FormatSandbox(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "range over integer field", text: "{{range .Payload.OccurAt}}{{end}}"},
+		{name: "range over integer variable", text: "{{$n := 1000000000}}{{range $n}}{{end}}"},
+		{name: "range over integer dot", text: "{{with 1000000000}}{{range .}}{{end}}{{end}}"},
+		{name: "recursive template", text: `{{define "r"}}{{template "r" .}}{{template "r" .}}{{end}}{{template "r" .}}`},
+	}
+	tpl := &Template{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			he := newPushEvent()
+			he.Target = &policy_model.EventTarget{Type: "custom", Template: tt.text}
+			start := time.Now()
+			_, _, err := tpl.Format(context.TODO(), he)
+			assert.Error(t, err)
+			assert.Less(t, time.Since(start), renderTimeout)
+		})
+	}
+}
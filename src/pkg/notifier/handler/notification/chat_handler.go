@@ -0,0 +1,93 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/notifier/formats"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+// TeamsHandler preprocess event data to Microsoft Teams Adaptive Card and start the hook processing
+type TeamsHandler struct {
+}
+
+// Name ...
+func (t *TeamsHandler) Name() string {
+	return "Teams"
+}
+
+// Handle handles event to Microsoft Teams
+func (t *TeamsHandler) Handle(ctx context.Context, value interface{}) error {
+	event, ok := value.(*model.HookEvent)
+	if !ok || event == nil {
+		return errors.New("invalid notification teams event")
+	}
+	return startFormattedHook(ctx, event, job.TeamsJobVendorType, &formats.Teams{})
+}
+
+// IsStateful ...
+func (t *TeamsHandler) IsStateful() bool {
+	return false
+}
+
+// GoogleChatHandler preprocess event data to Google Chat message and start the hook processing
+type GoogleChatHandler struct {
+}
+
+// Name ...
+func (g *GoogleChatHandler) Name() string {
+	return "GoogleChat"
+}
+
+// Handle handles event to Google Chat
+func (g *GoogleChatHandler) Handle(ctx context.Context, value interface{}) error {
+	event, ok := value.(*model.HookEvent)
+	if !ok || event == nil {
+		return errors.New("invalid notification google chat event")
+	}
+	return startFormattedHook(ctx, event, job.GoogleChatJobVendorType, &formats.GoogleChat{})
+}
+
+// IsStateful ...
+func (g *GoogleChatHandler) IsStateful() bool {
+	return false
+}
+
+// CustomHandler renders event data with the template of the target and start the hook processing
+type CustomHandler struct {
+}
+
+// Name ...
+func (c *CustomHandler) Name() string {
+	return "Custom"
+}
+
+// Handle handles event to the endpoint accepting the custom payload
+func (c *CustomHandler) Handle(ctx context.Context, value interface{}) error {
+	event, ok := value.(*model.HookEvent)
+	if !ok || event == nil {
+		return errors.New("invalid notification custom event")
+	}
+	return startFormattedHook(ctx, event, job.CustomJobVendorType, &formats.Template{})
+}
+
+// IsStateful ...
+func (c *CustomHandler) IsStateful() bool {
+	return false
+}
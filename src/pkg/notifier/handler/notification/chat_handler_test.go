@@ -0,0 +1,88 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+	"github.com/goharbor/harbor/src/testing/mock"
+	testingPolicy "github.com/goharbor/harbor/src/testing/pkg/notification/policy"
+)
+
+func TestChatHandlers_Handle(t *testing.T) {
+	hookMgr := notification.HookManager
+	defer func() {
+		notification.HookManager = hookMgr
+	}()
+	recorder := &recordedHookManager{}
+	notification.HookManager = recorder
+	policyMgr := policy.Mgr
+	defer func() {
+		policy.Mgr = policyMgr
+	}()
+	mgr := &testingPolicy.Manager{}
+	mgr.On("GetSigningSecrets", mock.Anything, int64(1)).Return(nil, nil)
+	policy.Mgr = mgr
+
+	tests := []struct {
+		handler    notifier.NotificationHandler
+		target     *policy_model.EventTarget
+		vendorType string
+		wantErr    bool
+	}{
+		{
+			handler:    &TeamsHandler{},
+			target:     &policy_model.EventTarget{Type: "teams", Address: "https://example.webhook.office.com/webhookb2/abc"},
+			vendorType: job.TeamsJobVendorType,
+		},
+		{
+			handler:    &GoogleChatHandler{},
+			target:     &policy_model.EventTarget{Type: "googlechat", Address: "https://chat.googleapis.com/v1/spaces/abc/messages"},
+			vendorType: job.GoogleChatJobVendorType,
+		},
+		{
+			handler:    &CustomHandler{},
+			target:     &policy_model.EventTarget{Type: "custom", Address: "https://example.com/hook", AuthHeader: "Bearer token", Template: "{{.Payload.Type}}"},
+			vendorType: job.CustomJobVendorType,
+		},
+		{
+			handler: &CustomHandler{},
+			target:  &policy_model.EventTarget{Type: "custom", Address: "https://example.com/hook"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.handler.Name(), func(t *testing.T) {
+			assert.False(t, tt.handler.IsStateful())
+			assert.Error(t, tt.handler.Handle(context.TODO(), nil))
+
+			recorder.job = nil
+			err := tt.handler.Handle(context.TODO(), &model.HookEvent{
+				PolicyID:  1,
+				EventType: "PUSH_ARTIFACT",
+				Target:    tt.target,
+				Payload:   &model.Payload{Type: "PUSH_ARTIFACT", Operator: "admin"},
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, recorder.job)
+			assert.Equal(t, tt.vendorType, recorder.job.Name)
+			assert.Equal(t, tt.target.Address, recorder.job.Parameters["address"])
+			assert.NotEmpty(t, recorder.job.Parameters["payload"])
+			if len(tt.target.AuthHeader) > 0 {
+				assert.Contains(t, recorder.job.Parameters["header"], tt.target.AuthHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/job/models"
+	"github.com/goharbor/harbor/src/controller/event"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+const (
+	// emailHeaderTemplate defines the common header of the email body
+	emailHeaderTemplate = `{{define "header"}}<p><b>Event type:</b> {{.Payload.Type}}<br/>
+<b>Occur at:</b> {{.OccurAt}}<br/>
+<b>Operator:</b> {{.Payload.Operator}}</p>
+{{- with .Payload.EventData}}{{with .Repository}}
+<p><b>Repository:</b> {{.RepoFullName}} ({{.RepoType}})</p>{{end}}{{end}}{{end}}`
+
+	// emailArtifactTemplate defines the email body template of the push/pull/delete artifact events
+	emailArtifactTemplate = `{{define "artifact"}}{{template "header" .}}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Tag</th><th>Digest</th><th>Resource URL</th></tr>
+{{- range .Payload.EventData.Resources}}
+<tr><td>{{.Tag}}</td><td>{{.Digest}}</td><td>{{.ResourceURL}}</td></tr>
+{{- end}}
+</table>{{end}}`
+
+	// emailScanTemplate defines the email body template of the scanning events
+	emailScanTemplate = `{{define "scan"}}{{template "header" .}}
+{{- with .Payload.EventData.Scan}}
+<p><b>Scan type:</b> {{.ScanType}}</p>{{end}}
+{{- range .Payload.EventData.Resources}}
+<p><b>Artifact:</b> {{.ResourceURL}} {{.Digest}}</p>
+{{- if .ScanOverview}}
+<pre>{{toJSON .ScanOverview}}</pre>{{end}}
+{{- end}}{{end}}`
+
+	// emailQuotaTemplate defines the email body template of the quota events
+	emailQuotaTemplate = `{{define "quota"}}{{template "header" .}}
+{{- range .Payload.EventData.Resources}}
+<p><b>Artifact:</b> {{.Tag}} {{.Digest}}</p>
+{{- end}}
+<p><b>Details:</b> {{index .Payload.EventData.Custom "Details"}}</p>{{end}}`
+
+	// emailReplicationTemplate defines the email body template of the replication events
+	emailReplicationTemplate = `{{define "replication"}}{{template "header" .}}
+{{- with .Payload.EventData.Replication}}
+<p><b>Status:</b> {{.JobStatus}}<br/>
+<b>Trigger type:</b> {{.TriggerType}}<br/>
+<b>Policy creator:</b> {{.PolicyCreator}}
+{{- with .SrcResource}}<br/>
+<b>Source:</b> {{.RegistryName}} {{.Endpoint}} {{.Namespace}}{{end}}
+{{- with .DestResource}}<br/>
+<b>Destination:</b> {{.RegistryName}} {{.Endpoint}} {{.Namespace}}{{end}}</p>
+{{- if .SuccessfulArtifact}}
+<p><b>Succeeded artifacts:</b></p>
+<ul>{{range .SuccessfulArtifact}}<li>{{.NameAndTag}}</li>{{end}}</ul>{{end}}
+{{- if .FailedArtifact}}
+<p><b>Failed artifacts:</b></p>
+<ul>{{range .FailedArtifact}}<li>{{.NameAndTag}}</li>{{end}}</ul>{{end}}
+{{- end}}{{end}}`
+
+	// emailDefaultTemplate defines the email body template of the events without a dedicated template
+	emailDefaultTemplate = `{{define "default"}}{{template "header" .}}
+<pre>{{toJSON .Payload.EventData}}</pre>{{end}}`
+)
+
+var (
+	emailTemplates = template.Must(template.New("email").Funcs(template.FuncMap{
+		"toJSON": func(v interface{}) (string, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			return string(data), err
+		},
+	}).Parse(emailHeaderTemplate + emailArtifactTemplate + emailScanTemplate +
+		emailQuotaTemplate + emailReplicationTemplate + emailDefaultTemplate))
+
+	// emailTemplateNames maps the event types to the names of the templates rendering the email body
+	emailTemplateNames = map[string]string{
+		event.TopicPushArtifact:      "artifact",
+		event.TopicPullArtifact:      "artifact",
+		event.TopicDeleteArtifact:    "artifact",
+		event.TopicScanningCompleted: "scan",
+		event.TopicScanningFailed:    "scan",
+		event.TopicScanningStopped:   "scan",
+		event.TopicQuotaWarning:      "quota",
+		event.TopicQuotaExceed:       "quota",
+		event.TopicReplication:       "replication",
+	}
+)
+
+// EmailHandler preprocess event data to email and start the hook processing
+type EmailHandler struct {
+}
+
+// Name ...
+func (e *EmailHandler) Name() string {
+	return "Email"
+}
+
+// Handle handles event to email
+func (e *EmailHandler) Handle(ctx context.Context, value interface{}) error {
+	if value == nil {
+		return errors.New("EmailHandler cannot handle nil value")
+	}
+
+	event, ok := value.(*model.HookEvent)
+	if !ok || event == nil || event.Payload == nil {
+		return errors.New("invalid notification email event")
+	}
+
+	return e.process(ctx, event)
+}
+
+// IsStateful ...
+func (e *EmailHandler) IsStateful() bool {
+	return false
+}
+
+func (e *EmailHandler) process(ctx context.Context, event *model.HookEvent) error {
+	j := &models.JobData{
+		Metadata: &models.JobMetadata{
+			JobKind: job.KindGeneric,
+		},
+	}
+	// Create an emailJob to send the email
+	j.Name = job.EmailJobVendorType
+
+	body, err := e.render(event.Payload)
+	if err != nil {
+		return fmt.Errorf("convert payload to email body failed: %v", err)
+	}
+
+	j.Parameters = map[string]interface{}{
+		"payload": body,
+		"subject": e.subject(event.Payload),
+		"address": event.Target.Address,
+	}
+	return notification.HookManager.StartHook(ctx, event, j)
+}
+
+// subject returns the subject of the email, line breaks are removed to avoid the header injection
+func (e *EmailHandler) subject(payload *model.Payload) string {
+	subject := fmt.Sprintf("[Harbor] %s", payload.Type)
+	if payload.EventData != nil && payload.EventData.Repository != nil && len(payload.EventData.Repository.RepoFullName) > 0 {
+		subject = fmt.Sprintf("%s: %s", subject, payload.EventData.Repository.RepoFullName)
+	}
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+}
+
+// render renders the email body with the template of the event type
+func (e *EmailHandler) render(payload *model.Payload) (string, error) {
+	if payload.EventData == nil {
+		payload.EventData = &model.EventData{}
+	}
+	name, ok := emailTemplateNames[payload.Type]
+	if !ok {
+		name = "default"
+	}
+	data := map[string]interface{}{
+		"Payload": payload,
+		"OccurAt": time.Unix(payload.OccurAt, 0).UTC().Format(time.RFC3339),
+	}
+
+	var buf bytes.Buffer
+	if err := emailTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
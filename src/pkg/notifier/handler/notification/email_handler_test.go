@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/common/job/models"
+	evtModel "github.com/goharbor/harbor/src/controller/event/model"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier/model"
+)
+
+type recordedHookManager struct {
+	job *models.JobData
+}
+
+func (r *recordedHookManager) StartHook(_ context.Context, _ *model.HookEvent, job *models.JobData) error {
+	r.job = job
+	return nil
+}
+
+func TestEmailHandler_Handle(t *testing.T) {
+	hookMgr := notification.HookManager
+	defer func() {
+		notification.HookManager = hookMgr
+	}()
+	recorder := &recordedHookManager{}
+	notification.HookManager = recorder
+
+	handler := &EmailHandler{}
+	assert.NotNil(t, handler.Handle(context.TODO(), nil))
+	assert.NotNil(t, handler.Handle(context.TODO(), &model.EventData{}))
+
+	err := handler.Handle(context.TODO(), &model.HookEvent{
+		PolicyID:  1,
+		EventType: "SCANNING_FAILED",
+		Target: &policy_model.EventTarget{
+			Type:    "email",
+			Address: "dev@example.com",
+		},
+		Payload: &model.Payload{
+			OccurAt:  time.Now().Unix(),
+			Type:     "SCANNING_FAILED",
+			Operator: "admin",
+			EventData: &model.EventData{
+				Resources: []*model.Resource{
+					{
+						Digest:       "sha256:abc",
+						ResourceURL:  "harbor.example.com/library/debian:v9.0",
+						ScanOverview: map[string]interface{}{"scan_status": "Error"},
+					},
+				},
+				Repository: &model.Repository{
+					RepoFullName: "library/debian\r\nBcc: evil@example.com",
+				},
+				Scan: &evtModel.Scan{ScanType: "vulnerability"},
+			},
+		},
+	})
+	require.Nil(t, err)
+	require.NotNil(t, recorder.job)
+	assert.Equal(t, job.EmailJobVendorType, recorder.job.Name)
+	assert.Equal(t, "dev@example.com", recorder.job.Parameters["address"])
+	assert.NotContains(t, recorder.job.Parameters["subject"], "\n")
+	body := recorder.job.Parameters["payload"].(string)
+	assert.Contains(t, body, "vulnerability")
+	assert.Contains(t, body, "harbor.example.com/library/debian:v9.0")
+}
+
+func TestEmailHandler_render(t *testing.T) {
+	handler := &EmailHandler{}
+	tests := []struct {
+		name     string
+		payload  *model.Payload
+		contains []string
+	}{
+		{
+			name: "push artifact",
+			payload: &model.Payload{
+				Type: "PUSH_ARTIFACT",
+				EventData: &model.EventData{
+					Resources:  []*model.Resource{{Tag: "v1.0", Digest: "sha256:abc"}},
+					Repository: &model.Repository{RepoFullName: "library/<script>", RepoType: "public"},
+				},
+			},
+			contains: []string{"v1.0", "sha256:abc", "library/&lt;script&gt;"},
+		},
+		{
+			name: "quota warning",
+			payload: &model.Payload{
+				Type: "QUOTA_WARNING",
+				EventData: &model.EventData{
+					Custom: map[string]string{"Details": "quota usage reaches 85%"},
+				},
+			},
+			contains: []string{"quota usage reaches 85%"},
+		},
+		{
+			name: "replication",
+			payload: &model.Payload{
+				Type: "REPLICATION",
+				EventData: &model.EventData{
+					Replication: &evtModel.Replication{
+						JobStatus:      "Failure",
+						FailedArtifact: []*evtModel.ArtifactInfo{{NameAndTag: "library/debian:v9.0"}},
+					},
+				},
+			},
+			contains: []string{"Failure", "library/debian:v9.0"},
+		},
+		{
+			name: "default",
+			payload: &model.Payload{
+				Type:      "TAG_RETENTION",
+				EventData: &model.EventData{Retention: &evtModel.Retention{Total: 10, Retained: 3}},
+			},
+			contains: []string{"TAG_RETENTION", "&#34;retained&#34;: 3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := handler.render(tt.payload)
+			require.Nil(t, err)
+			for _, s := range tt.contains {
+				assert.Contains(t, body, s)
+			}
+		})
+	}
+}
+
+func TestEmailHandler_Name(t *testing.T) {
+	handler := &EmailHandler{}
+	assert.Equal(t, "Email", handler.Name())
+	assert.False(t, handler.IsStateful())
+}
@@ -18,10 +18,13 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/google/uuid"
+
 	"github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
 	"github.com/goharbor/harbor/src/pkg/notifier/formats"
 	"github.com/goharbor/harbor/src/pkg/notifier/model"
 )
@@ -54,13 +57,6 @@ func (h *HTTPHandler) IsStateful() bool {
 }
 
 func (h *HTTPHandler) process(ctx context.Context, event *model.HookEvent) error {
-	j := &models.JobData{
-		Metadata: &models.JobMetadata{
-			JobKind: job.KindGeneric,
-		},
-	}
-	j.Name = job.WebhookJobVendorType
-
 	if event == nil || event.Payload == nil || event.Target == nil {
 		return errors.Errorf("invalid event: %+v", event)
 	}
@@ -69,6 +65,22 @@ func (h *HTTPHandler) process(ctx context.Context, event *model.HookEvent) error
 	if err != nil {
 		return errors.Wrap(err, "error to get formatter")
 	}
+	return startFormattedHook(ctx, event, job.WebhookJobVendorType, formatter)
+}
+
+// startFormattedHook formats the event with the formatter and starts the hook with the job
+// of the vendor type which posts the formatted payload to the target address
+func startFormattedHook(ctx context.Context, event *model.HookEvent, vendorType string, formatter formats.Formatter) error {
+	if event == nil || event.Payload == nil || event.Target == nil {
+		return errors.Errorf("invalid event: %+v", event)
+	}
+
+	j := &models.JobData{
+		Metadata: &models.JobMetadata{
+			JobKind: job.KindGeneric,
+		},
+	}
+	j.Name = vendorType
 
 	header, payload, err := formatter.Format(ctx, event)
 	if err != nil {
@@ -79,6 +91,16 @@ func (h *HTTPHandler) process(ctx context.Context, event *model.HookEvent) error
 		header.Set("Authorization", event.Target.AuthHeader)
 	}
 
+	// the payload is signed with the secrets of the policy right before sending, only the
+	// policy is passed to the job which gets the signatures from the core
+	if len(event.DeliveryID) == 0 {
+		event.DeliveryID = uuid.NewString()
+	}
+	secrets, err := policy.Mgr.GetSigningSecrets(ctx, event.PolicyID)
+	if err != nil {
+		return errors.Wrapf(err, "error to get the signing secrets of policy %d", event.PolicyID)
+	}
+
 	headerBytes, err := json.Marshal(header)
 	if err != nil {
 		return errors.Wrap(err, "error to marshal header")
@@ -89,6 +111,10 @@ func (h *HTTPHandler) process(ctx context.Context, event *model.HookEvent) error
 		"address":          event.Target.Address,
 		"header":           string(headerBytes),
 		"skip_cert_verify": event.Target.SkipCertVerify,
+		"delivery_id":      event.DeliveryID,
+	}
+	if len(secrets) > 0 {
+		j.Parameters["signing_policy_id"] = event.PolicyID
 	}
 	return notification.HookManager.StartHook(ctx, event, j)
 }
@@ -2,6 +2,8 @@ package notification
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
 	"time"
 
@@ -10,9 +12,13 @@ import (
 
 	"github.com/goharbor/harbor/src/common/job/models"
 	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
 	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
 	"github.com/goharbor/harbor/src/pkg/notifier/event"
 	"github.com/goharbor/harbor/src/pkg/notifier/model"
+	"github.com/goharbor/harbor/src/testing/mock"
+	testingPolicy "github.com/goharbor/harbor/src/testing/pkg/notification/policy"
 )
 
 type fakedHookManager struct {
@@ -92,6 +98,48 @@ func TestHTTPHandler_Handle(t *testing.T) {
 	}
 }
 
+func TestHTTPHandler_Sign(t *testing.T) {
+	hookMgr := notification.HookManager
+	policyMgr := policy.Mgr
+	defer func() {
+		notification.HookManager = hookMgr
+		policy.Mgr = policyMgr
+	}()
+	recorder := &recordedHookManager{}
+	notification.HookManager = recorder
+	mgr := &testingPolicy.Manager{}
+	mgr.On("GetSigningSecrets", mock.Anything, int64(1)).Return([]string{"new", "old"}, nil)
+	policy.Mgr = mgr
+
+	hookEvent := &model.HookEvent{
+		PolicyID:  1,
+		EventType: "PUSH_ARTIFACT",
+		Target: &policy_model.EventTarget{
+			Type:       "http",
+			Address:    "http://127.0.0.1:8080",
+			AuthHeader: "Bearer token",
+		},
+		Payload: &model.Payload{
+			Type:    "PUSH_ARTIFACT",
+			OccurAt: time.Now().Unix(),
+		},
+	}
+	handler := &HTTPHandler{}
+	require.Nil(t, handler.Handle(context.TODO(), hookEvent))
+	require.NotNil(t, recorder.job)
+
+	header := http.Header{}
+	require.Nil(t, json.Unmarshal([]byte(recorder.job.Parameters["header"].(string)), &header))
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	// the payload is signed by the job right before sending
+	assert.Empty(t, header.Get(signature.HeaderSignature))
+	assert.NotEmpty(t, hookEvent.DeliveryID)
+	assert.Equal(t, hookEvent.DeliveryID, recorder.job.Parameters["delivery_id"])
+	// the secrets are never passed to the job
+	assert.Equal(t, int64(1), recorder.job.Parameters["signing_policy_id"])
+	assert.NotContains(t, recorder.job.Parameters, "signing_secrets")
+}
+
 func TestHTTPHandler_IsStateful(t *testing.T) {
 	handler := &HTTPHandler{}
 	assert.False(t, handler.IsStateful())
@@ -14,8 +14,55 @@
 
 package model
 
+import "github.com/goharbor/harbor/src/jobservice/job"
+
 // const definitions
 const (
 	NotifyTypeHTTP  = "http"
 	NotifyTypeSlack = "slack"
+	NotifyTypeEmail = "email"
+	// NotifyTypeTeams sends the Adaptive Card to Microsoft Teams
+	NotifyTypeTeams = "teams"
+	// NotifyTypeGoogleChat sends the message to Google Chat
+	NotifyTypeGoogleChat = "googlechat"
+	// NotifyTypeCustom sends the payload rendered by the template defined in the target
+	NotifyTypeCustom = "custom"
 )
+
+// notifyTypeVendorTypes maps the notify types to the vendor types of the jobs sending the notifications
+var notifyTypeVendorTypes = map[string]string{
+	NotifyTypeHTTP:       job.WebhookJobVendorType,
+	NotifyTypeSlack:      job.SlackJobVendorType,
+	NotifyTypeEmail:      job.EmailJobVendorType,
+	NotifyTypeTeams:      job.TeamsJobVendorType,
+	NotifyTypeGoogleChat: job.GoogleChatJobVendorType,
+	NotifyTypeCustom:     job.CustomJobVendorType,
+}
+
+// VendorTypeOf returns the vendor type of the job sending the notification of the notify type,
+// empty string is returned for the unknown notify types
+func VendorTypeOf(notifyType string) string {
+	return notifyTypeVendorTypes[notifyType]
+}
+
+// NotifyTypeOf returns the notify type of the vendor type, it is the reverse of VendorTypeOf
+func NotifyTypeOf(vendorType string) string {
+	for notifyType, vt := range notifyTypeVendorTypes {
+		if vt == vendorType {
+			return notifyType
+		}
+	}
+	return ""
+}
+
+// notifyTypes keeps the notify types in a stable order
+var notifyTypes = []string{NotifyTypeHTTP, NotifyTypeSlack, NotifyTypeEmail, NotifyTypeTeams, NotifyTypeGoogleChat, NotifyTypeCustom}
+
+// NotificationVendorTypes returns the vendor types of all the jobs sending the notifications
+func NotificationVendorTypes() []string {
+	var vendorTypes []string
+	for _, notifyType := range notifyTypes {
+		vendorTypes = append(vendorTypes, notifyTypeVendorTypes[notifyType])
+	}
+	return vendorTypes
+}
@@ -26,6 +26,8 @@ type HookEvent struct {
 	EventType string
 	Target    *policy_model.EventTarget
 	Payload   *Payload
+	// DeliveryID identifies the delivery of the event to the target, it keeps unchanged when re-delivered
+	DeliveryID string
 }
 
 // Payload of notification event
@@ -22,4 +22,10 @@ const (
 	SlackTopic = "slack"
 	// EmailTopic is topic for sending email payload
 	EmailTopic = "email"
+	// TeamsTopic is topic for sending Microsoft Teams payload
+	TeamsTopic = "teams"
+	// GoogleChatTopic is topic for sending Google Chat payload
+	GoogleChatTopic = "googlechat"
+	// CustomTopic is topic for sending the payload rendered by the custom template
+	CustomTopic = "custom"
 )
@@ -24,8 +24,12 @@ import (
 // Subscribe topics
 func init() {
 	handlersMap := map[string][]notifier.NotificationHandler{
-		model.WebhookTopic: {&notification.HTTPHandler{}},
-		model.SlackTopic:   {&notification.SlackHandler{}},
+		model.WebhookTopic:    {&notification.HTTPHandler{}},
+		model.SlackTopic:      {&notification.SlackHandler{}},
+		model.EmailTopic:      {&notification.EmailHandler{}},
+		model.TeamsTopic:      {&notification.TeamsHandler{}},
+		model.GoogleChatTopic: {&notification.GoogleChatHandler{}},
+		model.CustomTopic:     {&notification.CustomHandler{}},
 	}
 
 	for t, handlers := range handlersMap {
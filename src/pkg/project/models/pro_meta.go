@@ -16,13 +16,18 @@ package models
 
 // keys of project metadata and severity values
 const (
-	ProMetaPublic                   = "public"
-	ProMetaEnableContentTrust       = "enable_content_trust"
-	ProMetaEnableContentTrustCosign = "enable_content_trust_cosign"
-	ProMetaPreventVul               = "prevent_vul" // prevent vulnerable images from being pulled
-	ProMetaSeverity                 = "severity"
-	ProMetaAutoScan                 = "auto_scan"
-	ProMetaReuseSysCVEAllowlist     = "reuse_sys_cve_allowlist"
-	ProMetaAutoSBOMGen              = "auto_sbom_generation"
-	ProMetaProxySpeed               = "proxy_speed_kb"
+	ProMetaPublic                         = "public"
+	ProMetaEnableContentTrust             = "enable_content_trust"
+	ProMetaEnableContentTrustCosign       = "enable_content_trust_cosign"
+	ProMetaPreventVul                     = "prevent_vul" // prevent vulnerable images from being pulled
+	ProMetaSeverity                       = "severity"
+	ProMetaAutoScan                       = "auto_scan"
+	ProMetaReuseSysCVEAllowlist           = "reuse_sys_cve_allowlist"
+	ProMetaAutoSBOMGen                    = "auto_sbom_generation"
+	ProMetaScanExternalSBOM               = "scan_external_sbom" // submit the SBOMs pushed by the users to the scanners
+	ProMetaProxySpeed                     = "proxy_speed_kb"
+	ProMetaProxyCacheTTL                  = "proxy_cache_ttl" // in seconds
+	ProMetaProxyCacheStaleWhileRevalidate = "proxy_cache_stale_while_revalidate"
+	ProMetaProxyCacheStaleIfError         = "proxy_cache_stale_if_error"
+	ProMetaProxyCacheUpstreamRegistries   = "proxy_cache_upstream_registries" // comma separated registry IDs
 )
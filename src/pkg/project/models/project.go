@@ -156,6 +156,15 @@ func (p *Project) AutoSBOMGen() bool {
 	return isTrue(auto)
 }
 
+// ScanExternalSBOM ...
+func (p *Project) ScanExternalSBOM() bool {
+	scan, exist := p.GetMetadata(ProMetaScanExternalSBOM)
+	if !exist {
+		return false
+	}
+	return isTrue(scan)
+}
+
 // ProxyCacheSpeed ...
 func (p *Project) ProxyCacheSpeed() int32 {
 	speed, exist := p.GetMetadata(ProMetaProxySpeed)
@@ -169,6 +178,61 @@ func (p *Project) ProxyCacheSpeed() int32 {
 	return int32(speedInt)
 }
 
+// ProxyCacheTTL returns the freshness TTL of the tag to digest resolution of proxy cache
+func (p *Project) ProxyCacheTTL() time.Duration {
+	ttl, exist := p.GetMetadata(ProMetaProxyCacheTTL)
+	if !exist {
+		return 0
+	}
+	seconds, err := strconv.ParseInt(ttl, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ProxyCacheStaleWhileRevalidate ...
+func (p *Project) ProxyCacheStaleWhileRevalidate() bool {
+	swr, exist := p.GetMetadata(ProMetaProxyCacheStaleWhileRevalidate)
+	if !exist {
+		return false
+	}
+	return isTrue(swr)
+}
+
+// ProxyCacheStaleIfError ...
+func (p *Project) ProxyCacheStaleIfError() bool {
+	sie, exist := p.GetMetadata(ProMetaProxyCacheStaleIfError)
+	if !exist {
+		return false
+	}
+	return isTrue(sie)
+}
+
+// ProxyCacheUpstreamRegistryIDs returns the IDs of the upstream registries of the proxy cache project
+// in the order they are tried: the registry the project is bound to comes first, followed by the
+// fallback registries configured in the metadata. Invalid and duplicated IDs are skipped.
+func (p *Project) ProxyCacheUpstreamRegistryIDs() []int64 {
+	if p.RegistryID < 1 {
+		return nil
+	}
+	ids := []int64{p.RegistryID}
+	upstreams, exist := p.GetMetadata(ProMetaProxyCacheUpstreamRegistries)
+	if !exist {
+		return ids
+	}
+	seen := map[int64]bool{p.RegistryID: true}
+	for _, item := range strings.Split(upstreams, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(item), 10, 64)
+		if err != nil || id < 1 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // FilterByPublic returns orm.QuerySeter with public filter
 func (p *Project) FilterByPublic(_ context.Context, qs orm.QuerySeter, _ string, value interface{}) orm.QuerySeter {
 	subQuery := `SELECT project_id FROM project_metadata WHERE name = 'public' AND value = '%s'`
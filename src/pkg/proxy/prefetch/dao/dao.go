@@ -0,0 +1,117 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+)
+
+// DAO defines the interface to access the prefetch policy data model
+type DAO interface {
+	// Create the prefetch policy
+	Create(ctx context.Context, policy *model.Policy) (int64, error)
+	// Update the prefetch policy
+	Update(ctx context.Context, policy *model.Policy, props ...string) error
+	// Get the prefetch policy by ID
+	Get(ctx context.Context, id int64) (*model.Policy, error)
+	// List the prefetch policies
+	List(ctx context.Context, query *q.Query) ([]*model.Policy, error)
+	// Delete the prefetch policy by ID
+	Delete(ctx context.Context, id int64) error
+}
+
+// New creates a default implementation for DAO
+func New() DAO {
+	return &dao{}
+}
+
+type dao struct{}
+
+func (d *dao) Create(ctx context.Context, policy *model.Policy) (int64, error) {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	id, err := ormer.Insert(policy)
+	if err != nil {
+		if e := orm.AsConflictError(err, "prefetch policy of project %d already exists", policy.ProjectID); e != nil {
+			err = e
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (d *dao) Update(ctx context.Context, policy *model.Policy, props ...string) error {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := ormer.Update(policy, props...)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.NotFoundError(nil).WithMessagef("prefetch policy %d not found", policy.ID)
+	}
+	return nil
+}
+
+func (d *dao) Get(ctx context.Context, id int64) (*model.Policy, error) {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy := &model.Policy{ID: id}
+	if err = ormer.Read(policy); err != nil {
+		if e := orm.AsNotFoundError(err, "prefetch policy %d not found", id); e != nil {
+			err = e
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (d *dao) List(ctx context.Context, query *q.Query) ([]*model.Policy, error) {
+	policies := []*model.Policy{}
+	qs, err := orm.QuerySetter(ctx, &model.Policy{}, query)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = qs.All(&policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (d *dao) Delete(ctx context.Context, id int64) error {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	n, err := ormer.Delete(&model.Policy{ID: id})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.NotFoundError(nil).WithMessagef("prefetch policy %d not found", id)
+	}
+	return nil
+}
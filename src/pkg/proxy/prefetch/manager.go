@@ -0,0 +1,120 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/dao"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+)
+
+// Mgr is the global prefetch policy manager
+var Mgr = NewManager()
+
+// Manager manages the prefetch policies of the proxy cache projects
+type Manager interface {
+	// Create the prefetch policy
+	Create(ctx context.Context, policy *model.Policy) (int64, error)
+	// Update the prefetch policy
+	Update(ctx context.Context, policy *model.Policy, props ...string) error
+	// Get the prefetch policy by ID
+	Get(ctx context.Context, id int64) (*model.Policy, error)
+	// GetByProject gets the prefetch policy of the project, a not found error is returned if the project has no policy
+	GetByProject(ctx context.Context, projectID int64) (*model.Policy, error)
+	// List the prefetch policies
+	List(ctx context.Context, query *q.Query) ([]*model.Policy, error)
+	// Delete the prefetch policy by ID
+	Delete(ctx context.Context, id int64) error
+	// IsPinned checks whether any of the tags under the upstream repository of the project is pinned
+	IsPinned(ctx context.Context, projectID int64, repository string, tags ...string) (bool, error)
+}
+
+// NewManager creates a default implementation for Manager
+func NewManager() Manager {
+	return &manager{
+		dao: dao.New(),
+	}
+}
+
+type manager struct {
+	dao dao.DAO
+}
+
+func (m *manager) Create(ctx context.Context, policy *model.Policy) (int64, error) {
+	if err := policy.Encode(); err != nil {
+		return 0, err
+	}
+	return m.dao.Create(ctx, policy)
+}
+
+func (m *manager) Update(ctx context.Context, policy *model.Policy, props ...string) error {
+	if err := policy.Encode(); err != nil {
+		return err
+	}
+	return m.dao.Update(ctx, policy, props...)
+}
+
+func (m *manager) Get(ctx context.Context, id int64) (*model.Policy, error) {
+	policy, err := m.dao.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = policy.Decode(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (m *manager) GetByProject(ctx context.Context, projectID int64) (*model.Policy, error) {
+	policies, err := m.List(ctx, q.New(q.KeyWords{"ProjectID": projectID}))
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, errors.NotFoundError(nil).WithMessagef("prefetch policy of project %d not found", projectID)
+	}
+	return policies[0], nil
+}
+
+func (m *manager) List(ctx context.Context, query *q.Query) ([]*model.Policy, error) {
+	policies, err := m.dao.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		if err = policy.Decode(); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+func (m *manager) Delete(ctx context.Context, id int64) error {
+	return m.dao.Delete(ctx, id)
+}
+
+func (m *manager) IsPinned(ctx context.Context, projectID int64, repository string, tags ...string) (bool, error) {
+	policy, err := m.GetByProject(ctx, projectID)
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return policy.IsPinned(repository, tags...), nil
+}
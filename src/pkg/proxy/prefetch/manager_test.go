@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prefetch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+	"github.com/goharbor/harbor/src/testing/mock"
+	"github.com/goharbor/harbor/src/testing/pkg/proxy/prefetch/dao"
+)
+
+type managerTestSuite struct {
+	suite.Suite
+	mgr *manager
+	dao *dao.DAO
+}
+
+func (m *managerTestSuite) SetupTest() {
+	m.dao = &dao.DAO{}
+	m.mgr = &manager{
+		dao: m.dao,
+	}
+}
+
+func (m *managerTestSuite) TestCreate() {
+	m.dao.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil)
+	policy := &model.Policy{
+		ProjectID: 1,
+		Rules:     []*model.Rule{{Repository: "library/nginx"}},
+	}
+	id, err := m.mgr.Create(context.Background(), policy)
+	m.Require().Nil(err)
+	m.Equal(int64(1), id)
+	m.Equal(`[{"repository":"library/nginx","tag":""}]`, policy.RulesStr)
+	m.Empty(policy.PinnedStr)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestGetByProject() {
+	m.dao.On("List", mock.Anything, mock.Anything).Return([]*model.Policy{
+		{
+			ID:        1,
+			ProjectID: 1,
+			RulesStr:  `[{"repository":"library/nginx","tag":"1.*"}]`,
+		},
+	}, nil).Once()
+	policy, err := m.mgr.GetByProject(context.Background(), 1)
+	m.Require().Nil(err)
+	m.Require().Len(policy.Rules, 1)
+	m.Equal("1.*", policy.Rules[0].Tag)
+
+	m.dao.On("List", mock.Anything, mock.Anything).Return([]*model.Policy{}, nil).Once()
+	_, err = m.mgr.GetByProject(context.Background(), 2)
+	m.True(errors.IsNotFoundErr(err))
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestIsPinned() {
+	m.dao.On("List", mock.Anything, mock.Anything).Return([]*model.Policy{
+		{
+			ID:        1,
+			ProjectID: 1,
+			PinnedStr: `[{"repository":"library/nginx","tag":"stable"}]`,
+		},
+	}, nil).Twice()
+	pinned, err := m.mgr.IsPinned(context.Background(), 1, "library/nginx", "latest", "stable")
+	m.Require().Nil(err)
+	m.True(pinned)
+	pinned, err = m.mgr.IsPinned(context.Background(), 1, "library/nginx", "latest")
+	m.Require().Nil(err)
+	m.False(pinned)
+
+	m.dao.On("List", mock.Anything, mock.Anything).Return([]*model.Policy{}, nil).Once()
+	pinned, err = m.mgr.IsPinned(context.Background(), 2, "library/nginx", "stable")
+	m.Require().Nil(err)
+	m.False(pinned)
+	m.dao.AssertExpectations(m.T())
+}
+
+func TestManager(t *testing.T) {
+	suite.Run(t, &managerTestSuite{})
+}
@@ -0,0 +1,177 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	beego_orm "github.com/beego/beego/v2/client/orm"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+func init() {
+	beego_orm.RegisterModel(&Policy{})
+}
+
+// Policy defines the prefetch policy of a proxy cache project, the repositories and tags matched by
+// the rules are resolved against the upstream registry and cached periodically, and the tags matched
+// by the pinned tags are neither refreshed from the upstream registry nor evicted by the tag retention
+type Policy struct {
+	ID        int64 `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID int64 `orm:"column(project_id)" json:"project_id"`
+	Enabled   bool  `orm:"column(enabled)" json:"enabled"`
+	// Cron is the schedule of the prefetch, no schedule is created if it is empty
+	Cron         string       `orm:"column(cron)" json:"cron"`
+	Rules        []*Rule      `orm:"-" json:"rules"`
+	RulesStr     string       `orm:"column(rules)" json:"-"`
+	PinnedTags   []*PinnedTag `orm:"-" json:"pinned_tags"`
+	PinnedStr    string       `orm:"column(pinned_tags)" json:"-"`
+	CreationTime time.Time    `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+	UpdateTime   time.Time    `orm:"column(update_time);auto_now" json:"update_time"`
+}
+
+// Rule selects the upstream repositories and tags to prefetch
+type Rule struct {
+	// Repository is the name of the repository in the upstream registry, e.g. "library/nginx",
+	// the "{a,b}" syntax can be used to specify several repositories
+	Repository string `json:"repository"`
+	// Tag is the doublestar pattern matching the tags to prefetch, all tags are matched if it is empty
+	Tag string `json:"tag"`
+}
+
+// PinnedTag selects the cached tags which are pinned
+type PinnedTag struct {
+	// Repository is the doublestar pattern matching the name of the repository in the upstream registry
+	Repository string `json:"repository"`
+	// Tag is the doublestar pattern matching the pinned tags
+	Tag string `json:"tag"`
+}
+
+// TableName ...
+func (p *Policy) TableName() string {
+	return "proxy_cache_prefetch_policy"
+}
+
+// Validate the policy
+func (p *Policy) Validate() error {
+	if len(p.Cron) > 0 {
+		if err := utils.ValidateCronString(p.Cron); err != nil {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessagef("invalid cron string for prefetch policy: %s, error: %v", p.Cron, err)
+		}
+	}
+	for _, rule := range p.Rules {
+		if rule == nil || len(rule.Repository) == 0 {
+			return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("the repository of the prefetch rule is required")
+		}
+		// the catalog of the upstream registry isn't always available, so the repositories must be listed explicitly
+		if _, specific := util.IsSpecificPath(rule.Repository); !specific {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessagef("the repository of the prefetch rule must be specific names: %s", rule.Repository)
+		}
+		if err := validatePattern(rule.Tag); err != nil {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessagef("invalid tag pattern of the prefetch rule: %s", rule.Tag)
+		}
+	}
+	for _, pinned := range p.PinnedTags {
+		if pinned == nil || len(pinned.Repository) == 0 || len(pinned.Tag) == 0 {
+			return errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("both the repository and the tag of the pinned tag are required")
+		}
+		if err := validatePattern(pinned.Repository); err != nil {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessagef("invalid repository pattern of the pinned tag: %s", pinned.Repository)
+		}
+		if err := validatePattern(pinned.Tag); err != nil {
+			return errors.New(nil).WithCode(errors.BadRequestCode).
+				WithMessagef("invalid tag pattern of the pinned tag: %s", pinned.Tag)
+		}
+	}
+	return nil
+}
+
+// the syntax of the whole pattern is checked only when matching a string with the same path depth,
+// so the pattern is matched against itself
+func validatePattern(pattern string) error {
+	_, err := util.Match(pattern, pattern)
+	return err
+}
+
+// IsPinned checks whether any of the tags under the upstream repository is pinned
+func (p *Policy) IsPinned(repository string, tags ...string) bool {
+	for _, pinned := range p.PinnedTags {
+		matched, err := util.Match(pinned.Repository, repository)
+		if err != nil {
+			log.Errorf("failed to match the repository %s with the pattern %s: %v", repository, pinned.Repository, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		for _, tag := range tags {
+			matched, err = util.Match(pinned.Tag, tag)
+			if err != nil {
+				log.Errorf("failed to match the tag %s with the pattern %s: %v", tag, pinned.Tag, err)
+				break
+			}
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Encode encodes the rules and the pinned tags of the policy
+func (p *Policy) Encode() error {
+	p.RulesStr, p.PinnedStr = "", ""
+	if len(p.Rules) > 0 {
+		rules, err := json.Marshal(p.Rules)
+		if err != nil {
+			return err
+		}
+		p.RulesStr = string(rules)
+	}
+	if len(p.PinnedTags) > 0 {
+		pinned, err := json.Marshal(p.PinnedTags)
+		if err != nil {
+			return err
+		}
+		p.PinnedStr = string(pinned)
+	}
+	return nil
+}
+
+// Decode decodes the rules and the pinned tags of the policy
+func (p *Policy) Decode() error {
+	p.Rules = []*Rule{}
+	if len(p.RulesStr) > 0 {
+		if err := json.Unmarshal([]byte(p.RulesStr), &p.Rules); err != nil {
+			return err
+		}
+	}
+	p.PinnedTags = []*PinnedTag{}
+	if len(p.PinnedStr) > 0 {
+		if err := json.Unmarshal([]byte(p.PinnedStr), &p.PinnedTags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy *Policy
+		valid  bool
+	}{
+		{
+			name:   "empty policy",
+			policy: &Policy{},
+			valid:  true,
+		},
+		{
+			name: "valid policy",
+			policy: &Policy{
+				Cron:       "0 0 * * * *",
+				Rules:      []*Rule{{Repository: "library/{nginx,redis}", Tag: "1.*"}},
+				PinnedTags: []*PinnedTag{{Repository: "library/**", Tag: "stable"}},
+			},
+			valid: true,
+		},
+		{
+			name:   "invalid cron",
+			policy: &Policy{Cron: "invalid"},
+		},
+		{
+			name:   "rule without repository",
+			policy: &Policy{Rules: []*Rule{{Tag: "latest"}}},
+		},
+		{
+			name:   "rule with repository pattern",
+			policy: &Policy{Rules: []*Rule{{Repository: "library/**"}}},
+		},
+		{
+			name:   "rule with invalid tag pattern",
+			policy: &Policy{Rules: []*Rule{{Repository: "library/nginx", Tag: "1.[0"}}},
+		},
+		{
+			name:   "pinned tag without tag",
+			policy: &Policy{PinnedTags: []*PinnedTag{{Repository: "library/nginx"}}},
+		},
+		{
+			name:   "pinned tag with invalid repository pattern",
+			policy: &Policy{PinnedTags: []*PinnedTag{{Repository: "library/{nginx", Tag: "latest"}}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.policy.Validate()
+			if c.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestIsPinned(t *testing.T) {
+	policy := &Policy{
+		PinnedTags: []*PinnedTag{
+			{Repository: "library/nginx", Tag: "1.2*"},
+			{Repository: "bitnami/**", Tag: "stable"},
+		},
+	}
+	assert.True(t, policy.IsPinned("library/nginx", "latest", "1.25"))
+	assert.False(t, policy.IsPinned("library/nginx", "latest"))
+	assert.True(t, policy.IsPinned("bitnami/charts/redis", "stable"))
+	assert.False(t, policy.IsPinned("library/redis", "1.2", "stable"))
+	assert.False(t, policy.IsPinned("library/nginx"))
+	assert.False(t, (&Policy{}).IsPinned("library/nginx", "latest"))
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+	policy := &Policy{
+		Rules:      []*Rule{{Repository: "library/nginx", Tag: "1.*"}},
+		PinnedTags: []*PinnedTag{{Repository: "library/nginx", Tag: "1.25"}},
+	}
+	require.Nil(t, policy.Encode())
+
+	decoded := &Policy{RulesStr: policy.RulesStr, PinnedStr: policy.PinnedStr}
+	require.Nil(t, decoded.Decode())
+	assert.Equal(t, policy.Rules, decoded.Rules)
+	assert.Equal(t, policy.PinnedTags, decoded.PinnedTags)
+
+	empty := &Policy{}
+	require.Nil(t, empty.Decode())
+	assert.Empty(t, empty.Rules)
+	assert.Empty(t, empty.PinnedTags)
+}
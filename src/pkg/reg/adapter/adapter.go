@@ -21,7 +21,9 @@ import (
 	"sort"
 
 	"github.com/docker/distribution"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 
+	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 )
 
@@ -51,6 +53,12 @@ type Adapter interface {
 	HealthCheck() (string, error)
 }
 
+// URLValidator is implemented by the factories whose registry URLs aren't the HTTP URLs
+type URLValidator interface {
+	// ValidateURL validates the URL and returns the normalized one
+	ValidateURL(url string) (string, error)
+}
+
 // ArtifactRegistry defines the capabilities that an artifact registry should have
 type ArtifactRegistry interface {
 	FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error)
@@ -69,6 +77,12 @@ type ArtifactRegistry interface {
 	ListTags(repository string) (tags []string, err error)
 }
 
+// ReferrerRegistry defines the capability of listing the referrers of the artifacts with the OCI referrers API
+type ReferrerRegistry interface {
+	// ListReferrers returns the not found error if the referrers API isn't supported by the registry
+	ListReferrers(repository, digest string) ([]v1.Descriptor, error)
+}
+
 // RegisterFactory registers one adapter factory to the registry
 func RegisterFactory(t string, factory Factory) error {
 	if len(t) == 0 {
@@ -100,6 +114,17 @@ func GetFactory(t string) (Factory, error) {
 	return factory, nil
 }
 
+// ValidateURL validates the URL of the registry with the specified type, the URL
+// must be an HTTP URL unless the factory of the type implements the URLValidator
+func ValidateURL(t, url string) (string, error) {
+	if factory, exist := registry[t]; exist {
+		if validator, ok := factory.(URLValidator); ok {
+			return validator.ValidateURL(url)
+		}
+	}
+	return lib.ValidateHTTPURL(url)
+}
+
 // ListRegisteredAdapterTypes lists the registered Adapter type
 func ListRegisteredAdapterTypes() []string {
 	return registryKeys
@@ -0,0 +1,282 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudsmith
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+// !!!! Limits:
+// - Cloudsmith doesn't support `/v2/_catalog`, the repositories are listed by the Cloudsmith API,
+//   which requires the API key unless the repository name filter is a specific name.
+// - The repositories are in format "<organization>/<repository>/<image>", and the Cloudsmith
+//   repositories are NOT created automatically when pushing.
+// - The access key of the credential is the user name and the access secret is the API key.
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeCloudsmith, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeCloudsmith, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeCloudsmith)
+}
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r), nil
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return getAdapterPattern()
+}
+
+func getAdapterPattern() *model.AdapterPattern {
+	return &model.AdapterPattern{
+		EndpointPattern: &model.EndpointPattern{
+			EndpointType: model.EndpointPatternTypeFix,
+			Endpoints: []*model.Endpoint{
+				{
+					Key:   "docker.cloudsmith.io",
+					Value: "https://docker.cloudsmith.io",
+				},
+			},
+		},
+	}
+}
+
+var (
+	_ adp.Adapter          = (*adapter)(nil)
+	_ adp.ArtifactRegistry = (*adapter)(nil)
+)
+
+// adapter for the docker registry of Cloudsmith
+type adapter struct {
+	*native.Adapter
+	registry *model.Registry
+	client   *client
+}
+
+func newAdapter(registry *model.Registry) *adapter {
+	return &adapter{
+		Adapter:  native.NewAdapter(registry),
+		registry: registry,
+		client:   newClient(registry),
+	}
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeCloudsmith,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+func (a *adapter) hasAPIKey() bool {
+	return a.registry.Credential != nil && len(a.registry.Credential.AccessSecret) > 0
+}
+
+// HealthCheck checks the API key when it is provided, otherwise pings the registry
+func (a *adapter) HealthCheck() (string, error) {
+	var err error
+	if a.hasAPIKey() {
+		err = a.client.checkUser()
+	} else {
+		err = a.PingSimple()
+	}
+	if err != nil {
+		log.Errorf("failed to check the health of Cloudsmith: %v", err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// splitRepository splits the repository into the organization, the Cloudsmith repository and the image name
+func splitRepository(repository string) (string, string, string, error) {
+	parts := strings.SplitN(repository, "/", 3)
+	if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 || len(parts[2]) == 0 {
+		return "", "", "", fmt.Errorf("invalid repository %s, the name should be in format \"<organization>/<repository>/<image>\"", repository)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// PrepareForPush checks the existence of the Cloudsmith repositories that the resources will be pushed to
+func (a *adapter) PrepareForPush(resources []*model.Resource) error {
+	checked := map[string]struct{}{}
+	for _, resource := range resources {
+		if resource == nil {
+			return errors.New("the resource cannot be null")
+		}
+		if resource.Metadata == nil {
+			return errors.New("the metadata of resource cannot be null")
+		}
+		if resource.Metadata.Repository == nil {
+			return errors.New("the namespace of resource cannot be null")
+		}
+		org, repo, _, err := splitRepository(resource.Metadata.Repository.Name)
+		if err != nil {
+			return err
+		}
+		key := fmt.Sprintf("%s/%s", org, repo)
+		if _, exist := checked[key]; exist {
+			continue
+		}
+		if _, err = a.client.getRepository(org, repo); err != nil {
+			return fmt.Errorf("failed to get the repository %s of Cloudsmith, create it before pushing: %v", key, err)
+		}
+		checked[key] = struct{}{}
+	}
+	return nil
+}
+
+// FetchArtifacts ...
+func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	pattern := ""
+	for _, filter := range filters {
+		if filter.Type == model.FilterTypeName {
+			pattern = filter.Value.(string)
+			break
+		}
+	}
+
+	// repository name -> tags, the tags of the specific repositories are listed later
+	tagsOfRepository := map[string][]string{}
+	// if the pattern of repository name filter is a specific repository name, just uses
+	// the parsed repositories and will check the existence later when listing the tags
+	paths, specific := util.IsSpecificPath(pattern)
+	if specific {
+		for _, path := range paths {
+			tagsOfRepository[path] = nil
+		}
+	} else {
+		if !a.hasAPIKey() {
+			return nil, errors.New("the API key is required to list the repositories of Cloudsmith unless the repository name is specified")
+		}
+		orgs, err := a.client.listOrganizations()
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			repos, err := a.client.listRepositories(org.Slug)
+			if err != nil {
+				return nil, err
+			}
+			for _, repo := range repos {
+				packages, err := a.client.listDockerPackages(org.Slug, repo.Slug)
+				if err != nil {
+					return nil, err
+				}
+				for _, p := range packages {
+					name := fmt.Sprintf("%s/%s/%s", org.Slug, repo.Slug, p.Name)
+					tags := p.Tags.Version
+					if len(tags) == 0 && len(p.Version) > 0 && !strings.HasPrefix(p.Version, "sha256:") {
+						tags = []string{p.Version}
+					}
+					tagsOfRepository[name] = append(tagsOfRepository[name], tags...)
+				}
+			}
+		}
+	}
+
+	var repositories []*model.Repository
+	for name := range tagsOfRepository {
+		repositories = append(repositories, &model.Repository{Name: name})
+	}
+	repositories, err := filter.DoFilterRepositories(repositories, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawResources = make([]*model.Resource, len(repositories))
+	runner := utils.NewLimitedConcurrentRunner(adp.MaxConcurrency)
+	for i, r := range repositories {
+		index := i
+		repo := r
+		runner.AddTask(func() error {
+			tags := tagsOfRepository[repo.Name]
+			if specific {
+				var err error
+				tags, err = a.ListTags(repo.Name)
+				if err != nil {
+					return fmt.Errorf("failed to list tags of repository %s: %v", repo.Name, err)
+				}
+			}
+			var artifacts []*model.Artifact
+			for _, tag := range tags {
+				artifacts = append(artifacts, &model.Artifact{
+					Tags: []string{tag},
+				})
+			}
+			artifacts, err := filter.DoFilterArtifacts(artifacts, filters)
+			if err != nil {
+				return err
+			}
+			if len(artifacts) == 0 {
+				return nil
+			}
+			rawResources[index] = &model.Resource{
+				Type:     model.ResourceTypeImage,
+				Registry: a.registry,
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: repo.Name,
+					},
+					Artifacts: artifacts,
+				},
+			}
+			return nil
+		})
+	}
+	if err = runner.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch artifacts: %v", err)
+	}
+
+	var resources []*model.Resource
+	for _, r := range rawResources {
+		if r != nil {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,171 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudsmith
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func mockCloudsmith() *httptest.Server {
+	authenticated := func(handler func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Api-Key") != "key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			handler(w, r)
+		}
+	}
+	return test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v1/user/self/",
+			Handler: authenticated(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`{"authenticated":true,"slug":"alice"}`))
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v1/orgs/",
+			Handler: authenticated(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`[{"name":"ACME","slug":"acme"}]`))
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v1/repos/acme/prod/",
+			Handler: authenticated(func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`{"name":"prod","slug":"prod"}`))
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v1/repos/acme/",
+			Handler: authenticated(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/repos/acme/" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Write([]byte(`[{"name":"prod","slug":"prod"}]`))
+			}),
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v1/packages/acme/prod/",
+			Handler: authenticated(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(pageTotalHeader, "2")
+				if r.URL.Query().Get("page") == "1" {
+					w.Write([]byte(`[{"name":"app","format":"docker","version":"sha256:abc","tags":{"version":["1.0","latest"]}}]`))
+					return
+				}
+				w.Write([]byte(`[{"name":"web/frontend","format":"docker","version":"2.0","tags":{}}]`))
+			}),
+		},
+	)
+}
+
+func TestGetAdapterPattern(t *testing.T) {
+	pattern := getAdapterPattern()
+	require.NotNil(t, pattern)
+	assert.Equal(t, model.EndpointPatternTypeFix, pattern.EndpointPattern.EndpointType)
+	assert.Equal(t, "https://docker.cloudsmith.io", pattern.EndpointPattern.Endpoints[0].Value)
+}
+
+func TestHealthCheck(t *testing.T) {
+	server := mockCloudsmith()
+	defer server.Close()
+	apiEndpoint = server.URL
+
+	a := newAdapter(&model.Registry{
+		URL:        "https://docker.cloudsmith.io",
+		Credential: &model.Credential{AccessKey: "alice", AccessSecret: "key"},
+	})
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Healthy, status)
+
+	a = newAdapter(&model.Registry{
+		URL:        "https://docker.cloudsmith.io",
+		Credential: &model.Credential{AccessKey: "alice", AccessSecret: "invalid"},
+	})
+	status, err = a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Unhealthy, status)
+}
+
+func TestFetchArtifacts(t *testing.T) {
+	server := mockCloudsmith()
+	defer server.Close()
+	apiEndpoint = server.URL
+
+	// the API key is required to list the repositories
+	a := newAdapter(&model.Registry{URL: "https://docker.cloudsmith.io"})
+	_, err := a.FetchArtifacts(nil)
+	assert.NotNil(t, err)
+
+	a = newAdapter(&model.Registry{
+		URL:        "https://docker.cloudsmith.io",
+		Credential: &model.Credential{AccessKey: "alice", AccessSecret: "key"},
+	})
+	resources, err := a.FetchArtifacts(nil)
+	require.Nil(t, err)
+	require.Len(t, resources, 2)
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Metadata.Repository.Name < resources[j].Metadata.Repository.Name
+	})
+	assert.Equal(t, "acme/prod/app", resources[0].Metadata.Repository.Name)
+	assert.Len(t, resources[0].Metadata.Artifacts, 2)
+	// the version is used when the package has no tag
+	assert.Equal(t, "acme/prod/web/frontend", resources[1].Metadata.Repository.Name)
+	assert.Equal(t, []string{"2.0"}, resources[1].Metadata.Artifacts[0].Tags)
+
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{Type: model.FilterTypeName, Value: "acme/prod/a*"},
+		{Type: model.FilterTypeTag, Value: "1.*"},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, []string{"1.0"}, resources[0].Metadata.Artifacts[0].Tags)
+}
+
+func TestPrepareForPush(t *testing.T) {
+	server := mockCloudsmith()
+	defer server.Close()
+	apiEndpoint = server.URL
+
+	a := newAdapter(&model.Registry{
+		URL:        "https://docker.cloudsmith.io",
+		Credential: &model.Credential{AccessKey: "alice", AccessSecret: "key"},
+	})
+	resource := func(name string) *model.Resource {
+		return &model.Resource{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{Name: name},
+			},
+		}
+	}
+	assert.Nil(t, a.PrepareForPush([]*model.Resource{resource("acme/prod/app"), resource("acme/prod/web/frontend")}))
+	assert.NotNil(t, a.PrepareForPush([]*model.Resource{resource("acme/dev/app")}))
+	assert.NotNil(t, a.PrepareForPush([]*model.Resource{resource("acme/app")}))
+}
@@ -0,0 +1,179 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudsmith
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+const (
+	// pageSize is the size of page used to list the packages
+	pageSize = 100
+	// pageTotalHeader is the header carrying the count of pages
+	pageTotalHeader = "X-Pagination-PageTotal"
+)
+
+// apiEndpoint is the endpoint of the Cloudsmith API, it's a variable to be overridden in testing
+var apiEndpoint = "https://api.cloudsmith.io"
+
+// namespace is the organization or repository of Cloudsmith
+type namespace struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// pkg is the package returned by the packages API of Cloudsmith
+type pkg struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Format  string `json:"format"`
+	Tags    struct {
+		Version []string `json:"version"`
+	} `json:"tags"`
+}
+
+// apiKeyAuthorizer authorizes the requests with the API key of Cloudsmith
+type apiKeyAuthorizer struct {
+	apiKey string
+}
+
+// Modify sets the API key header of the request
+func (a *apiKeyAuthorizer) Modify(req *http.Request) error {
+	req.Header.Set("X-Api-Key", a.apiKey)
+	return nil
+}
+
+// client is a client to interact with the REST API of Cloudsmith
+type client struct {
+	client *common_http.Client
+	url    string
+}
+
+// newClient constructs a Cloudsmith client, the access secret of the credential is the API key
+func newClient(reg *model.Registry) *client {
+	var modifiers []modifier.Modifier
+	if reg.Credential != nil && len(reg.Credential.AccessSecret) > 0 {
+		modifiers = append(modifiers, &apiKeyAuthorizer{apiKey: reg.Credential.AccessSecret})
+	}
+	return &client{
+		client: common_http.NewClient(
+			&http.Client{
+				Transport: common_http.GetHTTPTransport(common_http.WithInsecure(reg.Insecure)),
+			},
+			modifiers...,
+		),
+		url: apiEndpoint,
+	}
+}
+
+// get requests the URL, decodes the response into "v" and returns the response headers
+func (c *client) get(url string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &common_http.Error{
+			Code:    resp.StatusCode,
+			Message: string(data),
+		}
+	}
+	return resp.Header, json.Unmarshal(data, v)
+}
+
+// checkUser checks whether the API key is valid
+func (c *client) checkUser() error {
+	user := struct {
+		Authenticated bool   `json:"authenticated"`
+		Slug          string `json:"slug"`
+	}{}
+	if _, err := c.get(fmt.Sprintf("%s/v1/user/self/", c.url), &user); err != nil {
+		return err
+	}
+	if !user.Authenticated {
+		return fmt.Errorf("the API key isn't authenticated")
+	}
+	return nil
+}
+
+// listOrganizations lists the organizations that the user belongs to
+func (c *client) listOrganizations() ([]*namespace, error) {
+	var orgs []*namespace
+	if _, err := c.get(fmt.Sprintf("%s/v1/orgs/", c.url), &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// listRepositories lists the repositories of the organization
+func (c *client) listRepositories(org string) ([]*namespace, error) {
+	var repos []*namespace
+	if _, err := c.get(fmt.Sprintf("%s/v1/repos/%s/", c.url, org), &repos); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// getRepository gets the repository of the organization
+func (c *client) getRepository(org, repo string) (*namespace, error) {
+	repository := &namespace{}
+	if _, err := c.get(fmt.Sprintf("%s/v1/repos/%s/%s/", c.url, org, repo), repository); err != nil {
+		return nil, err
+	}
+	return repository, nil
+}
+
+// listDockerPackages lists the docker packages of the repository
+func (c *client) listDockerPackages(org, repo string) ([]*pkg, error) {
+	var packages []*pkg
+	for page := 1; ; page++ {
+		var pkgs []*pkg
+		header, err := c.get(fmt.Sprintf("%s/v1/packages/%s/%s/?query=format:docker&page=%d&page_size=%d",
+			c.url, org, repo, page, pageSize), &pkgs)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkgs...)
+		// stop when the count of pages is reached, or when there is no more item if the header is absent
+		total, err := strconv.Atoi(header.Get(pageTotalHeader))
+		if err != nil {
+			if len(pkgs) < pageSize {
+				break
+			}
+			continue
+		}
+		if page >= total {
+			break
+		}
+	}
+	return packages, nil
+}
@@ -0,0 +1,224 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+// !!!! Limits:
+// - Gitea doesn't support `/v2/_catalog`, the repositories are listed by the packages API,
+//   which requires the credential unless the repository name filter is a specific name.
+// - The repositories are in format "<owner>/<image>", the owner is a user or an organization.
+// - Works with Forgejo as well as it keeps the APIs of Gitea.
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeGitea, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeGitea, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeGitea)
+}
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r), nil
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+var (
+	_ adp.Adapter          = (*adapter)(nil)
+	_ adp.ArtifactRegistry = (*adapter)(nil)
+)
+
+// adapter for the container registry of Gitea
+type adapter struct {
+	*native.Adapter
+	registry *model.Registry
+	client   *client
+}
+
+func newAdapter(registry *model.Registry) *adapter {
+	registry.URL = strings.TrimSuffix(registry.URL, "/")
+	return &adapter{
+		Adapter:  native.NewAdapter(registry),
+		registry: registry,
+		client:   newClient(registry),
+	}
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeGitea,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+func (a *adapter) hasCredential() bool {
+	return a.registry.Credential != nil && len(a.registry.Credential.AccessKey) > 0
+}
+
+// HealthCheck checks the credential via the user API when it is provided, otherwise pings the registry
+func (a *adapter) HealthCheck() (string, error) {
+	var err error
+	if a.hasCredential() {
+		_, err = a.client.getCurrentUser()
+	} else {
+		err = a.PingSimple()
+	}
+	if err != nil {
+		log.Errorf("failed to check the health of Gitea %s: %v", a.registry.URL, err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// FetchArtifacts ...
+func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	pattern := ""
+	for _, filter := range filters {
+		if filter.Type == model.FilterTypeName {
+			pattern = filter.Value.(string)
+			break
+		}
+	}
+
+	// repository name -> tags, the tags of the specific repositories are listed later
+	tagsOfRepository := map[string][]string{}
+	// if the pattern of repository name filter is a specific repository name, just uses
+	// the parsed repositories and will check the existence later when listing the tags
+	paths, specific := util.IsSpecificPath(pattern)
+	if specific {
+		for _, path := range paths {
+			tagsOfRepository[path] = nil
+		}
+	} else {
+		if !a.hasCredential() {
+			return nil, errors.New("the credential is required to list the repositories of Gitea unless the repository name is specified")
+		}
+		owners, err := a.client.listOwners()
+		if err != nil {
+			return nil, err
+		}
+		for _, owner := range owners {
+			packages, err := a.client.listContainerPackages(owner)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range packages {
+				// the untagged manifests are listed as the versions named by the digest
+				if strings.HasPrefix(p.Version, "sha256:") {
+					continue
+				}
+				name := fmt.Sprintf("%s/%s", owner, p.Name)
+				tagsOfRepository[name] = append(tagsOfRepository[name], p.Version)
+			}
+		}
+	}
+
+	var repositories []*model.Repository
+	for name := range tagsOfRepository {
+		repositories = append(repositories, &model.Repository{Name: name})
+	}
+	repositories, err := filter.DoFilterRepositories(repositories, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawResources = make([]*model.Resource, len(repositories))
+	runner := utils.NewLimitedConcurrentRunner(adp.MaxConcurrency)
+	for i, r := range repositories {
+		index := i
+		repo := r
+		runner.AddTask(func() error {
+			tags := tagsOfRepository[repo.Name]
+			if specific {
+				var err error
+				tags, err = a.ListTags(repo.Name)
+				if err != nil {
+					return fmt.Errorf("failed to list tags of repository %s: %v", repo.Name, err)
+				}
+			}
+			var artifacts []*model.Artifact
+			for _, tag := range tags {
+				artifacts = append(artifacts, &model.Artifact{
+					Tags: []string{tag},
+				})
+			}
+			artifacts, err := filter.DoFilterArtifacts(artifacts, filters)
+			if err != nil {
+				return err
+			}
+			if len(artifacts) == 0 {
+				return nil
+			}
+			rawResources[index] = &model.Resource{
+				Type:     model.ResourceTypeImage,
+				Registry: a.registry,
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: repo.Name,
+					},
+					Artifacts: artifacts,
+				},
+			}
+			return nil
+		})
+	}
+	if err = runner.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch artifacts: %v", err)
+	}
+
+	var resources []*model.Resource
+	for _, r := range rawResources {
+		if r != nil {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,142 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func mockGitea() *httptest.Server {
+	return test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v1/user/orgs",
+			Handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`[{"id":2,"username":"infra"}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v1/user",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if _, _, ok := r.BasicAuth(); !ok {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{"id":1,"login":"alice"}`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v1/packages/alice",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("type") != "container" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Write([]byte(`[{"name":"app","type":"container","version":"v1"},
+{"name":"app","type":"container","version":"sha256:3b4f2e0b2a1e6d3c1f5e0a4d2c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b"}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/api/v1/packages/infra",
+			Handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`[{"name":"tools/builder","type":"container","version":"v2"},{"name":"tools/builder","type":"container","version":"latest"}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/alice/app/tags/list",
+			Handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`{"name":"alice/app","tags":["v1","v0"]}`))
+			},
+		},
+	)
+}
+
+func TestInfo(t *testing.T) {
+	a := newAdapter(&model.Registry{URL: "https://gitea.example.com/"})
+	assert.Equal(t, "https://gitea.example.com", a.registry.URL)
+	info, err := a.Info()
+	require.Nil(t, err)
+	assert.Equal(t, model.RegistryTypeGitea, info.Type)
+	assert.Equal(t, 2, len(info.SupportedResourceFilters))
+}
+
+func TestHealthCheck(t *testing.T) {
+	server := mockGitea()
+	defer server.Close()
+
+	a := newAdapter(&model.Registry{
+		URL:        server.URL,
+		Credential: &model.Credential{AccessKey: "alice", AccessSecret: "token"},
+	})
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Healthy, status)
+}
+
+func TestFetchArtifacts(t *testing.T) {
+	server := mockGitea()
+	defer server.Close()
+
+	// the credential is required to list the repositories
+	a := newAdapter(&model.Registry{URL: server.URL})
+	_, err := a.FetchArtifacts(nil)
+	assert.NotNil(t, err)
+
+	a = newAdapter(&model.Registry{
+		URL:        server.URL,
+		Credential: &model.Credential{AccessKey: "alice", AccessSecret: "token"},
+	})
+	resources, err := a.FetchArtifacts(nil)
+	require.Nil(t, err)
+	require.Len(t, resources, 2)
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Metadata.Repository.Name < resources[j].Metadata.Repository.Name
+	})
+	assert.Equal(t, "alice/app", resources[0].Metadata.Repository.Name)
+	// the untagged version is skipped
+	assert.Len(t, resources[0].Metadata.Artifacts, 1)
+	assert.Equal(t, "infra/tools/builder", resources[1].Metadata.Repository.Name)
+	assert.Len(t, resources[1].Metadata.Artifacts, 2)
+
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{Type: model.FilterTypeName, Value: "infra/**"},
+		{Type: model.FilterTypeTag, Value: "v*"},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, []string{"v2"}, resources[0].Metadata.Artifacts[0].Tags)
+
+	// the specific repository lists the tags via the registry API
+	a = newAdapter(&model.Registry{URL: server.URL})
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{Type: model.FilterTypeName, Value: "alice/app"},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	assert.Len(t, resources[0].Metadata.Artifacts, 2)
+}
@@ -0,0 +1,114 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/registry/auth/basic"
+)
+
+// pageSize is the size of page used to list the packages
+const pageSize = 50
+
+// user is the user or organization of Gitea
+type user struct {
+	Login    string `json:"login"`
+	UserName string `json:"username"`
+}
+
+// pkg is the package returned by the packages API of Gitea, every version
+// of the package is returned as a separate item
+type pkg struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+// client is a client to interact with the REST API of Gitea
+type client struct {
+	client *common_http.Client
+	url    string
+}
+
+// newClient constructs a Gitea client, the access token can be used as the password
+func newClient(reg *model.Registry) *client {
+	var modifiers []modifier.Modifier
+	if reg.Credential != nil && len(reg.Credential.AccessKey) > 0 {
+		modifiers = append(modifiers, basic.NewAuthorizer(reg.Credential.AccessKey, reg.Credential.AccessSecret))
+	}
+	return &client{
+		client: common_http.NewClient(
+			&http.Client{
+				Transport: common_http.GetHTTPTransport(common_http.WithInsecure(reg.Insecure)),
+			},
+			modifiers...,
+		),
+		url: reg.URL,
+	}
+}
+
+// getCurrentUser gets the user that the credential belongs to
+func (c *client) getCurrentUser() (*user, error) {
+	u := &user{}
+	if err := c.client.Get(fmt.Sprintf("%s/api/v1/user", c.url), u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// listOwners lists the current user and the organizations the user belongs to
+func (c *client) listOwners() ([]string, error) {
+	u, err := c.getCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	owners := []string{u.Login}
+	for page := 1; ; page++ {
+		var orgs []*user
+		if err := c.client.Get(fmt.Sprintf("%s/api/v1/user/orgs?page=%d&limit=%d", c.url, page, pageSize), &orgs); err != nil {
+			return nil, err
+		}
+		for _, org := range orgs {
+			owners = append(owners, org.UserName)
+		}
+		if len(orgs) < pageSize {
+			break
+		}
+	}
+	return owners, nil
+}
+
+// listContainerPackages lists the container packages of the owner
+func (c *client) listContainerPackages(owner string) ([]*pkg, error) {
+	var packages []*pkg
+	for page := 1; ; page++ {
+		var pkgs []*pkg
+		if err := c.client.Get(fmt.Sprintf("%s/api/v1/packages/%s?type=container&page=%d&limit=%d",
+			c.url, url.PathEscape(owner), page, pageSize), &pkgs); err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkgs...)
+		if len(pkgs) < pageSize {
+			break
+		}
+	}
+	return packages, nil
+}
@@ -0,0 +1,392 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/docker/distribution"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/reg/util"
+)
+
+// !!!! Limits:
+// - The docker repositories of Nexus are accessed by the path based routing("<url>/repository/<name>/v2/"),
+//   so the name of the artifact repository must be in format "<nexus repository>/<image>"
+// - The Nexus repositories are NOT created automatically when pushing, they must be created in advance
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeNexus, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeNexus, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeNexus)
+}
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r), nil
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+var (
+	_ adp.Adapter          = (*adapter)(nil)
+	_ adp.ArtifactRegistry = (*adapter)(nil)
+)
+
+// adapter for Sonatype Nexus Repository Manager
+type adapter struct {
+	registry *model.Registry
+	client   *client
+	// the native adapters for the docker repositories of Nexus, keyed by the repository name
+	adapters map[string]*native.Adapter
+	lock     sync.Mutex
+}
+
+func newAdapter(registry *model.Registry) *adapter {
+	registry.URL = strings.TrimSuffix(registry.URL, "/")
+	return &adapter{
+		registry: registry,
+		client:   newClient(registry),
+		adapters: map[string]*native.Adapter{},
+	}
+}
+
+// Info ...
+func (a *adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeNexus,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// HealthCheck checks whether the REST API of Nexus is accessible with the credential
+func (a *adapter) HealthCheck() (string, error) {
+	if _, err := a.client.listDockerRepositories(); err != nil {
+		log.Errorf("failed to list the docker repositories of Nexus %s: %v", a.registry.URL, err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// PrepareForPush checks the existence of the Nexus repositories that the resources will be pushed to
+func (a *adapter) PrepareForPush(resources []*model.Resource) error {
+	repositories, err := a.client.listDockerRepositories()
+	if err != nil {
+		return err
+	}
+	existing := map[string]struct{}{}
+	for _, repository := range repositories {
+		existing[repository.Name] = struct{}{}
+	}
+	for _, resource := range resources {
+		if resource == nil {
+			return errors.New("the resource cannot be null")
+		}
+		if resource.Metadata == nil {
+			return errors.New("the metadata of resource cannot be null")
+		}
+		if resource.Metadata.Repository == nil {
+			return errors.New("the namespace of resource cannot be null")
+		}
+		namespace, _, err := splitRepository(resource.Metadata.Repository.Name)
+		if err != nil {
+			return err
+		}
+		if _, exist := existing[namespace]; !exist {
+			return fmt.Errorf("the docker repository %s doesn't exist in Nexus, create it before pushing", namespace)
+		}
+	}
+	return nil
+}
+
+// FetchArtifacts ...
+func (a *adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	pattern := ""
+	for _, filter := range filters {
+		if filter.Type == model.FilterTypeName {
+			pattern = filter.Value.(string)
+			break
+		}
+	}
+
+	// repository name -> tags, the tags of the specific repositories are listed later
+	tagsOfRepository := map[string][]string{}
+	// if the pattern of repository name filter is a specific repository name, just uses
+	// the parsed repositories and will check the existence later when listing the tags
+	paths, specific := util.IsSpecificPath(pattern)
+	if specific {
+		for _, path := range paths {
+			tagsOfRepository[path] = nil
+		}
+	} else {
+		dockerRepositories, err := a.client.listDockerRepositories()
+		if err != nil {
+			return nil, err
+		}
+		for _, dockerRepository := range dockerRepositories {
+			components, err := a.client.listComponents(dockerRepository.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, component := range components {
+				name := fmt.Sprintf("%s/%s", dockerRepository.Name, component.Name)
+				tagsOfRepository[name] = append(tagsOfRepository[name], component.Version)
+			}
+		}
+	}
+
+	var repositories []*model.Repository
+	for name := range tagsOfRepository {
+		repositories = append(repositories, &model.Repository{Name: name})
+	}
+	repositories, err := filter.DoFilterRepositories(repositories, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawResources = make([]*model.Resource, len(repositories))
+	runner := utils.NewLimitedConcurrentRunner(adp.MaxConcurrency)
+	for i, r := range repositories {
+		index := i
+		repo := r
+		runner.AddTask(func() error {
+			tags := tagsOfRepository[repo.Name]
+			if specific {
+				var err error
+				tags, err = a.ListTags(repo.Name)
+				if err != nil {
+					return fmt.Errorf("failed to list tags of repository %s: %v", repo.Name, err)
+				}
+			}
+			var artifacts []*model.Artifact
+			for _, tag := range tags {
+				artifacts = append(artifacts, &model.Artifact{
+					Tags: []string{tag},
+				})
+			}
+			artifacts, err := filter.DoFilterArtifacts(artifacts, filters)
+			if err != nil {
+				return err
+			}
+			if len(artifacts) == 0 {
+				return nil
+			}
+			rawResources[index] = &model.Resource{
+				Type:     model.ResourceTypeImage,
+				Registry: a.registry,
+				Metadata: &model.ResourceMetadata{
+					Repository: &model.Repository{
+						Name: repo.Name,
+					},
+					Artifacts: artifacts,
+				},
+			}
+			return nil
+		})
+	}
+	if err = runner.Wait(); err != nil {
+		return nil, fmt.Errorf("failed to fetch artifacts: %v", err)
+	}
+
+	var resources []*model.Resource
+	for _, r := range rawResources {
+		if r != nil {
+			resources = append(resources, r)
+		}
+	}
+	return resources, nil
+}
+
+// splitRepository splits the repository into the Nexus repository and the image name
+func splitRepository(repository string) (string, string, error) {
+	parts := strings.SplitN(repository, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("invalid repository %s, the name should be in format \"<nexus repository>/<image>\"", repository)
+	}
+	return parts[0], parts[1], nil
+}
+
+// route returns the native adapter of the Nexus repository that the repository belongs to
+// and the image name inside the Nexus repository
+func (a *adapter) route(repository string) (*native.Adapter, string, error) {
+	namespace, image, err := splitRepository(repository)
+	if err != nil {
+		return nil, "", err
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if adapter, exist := a.adapters[namespace]; exist {
+		return adapter, image, nil
+	}
+	registry := *a.registry
+	registry.URL = fmt.Sprintf("%s/repository/%s", a.registry.URL, namespace)
+	adapter := native.NewAdapter(&registry)
+	a.adapters[namespace] = adapter
+	return adapter, image, nil
+}
+
+// ManifestExist ...
+func (a *adapter) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return false, nil, err
+	}
+	return adapter.ManifestExist(image, reference)
+}
+
+// PullManifest ...
+func (a *adapter) PullManifest(repository, reference string, acceptedMediaTypes ...string) (distribution.Manifest, string, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return nil, "", err
+	}
+	return adapter.PullManifest(image, reference, acceptedMediaTypes...)
+}
+
+// PushManifest ...
+func (a *adapter) PushManifest(repository, reference, mediaType string, payload []byte) (string, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return "", err
+	}
+	return adapter.PushManifest(image, reference, mediaType, payload)
+}
+
+// DeleteManifest ...
+func (a *adapter) DeleteManifest(repository, reference string) error {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return err
+	}
+	return adapter.DeleteManifest(image, reference)
+}
+
+// BlobExist ...
+func (a *adapter) BlobExist(repository, digest string) (bool, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return false, err
+	}
+	return adapter.BlobExist(image, digest)
+}
+
+// PullBlob ...
+func (a *adapter) PullBlob(repository, digest string) (int64, io.ReadCloser, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return 0, nil, err
+	}
+	return adapter.PullBlob(image, digest)
+}
+
+// PullBlobChunk ...
+func (a *adapter) PullBlobChunk(repository, digest string, blobSize, start, end int64) (int64, io.ReadCloser, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return 0, nil, err
+	}
+	return adapter.PullBlobChunk(image, digest, blobSize, start, end)
+}
+
+// PushBlobChunk ...
+func (a *adapter) PushBlobChunk(repository, digest string, size int64, chunk io.Reader, start, end int64, location string) (string, int64, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return "", 0, err
+	}
+	return adapter.PushBlobChunk(image, digest, size, chunk, start, end, location)
+}
+
+// PushBlob ...
+func (a *adapter) PushBlob(repository, digest string, size int64, blob io.Reader) error {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return err
+	}
+	return adapter.PushBlob(image, digest, size, blob)
+}
+
+// MountBlob mounts the blob between the images inside the same Nexus repository
+func (a *adapter) MountBlob(srcRepository, digest, dstRepository string) error {
+	srcNamespace, srcImage, err := splitRepository(srcRepository)
+	if err != nil {
+		return err
+	}
+	adapter, dstImage, err := a.route(dstRepository)
+	if err != nil {
+		return err
+	}
+	if dstNamespace, _, _ := splitRepository(dstRepository); dstNamespace != srcNamespace {
+		return fmt.Errorf("cannot mount the blob %s from %s to %s across the Nexus repositories", digest, srcRepository, dstRepository)
+	}
+	return adapter.MountBlob(srcImage, digest, dstImage)
+}
+
+// CanBeMount isn't supported
+func (a *adapter) CanBeMount(_ string) (bool, string, error) {
+	return false, "", nil
+}
+
+// DeleteTag ...
+func (a *adapter) DeleteTag(repository, tag string) error {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return err
+	}
+	return adapter.DeleteTag(image, tag)
+}
+
+// ListTags ...
+func (a *adapter) ListTags(repository string) ([]string, error) {
+	adapter, image, err := a.route(repository)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.ListTags(image)
+}
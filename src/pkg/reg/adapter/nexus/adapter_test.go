@@ -0,0 +1,152 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/common/utils/test"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func mockNexus() *httptest.Server {
+	return test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/service/rest/v1/repositories",
+			Handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`[{"name":"docker-hosted","format":"docker","type":"hosted"},{"name":"maven-central","format":"maven2","type":"proxy"}]`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/service/rest/v1/search",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("continuationToken") == "" {
+					w.Write([]byte(`{"items":[{"name":"library/nginx","version":"1.25","repository":"docker-hosted","format":"docker"},
+{"name":"library/nginx","version":"latest","repository":"docker-hosted","format":"docker"}],"continuationToken":"token"}`))
+					return
+				}
+				w.Write([]byte(`{"items":[{"name":"busybox","version":"1.36","repository":"docker-hosted","format":"docker"}],"continuationToken":null}`))
+			},
+		},
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/repository/docker-hosted/v2/busybox/tags/list",
+			Handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(`{"name":"busybox","tags":["1.36","latest"]}`))
+			},
+		},
+	)
+}
+
+func TestInfo(t *testing.T) {
+	a := newAdapter(&model.Registry{URL: "https://nexus.example.com/"})
+	assert.Equal(t, "https://nexus.example.com", a.registry.URL)
+	info, err := a.Info()
+	require.Nil(t, err)
+	assert.Equal(t, model.RegistryTypeNexus, info.Type)
+	assert.Equal(t, 2, len(info.SupportedResourceFilters))
+	assert.Equal(t, 2, len(info.SupportedTriggers))
+}
+
+func TestHealthCheck(t *testing.T) {
+	server := mockNexus()
+	defer server.Close()
+	a := newAdapter(&model.Registry{URL: server.URL})
+	status, err := a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Healthy, status)
+
+	a = newAdapter(&model.Registry{URL: server.URL + "/invalid"})
+	status, err = a.HealthCheck()
+	require.Nil(t, err)
+	assert.Equal(t, model.Unhealthy, status)
+}
+
+func TestFetchArtifacts(t *testing.T) {
+	server := mockNexus()
+	defer server.Close()
+	a := newAdapter(&model.Registry{URL: server.URL})
+
+	// list all the repositories via the search API
+	resources, err := a.FetchArtifacts(nil)
+	require.Nil(t, err)
+	require.Len(t, resources, 2)
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Metadata.Repository.Name < resources[j].Metadata.Repository.Name
+	})
+	assert.Equal(t, "docker-hosted/busybox", resources[0].Metadata.Repository.Name)
+	assert.Len(t, resources[0].Metadata.Artifacts, 1)
+	assert.Equal(t, "docker-hosted/library/nginx", resources[1].Metadata.Repository.Name)
+	assert.Len(t, resources[1].Metadata.Artifacts, 2)
+
+	// filter by name and tag
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{Type: model.FilterTypeName, Value: "docker-hosted/library/**"},
+		{Type: model.FilterTypeTag, Value: "1.*"},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "docker-hosted/library/nginx", resources[0].Metadata.Repository.Name)
+	require.Len(t, resources[0].Metadata.Artifacts, 1)
+	assert.Equal(t, []string{"1.25"}, resources[0].Metadata.Artifacts[0].Tags)
+
+	// the specific repository lists the tags via the registry API of the Nexus repository
+	resources, err = a.FetchArtifacts([]*model.Filter{
+		{Type: model.FilterTypeName, Value: "docker-hosted/busybox"},
+	})
+	require.Nil(t, err)
+	require.Len(t, resources, 1)
+	assert.Len(t, resources[0].Metadata.Artifacts, 2)
+}
+
+func TestPrepareForPush(t *testing.T) {
+	server := mockNexus()
+	defer server.Close()
+	a := newAdapter(&model.Registry{URL: server.URL})
+
+	resource := func(name string) *model.Resource {
+		return &model.Resource{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{Name: name},
+			},
+		}
+	}
+	assert.Nil(t, a.PrepareForPush([]*model.Resource{resource("docker-hosted/library/nginx")}))
+	assert.NotNil(t, a.PrepareForPush([]*model.Resource{resource("maven-central/library/nginx")}))
+	assert.NotNil(t, a.PrepareForPush([]*model.Resource{resource("nginx")}))
+}
+
+func TestRoute(t *testing.T) {
+	a := newAdapter(&model.Registry{URL: "https://nexus.example.com"})
+	_, _, err := a.route("nginx")
+	assert.NotNil(t, err)
+
+	adapter, image, err := a.route("docker-hosted/library/nginx")
+	require.Nil(t, err)
+	assert.Equal(t, "library/nginx", image)
+	// the adapter of the same Nexus repository is reused
+	another, _, err := a.route("docker-hosted/busybox")
+	require.Nil(t, err)
+	assert.Same(t, adapter, another)
+	assert.Len(t, a.adapters, 1)
+}
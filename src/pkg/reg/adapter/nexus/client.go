@@ -0,0 +1,110 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nexus
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	common_http "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/common/http/modifier"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+	"github.com/goharbor/harbor/src/pkg/registry/auth/basic"
+)
+
+// repository is the repository defined in Nexus Repository Manager
+type repository struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+}
+
+// component is the component returned by the search API of Nexus
+type component struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Repository string `json:"repository"`
+	Format     string `json:"format"`
+}
+
+type searchResult struct {
+	Items             []*component `json:"items"`
+	ContinuationToken string       `json:"continuationToken"`
+}
+
+// client is a client to interact with the REST API of Nexus
+type client struct {
+	client *common_http.Client
+	url    string
+}
+
+// newClient constructs a Nexus client
+func newClient(reg *model.Registry) *client {
+	var modifiers []modifier.Modifier
+	if reg.Credential != nil && len(reg.Credential.AccessKey) > 0 {
+		modifiers = append(modifiers, basic.NewAuthorizer(reg.Credential.AccessKey, reg.Credential.AccessSecret))
+	}
+	return &client{
+		client: common_http.NewClient(
+			&http.Client{
+				Transport: common_http.GetHTTPTransport(common_http.WithInsecure(reg.Insecure)),
+			},
+			modifiers...,
+		),
+		url: reg.URL,
+	}
+}
+
+// listDockerRepositories lists the repositories whose format is docker
+func (c *client) listDockerRepositories() ([]*repository, error) {
+	var repositories []*repository
+	if err := c.client.Get(fmt.Sprintf("%s/service/rest/v1/repositories", c.url), &repositories); err != nil {
+		return nil, err
+	}
+	var result []*repository
+	for _, repo := range repositories {
+		if repo.Format == "docker" {
+			result = append(result, repo)
+		}
+	}
+	return result, nil
+}
+
+// listComponents lists all the docker components under the specified repository,
+// the pages are iterated by the continuation token
+func (c *client) listComponents(repository string) ([]*component, error) {
+	var components []*component
+	token := ""
+	for {
+		query := url.Values{}
+		query.Set("repository", repository)
+		query.Set("format", "docker")
+		if len(token) > 0 {
+			query.Set("continuationToken", token)
+		}
+		result := &searchResult{}
+		if err := c.client.Get(fmt.Sprintf("%s/service/rest/v1/search?%s", c.url, query.Encode()), result); err != nil {
+			return nil, err
+		}
+		components = append(components, result.Items...)
+		if len(result.ContinuationToken) == 0 {
+			break
+		}
+		token = result.ContinuationToken
+	}
+	return components, nil
+}
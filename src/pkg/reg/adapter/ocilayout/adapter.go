@@ -0,0 +1,589 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ocilayout implements the adapter which reads and writes the artifacts as OCI image layouts
+// on the local filesystem or the S3 compatible storage, it is used to export the artifacts into a bundle
+// and import them into another instance which is disconnected from the network.
+//
+// The URL of the registry is one of:
+//
+//	file:///<path>         the directory under the bundle root directory
+//	file:///<path>.tar     the uncompressed tarball under the bundle root directory, it is read only
+//	s3://<bucket>/<prefix>?region=<region>&endpoint=<endpoint>
+//
+// The bundle root directory is specified by the environment variable "REPLICATION_BUNDLE_DIR", it must be
+// a volume shared by the core and the jobservice as the bundles are exported by the jobservice.
+// For the S3 storage, the access key and secret of the registry credential are used to access the bucket.
+// When the secret of the registry credential is provided, the indexes of the bundle are signed with it
+// on exporting and verified on importing.
+package ocilayout
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/distribution"
+	_ "github.com/docker/distribution/manifest/manifestlist" // register the manifest list unmarshal function
+	_ "github.com/docker/distribution/manifest/ocischema"    // register the oci manifest unmarshal function
+	_ "github.com/docker/distribution/manifest/schema2"      // register the schema2 manifest unmarshal function
+	"github.com/opencontainers/go-digest"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/log"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
+	"github.com/goharbor/harbor/src/pkg/reg/filter"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+const (
+	schemeFile = "file"
+	schemeS3   = "s3"
+
+	bundleDirEnv     = "REPLICATION_BUNDLE_DIR"
+	defaultBundleDir = "/data/bundles"
+)
+
+func init() {
+	if err := adp.RegisterFactory(model.RegistryTypeOCILayout, new(factory)); err != nil {
+		log.Errorf("failed to register factory for %s: %v", model.RegistryTypeOCILayout, err)
+		return
+	}
+	log.Infof("the factory for adapter %s registered", model.RegistryTypeOCILayout)
+}
+
+var (
+	_ adp.Factory      = (*factory)(nil)
+	_ adp.URLValidator = (*factory)(nil)
+)
+
+type factory struct{}
+
+// Create ...
+func (f *factory) Create(r *model.Registry) (adp.Adapter, error) {
+	return newAdapter(r)
+}
+
+// AdapterPattern ...
+func (f *factory) AdapterPattern() *model.AdapterPattern {
+	return nil
+}
+
+// ValidateURL validates the URL of the bundle, the path of the local bundle is cleaned
+// to make sure it is under the bundle root directory
+func (f *factory) ValidateURL(s string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", errors.BadRequestError(err).WithMessagef("invalid URL: %s", err.Error())
+	}
+	p := path.Clean("/" + u.Path)
+	switch u.Scheme {
+	case schemeFile:
+		if len(u.Host) > 0 || p == "/" {
+			return "", errors.BadRequestError(nil).WithMessage("the URL of the local bundle should be file:///<path>")
+		}
+		return fmt.Sprintf("%s://%s", schemeFile, p), nil
+	case schemeS3:
+		if len(u.Host) == 0 {
+			return "", errors.BadRequestError(nil).WithMessage("the URL of the S3 bundle should be s3://<bucket>/<prefix>")
+		}
+		query := url.Values{}
+		if region := u.Query().Get("region"); len(region) > 0 {
+			query.Set("region", region)
+		}
+		if endpoint := u.Query().Get("endpoint"); len(endpoint) > 0 {
+			endpoint, err = lib.ValidateHTTPURL(endpoint)
+			if err != nil {
+				return "", err
+			}
+			query.Set("endpoint", endpoint)
+		}
+		u = &url.URL{Scheme: schemeS3, Host: u.Host, Path: strings.TrimSuffix(p, "/"), RawQuery: query.Encode()}
+		return u.String(), nil
+	default:
+		return "", errors.BadRequestError(nil).WithMessagef("invalid scheme: %s", u.Scheme)
+	}
+}
+
+func bundleDir() string {
+	if dir := os.Getenv(bundleDirEnv); len(dir) > 0 {
+		return dir
+	}
+	return defaultBundleDir
+}
+
+var (
+	_ adp.Adapter          = (*Adapter)(nil)
+	_ adp.ArtifactRegistry = (*Adapter)(nil)
+	_ adp.ReferrerRegistry = (*Adapter)(nil)
+)
+
+// Adapter implements the adapter for the bundles of OCI image layouts
+type Adapter struct {
+	registry *model.Registry
+	bundle   *bundle
+}
+
+func newAdapter(r *model.Registry) (*Adapter, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	var s storage
+	switch u.Scheme {
+	case schemeFile:
+		p := filepath.Join(bundleDir(), filepath.FromSlash(path.Clean("/"+u.Path)))
+		if strings.HasSuffix(p, ".tar") {
+			s = &tarball{file: p}
+		} else {
+			s = &filesystem{root: p}
+		}
+	case schemeS3:
+		s, err = newS3Storage(u, r)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported URL %s for the registry type %s", r.URL, model.RegistryTypeOCILayout)
+	}
+	b := &bundle{
+		name:    r.URL,
+		storage: s,
+	}
+	if r.Credential != nil && len(r.Credential.AccessSecret) > 0 {
+		b.key = []byte(r.Credential.AccessSecret)
+	}
+	return &Adapter{
+		registry: r,
+		bundle:   b,
+	}, nil
+}
+
+// Info returns the basic information about the adapter
+func (a *Adapter) Info() (*model.RegistryInfo, error) {
+	return &model.RegistryInfo{
+		Type: model.RegistryTypeOCILayout,
+		SupportedResourceTypes: []string{
+			model.ResourceTypeImage,
+		},
+		SupportedResourceFilters: []*model.FilterStyle{
+			{
+				Type:  model.FilterTypeName,
+				Style: model.FilterStyleTypeText,
+			},
+			{
+				Type:  model.FilterTypeTag,
+				Style: model.FilterStyleTypeText,
+			},
+		},
+		SupportedTriggers: []string{
+			model.TriggerTypeManual,
+			model.TriggerTypeScheduled,
+		},
+	}, nil
+}
+
+// PrepareForPush creates the OCI image layouts for the repositories
+func (a *Adapter) PrepareForPush(resources []*model.Resource) error {
+	for _, resource := range resources {
+		if resource == nil || resource.Metadata == nil || resource.Metadata.Repository == nil {
+			continue
+		}
+		if err := a.bundle.initRepository(resource.Metadata.Repository.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HealthCheck checks whether the storage of the bundle is accessible
+func (a *Adapter) HealthCheck() (string, error) {
+	if err := a.bundle.storage.Check(); err != nil {
+		log.Errorf("failed to check the bundle %s: %v", a.registry.URL, err)
+		return model.Unhealthy, nil
+	}
+	return model.Healthy, nil
+}
+
+// FetchArtifacts returns the tagged artifacts in the bundle
+func (a *Adapter) FetchArtifacts(filters []*model.Filter) ([]*model.Resource, error) {
+	names, err := a.bundle.repositories()
+	if err != nil {
+		return nil, err
+	}
+	var repositories []*model.Repository
+	for _, name := range names {
+		repositories = append(repositories, &model.Repository{
+			Name: name,
+		})
+	}
+	repositories, err = filter.DoFilterRepositories(repositories, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []*model.Resource
+	for _, repository := range repositories {
+		tags, err := a.ListTags(repository.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts of repository %s: %v", repository.Name, err)
+		}
+		var artifacts []*model.Artifact
+		for _, tag := range tags {
+			artifacts = append(artifacts, &model.Artifact{
+				Tags: []string{tag},
+			})
+		}
+		artifacts, err = filter.DoFilterArtifacts(artifacts, filters)
+		if err != nil {
+			return nil, err
+		}
+		if len(artifacts) == 0 {
+			continue
+		}
+		resources = append(resources, &model.Resource{
+			Type:     model.ResourceTypeImage,
+			Registry: a.registry,
+			Metadata: &model.ResourceMetadata{
+				Repository: repository,
+				Artifacts:  artifacts,
+			},
+		})
+	}
+	return resources, nil
+}
+
+// ManifestExist ...
+func (a *Adapter) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
+	desc, err := a.bundle.resolve(repository, reference)
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	return true, &distribution.Descriptor{
+		MediaType: desc.MediaType,
+		Digest:    desc.Digest,
+		Size:      desc.Size,
+	}, nil
+}
+
+// PullManifest reads the manifest and verifies its digest
+func (a *Adapter) PullManifest(repository, reference string, _ ...string) (distribution.Manifest, string, error) {
+	desc, err := a.bundle.resolve(repository, reference)
+	if err != nil {
+		return nil, "", err
+	}
+	payload, err := a.bundle.readBlob(repository, desc.Digest)
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType := desc.MediaType
+	if len(mediaType) == 0 {
+		mediaType = mediaTypeOf(payload)
+	}
+	manifest, _, err := distribution.UnmarshalManifest(mediaType, payload)
+	if err != nil {
+		return nil, "", err
+	}
+	return manifest, desc.Digest.String(), nil
+}
+
+// PushManifest writes the manifest and records it in the index if it is pushed by tag or it is a referrer
+func (a *Adapter) PushManifest(repository, reference, mediaType string, payload []byte) (string, error) {
+	dgst := digest.FromBytes(payload)
+	byDigest := false
+	if d, err := digest.Parse(reference); err == nil {
+		if d != dgst {
+			return "", errors.BadRequestError(nil).WithMessagef("the digest of the manifest %s mismatches the reference %s", dgst, reference)
+		}
+		byDigest = true
+	}
+	if err := a.PushBlob(repository, dgst.String(), int64(len(payload)), bytes.NewReader(payload)); err != nil {
+		return "", err
+	}
+	// the manifests referenced by the indexes are only stored as blobs
+	if byDigest && subjectOf(payload) == nil {
+		return dgst.String(), nil
+	}
+
+	desc := v1.Descriptor{
+		MediaType: mediaType,
+		Digest:    dgst,
+		Size:      int64(len(payload)),
+	}
+	err := a.bundle.updateIndex(repository, func(index *v1.Index) {
+		var manifests []v1.Descriptor
+		for _, m := range index.Manifests {
+			if byDigest && m.Digest == dgst {
+				// recorded already
+				return
+			}
+			// replace the previous manifest of the tag and the untagged record of the same manifest
+			if !byDigest && (tagOf(m) == reference || m.Digest == dgst && len(tagOf(m)) == 0) {
+				continue
+			}
+			manifests = append(manifests, m)
+		}
+		if !byDigest {
+			desc.Annotations = map[string]string{v1.AnnotationRefName: reference}
+		}
+		index.Manifests = append(manifests, desc)
+	})
+	if err != nil {
+		return "", err
+	}
+	return dgst.String(), nil
+}
+
+// DeleteManifest removes the manifest from the index, the blobs are kept
+func (a *Adapter) DeleteManifest(repository, reference string) error {
+	desc, err := a.bundle.resolve(repository, reference)
+	if err != nil {
+		return err
+	}
+	return a.bundle.updateIndex(repository, func(index *v1.Index) {
+		var manifests []v1.Descriptor
+		for _, m := range index.Manifests {
+			if m.Digest != desc.Digest {
+				manifests = append(manifests, m)
+			}
+		}
+		index.Manifests = manifests
+	})
+}
+
+// BlobExist ...
+func (a *Adapter) BlobExist(repository, dgst string) (bool, error) {
+	p, err := a.blobPath(repository, dgst)
+	if err != nil {
+		return false, err
+	}
+	if _, err = a.bundle.storage.Stat(p); err != nil {
+		if errors.IsNotFoundErr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// PullBlob returns the reader of the blob which verifies the digest when reaching the end
+func (a *Adapter) PullBlob(repository, dgst string) (int64, io.ReadCloser, error) {
+	p, err := a.blobPath(repository, dgst)
+	if err != nil {
+		return 0, nil, err
+	}
+	size, err := a.bundle.storage.Stat(p)
+	if err != nil {
+		return 0, nil, err
+	}
+	reader, err := a.bundle.storage.Reader(p, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	return size, newVerifyReader(reader, digest.Digest(dgst)), nil
+}
+
+// PullBlobChunk ...
+func (a *Adapter) PullBlobChunk(repository, dgst string, _, start, end int64) (int64, io.ReadCloser, error) {
+	p, err := a.blobPath(repository, dgst)
+	if err != nil {
+		return 0, nil, err
+	}
+	reader, err := a.bundle.storage.Reader(p, start)
+	if err != nil {
+		return 0, nil, err
+	}
+	size := end - start + 1
+	return size, &struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.LimitReader(reader, size),
+		Closer: reader,
+	}, nil
+}
+
+// PushBlob writes the blob and removes it if the digest mismatches
+func (a *Adapter) PushBlob(repository, dgst string, _ int64, blob io.Reader) error {
+	p, err := a.blobPath(repository, dgst)
+	if err != nil {
+		return err
+	}
+	exist, err := a.BlobExist(repository, dgst)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return nil
+	}
+	verifier := digest.Digest(dgst).Verifier()
+	if err = a.bundle.storage.Write(p, io.TeeReader(blob, verifier)); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		if err = a.bundle.storage.Delete(p); err != nil {
+			log.Errorf("failed to delete the blob %s@%s: %v", repository, dgst, err)
+		}
+		return errors.BadRequestError(nil).WithMessagef("the digest of blob %s@%s mismatches", repository, dgst)
+	}
+	return nil
+}
+
+// PushBlobChunk uploads the blob by chunks into a temporary file and moves it to the blob path when the last chunk
+// is uploaded and the digest is verified, only the storages implementing the appender support it
+func (a *Adapter) PushBlobChunk(repository, dgst string, size int64, chunk io.Reader, start, end int64, location string) (string, int64, error) {
+	ap, ok := a.bundle.storage.(appender)
+	if !ok {
+		return "", start - 1, errors.New(nil).WithCode(errors.MethodNotAllowedCode).
+			WithMessage("pushing blob by chunks isn't supported by the bundle")
+	}
+	p, err := a.blobPath(repository, dgst)
+	if err != nil {
+		return "", start - 1, err
+	}
+	if len(location) == 0 {
+		location = path.Join(repository, "uploads", utils.GenerateRandomString())
+	}
+	if _, err = ap.Append(location, start, chunk); err != nil {
+		return location, start - 1, err
+	}
+	if end < size-1 {
+		return location, end, nil
+	}
+
+	// the last chunk
+	reader, err := a.bundle.storage.Reader(location, 0)
+	if err != nil {
+		return location, start - 1, err
+	}
+	defer reader.Close()
+	verifier := digest.Digest(dgst).Verifier()
+	if _, err = io.Copy(verifier, reader); err != nil {
+		return location, start - 1, err
+	}
+	if !verifier.Verified() {
+		if err = a.bundle.storage.Delete(location); err != nil {
+			log.Errorf("failed to delete the upload %s: %v", location, err)
+		}
+		return "", -1, errors.BadRequestError(nil).WithMessagef("the digest of blob %s@%s mismatches", repository, dgst)
+	}
+	if err = ap.Move(location, p); err != nil {
+		return location, start - 1, err
+	}
+	return location, end, nil
+}
+
+// MountBlob isn't supported
+func (a *Adapter) MountBlob(_, _, _ string) error {
+	return errors.New(nil).WithCode(errors.MethodNotAllowedCode).WithMessage("mounting blob isn't supported by the bundle")
+}
+
+// CanBeMount isn't supported
+func (a *Adapter) CanBeMount(_ string) (bool, string, error) {
+	return false, "", nil
+}
+
+// DeleteTag removes the tag from the index
+func (a *Adapter) DeleteTag(repository, tag string) error {
+	return a.bundle.updateIndex(repository, func(index *v1.Index) {
+		var manifests []v1.Descriptor
+		for _, m := range index.Manifests {
+			if tagOf(m) != tag {
+				manifests = append(manifests, m)
+			}
+		}
+		index.Manifests = manifests
+	})
+}
+
+// ListTags ...
+func (a *Adapter) ListTags(repository string) ([]string, error) {
+	index, err := a.bundle.readIndex(repository)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, m := range index.Manifests {
+		if tag := tagOf(m); len(tag) > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// ListReferrers returns the referrers recorded in the index, the not found error is returned when there is no
+// referrer to fall back to the tag scheme as the accessories may be recorded with the tags
+func (a *Adapter) ListReferrers(repository, dgst string) ([]v1.Descriptor, error) {
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		return nil, errors.BadRequestError(err).WithMessagef("invalid digest: %s", dgst)
+	}
+	referrers, err := a.bundle.referrers(repository, d)
+	if err != nil {
+		return nil, err
+	}
+	if len(referrers) == 0 {
+		return nil, errors.NotFoundError(nil).WithMessagef("no referrers of %s@%s", repository, dgst)
+	}
+	return referrers, nil
+}
+
+func (a *Adapter) blobPath(repository, dgst string) (string, error) {
+	if err := validateRepository(repository); err != nil {
+		return "", err
+	}
+	d, err := digest.Parse(dgst)
+	if err != nil {
+		return "", errors.BadRequestError(err).WithMessagef("invalid digest: %s", dgst)
+	}
+	return blobPath(repository, d), nil
+}
+
+// verifyReader verifies the digest of the content when reaching the end
+type verifyReader struct {
+	io.ReadCloser
+	digest   digest.Digest
+	verifier digest.Verifier
+}
+
+func newVerifyReader(reader io.ReadCloser, dgst digest.Digest) io.ReadCloser {
+	return &verifyReader{
+		ReadCloser: reader,
+		digest:     dgst,
+		verifier:   dgst.Verifier(),
+	}
+}
+
+func (v *verifyReader) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.verifier.Write(p[:n])
+	if err == io.EOF && !v.verifier.Verified() {
+		return n, errors.New(nil).WithCode(errors.PreconditionCode).
+			WithMessagef("the digest of blob %s mismatches", v.digest)
+	}
+	return n, err
+}
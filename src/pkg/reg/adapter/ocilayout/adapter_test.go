@@ -0,0 +1,342 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+func TestValidateURL(t *testing.T) {
+	f := &factory{}
+	cases := []struct {
+		url      string
+		expected string
+		hasErr   bool
+	}{
+		{url: "file:///export", expected: "file:///export"},
+		{url: "file:///a/../../export/", expected: "file:///export"},
+		{url: "file:///", hasErr: true},
+		{url: "file://host/export", hasErr: true},
+		{url: "s3://bucket/prefix/?region=us-west-1&foo=bar", expected: "s3://bucket/prefix?region=us-west-1"},
+		{url: "s3://bucket?endpoint=minio:9000", expected: "s3://bucket?endpoint=http%3A%2F%2Fminio%3A9000"},
+		{url: "s3:///prefix", hasErr: true},
+		{url: "http://registry", hasErr: true},
+	}
+	for _, c := range cases {
+		url, err := f.ValidateURL(c.url)
+		if c.hasErr {
+			if err == nil {
+				t.Errorf("expected error for %s", c.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", c.url, err)
+			continue
+		}
+		if url != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, url)
+		}
+	}
+}
+
+type adapterTestSuite struct {
+	suite.Suite
+	dir      string
+	adapter  *Adapter
+	config   []byte
+	layer    []byte
+	manifest []byte
+}
+
+func (a *adapterTestSuite) SetupTest() {
+	a.dir = a.T().TempDir()
+	a.T().Setenv(bundleDirEnv, a.dir)
+	adapter, err := newAdapter(&model.Registry{
+		URL: "file:///export",
+		Credential: &model.Credential{
+			AccessSecret: "signing-key",
+		},
+	})
+	a.Require().Nil(err)
+	a.adapter = adapter
+
+	a.config = []byte(`{"architecture":"amd64","os":"linux"}`)
+	a.layer = []byte("layer")
+	a.manifest = a.buildManifest(nil)
+}
+
+func (a *adapterTestSuite) buildManifest(subject *v1.Descriptor) []byte {
+	manifest := &v1.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: v1.MediaTypeImageManifest,
+		Config: v1.Descriptor{
+			MediaType: v1.MediaTypeImageConfig,
+			Digest:    digest.FromBytes(a.config),
+			Size:      int64(len(a.config)),
+		},
+		Layers: []v1.Descriptor{
+			{
+				MediaType: v1.MediaTypeImageLayer,
+				Digest:    digest.FromBytes(a.layer),
+				Size:      int64(len(a.layer)),
+			},
+		},
+		Subject: subject,
+	}
+	data, err := json.Marshal(manifest)
+	a.Require().Nil(err)
+	return data
+}
+
+// export pushes the artifact "library/hello:v1" into the bundle
+func (a *adapterTestSuite) export() {
+	a.Require().Nil(a.adapter.PrepareForPush([]*model.Resource{
+		{
+			Metadata: &model.ResourceMetadata{
+				Repository: &model.Repository{Name: "library/hello"},
+			},
+		},
+	}))
+	for _, blob := range [][]byte{a.config, a.layer} {
+		a.Require().Nil(a.adapter.PushBlob("library/hello", digest.FromBytes(blob).String(), int64(len(blob)), bytes.NewReader(blob)))
+	}
+	dgst, err := a.adapter.PushManifest("library/hello", "v1", v1.MediaTypeImageManifest, a.manifest)
+	a.Require().Nil(err)
+	a.Equal(digest.FromBytes(a.manifest).String(), dgst)
+}
+
+func (a *adapterTestSuite) TestHealthCheck() {
+	status, err := a.adapter.HealthCheck()
+	a.Require().Nil(err)
+	a.Equal(model.Healthy, status)
+
+	adapter, err := newAdapter(&model.Registry{URL: "file:///not/exist"})
+	a.Require().Nil(err)
+	status, err = adapter.HealthCheck()
+	a.Require().Nil(err)
+	a.Equal(model.Unhealthy, status)
+}
+
+func (a *adapterTestSuite) TestExportAndImport() {
+	a.export()
+
+	// the standard OCI image layout is created
+	_, err := os.Stat(filepath.Join(a.dir, "export", "library", "hello", v1.ImageLayoutFile))
+	a.Nil(err)
+
+	resources, err := a.adapter.FetchArtifacts([]*model.Filter{
+		{
+			Type:  model.FilterTypeName,
+			Value: "library/**",
+		},
+		{
+			Type:  model.FilterTypeTag,
+			Value: "v*",
+		},
+	})
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Equal("library/hello", resources[0].Metadata.Repository.Name)
+	a.Require().Len(resources[0].Metadata.Artifacts, 1)
+	a.Equal([]string{"v1"}, resources[0].Metadata.Artifacts[0].Tags)
+
+	exist, desc, err := a.adapter.ManifestExist("library/hello", "v1")
+	a.Require().Nil(err)
+	a.True(exist)
+	a.Equal(digest.FromBytes(a.manifest), desc.Digest)
+	a.Equal(v1.MediaTypeImageManifest, desc.MediaType)
+
+	exist, _, err = a.adapter.ManifestExist("library/hello", "v2")
+	a.Require().Nil(err)
+	a.False(exist)
+
+	manifest, dgst, err := a.adapter.PullManifest("library/hello", "v1")
+	a.Require().Nil(err)
+	a.Equal(digest.FromBytes(a.manifest).String(), dgst)
+	a.Len(manifest.References(), 2)
+
+	size, reader, err := a.adapter.PullBlob("library/hello", digest.FromBytes(a.layer).String())
+	a.Require().Nil(err)
+	defer reader.Close()
+	a.Equal(int64(len(a.layer)), size)
+	data, err := io.ReadAll(reader)
+	a.Require().Nil(err)
+	a.Equal(a.layer, data)
+
+	// delete the tag
+	a.Require().Nil(a.adapter.DeleteTag("library/hello", "v1"))
+	tags, err := a.adapter.ListTags("library/hello")
+	a.Require().Nil(err)
+	a.Empty(tags)
+}
+
+func (a *adapterTestSuite) TestVerification() {
+	a.export()
+
+	// the digest of the pushed blob mismatches
+	err := a.adapter.PushBlob("library/hello", digest.FromString("other").String(), 5, strings.NewReader("layer"))
+	a.NotNil(err)
+	exist, err := a.adapter.BlobExist("library/hello", digest.FromString("other").String())
+	a.Require().Nil(err)
+	a.False(exist)
+
+	// the content of the blob is tampered
+	layerPath := filepath.Join(a.dir, "export", filepath.FromSlash(blobPath("library/hello", digest.FromBytes(a.layer))))
+	a.Require().Nil(os.WriteFile(layerPath, []byte("tampered"), 0644))
+	_, reader, err := a.adapter.PullBlob("library/hello", digest.FromBytes(a.layer).String())
+	a.Require().Nil(err)
+	_, err = io.ReadAll(reader)
+	reader.Close()
+	a.NotNil(err)
+
+	// the signing key mismatches
+	adapter, err := newAdapter(&model.Registry{
+		URL: "file:///export",
+		Credential: &model.Credential{
+			AccessSecret: "other-key",
+		},
+	})
+	a.Require().Nil(err)
+	_, err = adapter.ListTags("library/hello")
+	a.True(errors.IsErr(err, errors.PreconditionCode))
+
+	// the index is tampered
+	indexPath := filepath.Join(a.dir, "export", "library", "hello", v1.ImageIndexFile)
+	index, err := os.ReadFile(indexPath)
+	a.Require().Nil(err)
+	a.Require().Nil(os.WriteFile(indexPath, bytes.ReplaceAll(index, []byte(`"v1"`), []byte(`"v2"`)), 0644))
+	_, err = a.adapter.ListTags("library/hello")
+	a.True(errors.IsErr(err, errors.PreconditionCode))
+}
+
+func (a *adapterTestSuite) TestReferrers() {
+	a.export()
+
+	subject := &v1.Descriptor{
+		MediaType: v1.MediaTypeImageManifest,
+		Digest:    digest.FromBytes(a.manifest),
+		Size:      int64(len(a.manifest)),
+	}
+	referrer := a.buildManifest(subject)
+	_, err := a.adapter.PushManifest("library/hello", digest.FromBytes(referrer).String(), v1.MediaTypeImageManifest, referrer)
+	a.Require().Nil(err)
+
+	referrers, err := a.adapter.ListReferrers("library/hello", subject.Digest.String())
+	a.Require().Nil(err)
+	a.Require().Len(referrers, 1)
+	a.Equal(digest.FromBytes(referrer), referrers[0].Digest)
+	a.Equal(v1.MediaTypeImageConfig, referrers[0].ArtifactType)
+
+	// the referrers aren't listed as tags
+	tags, err := a.adapter.ListTags("library/hello")
+	a.Require().Nil(err)
+	a.Equal([]string{"v1"}, tags)
+
+	// no referrers
+	_, err = a.adapter.ListReferrers("library/hello", digest.FromBytes(referrer).String())
+	a.True(errors.IsNotFoundErr(err))
+}
+
+func (a *adapterTestSuite) TestPushBlobChunk() {
+	blob := []byte("chunked-layer")
+	dgst := digest.FromBytes(blob).String()
+	location, end, err := a.adapter.PushBlobChunk("library/hello", dgst, int64(len(blob)), bytes.NewReader(blob[:5]), 0, 4, "")
+	a.Require().Nil(err)
+	a.Equal(int64(4), end)
+	_, end, err = a.adapter.PushBlobChunk("library/hello", dgst, int64(len(blob)), bytes.NewReader(blob[5:]), 5, int64(len(blob)-1), location)
+	a.Require().Nil(err)
+	a.Equal(int64(len(blob)-1), end)
+
+	exist, err := a.adapter.BlobExist("library/hello", dgst)
+	a.Require().Nil(err)
+	a.True(exist)
+}
+
+func (a *adapterTestSuite) TestImportFromTarball() {
+	a.export()
+
+	// pack the bundle into a tarball
+	root := filepath.Join(a.dir, "export")
+	buf := &bytes.Buffer{}
+	writer := tar.NewWriter(buf)
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err = writer.WriteHeader(&tar.Header{Name: "./" + filepath.ToSlash(rel), Mode: 0644, Size: int64(len(data))}); err != nil {
+			return err
+		}
+		_, err = writer.Write(data)
+		return err
+	})
+	a.Require().Nil(err)
+	a.Require().Nil(writer.Close())
+	a.Require().Nil(os.WriteFile(filepath.Join(a.dir, "export.tar"), buf.Bytes(), 0644))
+
+	adapter, err := newAdapter(&model.Registry{
+		URL: "file:///export.tar",
+		Credential: &model.Credential{
+			AccessSecret: "signing-key",
+		},
+	})
+	a.Require().Nil(err)
+	resources, err := adapter.FetchArtifacts(nil)
+	a.Require().Nil(err)
+	a.Require().Len(resources, 1)
+	a.Equal("library/hello", resources[0].Metadata.Repository.Name)
+
+	_, dgst, err := adapter.PullManifest("library/hello", "v1")
+	a.Require().Nil(err)
+	a.Equal(digest.FromBytes(a.manifest).String(), dgst)
+
+	_, reader, err := adapter.PullBlobChunk("library/hello", digest.FromBytes(a.layer).String(), int64(len(a.layer)), 1, 3)
+	a.Require().Nil(err)
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	a.Require().Nil(err)
+	a.Equal(a.layer[1:4], data)
+
+	// the tarball is read only
+	err = adapter.PushBlob("library/hello", digest.FromString("new").String(), 3, strings.NewReader("new"))
+	a.True(errors.IsErr(err, errors.PreconditionCode))
+}
+
+func TestAdapterTestSuite(t *testing.T) {
+	suite.Run(t, &adapterTestSuite{})
+}
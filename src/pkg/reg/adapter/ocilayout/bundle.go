@@ -0,0 +1,306 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+// the signature of the index file, it is the hex encoded HMAC-SHA256 of the index file
+const signatureFile = v1.ImageIndexFile + ".sig"
+
+// the locks of the index files as they are updated by read-modify-write
+var indexLocks sync.Map
+
+// bundle reads and writes the repositories, each repository is stored as an OCI image layout
+// under the directory named by the repository:
+//
+//	<repository>/oci-layout
+//	<repository>/index.json
+//	<repository>/index.json.sig
+//	<repository>/blobs/<algorithm>/<encoded>
+//
+// The tagged manifests and the referrers are recorded in the index, the tags are recorded
+// with the annotation "org.opencontainers.image.ref.name". The blobs are content addressable
+// and verified when reading, and the index is signed when the signing key is provided
+type bundle struct {
+	// the name identifies the bundle
+	name    string
+	storage storage
+	key     []byte
+}
+
+// referrer contains the fields to resolve the referrers from the manifest
+type referrer struct {
+	MediaType    string            `json:"mediaType"`
+	ArtifactType string            `json:"artifactType"`
+	Config       *v1.Descriptor    `json:"config"`
+	Subject      *v1.Descriptor    `json:"subject"`
+	Annotations  map[string]string `json:"annotations"`
+}
+
+func validateRepository(repository string) error {
+	if len(repository) == 0 || strings.HasPrefix(repository, "/") {
+		return errors.BadRequestError(nil).WithMessagef("invalid repository name: %s", repository)
+	}
+	for _, component := range strings.Split(repository, "/") {
+		if len(component) == 0 || component == "." || component == ".." {
+			return errors.BadRequestError(nil).WithMessagef("invalid repository name: %s", repository)
+		}
+	}
+	return nil
+}
+
+func blobPath(repository string, dgst digest.Digest) string {
+	return path.Join(repository, "blobs", dgst.Algorithm().String(), dgst.Encoded())
+}
+
+func tagOf(desc v1.Descriptor) string {
+	return desc.Annotations[v1.AnnotationRefName]
+}
+
+// repositories returns the repositories that contain the OCI image layout file
+func (b *bundle) repositories() ([]string, error) {
+	paths, err := b.storage.List("")
+	if err != nil {
+		return nil, err
+	}
+	var repositories []string
+	for _, p := range paths {
+		if repository, found := strings.CutSuffix(p, "/"+v1.ImageLayoutFile); found {
+			repositories = append(repositories, repository)
+		}
+	}
+	sort.Strings(repositories)
+	return repositories, nil
+}
+
+// initRepository creates the OCI image layout file of the repository if it doesn't exist
+func (b *bundle) initRepository(repository string) error {
+	if err := validateRepository(repository); err != nil {
+		return err
+	}
+	p := path.Join(repository, v1.ImageLayoutFile)
+	_, err := b.storage.Stat(p)
+	if err == nil || !errors.IsNotFoundErr(err) {
+		return err
+	}
+	data, err := json.Marshal(&v1.ImageLayout{Version: v1.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	return b.storage.Write(p, bytes.NewReader(data))
+}
+
+func (b *bundle) lock(repository string) func() {
+	value, _ := indexLocks.LoadOrStore(b.name+"/"+repository, &sync.Mutex{})
+	mutex := value.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock
+}
+
+func (b *bundle) sign(data []byte) string {
+	mac := hmac.New(sha256.New, b.key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (b *bundle) read(p string) ([]byte, error) {
+	reader, err := b.storage.Reader(p, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// verifyIndex verifies the signature of the index if the signing key is provided
+func (b *bundle) verifyIndex(repository string, data []byte) error {
+	if len(b.key) == 0 {
+		return nil
+	}
+	signature, err := b.read(path.Join(repository, signatureFile))
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return errors.New(nil).WithCode(errors.PreconditionCode).
+				WithMessagef("the index of repository %s isn't signed", repository)
+		}
+		return err
+	}
+	if !hmac.Equal([]byte(b.sign(data)), bytes.TrimSpace(signature)) {
+		return errors.New(nil).WithCode(errors.PreconditionCode).
+			WithMessagef("the signature of the index of repository %s doesn't match", repository)
+	}
+	return nil
+}
+
+// readIndex reads the index of the repository, an empty index is returned if the index doesn't exist
+func (b *bundle) readIndex(repository string) (*v1.Index, error) {
+	if err := validateRepository(repository); err != nil {
+		return nil, err
+	}
+	data, err := b.read(path.Join(repository, v1.ImageIndexFile))
+	if err != nil {
+		if errors.IsNotFoundErr(err) {
+			return &v1.Index{
+				Versioned: specs.Versioned{SchemaVersion: 2},
+				MediaType: v1.MediaTypeImageIndex,
+			}, nil
+		}
+		return nil, err
+	}
+	if err = b.verifyIndex(repository, data); err != nil {
+		return nil, err
+	}
+	index := &v1.Index{}
+	if err = json.Unmarshal(data, index); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the index of repository %s", repository)
+	}
+	return index, nil
+}
+
+// updateIndex updates the index of the repository and signs it if the signing key is provided
+func (b *bundle) updateIndex(repository string, update func(index *v1.Index)) error {
+	unlock := b.lock(repository)
+	defer unlock()
+
+	index, err := b.readIndex(repository)
+	if err != nil {
+		return err
+	}
+	update(index)
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err = b.storage.Write(path.Join(repository, v1.ImageIndexFile), bytes.NewReader(data)); err != nil {
+		return err
+	}
+	if len(b.key) == 0 {
+		return nil
+	}
+	return b.storage.Write(path.Join(repository, signatureFile), strings.NewReader(b.sign(data)))
+}
+
+// resolve returns the descriptor of the manifest referenced by the tag or digest
+func (b *bundle) resolve(repository, reference string) (*v1.Descriptor, error) {
+	index, err := b.readIndex(repository)
+	if err != nil {
+		return nil, err
+	}
+	dgst, err := digest.Parse(reference)
+	isDigest := err == nil
+	for _, desc := range index.Manifests {
+		if isDigest && desc.Digest == dgst || !isDigest && tagOf(desc) == reference {
+			return &desc, nil
+		}
+	}
+	if !isDigest {
+		return nil, errors.NotFoundError(nil).WithMessagef("%s:%s not found", repository, reference)
+	}
+	// the manifests referenced by the indexes aren't recorded in the index of the repository
+	payload, err := b.readBlob(repository, dgst)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.Descriptor{
+		MediaType: mediaTypeOf(payload),
+		Digest:    dgst,
+		Size:      int64(len(payload)),
+	}, nil
+}
+
+// readBlob reads the whole content of the blob and verifies its digest
+func (b *bundle) readBlob(repository string, dgst digest.Digest) ([]byte, error) {
+	if err := validateRepository(repository); err != nil {
+		return nil, err
+	}
+	if err := dgst.Validate(); err != nil {
+		return nil, errors.BadRequestError(err).WithMessagef("invalid digest: %s", dgst)
+	}
+	data, err := b.read(blobPath(repository, dgst))
+	if err != nil {
+		return nil, err
+	}
+	if actual := dgst.Algorithm().FromBytes(data); actual != dgst {
+		return nil, errors.New(nil).WithCode(errors.PreconditionCode).
+			WithMessagef("the digest of blob %s@%s mismatches: %s", repository, dgst, actual)
+	}
+	return data, nil
+}
+
+// referrers returns the referrers of the artifact recorded in the index
+func (b *bundle) referrers(repository string, dgst digest.Digest) ([]v1.Descriptor, error) {
+	index, err := b.readIndex(repository)
+	if err != nil {
+		return nil, err
+	}
+	var referrers []v1.Descriptor
+	for _, desc := range index.Manifests {
+		payload, err := b.readBlob(repository, desc.Digest)
+		if err != nil {
+			return nil, err
+		}
+		r := &referrer{}
+		if err = json.Unmarshal(payload, r); err != nil || r.Subject == nil || r.Subject.Digest != dgst {
+			continue
+		}
+		artifactType := r.ArtifactType
+		if len(artifactType) == 0 && r.Config != nil {
+			artifactType = r.Config.MediaType
+		}
+		referrers = append(referrers, v1.Descriptor{
+			MediaType:    desc.MediaType,
+			ArtifactType: artifactType,
+			Digest:       desc.Digest,
+			Size:         desc.Size,
+			Annotations:  r.Annotations,
+		})
+	}
+	return referrers, nil
+}
+
+// mediaTypeOf returns the media type declared in the manifest
+func mediaTypeOf(payload []byte) string {
+	r := &referrer{}
+	if err := json.Unmarshal(payload, r); err != nil {
+		return ""
+	}
+	return r.MediaType
+}
+
+// subjectOf returns the subject declared in the manifest
+func subjectOf(payload []byte) *v1.Descriptor {
+	r := &referrer{}
+	if err := json.Unmarshal(payload, r); err != nil {
+		return nil
+	}
+	return r.Subject
+}
@@ -0,0 +1,170 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	commonhttp "github.com/goharbor/harbor/src/common/http"
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/reg/model"
+)
+
+const defaultS3Region = "us-east-1"
+
+var _ storage = (*s3Storage)(nil)
+
+// s3Storage stores the bundle under the prefix of the bucket of the S3 compatible storage
+type s3Storage struct {
+	client   *s3.S3
+	uploader *s3manager.Uploader
+	bucket   string
+	prefix   string
+}
+
+// newS3Storage creates the storage by the URL "s3://<bucket>/<prefix>?region=<region>&endpoint=<endpoint>",
+// the endpoint is only needed by the S3 compatible storages other than AWS S3
+func newS3Storage(u *url.URL, reg *model.Registry) (*s3Storage, error) {
+	region := u.Query().Get("region")
+	if len(region) == 0 {
+		region = defaultS3Region
+	}
+	config := &aws.Config{
+		Region: aws.String(region),
+		HTTPClient: &http.Client{
+			Transport: commonhttp.GetHTTPTransport(commonhttp.WithInsecure(reg.Insecure)),
+		},
+	}
+	if endpoint := u.Query().Get("endpoint"); len(endpoint) > 0 {
+		config.Endpoint = aws.String(endpoint)
+		// the S3 compatible storages support the path style in general
+		config.S3ForcePathStyle = aws.Bool(true)
+	}
+	if reg.Credential != nil && len(reg.Credential.AccessKey) > 0 {
+		config.Credentials = credentials.NewStaticCredentials(reg.Credential.AccessKey, reg.Credential.AccessSecret, "")
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Storage{
+		client:   s3.New(sess),
+		uploader: s3manager.NewUploader(sess),
+		bucket:   u.Host,
+		prefix:   strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s *s3Storage) key(p string) string {
+	return strings.TrimPrefix(path.Join(s.prefix, path.Clean("/"+p)), "/")
+}
+
+func (s *s3Storage) convertError(err error, p string) error {
+	if e, ok := err.(awserr.RequestFailure); ok && e.StatusCode() == http.StatusNotFound {
+		return errors.NotFoundError(err).WithMessagef("%s not found", p)
+	}
+	return err
+}
+
+func (s *s3Storage) Check() error {
+	_, err := s.client.HeadBucket(&s3.HeadBucketInput{
+		Bucket: aws.String(s.bucket),
+	})
+	return s.convertError(err, s.bucket)
+}
+
+func (s *s3Storage) Stat(p string) (int64, error) {
+	output, err := s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(p)),
+	})
+	if err != nil {
+		return 0, s.convertError(err, p)
+	}
+	return aws.Int64Value(output.ContentLength), nil
+}
+
+func (s *s3Storage) Reader(p string, offset int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(p)),
+	}
+	if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+	output, err := s.client.GetObject(input)
+	if err != nil {
+		return nil, s.convertError(err, p)
+	}
+	return output.Body, nil
+}
+
+func (s *s3Storage) Write(p string, content io.Reader) error {
+	_, err := s.uploader.Upload(&s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(p)),
+		Body:   content,
+	})
+	return err
+}
+
+func (s *s3Storage) Delete(p string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(p)),
+	})
+	if err = s.convertError(err, p); err != nil && !errors.IsNotFoundErr(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *s3Storage) List(dir string) ([]string, error) {
+	prefix := s.key(dir)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+	// the paths are relative to the root of the bundle
+	root := s.prefix
+	if len(root) > 0 {
+		root += "/"
+	}
+	var paths []string
+	err := s.client.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(output *s3.ListObjectsV2Output, _ bool) bool {
+		for _, object := range output.Contents {
+			paths = append(paths, strings.TrimPrefix(aws.StringValue(object.Key), root))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, s.convertError(err, dir)
+	}
+	return paths, nil
+}
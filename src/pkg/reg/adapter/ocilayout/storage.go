@@ -0,0 +1,197 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+var errReadOnly = errors.New(nil).WithCode(errors.PreconditionCode).WithMessage("the bundle is read only")
+
+// storage abstracts where the bundle is stored, the paths are slash separated and relative to the root of the bundle
+type storage interface {
+	// Check checks whether the storage is accessible
+	Check() error
+	// Stat returns the size of the file, the not found error is returned if the file doesn't exist
+	Stat(path string) (int64, error)
+	// Reader returns the reader of the file content starting from the offset
+	Reader(path string, offset int64) (io.ReadCloser, error)
+	// Write creates or overwrites the file with the content
+	Write(path string, content io.Reader) error
+	// Delete deletes the file, no error is returned if the file doesn't exist
+	Delete(path string) error
+	// List returns the paths of the files under the directory recursively
+	List(dir string) ([]string, error)
+}
+
+// appender is implemented by the storages supporting to upload the file by chunks
+type appender interface {
+	// Append writes the content into the file from the offset and returns the size of the file
+	Append(path string, offset int64, content io.Reader) (int64, error)
+	// Move moves the file to the destination path
+	Move(src, dst string) error
+}
+
+var (
+	_ storage  = (*filesystem)(nil)
+	_ appender = (*filesystem)(nil)
+)
+
+// filesystem stores the bundle as a directory on the local filesystem
+type filesystem struct {
+	root string
+}
+
+func (f *filesystem) fullPath(p string) string {
+	return filepath.Join(f.root, filepath.FromSlash(path.Clean("/"+p)))
+}
+
+// Check checks whether the bundle directory or its parent directory exists
+func (f *filesystem) Check() error {
+	for _, dir := range []string{f.root, filepath.Dir(f.root)} {
+		info, err := os.Stat(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return errors.Errorf("%s isn't a directory", dir)
+		}
+		return nil
+	}
+	return errors.NotFoundError(nil).WithMessagef("the directory %s doesn't exist", f.root)
+}
+
+func (f *filesystem) Stat(p string) (int64, error) {
+	info, err := os.Stat(f.fullPath(p))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, errors.NotFoundError(err).WithMessagef("%s not found", p)
+		}
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+func (f *filesystem) Reader(p string, offset int64) (io.ReadCloser, error) {
+	file, err := os.Open(f.fullPath(p))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NotFoundError(err).WithMessagef("%s not found", p)
+		}
+		return nil, err
+	}
+	if offset > 0 {
+		if _, err = file.Seek(offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+// Write writes the content into a temporary file and renames it to make the file complete when it is visible
+func (f *filesystem) Write(p string, content io.Reader) error {
+	fullPath := f.fullPath(p)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(fullPath), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), fullPath)
+}
+
+func (f *filesystem) Delete(p string) error {
+	if err := os.Remove(f.fullPath(p)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *filesystem) List(dir string) ([]string, error) {
+	root := f.fullPath(dir)
+	var paths []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(f.root, p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (f *filesystem) Append(p string, offset int64, content io.Reader) (int64, error) {
+	fullPath := f.fullPath(p)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return 0, err
+	}
+	file, err := os.OpenFile(fullPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	// drop the content written by the failed uploading
+	if err = file.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(file, content)
+	if err != nil {
+		return 0, err
+	}
+	return offset + n, nil
+}
+
+func (f *filesystem) Move(src, dst string) error {
+	fullPath := f.fullPath(dst)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.Rename(f.fullPath(src), fullPath)
+}
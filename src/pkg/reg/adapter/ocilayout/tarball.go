@@ -0,0 +1,155 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocilayout
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+var _ storage = (*tarball)(nil)
+
+type tarEntry struct {
+	offset int64
+	size   int64
+}
+
+// tarball reads the bundle from an uncompressed tar file, it is read only
+type tarball struct {
+	file    string
+	once    sync.Once
+	err     error
+	entries map[string]*tarEntry
+}
+
+// load scans the tar file once to record the positions of the files
+func (t *tarball) load() error {
+	t.once.Do(func() {
+		t.entries, t.err = scanTar(t.file)
+	})
+	return t.err
+}
+
+func scanTar(file string) (map[string]*tarEntry, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.NotFoundError(err).WithMessagef("the tarball %s not found", file)
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := map[string]*tarEntry{}
+	reader := tar.NewReader(f)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the tarball %s", file)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// the reader is positioned at the beginning of the file content after reading the header
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		entries[cleanTarPath(header.Name)] = &tarEntry{
+			offset: offset,
+			size:   header.Size,
+		}
+	}
+	return entries, nil
+}
+
+func cleanTarPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+func (t *tarball) entry(p string) (*tarEntry, error) {
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+	entry, exist := t.entries[cleanTarPath(p)]
+	if !exist {
+		return nil, errors.NotFoundError(nil).WithMessagef("%s not found", p)
+	}
+	return entry, nil
+}
+
+func (t *tarball) Check() error {
+	return t.load()
+}
+
+func (t *tarball) Stat(p string) (int64, error) {
+	entry, err := t.entry(p)
+	if err != nil {
+		return 0, err
+	}
+	return entry.size, nil
+}
+
+func (t *tarball) Reader(p string, offset int64) (io.ReadCloser, error) {
+	entry, err := t.entry(p)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(t.file)
+	if err != nil {
+		return nil, err
+	}
+	return &struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.NewSectionReader(f, entry.offset+offset, entry.size-offset),
+		Closer: f,
+	}, nil
+}
+
+func (t *tarball) Write(_ string, _ io.Reader) error {
+	return errReadOnly
+}
+
+func (t *tarball) Delete(_ string) error {
+	return errReadOnly
+}
+
+func (t *tarball) List(dir string) ([]string, error) {
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+	prefix := cleanTarPath(dir)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+	var paths []string
+	for p := range t.entries {
+		if strings.HasPrefix(p, prefix) {
+			paths = append(paths, p)
+		}
+	}
+	return paths, nil
+}
@@ -16,6 +16,7 @@ package reg
 
 import (
 	"context"
+	"encoding/json"
 
 	commonthttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/common/utils"
@@ -31,10 +32,14 @@ import (
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/awsecr"
 	// register the AzureAcr adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/azurecr"
+	// register the Cloudsmith adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/cloudsmith"
 	// register the DockerHub adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/dockerhub"
 	// register the DTR adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/dtr"
+	// register the Gitea adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/gitea"
 	// register the Github Container Registry adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/githubcr"
 	// register the GitLab adapter
@@ -49,6 +54,10 @@ import (
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/jfrog"
 	// register the Native adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/native"
+	// register the Sonatype Nexus adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/nexus"
+	// register the OCI image layout adapter
+	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/ocilayout"
 	// register the Quay.io adapter
 	_ "github.com/goharbor/harbor/src/pkg/reg/adapter/quay"
 	// register the TencentCloud TCR adapter
@@ -217,16 +226,23 @@ func encrypt(secret string) (string, error) {
 // Also, if access secret is provided, decrypt it.
 func fromDaoModel(registry *dao.Registry) (*model.Registry, error) {
 	r := &model.Registry{
-		ID:           registry.ID,
-		Name:         registry.Name,
-		Description:  registry.Description,
-		Type:         registry.Type,
-		Credential:   &model.Credential{},
-		URL:          registry.URL,
-		Insecure:     registry.Insecure,
-		Status:       registry.Status,
-		CreationTime: registry.CreationTime,
-		UpdateTime:   registry.UpdateTime,
+		ID:                 registry.ID,
+		Name:               registry.Name,
+		Description:        registry.Description,
+		Type:               registry.Type,
+		Credential:         &model.Credential{},
+		URL:                registry.URL,
+		Insecure:           registry.Insecure,
+		Status:             registry.Status,
+		CreationTime:       registry.CreationTime,
+		UpdateTime:         registry.UpdateTime,
+		Speed:              registry.Speed,
+		MaxConcurrentTasks: registry.MaxConcurrentTasks,
+	}
+	if len(registry.BandwidthWindows) > 0 {
+		if err := json.Unmarshal([]byte(registry.BandwidthWindows), &r.BandwidthWindows); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(registry.AccessKey) != 0 {
@@ -252,15 +268,24 @@ func fromDaoModel(registry *dao.Registry) (*model.Registry, error) {
 // Also, if access secret is provided, encrypt it.
 func toDaoModel(registry *model.Registry) (*dao.Registry, error) {
 	m := &dao.Registry{
-		ID:           registry.ID,
-		URL:          registry.URL,
-		Name:         registry.Name,
-		Type:         string(registry.Type),
-		Insecure:     registry.Insecure,
-		Description:  registry.Description,
-		Status:       registry.Status,
-		CreationTime: registry.CreationTime,
-		UpdateTime:   registry.UpdateTime,
+		ID:                 registry.ID,
+		URL:                registry.URL,
+		Name:               registry.Name,
+		Type:               string(registry.Type),
+		Insecure:           registry.Insecure,
+		Description:        registry.Description,
+		Status:             registry.Status,
+		CreationTime:       registry.CreationTime,
+		UpdateTime:         registry.UpdateTime,
+		Speed:              registry.Speed,
+		MaxConcurrentTasks: registry.MaxConcurrentTasks,
+	}
+	if len(registry.BandwidthWindows) > 0 {
+		windows, err := json.Marshal(registry.BandwidthWindows)
+		if err != nil {
+			return nil, err
+		}
+		m.BandwidthWindows = string(windows)
 	}
 
 	if registry.Credential != nil && len(registry.Credential.AccessKey) != 0 {
@@ -0,0 +1,99 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+)
+
+// the layout of the start and end time of the bandwidth window
+const bandwidthWindowLayout = "15:04"
+
+// BandwidthWindow limits the bandwidth of the replication during a time range of the day.
+// The time is the local time of the jobservice, the window crosses midnight when the end is before the start
+type BandwidthWindow struct {
+	// the start time in "HH:MM" format, inclusive
+	Start string `json:"start"`
+	// the end time in "HH:MM" format, exclusive
+	End string `json:"end"`
+	// the bandwidth in Kbps during the window, 0 means unlimited
+	Speed int32 `json:"speed"`
+}
+
+// Validate the bandwidth window
+func (b *BandwidthWindow) Validate() error {
+	start, err := time.Parse(bandwidthWindowLayout, b.Start)
+	if err != nil {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessagef("invalid start time of the bandwidth window: %s, the format must be HH:MM", b.Start)
+	}
+	end, err := time.Parse(bandwidthWindowLayout, b.End)
+	if err != nil {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessagef("invalid end time of the bandwidth window: %s, the format must be HH:MM", b.End)
+	}
+	if start.Equal(end) {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessagef("the start and end time of the bandwidth window cannot be the same: %s", b.Start)
+	}
+	if b.Speed < 0 {
+		return errors.New(nil).WithCode(errors.BadRequestCode).
+			WithMessagef("the speed of the bandwidth window cannot be negative: %d", b.Speed)
+	}
+	return nil
+}
+
+// Contains returns whether the time is inside the window
+func (b *BandwidthWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(bandwidthWindowLayout, b.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(bandwidthWindowLayout, b.End)
+	if err != nil {
+		return false
+	}
+	minutes := t.Hour()*60 + t.Minute()
+	startMinutes := start.Hour()*60 + start.Minute()
+	endMinutes := end.Hour()*60 + end.Minute()
+	if startMinutes < endMinutes {
+		return minutes >= startMinutes && minutes < endMinutes
+	}
+	// crosses midnight
+	return minutes >= startMinutes || minutes < endMinutes
+}
+
+// ValidateBandwidthWindows validates the bandwidth windows
+func ValidateBandwidthWindows(windows []*BandwidthWindow) error {
+	for _, window := range windows {
+		if err := window.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SpeedAt returns the speed of the first window containing the time, if no window contains the time,
+// the default speed is returned. The speed is in Kbps and 0 means unlimited
+func SpeedAt(windows []*BandwidthWindow, defaultSpeed int32, t time.Time) int32 {
+	for _, window := range windows {
+		if window.Contains(t) {
+			return window.Speed
+		}
+	}
+	return defaultSpeed
+}
@@ -0,0 +1,48 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBandwidthWindows(t *testing.T) {
+	assert.Nil(t, ValidateBandwidthWindows(nil))
+	assert.Nil(t, ValidateBandwidthWindows([]*BandwidthWindow{{Start: "22:00", End: "06:00", Speed: 1024}}))
+	assert.NotNil(t, ValidateBandwidthWindows([]*BandwidthWindow{{Start: "25:00", End: "06:00"}}))
+	assert.NotNil(t, ValidateBandwidthWindows([]*BandwidthWindow{{Start: "22:00", End: "6"}}))
+	assert.NotNil(t, ValidateBandwidthWindows([]*BandwidthWindow{{Start: "22:00", End: "22:00"}}))
+	assert.NotNil(t, ValidateBandwidthWindows([]*BandwidthWindow{{Start: "22:00", End: "06:00", Speed: -1}}))
+}
+
+func TestSpeedAt(t *testing.T) {
+	windows := []*BandwidthWindow{
+		{Start: "09:00", End: "18:00", Speed: 100},
+		{Start: "22:00", End: "02:00", Speed: 0},
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	assert.Equal(t, int32(100), SpeedAt(windows, 500, at(9, 0)))
+	assert.Equal(t, int32(100), SpeedAt(windows, 500, at(17, 59)))
+	assert.Equal(t, int32(500), SpeedAt(windows, 500, at(18, 0)))
+	assert.Equal(t, int32(0), SpeedAt(windows, 500, at(23, 30)))
+	assert.Equal(t, int32(0), SpeedAt(windows, 500, at(1, 0)))
+	assert.Equal(t, int32(500), SpeedAt(windows, 500, at(2, 0)))
+	assert.Equal(t, int32(500), SpeedAt(nil, 500, at(2, 0)))
+}
@@ -35,6 +35,10 @@ const (
 	RegistryTypeTencentTcr       = "tencent-tcr"
 	RegistryTypeGithubCR         = "github-ghcr"
 	RegistryTypeVolcCR           = "volcengine-cr"
+	RegistryTypeOCILayout        = "oci-layout"
+	RegistryTypeNexus            = "nexus"
+	RegistryTypeGitea            = "gitea"
+	RegistryTypeCloudsmith       = "cloudsmith"
 
 	RegistryTypeHelmHub     = "helm-hub"
 	RegistryTypeArtifactHub = "artifact-hub"
@@ -103,6 +107,14 @@ type Registry struct {
 	Status          string      `json:"status"`
 	CreationTime    time.Time   `json:"creation_time"`
 	UpdateTime      time.Time   `json:"update_time"`
+	// Speed is the bandwidth in Kbps shared by all the replication tasks transferring
+	// with the registry, 0 means unlimited
+	Speed int32 `json:"speed"`
+	// BandwidthWindows overrides the Speed during the time ranges of the day
+	BandwidthWindows []*BandwidthWindow `json:"bandwidth_windows"`
+	// MaxConcurrentTasks is the max count of the replication tasks transferring with
+	// the registry at the same time, 0 means unlimited
+	MaxConcurrentTasks int `json:"max_concurrent_tasks"`
 }
 
 // FilterStyle ...
@@ -119,6 +119,9 @@ type Client interface {
 	MountBlob(srcRepository, digest, dstRepository string) (err error)
 	// DeleteBlob deletes the specified blob
 	DeleteBlob(repository, digest string) (err error)
+	// ListReferrers lists the referrers of the manifest specified by the digest with the OCI referrers API,
+	// the not found error is returned if the registry doesn't support the referrers API
+	ListReferrers(repository, digest string) (referrers []v1.Descriptor, err error)
 	// Copy the artifact from source repository to the destination. The "override"
 	// is used to specify whether the destination artifact will be overridden if
 	// its name is same with source but digest isn't
@@ -262,6 +265,52 @@ func (c *client) listTags(url string) ([]string, string, error) {
 	return tgs.Tags, next(resp.Header.Get("Link")), nil
 }
 
+func (c *client) ListReferrers(repository, digest string) ([]v1.Descriptor, error) {
+	var referrers []v1.Descriptor
+	url := buildReferrersURL(c.url, repository, digest)
+	for {
+		refs, next, err := c.listReferrers(url)
+		if err != nil {
+			return nil, err
+		}
+		referrers = append(referrers, refs...)
+
+		url = next
+		// no next page, end the loop
+		if len(url) == 0 {
+			break
+		}
+		// relative URL
+		if !strings.Contains(url, "://") {
+			url = c.url + url
+		}
+	}
+	return referrers, nil
+}
+
+func (c *client) listReferrers(url string) ([]v1.Descriptor, string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", v1.MediaTypeImageIndex)
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	index := &v1.Index{}
+	if err := json.Unmarshal(body, index); err != nil {
+		return nil, "", err
+	}
+	return index.Manifests, next(resp.Header.Get("Link")), nil
+}
+
 func (c *client) ManifestExist(repository, reference string) (bool, *distribution.Descriptor, error) {
 	req, err := http.NewRequest(http.MethodHead, buildManifestURL(c.url, repository, reference), nil)
 	if err != nil {
@@ -713,6 +762,10 @@ func buildManifestURL(endpoint, repository, reference string) string {
 	return fmt.Sprintf("%s/v2/%s/manifests/%s", endpoint, repository, reference)
 }
 
+func buildReferrersURL(endpoint, repository, digest string) string {
+	return fmt.Sprintf("%s/v2/%s/referrers/%s", endpoint, repository, digest)
+}
+
 func buildBlobURL(endpoint, repository, reference string) string {
 	return fmt.Sprintf("%s/v2/%s/blobs/%s", endpoint, repository, reference)
 }
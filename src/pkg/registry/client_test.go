@@ -22,10 +22,12 @@ import (
 	"testing"
 
 	"github.com/docker/distribution/manifest/schema2"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/goharbor/harbor/src/common/utils/test"
 	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/errors"
 )
 
 type clientTestSuite struct {
@@ -149,6 +151,42 @@ func (c *clientTestSuite) TestListTags() {
 	c.EqualValues([]string{"1.0", "2.0"}, repos)
 }
 
+func (c *clientTestSuite) TestListReferrers() {
+	dgt := "sha256:418fb88ec412e340cdbef913b8ca1bbe8f9e8dc705f9617414c1f2c8db980180"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		index := &v1.Index{
+			MediaType: v1.MediaTypeImageIndex,
+			Manifests: []v1.Descriptor{
+				{
+					MediaType:    v1.MediaTypeImageManifest,
+					ArtifactType: "application/vnd.dev.cosign.artifact.sig.v1+json",
+					Digest:       "sha256:0f4e8f1d4f3b1d5b7e0d8e6c1e5a3a1f0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f",
+				},
+			},
+		}
+		encoder := json.NewEncoder(w)
+		err := encoder.Encode(index)
+		c.Require().Nil(err)
+	}
+	server := test.NewServer(
+		&test.RequestHandlerMapping{
+			Method:  http.MethodGet,
+			Pattern: "/v2/library/hello-world/referrers/" + dgt,
+			Handler: handler,
+		})
+	defer server.Close()
+
+	referrers, err := NewClient(server.URL, "", "", true).ListReferrers("library/hello-world", dgt)
+	c.Require().Nil(err)
+	c.Require().Len(referrers, 1)
+	c.Equal("application/vnd.dev.cosign.artifact.sig.v1+json", referrers[0].ArtifactType)
+
+	// the referrers API isn't supported
+	_, err = NewClient(server.URL, "", "", true).ListReferrers("library/alpine", dgt)
+	c.Require().NotNil(err)
+	c.True(errors.IsNotFoundErr(err))
+}
+
 func (c *clientTestSuite) TestManifestExist() {
 	server := test.NewServer(
 		&test.RequestHandlerMapping{
@@ -43,6 +43,9 @@ type Policy struct {
 	UpdateTime                time.Time `orm:"column(update_time);auto_now"`
 	Speed                     int32     `orm:"column(speed_kb)"`
 	CopyByChunk               bool      `orm:"column(copy_by_chunk)"`
+	RequireAccessories        bool      `orm:"column(require_accessories)"`
+	Reconcile                 bool      `orm:"column(reconcile)"`
+	BandwidthWindows          string    `orm:"column(bandwidth_windows)"`
 }
 
 // TableName set table name for ORM
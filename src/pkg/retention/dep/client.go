@@ -18,12 +18,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/goharbor/harbor/src/common/http/modifier/auth"
+	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/jobservice/config"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/selector"
+	accessoryModel "github.com/goharbor/harbor/src/pkg/accessory/model"
 	"github.com/goharbor/harbor/src/pkg/clients/core"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 )
 
 // DefaultClient for the retention
@@ -35,11 +39,12 @@ type Client interface {
 	//
 	//  Arguments:
 	//    repo *art.Repository : repository info
+	//    option *core.ListArtifactsOption : the extra information of the candidates required by the rules
 	//
 	//  Returns:
 	//    []*art.Candidate : candidates returned
 	//    error            : common error if any errors occurred
-	GetCandidates(repo *selector.Repository) ([]*selector.Candidate, error)
+	GetCandidates(repo *selector.Repository, option *core.ListArtifactsOption) ([]*selector.Candidate, error)
 
 	// Delete the given repository
 	//
@@ -58,6 +63,16 @@ type Client interface {
 	//  Returns:
 	//    error : common error if any errors occurred
 	Delete(candidate *selector.Candidate) error
+
+	// Archive the specified candidate by copying it to the archive project and deleting it then
+	//
+	//  Arguments:
+	//    candidate *art.Candidate : the archiving candidate
+	//    project string           : the name of the archive project
+	//
+	//  Returns:
+	//    error : common error if any errors occurred
+	Archive(candidate *selector.Candidate, project string) error
 }
 
 type injectVendorType struct{}
@@ -100,51 +115,17 @@ type basicClient struct {
 }
 
 // GetCandidates gets the tag candidates under the repository
-func (bc *basicClient) GetCandidates(repository *selector.Repository) ([]*selector.Candidate, error) {
+func (bc *basicClient) GetCandidates(repository *selector.Repository, option *core.ListArtifactsOption) ([]*selector.Candidate, error) {
 	if repository == nil {
 		return nil, errors.New("repository is nil")
 	}
-	candidates := make([]*selector.Candidate, 0)
 	switch repository.Kind {
 	case selector.Image:
-		artifacts, err := bc.coreClient.ListAllArtifacts(repository.Namespace, repository.Name)
+		artifacts, err := bc.coreClient.ListAllArtifacts(repository.Namespace, repository.Name, option)
 		if err != nil {
 			return nil, err
 		}
-		for _, art := range artifacts {
-			if art.Digest == "" {
-				return nil, fmt.Errorf("lack digest of candidate for %s/%s", repository.Namespace, repository.Name)
-			}
-			labels := make([]string, 0)
-			for _, label := range art.Labels {
-				labels = append(labels, label.Name)
-			}
-			tags := make([]string, 0)
-			lastPulledTime := art.PullTime
-			lastPushedTime := art.PushTime
-			for _, t := range art.Tags {
-				tags = append(tags, t.Name)
-				if t.PullTime.After(lastPulledTime) {
-					lastPulledTime = t.PullTime
-				}
-				if t.PushTime.After(lastPushedTime) {
-					lastPushedTime = t.PushTime
-				}
-			}
-			candidate := &selector.Candidate{
-				Kind:         selector.Image,
-				NamespaceID:  repository.NamespaceID,
-				Namespace:    repository.Namespace,
-				Repository:   repository.Name,
-				Tags:         tags,
-				Digest:       art.Digest,
-				Labels:       labels,
-				CreationTime: art.PushTime.Unix(),
-				PulledTime:   lastPulledTime.Unix(),
-				PushedTime:   lastPushedTime.Unix(),
-			}
-			candidates = append(candidates, candidate)
-		}
+		return ToCandidates(repository, artifacts)
 	/*
 		case art.Chart:
 			charts, err := bc.coreClient.ListAllCharts(repository.Namespace, repository.Name)
@@ -172,9 +153,140 @@ func (bc *basicClient) GetCandidates(repository *selector.Repository) ([]*select
 	default:
 		return nil, fmt.Errorf("unsupported repository kind: %s", repository.Kind)
 	}
+}
+
+// ToCandidates converts the artifacts of the repository to the retention candidates, the
+// artifacts may be listed with all the levels when the rules require the child artifacts
+func ToCandidates(repository *selector.Repository, artifacts []*modelsv2.Artifact) ([]*selector.Candidate, error) {
+	candidates := make([]*selector.Candidate, 0)
+	// collect the children to find out the ones only referenced by the indexes
+	all := make(map[string]*modelsv2.Artifact, len(artifacts))
+	referenced := make(map[string]bool)
+	for _, art := range artifacts {
+		if art.Digest == "" {
+			return nil, fmt.Errorf("lack digest of candidate for %s/%s", repository.Namespace, repository.Name)
+		}
+		all[art.Digest] = art
+		for _, ref := range art.References {
+			referenced[ref.ChildDigest] = true
+		}
+	}
+	for _, art := range artifacts {
+		// keep the same candidates as the default artifact list: the tagged artifacts
+		// and the untagged artifacts that aren't referenced by others
+		if len(art.Tags) == 0 && referenced[art.Digest] {
+			continue
+		}
+		labels := make([]string, 0)
+		for _, label := range art.Labels {
+			labels = append(labels, label.Name)
+		}
+		tags := make([]string, 0)
+		lastPulledTime := art.PullTime
+		lastPushedTime := art.PushTime
+		for _, t := range art.Tags {
+			tags = append(tags, t.Name)
+			if t.PullTime.After(lastPulledTime) {
+				lastPulledTime = t.PullTime
+			}
+			if t.PushTime.After(lastPushedTime) {
+				lastPushedTime = t.PushTime
+			}
+		}
+		references := make([]string, 0, len(art.References))
+		for _, ref := range art.References {
+			references = append(references, ref.ChildDigest)
+		}
+		signed := isSigned(art)
+		signatures := make(map[string]bool, len(tags))
+		for _, t := range tags {
+			signatures[t] = signed
+		}
+		scanStatus, severity := scanSummary(art)
+		candidate := &selector.Candidate{
+			Kind:                  selector.Image,
+			NamespaceID:           repository.NamespaceID,
+			Namespace:             repository.Namespace,
+			Repository:            repository.Name,
+			Tags:                  tags,
+			Digest:                art.Digest,
+			Labels:                labels,
+			CreationTime:          art.PushTime.Unix(),
+			PulledTime:            lastPulledTime.Unix(),
+			PushedTime:            lastPushedTime.Unix(),
+			Size:                  art.Size,
+			References:            references,
+			Referenced:            referenced[art.Digest],
+			ReferencePulledTime:   referencePulledTime(art, all, map[string]bool{}).Unix(),
+			Signatures:            signatures,
+			ScanStatus:            scanStatus,
+			VulnerabilitySeverity: severity,
+		}
+		candidates = append(candidates, candidate)
+	}
 	return candidates, nil
 }
 
+// isSigned checks whether the artifact has the signature accessories
+func isSigned(art *modelsv2.Artifact) bool {
+	for _, acc := range art.Accessories {
+		switch acc.GetData().Type {
+		case accessoryModel.TypeCosignSignature, accessoryModel.TypeNotationSignature:
+			return true
+		}
+	}
+	return false
+}
+
+// scanSummary returns the status and the severity code of the vulnerability scan overview,
+// the highest severity is returned if the artifact is scanned by several scanners
+func scanSummary(art *modelsv2.Artifact) (string, uint) {
+	var (
+		status   string
+		severity uint
+	)
+	for _, v := range art.ScanOverview {
+		summary, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		s, _ := summary["scan_status"].(string)
+		if len(status) == 0 || s == job.SuccessStatus.String() {
+			status = s
+		}
+		if s != job.SuccessStatus.String() {
+			continue
+		}
+		sev, _ := summary["severity"].(string)
+		if code := uint(vuln.Severity(sev).Code()); code > severity {
+			severity = code
+		}
+	}
+	return status, severity
+}
+
+// referencePulledTime returns the latest pulled time of the artifacts referenced by the given one recursively
+func referencePulledTime(art *modelsv2.Artifact, all map[string]*modelsv2.Artifact, visited map[string]bool) time.Time {
+	var latest time.Time
+	for _, ref := range art.References {
+		if visited[ref.ChildDigest] {
+			continue
+		}
+		visited[ref.ChildDigest] = true
+		child, ok := all[ref.ChildDigest]
+		if !ok {
+			continue
+		}
+		if child.PullTime.After(latest) {
+			latest = child.PullTime
+		}
+		if t := referencePulledTime(child, all, visited); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
 // DeleteRepository deletes the specified repository
 func (bc *basicClient) DeleteRepository(repo *selector.Repository) error {
 	if repo == nil {
@@ -208,3 +320,20 @@ func (bc *basicClient) Delete(candidate *selector.Candidate) error {
 		return fmt.Errorf("unsupported candidate kind: %s", candidate.Kind)
 	}
 }
+
+// Archive the specified candidate
+func (bc *basicClient) Archive(candidate *selector.Candidate, project string) error {
+	if candidate == nil {
+		return errors.New("candidate is nil")
+	}
+	switch candidate.Kind {
+	case selector.Image:
+		from := fmt.Sprintf("%s/%s@%s", candidate.Namespace, candidate.Repository, candidate.Digest)
+		if err := bc.coreClient.CopyArtifact(project, candidate.Repository, from); err != nil {
+			return err
+		}
+		return bc.coreClient.DeleteArtifact(candidate.Namespace, candidate.Repository, candidate.Digest)
+	default:
+		return fmt.Errorf("unsupported candidate kind: %s", candidate.Kind)
+	}
+}
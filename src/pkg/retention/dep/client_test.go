@@ -17,12 +17,15 @@ package dep
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	jmodels "github.com/goharbor/harbor/src/common/job/models"
 	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
 	"github.com/goharbor/harbor/src/controller/tag"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/artifact"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 	model_tag "github.com/goharbor/harbor/src/pkg/tag/model/tag"
 	"github.com/goharbor/harbor/src/testing/clients"
 	"github.com/stretchr/testify/assert"
@@ -34,7 +37,7 @@ type fakeCoreClient struct {
 	clients.DumbCoreClient
 }
 
-func (f *fakeCoreClient) ListAllArtifacts(project, repository string) ([]*modelsv2.Artifact, error) {
+func (f *fakeCoreClient) ListAllArtifacts(project, repository string, option *core.ListArtifactsOption) ([]*modelsv2.Artifact, error) {
 	image := &modelsv2.Artifact{}
 	image.Digest = "sha256:123456"
 	image.Tags = []*tag.Tag{
@@ -47,6 +50,34 @@ func (f *fakeCoreClient) ListAllArtifacts(project, repository string) ([]*models
 	return []*modelsv2.Artifact{image}, nil
 }
 
+type fakeIndexCoreClient struct {
+	clients.DumbCoreClient
+}
+
+func (f *fakeIndexCoreClient) ListAllArtifacts(project, repository string, option *core.ListArtifactsOption) ([]*modelsv2.Artifact, error) {
+	now := time.Now()
+	index := &modelsv2.Artifact{}
+	index.Digest = "sha256:index"
+	index.Size = 100
+	index.Tags = []*tag.Tag{{Tag: model_tag.Tag{Name: "latest"}}}
+	index.References = []*artifact.Reference{{ChildDigest: "sha256:amd64"}, {ChildDigest: "sha256:arm64"}}
+	index.ScanOverview = map[string]interface{}{
+		"application/vnd.security.vulnerability.report; version=1.1": map[string]interface{}{
+			"scan_status": "Success",
+			"severity":    "High",
+		},
+	}
+	// untagged child
+	amd64 := &modelsv2.Artifact{}
+	amd64.Digest = "sha256:amd64"
+	amd64.PullTime = now
+	// tagged child
+	arm64 := &modelsv2.Artifact{}
+	arm64.Digest = "sha256:arm64"
+	arm64.Tags = []*tag.Tag{{Tag: model_tag.Tag{Name: "arm64"}}}
+	return []*modelsv2.Artifact{index, amd64, arm64}, nil
+}
+
 type fakeJobserviceClient struct{}
 
 func (f *fakeJobserviceClient) SubmitJob(*jmodels.JobData) (string, error) {
@@ -71,7 +102,7 @@ func (c *clientTestSuite) TestGetCandidates() {
 	client.coreClient = &fakeCoreClient{}
 	var repository *selector.Repository
 	// nil repository
-	candidates, err := client.GetCandidates(repository)
+	candidates, err := client.GetCandidates(repository, nil)
 	require.NotNil(c.T(), err)
 
 	// image repository
@@ -79,7 +110,7 @@ func (c *clientTestSuite) TestGetCandidates() {
 	repository.Kind = selector.Image
 	repository.Namespace = "library"
 	repository.Name = "hello-world"
-	candidates, err = client.GetCandidates(repository)
+	candidates, err = client.GetCandidates(repository, nil)
 	require.Nil(c.T(), err)
 	assert.Equal(c.T(), 1, len(candidates))
 	assert.Equal(c.T(), selector.Image, candidates[0].Kind)
@@ -92,7 +123,7 @@ func (c *clientTestSuite) TestGetCandidates() {
 		repository.Kind = art.Chart
 		repository.Namespace = "goharbor"
 		repository.Name = "harbor"
-		candidates, err = client.GetCandidates(repository)
+		candidates, err = client.GetCandidates(repository, nil)
 		require.Nil(c.T(), err)
 		assert.Equal(c.T(), 1, len(candidates))
 		assert.Equal(c.T(), art.Chart, candidates[0].Kind)
@@ -101,6 +132,39 @@ func (c *clientTestSuite) TestGetCandidates() {
 	*/
 }
 
+func (c *clientTestSuite) TestGetCandidatesWithReferences() {
+	client := &basicClient{}
+	client.coreClient = &fakeIndexCoreClient{}
+	repository := &selector.Repository{
+		Kind:      selector.Image,
+		Namespace: "library",
+		Name:      "hello-world",
+	}
+	candidates, err := client.GetCandidates(repository, &core.ListArtifactsOption{
+		WithChildren:     true,
+		WithAccessory:    true,
+		WithScanOverview: true,
+	})
+	require.Nil(c.T(), err)
+	// the untagged child is excluded
+	require.Equal(c.T(), 2, len(candidates))
+
+	index := candidates[0]
+	assert.Equal(c.T(), "sha256:index", index.Digest)
+	assert.Equal(c.T(), int64(100), index.Size)
+	assert.Equal(c.T(), []string{"sha256:amd64", "sha256:arm64"}, index.References)
+	assert.False(c.T(), index.Referenced)
+	assert.True(c.T(), index.ReferencePulledTime > index.PulledTime)
+	assert.Equal(c.T(), "Success", index.ScanStatus)
+	assert.Equal(c.T(), uint(4), index.VulnerabilitySeverity)
+	assert.Equal(c.T(), map[string]bool{"latest": false}, index.Signatures)
+
+	child := candidates[1]
+	assert.Equal(c.T(), "sha256:arm64", child.Digest)
+	assert.True(c.T(), child.Referenced)
+	assert.Equal(c.T(), "", child.ScanStatus)
+}
+
 func (c *clientTestSuite) TestDelete() {
 	client := &basicClient{}
 	client.coreClient = &fakeCoreClient{}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,16 +31,25 @@ import (
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/retry"
 	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/lib/selector/selectors/severity"
+	"github.com/goharbor/harbor/src/lib/selector/selectors/signature"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 	"github.com/goharbor/harbor/src/pkg/retention/dep"
 	"github.com/goharbor/harbor/src/pkg/retention/policy"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/lwp"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/daysplany"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestpsref"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/scanpassed"
 )
 
 const (
 	actionMarkRetain    = "RETAIN"
 	actionMarkDeletion  = "DEL"
+	actionMarkArchive   = "ARCHIVE"
 	actionMarkError     = "ERR"
 	actionMarkImmutable = "IMMUTABLE"
+	actionMarkPinned    = "PINNED"
 )
 
 // Job of running retention process
@@ -92,7 +102,7 @@ func (pj *Job) Run(ctx job.Context, params job.Parameters) error {
 	}
 
 	// Retrieve all the candidates under the specified repository
-	allCandidates, err := dep.DefaultClient.GetCandidates(repo)
+	allCandidates, err := dep.DefaultClient.GetCandidates(repo, listOption(liteMeta))
 	if err != nil {
 		return logError(myLogger, err)
 	}
@@ -134,6 +144,32 @@ func (pj *Job) Run(ctx job.Context, params job.Parameters) error {
 	return saveRetainNum(ctx, results, allCandidates, isDryRun)
 }
 
+// listOption returns the option to list the artifacts with the extra information required by the rules,
+// the child artifacts, accessories and scan overviews are expensive to load for the large repositories
+func listOption(meta *lwp.Metadata) *core.ListArtifactsOption {
+	option := &core.ListArtifactsOption{}
+	for _, r := range meta.Rules {
+		switch r.Template {
+		case daysplany.TemplateID:
+			option.WithChildren = true
+		case latestpsref.TemplateID:
+			option.WithChildren = true
+			option.WithAccessory = true
+		case scanpassed.TemplateID:
+			option.WithScanOverview = true
+		}
+		for _, s := range r.TagSelectors {
+			switch s.Kind {
+			case signature.Kind:
+				option.WithAccessory = true
+			case severity.Kind:
+				option.WithScanOverview = true
+			}
+		}
+	}
+	return option
+}
+
 func saveRetainNum(ctx job.Context, results []*selector.Result, allCandidates []*selector.Candidate, isDryRun bool) error {
 	var realDelete []*selector.Result
 	for _, r := range results {
@@ -161,28 +197,47 @@ func saveRetainNum(ctx job.Context, results []*selector.Result, allCandidates []
 }
 
 func logResults(logger logger.Interface, all []*selector.Candidate, results []*selector.Result) {
-	hash := make(map[string]error, len(results))
+	hash := make(map[string]*selector.Result, len(results))
 	for _, r := range results {
 		if r.Target != nil {
-			hash[r.Target.Hash()] = r.Error
+			hash[r.Target.Hash()] = r
 		}
 	}
 
 	op := func(c *selector.Candidate) string {
-		if e, exists := hash[c.Hash()]; exists {
-			if e != nil {
-				if _, ok := e.(*selector.ImmutableError); ok {
+		if r, exists := hash[c.Hash()]; exists {
+			if r.Error != nil {
+				if _, ok := r.Error.(*selector.ImmutableError); ok {
 					return actionMarkImmutable
 				}
+				if _, ok := r.Error.(*selector.PinnedError); ok {
+					return actionMarkPinned
+				}
 				return actionMarkError
 			}
 
+			if r.Action == action.Archive {
+				return actionMarkArchive
+			}
 			return actionMarkDeletion
 		}
 
 		return actionMarkRetain
 	}
 
+	// the rules selecting the candidate
+	rules := func(c *selector.Candidate) string {
+		if r, exists := hash[c.Hash()]; exists {
+			ids := make([]string, 0, len(r.Rules))
+			for _, id := range r.Rules {
+				ids = append(ids, strconv.Itoa(id))
+			}
+			return strings.Join(ids, ",")
+		}
+
+		return ""
+	}
+
 	var buf bytes.Buffer
 
 	data := make([][]string, 0, len(all))
@@ -197,13 +252,14 @@ func logResults(logger logger.Interface, all []*selector.Candidate, results []*s
 			t(c.PulledTime),
 			t(c.CreationTime),
 			op(c),
+			rules(c),
 		}
 		data = append(data, row)
 	}
 
 	table := tablewriter.NewWriter(&buf)
 	table.SetAutoFormatHeaders(false)
-	table.SetHeader([]string{"Digest", "Tag", "Kind", "Labels", "PushedTime", "PulledTime", "CreatedTime", "Retention"})
+	table.SetHeader([]string{"Digest", "Tag", "Kind", "Labels", "PushedTime", "PulledTime", "CreatedTime", "Retention", "Rules"})
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
 	table.AppendBulk(data)
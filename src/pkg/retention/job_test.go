@@ -27,12 +27,16 @@ import (
 	"github.com/goharbor/harbor/src/jobservice/logger"
 	"github.com/goharbor/harbor/src/lib/selector"
 	"github.com/goharbor/harbor/src/lib/selector/selectors/doublestar"
+	"github.com/goharbor/harbor/src/lib/selector/selectors/severity"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 	"github.com/goharbor/harbor/src/pkg/retention/dep"
 	"github.com/goharbor/harbor/src/pkg/retention/policy"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/lwp"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/daysplany"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestps"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestpsref"
 )
 
 // JobTestSuite is test suite for testing job
@@ -110,10 +114,48 @@ func (suite *JobTestSuite) TestRunSuccess() {
 	require.NoError(suite.T(), err)
 }
 
+func (suite *JobTestSuite) TestListOption() {
+	meta := &lwp.Metadata{
+		Rules: []*rule.Metadata{
+			{
+				Template: latestps.TemplateID,
+				TagSelectors: []*rule.Selector{{
+					Kind:       doublestar.Kind,
+					Decoration: doublestar.Matches,
+					Pattern:    "**",
+				}},
+			},
+		},
+	}
+	suite.Equal(&core.ListArtifactsOption{}, listOption(meta))
+
+	meta.Rules = append(meta.Rules, &rule.Metadata{
+		Template: daysplany.TemplateID,
+		TagSelectors: []*rule.Selector{{
+			Kind:       severity.Kind,
+			Decoration: severity.Lt,
+			Pattern:    "3",
+		}},
+	})
+	suite.Equal(&core.ListArtifactsOption{
+		WithChildren:     true,
+		WithScanOverview: true,
+	}, listOption(meta))
+
+	meta.Rules = append(meta.Rules, &rule.Metadata{
+		Template: latestpsref.TemplateID,
+	})
+	suite.Equal(&core.ListArtifactsOption{
+		WithChildren:     true,
+		WithAccessory:    true,
+		WithScanOverview: true,
+	}, listOption(meta))
+}
+
 type fakeRetentionClient struct{}
 
 // GetCandidates ...
-func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository) ([]*selector.Candidate, error) {
+func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository, option *core.ListArtifactsOption) ([]*selector.Candidate, error) {
 	return []*selector.Candidate{
 		{
 			Namespace:    "library",
@@ -145,6 +187,11 @@ func (frc *fakeRetentionClient) Delete(candidate *selector.Candidate) error {
 	return nil
 }
 
+// Archive ...
+func (frc *fakeRetentionClient) Archive(candidate *selector.Candidate, project string) error {
+	return nil
+}
+
 // SubmitTask ...
 func (frc *fakeRetentionClient) DeleteRepository(repo *selector.Repository) error {
 	return nil
@@ -104,85 +104,9 @@ func (l *launcher) Launch(ctx context.Context, ply *policy.Metadata, executionID
 		log.Debugf("no rules for policy %d, skip", ply.ID)
 		return 0, nil
 	}
-	scope := ply.Scope
-	if scope == nil {
-		return 0, launcherError(fmt.Errorf("the scope of policy is nil"))
-	}
-	repositoryRules := make(map[selector.Repository]*lwp.Metadata, 0)
-	level := scope.Level
-	var allProjects []*selector.Candidate
-	var err error
-	if level == "system" {
-		// get projects
-		allProjects, err = getProjects(ctx, l.projectMgr)
-		if err != nil {
-			return 0, launcherError(err)
-		}
-	}
-
-	for _, rule := range ply.Rules {
-		if rule.Disabled {
-			log.Infof("Policy %d rule %d %s is deactivated", ply.ID, rule.ID, rule.Template)
-			continue
-		}
-		projectCandidates := allProjects
-		switch level {
-		case "system":
-			// filter projects according to the project selectors
-			for _, projectSelector := range rule.ScopeSelectors["project"] {
-				selector, err := index.Get(projectSelector.Kind, projectSelector.Decoration,
-					projectSelector.Pattern, "")
-				if err != nil {
-					return 0, launcherError(err)
-				}
-				projectCandidates, err = selector.Select(projectCandidates)
-				if err != nil {
-					return 0, launcherError(err)
-				}
-			}
-		case "project":
-			projectCandidates = append(projectCandidates, &selector.Candidate{
-				NamespaceID: scope.Reference,
-			})
-		}
-
-		var repositoryCandidates []*selector.Candidate
-		// get repositories of projects
-		for _, projectCandidate := range projectCandidates {
-			repositories, err := getRepositories(ctx, l.projectMgr, l.repositoryMgr, projectCandidate.NamespaceID)
-			if err != nil {
-				return 0, launcherError(err)
-			}
-			repositoryCandidates = append(repositoryCandidates, repositories...)
-		}
-		// filter repositories according to the repository selectors
-		for _, repositorySelector := range rule.ScopeSelectors["repository"] {
-			selector, err := index.Get(repositorySelector.Kind, repositorySelector.Decoration,
-				repositorySelector.Pattern, repositorySelector.Extras)
-			if err != nil {
-				return 0, launcherError(err)
-			}
-			repositoryCandidates, err = selector.Select(repositoryCandidates)
-			if err != nil {
-				return 0, launcherError(err)
-			}
-		}
-
-		for _, repositoryCandidate := range repositoryCandidates {
-			reposit := selector.Repository{
-				NamespaceID: repositoryCandidate.NamespaceID,
-				Namespace:   repositoryCandidate.Namespace,
-				Name:        repositoryCandidate.Repository,
-				Kind:        repositoryCandidate.Kind,
-			}
-			if repositoryRules[reposit] == nil {
-				repositoryRules[reposit] = &lwp.Metadata{
-					Algorithm: ply.Algorithm,
-				}
-			}
-			r := rule
-			repositoryRules[reposit].Rules = append(repositoryRules[reposit].Rules, &r)
-		}
+	repositoryRules, err := resolveRepositoryRules(ctx, l.projectMgr, l.repositoryMgr, ply)
+	if err != nil {
+		return 0, launcherError(err)
 	}
 
 	// create job data list
@@ -259,6 +183,93 @@ func (l *launcher) Stop(ctx context.Context, executionID int64) error {
 	return l.execMgr.Stop(ctx, executionID)
 }
 
+// resolveRepositoryRules resolves the repositories in the scope of the policy and the rules applied to each of them
+func resolveRepositoryRules(ctx context.Context, projectMgr project.Manager, repositoryMgr repository.Manager,
+	ply *policy.Metadata) (map[selector.Repository]*lwp.Metadata, error) {
+	scope := ply.Scope
+	if scope == nil {
+		return nil, fmt.Errorf("the scope of policy is nil")
+	}
+	repositoryRules := make(map[selector.Repository]*lwp.Metadata, 0)
+	level := scope.Level
+	var allProjects []*selector.Candidate
+	var err error
+	if level == "system" {
+		// get projects
+		allProjects, err = getProjects(ctx, projectMgr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, rule := range ply.Rules {
+		if rule.Disabled {
+			log.Infof("Policy %d rule %d %s is deactivated", ply.ID, rule.ID, rule.Template)
+			continue
+		}
+		projectCandidates := allProjects
+		switch level {
+		case "system":
+			// filter projects according to the project selectors
+			for _, projectSelector := range rule.ScopeSelectors["project"] {
+				selector, err := index.Get(projectSelector.Kind, projectSelector.Decoration,
+					projectSelector.Pattern, "")
+				if err != nil {
+					return nil, err
+				}
+				projectCandidates, err = selector.Select(projectCandidates)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case "project":
+			projectCandidates = append(projectCandidates, &selector.Candidate{
+				NamespaceID: scope.Reference,
+			})
+		}
+
+		var repositoryCandidates []*selector.Candidate
+		// get repositories of projects
+		for _, projectCandidate := range projectCandidates {
+			repositories, err := getRepositories(ctx, projectMgr, repositoryMgr, projectCandidate.NamespaceID)
+			if err != nil {
+				return nil, err
+			}
+			repositoryCandidates = append(repositoryCandidates, repositories...)
+		}
+		// filter repositories according to the repository selectors
+		for _, repositorySelector := range rule.ScopeSelectors["repository"] {
+			selector, err := index.Get(repositorySelector.Kind, repositorySelector.Decoration,
+				repositorySelector.Pattern, repositorySelector.Extras)
+			if err != nil {
+				return nil, err
+			}
+			repositoryCandidates, err = selector.Select(repositoryCandidates)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, repositoryCandidate := range repositoryCandidates {
+			reposit := selector.Repository{
+				NamespaceID: repositoryCandidate.NamespaceID,
+				Namespace:   repositoryCandidate.Namespace,
+				Name:        repositoryCandidate.Repository,
+				Kind:        repositoryCandidate.Kind,
+			}
+			if repositoryRules[reposit] == nil {
+				repositoryRules[reposit] = &lwp.Metadata{
+					Algorithm: ply.Algorithm,
+				}
+			}
+			r := rule
+			repositoryRules[reposit].Rules = append(repositoryRules[reposit].Rules, &r)
+		}
+	}
+
+	return repositoryRules, nil
+}
+
 func launcherError(err error) error {
 	return errors.Wrap(err, "launcher")
 }
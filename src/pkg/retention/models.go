@@ -27,6 +27,14 @@ const (
 
 	ExecutionTriggerManual   string = "Manual"
 	ExecutionTriggerSchedule string = "Schedule"
+
+	SimulationStatusRetained  string = "retained"
+	SimulationStatusRemoved   string = "removed"
+	SimulationStatusArchived  string = "archived"
+	SimulationStatusImmutable string = "immutable"
+	SimulationStatusPinned    string = "pinned"
+	SimulationStatusMutable   string = "mutable"
+	SimulationStatusError     string = "error"
 )
 
 // Execution of retention
@@ -69,3 +77,20 @@ type History struct {
 	Artifact  string    `json:"tag"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// SimulationReport of the repository
+type SimulationReport struct {
+	// full path: :ns/:repo
+	Repository string                 `json:"repository"`
+	Candidates []*SimulationCandidate `json:"candidates"`
+}
+
+// SimulationCandidate is the simulated result of the candidate
+type SimulationCandidate struct {
+	Digest string   `json:"digest"`
+	Tags   []string `json:"tags"`
+	Status string   `json:"status"`
+	// the IDs of the rules matching the candidate
+	Rules []int64 `json:"rules"`
+	Error string  `json:"error,omitempty"`
+}
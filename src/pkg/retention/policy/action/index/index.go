@@ -19,6 +19,7 @@ import (
 
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
 )
 
 // index for keeping the mapping action and its performer
@@ -27,6 +28,10 @@ var index sync.Map
 func init() {
 	// Register retain action
 	Register(action.Retain, action.NewRetainAction)
+	// Register delete action
+	Register(action.Delete, action.NewDeleteAction)
+	// Register archive action
+	Register(action.Archive, action.NewArchiveAction)
 }
 
 // Register the performer with the corresponding action
@@ -39,6 +44,26 @@ func Register(action string, factory action.PerformerFactory) {
 	index.Store(action, factory)
 }
 
+// Valid checks whether the action is registered and the rule parameters required by the action are provided
+func Valid(act string, parameters rule.Parameters) error {
+	if len(act) == 0 {
+		return errors.New("empty action")
+	}
+
+	if _, ok := index.Load(act); !ok {
+		return errors.Errorf("action %s is not registered", act)
+	}
+
+	if act == action.Archive {
+		project, ok := parameters[action.ParameterArchiveProject].(string)
+		if !ok || len(project) == 0 {
+			return errors.Errorf("missing required parameter %s for action %s", action.ParameterArchiveProject, act)
+		}
+	}
+
+	return nil
+}
+
 // Get performer with the provided action
 func Get(act string, params interface{}, isDryRun bool) (action.Performer, error) {
 	if len(act) == 0 {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/goharbor/harbor/src/lib/selector"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
 )
 
 // IndexTestSuite tests the rule index
@@ -73,6 +74,17 @@ func (suite *IndexTestSuite) TestGet() {
 	})
 }
 
+// TestValid tests Valid
+func (suite *IndexTestSuite) TestValid() {
+	assert.Error(suite.T(), Valid("", nil))
+	assert.Error(suite.T(), Valid("unknown", nil))
+	assert.NoError(suite.T(), Valid(action.Retain, nil))
+	assert.NoError(suite.T(), Valid(action.Delete, nil))
+	assert.Error(suite.T(), Valid(action.Archive, nil))
+	assert.Error(suite.T(), Valid(action.Archive, rule.Parameters{action.ParameterArchiveProject: 1}))
+	assert.NoError(suite.T(), Valid(action.Archive, rule.Parameters{action.ParameterArchiveProject: "archive"}))
+}
+
 type fakePerformer struct {
 	parameters interface{}
 	isDryRun   bool
@@ -18,15 +18,24 @@ import (
 	"context"
 
 	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/selector"
 	"github.com/goharbor/harbor/src/pkg/immutable/match/rule"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch"
 	"github.com/goharbor/harbor/src/pkg/retention/dep"
 )
 
 const (
 	// Retain artifacts
 	Retain = "retain"
+	// Delete artifacts
+	Delete = "delete"
+	// Archive artifacts by moving them to the archive project
+	Archive = "archive"
+
+	// ParameterArchiveProject is the name of the rule parameter for the archive project
+	ParameterArchiveProject = "archiveProject"
 )
 
 // Performer performs the related actions targeting the candidates
@@ -55,7 +64,7 @@ type retainAction struct {
 // Perform the action
 func (ra *retainAction) Perform(ctx context.Context, candidates []*selector.Candidate) (results []*selector.Result, err error) {
 	retainedShare := make(map[string]bool)
-	immutableShare := make(map[string]bool)
+	protectedShare := make(map[string]error)
 	for _, c := range candidates {
 		retainedShare[c.Hash()] = true
 	}
@@ -64,8 +73,8 @@ func (ra *retainAction) Perform(ctx context.Context, candidates []*selector.Cand
 		if _, ok := retainedShare[c.Hash()]; ok {
 			continue
 		}
-		if isImmutable(ctx, c) {
-			immutableShare[c.Hash()] = true
+		if err := protected(ctx, c); err != nil {
+			protectedShare[c.Hash()] = err
 		}
 	}
 
@@ -75,9 +84,10 @@ func (ra *retainAction) Perform(ctx context.Context, candidates []*selector.Cand
 			if _, ok := retainedShare[c.Hash()]; !ok {
 				result := &selector.Result{
 					Target: c,
+					Action: Delete,
 				}
-				if _, ok = immutableShare[c.Hash()]; ok {
-					result.Error = &selector.ImmutableError{}
+				if err, ok := protectedShare[c.Hash()]; ok {
+					result.Error = err
 				} else {
 					if !ra.isDryRun {
 						if err := dep.DefaultClient.Delete(c); err != nil {
@@ -93,6 +103,73 @@ func (ra *retainAction) Perform(ctx context.Context, candidates []*selector.Cand
 	return
 }
 
+// deleteAction deletes all the candidates
+type deleteAction struct {
+	// Indicate if it is a dry run
+	isDryRun bool
+}
+
+// Perform the action
+func (da *deleteAction) Perform(ctx context.Context, candidates []*selector.Candidate) (results []*selector.Result, err error) {
+	for _, c := range candidates {
+		result := &selector.Result{
+			Target: c,
+			Action: Delete,
+		}
+		if err := protected(ctx, c); err != nil {
+			result.Error = err
+		} else if !da.isDryRun {
+			if err := dep.DefaultClient.Delete(c); err != nil {
+				result.Error = err
+			}
+		}
+		results = append(results, result)
+	}
+
+	return
+}
+
+// archiveAction moves all the candidates to the archive project
+type archiveAction struct {
+	// the name of the archive project
+	project string
+	// Indicate if it is a dry run
+	isDryRun bool
+}
+
+// Perform the action
+func (aa *archiveAction) Perform(ctx context.Context, candidates []*selector.Candidate) (results []*selector.Result, err error) {
+	for _, c := range candidates {
+		result := &selector.Result{
+			Target: c,
+			Action: Archive,
+		}
+		if len(aa.project) == 0 {
+			result.Error = errors.New("no archive project specified")
+		} else if err := protected(ctx, c); err != nil {
+			result.Error = err
+		} else if !aa.isDryRun {
+			if err := dep.DefaultClient.Archive(c, aa.project); err != nil {
+				result.Error = err
+			}
+		}
+		results = append(results, result)
+	}
+
+	return
+}
+
+// protected returns the error describing why the candidate is protected from being removed, nil if it isn't protected
+func protected(ctx context.Context, c *selector.Candidate) error {
+	if isImmutable(ctx, c) {
+		return &selector.ImmutableError{}
+	}
+	if isPinned(ctx, c) {
+		return &selector.PinnedError{}
+	}
+	return nil
+}
+
 func isImmutable(ctx context.Context, c *selector.Candidate) bool {
 	projectID := c.NamespaceID
 	repo := c.Repository
@@ -109,6 +186,17 @@ func isImmutable(ctx context.Context, c *selector.Candidate) bool {
 	return matched
 }
 
+// isPinned checks whether the candidate is pinned by the prefetch policy of the proxy cache project,
+// the repository of the candidate doesn't contain the project name already
+func isPinned(ctx context.Context, c *selector.Candidate) bool {
+	pinned, err := prefetch.Mgr.IsPinned(ctx, c.NamespaceID, c.Repository, c.Tags...)
+	if err != nil {
+		log.Error(err)
+		return false
+	}
+	return pinned
+}
+
 // NewRetainAction is factory method for RetainAction
 func NewRetainAction(params interface{}, isDryRun bool) Performer {
 	if params != nil {
@@ -125,3 +213,19 @@ func NewRetainAction(params interface{}, isDryRun bool) Performer {
 		isDryRun: isDryRun,
 	}
 }
+
+// NewDeleteAction is factory method for DeleteAction
+func NewDeleteAction(_ interface{}, isDryRun bool) Performer {
+	return &deleteAction{
+		isDryRun: isDryRun,
+	}
+}
+
+// NewArchiveAction is factory method for ArchiveAction, the params is the name of the archive project
+func NewArchiveAction(params interface{}, isDryRun bool) Performer {
+	project, _ := params.(string)
+	return &archiveAction{
+		project:  project,
+		isDryRun: isDryRun,
+	}
+}
@@ -15,6 +15,7 @@
 package action
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -27,10 +28,29 @@ import (
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 	immumodel "github.com/goharbor/harbor/src/pkg/immutable/model"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch"
 	"github.com/goharbor/harbor/src/pkg/retention/dep"
+	"github.com/goharbor/harbor/src/testing/mock"
+	testingPrefetch "github.com/goharbor/harbor/src/testing/pkg/proxy/prefetch"
 )
 
+// TestIsPinned tests isPinned with the multi-level repository of the proxy cache project
+func TestIsPinned(t *testing.T) {
+	mgr := prefetch.Mgr
+	defer func() { prefetch.Mgr = mgr }()
+	pinnedMgr := &testingPrefetch.Manager{}
+	pinnedMgr.On("IsPinned", mock.Anything, int64(1), "library/nginx", "stable").Return(true, nil)
+	pinnedMgr.On("IsPinned", mock.Anything, int64(1), "library/nginx", "latest").Return(false, nil)
+	prefetch.Mgr = pinnedMgr
+
+	// the repository of the candidate is stripped of the project name "dockerhub" already
+	assert.True(t, isPinned(context.Background(), &selector.Candidate{NamespaceID: 1, Namespace: "dockerhub", Repository: "library/nginx", Tags: []string{"stable"}}))
+	assert.False(t, isPinned(context.Background(), &selector.Candidate{NamespaceID: 1, Namespace: "dockerhub", Repository: "library/nginx", Tags: []string{"latest"}}))
+	pinnedMgr.AssertExpectations(t)
+}
+
 // TestPerformerSuite tests the performer related function
 type TestPerformerSuite struct {
 	suite.Suite
@@ -103,6 +123,38 @@ func (suite *TestPerformerSuite) TestPerform() {
 	assert.Equal(suite.T(), "dev", results[0].Target.Tags[0])
 }
 
+// TestPerformDelete tests Perform of the delete action
+func (suite *TestPerformerSuite) TestPerformDelete() {
+	p := NewDeleteAction(nil, false)
+
+	results, err := p.Perform(orm.Context(), suite.all[1:])
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, len(results))
+	require.NotNil(suite.T(), results[0].Target)
+	assert.NoError(suite.T(), results[0].Error)
+	assert.Equal(suite.T(), Delete, results[0].Action)
+	assert.Equal(suite.T(), "dev", results[0].Target.Tags[0])
+}
+
+// TestPerformArchive tests Perform of the archive action
+func (suite *TestPerformerSuite) TestPerformArchive() {
+	p := NewArchiveAction("archive", false)
+
+	results, err := p.Perform(orm.Context(), suite.all)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 2, len(results))
+	for _, r := range results {
+		require.NotNil(suite.T(), r.Target)
+		assert.NoError(suite.T(), r.Error)
+		assert.Equal(suite.T(), Archive, r.Action)
+	}
+
+	results, err = NewArchiveAction(nil, false).Perform(orm.Context(), suite.all)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 2, len(results))
+	assert.Error(suite.T(), results[0].Error)
+}
+
 // TestPerform tests Perform action
 func (suite *TestPerformerSuite) TestPerformImmutable() {
 	all := []*selector.Candidate{
@@ -215,7 +267,7 @@ func (suite *TestPerformerSuite) TestPerformImmutable() {
 type fakeRetentionClient struct{}
 
 // GetCandidates ...
-func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository) ([]*selector.Candidate, error) {
+func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository, option *core.ListArtifactsOption) ([]*selector.Candidate, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -224,6 +276,11 @@ func (frc *fakeRetentionClient) Delete(candidate *selector.Candidate) error {
 	return nil
 }
 
+// Archive ...
+func (frc *fakeRetentionClient) Archive(candidate *selector.Candidate, project string) error {
+	return nil
+}
+
 // DeleteRepository ...
 func (frc *fakeRetentionClient) DeleteRepository(repo *selector.Repository) error {
 	panic("implement me")
@@ -30,27 +30,43 @@ import (
 type processor struct {
 	// keep evaluator and its related selector if existing
 	// attentions here, the selectors can be empty/nil, that means match all "**"
-	evaluators map[*rule.Evaluator][]selector.Selector
+	evaluators map[*rule.Evaluator]*evaluatorItem
 	// action performer
 	performers map[string]action.Performer
 }
 
+// evaluatorItem keeps the selectors, the action and the rule ID of the evaluator
+type evaluatorItem struct {
+	selectors []selector.Selector
+	action    string
+	ruleID    int
+}
+
 // New processor
 func New(parameters []*alg.Parameter) alg.Processor {
 	p := &processor{
-		evaluators: make(map[*rule.Evaluator][]selector.Selector),
+		evaluators: make(map[*rule.Evaluator]*evaluatorItem),
 		performers: make(map[string]action.Performer),
 	}
 
 	if len(parameters) > 0 {
 		for _, param := range parameters {
 			if param.Evaluator != nil {
+				act := param.Action
+				if len(act) == 0 {
+					act = param.Evaluator.Action()
+				}
+
 				if len(param.Selectors) > 0 {
-					p.evaluators[&param.Evaluator] = param.Selectors
+					p.evaluators[&param.Evaluator] = &evaluatorItem{
+						selectors: param.Selectors,
+						action:    act,
+						ruleID:    param.RuleID,
+					}
 				}
 
 				if param.Performer != nil {
-					p.performers[param.Evaluator.Action()] = param.Performer
+					p.performers[act] = param.Performer
 				}
 			}
 		}
@@ -66,16 +82,95 @@ func (p *processor) Process(ctx context.Context, artifacts []*selector.Candidate
 		return make([]*selector.Result, 0), nil
 	}
 
+	processedCandidates, selectedBy, err := p.evaluate(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveActions(processedCandidates)
+
+	results := make([]*selector.Result, 0)
+	// Perform actions
+	for act, hash := range processedCandidates {
+		var attachedErr error
+
+		cl := hash.toList()
+
+		if pf, ok := p.performers[act]; ok {
+			if theRes, err := pf.Perform(ctx, cl); err != nil {
+				attachedErr = err
+			} else {
+				for _, r := range theRes {
+					if r.Target != nil {
+						r.Rules = selectedBy[r.Action][r.Target.Hash()]
+					}
+				}
+				results = append(results, theRes...)
+			}
+		} else {
+			attachedErr = errors.Errorf("no performer added for action %s in OR processor", act)
+		}
+
+		if attachedErr != nil {
+			for _, c := range cl {
+				results = append(results, &selector.Result{
+					Target: c,
+					Error:  attachedErr,
+				})
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// Explain the candidates selected by the rules without performing the actions
+func (p *processor) Explain(_ context.Context, artifacts []*selector.Candidate) ([]*selector.Result, error) {
+	results := make([]*selector.Result, 0)
+	if len(artifacts) == 0 {
+		return results, nil
+	}
+
+	processedCandidates, selectedBy, err := p.evaluate(artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	resolveActions(processedCandidates)
+
+	for act, hash := range processedCandidates {
+		for h, c := range hash {
+			// the archived candidates are handed to the retain performer to be skipped
+			if _, archived := processedCandidates[action.Archive][h]; archived && act != action.Archive {
+				continue
+			}
+			results = append(results, &selector.Result{
+				Target: c,
+				Action: act,
+				Rules:  selectedBy[act][h],
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// evaluate the rules against the candidates and collect the selected candidates and
+// the rules selecting them by action
+func (p *processor) evaluate(artifacts []*selector.Candidate) (map[string]cHash, map[string]map[string][]int, error) {
 	var (
 		// collect errors by wrapping
 		err error
 		// collect processed candidates
 		processedCandidates = make(map[string]cHash)
+		// collect the rules selecting the candidates by action
+		selectedBy = make(map[string]map[string][]int)
 	)
 
 	// for sync
 	type chanItem struct {
 		action    string
+		ruleID    int
 		processed []*selector.Candidate
 	}
 
@@ -104,10 +199,16 @@ func (p *processor) Process(ctx context.Context, artifacts []*selector.Candidate
 					processedCandidates[result.action] = make(cHash)
 				}
 
+				if _, ok := selectedBy[result.action]; !ok {
+					selectedBy[result.action] = make(map[string][]int)
+				}
+
 				listByAction := processedCandidates[result.action]
+				rulesByAction := selectedBy[result.action]
 				for _, rp := range result.processed {
 					// remove duplicated ones
 					listByAction[rp.Hash()] = rp
+					rulesByAction[rp.Hash()] = append(rulesByAction[rp.Hash()], result.ruleID)
 				}
 			case e := <-errChan:
 				if err == nil {
@@ -122,10 +223,10 @@ func (p *processor) Process(ctx context.Context, artifacts []*selector.Candidate
 	wg := new(sync.WaitGroup)
 	wg.Add(len(p.evaluators))
 
-	for eva, selectors := range p.evaluators {
+	for eva, item := range p.evaluators {
 		var evaluator = *eva
 
-		go func(evaluator rule.Evaluator, selectors []selector.Selector) {
+		go func(evaluator rule.Evaluator, item *evaluatorItem) {
 			var (
 				processed []*selector.Candidate
 				err       error
@@ -139,10 +240,10 @@ func (p *processor) Process(ctx context.Context, artifacts []*selector.Candidate
 			// pass array copy to the selector
 			processed = append(processed, artifacts...)
 
-			if len(selectors) > 0 {
+			if len(item.selectors) > 0 {
 				// selecting artifacts one by one
 				// `&&` mappings
-				for _, s := range selectors {
+				for _, s := range item.selectors {
 					if processed, err = s.Select(processed); err != nil {
 						errChan <- err
 						return
@@ -157,10 +258,11 @@ func (p *processor) Process(ctx context.Context, artifacts []*selector.Candidate
 
 			// Pass to the outside
 			resChan <- &chanItem{
-				action:    evaluator.Action(),
+				action:    item.action,
+				ruleID:    item.ruleID,
 				processed: processed,
 			}
-		}(evaluator, selectors)
+		}(evaluator, item)
 	}
 
 	// waiting for all the rules are evaluated
@@ -171,37 +273,36 @@ func (p *processor) Process(ctx context.Context, artifacts []*selector.Candidate
 	<-done
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	results := make([]*selector.Result, 0)
-	// Perform actions
-	for act, hash := range processedCandidates {
-		var attachedErr error
-
-		cl := hash.toList()
+	return processedCandidates, selectedBy, nil
+}
 
-		if pf, ok := p.performers[act]; ok {
-			if theRes, err := pf.Perform(ctx, cl); err != nil {
-				attachedErr = err
-			} else {
-				results = append(results, theRes...)
-			}
-		} else {
-			attachedErr = errors.Errorf("no performer added for action %s in OR processor", act)
+// resolveActions resolves the conflicts of the actions selecting the same candidates:
+// the retained candidates are never deleted or archived and the archived ones are not deleted.
+// As the retain performer removes all the candidates which are not retained, the archived
+// candidates are handed to it as well to be skipped and the delete rules are covered by it.
+func resolveActions(processed map[string]cHash) {
+	retained := processed[action.Retain]
+	if archived, ok := processed[action.Archive]; ok {
+		for h := range retained {
+			delete(archived, h)
 		}
-
-		if attachedErr != nil {
-			for _, c := range cl {
-				results = append(results, &selector.Result{
-					Target: c,
-					Error:  attachedErr,
-				})
-			}
+	}
+	if deleted, ok := processed[action.Delete]; ok {
+		for h := range processed[action.Archive] {
+			delete(deleted, h)
+		}
+		if retained != nil {
+			delete(processed, action.Delete)
+		}
+	}
+	if retained != nil {
+		for h, c := range processed[action.Archive] {
+			retained[h] = c
 		}
 	}
-
-	return results, nil
 }
 
 type cHash map[string]*selector.Candidate
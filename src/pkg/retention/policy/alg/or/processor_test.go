@@ -28,6 +28,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/selector"
 	"github.com/goharbor/harbor/src/lib/selector/selectors/doublestar"
 	"github.com/goharbor/harbor/src/lib/selector/selectors/label"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 	"github.com/goharbor/harbor/src/pkg/retention/dep"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/alg"
@@ -164,10 +165,84 @@ func (suite *ProcessorTestSuite) TestProcess2() {
 
 }
 
+// TestProcessDeleteAndArchive tests process method with the delete and archive actions
+func (suite *ProcessorTestSuite) TestProcessDeleteAndArchive() {
+	params := make([]*alg.Parameter, 0)
+	params = append(params, &alg.Parameter{
+		Evaluator: always.New(make(map[string]rule.Parameter)),
+		Selectors: []selector.Selector{
+			doublestar.New(doublestar.Matches, "**", ""),
+		},
+		Performer: action.NewDeleteAction(nil, true),
+		Action:    action.Delete,
+		RuleID:    0,
+	})
+	params = append(params, &alg.Parameter{
+		Evaluator: always.New(make(map[string]rule.Parameter)),
+		Selectors: []selector.Selector{
+			doublestar.New(doublestar.Matches, "dev", ""),
+		},
+		Performer: action.NewArchiveAction("archive", true),
+		Action:    action.Archive,
+		RuleID:    1,
+	})
+
+	p := New(params)
+
+	results, err := p.Process(orm.Context(), suite.all)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 2, len(results))
+	for _, r := range results {
+		require.NoError(suite.T(), r.Error)
+		switch r.Target.Tags[0] {
+		case "dev":
+			assert.Equal(suite.T(), action.Archive, r.Action)
+			assert.Equal(suite.T(), []int{1}, r.Rules)
+		case "latest":
+			assert.Equal(suite.T(), action.Delete, r.Action)
+			assert.Equal(suite.T(), []int{0}, r.Rules)
+		default:
+			suite.Fail("unexpected result", r.Target.Tags[0])
+		}
+	}
+}
+
+// TestProcessRetainPrecedence tests the retained candidates are never archived
+func (suite *ProcessorTestSuite) TestProcessRetainPrecedence() {
+	params := make([]*alg.Parameter, 0)
+	params = append(params, &alg.Parameter{
+		Evaluator: always.New(make(map[string]rule.Parameter)),
+		Selectors: []selector.Selector{
+			doublestar.New(doublestar.Matches, "latest", ""),
+		},
+		Performer: action.NewRetainAction(suite.all, true),
+		Action:    action.Retain,
+		RuleID:    0,
+	})
+	params = append(params, &alg.Parameter{
+		Evaluator: always.New(make(map[string]rule.Parameter)),
+		Selectors: []selector.Selector{
+			doublestar.New(doublestar.Matches, "**", ""),
+		},
+		Performer: action.NewArchiveAction("archive", true),
+		Action:    action.Archive,
+		RuleID:    1,
+	})
+
+	p := New(params)
+
+	results, err := p.Process(orm.Context(), suite.all)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, len(results))
+	assert.Equal(suite.T(), "dev", results[0].Target.Tags[0])
+	assert.Equal(suite.T(), action.Archive, results[0].Action)
+	assert.Equal(suite.T(), []int{1}, results[0].Rules)
+}
+
 type fakeRetentionClient struct{}
 
 // GetCandidates ...
-func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository) ([]*selector.Candidate, error) {
+func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository, option *core.ListArtifactsOption) ([]*selector.Candidate, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -176,6 +251,11 @@ func (frc *fakeRetentionClient) Delete(candidate *selector.Candidate) error {
 	return nil
 }
 
+// Archive ...
+func (frc *fakeRetentionClient) Archive(candidate *selector.Candidate, project string) error {
+	return nil
+}
+
 // DeleteRepository ...
 func (frc *fakeRetentionClient) DeleteRepository(repo *selector.Repository) error {
 	panic("implement me")
@@ -37,6 +37,20 @@ type Processor interface {
 	Process(ctx context.Context, artifacts []*selector.Candidate) ([]*selector.Result, error)
 }
 
+// Explainer explains which rules select the candidates without performing any action.
+// It's optionally implemented by the processors.
+type Explainer interface {
+	// Explain the artifact candidates
+	//
+	//  Arguments:
+	//    artifacts []*art.Candidate : the retention candidates
+	//
+	//  Returns:
+	//    []*art.Result : the selected candidates with the action and the rules selecting them
+	//    error         : common error object if any errors occurred
+	Explain(ctx context.Context, artifacts []*selector.Candidate) ([]*selector.Result, error)
+}
+
 // Parameter for constructing a processor
 // Represents one rule
 type Parameter struct {
@@ -48,6 +62,12 @@ type Parameter struct {
 
 	// Performer for the rule evaluator
 	Performer action.Performer
+
+	// Action of the rule, the action of the evaluator is used if it's empty
+	Action string
+
+	// RuleID is the ID of the rule
+	RuleID int
 }
 
 // Factory for creating processor
@@ -20,6 +20,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/selector"
 	index2 "github.com/goharbor/harbor/src/lib/selector/selectors/index"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
 	index4 "github.com/goharbor/harbor/src/pkg/retention/policy/action/index"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/alg"
 	index3 "github.com/goharbor/harbor/src/pkg/retention/policy/alg/index"
@@ -67,7 +68,12 @@ func (bb *basicBuilder) Build(policy *lwp.Metadata, isDryRun bool) (alg.Processo
 			return nil, err
 		}
 
-		perf, err := index4.Get(r.Action, bb.allCandidates, isDryRun)
+		var performerParams interface{} = bb.allCandidates
+		if r.Action == action.Archive {
+			performerParams = r.Parameters[action.ParameterArchiveProject]
+		}
+
+		perf, err := index4.Get(r.Action, performerParams, isDryRun)
 		if err != nil {
 			return nil, errors.Wrap(err, "get action performer by metadata")
 		}
@@ -86,6 +92,8 @@ func (bb *basicBuilder) Build(policy *lwp.Metadata, isDryRun bool) (alg.Processo
 			Evaluator: evaluator,
 			Selectors: sl,
 			Performer: perf,
+			Action:    r.Action,
+			RuleID:    r.ID,
 		})
 	}
 
@@ -29,6 +29,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/selector/selectors/doublestar"
 	"github.com/goharbor/harbor/src/lib/selector/selectors/index"
 	"github.com/goharbor/harbor/src/lib/selector/selectors/label"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 	"github.com/goharbor/harbor/src/pkg/retention/dep"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
 	index3 "github.com/goharbor/harbor/src/pkg/retention/policy/action/index"
@@ -157,7 +158,7 @@ func (frc *fakeRetentionClient) DeleteRepository(repo *selector.Repository) erro
 }
 
 // GetCandidates ...
-func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository) ([]*selector.Candidate, error) {
+func (frc *fakeRetentionClient) GetCandidates(repo *selector.Repository, option *core.ListArtifactsOption) ([]*selector.Candidate, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -166,6 +167,11 @@ func (frc *fakeRetentionClient) Delete(candidate *selector.Candidate) error {
 	return nil
 }
 
+// Archive ...
+func (frc *fakeRetentionClient) Archive(candidate *selector.Candidate, project string) error {
+	return nil
+}
+
 // SubmitTask ...
 func (frc *fakeRetentionClient) SubmitTask(taskID int64, repository *selector.Repository, meta *lwp.Metadata) (string, error) {
 	return "", errors.New("not implemented")
@@ -20,6 +20,7 @@ import (
 	"github.com/goharbor/harbor/src/common/utils"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/selector/selectors/doublestar"
+	index4 "github.com/goharbor/harbor/src/pkg/retention/policy/action/index"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/index"
 )
@@ -102,6 +103,10 @@ func (m *Metadata) Valid(v *validation.Validation) {
 				_ = v.SetError("Parameters", err.Error())
 				return
 			}
+			if err := index4.Valid(r.Action, r.Parameters); err != nil {
+				_ = v.SetError("Action", err.Error())
+				return
+			}
 			if ok, _ := v.Valid(&r); !ok {
 				return
 			}
@@ -0,0 +1,119 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daysplany
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name      string
+		args      rule.Parameters
+		expectedN int
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterN: float64(5)}, expectedN: 5},
+		{Name: "Default If Negative", args: map[string]rule.Parameter{ParameterN: float64(-1)}, expectedN: DefaultN},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedN: DefaultN},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterN: "foo"}, expectedN: DefaultN},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedN, e.n)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	now := time.Now().UTC()
+	never := time.Time{}.Unix()
+	data := []*selector.Candidate{
+		// pulled by tag
+		{Digest: "1", PulledTime: daysAgo(now, 1, time.Hour), ReferencePulledTime: never},
+		// the child is pulled by digest
+		{Digest: "2", PulledTime: daysAgo(now, 20, time.Hour), ReferencePulledTime: daysAgo(now, 2, time.Hour)},
+		{Digest: "3", PulledTime: daysAgo(now, 10, time.Hour), ReferencePulledTime: daysAgo(now, 20, time.Hour)},
+		{Digest: "4", PulledTime: never, ReferencePulledTime: never},
+	}
+
+	tests := []struct {
+		n        float64
+		expected []string
+	}{
+		{n: 0, expected: nil},
+		{n: 1, expected: []string{"1"}},
+		{n: 5, expected: []string{"1", "2"}},
+		{n: 15, expected: []string{"1", "2", "3"}},
+		{n: 90, expected: []string{"1", "2", "3"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(fmt.Sprintf("%v", tt.n), func(t *testing.T) {
+			sut := New(map[string]rule.Parameter{ParameterN: tt.n})
+
+			result, err := sut.Process(data)
+
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, r := range result {
+				require.Equal(t, tt.expected[i], r.Digest)
+			}
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name      string
+		args      rule.Parameters
+		expectedN error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterN: 5}, expectedN: nil},
+		{Name: "Negative", args: map[string]rule.Parameter{ParameterN: -1}, expectedN: errors.New("nDaysSinceLastPullByAnyClient is less than zero")},
+		{Name: "Big", args: map[string]rule.Parameter{ParameterN: 21000000}, expectedN: errors.New("nDaysSinceLastPullByAnyClient is too large")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expectedN, err)
+		})
+	}
+}
+
+func TestEvaluatorSuite(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
+
+func daysAgo(from time.Time, n int, offset time.Duration) int64 {
+	return from.Add(time.Duration(-1*24*n)*time.Hour + offset).Unix()
+}
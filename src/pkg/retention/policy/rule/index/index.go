@@ -22,11 +22,15 @@ import (
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/always"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/dayspl"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/daysplany"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/daysps"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/lastx"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestk"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestpl"
 	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestps"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/latestpsref"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/scanpassed"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule/storagebudget"
 )
 
 // index for keeping the mapping between template ID and evaluator
@@ -163,6 +167,62 @@ func init() {
 			},
 		},
 	}, daysps.New, daysps.Valid)
+
+	// Register latest pushed with references
+	Register(&Metadata{
+		TemplateID: latestpsref.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     latestpsref.ParameterK,
+				Type:     "int",
+				Unit:     "count",
+				Required: true,
+			},
+		},
+	}, latestpsref.New, latestpsref.Valid)
+
+	// Register storage budget
+	Register(&Metadata{
+		TemplateID: storagebudget.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     storagebudget.ParameterBudget,
+				Type:     "int",
+				Unit:     "GB",
+				Required: true,
+			},
+		},
+	}, storagebudget.New, storagebudget.Valid)
+
+	// Register daysplany
+	Register(&Metadata{
+		TemplateID: daysplany.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     daysplany.ParameterN,
+				Type:     "int",
+				Unit:     "days",
+				Required: true,
+			},
+		},
+	}, daysplany.New, daysplany.Valid)
+
+	// Register scan passed
+	Register(&Metadata{
+		TemplateID: scanpassed.TemplateID,
+		Action:     action.Retain,
+		Parameters: []*IndexedParam{
+			{
+				Name:     scanpassed.ParameterSeverity,
+				Type:     "string",
+				Unit:     "severity",
+				Required: true,
+			},
+		},
+	}, scanpassed.New, scanpassed.Valid)
 }
 
 // Register the rule evaluator with the corresponding rule template
@@ -0,0 +1,114 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestpsref
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name      string
+		args      rule.Parameters
+		expectedK int
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterK: float64(5)}, expectedK: 5},
+		{Name: "Default If Negative", args: map[string]rule.Parameter{ParameterK: float64(-1)}, expectedK: DefaultK},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedK: DefaultK},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterK: "foo"}, expectedK: DefaultK},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedK, e.k)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	data := []*selector.Candidate{
+		{Digest: "0", PushedTime: 0, Referenced: true},
+		{Digest: "1", PushedTime: 1, Tags: []string{"signed"}, Signatures: map[string]bool{"signed": true}},
+		{Digest: "2", PushedTime: 2, Tags: []string{"unsigned"}, Signatures: map[string]bool{"unsigned": false}},
+		{Digest: "3", PushedTime: 3},
+		{Digest: "4", PushedTime: 4},
+	}
+	rand.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+
+	tests := []struct {
+		k        float64
+		expected []string
+	}{
+		{k: 0, expected: []string{"1", "0"}},
+		{k: 1, expected: []string{"4", "1", "0"}},
+		{k: 3, expected: []string{"4", "3", "2", "1", "0"}},
+		{k: 6, expected: []string{"4", "3", "2", "1", "0"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(fmt.Sprintf("%v", tt.k), func(t *testing.T) {
+			e := New(map[string]rule.Parameter{ParameterK: tt.k})
+
+			result, err := e.Process(data)
+
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, r := range result {
+				require.Equal(t, tt.expected[i], r.Digest)
+			}
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name      string
+		args      rule.Parameters
+		expectedK error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterK: 5}, expectedK: nil},
+		{Name: "Negative", args: map[string]rule.Parameter{ParameterK: -1}, expectedK: errors.New("latestPushedKWithReferences is less than zero")},
+		{Name: "Big", args: map[string]rule.Parameter{ParameterK: 40000}, expectedK: errors.New("latestPushedKWithReferences is too large")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expectedK, err)
+		})
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
@@ -0,0 +1,94 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanpassed
+
+import (
+	"fmt"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+const (
+	// TemplateID of the rule
+	TemplateID = "scanPassed"
+
+	// ParameterSeverity is the name of the metadata parameter for the severity,
+	// the scan report passes when no vulnerability reaches the severity
+	ParameterSeverity = TemplateID
+
+	// DefaultSeverity is the default severity
+	DefaultSeverity = vuln.High
+)
+
+// evaluator retains the artifacts with a successful scan report which has no vulnerability reaching the severity
+type evaluator struct {
+	severity vuln.Severity
+}
+
+func (e *evaluator) Process(artifacts []*selector.Candidate) (result []*selector.Candidate, err error) {
+	for _, a := range artifacts {
+		if a.ScanStatus == job.SuccessStatus.String() && int(a.VulnerabilitySeverity) < e.severity.Code() {
+			result = append(result, a)
+		}
+	}
+
+	return
+}
+
+func (e *evaluator) Action() string {
+	return action.Retain
+}
+
+func New(params rule.Parameters) rule.Evaluator {
+	if params != nil {
+		if p, ok := params[ParameterSeverity]; ok {
+			if s, ok := p.(string); ok && isSupported(s) {
+				return &evaluator{severity: vuln.Severity(s)}
+			}
+		}
+	}
+
+	log.Warningf("default parameter %s used for rule %s", DefaultSeverity, TemplateID)
+
+	return &evaluator{severity: DefaultSeverity}
+}
+
+func Valid(params rule.Parameters) error {
+	if params != nil {
+		if p, ok := params[ParameterSeverity]; ok {
+			s, ok := p.(string)
+			if !ok {
+				return fmt.Errorf("%s type error", ParameterSeverity)
+			}
+			if !isSupported(s) {
+				return fmt.Errorf("%s is not a supported severity", ParameterSeverity)
+			}
+		}
+	}
+	return nil
+}
+
+func isSupported(s string) bool {
+	switch vuln.Severity(s) {
+	case vuln.Negligible, vuln.Low, vuln.Medium, vuln.High, vuln.Critical:
+		return true
+	}
+	return false
+}
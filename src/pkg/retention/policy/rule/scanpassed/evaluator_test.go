@@ -0,0 +1,111 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanpassed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name             string
+		args             rule.Parameters
+		expectedSeverity vuln.Severity
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterSeverity: "Medium"}, expectedSeverity: vuln.Medium},
+		{Name: "Default If Unsupported", args: map[string]rule.Parameter{ParameterSeverity: "foo"}, expectedSeverity: DefaultSeverity},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedSeverity: DefaultSeverity},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterSeverity: 1}, expectedSeverity: DefaultSeverity},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedSeverity, e.severity)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	success := job.SuccessStatus.String()
+	data := []*selector.Candidate{
+		{Digest: "clean", ScanStatus: success, VulnerabilitySeverity: uint(vuln.None.Code())},
+		{Digest: "medium", ScanStatus: success, VulnerabilitySeverity: uint(vuln.Medium.Code())},
+		{Digest: "critical", ScanStatus: success, VulnerabilitySeverity: uint(vuln.Critical.Code())},
+		{Digest: "error", ScanStatus: job.ErrorStatus.String()},
+		{Digest: "unscanned"},
+	}
+
+	tests := []struct {
+		severity string
+		expected []string
+	}{
+		{severity: "Low", expected: []string{"clean"}},
+		{severity: "High", expected: []string{"clean", "medium"}},
+		{severity: "Critical", expected: []string{"clean", "medium"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.severity, func(t *testing.T) {
+			sut := New(map[string]rule.Parameter{ParameterSeverity: tt.severity})
+
+			result, err := sut.Process(data)
+
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, r := range result {
+				require.Equal(t, tt.expected[i], r.Digest)
+			}
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name     string
+		args     rule.Parameters
+		expected error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterSeverity: "High"}, expected: nil},
+		{Name: "Unsupported", args: map[string]rule.Parameter{ParameterSeverity: "foo"}, expected: errors.New("scanPassed is not a supported severity")},
+		{Name: "Wrong Type", args: map[string]rule.Parameter{ParameterSeverity: 1}, expected: errors.New("scanPassed type error")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
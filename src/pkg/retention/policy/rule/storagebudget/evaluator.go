@@ -0,0 +1,103 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagebudget
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+)
+
+const (
+	// TemplateID of the rule
+	TemplateID = "storageBudgetGB"
+
+	// ParameterBudget is the name of the metadata parameter for the budget value in GB
+	ParameterBudget = TemplateID
+
+	// DefaultBudget is the default storage budget of a repository in GB
+	DefaultBudget = 10
+
+	// MaxBudget is the max storage budget of a repository in GB
+	MaxBudget = 1 << 20
+
+	gb = int64(1) << 30
+)
+
+// evaluator caps the storage of the repository to the budget, the newest pushed artifacts are retained first
+type evaluator struct {
+	// budget in bytes
+	budget int64
+}
+
+func (e *evaluator) Process(artifacts []*selector.Candidate) ([]*selector.Candidate, error) {
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].PushedTime > artifacts[j].PushedTime
+	})
+
+	result := make([]*selector.Candidate, 0)
+	var total int64
+	for _, a := range artifacts {
+		// stop at the first artifact which exceeds the budget to keep the newest artifacts only
+		if total+a.Size > e.budget {
+			break
+		}
+		total += a.Size
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+func (e *evaluator) Action() string {
+	return action.Retain
+}
+
+func New(params rule.Parameters) rule.Evaluator {
+	if params != nil {
+		if p, ok := params[ParameterBudget]; ok {
+			if v, ok := utils.ParseJSONInt(p); ok && v >= 0 && v <= MaxBudget {
+				return &evaluator{budget: int64(v) * gb}
+			}
+		}
+	}
+
+	log.Warningf("default parameter %d used for rule %s", DefaultBudget, TemplateID)
+
+	return &evaluator{budget: DefaultBudget * gb}
+}
+
+func Valid(params rule.Parameters) error {
+	if params != nil {
+		if p, ok := params[ParameterBudget]; ok {
+			if v, ok := utils.ParseJSONInt(p); ok {
+				if v < 0 {
+					return fmt.Errorf("%s is less than zero", ParameterBudget)
+				}
+				if v > MaxBudget {
+					return fmt.Errorf("%s is too large", ParameterBudget)
+				}
+			} else {
+				return fmt.Errorf("%s type error", ParameterBudget)
+			}
+		}
+	}
+	return nil
+}
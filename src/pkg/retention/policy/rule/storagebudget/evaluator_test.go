@@ -0,0 +1,115 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagebudget
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+)
+
+type EvaluatorTestSuite struct {
+	suite.Suite
+}
+
+func (e *EvaluatorTestSuite) TestNew() {
+	tests := []struct {
+		Name           string
+		args           rule.Parameters
+		expectedBudget int64
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterBudget: float64(5)}, expectedBudget: 5 * gb},
+		{Name: "Default If Negative", args: map[string]rule.Parameter{ParameterBudget: float64(-1)}, expectedBudget: DefaultBudget * gb},
+		{Name: "Default If Not Set", args: map[string]rule.Parameter{}, expectedBudget: DefaultBudget * gb},
+		{Name: "Default If Wrong Type", args: map[string]rule.Parameter{ParameterBudget: "foo"}, expectedBudget: DefaultBudget * gb},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			e := New(tt.args).(*evaluator)
+
+			require.Equal(t, tt.expectedBudget, e.budget)
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestProcess() {
+	data := []*selector.Candidate{
+		{Digest: "0", PushedTime: 0, Size: gb / 2},
+		{Digest: "1", PushedTime: 1, Size: gb / 2},
+		{Digest: "2", PushedTime: 2, Size: 2 * gb},
+		{Digest: "3", PushedTime: 3, Size: gb / 2},
+		{Digest: "4", PushedTime: 4, Size: gb / 2},
+	}
+	rand.Shuffle(len(data), func(i, j int) {
+		data[i], data[j] = data[j], data[i]
+	})
+
+	tests := []struct {
+		budget   float64
+		expected []string
+	}{
+		{budget: 0, expected: []string{}},
+		{budget: 1, expected: []string{"4", "3"}},
+		{budget: 2, expected: []string{"4", "3"}},
+		{budget: 3, expected: []string{"4", "3", "2"}},
+		{budget: 4, expected: []string{"4", "3", "2", "1", "0"}},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(fmt.Sprintf("%v", tt.budget), func(t *testing.T) {
+			e := New(map[string]rule.Parameter{ParameterBudget: tt.budget})
+
+			result, err := e.Process(data)
+
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, r := range result {
+				require.Equal(t, tt.expected[i], r.Digest)
+			}
+		})
+	}
+}
+
+func (e *EvaluatorTestSuite) TestValid() {
+	tests := []struct {
+		Name     string
+		args     rule.Parameters
+		expected error
+	}{
+		{Name: "Valid", args: map[string]rule.Parameter{ParameterBudget: 5}, expected: nil},
+		{Name: "Negative", args: map[string]rule.Parameter{ParameterBudget: -1}, expected: errors.New("storageBudgetGB is less than zero")},
+		{Name: "Big", args: map[string]rule.Parameter{ParameterBudget: MaxBudget + 1}, expected: errors.New("storageBudgetGB is too large")},
+	}
+
+	for _, tt := range tests {
+		e.T().Run(tt.Name, func(t *testing.T) {
+			err := Valid(tt.args)
+
+			require.Equal(t, tt.expected, err)
+		})
+	}
+}
+
+func TestEvaluator(t *testing.T) {
+	suite.Run(t, &EvaluatorTestSuite{})
+}
@@ -0,0 +1,252 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/lib/selector"
+	"github.com/goharbor/harbor/src/pkg/immutable/match/rule"
+	immumodel "github.com/goharbor/harbor/src/pkg/immutable/model"
+	"github.com/goharbor/harbor/src/pkg/project"
+	"github.com/goharbor/harbor/src/pkg/repository"
+	"github.com/goharbor/harbor/src/pkg/retention/policy"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/alg"
+)
+
+// CandidateLoader loads the candidates under the repository
+type CandidateLoader func(ctx context.Context, repository *selector.Repository) ([]*selector.Candidate, error)
+
+// Simulator simulates the policies against the current contents of the projects.
+// Nothing is changed and no job is launched during the simulation.
+type Simulator interface {
+	// SimulateRetention simulates the retention policy which is not required to be saved
+	//
+	//  Arguments:
+	//   policy *policy.Metadata : the policy to simulate
+	//   query *q.Query          : the pagination of the repositories
+	//
+	//  Returns:
+	//   int64               : the total count of the repositories in the scope of the policy
+	//   []*SimulationReport : the reports of the repositories in the page
+	//   error               : common error if any errors occurred
+	SimulateRetention(ctx context.Context, policy *policy.Metadata, query *q.Query) (int64, []*SimulationReport, error)
+	// SimulateImmutable simulates the immutable rules which are not required to be saved
+	//
+	//  Arguments:
+	//   projectID int64               : the project the rules apply to
+	//   rules []*immumodel.Metadata   : the immutable rules to simulate
+	//   query *q.Query                : the pagination of the repositories
+	//
+	//  Returns:
+	//   int64               : the total count of the repositories in the project
+	//   []*SimulationReport : the reports of the repositories in the page
+	//   error               : common error if any errors occurred
+	SimulateImmutable(ctx context.Context, projectID int64, rules []*immumodel.Metadata, query *q.Query) (int64, []*SimulationReport, error)
+}
+
+// NewSimulator returns an instance of Simulator
+func NewSimulator(projectMgr project.Manager, repositoryMgr repository.Manager, loader CandidateLoader) Simulator {
+	return &simulator{
+		projectMgr:    projectMgr,
+		repositoryMgr: repositoryMgr,
+		loader:        loader,
+	}
+}
+
+type simulator struct {
+	projectMgr    project.Manager
+	repositoryMgr repository.Manager
+	loader        CandidateLoader
+}
+
+func (s *simulator) SimulateRetention(ctx context.Context, ply *policy.Metadata, query *q.Query) (int64, []*SimulationReport, error) {
+	if ply == nil {
+		return 0, nil, errors.New("the policy is nil")
+	}
+	repositoryRules, err := resolveRepositoryRules(ctx, s.projectMgr, s.repositoryMgr, ply)
+	if err != nil {
+		return 0, nil, err
+	}
+	repositories := make([]selector.Repository, 0, len(repositoryRules))
+	for repo := range repositoryRules {
+		repositories = append(repositories, repo)
+	}
+
+	reports := make([]*SimulationReport, 0)
+	for _, repo := range paginate(repositories, query) {
+		candidates, err := s.loader(ctx, &repo)
+		if err != nil {
+			return 0, nil, err
+		}
+		processor, err := policy.NewBuilder(candidates).Build(repositoryRules[repo], true)
+		if err != nil {
+			return 0, nil, err
+		}
+		results, err := processor.Process(ctx, candidates)
+		if err != nil {
+			return 0, nil, err
+		}
+		selected := make([]*selector.Result, 0)
+		if explainer, ok := processor.(alg.Explainer); ok {
+			if selected, err = explainer.Explain(ctx, candidates); err != nil {
+				return 0, nil, err
+			}
+		}
+		reports = append(reports, retentionReport(&repo, candidates, results, selected))
+	}
+
+	return int64(len(repositories)), reports, nil
+}
+
+func (s *simulator) SimulateImmutable(ctx context.Context, projectID int64, rules []*immumodel.Metadata, query *q.Query) (int64, []*SimulationReport, error) {
+	repositoryCandidates, err := getRepositories(ctx, s.projectMgr, s.repositoryMgr, projectID)
+	if err != nil {
+		return 0, nil, err
+	}
+	repositories := make([]selector.Repository, 0, len(repositoryCandidates))
+	for _, c := range repositoryCandidates {
+		repositories = append(repositories, selector.Repository{
+			NamespaceID: c.NamespaceID,
+			Namespace:   c.Namespace,
+			Name:        c.Repository,
+			Kind:        c.Kind,
+		})
+	}
+
+	reports := make([]*SimulationReport, 0)
+	for _, repo := range paginate(repositories, query) {
+		candidates, err := s.loader(ctx, &repo)
+		if err != nil {
+			return 0, nil, err
+		}
+		report := &SimulationReport{
+			Repository: fmt.Sprintf("%s/%s", repo.Namespace, repo.Name),
+			Candidates: make([]*SimulationCandidate, 0, len(candidates)),
+		}
+		for _, c := range candidates {
+			sc := &SimulationCandidate{
+				Digest: c.Digest,
+				Tags:   c.Tags,
+				Status: SimulationStatusMutable,
+				Rules:  make([]int64, 0),
+			}
+			for _, r := range rules {
+				if r.Disabled {
+					continue
+				}
+				matched, err := rule.NewRuleMatcherWithRules([]*immumodel.Metadata{r}).Match(ctx, projectID, *c)
+				if err != nil {
+					return 0, nil, err
+				}
+				if matched {
+					sc.Status = SimulationStatusImmutable
+					sc.Rules = append(sc.Rules, r.ID)
+				}
+			}
+			report.Candidates = append(report.Candidates, sc)
+		}
+		reports = append(reports, report)
+	}
+
+	return int64(len(repositories)), reports, nil
+}
+
+// retentionReport builds the report of the repository with the results of the dry run and the rules selecting the candidates
+func retentionReport(repo *selector.Repository, candidates []*selector.Candidate, results, selected []*selector.Result) *SimulationReport {
+	performed := make(map[string]*selector.Result, len(results))
+	for _, r := range results {
+		if r.Target != nil {
+			performed[r.Target.Hash()] = r
+		}
+	}
+	retained := make(map[string]*selector.Result, len(selected))
+	for _, r := range selected {
+		if r.Target != nil && r.Action == action.Retain {
+			retained[r.Target.Hash()] = r
+		}
+	}
+
+	report := &SimulationReport{
+		Repository: fmt.Sprintf("%s/%s", repo.Namespace, repo.Name),
+		Candidates: make([]*SimulationCandidate, 0, len(candidates)),
+	}
+	for _, c := range candidates {
+		sc := &SimulationCandidate{
+			Digest: c.Digest,
+			Tags:   c.Tags,
+			Status: SimulationStatusRetained,
+			Rules:  make([]int64, 0),
+		}
+		r, ok := performed[c.Hash()]
+		if ok {
+			switch {
+			case r.Error != nil:
+				if _, immutable := r.Error.(*selector.ImmutableError); immutable {
+					sc.Status = SimulationStatusImmutable
+				} else if _, pinned := r.Error.(*selector.PinnedError); pinned {
+					sc.Status = SimulationStatusPinned
+				} else {
+					sc.Status = SimulationStatusError
+					sc.Error = r.Error.Error()
+				}
+			case r.Action == action.Archive:
+				sc.Status = SimulationStatusArchived
+			default:
+				sc.Status = SimulationStatusRemoved
+			}
+		} else {
+			r = retained[c.Hash()]
+		}
+		if r != nil {
+			for _, id := range r.Rules {
+				sc.Rules = append(sc.Rules, int64(id))
+			}
+		}
+		report.Candidates = append(report.Candidates, sc)
+	}
+	return report
+}
+
+// paginate sorts the repositories by the names and returns the ones in the page
+func paginate(repositories []selector.Repository, query *q.Query) []selector.Repository {
+	sort.Slice(repositories, func(i, j int) bool {
+		if repositories[i].Namespace != repositories[j].Namespace {
+			return repositories[i].Namespace < repositories[j].Namespace
+		}
+		return repositories[i].Name < repositories[j].Name
+	})
+	if query == nil || query.PageSize <= 0 {
+		return repositories
+	}
+	page := query.PageNumber
+	if page <= 0 {
+		page = 1
+	}
+	start := (page - 1) * query.PageSize
+	if start >= int64(len(repositories)) {
+		return []selector.Repository{}
+	}
+	end := start + query.PageSize
+	if end > int64(len(repositories)) {
+		end = int64(len(repositories))
+	}
+	return repositories[start:end]
+}
@@ -0,0 +1,178 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/orm"
+	pq "github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/lib/selector"
+	immumodel "github.com/goharbor/harbor/src/pkg/immutable/model"
+	"github.com/goharbor/harbor/src/pkg/repository/model"
+	"github.com/goharbor/harbor/src/pkg/retention/policy"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/rule"
+	_ "github.com/goharbor/harbor/src/pkg/retention/policy/rule/index"
+	"github.com/goharbor/harbor/src/testing/mock"
+	projecttesting "github.com/goharbor/harbor/src/testing/pkg/project"
+	"github.com/goharbor/harbor/src/testing/pkg/repository"
+)
+
+type simulatorTestSuite struct {
+	suite.Suite
+	simulator Simulator
+}
+
+func (s *simulatorTestSuite) SetupTest() {
+	repositoryMgr := &repository.Manager{}
+	repositoryMgr.On("List", mock.Anything, mock.Anything).Return([]*model.RepoRecord{
+		{
+			RepositoryID: 1,
+			ProjectID:    1,
+			Name:         "library/redis",
+		},
+		{
+			RepositoryID: 2,
+			ProjectID:    1,
+			Name:         "library/nginx",
+		},
+	}, nil)
+	loader := func(_ context.Context, repo *selector.Repository) ([]*selector.Candidate, error) {
+		return []*selector.Candidate{
+			{
+				NamespaceID: repo.NamespaceID,
+				Namespace:   repo.Namespace,
+				Repository:  repo.Name,
+				Kind:        selector.Image,
+				Digest:      "d1",
+				Tags:        []string{"v1"},
+				PushedTime:  1,
+			},
+			{
+				NamespaceID: repo.NamespaceID,
+				Namespace:   repo.Namespace,
+				Repository:  repo.Name,
+				Kind:        selector.Image,
+				Digest:      "d2",
+				Tags:        []string{"latest"},
+				PushedTime:  2,
+			},
+		}, nil
+	}
+	s.simulator = NewSimulator(&projecttesting.Manager{}, repositoryMgr, loader)
+}
+
+func (s *simulatorTestSuite) TestSimulateRetention() {
+	ply := &policy.Metadata{
+		Algorithm: policy.AlgorithmOR,
+		Scope: &policy.Scope{
+			Level:     policy.ScopeLevelProject,
+			Reference: 1,
+		},
+		Rules: []rule.Metadata{
+			{
+				ID:       3,
+				Action:   action.Retain,
+				Template: "always",
+				TagSelectors: []*rule.Selector{
+					{
+						Kind:       "doublestar",
+						Decoration: "matches",
+						Pattern:    "latest",
+					},
+				},
+				ScopeSelectors: map[string][]*rule.Selector{
+					"repository": {
+						{
+							Kind:       "doublestar",
+							Decoration: "repoMatches",
+							Pattern:    "**",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	total, reports, err := s.simulator.SimulateRetention(orm.Context(), ply, &pq.Query{PageNumber: 2, PageSize: 1})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), total)
+	require.Len(s.T(), reports, 1)
+	assert.Equal(s.T(), "library/redis", reports[0].Repository)
+	require.Len(s.T(), reports[0].Candidates, 2)
+	for _, c := range reports[0].Candidates {
+		switch c.Digest {
+		case "d1":
+			assert.Equal(s.T(), SimulationStatusRemoved, c.Status)
+			assert.Empty(s.T(), c.Rules)
+		case "d2":
+			assert.Equal(s.T(), SimulationStatusRetained, c.Status)
+			assert.Equal(s.T(), []int64{3}, c.Rules)
+		}
+	}
+}
+
+func (s *simulatorTestSuite) TestSimulateImmutable() {
+	rules := []*immumodel.Metadata{
+		{
+			ID:        5,
+			ProjectID: 1,
+			TagSelectors: []*immumodel.Selector{
+				{
+					Kind:       "doublestar",
+					Decoration: "matches",
+					Pattern:    "v*",
+				},
+			},
+			ScopeSelectors: map[string][]*immumodel.Selector{
+				"repository": {
+					{
+						Kind:       "doublestar",
+						Decoration: "repoMatches",
+						Pattern:    "**",
+					},
+				},
+			},
+		},
+	}
+
+	total, reports, err := s.simulator.SimulateImmutable(orm.Context(), 1, rules, nil)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), total)
+	require.Len(s.T(), reports, 2)
+	assert.Equal(s.T(), "library/nginx", reports[0].Repository)
+	for _, report := range reports {
+		require.Len(s.T(), report.Candidates, 2)
+		for _, c := range report.Candidates {
+			if c.Digest == "d1" {
+				assert.Equal(s.T(), SimulationStatusImmutable, c.Status)
+				assert.Equal(s.T(), []int64{5}, c.Rules)
+			} else {
+				assert.Equal(s.T(), SimulationStatusMutable, c.Status)
+				assert.Empty(s.T(), c.Rules)
+			}
+		}
+	}
+}
+
+func TestSimulatorTestSuite(t *testing.T) {
+	suite.Run(t, new(simulatorTestSuite))
+}
@@ -17,7 +17,9 @@ package scan
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/orm"
 	"github.com/goharbor/harbor/src/lib/q"
@@ -41,6 +43,10 @@ type DAO interface {
 	Update(ctx context.Context, r *Report, cols ...string) error
 	// DeleteByExtraAttr delete the scan_report by mimeType and extra attribute
 	DeleteByExtraAttr(ctx context.Context, mimeType, attrName, attrValue string) error
+	// ListStaleArtifactIDs lists the IDs of the artifacts which have the reports of the registration but haven't been
+	// scanned since scannedBefore, ordered by the pull count of their repositories and their pull time, the artifacts
+	// not pulled since pulledSince are excluded when it isn't zero
+	ListStaleArtifactIDs(ctx context.Context, registrationUUID string, scannedBefore, pulledSince time.Time, limit int) ([]int64, error)
 }
 
 // New returns an instance of the default DAO
@@ -124,3 +130,28 @@ func (d *dao) DeleteByExtraAttr(ctx context.Context, mimeType, attrName, attrVal
 	_, err = o.Raw(delReportSQL, mimeType, dgstJSONStr).Exec()
 	return err
 }
+
+func (d *dao) ListStaleArtifactIDs(ctx context.Context, registrationUUID string, scannedBefore, pulledSince time.Time, limit int) ([]int64, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// the tasks of the single artifact scan, the scan all and the rescan record the artifact ID in the extra attributes
+	sql := `SELECT a.id FROM artifact AS a JOIN repository AS r ON a.repository_id = r.repository_id
+		WHERE EXISTS (SELECT 1 FROM scan_report AS s WHERE s.digest = a.digest AND s.registration_uuid = ?)
+		AND NOT EXISTS (SELECT 1 FROM task AS t WHERE t.vendor_type IN (?, ?, ?)
+			AND t.extra_attrs::jsonb ->> 'artifact_id' = a.id::text AND t.creation_time >= ?)`
+	params := []interface{}{registrationUUID, job.ImageScanJobVendorType, job.ScanAllVendorType, job.ScanDBUpdateVendorType, scannedBefore}
+	if !pulledSince.IsZero() {
+		sql += ` AND a.pull_time >= ?`
+		params = append(params, pulledSince)
+	}
+	sql += ` ORDER BY r.pull_count DESC, a.pull_time DESC NULLS LAST, a.id LIMIT ?`
+	params = append(params, limit)
+
+	ids := []int64{}
+	if _, err := o.Raw(sql, params...).QueryRows(&ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -16,6 +16,7 @@ package scan
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -105,6 +106,13 @@ func (suite *ReportTestSuite) TestReportUpdateReportData() {
 	suite.Require().NoError(err)
 }
 
+// TestListStaleArtifactIDs tests listing the IDs of the artifacts with the stale reports.
+func (suite *ReportTestSuite) TestListStaleArtifactIDs() {
+	ids, err := suite.dao.ListStaleArtifactIDs(orm.Context(), "ruuid", time.Now(), time.Now().Add(-24*time.Hour), 10)
+	suite.Require().NoError(err)
+	suite.Empty(ids)
+}
+
 func (suite *ReportTestSuite) create(r *Report) {
 	id, err := suite.dao.Create(orm.Context(), r)
 	suite.Require().NoError(err)
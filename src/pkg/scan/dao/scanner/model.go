@@ -65,6 +65,11 @@ type Registration struct {
 
 	Metadata *v1.ScannerAdapterMetadata `orm:"-" json:"-"`
 
+	// The updated_at property of the vulnerability database reported by the adapter and the time its latest
+	// change was detected, the reports generated before the change are stale
+	DBUpdatedAt        string    `orm:"column(db_updated_at);null" json:"-"`
+	DBUpdateDetectedAt time.Time `orm:"column(db_update_detected_at);null;type(datetime)" json:"-"`
+
 	// Timestamps
 	CreateTime   time.Time              `orm:"column(create_time);auto_now_add;type(datetime)" json:"create_time"`
 	UpdateTime   time.Time              `orm:"column(update_time);auto_now;type(datetime)" json:"update_time"`
@@ -190,6 +195,15 @@ func (r *Registration) GetCapability(mimeType string) *v1.ScannerCapability {
 	return nil
 }
 
+// GetSBOMMimeTypes returns the mime types of the SBOM documents which the scanner matches the vulnerabilities against
+func (r *Registration) GetSBOMMimeTypes() []string {
+	if r.Metadata == nil {
+		return nil
+	}
+
+	return r.Metadata.GetSBOMMimeTypes()
+}
+
 // GetRegistryAuthorizationType returns the registry authorization type of the scanner
 func (r *Registration) GetRegistryAuthorizationType() string {
 	var auth string
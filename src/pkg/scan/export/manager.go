@@ -33,6 +33,7 @@ const (
 	// consider for performance, the caller will slice the artifact ids to multi
 	// groups if it's length over limit, so rowNum offset is designed to ensure the
 	// final row id is sequence in the final output csv file.
+	// The vulnerabilities declared as not_affected or fixed by the VEX statements of the artifact in its project are excluded.
 	VulnScanReportQueryTemplate = `
 select
     artifact.digest as artifact_digest,
@@ -54,6 +55,16 @@ from
     inner join vulnerability_record on report_vulnerability_record.vuln_record_id = vulnerability_record.id
     inner join scanner_registration on scan_report.registration_uuid = scanner_registration.uuid
 and artifact.id in (%s)
+where not exists (
+    select 1
+    from vex_statement
+    where vex_statement.project_id = artifact.project_id
+        and vex_statement.artifact_digest = artifact.digest
+        and vex_statement.cve_id = vulnerability_record.cve_id
+        and vex_statement.status in ('not_affected', 'fixed')
+        and coalesce(vex_statement.package, '') in ('', vulnerability_record.package)
+        and coalesce(vex_statement.package_version, '') in ('', vulnerability_record.package_version)
+)
 
 group by
     package,
@@ -404,6 +404,7 @@ func removeScanAuthInfo(sr *v1.ScanRequest) string {
 			Authorization: "[HIDDEN]",
 		},
 		RequestType: sr.RequestType,
+		SBOM:        sr.SBOM,
 	}
 
 	str, err := req.ToJSON()
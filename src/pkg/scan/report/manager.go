@@ -16,6 +16,7 @@ package report
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -106,6 +107,18 @@ type Manager interface {
 	Update(ctx context.Context, r *scan.Report, cols ...string) error
 	// DeleteByExtraAttr delete scan_report by sbom_digest
 	DeleteByExtraAttr(ctx context.Context, mimeType, attrName, attrValue string) error
+
+	// ListStaleArtifactIDs lists the IDs of the artifacts whose reports generated by the registration are stale.
+	//  Arguments:
+	//    ctx context.Context : the context for this method
+	//    registrationUUID string   : the UUID of the registration which generated the reports
+	//    scannedBefore time.Time   : the artifacts not scanned since the time are stale
+	//    pulledSince time.Time     : [optional] only the artifacts pulled since the time are listed
+	//    limit int                 : the max count of the artifact IDs
+	//  Returns:
+	//    []int64 : the artifact IDs ordered by the pull count of the repositories and the pull time of the artifacts
+	//    error   : non nil error if any errors occurred
+	ListStaleArtifactIDs(ctx context.Context, registrationUUID string, scannedBefore, pulledSince time.Time, limit int) ([]int64, error)
 }
 
 // basicManager is a default implementation of report manager.
@@ -232,3 +245,7 @@ func (bm *basicManager) Update(ctx context.Context, r *scan.Report, cols ...stri
 func (bm *basicManager) DeleteByExtraAttr(ctx context.Context, mimeType, attrName, attrValue string) error {
 	return bm.dao.DeleteByExtraAttr(ctx, mimeType, attrName, attrValue)
 }
+
+func (bm *basicManager) ListStaleArtifactIDs(ctx context.Context, registrationUUID string, scannedBefore, pulledSince time.Time, limit int) ([]int64, error) {
+	return bm.dao.ListStaleArtifactIDs(ctx, registrationUUID, scannedBefore, pulledSince, limit)
+}
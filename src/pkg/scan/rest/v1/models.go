@@ -24,6 +24,10 @@ import (
 const (
 	supportVulnerability = "support_vulnerability"
 	supportSBOM          = "support_sbom"
+	supportSBOMInput     = "support_sbom_input"
+
+	// PropertyDBUpdatedAt is the property reporting when the vulnerability database of the scanner was updated
+	PropertyDBUpdatedAt = "harbor.scanner-adapter/vulnerability-database-updated-at"
 )
 
 var supportedMimeTypes = []string{
@@ -32,6 +36,11 @@ var supportedMimeTypes = []string{
 	MimeTypeSBOMReport,
 }
 
+var sbomMimeTypes = []string{
+	MimeTypeSPDX,
+	MimeTypeCycloneDX,
+}
+
 // Scanner represents metadata of a Scanner Adapter which allow Harbor to lookup a scanner capable of
 // scanning a given Artifact stored in its registry and making sure that it can interpret a
 // returned result.
@@ -158,6 +167,33 @@ func (md *ScannerAdapterMetadata) GetCapability(mimeType string) *ScannerCapabil
 	return nil
 }
 
+// GetSBOMMimeTypes returns the mime types of the SBOM documents consumed by the vulnerability capability,
+// the scanner matches the vulnerabilities against the SBOM of the artifact instead of pulling the artifact
+func (md *ScannerAdapterMetadata) GetSBOMMimeTypes() []string {
+	var mimeTypes []string
+	for _, capability := range md.Capabilities {
+		if len(capability.Type) > 0 && capability.Type != ScanTypeVulnerability {
+			continue
+		}
+		for _, mt := range capability.ConsumesMimeTypes {
+			if isSBOMMimeType(mt) {
+				mimeTypes = append(mimeTypes, mt)
+			}
+		}
+	}
+
+	return mimeTypes
+}
+
+func isSBOMMimeType(mimeType string) bool {
+	for _, mt := range sbomMimeTypes {
+		if mt == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
 // ConvertCapability converts the capability to map, used in get scanner API
 func (md *ScannerAdapterMetadata) ConvertCapability() map[string]interface{} {
 	capabilities := make(map[string]interface{})
@@ -172,6 +208,9 @@ func (md *ScannerAdapterMetadata) ConvertCapability() map[string]interface{} {
 			capabilities[supportSBOM] = true
 		}
 	}
+	if len(md.GetSBOMMimeTypes()) > 0 {
+		capabilities[supportSBOMInput] = true
+	}
 	if oldScanner && len(capabilities) == 0 {
 		// to compatible with old version scanner, suppose they should always support scan vulnerability when capability is empty
 		capabilities[supportVulnerability] = true
@@ -219,6 +258,9 @@ type ScanRequest struct {
 	Artifact *Artifact `json:"artifact"`
 	// RequestType
 	RequestType []*ScanType `json:"enabled_capabilities"`
+	// SBOM of the artifact stored as its accessory, only sent to the scanner consuming the SBOM
+	// which matches the vulnerabilities against it instead of pulling the artifact.
+	SBOM *Artifact `json:"sbom,omitempty"`
 }
 
 // ScanType represent the type of the scan request
@@ -39,3 +39,26 @@ func TestConvertCapabilityOldScaner(t *testing.T) {
 	assert.Equal(t, result[supportSBOM], false)
 	assert.Equal(t, result[supportVulnerability], true)
 }
+
+func TestGetSBOMMimeTypes(t *testing.T) {
+	md := &ScannerAdapterMetadata{
+		Capabilities: []*ScannerCapability{
+			{
+				Type:              ScanTypeVulnerability,
+				ConsumesMimeTypes: []string{MimeTypeOCIArtifact, MimeTypeCycloneDX, MimeTypeSPDX},
+				ProducesMimeTypes: []string{MimeTypeNativeReport},
+			},
+			{
+				Type:              ScanTypeSbom,
+				ConsumesMimeTypes: []string{MimeTypeOCIArtifact},
+				ProducesMimeTypes: []string{MimeTypeSBOMReport},
+			},
+		},
+	}
+	assert.Equal(t, []string{MimeTypeCycloneDX, MimeTypeSPDX}, md.GetSBOMMimeTypes())
+	assert.Equal(t, true, md.ConvertCapability()[supportSBOMInput])
+
+	md.Capabilities[0].ConsumesMimeTypes = []string{MimeTypeOCIArtifact}
+	assert.Empty(t, md.GetSBOMMimeTypes())
+	assert.Nil(t, md.ConvertCapability()[supportSBOMInput])
+}
@@ -43,6 +43,10 @@ const (
 	MimeTypeSBOMReport = "application/vnd.security.sbom.report+json; version=1.0"
 	// MimeTypeGenericVulnerabilityReport defines the MIME type for the generic report with enhanced information
 	MimeTypeGenericVulnerabilityReport = "application/vnd.security.vulnerability.report; version=1.1"
+	// MimeTypeSPDX defines the mime type for the SPDX JSON SBOM
+	MimeTypeSPDX = "application/spdx+json"
+	// MimeTypeCycloneDX defines the mime type for the CycloneDX JSON SBOM
+	MimeTypeCycloneDX = "application/vnd.cyclonedx+json"
 
 	ScanTypeVulnerability = "vulnerability"
 	ScanTypeSbom          = "sbom"
@@ -17,6 +17,7 @@ package vuln
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
 )
@@ -36,6 +37,9 @@ type Report struct {
 
 	// SBOM sbom content
 	SBOM map[string]interface{} `json:"sbom,omitempty"`
+	// SBOMDigest is the digest of the SBOM accessory the scanner matched the vulnerabilities against,
+	// empty when the artifact itself is scanned
+	SBOMDigest string `json:"sbom_digest,omitempty"`
 }
 
 // GetVulnerabilityItemList returns VulnerabilityItemList from the Vulnerabilities of report
@@ -134,7 +138,8 @@ func (l *VulnerabilityItemList) Items() []*VulnerabilityItem {
 	return l.items
 }
 
-// Add add item to the list when the item not exists in list
+// Add add item to the list when the item not exists in list, the existing item takes the
+// worst severity of the items when they are reported by different scanners with different severities
 func (l *VulnerabilityItemList) Add(items ...*VulnerabilityItem) {
 	if l.indexed == nil {
 		l.indexed = map[string]*VulnerabilityItem{}
@@ -143,7 +148,14 @@ func (l *VulnerabilityItemList) Add(items ...*VulnerabilityItem) {
 	for _, item := range items {
 		key := item.Key()
 		if v, ok := l.indexed[key]; ok {
-			v.ArtifactDigests = append(v.ArtifactDigests, item.ArtifactDigests...)
+			for _, dgst := range item.ArtifactDigests {
+				if !slices.Contains(v.ArtifactDigests, dgst) {
+					v.ArtifactDigests = append(v.ArtifactDigests, dgst)
+				}
+			}
+			if item.Severity.Code() > v.Severity.Code() {
+				v.Severity = item.Severity
+			}
 		} else {
 			l.items = append(l.items, item)
 			l.indexed[key] = item
@@ -113,3 +113,20 @@ func TestGetSummarySeverity(t *testing.T) {
 	assert.Equal(1, sum.Fixable)
 	assert.Equal(s, sum.Summary)
 }
+
+func TestVulnerabilityItemListAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	l := VulnerabilityItemList{}
+	// the same vulnerability reported by different scanners for the same artifact
+	l.Add(&VulnerabilityItem{ID: "cve1", Package: "openssl", Version: "1.1", Severity: Medium, ArtifactDigests: []string{"sha256:a"}})
+	l.Add(&VulnerabilityItem{ID: "cve1", Package: "openssl", Version: "1.1", Severity: High, ArtifactDigests: []string{"sha256:a"}})
+	l.Add(&VulnerabilityItem{ID: "cve1", Package: "openssl", Version: "1.1", Severity: Low, ArtifactDigests: []string{"sha256:b"}})
+	l.Add(&VulnerabilityItem{ID: "cve2", Package: "openssl", Version: "1.1", Severity: Low, ArtifactDigests: []string{"sha256:a"}})
+
+	items := l.Items()
+	assert.Len(items, 2)
+	assert.Equal(High, items[0].Severity)
+	assert.Equal([]string{"sha256:a", "sha256:b"}, items[0].ArtifactDigests)
+	assert.Equal(Low, items[1].Severity)
+}
@@ -16,6 +16,8 @@ package vulnerability
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
 	"sync"
 	"time"
 
@@ -35,7 +37,10 @@ import (
 	"github.com/goharbor/harbor/src/pkg/scan/postprocessors"
 	"github.com/goharbor/harbor/src/pkg/scan/report"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/goharbor/harbor/src/pkg/task"
+	"github.com/goharbor/harbor/src/pkg/vex"
+	vexModel "github.com/goharbor/harbor/src/pkg/vex/model"
 )
 
 func init() {
@@ -44,6 +49,7 @@ func init() {
 		ReportMgrFunc:      func() report.Manager { return report.Mgr },
 		TaskMgrFunc:        func() task.Manager { return task.Mgr },
 		ScanControllerFunc: func() scanCtl.Controller { return scanCtl.DefaultController },
+		VEXMgrFunc:         func() vex.Manager { return vex.Mgr },
 		cloneCtx:           orm.Clone,
 	})
 }
@@ -54,6 +60,7 @@ type scanHandler struct {
 	ReportMgrFunc      func() report.Manager
 	TaskMgrFunc        func() task.Manager
 	ScanControllerFunc func() scanCtl.Controller
+	VEXMgrFunc         func() vex.Manager
 	cloneCtx           func(ctx context.Context) context.Context
 }
 
@@ -249,12 +256,26 @@ func (h *scanHandler) RequiredPermissions() []*types.Policy {
 }
 
 // PostScan ...
-func (h *scanHandler) PostScan(ctx job.Context, _ *v1.ScanRequest, origRp *scan.Report, rawReport string,
+func (h *scanHandler) PostScan(ctx job.Context, sr *v1.ScanRequest, origRp *scan.Report, rawReport string,
 	_ time.Time, _ *model.Robot) (string, error) {
 	// use a new ormer here to use the short db connection
 	_, refreshedReport, err := postprocessors.Converter.ToRelationalSchema(ctx.SystemContext(), origRp.UUID,
 		origRp.RegistrationUUID, origRp.Digest, rawReport)
-	return refreshedReport, err
+	if err != nil || sr == nil || sr.SBOM == nil || len(refreshedReport) == 0 {
+		return refreshedReport, err
+	}
+
+	// record the SBOM submitted to the scanner in the report
+	rp := &vuln.Report{}
+	if err := json.Unmarshal([]byte(refreshedReport), rp); err != nil {
+		return "", err
+	}
+	rp.SBOMDigest = sr.SBOM.Digest
+	data, err := json.Marshal(rp)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 // URLParameter vulnerability doesn't require any scan report parameters
@@ -280,12 +301,29 @@ func (h *scanHandler) GetSummary(ctx context.Context, ar *artifact.Artifact, mim
 	if err != nil {
 		return nil, err
 	}
+	statements, err := h.listVEXStatements(ctx, ar, rps)
+	if err != nil {
+		return nil, err
+	}
 	summaries := make(map[string]interface{}, len(rps))
 	for _, rp := range rps {
 		sum, err := report.GenerateSummary(rp)
 		if err != nil {
 			return nil, err
 		}
+		// drop the vulnerabilities declared as not_affected or fixed by the VEX statements of the artifact
+		if s, ok := sum.(*vuln.NativeReportSummary); ok && s.VulnerabilityItemList != nil && len(statements) > 0 {
+			var scoped []*vexModel.Statement
+			for _, st := range statements {
+				if st.ArtifactDigest == ar.Digest || st.ArtifactDigest == rp.Digest {
+					scoped = append(scoped, st)
+				}
+			}
+			items, _ := vex.Filter(scoped, s.VulnerabilityItemList.Items())
+			l := &vuln.VulnerabilityItemList{}
+			l.Add(items...)
+			s.UpdateSeveritySummary(l)
+		}
 
 		if s, ok := summaries[rp.MimeType]; ok {
 			r, err := report.MergeSummary(rp.MimeType, s, sum)
@@ -302,6 +340,20 @@ func (h *scanHandler) GetSummary(ctx context.Context, ar *artifact.Artifact, mim
 	return summaries, nil
 }
 
+// listVEXStatements lists the VEX statements of the artifact's project about the artifact and the artifacts of the reports
+func (h *scanHandler) listVEXStatements(ctx context.Context, ar *artifact.Artifact, rps []*scan.Report) ([]*vexModel.Statement, error) {
+	if len(rps) == 0 {
+		return nil, nil
+	}
+	digests := []string{ar.Digest}
+	for _, rp := range rps {
+		if !slices.Contains(digests, rp.Digest) {
+			digests = append(digests, rp.Digest)
+		}
+	}
+	return h.VEXMgrFunc().ListByDigests(ctx, ar.ProjectID, digests...)
+}
+
 func (h *scanHandler) JobVendorType() string {
 	return job.ImageScanJobVendorType
 }
@@ -2,6 +2,7 @@ package vulnerability
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/goharbor/harbor/src/controller/artifact"
 	scanCtl "github.com/goharbor/harbor/src/controller/scan"
+	"github.com/goharbor/harbor/src/jobservice/job"
 	art "github.com/goharbor/harbor/src/pkg/artifact"
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scanner"
 	"github.com/goharbor/harbor/src/pkg/scan/report"
@@ -23,6 +25,7 @@ import (
 	accessorytesting "github.com/goharbor/harbor/src/testing/pkg/accessory"
 	reporttesting "github.com/goharbor/harbor/src/testing/pkg/scan/report"
 	tasktesting "github.com/goharbor/harbor/src/testing/pkg/task"
+	vextesting "github.com/goharbor/harbor/src/testing/pkg/vex"
 
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/lib/orm"
@@ -32,6 +35,9 @@ import (
 	"github.com/goharbor/harbor/src/pkg/scan/dao/scan"
 	"github.com/goharbor/harbor/src/pkg/scan/postprocessors"
 	v1 "github.com/goharbor/harbor/src/pkg/scan/rest/v1"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/goharbor/harbor/src/pkg/vex"
+	vexModel "github.com/goharbor/harbor/src/pkg/vex/model"
 	"github.com/goharbor/harbor/src/testing/jobservice"
 	ormtesting "github.com/goharbor/harbor/src/testing/lib/orm"
 	postprocessorstesting "github.com/goharbor/harbor/src/testing/pkg/scan/postprocessors"
@@ -69,6 +75,18 @@ func TestPostScan(t *testing.T) {
 	refreshedReport, err := v.PostScan(ctx, sr, origRp, rawReport, time.Now(), &model.Robot{})
 	assert.Equal(t, "original report", refreshedReport, "PostScan should return the refreshed report")
 	assert.Nil(t, err, "PostScan should not return an error")
+
+	// the SBOM submitted to the scanner is recorded in the report
+	mocker = &postprocessorstesting.NativeScanReportConverter{}
+	mocker.On("ToRelationalSchema", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", `{"severity":"High"}`, nil)
+	postprocessors.Converter = mocker
+	sr.SBOM = &v1.Artifact{Digest: "sha256:sbom"}
+	refreshedReport, err = v.PostScan(ctx, sr, origRp, rawReport, time.Now(), &model.Robot{})
+	require.NoError(t, err)
+	rp := &vuln.Report{}
+	require.NoError(t, json.Unmarshal([]byte(refreshedReport), rp))
+	assert.Equal(t, "sha256:sbom", rp.SBOMDigest)
+	assert.Equal(t, vuln.High, rp.Severity)
 }
 
 func TestScanHandler_RequiredPermissions(t *testing.T) {
@@ -142,6 +160,7 @@ type VulHandlerTestSuite struct {
 	taskMgr        *tasktesting.Manager
 	reportMgr      *reporttesting.Manager
 	scanController *scanCtlTest.Controller
+	vexMgr         *vextesting.Manager
 	handler        *scanHandler
 }
 
@@ -151,6 +170,7 @@ func (suite *VulHandlerTestSuite) SetupSuite() {
 	suite.taskMgr = &tasktesting.Manager{}
 	suite.scanController = &scanCtlTest.Controller{}
 	suite.reportMgr = &reporttesting.Manager{}
+	suite.vexMgr = &vextesting.Manager{}
 	suite.artifact = &artifact.Artifact{Artifact: art.Artifact{ID: 1}}
 	suite.artifact.Type = "IMAGE"
 	suite.artifact.ProjectID = 1
@@ -162,6 +182,7 @@ func (suite *VulHandlerTestSuite) SetupSuite() {
 		ReportMgrFunc:      func() report.Manager { return suite.reportMgr },
 		TaskMgrFunc:        func() task.Manager { return suite.taskMgr },
 		ScanControllerFunc: func() scanCtl.Controller { return suite.scanController },
+		VEXMgrFunc:         func() vex.Manager { return suite.vexMgr },
 		cloneCtx:           func(ctx context.Context) context.Context { return ctx },
 	}
 
@@ -188,11 +209,41 @@ func (suite *VulHandlerTestSuite) TestScanControllerGetSummary() {
 	}).Once()
 	mock.OnAnything(suite.taskMgr, "ListScanTasksByReportUUID").Return(nil, nil).Once()
 	mock.OnAnything(suite.scanController, "GetReport").Return(rpts, nil).Once()
+	suite.vexMgr.On("ListByDigests", mock.Anything, suite.artifact.ProjectID, suite.artifact.Digest, "").Return(nil, nil).Once()
 	sum, err := suite.handler.GetSummary(ctx, suite.artifact, []string{v1.MimeTypeGenericVulnerabilityReport})
 	require.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 1, len(sum))
 }
 
+// TestScanControllerGetSummaryWithVEX ...
+func (suite *VulHandlerTestSuite) TestScanControllerGetSummaryWithVEX() {
+	rpts := []*scan.Report{
+		{
+			UUID:     "uuid",
+			Digest:   suite.artifact.Digest,
+			MimeType: v1.MimeTypeGenericVulnerabilityReport,
+			Status:   job.SuccessStatus.String(),
+			Report: `{"severity":"Critical","vulnerabilities":[
+				{"id":"CVE-2024-0001","package":"openssl","version":"1.1","severity":"Critical"},
+				{"id":"CVE-2024-0002","package":"curl","version":"7.0","severity":"Low"}]}`,
+		},
+	}
+	statements := []*vexModel.Statement{
+		{ArtifactDigest: suite.artifact.Digest, CVEID: "CVE-2024-0001", Package: "openssl", Status: vexModel.StatusNotAffected},
+		// the statement about another artifact is ignored
+		{ArtifactDigest: "another-digest", CVEID: "CVE-2024-0002", Status: vexModel.StatusNotAffected},
+	}
+	ctx := orm.NewContext(nil, &ormtesting.FakeOrmer{})
+	mock.OnAnything(suite.scanController, "GetReport").Return(rpts, nil).Once()
+	suite.vexMgr.On("ListByDigests", mock.Anything, suite.artifact.ProjectID, suite.artifact.Digest).Return(statements, nil).Once()
+	sum, err := suite.handler.GetSummary(ctx, suite.artifact, []string{v1.MimeTypeGenericVulnerabilityReport})
+	require.NoError(suite.T(), err)
+	nativeSum, ok := sum[v1.MimeTypeGenericVulnerabilityReport].(*vuln.NativeReportSummary)
+	require.True(suite.T(), ok)
+	assert.Equal(suite.T(), 1, nativeSum.Summary.Total)
+	assert.Equal(suite.T(), vuln.Low, nativeSum.Severity)
+}
+
 func (suite *VulHandlerTestSuite) TestMakeReportPlaceHolder() {
 	ctx := orm.NewContext(nil, &ormtesting.FakeOrmer{})
 	art := &artifact.Artifact{Artifact: art.Artifact{ID: 1, Digest: "digest", ManifestMediaType: v1.MimeTypeDockerArtifact}}
@@ -37,16 +37,53 @@ const (
        sum(s.fixable_cnt)  fixable_cnt
 from artifact a
          left join scan_report s on a.digest = s.digest
-         where s.registration_uuid = ?`
+         where s.registration_uuid in (%s)`
+	// sql to query the security summary of the reports of multiple scanners, the vulnerability reported
+	// by several scanners for the same artifact and package is counted once with the worst severity
+	mergedSummarySQL = `select count(1) filter (where v.severity_level = 5) critical_cnt,
+       count(1) filter (where v.severity_level = 4) high_cnt,
+       count(1) filter (where v.severity_level = 3) medium_cnt,
+       count(1) filter (where v.severity_level = 2) low_cnt,
+       count(1) filter (where v.severity_level = 1) none_cnt,
+       count(1) filter (where v.severity_level = 0) unknown_cnt,
+       count(1) filter (where v.fixable) fixable_cnt
+from artifact a,
+     (` + mergedVulnerabilitySQL + `) v
+where a.digest = v.digest`
 	// sql to query the dangerous artifact
 	dangerousArtifactSQL = `select a.project_id project, a.repository_name repository, a.digest, s.critical_cnt, s.high_cnt, s.medium_cnt, s.low_cnt
 from artifact a,
      scan_report s
 where a.digest = s.digest
-  and s.registration_uuid = ?
+  and s.registration_uuid in (%s)
   and s.critical_cnt+s.high_cnt+s.medium_cnt+s.low_cnt > 0
 order by s.critical_cnt desc, s.high_cnt desc, s.medium_cnt desc, s.low_cnt desc
 limit 5`
+	// sql to query the dangerous artifact with the reports of multiple scanners
+	mergedDangerousArtifactSQL = `select a.project_id project, a.repository_name repository, a.digest,
+       count(1) filter (where v.severity_level = 5) critical_cnt,
+       count(1) filter (where v.severity_level = 4) high_cnt,
+       count(1) filter (where v.severity_level = 3) medium_cnt,
+       count(1) filter (where v.severity_level = 2) low_cnt
+from artifact a,
+     (` + mergedVulnerabilitySQL + `) v
+where a.digest = v.digest
+group by a.id, a.project_id, a.repository_name, a.digest
+having count(1) filter (where v.severity_level between 2 and 5) > 0
+order by critical_cnt desc, high_cnt desc, medium_cnt desc, low_cnt desc
+limit 5`
+	// sql to merge the vulnerabilities of the artifacts reported by multiple scanners, keeps the worst
+	// severity of the same CVE and package
+	mergedVulnerabilitySQL = `select s.digest, vr.cve_id, vr.package,
+       max(` + severityLevelSQL + `) severity_level,
+       bool_or(coalesce(vr.fixed_version, '') <> '') fixable
+from scan_report s,
+     report_vulnerability_record rvr,
+     vulnerability_record vr
+where s.uuid = rvr.report_uuid
+  and rvr.vuln_record_id = vr.id
+  and vr.registration_uuid in (%s)
+group by s.digest, vr.cve_id, vr.package`
 
 	// sql to query the total artifact count, include all artifacts in the artifact table
 	totalArtifactCountSQL = `SELECT COUNT(1) FROM artifact`
@@ -57,7 +94,7 @@ FROM artifact a
 WHERE EXISTS (SELECT 1
               FROM scan_report s
               WHERE a.digest = s.digest
-                AND s.registration_uuid = ?)`
+                AND s.registration_uuid in (%s))`
 
 	// sql to query the dangerous CVEs
 	// sort the CVEs by CVSS score and severity level, make sure it is referred by a report
@@ -68,21 +105,33 @@ WHERE EXISTS (SELECT 1
        vr.description,
        vr.package_version,
        vr.severity,
-       CASE vr.severity
-           WHEN 'Critical' THEN 5
-           WHEN 'High' THEN 4
-           WHEN 'Medium' THEN 3
-           WHEN 'Low' THEN 2
-           WHEN 'None' THEN 1
-           WHEN 'Unknown' THEN 0 END AS severity_level
+       ` + severityLevelSQL + ` AS severity_level
 FROM vulnerability_record vr
 WHERE EXISTS (SELECT 1 FROM report_vulnerability_record WHERE vuln_record_id = vr.id)
   AND vr.cvss_score_v3 IS NOT NULL
-  AND vr.registration_uuid = ?
+  AND vr.registration_uuid in (%s)
 ORDER BY vr.cvss_score_v3 DESC, severity_level DESC
+LIMIT 5`
+	// sql to query the dangerous CVEs reported by multiple scanners, the same CVE and package is returned once
+	// with the worst severity
+	mergedDangerousCVESQL = `SELECT *
+FROM (SELECT DISTINCT ON (vr.cve_id, vr.package) vr.id,
+             vr.cve_id,
+             vr.package,
+             vr.cvss_score_v3,
+             vr.description,
+             vr.package_version,
+             vr.severity,
+             ` + severityLevelSQL + ` AS severity_level
+      FROM vulnerability_record vr
+      WHERE EXISTS (SELECT 1 FROM report_vulnerability_record WHERE vuln_record_id = vr.id)
+        AND vr.cvss_score_v3 IS NOT NULL
+        AND vr.registration_uuid in (%s)
+      ORDER BY vr.cve_id, vr.package, severity_level DESC, vr.cvss_score_v3 DESC) t
+ORDER BY cvss_score_v3 DESC, severity_level DESC
 LIMIT 5`
 
-	// sql to query vulnerabilities
+	// sql to query vulnerabilities, exclude the ones declared as not_affected or fixed by the VEX statements of the artifact in its project
 	vulnerabilitySQL = `select  vr.cve_id, vr.cvss_score_v3, vr.package, a.repository_name, a.id artifact_id, a.digest, vr.package, vr.package_version, vr.severity, vr.fixed_version, vr.description, vr.urls, a.project_id
 from artifact a,
      scan_report s,
@@ -92,7 +141,46 @@ where a.digest = s.digest
   and s.uuid = rvr.report_uuid
   and rvr.vuln_record_id = vr.id
   and rvr.report_uuid is not null
-  and vr.registration_uuid = ? `
+  and vr.registration_uuid in (%s)
+  and not exists (select 1
+                  from vex_statement vs
+                  where vs.project_id = a.project_id
+                    and vs.artifact_digest = a.digest
+                    and vs.cve_id = vr.cve_id
+                    and vs.status in ('not_affected', 'fixed')
+                    and coalesce(vs.package, '') in ('', vr.package)
+                    and coalesce(vs.package_version, '') in ('', vr.package_version)) `
+	// the condition appended to the vulnerabilitySQL when querying the reports of multiple scanners,
+	// the vulnerability reported by several scanners for the same artifact and package is returned once
+	// with the worst severity
+	mergedVulnerabilityCondition = `  and not exists (select 1
+                  from scan_report s2,
+                       report_vulnerability_record rvr2,
+                       vulnerability_record vr2
+                  where s2.digest = a.digest
+                    and s2.uuid = rvr2.report_uuid
+                    and rvr2.vuln_record_id = vr2.id
+                    and vr2.registration_uuid in (%s)
+                    and vr2.cve_id = vr.cve_id
+                    and vr2.package is not distinct from vr.package
+                    and (` + severityLevelSQL2 + ` > ` + severityLevelSQL + `
+                      or ` + severityLevelSQL2 + ` = ` + severityLevelSQL + ` and vr2.id < vr.id)) `
+
+	// the level of the severity of the vulnerability record, the higher the more severe
+	severityLevelSQL = `CASE vr.severity
+           WHEN 'Critical' THEN 5
+           WHEN 'High' THEN 4
+           WHEN 'Medium' THEN 3
+           WHEN 'Low' THEN 2
+           WHEN 'None' THEN 1
+           ELSE 0 END`
+	severityLevelSQL2 = `CASE vr2.severity
+           WHEN 'Critical' THEN 5
+           WHEN 'High' THEN 4
+           WHEN 'Medium' THEN 3
+           WHEN 'Low' THEN 2
+           WHEN 'None' THEN 1
+           ELSE 0 END`
 
 	stringType = "string"
 	intType    = "int"
@@ -169,20 +257,20 @@ func tagFilter(ctx context.Context, _ string, query *q.Query) (sqlStr string, pa
 
 // SecurityHubDao defines the interface to access security hub data.
 type SecurityHubDao interface {
-	// Summary returns the summary of the scan cve reports.
-	Summary(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (*model.Summary, error)
+	// Summary returns the summary of the scan cve reports of the given scanners.
+	Summary(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (*model.Summary, error)
 	// DangerousCVEs get the top 5 most dangerous CVEs, return top 5 result
-	DangerousCVEs(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error)
-	// DangerousArtifacts returns top 5 dangerous artifact for the given scanner. return top 5 result
-	DangerousArtifacts(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error)
+	DangerousCVEs(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error)
+	// DangerousArtifacts returns top 5 dangerous artifact for the given scanners. return top 5 result
+	DangerousArtifacts(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error)
 	// TotalArtifactsCount return the count of total artifacts.
 	TotalArtifactsCount(ctx context.Context, projectID int64) (int64, error)
 	// ScannedArtifactsCount return the count of scanned artifacts.
-	ScannedArtifactsCount(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (int64, error)
+	ScannedArtifactsCount(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (int64, error)
 	// ListVulnerabilities search vulnerability record by cveID
-	ListVulnerabilities(ctx context.Context, registrationUUID string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error)
+	ListVulnerabilities(ctx context.Context, registrationUUIDs []string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error)
 	// CountVulnerabilities count the total vulnerabilities
-	CountVulnerabilities(ctx context.Context, registrationUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error)
+	CountVulnerabilities(ctx context.Context, registrationUUIDs []string, projectID int64, tuneCount bool, query *q.Query) (int64, error)
 }
 
 // New creates a new SecurityHubDao instance.
@@ -206,16 +294,17 @@ func (d *dao) TotalArtifactsCount(ctx context.Context, projectID int64) (int64,
 	return count, err
 }
 
-func (d *dao) Summary(ctx context.Context, scannerUUID string, projectID int64, _ *q.Query) (*model.Summary, error) {
-	if len(scannerUUID) == 0 || projectID != 0 {
+func (d *dao) Summary(ctx context.Context, scannerUUIDs []string, projectID int64, _ *q.Query) (*model.Summary, error) {
+	if len(scannerUUIDs) == 0 || projectID != 0 {
 		return nil, nil
 	}
 	o, err := orm.FromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	sqlStr, params := withScanners(summarySQL, mergedSummarySQL, scannerUUIDs)
 	var sum model.Summary
-	err = o.Raw(summarySQL, scannerUUID).QueryRow(&sum.CriticalCnt,
+	err = o.Raw(sqlStr, params).QueryRow(&sum.CriticalCnt,
 		&sum.HighCnt,
 		&sum.MediumCnt,
 		&sum.LowCnt,
@@ -224,21 +313,22 @@ func (d *dao) Summary(ctx context.Context, scannerUUID string, projectID int64,
 		&sum.FixableCnt)
 	return &sum, err
 }
-func (d *dao) DangerousArtifacts(ctx context.Context, scannerUUID string, projectID int64, _ *q.Query) ([]*model.DangerousArtifact, error) {
-	if len(scannerUUID) == 0 || projectID != 0 {
+func (d *dao) DangerousArtifacts(ctx context.Context, scannerUUIDs []string, projectID int64, _ *q.Query) ([]*model.DangerousArtifact, error) {
+	if len(scannerUUIDs) == 0 || projectID != 0 {
 		return nil, nil
 	}
 	o, err := orm.FromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	sqlStr, params := withScanners(dangerousArtifactSQL, mergedDangerousArtifactSQL, scannerUUIDs)
 	var artifacts []*model.DangerousArtifact
-	_, err = o.Raw(dangerousArtifactSQL, scannerUUID).QueryRows(&artifacts)
+	_, err = o.Raw(sqlStr, params).QueryRows(&artifacts)
 	return artifacts, err
 }
 
-func (d *dao) ScannedArtifactsCount(ctx context.Context, scannerUUID string, projectID int64, _ *q.Query) (int64, error) {
-	if len(scannerUUID) == 0 || projectID != 0 {
+func (d *dao) ScannedArtifactsCount(ctx context.Context, scannerUUIDs []string, projectID int64, _ *q.Query) (int64, error) {
+	if len(scannerUUIDs) == 0 || projectID != 0 {
 		return 0, nil
 	}
 	var cnt int64
@@ -246,11 +336,12 @@ func (d *dao) ScannedArtifactsCount(ctx context.Context, scannerUUID string, pro
 	if err != nil {
 		return cnt, err
 	}
-	err = o.Raw(scannedArtifactCountSQL, scannerUUID).QueryRow(&cnt)
+	sqlStr, params := withScanners(scannedArtifactCountSQL, scannedArtifactCountSQL, scannerUUIDs)
+	err = o.Raw(sqlStr, params).QueryRow(&cnt)
 	return cnt, err
 }
-func (d *dao) DangerousCVEs(ctx context.Context, scannerUUID string, projectID int64, _ *q.Query) ([]*scan.VulnerabilityRecord, error) {
-	if len(scannerUUID) == 0 || projectID != 0 {
+func (d *dao) DangerousCVEs(ctx context.Context, scannerUUIDs []string, projectID int64, _ *q.Query) ([]*scan.VulnerabilityRecord, error) {
+	if len(scannerUUIDs) == 0 || projectID != 0 {
 		return nil, nil
 	}
 	cves := make([]*scan.VulnerabilityRecord, 0)
@@ -258,21 +349,47 @@ func (d *dao) DangerousCVEs(ctx context.Context, scannerUUID string, projectID i
 	if err != nil {
 		return nil, err
 	}
-	_, err = o.Raw(dangerousCVESQL, scannerUUID).QueryRows(&cves)
+	sqlStr, params := withScanners(dangerousCVESQL, mergedDangerousCVESQL, scannerUUIDs)
+	_, err = o.Raw(sqlStr, params).QueryRows(&cves)
 	return cves, err
 }
 
+// withScanners populates the scanners into the sql, the merged sql is used when there are multiple scanners
+func withScanners(sqlStr, mergedSQLStr string, scannerUUIDs []string) (string, []interface{}) {
+	if len(scannerUUIDs) > 1 {
+		sqlStr = mergedSQLStr
+	}
+	var params []interface{}
+	for _, uuid := range scannerUUIDs {
+		params = append(params, uuid)
+	}
+	return fmt.Sprintf(sqlStr, orm.ParamPlaceholderForIn(len(scannerUUIDs))), params
+}
+
+// vulnerabilityQuery returns the sql and the params to query the vulnerabilities reported by the scanners
+func vulnerabilityQuery(scannerUUIDs []string) (string, []interface{}) {
+	sqlStr, params := withScanners(vulnerabilitySQL, vulnerabilitySQL, scannerUUIDs)
+	if len(scannerUUIDs) > 1 {
+		condition, conditionParams := withScanners(mergedVulnerabilityCondition, mergedVulnerabilityCondition, scannerUUIDs)
+		sqlStr += condition
+		params = append(params, conditionParams...)
+	}
+	return sqlStr, params
+}
+
 func countSQL(strSQL string) string {
 	return fmt.Sprintf(`select count(1) cnt from (%v) as t`, strSQL)
 }
 
-func (d *dao) CountVulnerabilities(ctx context.Context, registrationUUID string, _ int64, tuneCount bool, query *q.Query) (int64, error) {
+func (d *dao) CountVulnerabilities(ctx context.Context, registrationUUIDs []string, _ int64, tuneCount bool, query *q.Query) (int64, error) {
+	if len(registrationUUIDs) == 0 {
+		return 0, nil
+	}
 	o, err := orm.FromContext(ctx)
 	if err != nil {
 		return 0, err
 	}
-	sqlStr := vulnerabilitySQL
-	params := []interface{}{registrationUUID}
+	sqlStr, params := vulnerabilityQuery(registrationUUIDs)
 	if err := checkQFilter(query, filterMap); err != nil {
 		return 0, err
 	}
@@ -307,13 +424,15 @@ func (d *dao) countExceedLimit(ctx context.Context, sqlStr string, params []inte
 	return exceed, nil
 }
 
-func (d *dao) ListVulnerabilities(ctx context.Context, registrationUUID string, _ int64, query *q.Query) ([]*model.VulnerabilityItem, error) {
+func (d *dao) ListVulnerabilities(ctx context.Context, registrationUUIDs []string, _ int64, query *q.Query) ([]*model.VulnerabilityItem, error) {
+	if len(registrationUUIDs) == 0 {
+		return []*model.VulnerabilityItem{}, nil
+	}
 	o, err := orm.FromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	sqlStr := vulnerabilitySQL
-	params := []interface{}{registrationUUID}
+	sqlStr, params := vulnerabilityQuery(registrationUUIDs)
 	if err := checkQFilter(query, filterMap); err != nil {
 		return nil, err
 	}
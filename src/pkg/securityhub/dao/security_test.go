@@ -93,7 +93,7 @@ func (suite *SecurityDaoTestSuite) TearDownTest() {
 }
 
 func (suite *SecurityDaoTestSuite) TestGetSummary() {
-	s, err := suite.dao.Summary(suite.Context(), "ruuid", 0, nil)
+	s, err := suite.dao.Summary(suite.Context(), []string{"ruuid"}, 0, nil)
 	suite.Require().NoError(err)
 	suite.Equal(int64(50), s.CriticalCnt)
 	suite.Equal(int64(50), s.HighCnt)
@@ -101,7 +101,7 @@ func (suite *SecurityDaoTestSuite) TestGetSummary() {
 	suite.Equal(int64(20), s.FixableCnt)
 }
 func (suite *SecurityDaoTestSuite) TestGetMostDangerousArtifact() {
-	aList, err := suite.dao.DangerousArtifacts(orm.Context(), "ruuid", 0, nil)
+	aList, err := suite.dao.DangerousArtifacts(orm.Context(), []string{"ruuid"}, 0, nil)
 	suite.Require().NoError(err)
 	suite.Equal(1, len(aList))
 	suite.Equal(int64(50), aList[0].CriticalCnt)
@@ -111,13 +111,13 @@ func (suite *SecurityDaoTestSuite) TestGetMostDangerousArtifact() {
 }
 
 func (suite *SecurityDaoTestSuite) TestGetScannedArtifactCount() {
-	count, err := suite.dao.ScannedArtifactsCount(orm.Context(), "ruuid", 0, nil)
+	count, err := suite.dao.ScannedArtifactsCount(orm.Context(), []string{"ruuid"}, 0, nil)
 	suite.Require().NoError(err)
 	suite.Equal(int64(1), count)
 }
 
 func (suite *SecurityDaoTestSuite) TestGetDangerousCVEs() {
-	records, err := suite.dao.DangerousCVEs(suite.Context(), `uuid2`, 0, nil)
+	records, err := suite.dao.DangerousCVEs(suite.Context(), []string{`uuid2`}, 0, nil)
 	suite.NoError(err, "Error when fetching most dangerous artifact")
 	suite.Equal(5, len(records))
 }
@@ -203,17 +203,71 @@ func (suite *SecurityDaoTestSuite) TestCountArtifact() {
 	suite.Equal(int64(3), count)
 }
 func (suite *SecurityDaoTestSuite) TestCountVul() {
-	count, err := suite.dao.CountVulnerabilities(suite.Context(), "ruuid", 0, true, nil)
+	count, err := suite.dao.CountVulnerabilities(suite.Context(), []string{"ruuid"}, 0, true, nil)
 	suite.NoError(err)
 	suite.Equal(int64(1), count)
 }
 
 func (suite *SecurityDaoTestSuite) TestListVul() {
-	vuls, err := suite.dao.ListVulnerabilities(suite.Context(), "ruuid", 0, nil)
+	vuls, err := suite.dao.ListVulnerabilities(suite.Context(), []string{"ruuid"}, 0, nil)
 	suite.NoError(err)
 	suite.Equal(1, len(vuls))
 }
 
+// TestMergedVulnerabilities tests the vulnerabilities reported by multiple scanners
+func (suite *SecurityDaoTestSuite) TestMergedVulnerabilities() {
+	// the CVE reported by the scanner "ruuid" as Low is reported by the scanner "uuid3" as Critical
+	testDao.ExecuteBatchSQL([]string{
+		`update vulnerability_record set severity = 'Low', package = 'openssl' where id = 1`,
+		`insert into scanner_registration (name, url, uuid, auth) values('trivy3', 'https://www.trivy3.com', 'uuid3', 'empty')`,
+		`insert into scan_report(uuid, digest, registration_uuid, mime_type, critical_cnt) values('uuid3', 'digest1001', 'uuid3', 'application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0', 1)`,
+		`insert into vulnerability_record (id, cve_id, registration_uuid, cvss_score_v3, package, severity, fixed_version) values (1003, '2023-4567-12345', 'uuid3', 9.8, 'openssl', 'Critical', '3.0.1')`,
+		`insert into report_vulnerability_record (report_uuid, vuln_record_id) VALUES ('uuid3', 1003)`,
+	})
+	defer testDao.ExecuteBatchSQL([]string{
+		`delete from report_vulnerability_record where report_uuid = 'uuid3'`,
+		`delete from vulnerability_record where id = 1003`,
+		`delete from scan_report where uuid = 'uuid3'`,
+		`delete from scanner_registration where uuid = 'uuid3'`,
+	})
+
+	scanners := []string{"ruuid", "uuid3"}
+	vuls, err := suite.dao.ListVulnerabilities(suite.Context(), scanners, 0, q.New(q.KeyWords{"cve_id": "2023-4567-12345"}))
+	suite.Require().NoError(err)
+	suite.Require().Len(vuls, 1)
+	suite.Equal("Critical", vuls[0].Severity)
+
+	count, err := suite.dao.CountVulnerabilities(suite.Context(), scanners, 0, false, q.New(q.KeyWords{"cve_id": "2023-4567-12345"}))
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), count)
+
+	// the worst severity is filtered
+	count, err = suite.dao.CountVulnerabilities(suite.Context(), scanners, 0, false, q.New(q.KeyWords{"severity": "Low"}))
+	suite.Require().NoError(err)
+	suite.Equal(int64(0), count)
+
+	sum, err := suite.dao.Summary(suite.Context(), scanners, 0, nil)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), sum.CriticalCnt)
+	suite.Equal(int64(0), sum.LowCnt)
+	suite.Equal(int64(1), sum.FixableCnt)
+
+	artifacts, err := suite.dao.DangerousArtifacts(suite.Context(), scanners, 0, nil)
+	suite.Require().NoError(err)
+	suite.Require().Len(artifacts, 1)
+	suite.Equal(int64(1), artifacts[0].CriticalCnt)
+	suite.Equal(int64(0), artifacts[0].LowCnt)
+
+	cves, err := suite.dao.DangerousCVEs(suite.Context(), scanners, 0, nil)
+	suite.Require().NoError(err)
+	suite.Require().Len(cves, 1)
+	suite.Equal("Critical", cves[0].Severity)
+
+	scanned, err := suite.dao.ScannedArtifactsCount(suite.Context(), scanners, 0, nil)
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), scanned)
+}
+
 func (suite *SecurityDaoTestSuite) TestTagFilter() {
 	type args struct {
 		ctx   context.Context
@@ -31,19 +31,19 @@ var (
 // Manager is used to manage the security manager.
 type Manager interface {
 	// Summary returns the summary of the scan cve reports.
-	Summary(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (*model.Summary, error)
-	// DangerousArtifacts returns the most dangerous artifact for the given scanner.
-	DangerousArtifacts(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error)
+	Summary(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (*model.Summary, error)
+	// DangerousArtifacts returns the most dangerous artifact for the given scanners.
+	DangerousArtifacts(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error)
 	// TotalArtifactsCount return the count of artifacts.
 	TotalArtifactsCount(ctx context.Context, projectID int64) (int64, error)
 	// ScannedArtifactsCount return the count of scanned artifacts.
-	ScannedArtifactsCount(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (int64, error)
-	// DangerousCVEs returns the most dangerous CVEs for the given scanner.
-	DangerousCVEs(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error)
+	ScannedArtifactsCount(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (int64, error)
+	// DangerousCVEs returns the most dangerous CVEs for the given scanners.
+	DangerousCVEs(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error)
 	// TotalVuls return the count of vulnerabilities
-	TotalVuls(ctx context.Context, scannerUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error)
+	TotalVuls(ctx context.Context, scannerUUIDs []string, projectID int64, tuneCount bool, query *q.Query) (int64, error)
 	// ListVuls returns vulnerabilities list
-	ListVuls(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error)
+	ListVuls(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error)
 }
 
 // NewManager news security manager.
@@ -62,26 +62,26 @@ func (s *securityManager) TotalArtifactsCount(ctx context.Context, projectID int
 	return s.dao.TotalArtifactsCount(ctx, projectID)
 }
 
-func (s *securityManager) Summary(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (*model.Summary, error) {
-	return s.dao.Summary(ctx, scannerUUID, projectID, query)
+func (s *securityManager) Summary(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (*model.Summary, error) {
+	return s.dao.Summary(ctx, scannerUUIDs, projectID, query)
 }
 
-func (s *securityManager) DangerousArtifacts(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error) {
-	return s.dao.DangerousArtifacts(ctx, scannerUUID, projectID, query)
+func (s *securityManager) DangerousArtifacts(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error) {
+	return s.dao.DangerousArtifacts(ctx, scannerUUIDs, projectID, query)
 }
 
-func (s *securityManager) ScannedArtifactsCount(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (int64, error) {
-	return s.dao.ScannedArtifactsCount(ctx, scannerUUID, projectID, query)
+func (s *securityManager) ScannedArtifactsCount(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (int64, error) {
+	return s.dao.ScannedArtifactsCount(ctx, scannerUUIDs, projectID, query)
 }
 
-func (s *securityManager) DangerousCVEs(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error) {
-	return s.dao.DangerousCVEs(ctx, scannerUUID, projectID, query)
+func (s *securityManager) DangerousCVEs(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error) {
+	return s.dao.DangerousCVEs(ctx, scannerUUIDs, projectID, query)
 }
 
-func (s *securityManager) TotalVuls(ctx context.Context, scannerUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
-	return s.dao.CountVulnerabilities(ctx, scannerUUID, projectID, tuneCount, query)
+func (s *securityManager) TotalVuls(ctx context.Context, scannerUUIDs []string, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
+	return s.dao.CountVulnerabilities(ctx, scannerUUIDs, projectID, tuneCount, query)
 }
 
-func (s *securityManager) ListVuls(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error) {
-	return s.dao.ListVulnerabilities(ctx, scannerUUID, projectID, query)
+func (s *securityManager) ListVuls(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error) {
+	return s.dao.ListVulnerabilities(ctx, scannerUUIDs, projectID, query)
 }
@@ -0,0 +1,100 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+)
+
+// DAO defines the interface to access the VEX statement data model
+type DAO interface {
+	// Create the VEX statement
+	Create(ctx context.Context, statement *model.Statement) (int64, error)
+	// List the VEX statements
+	List(ctx context.Context, query *q.Query) ([]*model.Statement, error)
+	// DeleteBy deletes the VEX statements specified by the query
+	DeleteBy(ctx context.Context, query *q.Query) (int64, error)
+	// DeleteByDigests deletes the VEX statements of the project about the artifacts specified by the digests
+	// and the ones coming from the VEX accessories specified by the digests, the statements are kept
+	// when the artifact or the accessory with the same digest still exists in the project
+	DeleteByDigests(ctx context.Context, projectID int64, digests ...string) error
+}
+
+// New creates a default implementation for DAO
+func New() DAO {
+	return &dao{}
+}
+
+type dao struct{}
+
+func (d *dao) Create(ctx context.Context, statement *model.Statement) (int64, error) {
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return ormer.Insert(statement)
+}
+
+func (d *dao) List(ctx context.Context, query *q.Query) ([]*model.Statement, error) {
+	statements := []*model.Statement{}
+	qs, err := orm.QuerySetter(ctx, &model.Statement{}, query)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = qs.All(&statements); err != nil {
+		return nil, err
+	}
+	return statements, nil
+}
+
+func (d *dao) DeleteBy(ctx context.Context, query *q.Query) (int64, error) {
+	qs, err := orm.QuerySetter(ctx, &model.Statement{}, query)
+	if err != nil {
+		return 0, err
+	}
+	return qs.Delete()
+}
+
+func (d *dao) DeleteByDigests(ctx context.Context, projectID int64, digests ...string) error {
+	if len(digests) == 0 {
+		return nil
+	}
+	ormer, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	placeholders := orm.ParamPlaceholderForIn(len(digests))
+	sql := fmt.Sprintf(`DELETE FROM vex_statement vs WHERE vs.project_id = ? AND (
+  (vs.artifact_digest IN (%s)
+    AND NOT EXISTS (SELECT 1 FROM artifact a WHERE a.project_id = vs.project_id AND a.digest = vs.artifact_digest))
+  OR (vs.source = ? AND vs.source_digest IN (%s)
+    AND NOT EXISTS (SELECT 1 FROM artifact a WHERE a.project_id = vs.project_id AND a.digest = vs.source_digest)))`,
+		placeholders, placeholders)
+	params := []interface{}{projectID}
+	for _, digest := range digests {
+		params = append(params, digest)
+	}
+	params = append(params, model.SourceAccessory)
+	for _, digest := range digests {
+		params = append(params, digest)
+	}
+	_, err = ormer.Raw(sql, params...).Exec()
+	return err
+}
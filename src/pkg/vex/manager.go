@@ -0,0 +1,112 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vex
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/goharbor/harbor/src/pkg/vex/dao"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+)
+
+// Mgr is the global VEX statement manager
+var Mgr = NewManager()
+
+// Manager manages the VEX statements of the artifacts
+type Manager interface {
+	// Create the VEX statements
+	Create(ctx context.Context, statements ...*model.Statement) error
+	// List the VEX statements
+	List(ctx context.Context, query *q.Query) ([]*model.Statement, error)
+	// ListByDigests lists the VEX statements of the project about the artifacts specified by the digests
+	ListByDigests(ctx context.Context, projectID int64, digests ...string) ([]*model.Statement, error)
+	// DeleteBy deletes the VEX statements specified by the query
+	DeleteBy(ctx context.Context, query *q.Query) (int64, error)
+	// DeleteByDigests deletes the VEX statements of the project about the artifacts specified by the digests
+	// and the ones coming from the VEX accessories specified by the digests, unless the artifacts still exist in the project
+	DeleteByDigests(ctx context.Context, projectID int64, digests ...string) error
+}
+
+// NewManager creates an instance of the default VEX statement manager
+func NewManager() Manager {
+	return &manager{
+		dao: dao.New(),
+	}
+}
+
+type manager struct {
+	dao dao.DAO
+}
+
+func (m *manager) Create(ctx context.Context, statements ...*model.Statement) error {
+	for _, s := range statements {
+		id, err := m.dao.Create(ctx, s)
+		if err != nil {
+			return err
+		}
+		s.ID = id
+	}
+	return nil
+}
+
+func (m *manager) List(ctx context.Context, query *q.Query) ([]*model.Statement, error) {
+	return m.dao.List(ctx, query)
+}
+
+func (m *manager) ListByDigests(ctx context.Context, projectID int64, digests ...string) ([]*model.Statement, error) {
+	if len(digests) == 0 {
+		return nil, nil
+	}
+	var values []interface{}
+	for _, digest := range digests {
+		values = append(values, digest)
+	}
+	return m.dao.List(ctx, q.New(q.KeyWords{
+		"project_id":      projectID,
+		"artifact_digest": &q.OrList{Values: values},
+	}))
+}
+
+func (m *manager) DeleteBy(ctx context.Context, query *q.Query) (int64, error) {
+	return m.dao.DeleteBy(ctx, query)
+}
+
+func (m *manager) DeleteByDigests(ctx context.Context, projectID int64, digests ...string) error {
+	return m.dao.DeleteByDigests(ctx, projectID, digests...)
+}
+
+// Filter splits the vulnerability items into the ones still affecting the artifact
+// and the ones suppressed by the not_affected or fixed statements
+func Filter(statements []*model.Statement, items []*vuln.VulnerabilityItem) (affected, suppressed []*vuln.VulnerabilityItem) {
+	for _, item := range items {
+		if suppressedBy(statements, item) {
+			suppressed = append(suppressed, item)
+		} else {
+			affected = append(affected, item)
+		}
+	}
+	return affected, suppressed
+}
+
+func suppressedBy(statements []*model.Statement, item *vuln.VulnerabilityItem) bool {
+	for _, s := range statements {
+		if s.Suppresses(item) {
+			return true
+		}
+	}
+	return false
+}
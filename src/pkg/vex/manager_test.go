@@ -0,0 +1,96 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vex
+
+import (
+	"context"
+	"testing"
+
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+	"github.com/goharbor/harbor/src/testing/mock"
+	"github.com/goharbor/harbor/src/testing/pkg/vex/dao"
+)
+
+type managerTestSuite struct {
+	suite.Suite
+	mgr *manager
+	dao *dao.DAO
+}
+
+func (m *managerTestSuite) SetupTest() {
+	m.dao = &dao.DAO{}
+	m.mgr = &manager{
+		dao: m.dao,
+	}
+}
+
+func (m *managerTestSuite) TestCreate() {
+	m.dao.On("Create", mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+	m.dao.On("Create", mock.Anything, mock.Anything).Return(int64(2), nil).Once()
+	statements := []*model.Statement{{CVEID: "CVE-2023-0001"}, {CVEID: "CVE-2023-0002"}}
+	m.Require().NoError(m.mgr.Create(context.Background(), statements...))
+	m.Equal(int64(1), statements[0].ID)
+	m.Equal(int64(2), statements[1].ID)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestListByDigests() {
+	m.dao.On("List", mock.Anything, testifymock.MatchedBy(func(query *q.Query) bool {
+		l, ok := query.Keywords["artifact_digest"].(*q.OrList)
+		return ok && len(l.Values) == 2 && query.Keywords["project_id"] == int64(1)
+	})).Return([]*model.Statement{{CVEID: "CVE-2023-0001"}}, nil).Once()
+	statements, err := m.mgr.ListByDigests(context.Background(), 1, "sha256:a", "sha256:b")
+	m.Require().NoError(err)
+	m.Len(statements, 1)
+
+	statements, err = m.mgr.ListByDigests(context.Background(), 1)
+	m.Require().NoError(err)
+	m.Empty(statements)
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestDeleteByDigests() {
+	m.dao.On("DeleteByDigests", mock.Anything, int64(1), "sha256:a").Return(nil).Once()
+	m.NoError(m.mgr.DeleteByDigests(context.Background(), 1, "sha256:a"))
+	m.dao.AssertExpectations(m.T())
+}
+
+func (m *managerTestSuite) TestFilter() {
+	items := []*vuln.VulnerabilityItem{
+		{ID: "CVE-2023-0001", Package: "openssl", Version: "3.0.11"},
+		{ID: "CVE-2023-0001", Package: "openssl", Version: "3.0.12"},
+		{ID: "CVE-2023-0002", Package: "zlib", Version: "1.2.13"},
+		{ID: "CVE-2023-0003", Package: "curl", Version: "8.0.0"},
+		{ID: "CVE-2023-0004", Package: "git", Version: "2.39.0"},
+	}
+	statements := []*model.Statement{
+		{CVEID: "CVE-2023-0001", Package: "openssl", PackageVersion: "3.0.11", Status: model.StatusNotAffected},
+		{CVEID: "CVE-2023-0002", Status: model.StatusFixed},
+		{CVEID: "CVE-2023-0003", Status: model.StatusUnderInvestigation},
+		{CVEID: "CVE-2023-0004", Package: "curl", Status: model.StatusNotAffected},
+	}
+	affected, suppressed := Filter(statements, items)
+	m.Equal([]*vuln.VulnerabilityItem{items[1], items[3], items[4]}, affected)
+	m.Equal([]*vuln.VulnerabilityItem{items[0], items[2]}, suppressed)
+}
+
+func TestManager(t *testing.T) {
+	suite.Run(t, &managerTestSuite{})
+}
@@ -0,0 +1,98 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
+)
+
+func init() {
+	orm.RegisterModel(&Statement{})
+}
+
+const (
+	// FormatOpenVEX is the format of the OpenVEX documents
+	FormatOpenVEX = "openvex"
+	// FormatCycloneDX is the format of the CycloneDX VEX documents
+	FormatCycloneDX = "cyclonedx"
+
+	// SourceAccessory means the statement comes from the VEX accessory attached to the artifact
+	SourceAccessory = "accessory"
+	// SourceAPI means the statement comes from the VEX document uploaded via API
+	SourceAPI = "api"
+
+	// StatusNotAffected means the product is not affected by the vulnerability
+	StatusNotAffected = "not_affected"
+	// StatusAffected means the product is affected by the vulnerability
+	StatusAffected = "affected"
+	// StatusFixed means the product contains the fix of the vulnerability
+	StatusFixed = "fixed"
+	// StatusUnderInvestigation means it is not yet known whether the product is affected
+	StatusUnderInvestigation = "under_investigation"
+)
+
+// Statement is a VEX statement about one vulnerability of the artifact,
+// optionally scoped to the package the vulnerability is reported in
+type Statement struct {
+	ID int64 `orm:"pk;auto;column(id)" json:"id"`
+	// ProjectID is the project of the VEX accessory or the artifact the document is uploaded to,
+	// the statement only applies to the artifacts in this project
+	ProjectID      int64  `orm:"column(project_id)" json:"project_id"`
+	ArtifactDigest string `orm:"column(artifact_digest)" json:"artifact_digest"`
+	// Source is where the statement comes from, SourceAccessory or SourceAPI
+	Source string `orm:"column(source)" json:"source"`
+	// SourceDigest is the digest of the accessory artifact for SourceAccessory
+	// or the digest of the uploaded document for SourceAPI
+	SourceDigest string `orm:"column(source_digest)" json:"source_digest"`
+	Format       string `orm:"column(format)" json:"format"`
+	DocumentID   string `orm:"column(document_id)" json:"document_id"`
+	CVEID        string `orm:"column(cve_id)" json:"cve_id"`
+	// Package is the name of the package, empty means all the packages of the artifact
+	Package string `orm:"column(package)" json:"package"`
+	// PackageVersion is the version of the package, empty means all the versions
+	PackageVersion string    `orm:"column(package_version)" json:"package_version"`
+	PURL           string    `orm:"column(purl)" json:"purl"`
+	Status         string    `orm:"column(status)" json:"status"`
+	Justification  string    `orm:"column(justification)" json:"justification"`
+	CreationTime   time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName ...
+func (s *Statement) TableName() string {
+	return "vex_statement"
+}
+
+// Suppressing returns true when the statement declares the vulnerability doesn't affect the artifact
+func (s *Statement) Suppressing() bool {
+	return s.Status == StatusNotAffected || s.Status == StatusFixed
+}
+
+// Suppresses returns true when the statement silences the vulnerability item
+func (s *Statement) Suppresses(item *vuln.VulnerabilityItem) bool {
+	if item == nil || !s.Suppressing() || s.CVEID != item.ID {
+		return false
+	}
+	if len(s.Package) > 0 && s.Package != item.Package {
+		return false
+	}
+	if len(s.PackageVersion) > 0 && s.PackageVersion != item.Version {
+		return false
+	}
+	return true
+}
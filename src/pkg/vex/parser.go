@@ -0,0 +1,324 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vex
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+)
+
+var digestPattern = regexp.MustCompile(`(?i)sha256(?::|%3A)([a-f0-9]{64})`)
+
+// cycloneDXStates maps the CycloneDX analysis states to the VEX statuses
+var cycloneDXStates = map[string]string{
+	"not_affected":           model.StatusNotAffected,
+	"false_positive":         model.StatusNotAffected,
+	"resolved":               model.StatusFixed,
+	"resolved_with_pedigree": model.StatusFixed,
+	"exploitable":            model.StatusAffected,
+	"in_triage":              model.StatusUnderInvestigation,
+}
+
+// Parse parses the OpenVEX or CycloneDX VEX document and returns the statements
+// about the artifact specified by the digest, the statements about other products are dropped
+func Parse(data []byte, artifactDigest string) ([]*model.Statement, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.BadRequestError(err).WithMessage("the VEX document is not a valid JSON object")
+	}
+	if _, ok := fields["bomFormat"]; ok {
+		return parseCycloneDX(data, artifactDigest)
+	}
+	if _, ok := fields["statements"]; ok {
+		return parseOpenVEX(data, artifactDigest)
+	}
+	return nil, errors.BadRequestError(nil).WithMessage("unsupported VEX document, only OpenVEX and CycloneDX VEX are supported")
+}
+
+type openVEXDocument struct {
+	ID         string              `json:"@id"`
+	Statements []*openVEXStatement `json:"statements"`
+}
+
+type openVEXStatement struct {
+	Vulnerability json.RawMessage   `json:"vulnerability"`
+	Products      []json.RawMessage `json:"products"`
+	Subcomponents []json.RawMessage `json:"subcomponents"`
+	Status        string            `json:"status"`
+	Justification string            `json:"justification"`
+}
+
+// openVEXComponent is the product or the subcomponent of the OpenVEX statement,
+// it is a plain identifier string in the early OpenVEX specifications
+type openVEXComponent struct {
+	ID            string            `json:"@id"`
+	Identifiers   map[string]string `json:"identifiers"`
+	Subcomponents []json.RawMessage `json:"subcomponents"`
+}
+
+func (c *openVEXComponent) identifier() string {
+	if purl := c.Identifiers["purl"]; len(purl) > 0 {
+		return purl
+	}
+	return c.ID
+}
+
+func parseOpenVEXComponent(raw json.RawMessage) (*openVEXComponent, error) {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return &openVEXComponent{ID: id}, nil
+	}
+	c := &openVEXComponent{}
+	if err := json.Unmarshal(raw, c); err != nil {
+		return nil, errors.BadRequestError(err).WithMessage("invalid component in the OpenVEX statement")
+	}
+	return c, nil
+}
+
+func parseOpenVEXVulnerability(raw json.RawMessage) string {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return name
+	}
+	v := struct {
+		ID   string `json:"@id"`
+		Name string `json:"name"`
+	}{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+	if len(v.Name) > 0 {
+		return v.Name
+	}
+	return v.ID
+}
+
+func parseOpenVEX(data []byte, artifactDigest string) ([]*model.Statement, error) {
+	doc := &openVEXDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, errors.BadRequestError(err).WithMessage("invalid OpenVEX document")
+	}
+	b := &builder{format: model.FormatOpenVEX, documentID: doc.ID, indexed: map[string]bool{}}
+	for _, s := range doc.Statements {
+		cve := parseOpenVEXVulnerability(s.Vulnerability)
+		if len(cve) == 0 {
+			return nil, errors.BadRequestError(nil).WithMessage("the vulnerability of the OpenVEX statement is required")
+		}
+		switch s.Status {
+		case model.StatusNotAffected, model.StatusAffected, model.StatusFixed, model.StatusUnderInvestigation:
+		default:
+			return nil, errors.BadRequestError(nil).WithMessagef("invalid status %q of the OpenVEX statement for %s", s.Status, cve)
+		}
+		products := []*openVEXComponent{{}}
+		if len(s.Products) > 0 {
+			products = nil
+			for _, raw := range s.Products {
+				p, err := parseOpenVEXComponent(raw)
+				if err != nil {
+					return nil, err
+				}
+				products = append(products, p)
+			}
+		}
+		for _, p := range products {
+			if !productMatches(p.identifier(), artifactDigest) {
+				continue
+			}
+			subs := make([]json.RawMessage, 0, len(p.Subcomponents)+len(s.Subcomponents))
+			subs = append(subs, p.Subcomponents...)
+			subs = append(subs, s.Subcomponents...)
+			if len(subs) == 0 {
+				// the product itself may be a package rather than the artifact
+				ref := ""
+				if isPackage(p.identifier()) {
+					ref = p.identifier()
+				}
+				b.add(cve, s.Status, s.Justification, ref)
+				continue
+			}
+			for _, raw := range subs {
+				sub, err := parseOpenVEXComponent(raw)
+				if err != nil {
+					return nil, err
+				}
+				b.add(cve, s.Status, s.Justification, sub.identifier())
+			}
+		}
+	}
+	return b.statements, nil
+}
+
+type cycloneDXDocument struct {
+	BOMFormat    string `json:"bomFormat"`
+	SerialNumber string `json:"serialNumber"`
+	Metadata     struct {
+		Component *cycloneDXComponent `json:"component"`
+	} `json:"metadata"`
+	Components      []*cycloneDXComponent `json:"components"`
+	Vulnerabilities []*struct {
+		ID       string `json:"id"`
+		Analysis struct {
+			State         string `json:"state"`
+			Justification string `json:"justification"`
+		} `json:"analysis"`
+		Affects []struct {
+			Ref string `json:"ref"`
+		} `json:"affects"`
+	} `json:"vulnerabilities"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef     string                `json:"bom-ref"`
+	Name       string                `json:"name"`
+	Version    string                `json:"version"`
+	PURL       string                `json:"purl"`
+	Components []*cycloneDXComponent `json:"components"`
+}
+
+func (c *cycloneDXComponent) index(components map[string]*cycloneDXComponent) {
+	if len(c.BOMRef) > 0 {
+		components[c.BOMRef] = c
+	}
+	for _, sub := range c.Components {
+		sub.index(components)
+	}
+}
+
+func parseCycloneDX(data []byte, artifactDigest string) ([]*model.Statement, error) {
+	doc := &cycloneDXDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, errors.BadRequestError(err).WithMessage("invalid CycloneDX VEX document")
+	}
+	if doc.BOMFormat != "CycloneDX" {
+		return nil, errors.BadRequestError(nil).WithMessagef("unsupported bom format %q", doc.BOMFormat)
+	}
+	b := &builder{format: model.FormatCycloneDX, documentID: doc.SerialNumber, indexed: map[string]bool{}}
+	product := doc.Metadata.Component
+	if product != nil && (!productMatches(product.PURL, artifactDigest) || !productMatches(product.BOMRef, artifactDigest)) {
+		return b.statements, nil
+	}
+	components := map[string]*cycloneDXComponent{}
+	for _, c := range doc.Components {
+		c.index(components)
+	}
+	for _, v := range doc.Vulnerabilities {
+		if len(v.ID) == 0 {
+			return nil, errors.BadRequestError(nil).WithMessage("the id of the CycloneDX vulnerability is required")
+		}
+		status, ok := cycloneDXStates[v.Analysis.State]
+		if !ok {
+			// the vulnerability without analysis is the scan finding rather than a VEX statement
+			continue
+		}
+		if len(v.Affects) == 0 {
+			b.add(v.ID, status, v.Analysis.Justification, "")
+			continue
+		}
+		for _, affect := range v.Affects {
+			ref := affect.Ref
+			// strip the serial number and version of the BOM-Link
+			if strings.HasPrefix(ref, "urn:cdx:") {
+				if i := strings.Index(ref, "#"); i >= 0 {
+					ref, _ = url.PathUnescape(ref[i+1:])
+				}
+			}
+			switch c, ok := components[ref]; {
+			case product != nil && ref == product.BOMRef:
+				b.add(v.ID, status, v.Analysis.Justification, "")
+			case ok && len(c.PURL) > 0:
+				b.add(v.ID, status, v.Analysis.Justification, c.PURL)
+			case ok:
+				b.addPackage(v.ID, status, v.Analysis.Justification, c.Name, c.Version, "")
+			default:
+				b.add(v.ID, status, v.Analysis.Justification, ref)
+			}
+		}
+	}
+	return b.statements, nil
+}
+
+// builder collects the statements and drops the duplicated ones
+type builder struct {
+	format     string
+	documentID string
+	statements []*model.Statement
+	indexed    map[string]bool
+}
+
+// add the statement of the package referred by the purl or the name, empty ref means all the packages
+func (b *builder) add(cve, status, justification, ref string) {
+	if !strings.HasPrefix(ref, "pkg:") {
+		b.addPackage(cve, status, justification, ref, "", "")
+		return
+	}
+	name, version := parsePURL(ref)
+	b.addPackage(cve, status, justification, name, version, ref)
+}
+
+func (b *builder) addPackage(cve, status, justification, name, version, purl string) {
+	key := strings.Join([]string{cve, name, version, status}, "|")
+	if b.indexed[key] {
+		return
+	}
+	b.indexed[key] = true
+	b.statements = append(b.statements, &model.Statement{
+		Format:         b.format,
+		DocumentID:     b.documentID,
+		CVEID:          cve,
+		Package:        name,
+		PackageVersion: version,
+		PURL:           purl,
+		Status:         status,
+		Justification:  justification,
+	})
+}
+
+// parsePURL returns the name and the version of the package URL,
+// e.g. pkg:deb/debian/openssl@1.1.1n-0+deb11u3?arch=amd64
+func parsePURL(purl string) (name, version string) {
+	s := strings.TrimPrefix(purl, "pkg:")
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		version, _ = url.PathUnescape(s[i+1:])
+		s = s[:i]
+	}
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	name, _ = url.PathUnescape(s)
+	return name, version
+}
+
+// isPackage returns true when the identifier refers a package rather than an artifact
+func isPackage(id string) bool {
+	return strings.HasPrefix(id, "pkg:") && !strings.HasPrefix(id, "pkg:oci/") && !strings.HasPrefix(id, "pkg:docker/")
+}
+
+// productMatches returns false when the product identifier carries a digest other than the artifact's,
+// the product without digest is scoped by the artifact the document is attached to
+func productMatches(id, artifactDigest string) bool {
+	m := digestPattern.FindStringSubmatch(id)
+	if m == nil {
+		return true
+	}
+	return "sha256:"+strings.ToLower(m[1]) == artifactDigest
+}
@@ -0,0 +1,150 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+)
+
+const artifactDigest = "sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"
+
+func TestParseOpenVEX(t *testing.T) {
+	doc := `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/1",
+  "statements": [
+    {
+      "vulnerability": {"name": "CVE-2023-0001"},
+      "products": [
+        {
+          "@id": "pkg:oci/nginx@sha256%3A5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270",
+          "subcomponents": [{"@id": "pkg:deb/debian/openssl@3.0.11-1?arch=amd64"}]
+        }
+      ],
+      "status": "not_affected",
+      "justification": "vulnerable_code_not_in_execute_path"
+    },
+    {
+      "vulnerability": {"name": "CVE-2023-0002"},
+      "products": [{"@id": "pkg:oci/nginx@sha256%3Aee1d00c5250b5a886b09be2d5f9506add35dfb557f1ef37a7e4b8f0138f32956"}],
+      "status": "not_affected"
+    },
+    {
+      "vulnerability": "CVE-2023-0003",
+      "products": ["pkg:golang/golang.org/x/net@v0.17.0"],
+      "status": "fixed"
+    },
+    {
+      "vulnerability": {"name": "CVE-2023-0004"},
+      "status": "under_investigation"
+    }
+  ]
+}`
+	statements, err := Parse([]byte(doc), artifactDigest)
+	require.NoError(t, err)
+	require.Len(t, statements, 3)
+
+	assert.Equal(t, "CVE-2023-0001", statements[0].CVEID)
+	assert.Equal(t, "openssl", statements[0].Package)
+	assert.Equal(t, "3.0.11-1", statements[0].PackageVersion)
+	assert.Equal(t, "pkg:deb/debian/openssl@3.0.11-1?arch=amd64", statements[0].PURL)
+	assert.Equal(t, model.StatusNotAffected, statements[0].Status)
+	assert.Equal(t, "vulnerable_code_not_in_execute_path", statements[0].Justification)
+	assert.Equal(t, model.FormatOpenVEX, statements[0].Format)
+	assert.Equal(t, "https://example.com/vex/1", statements[0].DocumentID)
+
+	// the product is a package
+	assert.Equal(t, "CVE-2023-0003", statements[1].CVEID)
+	assert.Equal(t, "net", statements[1].Package)
+	assert.Equal(t, "v0.17.0", statements[1].PackageVersion)
+	assert.Equal(t, model.StatusFixed, statements[1].Status)
+
+	// no product means the artifact the document is attached to
+	assert.Equal(t, "CVE-2023-0004", statements[2].CVEID)
+	assert.Empty(t, statements[2].Package)
+
+	_, err = Parse([]byte(`{"statements": [{"vulnerability": {"name": "CVE-2023-0001"}, "status": "unknown"}]}`), artifactDigest)
+	assert.True(t, errors.IsErr(err, errors.BadRequestCode))
+}
+
+func TestParseCycloneDX(t *testing.T) {
+	doc := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "serialNumber": "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+  "metadata": {
+    "component": {"bom-ref": "image", "name": "nginx", "purl": "pkg:oci/nginx@sha256:5b0bcabd1ed22e9fb1310cf6c2dec7cdef19f0ad69efa1f392e94a4333501270"}
+  },
+  "components": [
+    {"bom-ref": "openssl", "name": "openssl", "version": "3.0.11", "purl": "pkg:deb/debian/openssl@3.0.11"},
+    {"bom-ref": "zlib", "name": "zlib", "version": "1.2.13"}
+  ],
+  "vulnerabilities": [
+    {"id": "CVE-2023-0001", "analysis": {"state": "not_affected", "justification": "code_not_reachable"},
+     "affects": [{"ref": "urn:cdx:3e671687-395b-41f5-a30f-a58921a69b79/1#openssl"}, {"ref": "zlib"}]},
+    {"id": "CVE-2023-0002", "analysis": {"state": "resolved"}, "affects": [{"ref": "image"}]},
+    {"id": "CVE-2023-0003", "analysis": {"state": "exploitable"}},
+    {"id": "CVE-2023-0004"}
+  ]
+}`
+	statements, err := Parse([]byte(doc), artifactDigest)
+	require.NoError(t, err)
+	require.Len(t, statements, 4)
+
+	assert.Equal(t, "openssl", statements[0].Package)
+	assert.Equal(t, "3.0.11", statements[0].PackageVersion)
+	assert.Equal(t, model.StatusNotAffected, statements[0].Status)
+	assert.Equal(t, "code_not_reachable", statements[0].Justification)
+	assert.Equal(t, model.FormatCycloneDX, statements[0].Format)
+
+	assert.Equal(t, "zlib", statements[1].Package)
+	assert.Equal(t, "1.2.13", statements[1].PackageVersion)
+	assert.Empty(t, statements[1].PURL)
+
+	assert.Equal(t, "CVE-2023-0002", statements[2].CVEID)
+	assert.Empty(t, statements[2].Package)
+	assert.Equal(t, model.StatusFixed, statements[2].Status)
+
+	assert.Equal(t, model.StatusAffected, statements[3].Status)
+
+	// the document about another product
+	statements, err = Parse([]byte(doc), "sha256:ee1d00c5250b5a886b09be2d5f9506add35dfb557f1ef37a7e4b8f0138f32956")
+	require.NoError(t, err)
+	assert.Empty(t, statements)
+}
+
+func TestParseUnsupported(t *testing.T) {
+	_, err := Parse([]byte(`not json`), artifactDigest)
+	assert.True(t, errors.IsErr(err, errors.BadRequestCode))
+
+	_, err = Parse([]byte(`{"spdxVersion": "SPDX-2.3"}`), artifactDigest)
+	assert.True(t, errors.IsErr(err, errors.BadRequestCode))
+}
+
+func TestParsePURL(t *testing.T) {
+	name, version := parsePURL("pkg:npm/%40angular/core@16.0.0")
+	assert.Equal(t, "core", name)
+	assert.Equal(t, "16.0.0", version)
+
+	name, version = parsePURL("pkg:apk/alpine/busybox")
+	assert.Equal(t, "busybox", name)
+	assert.Empty(t, version)
+}
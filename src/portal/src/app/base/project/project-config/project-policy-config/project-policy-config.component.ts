@@ -135,7 +135,7 @@ export class ProjectPolicyConfigComponent implements OnInit {
     bandwidthError: string | null = null;
     registries: Registry[] = [];
     supportedRegistryTypeQueryString: string =
-        'type={docker-hub harbor azure-acr aws-ecr google-gcr quay docker-registry github-ghcr jfrog-artifactory}';
+        'type={docker-hub harbor azure-acr aws-ecr google-gcr quay docker-registry github-ghcr jfrog-artifactory nexus gitea cloudsmith}';
 
     constructor(
         private errorHandler: ErrorHandler,
@@ -30,6 +30,9 @@ export const ADAPTERS_MAP = {
     'tencent-tcr': 'Tencent TCR',
     'github-ghcr': 'Github GHCR',
     'volcengine-cr': 'VolcEngine CR',
+    nexus: 'Sonatype Nexus',
+    gitea: 'Gitea',
+    cloudsmith: 'Cloudsmith',
 };
 
 /**
@@ -0,0 +1,76 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/goharbor/harbor/src/common/secret"
+	"github.com/goharbor/harbor/src/common/security"
+	"github.com/goharbor/harbor/src/lib/errors"
+	libhttp "github.com/goharbor/harbor/src/lib/http"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
+	"github.com/goharbor/harbor/src/pkg/notification/signature"
+	"github.com/goharbor/harbor/src/server/router"
+)
+
+// NewWebhookSignatureHandler creates a handler to sign the webhook payloads for the jobservice
+// right before sending, the signing secrets of the policies never leave the core
+func NewWebhookSignatureHandler() http.Handler {
+	return &webhookSignatureHandler{
+		policyMgr: policy.Mgr,
+	}
+}
+
+type webhookSignatureHandler struct {
+	policyMgr policy.Manager
+}
+
+func (h *webhookSignatureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	ctx := r.Context()
+	secCtx, ok := security.FromContext(ctx)
+	if !ok || !secCtx.IsSolutionUser() || secCtx.GetUsername() != secret.JobserviceUser {
+		libhttp.SendError(w, errors.ForbiddenError(nil).WithMessage("only the jobservice can sign the webhook payloads"))
+		return
+	}
+	policyID, err := strconv.ParseInt(router.Param(ctx, ":id"), 10, 64)
+	if err != nil {
+		libhttp.SendError(w, errors.BadRequestError(err))
+		return
+	}
+	req := struct {
+		Payload string `json:"payload"`
+	}{}
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		libhttp.SendError(w, errors.BadRequestError(err))
+		return
+	}
+	secrets, err := h.policyMgr.GetSigningSecrets(ctx, policyID)
+	if err != nil {
+		libhttp.SendError(w, err)
+		return
+	}
+	header := http.Header{}
+	signature.Apply(header, "", secrets, []byte(req.Payload), time.Now())
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(header); err != nil {
+		libhttp.SendError(w, err)
+	}
+}
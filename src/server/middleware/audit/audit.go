@@ -0,0 +1,236 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/goharbor/harbor/src/common/security"
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	"github.com/goharbor/harbor/src/pkg/notifier/event"
+	"github.com/goharbor/harbor/src/server/middleware"
+	mwsecurity "github.com/goharbor/harbor/src/server/middleware/security"
+)
+
+const (
+	// the max size of the request body which will be recorded in the audit log
+	maxPayloadSize = 1 << 20
+
+	operationLogin  = "login"
+	operationLogout = "logout"
+)
+
+// resource describes a kind of resource whose mutating API calls are recorded in the audit log
+type resource struct {
+	resourceType string
+	// pattern matches the request path, the named group "project" captures the project name or ID
+	// and the named group "id" captures the identifier of the resource
+	pattern *regexp.Regexp
+	methods []string
+	// operation overrides the operation resolved from the request method
+	operation string
+	// singleton indicates there is only one instance of the resource, e.g. the configurations
+	singleton bool
+	// excludedIDs are the path segments matched by the "id" group that aren't resources, e.g. the ping API
+	excludedIDs []string
+	// snapshot returns the current state of the resource identified by the groups matched from
+	// the request path, it is used to calculate the before/after diff of the operation
+	snapshot func(ctx context.Context, groups map[string]string) (interface{}, error)
+}
+
+func (r *resource) match(req *http.Request) (map[string]string, bool) {
+	matched := false
+	for _, m := range r.methods {
+		if m == req.Method {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil, false
+	}
+	subs := r.pattern.FindStringSubmatch(req.URL.Path)
+	if subs == nil {
+		return nil, false
+	}
+	groups := map[string]string{}
+	for i, name := range r.pattern.SubexpNames() {
+		if i > 0 && len(name) > 0 {
+			groups[name] = subs[i]
+		}
+	}
+	for _, id := range r.excludedIDs {
+		if groups["id"] == id {
+			return nil, false
+		}
+	}
+	return groups, true
+}
+
+func (r *resource) operationOf(method string) string {
+	if len(r.operation) > 0 {
+		return r.operation
+	}
+	switch method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut, http.MethodPatch:
+		return "update"
+	case http.MethodDelete:
+		return "delete"
+	}
+	return ""
+}
+
+// Middleware records the mutating API calls against the registered resources in the audit log
+func Middleware(skippers ...middleware.Skipper) func(http.Handler) http.Handler {
+	return middleware.New(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		var (
+			res    *resource
+			groups map[string]string
+		)
+		for _, rs := range resources {
+			if g, ok := rs.match(r); ok {
+				res, groups = rs, g
+				break
+			}
+		}
+		if res == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		logger := log.G(ctx)
+		operation := res.operationOf(r.Method)
+		id := groups["id"]
+
+		var body []byte
+		if r.Body != nil && r.Body != http.NoBody {
+			data, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+			if err != nil {
+				logger.Warningf("failed to read the request body for the audit log: %v", err)
+			}
+			// restore the body for the following handlers
+			r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), r.Body))
+			if len(data) <= maxPayloadSize {
+				body = data
+			}
+		}
+
+		md := &metadata.APIOperationMetadata{
+			Ctx:          ctx,
+			ResourceType: res.resourceType,
+			Resource:     id,
+			Operation:    operation,
+			ClientIP:     mwsecurity.GetClientIP(r),
+			UserAgent:    mwsecurity.GetUserAgent(r),
+		}
+		if secCtx, ok := security.FromContext(ctx); ok && secCtx.IsAuthenticated() {
+			md.AuthMethod = secCtx.Name()
+			if operation == operationLogout {
+				md.Resource = secCtx.GetUsername()
+			}
+		}
+		if operation == operationLogin {
+			md.AuthMethod = lib.GetAuthMode(ctx)
+			if values, err := url.ParseQuery(string(body)); err == nil {
+				md.Username = values.Get("principal")
+				md.Resource = md.Username
+			}
+		}
+		if p := groups["project"]; len(p) > 0 {
+			md.ProjectID = resolveProjectID(ctx, r, p)
+		}
+
+		var before interface{}
+		if res.snapshot != nil && (len(id) > 0 || res.singleton) && r.Method != http.MethodPost {
+			before = takeSnapshot(ctx, res, groups)
+		}
+
+		rec := lib.NewResponseRecorder(w)
+		next.ServeHTTP(rec, r)
+		md.Succeeded = rec.Success()
+
+		if len(id) == 0 && r.Method == http.MethodPost {
+			if location := rec.Header().Get("Location"); len(location) > 0 {
+				id = path.Base(location)
+				groups["id"] = id
+				md.Resource = id
+			}
+		}
+		md.Description = fmt.Sprintf("%s %s", operation, res.resourceType)
+		if len(md.Resource) > 0 {
+			md.Description = fmt.Sprintf("%s %s", md.Description, md.Resource)
+		} else {
+			// the singleton resources have no name or ID
+			md.Resource = res.resourceType
+		}
+
+		var after interface{}
+		if md.Succeeded && res.snapshot != nil && r.Method != http.MethodDelete && (len(id) > 0 || res.singleton) {
+			after = takeSnapshot(ctx, res, groups)
+		}
+		if after == nil && r.Method != http.MethodDelete && operation != operationLogin && len(body) > 0 {
+			// fall back to the request payload when the state of the resource cannot be retrieved
+			after = body
+		}
+		payload, err := diff(before, after)
+		if err != nil {
+			logger.Warningf("failed to calculate the payload diff for the audit log: %v", err)
+		}
+		md.Payload = payload
+
+		if md.Succeeded {
+			// send the event by the notification middleware to make sure the transaction is committed
+			notification.AddEvent(ctx, md)
+			return
+		}
+		event.BuildAndPublish(ctx, md)
+	}, skippers...)
+}
+
+func takeSnapshot(ctx context.Context, res *resource, groups map[string]string) interface{} {
+	snapshot, err := res.snapshot(ctx, groups)
+	if err != nil {
+		log.G(ctx).Debugf("failed to get the snapshot of %s %s for the audit log: %v", res.resourceType, groups["id"], err)
+		return nil
+	}
+	return snapshot
+}
+
+func resolveProjectID(ctx context.Context, r *http.Request, projectNameOrID string) int64 {
+	var nameOrID interface{} = projectNameOrID
+	if r.Header.Get("X-Is-Resource-Name") != "true" {
+		nameOrID = parseProjectNameOrID(projectNameOrID)
+	}
+	p, err := project.Ctl.Get(ctx, nameOrID)
+	if err != nil {
+		log.G(ctx).Debugf("failed to get the project %s for the audit log: %v", projectNameOrID, err)
+		return 0
+	}
+	return p.ProjectID
+}
@@ -0,0 +1,195 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/goharbor/harbor/src/controller/event/metadata"
+	"github.com/goharbor/harbor/src/pkg/notification"
+)
+
+type auditTestSuite struct {
+	suite.Suite
+	originalResources []*resource
+	state             map[string]interface{}
+}
+
+func (a *auditTestSuite) SetupTest() {
+	a.originalResources = resources
+	a.state = map[string]interface{}{"name": "old", "password": "p1", "public": true}
+	resources = []*resource{
+		{
+			resourceType: "fake",
+			pattern:      regexp.MustCompile(`^/api/v2\.0/fakes(?:/(?P<id>\d+))?$`),
+			methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+			snapshot: func(_ context.Context, _ map[string]string) (interface{}, error) {
+				state := map[string]interface{}{}
+				for k, v := range a.state {
+					state[k] = v
+				}
+				return state, nil
+			},
+		},
+		{
+			resourceType: "configuration",
+			pattern:      regexp.MustCompile(`^/api/v2\.0/fakeconfigs$`),
+			methods:      []string{http.MethodPut},
+			singleton:    true,
+		},
+		{
+			resourceType: "user",
+			pattern:      regexp.MustCompile(`^/c/login$`),
+			methods:      []string{http.MethodPost},
+			operation:    operationLogin,
+		},
+	}
+}
+
+func (a *auditTestSuite) TearDownTest() {
+	resources = a.originalResources
+}
+
+func (a *auditTestSuite) serve(req *http.Request, next http.Handler) *notification.EventCtx {
+	evc := notification.NewEventCtx()
+	req = req.WithContext(notification.NewContext(req.Context(), evc))
+	Middleware()(next).ServeHTTP(httptest.NewRecorder(), req)
+	return evc
+}
+
+func (a *auditTestSuite) TestUnmatched() {
+	req := httptest.NewRequest(http.MethodGet, "/api/v2.0/fakes/1", nil)
+	evc := a.serve(req, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	a.Equal(0, evc.Events.Len())
+}
+
+func (a *auditTestSuite) TestUpdate() {
+	req := httptest.NewRequest(http.MethodPut, "/api/v2.0/fakes/1", strings.NewReader(`{"name":"new"}`))
+	evc := a.serve(req, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the body is still readable by the handler
+		data, err := io.ReadAll(r.Body)
+		a.Require().Nil(err)
+		a.Equal(`{"name":"new"}`, string(data))
+		a.state["name"] = "new"
+		a.state["password"] = "p2"
+		w.WriteHeader(http.StatusOK)
+	}))
+	a.Require().Equal(1, evc.Events.Len())
+	md, ok := evc.Events.Front().Value.(*metadata.APIOperationMetadata)
+	a.Require().True(ok)
+	a.Equal("fake", md.ResourceType)
+	a.Equal("1", md.Resource)
+	a.Equal("update", md.Operation)
+	a.Equal("update fake 1", md.Description)
+	a.True(md.Succeeded)
+
+	payload := &diffPayload{}
+	a.Require().Nil(json.Unmarshal([]byte(md.Payload), payload))
+	a.Equal(map[string]interface{}{"name": "old", "password": redacted}, payload.Before)
+	a.Equal(map[string]interface{}{"name": "new", "password": redacted}, payload.After)
+}
+
+func (a *auditTestSuite) TestUpdateSingleton() {
+	req := httptest.NewRequest(http.MethodPut, "/api/v2.0/fakeconfigs", strings.NewReader(`{"read_only":true}`))
+	evc := a.serve(req, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	a.Require().Equal(1, evc.Events.Len())
+	md := evc.Events.Front().Value.(*metadata.APIOperationMetadata)
+	a.Equal("configuration", md.Resource)
+	a.Equal("update configuration", md.Description)
+}
+
+func (a *auditTestSuite) TestCreate() {
+	req := httptest.NewRequest(http.MethodPost, "/api/v2.0/fakes", strings.NewReader(`{"name":"old"}`))
+	evc := a.serve(req, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", "/api/v2.0/fakes/2")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	a.Require().Equal(1, evc.Events.Len())
+	md := evc.Events.Front().Value.(*metadata.APIOperationMetadata)
+	a.Equal("2", md.Resource)
+	a.Equal("create", md.Operation)
+	a.Contains(md.Payload, `"after"`)
+	a.NotContains(md.Payload, `"before"`)
+}
+
+func (a *auditTestSuite) TestFailed() {
+	req := httptest.NewRequest(http.MethodDelete, "/api/v2.0/fakes/1", nil)
+	evc := a.serve(req, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	// the failed operations are published directly rather than by the notification middleware
+	a.Equal(0, evc.Events.Len())
+}
+
+func (a *auditTestSuite) TestLogin() {
+	req := httptest.NewRequest(http.MethodPost, "/c/login", strings.NewReader("principal=admin&password=Harbor12345"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	evc := a.serve(req, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	a.Require().Equal(1, evc.Events.Len())
+	md := evc.Events.Front().Value.(*metadata.APIOperationMetadata)
+	a.Equal("login", md.Operation)
+	a.Equal("admin", md.Username)
+	a.Equal("admin", md.Resource)
+	a.Empty(md.Payload)
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, &auditTestSuite{})
+}
+
+func TestResourcesMatch(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		typ    string
+		id     string
+	}{
+		{http.MethodPut, "/api/v2.0/projects/library/webhook/policies/1", "webhook_policy", "1"},
+		{http.MethodPost, "/api/v2.0/system/webhook/policies", "webhook_policy", ""},
+		{http.MethodDelete, "/api/v2.0/system/webhook/policies/2", "webhook_policy", "2"},
+		{http.MethodGet, "/api/v2.0/system/webhook/policies/2", "", ""},
+		{http.MethodPost, "/api/v2.0/scanners", "scanner", ""},
+		{http.MethodPatch, "/api/v2.0/scanners/uuid", "scanner", "uuid"},
+		{http.MethodPost, "/api/v2.0/scanners/ping", "", ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, nil)
+		var typ, id string
+		for _, r := range resources {
+			if groups, ok := r.match(req); ok {
+				typ, id = r.resourceType, groups["id"]
+				break
+			}
+		}
+		assert.Equal(t, c.typ, typ, c.path)
+		assert.Equal(t, c.id, id, c.path)
+	}
+}
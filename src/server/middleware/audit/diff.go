@@ -0,0 +1,157 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const redacted = "******"
+
+var (
+	// the keys whose values are never recorded in the audit log
+	sensitiveKeys = []string{"password", "secret", "credential", "auth_header", "authorization", "headers"}
+	// the key suffixes whose values are never recorded, matched as suffixes
+	// to keep the settings like "token_expiration"
+	sensitiveSuffixes = []string{"token"}
+)
+
+// diffPayload is the payload recorded in the audit log
+type diffPayload struct {
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// diff returns the JSON encoded changes between the before and after state of a resource,
+// only the changed top level fields are kept when both states are objects
+func diff(before, after interface{}) (string, error) {
+	b, err := normalize(before)
+	if err != nil {
+		return "", err
+	}
+	a, err := normalize(after)
+	if err != nil {
+		return "", err
+	}
+	if b == nil && a == nil {
+		return "", nil
+	}
+
+	bm, bok := b.(map[string]interface{})
+	am, aok := a.(map[string]interface{})
+	if bok && aok {
+		for k, v := range bm {
+			if av, exist := am[k]; exist && reflect.DeepEqual(v, av) {
+				delete(bm, k)
+				delete(am, k)
+			}
+		}
+		b, a = bm, am
+		if len(bm) == 0 {
+			b = nil
+		}
+		if len(am) == 0 {
+			a = nil
+		}
+	}
+	// redact after comparing to keep the changes of the sensitive fields
+	b, a = redact(b), redact(a)
+
+	data, err := json.Marshal(&diffPayload{Before: b, After: a})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// normalize converts the value into the generic JSON representation
+func normalize(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, ok := v.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		// not a JSON payload, record nothing rather than the raw content
+		return nil, nil
+	}
+	return result, nil
+}
+
+func redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if isSensitive(k) {
+				val[k] = redacted
+				continue
+			}
+			val[k] = redact(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redact(item)
+		}
+	case string:
+		return redactEncoded(val)
+	}
+	return v
+}
+
+// redactEncoded redacts the sensitive fields of the JSON encoded string value, e.g. the
+// audit log forwarders configuration, the value is returned as it is if nothing is redacted
+func redactEncoded(s string) string {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return s
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+		return s
+	}
+	origin, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+	data, err := json.Marshal(redact(v))
+	if err != nil || bytes.Equal(origin, data) {
+		return s
+	}
+	return string(data)
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	for _, s := range sensitiveSuffixes {
+		if strings.HasSuffix(key, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	// nothing to record
+	payload, err := diff(nil, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, payload)
+
+	// only the changed fields are recorded
+	payload, err = diff(map[string]interface{}{"a": 1, "b": "x"}, map[string]interface{}{"a": 1, "b": "y"})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"before":{"b":"x"},"after":{"b":"y"}}`, payload)
+
+	// the raw request payload with nested sensitive fields
+	payload, err = diff(nil, []byte(`{"name":"reg","credential":{"access_key":"ak","access_secret":"sk"}}`))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"after":{"name":"reg","credential":"******"}}`, payload)
+
+	// the sensitive fields of the JSON encoded values
+	payload, err = diff(map[string]interface{}{"audit_log_forwarders": "", "token_expiration": 30},
+		map[string]interface{}{
+			"audit_log_forwarders": `[{"name":"siem","type":"http","headers":{"Authorization":"Bearer xxx"}}]`,
+			"token_expiration":     60,
+			"access_token":         "xxx",
+		})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"before":{"audit_log_forwarders":"","token_expiration":30},"after":{"audit_log_forwarders":"[{\"headers\":\"******\",\"name\":\"siem\",\"type\":\"http\"}]","token_expiration":60,"access_token":"******"}}`, payload)
+
+	// non JSON payload is ignored
+	payload, err = diff(nil, []byte("principal=admin"))
+	assert.Nil(t, err)
+	assert.Empty(t, payload)
+}
@@ -0,0 +1,220 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/controller/config"
+	"github.com/goharbor/harbor/src/controller/immutable"
+	"github.com/goharbor/harbor/src/controller/member"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/controller/proxy/prefetch"
+	"github.com/goharbor/harbor/src/controller/registry"
+	"github.com/goharbor/harbor/src/controller/replication"
+	"github.com/goharbor/harbor/src/controller/retention"
+	"github.com/goharbor/harbor/src/controller/robot"
+	"github.com/goharbor/harbor/src/controller/scanner"
+	"github.com/goharbor/harbor/src/controller/user"
+	"github.com/goharbor/harbor/src/controller/usergroup"
+	"github.com/goharbor/harbor/src/controller/webhook"
+)
+
+// resources whose mutating API calls are recorded in the audit log,
+// the robot creation and deletion are recorded by the robot events
+var resources = []*resource{
+	{
+		resourceType: "configuration",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/configurations$`),
+		methods:      []string{http.MethodPut},
+		singleton:    true,
+		snapshot: func(ctx context.Context, _ map[string]string) (interface{}, error) {
+			cfgs, err := config.Ctl.UserConfigs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			values := map[string]interface{}{}
+			for k, v := range cfgs {
+				values[k] = v.Val
+			}
+			return values, nil
+		},
+	},
+	{
+		resourceType: "member",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/projects/(?P<project>[^/]+)/members(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.Atoi(groups["id"])
+			if err != nil {
+				return nil, err
+			}
+			return member.NewController().Get(ctx, parseProjectNameOrID(groups["project"]), id)
+		},
+	},
+	{
+		resourceType: "user_group",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/usergroups(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.Atoi(groups["id"])
+			if err != nil {
+				return nil, err
+			}
+			return usergroup.Ctl.Get(ctx, id)
+		},
+	},
+	{
+		resourceType: "robot",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/(?:projects/(?P<project>[^/]+)/)?robots/(?P<id>\d+)$`),
+		methods:      []string{http.MethodPut, http.MethodPatch},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return robot.Ctl.Get(ctx, id, &robot.Option{WithPermission: true})
+		},
+	},
+	{
+		resourceType: "scanner",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/scanners(?:/(?P<id>[^/]+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		// the connectivity test of the scanner changes nothing
+		excludedIDs: []string{"ping"},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			return scanner.DefaultController.GetRegistration(ctx, groups["id"])
+		},
+	},
+	{
+		resourceType: "registry",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/registries(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return registry.Ctl.Get(ctx, id)
+		},
+	},
+	{
+		resourceType: "replication_policy",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/replication/policies(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return replication.Ctl.GetPolicy(ctx, id)
+		},
+	},
+	{
+		resourceType: "retention_policy",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/retentions(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return retention.Ctl.GetRetention(ctx, id)
+		},
+	},
+	{
+		resourceType: "immutable_rule",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/projects/(?P<project>[^/]+)/immutabletagrules(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return immutable.Ctr.GetImmutableRule(ctx, id)
+		},
+	},
+	{
+		resourceType: "prefetch_policy",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/projects/(?P<project>[^/]+)/proxy-cache/prefetch-policy$`),
+		methods:      []string{http.MethodPut, http.MethodDelete},
+		singleton:    true,
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			p, err := project.Ctl.Get(ctx, parseProjectNameOrID(groups["project"]))
+			if err != nil {
+				return nil, err
+			}
+			return prefetch.Ctl.GetPolicyByProject(ctx, p.ProjectID)
+		},
+	},
+	{
+		resourceType: "webhook_policy",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/projects/(?P<project>[^/]+)/webhook/policies(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return webhook.Ctl.GetPolicy(ctx, id)
+		},
+	},
+	{
+		resourceType: "webhook_policy",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/system/webhook/policies(?:/(?P<id>\d+))?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.ParseInt(groups["id"], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			return webhook.Ctl.GetPolicy(ctx, id)
+		},
+	},
+	{
+		resourceType: "user",
+		pattern:      regexp.MustCompile(`^/api/v2\.0/users(?:/(?P<id>\d+)(?:/(?:sysadmin|password|cli_secret))?)?$`),
+		methods:      []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+		snapshot: func(ctx context.Context, groups map[string]string) (interface{}, error) {
+			id, err := strconv.Atoi(groups["id"])
+			if err != nil {
+				return nil, err
+			}
+			return user.Ctl.Get(ctx, id, nil)
+		},
+	},
+	{
+		resourceType: "user",
+		pattern:      regexp.MustCompile(`^/c/login$`),
+		methods:      []string{http.MethodPost},
+		operation:    operationLogin,
+	},
+	{
+		resourceType: "user",
+		pattern:      regexp.MustCompile(`^/c/log_out$`),
+		methods:      []string{http.MethodGet},
+		operation:    operationLogout,
+	},
+}
+
+func parseProjectNameOrID(s string) interface{} {
+	if id, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return id
+	}
+	return s
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/orm"
 	proModels "github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/server/middleware"
 )
@@ -43,12 +44,16 @@ const (
 	contentType         = "Content-Type"
 	dockerContentDigest = "Docker-Content-Digest"
 	etag                = "Etag"
+	warning             = "Warning"
 	ensureTagInterval   = 10 * time.Second
 	ensureTagMaxRetry   = 60
 )
 
 var tooManyRequestsError = errors.New("too many requests to upstream registry").WithCode(errors.RateLimitCode)
 
+// staleWarning is the warning header returned when serving the local copy because the upstream registry is unavailable
+var staleWarning = `110 harbor "Response is Stale: the upstream registry is unavailable, serving the cached content"`
+
 // BlobGetMiddleware handle get blob request
 func BlobGetMiddleware() func(http.Handler) http.Handler {
 	return middleware.New(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
@@ -170,17 +175,29 @@ func handleManifest(w http.ResponseWriter, r *http.Request, next http.Handler) e
 		return nil
 	}
 
+	if servePinned(w, r, next, proxyCtl, p, art) {
+		return nil
+	}
+
 	if !canProxy(r.Context(), p) {
-		next.ServeHTTP(w, r)
+		if !serveStale(w, r, next, proxyCtl, p, art, errors.New("the upstream registry is unhealthy")) {
+			next.ServeHTTP(w, r)
+		}
 		return nil
 	}
-	remote, err := proxy.NewRemoteHelper(r.Context(), p.RegistryID, proxy.WithSpeed(p.ProxyCacheSpeed()))
+	opts := proxyOptions(p)
+	remote, err := proxy.NewRemoteHelperWithUpstreams(r.Context(), p.ProxyCacheUpstreamRegistryIDs(), opts...)
 	if err != nil {
+		if serveStale(w, r, next, proxyCtl, p, art, err) {
+			return nil
+		}
 		return err
 	}
-	useLocal, man, err := proxyCtl.UseLocalManifest(ctx, art, remote)
-
+	useLocal, man, err := proxyCtl.UseLocalManifest(ctx, art, remote, opts...)
 	if err != nil {
+		if !errors.IsNotFoundErr(err) && serveStale(w, r, next, proxyCtl, p, art, err) {
+			return nil
+		}
 		return err
 	}
 	if useLocal {
@@ -209,7 +226,13 @@ func handleManifest(w http.ResponseWriter, r *http.Request, next http.Handler) e
 		err = proxyManifestGet(ctx, w, proxyCtl, p, art, remote)
 	}
 	if err != nil {
-		if errors.IsNotFoundErr(err) || errors.IsRateLimitError(err) {
+		if errors.IsNotFoundErr(err) {
+			return err
+		}
+		if serveStale(w, r, next, proxyCtl, p, art, err) {
+			return nil
+		}
+		if errors.IsRateLimitError(err) {
 			return err
 		}
 		log.Warningf("Proxy to remote failed, fallback to local repo, error: %v", err)
@@ -234,19 +257,78 @@ func proxyManifestGet(ctx context.Context, w http.ResponseWriter, ctl proxy.Cont
 	return nil
 }
 
-func canProxy(ctx context.Context, p *proModels.Project) bool {
-	if p.RegistryID < 1 {
+// proxyOptions returns the options of proxy cache configured in the project
+func proxyOptions(p *proModels.Project) []proxy.Option {
+	return []proxy.Option{
+		proxy.WithSpeed(p.ProxyCacheSpeed()),
+		proxy.WithTTL(p.ProxyCacheTTL()),
+		proxy.WithStaleWhileRevalidate(p.ProxyCacheStaleWhileRevalidate()),
+		proxy.WithStaleIfError(p.ProxyCacheStaleIfError()),
+	}
+}
+
+// serveStale serves the local copy of the manifest with the warning header when the project enables
+// serving the stale content on error and the manifest exists in local, returns false if it isn't served
+func serveStale(w http.ResponseWriter, r *http.Request, next http.Handler, ctl proxy.Controller, p *proModels.Project, art lib.ArtifactInfo, cause error) bool {
+	if !p.ProxyCacheStaleIfError() {
+		return false
+	}
+	exist, err := ctl.LocalManifestExist(r.Context(), art)
+	if err != nil {
+		log.Errorf("failed to check the existence of manifest %s:%s%s in local, error: %v", art.Repository, art.Tag, art.Digest, err)
+		return false
+	}
+	if !exist {
 		return false
 	}
-	reg, err := registry.Ctl.Get(ctx, p.RegistryID)
+	log.Warningf("serve the local copy of %s:%s%s as the upstream registry is unavailable, error: %v", art.Repository, art.Tag, art.Digest, cause)
+	w.Header().Set(warning, staleWarning)
+	next.ServeHTTP(w, r)
+	return true
+}
+
+// servePinned serves the local copy of the manifest without resolving it against the upstream registry
+// when the tag is pinned by the prefetch policy of the project, returns false if it isn't served
+func servePinned(w http.ResponseWriter, r *http.Request, next http.Handler, ctl proxy.Controller, p *proModels.Project, art lib.ArtifactInfo) bool {
+	if len(art.Tag) == 0 {
+		return false
+	}
+	repository := strings.TrimPrefix(art.Repository, p.Name+"/")
+	pinned, err := prefetch.Mgr.IsPinned(r.Context(), p.ProjectID, repository, art.Tag)
 	if err != nil {
-		log.Errorf("failed to get registry, error:%v", err)
+		log.Errorf("failed to check whether the tag %s:%s is pinned, error: %v", art.Repository, art.Tag, err)
 		return false
 	}
-	if reg.Status != model.Healthy {
+	if !pinned {
+		return false
+	}
+	exist, err := ctl.LocalManifestExist(r.Context(), art)
+	if err != nil {
+		log.Errorf("failed to check the existence of manifest %s:%s in local, error: %v", art.Repository, art.Tag, err)
+		return false
+	}
+	if !exist {
+		return false
+	}
+	log.Debugf("the tag %s:%s is pinned, serve the local copy", art.Repository, art.Tag)
+	next.ServeHTTP(w, r)
+	return true
+}
+
+// canProxy checks whether any of the upstream registries of the proxy cache project is healthy
+func canProxy(ctx context.Context, p *proModels.Project) bool {
+	for _, regID := range p.ProxyCacheUpstreamRegistryIDs() {
+		reg, err := registry.Ctl.Get(ctx, regID)
+		if err != nil {
+			log.Errorf("failed to get registry, error:%v", err)
+			continue
+		}
+		if reg.Status == model.Healthy {
+			return true
+		}
 		log.Errorf("current registry is unhealthy, regID:%v, Name:%v, Status: %v", reg.ID, reg.Name, reg.Status)
 	}
-	return reg.Status == model.Healthy
+	return false
 }
 
 func setHeaders(w http.ResponseWriter, size int64, mediaType string, dig string) {
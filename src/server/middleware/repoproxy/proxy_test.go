@@ -16,13 +16,24 @@ package repoproxy
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 
 	"github.com/goharbor/harbor/src/common/models"
 	"github.com/goharbor/harbor/src/common/security"
 	"github.com/goharbor/harbor/src/common/security/local"
 	"github.com/goharbor/harbor/src/common/security/proxycachesecret"
 	securitySecret "github.com/goharbor/harbor/src/common/security/secret"
+	"github.com/goharbor/harbor/src/controller/proxy"
+	"github.com/goharbor/harbor/src/lib"
+	proModels "github.com/goharbor/harbor/src/pkg/project/models"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch"
+	"github.com/goharbor/harbor/src/testing/mock"
+	testingPrefetch "github.com/goharbor/harbor/src/testing/pkg/proxy/prefetch"
 )
 
 func TestIsProxySession(t *testing.T) {
@@ -80,3 +91,64 @@ func TestIsProxySession(t *testing.T) {
 		})
 	}
 }
+
+func TestProxyOptions(t *testing.T) {
+	p := &proModels.Project{
+		Metadata: map[string]string{
+			proModels.ProMetaProxySpeed:                     "1024",
+			proModels.ProMetaProxyCacheTTL:                  "300",
+			proModels.ProMetaProxyCacheStaleWhileRevalidate: "true",
+			proModels.ProMetaProxyCacheStaleIfError:         "true",
+		},
+	}
+	o := proxy.NewOptions(proxyOptions(p)...)
+	if o.Speed != 1024 || o.TTL != 5*time.Minute || !o.StaleWhileRevalidate || !o.StaleIfError {
+		t.Errorf("unexpected options: %+v", o)
+	}
+
+	// the freshness policies are disabled by default
+	o = proxy.NewOptions(proxyOptions(&proModels.Project{})...)
+	if o.TTL != 0 || o.StaleWhileRevalidate || o.StaleIfError {
+		t.Errorf("unexpected options: %+v", o)
+	}
+}
+
+type localManifestController struct {
+	proxy.Controller
+	exist bool
+}
+
+func (l *localManifestController) LocalManifestExist(_ context.Context, _ lib.ArtifactInfo) (bool, error) {
+	return l.exist, nil
+}
+
+func TestServePinned(t *testing.T) {
+	mgr := prefetch.Mgr
+	defer func() { prefetch.Mgr = mgr }()
+	pinnedMgr := &testingPrefetch.Manager{}
+	pinnedMgr.On("IsPinned", mock.Anything, int64(1), "library/nginx", "stable").Return(true, nil)
+	pinnedMgr.On("IsPinned", mock.Anything, int64(1), "library/nginx", "latest").Return(false, nil)
+	prefetch.Mgr = pinnedMgr
+
+	p := &proModels.Project{ProjectID: 1, Name: "dockerhub"}
+	served := false
+	next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) { served = true })
+	serve := func(art lib.ArtifactInfo, exist bool) bool {
+		served = false
+		r := httptest.NewRequest(http.MethodGet, "/v2/dockerhub/library/nginx/manifests/"+art.Tag+art.Digest, nil)
+		return servePinned(httptest.NewRecorder(), r, next, &localManifestController{exist: exist}, p, art)
+	}
+
+	// pinned and cached
+	assert.True(t, serve(lib.ArtifactInfo{Repository: "dockerhub/library/nginx", Tag: "stable"}, true))
+	assert.True(t, served)
+	// pinned but not cached yet
+	assert.False(t, serve(lib.ArtifactInfo{Repository: "dockerhub/library/nginx", Tag: "stable"}, false))
+	assert.False(t, served)
+	// not pinned
+	assert.False(t, serve(lib.ArtifactInfo{Repository: "dockerhub/library/nginx", Tag: "latest"}, true))
+	assert.False(t, served)
+	// pulled by digest
+	assert.False(t, serve(lib.ArtifactInfo{Repository: "dockerhub/library/nginx", Digest: "sha256:1234"}, true))
+	assert.False(t, served)
+}
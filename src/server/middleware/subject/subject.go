@@ -25,6 +25,7 @@ import (
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/goharbor/harbor/src/controller/artifact"
+	"github.com/goharbor/harbor/src/controller/vex"
 	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
@@ -47,6 +48,14 @@ var (
 
 	// media type of harbor sbom
 	mediaTypeHarborSBOM = "application/vnd.goharbor.harbor.sbom.v1"
+
+	// artifact types of the OpenVEX and CycloneDX VEX documents
+	mediaTypeOpenVEX    = "application/vnd.openvex+json"
+	mediaTypeCycloneVEX = "application/vnd.cyclonedx.vex+json"
+
+	// artifact types of the SPDX and CycloneDX SBOM documents produced outside of harbor
+	mediaTypeSPDX      = "application/spdx+json"
+	mediaTypeCycloneDX = "application/vnd.cyclonedx+json"
 )
 
 /*
@@ -159,6 +168,10 @@ func Middleware() func(http.Handler) http.Handler {
 				accData.Type = model.TypeCosignSignature
 			case mediaTypeHarborSBOM:
 				accData.Type = model.TypeHarborSBOM
+			case mediaTypeOpenVEX, mediaTypeCycloneVEX:
+				accData.Type = model.TypeVEX
+			case mediaTypeSPDX, mediaTypeCycloneDX:
+				accData.Type = model.TypeSBOM
 			}
 			if subjectArt != nil {
 				accData.SubArtifactID = subjectArt.ID
@@ -173,6 +186,13 @@ func Middleware() func(http.Handler) http.Handler {
 				}
 			}
 
+			// the statements are stored by the subject digest, so they apply even if the subject artifact is pushed later
+			if accData.Type == model.TypeVEX {
+				if err := vex.Ctl.Ingest(ctx, art.ProjectID, info.Repository, art.Digest, accData.SubArtifactDigest); err != nil {
+					logger.Errorf("failed to ingest the VEX document %s of artifact %s, error: %v", art.Digest, accData.SubArtifactDigest, err)
+				}
+			}
+
 			// when subject artifact is pushed after accessory artifact, current subject artifact do not exist.
 			// so we use reference manifest subject digest instead of subjectArt.Digest
 			w.Header().Set("OCI-Subject", mf.Subject.Digest.String())
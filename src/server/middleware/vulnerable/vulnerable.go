@@ -141,6 +141,9 @@ func Middleware() func(http.Handler) http.Handler {
 		for _, cve := range vulnerable.CVEBypassed {
 			logger.Infof("Vulnerable policy check: bypassed CVE %s", cve)
 		}
+		for _, cve := range vulnerable.CVESuppressed {
+			logger.Infof("Vulnerable policy check: CVE %s suppressed by VEX statement", cve)
+		}
 
 		return nil
 	})
@@ -54,6 +54,7 @@ func registerRoutes() {
 	router.NewRoute().Method(http.MethodPost).Path("/service/notifications/jobs/replication/task/:id([0-9]+)").Handler(handler.NewJobStatusHandler()) // legacy job status hook endpoint for replication task
 	router.NewRoute().Method(http.MethodPost).Path("/service/notifications/jobs/retention/task/:id([0-9]+)").Handler(handler.NewJobStatusHandler())
 	router.NewRoute().Method(http.MethodPost).Path("/service/notifications/tasks/:id").Handler(handler.NewJobStatusHandler())
+	router.NewRoute().Method(http.MethodPost).Path("/service/webhook/policies/:id([0-9]+)/signature").Handler(handler.NewWebhookSignatureHandler()) // sign the webhook payloads for the jobservice
 
 	web.Router("/service/token", &token.Handler{})
 
@@ -36,6 +36,7 @@ import (
 	"github.com/goharbor/harbor/src/controller/repository"
 	"github.com/goharbor/harbor/src/controller/scan"
 	"github.com/goharbor/harbor/src/controller/tag"
+	"github.com/goharbor/harbor/src/controller/vex"
 	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
@@ -59,6 +60,7 @@ func newArtifactAPI() *artifactAPI {
 		scanCtl:  scan.DefaultController,
 		tagCtl:   tag.Ctl,
 		labelMgr: label.Mgr,
+		vexCtl:   vex.Ctl,
 	}
 }
 
@@ -71,6 +73,7 @@ type artifactAPI struct {
 	scanCtl  scan.Controller
 	tagCtl   tag.Controller
 	labelMgr label.Manager
+	vexCtl   vex.Controller
 }
 
 func (a *artifactAPI) Prepare(ctx context.Context, _ string, params interface{}) middleware.Responder {
@@ -405,12 +408,7 @@ func (a *artifactAPI) GetVulnerabilitiesAddition(ctx context.Context, params ope
 	vulnerabilities := make(map[string]interface{})
 
 	for _, mimeType := range parseScanReportMimeTypes(params.XAcceptVulnerabilities) {
-		reports, err := a.scanCtl.GetReport(ctx, artifact, []string{mimeType})
-		if err != nil {
-			return a.SendError(ctx, err)
-		}
-
-		vrp, err := report.Reports(reports).ResolveData(mimeType)
+		vrp, err := a.resolveVulnerabilities(ctx, artifact, lib.StringValue(params.ScannerUUID), mimeType)
 		if err != nil {
 			return a.SendError(ctx, err)
 		}
@@ -434,6 +432,80 @@ func (a *artifactAPI) GetVulnerabilitiesAddition(ctx context.Context, params ope
 	})
 }
 
+// resolveVulnerabilities resolves the vulnerabilities found by the specified scanner,
+// or the merged ones found by all the scanners of the project if the scanner isn't specified
+func (a *artifactAPI) resolveVulnerabilities(ctx context.Context, art *artifact.Artifact, scannerUUID string, mimeType string) (interface{}, error) {
+	if len(scannerUUID) > 0 {
+		reports, err := a.scanCtl.GetScannerReport(ctx, art, scannerUUID, []string{mimeType})
+		if err != nil {
+			return nil, err
+		}
+		return report.Reports(reports).ResolveData(mimeType)
+	}
+
+	reports, err := a.scanCtl.GetReport(ctx, art, []string{mimeType})
+	if err != nil {
+		return nil, err
+	}
+	return report.Reports(reports).ResolveData(mimeType)
+}
+
+func (a *artifactAPI) ListVEXStatements(ctx context.Context, params operation.ListVEXStatementsParams) middleware.Responder {
+	if err := a.RequireProjectAccess(ctx, params.ProjectName, rbac.ActionRead, rbac.ResourceArtifact); err != nil {
+		return a.SendError(ctx, err)
+	}
+	art, err := a.artCtl.GetByReference(ctx, fmt.Sprintf("%s/%s", params.ProjectName, params.RepositoryName), params.Reference, nil)
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+	statements, err := a.vexCtl.List(ctx, art.ProjectID, art.Digest)
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+	res := []*models.VEXStatement{}
+	for _, s := range statements {
+		res = append(res, model.NewVEXStatement(s).ToSwagger())
+	}
+	return operation.NewListVEXStatementsOK().WithPayload(res)
+}
+
+func (a *artifactAPI) UploadVEX(ctx context.Context, params operation.UploadVEXParams) middleware.Responder {
+	if err := a.RequireProjectAccess(ctx, params.ProjectName, rbac.ActionPush, rbac.ResourceRepository); err != nil {
+		return a.SendError(ctx, err)
+	}
+	art, err := a.artCtl.GetByReference(ctx, fmt.Sprintf("%s/%s", params.ProjectName, params.RepositoryName), params.Reference, nil)
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+	document, err := json.Marshal(params.Document)
+	if err != nil {
+		return a.SendError(ctx, errors.BadRequestError(err))
+	}
+	statements, err := a.vexCtl.Upload(ctx, art.ProjectID, art.Digest, document)
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+	res := []*models.VEXStatement{}
+	for _, s := range statements {
+		res = append(res, model.NewVEXStatement(s).ToSwagger())
+	}
+	return operation.NewUploadVEXCreated().WithPayload(res)
+}
+
+func (a *artifactAPI) DeleteVEX(ctx context.Context, params operation.DeleteVEXParams) middleware.Responder {
+	if err := a.RequireProjectAccess(ctx, params.ProjectName, rbac.ActionPush, rbac.ResourceRepository); err != nil {
+		return a.SendError(ctx, err)
+	}
+	art, err := a.artCtl.GetByReference(ctx, fmt.Sprintf("%s/%s", params.ProjectName, params.RepositoryName), params.Reference, nil)
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+	if err := a.vexCtl.Delete(ctx, art.ProjectID, art.Digest); err != nil {
+		return a.SendError(ctx, err)
+	}
+	return operation.NewDeleteVEXOK()
+}
+
 func (a *artifactAPI) GetAddition(ctx context.Context, params operation.GetAdditionParams) middleware.Responder {
 	if err := a.RequireProjectAccess(ctx, params.ProjectName, rbac.ActionRead, rbac.ResourceArtifactAddition); err != nil {
 		return a.SendError(ctx, err)
@@ -97,12 +97,18 @@ func (a *auditlogAPI) ListAuditLogs(ctx context.Context, params auditlog.ListAud
 	var auditLogs []*models.AuditLog
 	for _, log := range logs {
 		auditLogs = append(auditLogs, &models.AuditLog{
-			ID:           log.ID,
-			Resource:     log.Resource,
-			ResourceType: log.ResourceType,
-			Username:     log.Username,
-			Operation:    log.Operation,
-			OpTime:       strfmt.DateTime(log.OpTime),
+			ID:                   log.ID,
+			Resource:             log.Resource,
+			ResourceType:         log.ResourceType,
+			Username:             log.Username,
+			Operation:            log.Operation,
+			OperationDescription: log.OperationDescription,
+			IsSuccessful:         log.IsSuccessful,
+			Payload:              log.Payload,
+			ClientIP:             log.ClientIP,
+			UserAgent:            log.UserAgent,
+			AuthMethod:           log.AuthMethod,
+			OpTime:               strfmt.DateTime(log.OpTime),
 		})
 	}
 	return auditlog.NewListAuditLogsOK().
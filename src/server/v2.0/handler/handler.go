@@ -71,6 +71,7 @@ func New() http.Handler {
 		ScheduleAPI:           newScheduleAPI(),
 		SecurityhubAPI:        newSecurityAPI(),
 		PermissionsAPI:        newPermissionsAPIAPI(),
+		ProxyCacheAPI:         newProxyCacheAPI(),
 	})
 	if err != nil {
 		log.Fatal(err)
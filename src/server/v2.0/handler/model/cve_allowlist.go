@@ -38,7 +38,14 @@ func (l *CVEAllowlist) ToSwagger() *svrmodels.CVEAllowlist {
 	}
 	for _, it := range l.Items {
 		cveItem := &svrmodels.CVEAllowlistItem{
-			CVEID: it.CVEID,
+			CVEID:         it.CVEID,
+			ExpiresAt:     it.ExpiresAt,
+			Justification: it.Justification,
+			Approver:      it.Approver,
+			Repository:    it.Repository,
+			Package:       it.Package,
+			AddedBy:       it.AddedBy,
+			AddedAt:       it.AddedAt,
 		}
 		res.Items = append(res.Items, cveItem)
 	}
@@ -0,0 +1,50 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"github.com/go-openapi/strfmt"
+
+	"github.com/goharbor/harbor/src/pkg/vex/model"
+	"github.com/goharbor/harbor/src/server/v2.0/models"
+)
+
+// VEXStatement model
+type VEXStatement struct {
+	*model.Statement
+}
+
+// ToSwagger converts the VEX statement to the swagger model
+func (s *VEXStatement) ToSwagger() *models.VEXStatement {
+	return &models.VEXStatement{
+		ID:             s.ID,
+		CveID:          s.CVEID,
+		Package:        s.Package,
+		PackageVersion: s.PackageVersion,
+		Purl:           s.PURL,
+		Status:         s.Status,
+		Justification:  s.Justification,
+		Source:         s.Source,
+		SourceDigest:   s.SourceDigest,
+		Format:         s.Format,
+		DocumentID:     s.DocumentID,
+		CreationTime:   strfmt.DateTime(s.CreationTime),
+	}
+}
+
+// NewVEXStatement ...
+func NewVEXStatement(s *model.Statement) *VEXStatement {
+	return &VEXStatement{Statement: s}
+}
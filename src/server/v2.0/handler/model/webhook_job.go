@@ -17,7 +17,7 @@ package model
 import (
 	"github.com/go-openapi/strfmt"
 
-	"github.com/goharbor/harbor/src/jobservice/job"
+	notifierModel "github.com/goharbor/harbor/src/pkg/notifier/model"
 	"github.com/goharbor/harbor/src/pkg/task"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
 )
@@ -37,14 +37,8 @@ func (n *WebhookJob) ToSwagger() *models.WebhookJob {
 		UpdateTime:   strfmt.DateTime(n.UpdateTime),
 	}
 
-	var notifyType string
 	// do the conversion for compatible with old API
-	if n.VendorType == job.WebhookJobVendorType {
-		notifyType = "http"
-	} else if n.VendorType == job.SlackJobVendorType {
-		notifyType = "slack"
-	}
-	webhookJob.NotifyType = notifyType
+	webhookJob.NotifyType = notifierModel.NotifyTypeOf(n.VendorType)
 
 	if n.ExtraAttrs != nil {
 		if eventType, ok := n.ExtraAttrs["event_type"].(string); ok {
@@ -29,16 +29,31 @@ type WebhookPolicy struct {
 // ToSwagger ...
 func (n *WebhookPolicy) ToSwagger() *models.WebhookPolicy {
 	return &models.WebhookPolicy{
-		ID:           n.ID,
-		CreationTime: strfmt.DateTime(n.CreationTime),
-		UpdateTime:   strfmt.DateTime(n.UpdateTime),
-		Creator:      n.Creator,
-		Description:  n.Description,
-		Enabled:      n.Enabled,
-		EventTypes:   n.EventTypes,
-		Name:         n.Name,
-		ProjectID:    n.ProjectID,
-		Targets:      n.ToTargets(),
+		ID:             n.ID,
+		CreationTime:   strfmt.DateTime(n.CreationTime),
+		UpdateTime:     strfmt.DateTime(n.UpdateTime),
+		Creator:        n.Creator,
+		Description:    n.Description,
+		Enabled:        n.Enabled,
+		EventTypes:     n.EventTypes,
+		Name:           n.Name,
+		ProjectID:      n.ProjectID,
+		Targets:        n.ToTargets(),
+		SigningEnabled: n.SigningEnabled,
+		Filters:        n.ToFilters(),
+	}
+}
+
+// ToFilters ...
+func (n *WebhookPolicy) ToFilters() *models.WebhookEventFilters {
+	if n.Filters.IsEmpty() {
+		return nil
+	}
+	return &models.WebhookEventFilters{
+		Repositories: n.Filters.Repositories,
+		Tags:         n.Filters.Tags,
+		Labels:       n.Filters.Labels,
+		Severity:     n.Filters.Severity,
 	}
 }
 
@@ -52,6 +67,7 @@ func (n *WebhookPolicy) ToTargets() []*models.WebhookTargetObject {
 			AuthHeader:     t.AuthHeader,
 			SkipCertVerify: t.SkipCertVerify,
 			PayloadFormat:  models.PayloadFormatType(t.PayloadFormat),
+			Template:       t.Template,
 		})
 	}
 	return results
@@ -159,9 +159,13 @@ func (a *projectAPI) CreateProject(ctx context.Context, params operation.CreateP
 		}
 	}
 
-	// ignore metadata.proxy_speed_kb for non-proxy-cache project
+	// ignore metadata.proxy_speed_kb, the freshness policies and the upstream registries for non-proxy-cache project
 	if req.RegistryID == nil {
 		req.Metadata.ProxySpeedKb = nil
+		req.Metadata.ProxyCacheTTL = nil
+		req.Metadata.ProxyCacheStaleWhileRevalidate = nil
+		req.Metadata.ProxyCacheStaleIfError = nil
+		req.Metadata.ProxyCacheUpstreamRegistries = nil
 	}
 
 	// ignore enable_content_trust metadata for proxy cache project
@@ -325,12 +329,18 @@ func (a *projectAPI) GetLogs(ctx context.Context, params operation.GetLogsParams
 	var auditLogs []*models.AuditLog
 	for _, log := range logs {
 		auditLogs = append(auditLogs, &models.AuditLog{
-			ID:           log.ID,
-			Resource:     log.Resource,
-			ResourceType: log.ResourceType,
-			Username:     log.Username,
-			Operation:    log.Operation,
-			OpTime:       strfmt.DateTime(log.OpTime),
+			ID:                   log.ID,
+			Resource:             log.Resource,
+			ResourceType:         log.ResourceType,
+			Username:             log.Username,
+			Operation:            log.Operation,
+			OperationDescription: log.OperationDescription,
+			IsSuccessful:         log.IsSuccessful,
+			Payload:              log.Payload,
+			ClientIP:             log.ClientIP,
+			UserAgent:            log.UserAgent,
+			AuthMethod:           log.AuthMethod,
+			OpTime:               strfmt.DateTime(log.OpTime),
 		})
 	}
 	return operation.NewGetLogsOK().
@@ -551,14 +561,28 @@ func (a *projectAPI) UpdateProject(ctx context.Context, params operation.UpdateP
 				WithMessagef("project_id in cve_allowlist must be %d but it's %d", p.ProjectID, params.Project.CVEAllowlist.ProjectID))
 		}
 
+		// load the current allowlist to keep the added info of the existing items
+		prev, err := a.projectCtl.Get(ctx, p.ProjectID, project.Metadata(false), project.WithCVEAllowlist())
+		if err != nil {
+			return a.SendError(ctx, err)
+		}
 		if err := lib.JSONCopy(&p.CVEAllowlist, params.Project.CVEAllowlist); err != nil {
 			return a.SendError(ctx, errors.UnknownError(nil).WithMessagef("failed to process cve_allowlist, error: %v", err))
 		}
+		var operator string
+		if secCtx, ok := security.FromContext(ctx); ok {
+			operator = secCtx.GetUsername()
+		}
+		p.CVEAllowlist.InheritAddedInfo(&prev.CVEAllowlist, operator)
 	}
 
-	// ignore metadata.proxy_speed_kb for non-proxy-cache project
+	// ignore metadata.proxy_speed_kb, the freshness policies and the upstream registries for non-proxy-cache project
 	if params.Project.Metadata != nil && !p.IsProxy() {
 		params.Project.Metadata.ProxySpeedKb = nil
+		params.Project.Metadata.ProxyCacheTTL = nil
+		params.Project.Metadata.ProxyCacheStaleWhileRevalidate = nil
+		params.Project.Metadata.ProxyCacheStaleIfError = nil
+		params.Project.Metadata.ProxyCacheUpstreamRegistries = nil
 	}
 
 	// ignore enable_content_trust metadata for proxy cache project
@@ -566,6 +590,16 @@ func (a *projectAPI) UpdateProject(ctx context.Context, params operation.UpdateP
 	if params.Project.Metadata != nil && p.IsProxy() {
 		params.Project.Metadata.EnableContentTrust = nil
 	}
+	if params.Project.Metadata != nil && params.Project.Metadata.ProxyCacheUpstreamRegistries != nil {
+		if !a.isSysAdmin(ctx, rbac.ActionUpdate) {
+			return a.SendError(ctx, errors.ForbiddenError(nil).WithMessage("Only system admin can set the upstream registries of proxy cache project"))
+		}
+		upstreams, err := validateUpstreamRegistries(ctx, *params.Project.Metadata.ProxyCacheUpstreamRegistries)
+		if err != nil {
+			return a.SendError(ctx, err)
+		}
+		params.Project.Metadata.ProxyCacheUpstreamRegistries = &upstreams
+	}
 	if err := lib.JSONCopy(&p.Metadata, params.Project.Metadata); err != nil {
 		log.Warningf("failed to call JSONCopy on project metadata when UpdateProject, error: %v", err)
 	}
@@ -674,6 +708,58 @@ func (a *projectAPI) SetScannerOfProject(ctx context.Context, params operation.S
 	return operation.NewSetScannerOfProjectOK()
 }
 
+func (a *projectAPI) ListScannersOfProject(ctx context.Context, params operation.ListScannersOfProjectParams) middleware.Responder {
+	if err := a.RequireAuthenticated(ctx); err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := a.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionRead, rbac.ResourceScanner); err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	p, err := a.projectCtl.Get(ctx, projectNameOrID, project.Metadata(false))
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	scanners, err := a.scannerCtl.GetRegistrationsByProject(ctx, p.ProjectID)
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	payload := make([]*models.ScannerRegistration, len(scanners))
+	for i, s := range scanners {
+		if err := a.scannerCtl.RetrieveCap(ctx, s); err != nil {
+			log.Warningf(scanner.RetrieveCapFailMsg, err)
+		}
+		payload[i] = model.NewScannerRegistration(s).ToSwagger(ctx)
+	}
+	return operation.NewListScannersOfProjectOK().WithPayload(payload)
+}
+
+func (a *projectAPI) SetScannersOfProject(ctx context.Context, params operation.SetScannersOfProjectParams) middleware.Responder {
+	if err := a.RequireAuthenticated(ctx); err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := a.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionCreate, rbac.ResourceScanner); err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	p, err := a.projectCtl.Get(ctx, projectNameOrID, project.Metadata(false))
+	if err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	if err := a.scannerCtl.SetRegistrationsByProject(ctx, p.ProjectID, params.Payload.Scanners); err != nil {
+		return a.SendError(ctx, err)
+	}
+
+	return operation.NewSetScannersOfProjectOK()
+}
+
 func (a *projectAPI) ListArtifactsOfProject(ctx context.Context, params operation.ListArtifactsOfProjectParams) middleware.Responder {
 	if err := a.RequireAuthenticated(ctx); err != nil {
 		return a.SendError(ctx, err)
@@ -788,19 +874,8 @@ func (a *projectAPI) validateProjectReq(ctx context.Context, req *models.Project
 			return errors.BadRequestError(fmt.Errorf("%d is invalid value of registry_id, it should be geater than 0", *req.RegistryID))
 		}
 
-		registry, err := registry.Ctl.Get(ctx, *req.RegistryID)
-		if err != nil {
-			return fmt.Errorf("failed to get the registry %d: %v", *req.RegistryID, err)
-		}
-		permitted := false
-		for _, t := range config.GetPermittedRegistryTypesForProxyCache() {
-			if string(registry.Type) == t {
-				permitted = true
-				break
-			}
-		}
-		if !permitted {
-			return errors.BadRequestError(fmt.Errorf("unsupported registry type %s", string(registry.Type)))
+		if err := validateProxyCacheRegistry(ctx, *req.RegistryID); err != nil {
+			return err
 		}
 
 		// validate metadata.proxy_speed_kb. It should be an int32
@@ -809,6 +884,22 @@ func (a *projectAPI) validateProjectReq(ctx context.Context, req *models.Project
 				return errors.BadRequestError(nil).WithMessagef("metadata.proxy_speed_kb should by an int32, but got: '%s', err: %s", *ps, err)
 			}
 		}
+
+		// validate metadata.proxy_cache_ttl. It should be a non-negative integer
+		if ttl := req.Metadata.ProxyCacheTTL; ttl != nil {
+			if v, err := strconv.ParseInt(*ttl, 10, 64); err != nil || v < 0 {
+				return errors.BadRequestError(nil).WithMessagef("metadata.proxy_cache_ttl should be a non-negative integer, but got: '%s'", *ttl)
+			}
+		}
+
+		// validate metadata.proxy_cache_upstream_registries. It should be the IDs of the permitted registries
+		if ups := req.Metadata.ProxyCacheUpstreamRegistries; ups != nil {
+			upstreams, err := validateUpstreamRegistries(ctx, *ups)
+			if err != nil {
+				return err
+			}
+			req.Metadata.ProxyCacheUpstreamRegistries = &upstreams
+		}
 	}
 
 	if req.StorageLimit != nil {
@@ -821,6 +912,65 @@ func (a *projectAPI) validateProjectReq(ctx context.Context, req *models.Project
 	return nil
 }
 
+// validateProxyCacheRegistry checks whether the registry exists and its type is permitted for proxy cache
+func validateProxyCacheRegistry(ctx context.Context, registryID int64) error {
+	registry, err := registry.Ctl.Get(ctx, registryID)
+	if err != nil {
+		return fmt.Errorf("failed to get the registry %d: %v", registryID, err)
+	}
+	permitted := false
+	for _, t := range config.GetPermittedRegistryTypesForProxyCache() {
+		if string(registry.Type) == t {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return errors.BadRequestError(fmt.Errorf("unsupported registry type %s", string(registry.Type)))
+	}
+	return nil
+}
+
+// validateUpstreamRegistries validates the comma separated IDs of the upstream registries of proxy cache project
+// and returns the normalized value
+func validateUpstreamRegistries(ctx context.Context, value string) (string, error) {
+	ids, err := parseUpstreamRegistries(value)
+	if err != nil {
+		return "", err
+	}
+	for _, id := range ids {
+		if err := validateProxyCacheRegistry(ctx, id); err != nil {
+			return "", err
+		}
+	}
+	return formatUpstreamRegistries(ids), nil
+}
+
+// parseUpstreamRegistries parses the comma separated IDs of the upstream registries, the empty value means no fallback upstream
+func parseUpstreamRegistries(value string) ([]int64, error) {
+	var ids []int64
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		id, err := strconv.ParseInt(item, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, errors.BadRequestError(nil).WithMessagef("metadata.proxy_cache_upstream_registries should be comma separated registry IDs, but got: '%s'", value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func formatUpstreamRegistries(ids []int64) string {
+	items := make([]string, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(items, ",")
+}
+
 func (a *projectAPI) populateProperties(ctx context.Context, p *project.Project) error {
 	if secCtx, ok := security.FromContext(ctx); ok {
 		if sc, ok := secCtx.(*local.SecurityContext); ok {
@@ -49,7 +49,7 @@ func (p *projectMetadataAPI) AddProjectMetadatas(ctx context.Context, params ope
 		return p.SendError(ctx, err)
 	}
 	metadata := params.Metadata
-	metadata, err := p.validate(metadata)
+	metadata, err := p.validate(ctx, metadata)
 	if err != nil {
 		return p.SendError(ctx, err)
 	}
@@ -84,6 +84,11 @@ func (p *projectMetadataAPI) DeleteProjectMetadata(ctx context.Context, params o
 	if err := p.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionDelete, rbac.ResourceMetadata); err != nil {
 		return p.SendError(ctx, err)
 	}
+	if params.MetaName == proModels.ProMetaProxyCacheUpstreamRegistries {
+		if err := p.requireUpstreamRegistriesAccess(ctx); err != nil {
+			return p.SendError(ctx, err)
+		}
+	}
 	project, err := p.proCtl.Get(ctx, projectNameOrID)
 	if err != nil {
 		return p.SendError(ctx, err)
@@ -118,7 +123,7 @@ func (p *projectMetadataAPI) UpdateProjectMetadata(ctx context.Context, params o
 	metadata := map[string]string{
 		params.MetaName: params.Metadata[params.MetaName],
 	}
-	metadata, err := p.validate(metadata)
+	metadata, err := p.validate(ctx, metadata)
 	if err != nil {
 		return p.SendError(ctx, err)
 	}
@@ -132,7 +137,7 @@ func (p *projectMetadataAPI) UpdateProjectMetadata(ctx context.Context, params o
 	return operation.NewUpdateProjectMetadataOK()
 }
 
-func (p *projectMetadataAPI) validate(metas map[string]string) (map[string]string, error) {
+func (p *projectMetadataAPI) validate(ctx context.Context, metas map[string]string) (map[string]string, error) {
 	if len(metas) != 1 {
 		return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessage("only allow one key/value pair")
 	}
@@ -143,7 +148,8 @@ func (p *projectMetadataAPI) validate(metas map[string]string) (map[string]strin
 
 	switch key {
 	case proModels.ProMetaPublic, proModels.ProMetaEnableContentTrust, proModels.ProMetaEnableContentTrustCosign,
-		proModels.ProMetaAutoSBOMGen, proModels.ProMetaPreventVul, proModels.ProMetaAutoScan, proModels.ProMetaReuseSysCVEAllowlist:
+		proModels.ProMetaAutoSBOMGen, proModels.ProMetaScanExternalSBOM, proModels.ProMetaPreventVul, proModels.ProMetaAutoScan, proModels.ProMetaReuseSysCVEAllowlist,
+		proModels.ProMetaProxyCacheStaleWhileRevalidate, proModels.ProMetaProxyCacheStaleIfError:
 		v, err := strconv.ParseBool(value)
 		if err != nil {
 			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessagef("invalid value: %s", value)
@@ -161,8 +167,32 @@ func (p *projectMetadataAPI) validate(metas map[string]string) (map[string]strin
 			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessagef("invalid value: %s", value)
 		}
 		metas[proModels.ProMetaProxySpeed] = strconv.FormatInt(v, 10)
+	case proModels.ProMetaProxyCacheTTL:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || v < 0 {
+			return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessagef("invalid value: %s", value)
+		}
+		metas[proModels.ProMetaProxyCacheTTL] = strconv.FormatInt(v, 10)
+	case proModels.ProMetaProxyCacheUpstreamRegistries:
+		if err := p.requireUpstreamRegistriesAccess(ctx); err != nil {
+			return nil, err
+		}
+		upstreams, err := validateUpstreamRegistries(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		metas[proModels.ProMetaProxyCacheUpstreamRegistries] = upstreams
 	default:
 		return nil, errors.New(nil).WithCode(errors.BadRequestCode).WithMessagef("invalid key: %s", key)
 	}
 	return metas, nil
 }
+
+// requireUpstreamRegistriesAccess requires the system admin to change the upstream registries of the
+// proxy cache project, the same as the registry of the project
+func (p *projectMetadataAPI) requireUpstreamRegistriesAccess(ctx context.Context) error {
+	if err := p.RequireSystemAccess(ctx, rbac.ActionUpdate, rbac.ResourceProject); err != nil {
+		return errors.ForbiddenError(nil).WithMessage("Only system admin can set the upstream registries of proxy cache project")
+	}
+	return nil
+}
@@ -211,3 +211,24 @@ func (suite *ProjectTestSuite) TestSetScannerOfProject() {
 func TestProjectTestSuite(t *testing.T) {
 	suite.Run(t, &ProjectTestSuite{})
 }
+
+func TestParseUpstreamRegistries(t *testing.T) {
+	ids, err := parseUpstreamRegistries(" 3, 5,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := formatUpstreamRegistries(ids); got != "3,5" {
+		t.Errorf("expected 3,5, but got %s", got)
+	}
+
+	ids, err = parseUpstreamRegistries("")
+	if err != nil || len(ids) != 0 {
+		t.Errorf("expected no upstream registries, but got %v, error: %v", ids, err)
+	}
+
+	for _, value := range []string{"a", "0", "3,-1"} {
+		if _, err := parseUpstreamRegistries(value); err == nil {
+			t.Errorf("expected error for %s", value)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/controller/project"
+	"github.com/goharbor/harbor/src/controller/proxy/prefetch"
+	"github.com/goharbor/harbor/src/pkg/proxy/prefetch/model"
+	"github.com/goharbor/harbor/src/pkg/task"
+	"github.com/goharbor/harbor/src/server/v2.0/models"
+	operation "github.com/goharbor/harbor/src/server/v2.0/restapi/operations/proxy_cache"
+)
+
+func newProxyCacheAPI() *proxyCacheAPI {
+	return &proxyCacheAPI{
+		prefetchCtl: prefetch.Ctl,
+		projectCtl:  project.Ctl,
+	}
+}
+
+type proxyCacheAPI struct {
+	BaseAPI
+	prefetchCtl prefetch.Controller
+	projectCtl  project.Controller
+}
+
+func (p *proxyCacheAPI) GetPrefetchPolicy(ctx context.Context, params operation.GetPrefetchPolicyParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := p.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionRead); err != nil {
+		return p.SendError(ctx, err)
+	}
+	policy, err := p.getPolicy(ctx, projectNameOrID)
+	if err != nil {
+		return p.SendError(ctx, err)
+	}
+	return operation.NewGetPrefetchPolicyOK().WithPayload(toPrefetchPolicyModel(policy))
+}
+
+func (p *proxyCacheAPI) SetPrefetchPolicy(ctx context.Context, params operation.SetPrefetchPolicyParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := p.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionUpdate); err != nil {
+		return p.SendError(ctx, err)
+	}
+	pro, err := p.projectCtl.Get(ctx, projectNameOrID)
+	if err != nil {
+		return p.SendError(ctx, err)
+	}
+	policy := &model.Policy{
+		ProjectID: pro.ProjectID,
+		Enabled:   params.Policy.Enabled,
+		Cron:      params.Policy.Cron,
+	}
+	for _, rule := range params.Policy.Rules {
+		if rule == nil {
+			continue
+		}
+		policy.Rules = append(policy.Rules, &model.Rule{Repository: rule.Repository, Tag: rule.Tag})
+	}
+	for _, pinned := range params.Policy.PinnedTags {
+		if pinned == nil {
+			continue
+		}
+		policy.PinnedTags = append(policy.PinnedTags, &model.PinnedTag{Repository: pinned.Repository, Tag: pinned.Tag})
+	}
+	if _, err = p.prefetchCtl.SetPolicy(ctx, policy); err != nil {
+		return p.SendError(ctx, err)
+	}
+	return operation.NewSetPrefetchPolicyOK()
+}
+
+func (p *proxyCacheAPI) DeletePrefetchPolicy(ctx context.Context, params operation.DeletePrefetchPolicyParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := p.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionUpdate); err != nil {
+		return p.SendError(ctx, err)
+	}
+	policy, err := p.getPolicy(ctx, projectNameOrID)
+	if err != nil {
+		return p.SendError(ctx, err)
+	}
+	if err = p.prefetchCtl.DeletePolicyByProject(ctx, policy.ProjectID); err != nil {
+		return p.SendError(ctx, err)
+	}
+	return operation.NewDeletePrefetchPolicyOK()
+}
+
+func (p *proxyCacheAPI) StartPrefetch(ctx context.Context, params operation.StartPrefetchParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := p.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionUpdate); err != nil {
+		return p.SendError(ctx, err)
+	}
+	policy, err := p.getPolicy(ctx, projectNameOrID)
+	if err != nil {
+		return p.SendError(ctx, err)
+	}
+	id, err := p.prefetchCtl.Start(ctx, policy, task.ExecutionTriggerManual)
+	if err != nil {
+		return p.SendError(ctx, err)
+	}
+	location := fmt.Sprintf("%s/%d", strings.TrimSuffix(params.HTTPRequest.URL.Path, "/"), id)
+	return operation.NewStartPrefetchCreated().WithLocation(location)
+}
+
+func (p *proxyCacheAPI) getPolicy(ctx context.Context, projectNameOrID interface{}) (*model.Policy, error) {
+	pro, err := p.projectCtl.Get(ctx, projectNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return p.prefetchCtl.GetPolicyByProject(ctx, pro.ProjectID)
+}
+
+func toPrefetchPolicyModel(policy *model.Policy) *models.PrefetchPolicy {
+	m := &models.PrefetchPolicy{
+		ID:           policy.ID,
+		Enabled:      policy.Enabled,
+		Cron:         policy.Cron,
+		Rules:        []*models.PrefetchRule{},
+		PinnedTags:   []*models.PinnedTag{},
+		CreationTime: strfmt.DateTime(policy.CreationTime),
+		UpdateTime:   strfmt.DateTime(policy.UpdateTime),
+	}
+	for _, rule := range policy.Rules {
+		m.Rules = append(m.Rules, &models.PrefetchRule{Repository: rule.Repository, Tag: rule.Tag})
+	}
+	for _, pinned := range policy.PinnedTags {
+		m.PinnedTags = append(m.PinnedTags, &models.PinnedTag{Repository: pinned.Repository, Tag: pinned.Tag})
+	}
+	return m
+}
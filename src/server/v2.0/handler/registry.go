@@ -23,9 +23,9 @@ import (
 
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/controller/registry"
-	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/q"
+	adp "github.com/goharbor/harbor/src/pkg/reg/adapter"
 	"github.com/goharbor/harbor/src/pkg/reg/model"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
 	operation "github.com/goharbor/harbor/src/server/v2.0/restapi/operations/registry"
@@ -47,11 +47,14 @@ func (r *registryAPI) CreateRegistry(ctx context.Context, params operation.Creat
 		return r.SendError(ctx, err)
 	}
 	registry := &model.Registry{
-		Name:        params.Registry.Name,
-		Description: params.Registry.Description,
-		Type:        params.Registry.Type,
-		URL:         params.Registry.URL,
-		Insecure:    params.Registry.Insecure,
+		Name:               params.Registry.Name,
+		Description:        params.Registry.Description,
+		Type:               params.Registry.Type,
+		URL:                params.Registry.URL,
+		Insecure:           params.Registry.Insecure,
+		Speed:              params.Registry.Speed,
+		BandwidthWindows:   fromBandwidthWindowModels(params.Registry.BandwidthWindows),
+		MaxConcurrentTasks: int(params.Registry.MaxConcurrentTasks),
 	}
 	if params.Registry.Credential != nil {
 		registry.Credential = &model.Credential{
@@ -155,6 +158,15 @@ func (r *registryAPI) UpdateRegistry(ctx context.Context, params operation.Updat
 		if params.Registry.AccessSecret != nil {
 			registry.Credential.AccessSecret = *params.Registry.AccessSecret
 		}
+		if params.Registry.Speed != nil {
+			registry.Speed = *params.Registry.Speed
+		}
+		if params.Registry.BandwidthWindows != nil {
+			registry.BandwidthWindows = fromBandwidthWindowModels(params.Registry.BandwidthWindows)
+		}
+		if params.Registry.MaxConcurrentTasks != nil {
+			registry.MaxConcurrentTasks = int(*params.Registry.MaxConcurrentTasks)
+		}
 	}
 	if err := r.ctl.Update(ctx, registry); err != nil {
 		return r.SendError(ctx, err)
@@ -226,7 +238,7 @@ func (r *registryAPI) PingRegistry(ctx context.Context, params operation.PingReg
 			registry.Type = *params.Registry.Type
 		}
 		if params.Registry.URL != nil {
-			url, err := lib.ValidateHTTPURL(*params.Registry.URL)
+			url, err := adp.ValidateURL(registry.Type, *params.Registry.URL)
 			if err != nil {
 				return r.SendError(ctx, err)
 			}
@@ -308,3 +320,30 @@ func (r *registryAPI) ListRegistryProviderInfos(ctx context.Context, _ operation
 
 	return operation.NewListRegistryProviderInfosOK().WithPayload(result)
 }
+
+func fromBandwidthWindowModels(windows []*models.BandwidthWindow) []*model.BandwidthWindow {
+	var result []*model.BandwidthWindow
+	for _, window := range windows {
+		if window == nil {
+			continue
+		}
+		result = append(result, &model.BandwidthWindow{
+			Start: window.Start,
+			End:   window.End,
+			Speed: window.Speed,
+		})
+	}
+	return result
+}
+
+func toBandwidthWindowModels(windows []*model.BandwidthWindow) []*models.BandwidthWindow {
+	var result []*models.BandwidthWindow
+	for _, window := range windows {
+		result = append(result, &models.BandwidthWindow{
+			Start: window.Start,
+			End:   window.End,
+			Speed: window.Speed,
+		})
+	}
+	return result
+}
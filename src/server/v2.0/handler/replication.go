@@ -113,6 +113,16 @@ func (r *replicationAPI) CreateReplicationPolicy(ctx context.Context, params ope
 		policy.CopyByChunk = *params.Policy.CopyByChunk
 	}
 
+	if params.Policy.RequireAccessories != nil {
+		policy.RequireAccessories = *params.Policy.RequireAccessories
+	}
+
+	if params.Policy.Reconcile != nil {
+		policy.Reconcile = *params.Policy.Reconcile
+	}
+
+	policy.BandwidthWindows = fromBandwidthWindowModels(params.Policy.BandwidthWindows)
+
 	id, err := r.ctl.CreatePolicy(ctx, policy)
 	if err != nil {
 		return r.SendError(ctx, err)
@@ -181,6 +191,16 @@ func (r *replicationAPI) UpdateReplicationPolicy(ctx context.Context, params ope
 		policy.CopyByChunk = *params.Policy.CopyByChunk
 	}
 
+	if params.Policy.RequireAccessories != nil {
+		policy.RequireAccessories = *params.Policy.RequireAccessories
+	}
+
+	if params.Policy.Reconcile != nil {
+		policy.Reconcile = *params.Policy.Reconcile
+	}
+
+	policy.BandwidthWindows = fromBandwidthWindowModels(params.Policy.BandwidthWindows)
+
 	if err := r.ctl.UpdatePolicy(ctx, policy); err != nil {
 		return r.SendError(ctx, err)
 	}
@@ -239,6 +259,22 @@ func (r *replicationAPI) DeleteReplicationPolicy(ctx context.Context, params ope
 	return operation.NewDeleteReplicationPolicyOK()
 }
 
+func (r *replicationAPI) DryRunReplicationPolicy(ctx context.Context, params operation.DryRunReplicationPolicyParams) middleware.Responder {
+	if err := r.RequireSystemAccess(ctx, rbac.ActionCreate, rbac.ResourceReplication); err != nil {
+		return r.SendError(ctx, err)
+	}
+	policy, err := r.ctl.GetPolicy(ctx, params.ID)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	executionID, err := r.ctl.StartDryRun(ctx, policy)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	location := fmt.Sprintf("/api/v2.0/replication/executions/%d", executionID)
+	return operation.NewDryRunReplicationPolicyCreated().WithLocation(location)
+}
+
 func (r *replicationAPI) StartReplication(ctx context.Context, params operation.StartReplicationParams) middleware.Responder {
 	if err := r.RequireSystemAccess(ctx, rbac.ActionCreate, rbac.ResourceReplication); err != nil {
 		return r.SendError(ctx, err)
@@ -346,6 +382,35 @@ func (r *replicationAPI) GetReplicationExecution(ctx context.Context, params ope
 	return operation.NewGetReplicationExecutionOK().WithPayload(convertExecution(execution))
 }
 
+func (r *replicationAPI) GetReplicationDryRunReport(ctx context.Context, params operation.GetReplicationDryRunReportParams) middleware.Responder {
+	if err := r.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceReplication); err != nil {
+		return r.SendError(ctx, err)
+	}
+	report, err := r.ctl.GetDryRunReport(ctx, params.ID)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	payload := &models.ReplicationDryRunReport{
+		Create:         int64(report.Create),
+		Overwrite:      int64(report.Overwrite),
+		Skip:           int64(report.Skip),
+		Delete:         int64(report.Delete),
+		EstimatedBytes: report.EstimatedBytes,
+	}
+	for _, item := range report.Items {
+		payload.Items = append(payload.Items, &models.ReplicationDryRunItem{
+			SourceRepository:      item.SourceRepository,
+			DestinationRepository: item.DestinationRepository,
+			Reference:             item.Reference,
+			Digest:                item.Digest,
+			Action:                item.Action,
+			Reason:                item.Reason,
+			Bytes:                 item.Bytes,
+		})
+	}
+	return operation.NewGetReplicationDryRunReportOK().WithPayload(payload)
+}
+
 func (r *replicationAPI) ListReplicationTasks(ctx context.Context, params operation.ListReplicationTasksParams) middleware.Responder {
 	if err := r.RequireSystemAccess(ctx, rbac.ActionList, rbac.ResourceReplication); err != nil {
 		return r.SendError(ctx, err)
@@ -446,6 +511,9 @@ func convertReplicationPolicy(policy *repctlmodel.Policy) *models.ReplicationPol
 		Speed:                     &policy.Speed,
 		UpdateTime:                strfmt.DateTime(policy.UpdateTime),
 		CopyByChunk:               &policy.CopyByChunk,
+		RequireAccessories:        &policy.RequireAccessories,
+		Reconcile:                 &policy.Reconcile,
+		BandwidthWindows:          toBandwidthWindowModels(policy.BandwidthWindows),
 	}
 	if policy.SrcRegistry != nil {
 		p.SrcRegistry = convertRegistry(policy.SrcRegistry)
@@ -478,15 +546,18 @@ func convertReplicationPolicy(policy *repctlmodel.Policy) *models.ReplicationPol
 
 func convertRegistry(registry *model.Registry) *models.Registry {
 	r := &models.Registry{
-		CreationTime: strfmt.DateTime(registry.CreationTime),
-		Description:  registry.Description,
-		ID:           registry.ID,
-		Insecure:     registry.Insecure,
-		Name:         registry.Name,
-		Status:       registry.Status,
-		Type:         string(registry.Type),
-		UpdateTime:   strfmt.DateTime(registry.UpdateTime),
-		URL:          registry.URL,
+		CreationTime:       strfmt.DateTime(registry.CreationTime),
+		Description:        registry.Description,
+		ID:                 registry.ID,
+		Insecure:           registry.Insecure,
+		Name:               registry.Name,
+		Status:             registry.Status,
+		Type:               string(registry.Type),
+		UpdateTime:         strfmt.DateTime(registry.UpdateTime),
+		URL:                registry.URL,
+		Speed:              registry.Speed,
+		BandwidthWindows:   toBandwidthWindowModels(registry.BandwidthWindows),
+		MaxConcurrentTasks: int64(registry.MaxConcurrentTasks),
 	}
 	if registry.Credential != nil {
 		credential := &models.RegistryCredential{
@@ -508,6 +579,7 @@ func convertExecution(execution *replication.Execution) *models.ReplicationExecu
 		StatusText: execution.StatusMessage,
 		StartTime:  strfmt.DateTime(execution.StartTime),
 		EndTime:    strfmt.DateTime(execution.EndTime),
+		DryRun:     execution.DryRun,
 	}
 	// keep backward compatibility
 	if execution.Metrics != nil {
@@ -549,6 +621,8 @@ func convertTask(task *replication.Task) *models.ReplicationTask {
 		ResourceType: task.ResourceType,
 		SrcResource:  task.SourceResource,
 		DstResource:  task.DestinationResource,
+		CopiedBlobs:  task.CopiedBlobs,
+		CopiedBytes:  task.CopiedBytes,
 		StartTime:    strfmt.DateTime(task.StartTime),
 		EndTime:      strfmt.DateTime(task.EndTime),
 	}
@@ -27,10 +27,14 @@ import (
 	projectCtl "github.com/goharbor/harbor/src/controller/project"
 	retentionCtl "github.com/goharbor/harbor/src/controller/retention"
 	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/pkg"
+	immumodel "github.com/goharbor/harbor/src/pkg/immutable/model"
 	"github.com/goharbor/harbor/src/pkg/project/metadata"
+	"github.com/goharbor/harbor/src/pkg/retention"
 	"github.com/goharbor/harbor/src/pkg/retention/policy"
+	"github.com/goharbor/harbor/src/pkg/retention/policy/action"
 	"github.com/goharbor/harbor/src/pkg/task"
 	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
@@ -109,6 +113,54 @@ var (
 				Action:       "retain",
 				Params:       []*models.RetentionRuleParamMetadata{},
 			},
+			{
+				RuleTemplate: "latestPushedKWithReferences",
+				DisplayText:  "the most recently pushed # artifacts with the referenced and signed artifacts",
+				Action:       "retain",
+				Params: []*models.RetentionRuleParamMetadata{
+					{
+						Type:     "int",
+						Unit:     "COUNT",
+						Required: true,
+					},
+				},
+			},
+			{
+				RuleTemplate: "storageBudgetGB",
+				DisplayText:  "the most recently pushed artifacts within # GB",
+				Action:       "retain",
+				Params: []*models.RetentionRuleParamMetadata{
+					{
+						Type:     "int",
+						Unit:     "GB",
+						Required: true,
+					},
+				},
+			},
+			{
+				RuleTemplate: "nDaysSinceLastPullByAnyClient",
+				DisplayText:  "pulled by tag or digest within the last # days",
+				Action:       "retain",
+				Params: []*models.RetentionRuleParamMetadata{
+					{
+						Type:     "int",
+						Unit:     "DAYS",
+						Required: true,
+					},
+				},
+			},
+			{
+				RuleTemplate: "scanPassed",
+				DisplayText:  "scanned without vulnerabilities of # severity or above",
+				Action:       "retain",
+				Params: []*models.RetentionRuleParamMetadata{
+					{
+						Type:     "string",
+						Unit:     "SEVERITY",
+						Required: true,
+					},
+				},
+			},
 		},
 		ScopeSelectors: []*models.RetentionSelectorMetadata{
 			{
@@ -130,6 +182,11 @@ var (
 				},
 			},
 		},
+		Actions: []string{
+			action.Retain,
+			action.Delete,
+			action.Archive,
+		},
 	}
 )
 
@@ -187,6 +244,10 @@ func (r *retentionAPI) CreateRetention(ctx context.Context, params operation.Cre
 		return r.SendError(ctx, errors.BadRequestError(fmt.Errorf("scope %s is not support", p.Scope.Level)))
 	}
 
+	if err := r.checkArchiveProject(ctx, p); err != nil {
+		return r.SendError(ctx, err)
+	}
+
 	old, err := r.proMetaMgr.Get(ctx, p.Scope.Reference, "retention_id")
 	if err != nil {
 		return r.SendError(ctx, err)
@@ -227,6 +288,10 @@ func (r *retentionAPI) UpdateRetention(ctx context.Context, params operation.Upd
 		return r.SendError(ctx, err)
 	}
 
+	if err := r.checkArchiveProject(ctx, p); err != nil {
+		return r.SendError(ctx, err)
+	}
+
 	if err := r.retentionCtl.UpdateRetention(ctx, p); err != nil {
 		return r.SendError(ctx, err)
 	}
@@ -247,6 +312,34 @@ func (r *retentionAPI) checkRuleConflict(p *policy.Metadata) error {
 	return nil
 }
 
+// checkArchiveProject checks the archive projects of the archive rules exist and are not the project of the policy,
+// the artifacts are copied into the archive projects by the jobservice, so the operator must be able to push to them
+func (r *retentionAPI) checkArchiveProject(ctx context.Context, p *policy.Metadata) error {
+	for n, rule := range p.Rules {
+		if rule.Action != action.Archive {
+			continue
+		}
+		name, _ := rule.Parameters[action.ParameterArchiveProject].(string)
+		if len(name) == 0 {
+			return errors.BadRequestError(fmt.Errorf("rule %d: the archive project is required", n))
+		}
+		pro, err := r.projectCtl.GetByName(ctx, name)
+		if err != nil {
+			if errors.IsNotFoundErr(err) {
+				return errors.BadRequestError(fmt.Errorf("rule %d: the archive project %s not found", n, name))
+			}
+			return err
+		}
+		if p.Scope != nil && pro.ProjectID == p.Scope.Reference {
+			return errors.BadRequestError(fmt.Errorf("rule %d: the archive project can not be the project of the policy", n))
+		}
+		if err = r.RequireProjectAccess(ctx, pro.ProjectID, rbac.ActionCreate, rbac.ResourceArtifact); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *retentionAPI) DeleteRetention(ctx context.Context, params operation.DeleteRetentionParams) middleware.Responder {
 	p, err := r.retentionCtl.GetRetention(ctx, params.ID)
 	if err != nil {
@@ -272,6 +365,10 @@ func (r *retentionAPI) TriggerRetentionExecution(ctx context.Context, params ope
 	if err != nil {
 		return r.SendError(ctx, err)
 	}
+	// the access to the archive projects may be revoked after the policy is saved
+	if err = r.checkArchiveProject(ctx, p); err != nil {
+		return r.SendError(ctx, err)
+	}
 
 	eid, err := r.retentionCtl.TriggerRetentionExec(ctx, params.ID, task.ExecutionTriggerManual, params.Body.DryRun)
 	if err != nil {
@@ -338,6 +435,59 @@ func (r *retentionAPI) ListRetentionExecutions(ctx context.Context, params opera
 		WithPayload(payload)
 }
 
+func (r *retentionAPI) SimulateRetention(ctx context.Context, params operation.SimulateRetentionParams) middleware.Responder {
+	query, err := r.BuildQuery(ctx, nil, nil, params.Page, params.PageSize)
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+	sim := params.Simulation
+	var (
+		total   int64
+		reports []*retention.SimulationReport
+	)
+	switch {
+	case sim.Policy != nil:
+		p := model.NewRetentionPolicyFromSwagger(sim.Policy).Metadata
+		if p.Scope == nil {
+			return r.SendError(ctx, errors.BadRequestError(fmt.Errorf("the scope of the policy is required")))
+		}
+		if err := r.checkRuleConflict(p); err != nil {
+			return r.SendError(ctx, errors.ConflictError(err))
+		}
+		if err := r.requireAccess(ctx, p, rbac.ActionRead); err != nil {
+			return r.SendError(ctx, err)
+		}
+		total, reports, err = r.retentionCtl.SimulateRetention(ctx, p, query)
+	case len(sim.ImmutableRules) > 0:
+		if err := r.RequireProjectAccess(ctx, sim.ProjectID, rbac.ActionList, rbac.ResourceImmutableTag); err != nil {
+			return r.SendError(ctx, err)
+		}
+		rules := make([]*immumodel.Metadata, 0, len(sim.ImmutableRules))
+		for _, ir := range sim.ImmutableRules {
+			rule := &immumodel.Metadata{}
+			if err := lib.JSONCopy(rule, ir); err != nil {
+				return r.SendError(ctx, err)
+			}
+			rule.ProjectID = sim.ProjectID
+			rules = append(rules, rule)
+		}
+		total, reports, err = r.retentionCtl.SimulateImmutable(ctx, sim.ProjectID, rules, query)
+	default:
+		return r.SendError(ctx, errors.BadRequestError(fmt.Errorf("either the retention policy or the immutable rules are required")))
+	}
+	if err != nil {
+		return r.SendError(ctx, err)
+	}
+
+	var payload []*models.RetentionSimulationReport
+	if err := lib.JSONCopy(&payload, reports); err != nil {
+		return r.SendError(ctx, err)
+	}
+	return operation.NewSimulateRetentionOK().WithXTotalCount(total).
+		WithLink(r.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String()).
+		WithPayload(payload)
+}
+
 func (r *retentionAPI) ListRetentionTasks(ctx context.Context, params operation.ListRetentionTasksParams) middleware.Responder {
 	query, err := r.BuildQuery(ctx, nil, nil, params.Page, params.PageSize)
 	if err != nil {
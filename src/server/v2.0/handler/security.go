@@ -21,7 +21,6 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 
 	"github.com/goharbor/harbor/src/common/rbac"
-	"github.com/goharbor/harbor/src/pkg/scan/scanner"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
 	securityModel "github.com/goharbor/harbor/src/server/v2.0/restapi/operations/securityhub"
 
@@ -107,15 +106,11 @@ func (s *securityAPI) ListVulnerabilities(ctx context.Context, params securityMo
 	if err != nil {
 		return s.SendError(ctx, err)
 	}
-	scannerUUID, err := scanner.Mgr.DefaultScannerUUID(ctx)
-	if err != nil || len(scannerUUID) == 0 {
-		return securityModel.NewListVulnerabilitiesOK().WithPayload([]*models.VulnerabilityItem{}).WithXTotalCount(0)
-	}
-	cnt, err := s.controller.CountVuls(ctx, scannerUUID, 0, *params.TuneCount, query)
+	cnt, err := s.controller.CountVuls(ctx, 0, *params.TuneCount, query)
 	if err != nil {
 		return s.SendError(ctx, err)
 	}
-	vuls, err := s.controller.ListVuls(ctx, scannerUUID, 0, *params.WithTag, query)
+	vuls, err := s.controller.ListVuls(ctx, 0, *params.WithTag, query)
 	if err != nil {
 		return s.SendError(ctx, err)
 	}
@@ -20,6 +20,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 
 	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/common/security"
 	"github.com/goharbor/harbor/src/pkg/allowlist"
 	"github.com/goharbor/harbor/src/pkg/allowlist/models"
 	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
@@ -44,8 +45,24 @@ func (s systemCVEAllowListAPI) PutSystemCVEAllowlist(ctx context.Context, params
 	l := models.CVEAllowlist{}
 	l.ExpiresAt = params.Allowlist.ExpiresAt
 	for _, it := range params.Allowlist.Items {
-		l.Items = append(l.Items, models.CVEAllowlistItem{CVEID: it.CVEID})
+		l.Items = append(l.Items, models.CVEAllowlistItem{
+			CVEID:         it.CVEID,
+			ExpiresAt:     it.ExpiresAt,
+			Justification: it.Justification,
+			Approver:      it.Approver,
+			Repository:    it.Repository,
+			Package:       it.Package,
+		})
 	}
+	prev, err := s.mgr.GetSys(ctx)
+	if err != nil {
+		return s.SendError(ctx, err)
+	}
+	var operator string
+	if sc, ok := security.FromContext(ctx); ok {
+		operator = sc.GetUsername()
+	}
+	l.InheritAddedInfo(prev, operator)
 	if err := s.mgr.SetSys(ctx, l); err != nil {
 		return s.SendError(ctx, err)
 	}
@@ -17,22 +17,28 @@ package handler
 import (
 	"context"
 	"fmt"
+	"net/mail"
 	"strings"
+	"time"
 
+	"github.com/bmatcuk/doublestar"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 
 	"github.com/goharbor/harbor/src/common/rbac"
 	"github.com/goharbor/harbor/src/common/utils"
+	"github.com/goharbor/harbor/src/controller/event"
 	"github.com/goharbor/harbor/src/controller/task"
 	webhook_ctl "github.com/goharbor/harbor/src/controller/webhook"
-	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/lib"
 	"github.com/goharbor/harbor/src/lib/errors"
 	"github.com/goharbor/harbor/src/lib/log"
 	"github.com/goharbor/harbor/src/lib/q"
 	"github.com/goharbor/harbor/src/pkg/notification"
 	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/pkg/notifier/formats"
+	notifierModel "github.com/goharbor/harbor/src/pkg/notifier/model"
+	"github.com/goharbor/harbor/src/pkg/scan/vuln"
 	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
 	"github.com/goharbor/harbor/src/server/v2.0/models"
 	"github.com/goharbor/harbor/src/server/v2.0/restapi/operations/webhook"
@@ -80,7 +86,7 @@ func (n *webhookAPI) requireExecutionInPolicy(ctx context.Context, execID, polic
 		return err
 	}
 
-	if exec.VendorID == policyID && (exec.VendorType == job.WebhookJobVendorType || exec.VendorType == job.SlackJobVendorType) {
+	if exec.VendorID == policyID && len(notifierModel.NotifyTypeOf(exec.VendorType)) > 0 {
 		return nil
 	}
 
@@ -148,12 +154,15 @@ func (n *webhookAPI) CreateWebhookPolicyOfProject(ctx context.Context, params we
 		log.Warningf("failed to call JSONCopy on notification policy when CreateWebhookPolicyOfProject, error: %v", err)
 	}
 
-	if ok, err := n.validateEventTypes(policy); !ok {
+	if ok, err := n.validateEventTypes(policy, notification.GetSupportedEventTypes()); !ok {
 		return n.SendError(ctx, err)
 	}
 	if ok, err := n.validateTargets(policy); !ok {
 		return n.SendError(ctx, err)
 	}
+	if ok, err := n.validateFilters(policy); !ok {
+		return n.SendError(ctx, err)
+	}
 
 	projectID, err := getProjectID(ctx, projectNameOrID)
 	if err != nil {
@@ -187,12 +196,15 @@ func (n *webhookAPI) UpdateWebhookPolicyOfProject(ctx context.Context, params we
 		log.Warningf("failed to call JSONCopy on notification policy when UpdateWebhookPolicyOfProject, error: %v", err)
 	}
 
-	if ok, err := n.validateEventTypes(policy); !ok {
+	if ok, err := n.validateEventTypes(policy, notification.GetSupportedEventTypes()); !ok {
 		return n.SendError(ctx, err)
 	}
 	if ok, err := n.validateTargets(policy); !ok {
 		return n.SendError(ctx, err)
 	}
+	if ok, err := n.validateFilters(policy); !ok {
+		return n.SendError(ctx, err)
+	}
 
 	policy.ID = policyID
 	policy.ProjectID = projectID
@@ -350,6 +362,78 @@ func (n *webhookAPI) GetLogsOfWebhookTask(ctx context.Context, params webhook.Ge
 	return webhook.NewGetLogsOfWebhookTaskOK().WithPayload(string(l))
 }
 
+func (n *webhookAPI) RotateWebhookSigningSecret(ctx context.Context, params webhook.RotateWebhookSigningSecretParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := n.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionUpdate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requirePolicyInProject(ctx, projectNameOrID, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	var overlap time.Duration
+	if params.Rotation != nil {
+		if params.Rotation.OverlapSeconds < 0 {
+			return n.SendError(ctx, errors.BadRequestError(nil).WithMessage("overlap_seconds must not be negative"))
+		}
+		overlap = time.Duration(params.Rotation.OverlapSeconds) * time.Second
+	}
+	now := time.Now()
+	secret, err := n.webhookCtl.RotateSigningSecret(ctx, params.WebhookPolicyID, overlap)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	payload := &models.WebhookSigningSecret{Secret: secret}
+	if overlap > 0 {
+		payload.PreviousSecretExpiresAt = strfmt.DateTime(now.Add(overlap))
+	}
+	return webhook.NewRotateWebhookSigningSecretCreated().WithPayload(payload)
+}
+
+func (n *webhookAPI) DeleteWebhookSigningSecret(ctx context.Context, params webhook.DeleteWebhookSigningSecretParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	if err := n.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionUpdate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requirePolicyInProject(ctx, projectNameOrID, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.webhookCtl.DisableSigning(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	return webhook.NewDeleteWebhookSigningSecretOK()
+}
+
+func (n *webhookAPI) RedeliverWebhookExecution(ctx context.Context, params webhook.RedeliverWebhookExecutionParams) middleware.Responder {
+	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
+	projectID, err := getProjectID(ctx, projectNameOrID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.RequireProjectAccess(ctx, projectID, rbac.ActionUpdate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requirePolicyInProject(ctx, projectID, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireExecutionInPolicy(ctx, params.ExecutionID, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	id, err := n.webhookCtl.RedeliverExecution(ctx, params.WebhookPolicyID, params.ExecutionID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	// the location points to the new execution: .../executions/{execution_id}/redeliver -> .../executions/{id}
+	path := strings.TrimSuffix(params.HTTPRequest.URL.Path, "/")
+	path = path[:strings.LastIndex(path, "/")]
+	path = path[:strings.LastIndex(path, "/")]
+	location := fmt.Sprintf("%s/%d", path, id)
+	return webhook.NewRedeliverWebhookExecutionCreated().WithLocation(location)
+}
+
 func (n *webhookAPI) LastTrigger(ctx context.Context, params webhook.LastTriggerParams) middleware.Responder {
 	projectNameOrID := parseProjectNameOrID(params.ProjectNameOrID, params.XIsResourceName)
 	if err := n.RequireProjectAccess(ctx, projectNameOrID, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
@@ -407,20 +491,42 @@ func (n *webhookAPI) validateTargets(policy *policy_model.Policy) (bool, error)
 		return false, errors.New(nil).WithMessagef("empty notification target with policy %s", policy.Name).WithCode(errors.BadRequestCode)
 	}
 	for i, target := range policy.Targets {
-		url, err := utils.ParseEndpoint(target.Address)
-		if err != nil {
-			return false, errors.New(err).WithCode(errors.BadRequestCode)
-		}
-		// Prevent SSRF security issue #3755
-		target.Address = url.Scheme + "://" + url.Host + url.Path
-
 		if !isNotifyTypeSupported(target.Type) {
 			return false, errors.New(nil).WithMessagef("unsupported target type %s with policy %s", target.Type, policy.Name).WithCode(errors.BadRequestCode)
 		}
-		// don't allow set the payload format for slack type
+
+		if target.Type == notifierModel.NotifyTypeEmail {
+			// the address of the email target is a comma separated list of the recipients
+			recipients, err := mail.ParseAddressList(target.Address)
+			if err != nil {
+				return false, errors.New(err).WithMessagef("invalid email recipients %s", target.Address).WithCode(errors.BadRequestCode)
+			}
+			addresses := make([]string, 0, len(recipients))
+			for _, recipient := range recipients {
+				addresses = append(addresses, recipient.Address)
+			}
+			policy.Targets[i].Address = strings.Join(addresses, ",")
+		} else {
+			url, err := utils.ParseEndpoint(target.Address)
+			if err != nil {
+				return false, errors.New(err).WithCode(errors.BadRequestCode)
+			}
+			// Prevent SSRF security issue #3755
+			target.Address = url.Scheme + "://" + url.Host + url.Path
+		}
+
+		// only allow set the payload format for http type, the other types have their own payload
 		// slack should be migrated as a kind of payload in the future
-		if len(target.PayloadFormat) > 0 && target.Type == "slack" {
-			return false, errors.New(nil).WithMessage("set payload format is not allowed for slack").WithCode(errors.BadRequestCode)
+		if len(target.PayloadFormat) > 0 && target.Type != notifierModel.NotifyTypeHTTP {
+			return false, errors.New(nil).WithMessagef("set payload format is not allowed for %s", target.Type).WithCode(errors.BadRequestCode)
+		}
+
+		if target.Type == notifierModel.NotifyTypeCustom {
+			if _, err := formats.ParseTemplate(target.Template); err != nil {
+				return false, errors.New(err).WithCode(errors.BadRequestCode)
+			}
+		} else if len(target.Template) > 0 {
+			return false, errors.New(nil).WithMessagef("set template is not allowed for %s", target.Type).WithCode(errors.BadRequestCode)
 		}
 
 		if len(target.PayloadFormat) > 0 && !isPayloadFormatSupported(target.PayloadFormat) {
@@ -434,12 +540,41 @@ func (n *webhookAPI) validateTargets(policy *policy_model.Policy) (bool, error)
 	return true, nil
 }
 
-func (n *webhookAPI) validateEventTypes(policy *policy_model.Policy) (bool, error) {
+func (n *webhookAPI) validateFilters(policy *policy_model.Policy) (bool, error) {
+	filters := policy.Filters
+	if filters.IsEmpty() {
+		policy.Filters = nil
+		return true, nil
+	}
+	for _, pattern := range append(append([]string{}, filters.Repositories...), filters.Tags...) {
+		if _, err := doublestar.Match(pattern, ""); err != nil {
+			return false, errors.New(err).WithMessagef("invalid filter pattern %s", pattern).WithCode(errors.BadRequestCode)
+		}
+	}
+	if len(filters.Severity) > 0 {
+		if !isSeveritySupported(filters.Severity) {
+			return false, errors.New(nil).WithMessagef("unsupported severity %s", filters.Severity).WithCode(errors.BadRequestCode)
+		}
+		scanning := false
+		for _, eventType := range policy.EventTypes {
+			if eventType == event.TopicScanningCompleted {
+				scanning = true
+				break
+			}
+		}
+		if !scanning {
+			return false, errors.New(nil).WithMessagef("the severity filter requires the event type %s", event.TopicScanningCompleted).WithCode(errors.BadRequestCode)
+		}
+	}
+	return true, nil
+}
+
+func (n *webhookAPI) validateEventTypes(policy *policy_model.Policy, supported []notification.EventType) (bool, error) {
 	if len(policy.EventTypes) == 0 {
 		return false, errors.New(nil).WithMessage("empty event type").WithCode(errors.BadRequestCode)
 	}
 	for _, eventType := range policy.EventTypes {
-		if !isEventTypeSupported(eventType) {
+		if !isEventTypeSupported(eventType, supported) {
 			return false, errors.New(nil).WithMessagef("unsupported event type %s", eventType).WithCode(errors.BadRequestCode)
 		}
 	}
@@ -475,8 +610,8 @@ func (n *webhookAPI) constructPolicyWithTriggerTime(ctx context.Context, policie
 	return res, nil
 }
 
-func isEventTypeSupported(eventType string) bool {
-	for _, t := range notification.GetSupportedEventTypes() {
+func isEventTypeSupported(eventType string, supported []notification.EventType) bool {
+	for _, t := range supported {
 		if t.String() == eventType {
 			return true
 		}
@@ -504,3 +639,12 @@ func isPayloadFormatSupported(payloadFormat string) bool {
 
 	return false
 }
+
+func isSeveritySupported(severity string) bool {
+	switch vuln.Severity(severity) {
+	case vuln.Negligible, vuln.Low, vuln.Medium, vuln.High, vuln.Critical:
+		return true
+	default:
+		return false
+	}
+}
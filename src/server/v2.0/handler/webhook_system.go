@@ -0,0 +1,278 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/goharbor/harbor/src/common/rbac"
+	"github.com/goharbor/harbor/src/lib"
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/pkg/notification"
+	policy_model "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+	"github.com/goharbor/harbor/src/server/v2.0/handler/model"
+	"github.com/goharbor/harbor/src/server/v2.0/models"
+	"github.com/goharbor/harbor/src/server/v2.0/restapi/operations/webhook"
+)
+
+func (n *webhookAPI) requireSystemPolicy(ctx context.Context, policyID int64) error {
+	return n.requirePolicyInProject(ctx, policy_model.SystemProjectID, policyID)
+}
+
+func (n *webhookAPI) ListSystemWebhookPolicies(ctx context.Context, params webhook.ListSystemWebhookPoliciesParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionList, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	query, err := n.BuildQuery(ctx, params.Q, params.Sort, params.Page, params.PageSize)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	query.Keywords["ProjectID"] = policy_model.SystemProjectID
+
+	total, err := n.webhookCtl.CountPolicies(ctx, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policies, err := n.webhookCtl.ListPolicies(ctx, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+	var results []*models.WebhookPolicy
+	for _, p := range policies {
+		results = append(results, model.NewWebhookPolicy(p).ToSwagger())
+	}
+
+	return webhook.NewListSystemWebhookPoliciesOK().
+		WithXTotalCount(total).
+		WithLink(n.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String()).
+		WithPayload(results)
+}
+
+func (n *webhookAPI) CreateSystemWebhookPolicy(ctx context.Context, params webhook.CreateSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionCreate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policy := &policy_model.Policy{}
+	if err := lib.JSONCopy(policy, params.Policy); err != nil {
+		log.Warningf("failed to call JSONCopy on notification policy when CreateSystemWebhookPolicy, error: %v", err)
+	}
+
+	if ok, err := n.validateEventTypes(policy, notification.GetSupportedSystemEventTypes()); !ok {
+		return n.SendError(ctx, err)
+	}
+	if ok, err := n.validateTargets(policy); !ok {
+		return n.SendError(ctx, err)
+	}
+	if ok, err := n.validateFilters(policy); !ok {
+		return n.SendError(ctx, err)
+	}
+
+	policy.ProjectID = policy_model.SystemProjectID
+	id, err := n.webhookCtl.CreatePolicy(ctx, policy)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	location := fmt.Sprintf("%s/%d", strings.TrimSuffix(params.HTTPRequest.URL.Path, "/"), id)
+	return webhook.NewCreateSystemWebhookPolicyCreated().WithLocation(location)
+}
+
+func (n *webhookAPI) GetSystemWebhookPolicy(ctx context.Context, params webhook.GetSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireSystemPolicy(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	policy, err := n.webhookCtl.GetPolicy(ctx, params.WebhookPolicyID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	return webhook.NewGetSystemWebhookPolicyOK().WithPayload(model.NewWebhookPolicy(policy).ToSwagger())
+}
+
+func (n *webhookAPI) UpdateSystemWebhookPolicy(ctx context.Context, params webhook.UpdateSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionUpdate, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	policyID := params.WebhookPolicyID
+	if err := n.requireSystemPolicy(ctx, policyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	policy := &policy_model.Policy{}
+	if err := lib.JSONCopy(policy, params.Policy); err != nil {
+		log.Warningf("failed to call JSONCopy on notification policy when UpdateSystemWebhookPolicy, error: %v", err)
+	}
+
+	if ok, err := n.validateEventTypes(policy, notification.GetSupportedSystemEventTypes()); !ok {
+		return n.SendError(ctx, err)
+	}
+	if ok, err := n.validateTargets(policy); !ok {
+		return n.SendError(ctx, err)
+	}
+	if ok, err := n.validateFilters(policy); !ok {
+		return n.SendError(ctx, err)
+	}
+
+	policy.ID = policyID
+	policy.ProjectID = policy_model.SystemProjectID
+	if err := n.webhookCtl.UpdatePolicy(ctx, policy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	return webhook.NewUpdateSystemWebhookPolicyOK()
+}
+
+func (n *webhookAPI) DeleteSystemWebhookPolicy(ctx context.Context, params webhook.DeleteSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionDelete, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireSystemPolicy(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.webhookCtl.DeletePolicy(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	return webhook.NewDeleteSystemWebhookPolicyOK()
+}
+
+func (n *webhookAPI) ListExecutionsOfSystemWebhookPolicy(ctx context.Context, params webhook.ListExecutionsOfSystemWebhookPolicyParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireSystemPolicy(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	query, err := n.BuildQuery(ctx, params.Q, params.Sort, params.Page, params.PageSize)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	total, err := n.webhookCtl.CountExecutions(ctx, params.WebhookPolicyID, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	executions, err := n.webhookCtl.ListExecutions(ctx, params.WebhookPolicyID, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	var payloads []*models.Execution
+	for _, exec := range executions {
+		p, err := convertExecutionToPayload(exec)
+		if err != nil {
+			return n.SendError(ctx, err)
+		}
+		payloads = append(payloads, p)
+	}
+
+	return webhook.NewListExecutionsOfSystemWebhookPolicyOK().WithPayload(payloads).WithXTotalCount(total).
+		WithLink(n.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String())
+}
+
+func (n *webhookAPI) ListTasksOfSystemWebhookExecution(ctx context.Context, params webhook.ListTasksOfSystemWebhookExecutionParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireSystemPolicy(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireExecutionInPolicy(ctx, params.ExecutionID, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	query, err := n.BuildQuery(ctx, params.Q, params.Sort, params.Page, params.PageSize)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	total, err := n.webhookCtl.CountTasks(ctx, params.ExecutionID, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	tasks, err := n.webhookCtl.ListTasks(ctx, params.ExecutionID, query)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	var payloads []*models.Task
+	for _, task := range tasks {
+		p, err := convertTaskToPayload(task)
+		if err != nil {
+			return n.SendError(ctx, err)
+		}
+		payloads = append(payloads, p)
+	}
+
+	return webhook.NewListTasksOfSystemWebhookExecutionOK().WithPayload(payloads).WithXTotalCount(total).
+		WithLink(n.Links(ctx, params.HTTPRequest.URL, total, query.PageNumber, query.PageSize).String())
+}
+
+func (n *webhookAPI) GetLogsOfSystemWebhookTask(ctx context.Context, params webhook.GetLogsOfSystemWebhookTaskParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireSystemPolicy(ctx, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireExecutionInPolicy(ctx, params.ExecutionID, params.WebhookPolicyID); err != nil {
+		return n.SendError(ctx, err)
+	}
+	if err := n.requireTaskInExecution(ctx, params.TaskID, params.ExecutionID); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	l, err := n.webhookCtl.GetTaskLog(ctx, params.TaskID)
+	if err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	return webhook.NewGetLogsOfSystemWebhookTaskOK().WithPayload(string(l))
+}
+
+func (n *webhookAPI) GetSupportedSystemEventTypes(ctx context.Context, _ webhook.GetSupportedSystemEventTypesParams) middleware.Responder {
+	if err := n.RequireSystemAccess(ctx, rbac.ActionRead, rbac.ResourceNotificationPolicy); err != nil {
+		return n.SendError(ctx, err)
+	}
+
+	var notificationTypes = &models.SupportedWebhookEventTypes{}
+	for _, notifyType := range notification.GetSupportedNotifyTypes() {
+		notificationTypes.NotifyType = append(notificationTypes.NotifyType, models.NotifyType(notifyType))
+	}
+
+	for _, eventType := range notification.GetSupportedSystemEventTypes() {
+		notificationTypes.EventType = append(notificationTypes.EventType, models.EventType(eventType))
+	}
+	// currently only http type support payload format
+	httpPayloadFormats := &models.PayloadFormat{NotifyType: models.NotifyType("http")}
+	for _, formatType := range notification.GetSupportedPayloadFormats() {
+		httpPayloadFormats.Formats = append(httpPayloadFormats.Formats, models.PayloadFormatType(formatType))
+	}
+	notificationTypes.PayloadFormats = []*models.PayloadFormat{httpPayloadFormats}
+
+	return webhook.NewGetSupportedSystemEventTypesOK().WithPayload(notificationTypes)
+}
@@ -232,6 +232,42 @@ func (suite *WebhookTestSuite) TestGetSupportedEventTypes() {
 	suite.Len(body.NotifyType, len(notification.GetSupportedNotifyTypes()))
 }
 
+func (suite *WebhookTestSuite) TestCreateSystemWebhookPolicy() {
+	suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(true)
+	suite.webhookCtl.On("CreatePolicy", mock.Anything, mock.Anything).Return(int64(1), nil)
+	{
+		// system event type is not allowed for the project policy
+		url := fmt.Sprintf("/projects/%d/webhook/policies", 1)
+		resp, err := suite.PostJSON(url, &models.WebhookPolicy{EventTypes: []string{"GC_COMPLETED"}, Targets: []*models.WebhookTargetObject{{Type: "http", Address: "http://127.0.0.1"}}})
+		suite.NoError(err)
+		suite.Equal(400, resp.StatusCode)
+	}
+
+	{
+		// invalid event type should got 400
+		resp, err := suite.PostJSON("/system/webhook/policies", &models.WebhookPolicy{EventTypes: []string{"INVALID"}})
+		suite.NoError(err)
+		suite.Equal(400, resp.StatusCode)
+	}
+
+	{
+		// valid policy should got 201
+		resp, err := suite.PostJSON("/system/webhook/policies", &models.WebhookPolicy{EventTypes: []string{"GC_COMPLETED", "PUSH_ARTIFACT"}, Targets: []*models.WebhookTargetObject{{Type: "http", Address: "http://127.0.0.1"}}})
+		suite.NoError(err)
+		suite.Equal(201, resp.StatusCode)
+	}
+}
+
+func (suite *WebhookTestSuite) TestGetSupportedSystemEventTypes() {
+	suite.Security.On("Can", mock.Anything, mock.Anything, mock.Anything).Return(true)
+	var body *models.SupportedWebhookEventTypes
+	resp, err := suite.GetJSON("/system/webhook/events", &body)
+	suite.NoError(err)
+	suite.Equal(200, resp.StatusCode)
+	suite.Len(body.EventType, len(notification.GetSupportedSystemEventTypes()))
+	suite.Len(body.NotifyType, len(notification.GetSupportedNotifyTypes()))
+}
+
 func TestWebhookTestSuite(t *testing.T) {
 	suite.Run(t, &WebhookTestSuite{})
 }
@@ -15,7 +15,10 @@
 package clients
 
 import (
+	"net/http"
+
 	modelsv2 "github.com/goharbor/harbor/src/controller/artifact"
+	"github.com/goharbor/harbor/src/pkg/clients/core"
 )
 
 // DumbCoreClient provides an empty implement for pkg/clients/core.Client
@@ -23,7 +26,7 @@ import (
 type DumbCoreClient struct{}
 
 // ListAllArtifacts ...
-func (d *DumbCoreClient) ListAllArtifacts(project, repository string) ([]*modelsv2.Artifact, error) {
+func (d *DumbCoreClient) ListAllArtifacts(project, repository string, option *core.ListArtifactsOption) ([]*modelsv2.Artifact, error) {
 	return nil, nil
 }
 
@@ -32,8 +35,18 @@ func (d *DumbCoreClient) DeleteArtifact(project, repository, digest string) erro
 	return nil
 }
 
+// CopyArtifact ...
+func (d *DumbCoreClient) CopyArtifact(project, repository, from string) error {
+	return nil
+}
+
 // DeleteArtifactRepository ...
 func (d *DumbCoreClient) DeleteArtifactRepository(project, repository string) error {
 	return nil
 
 }
+
+// SignWebhookPayload ...
+func (d *DumbCoreClient) SignWebhookPayload(policyID int64, payload []byte) (http.Header, error) {
+	return nil, nil
+}
@@ -5,6 +5,7 @@ package replication
 import (
 	context "context"
 
+	flow "github.com/goharbor/harbor/src/controller/replication/flow"
 	model "github.com/goharbor/harbor/src/controller/replication/model"
 	mock "github.com/stretchr/testify/mock"
 
@@ -94,6 +95,36 @@ func (_m *Controller) ExecutionCount(ctx context.Context, query *q.Query) (int64
 	return r0, r1
 }
 
+// GetDryRunReport provides a mock function with given fields: ctx, executionID
+func (_m *Controller) GetDryRunReport(ctx context.Context, executionID int64) (*flow.DryRunReport, error) {
+	ret := _m.Called(ctx, executionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDryRunReport")
+	}
+
+	var r0 *flow.DryRunReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*flow.DryRunReport, error)); ok {
+		return rf(ctx, executionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *flow.DryRunReport); ok {
+		r0 = rf(ctx, executionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.DryRunReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, executionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetExecution provides a mock function with given fields: ctx, executionID
 func (_m *Controller) GetExecution(ctx context.Context, executionID int64) (*replication.Execution, error) {
 	ret := _m.Called(ctx, executionID)
@@ -360,6 +391,34 @@ func (_m *Controller) Start(ctx context.Context, policy *model.Policy, resource
 	return r0, r1
 }
 
+// StartDryRun provides a mock function with given fields: ctx, policy
+func (_m *Controller) StartDryRun(ctx context.Context, policy *model.Policy) (int64, error) {
+	ret := _m.Called(ctx, policy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartDryRun")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy) (int64, error)); ok {
+		return rf(ctx, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy) int64); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Policy) error); ok {
+		r1 = rf(ctx, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Stop provides a mock function with given fields: ctx, executionID
 func (_m *Controller) Stop(ctx context.Context, executionID int64) error {
 	ret := _m.Called(ctx, executionID)
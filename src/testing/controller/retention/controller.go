@@ -8,6 +8,8 @@ import (
 	pkgretention "github.com/goharbor/harbor/src/pkg/retention"
 	mock "github.com/stretchr/testify/mock"
 
+	model "github.com/goharbor/harbor/src/pkg/immutable/model"
+
 	policy "github.com/goharbor/harbor/src/pkg/retention/policy"
 
 	q "github.com/goharbor/harbor/src/lib/q"
@@ -336,6 +338,80 @@ func (_m *Controller) OperateRetentionExec(ctx context.Context, eid int64, actio
 	return r0
 }
 
+// SimulateImmutable provides a mock function with given fields: ctx, projectID, rules, query
+func (_m *Controller) SimulateImmutable(ctx context.Context, projectID int64, rules []*model.Metadata, query *q.Query) (int64, []*pkgretention.SimulationReport, error) {
+	ret := _m.Called(ctx, projectID, rules, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateImmutable")
+	}
+
+	var r0 int64
+	var r1 []*pkgretention.SimulationReport
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*model.Metadata, *q.Query) (int64, []*pkgretention.SimulationReport, error)); ok {
+		return rf(ctx, projectID, rules, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []*model.Metadata, *q.Query) int64); ok {
+		r0 = rf(ctx, projectID, rules, query)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []*model.Metadata, *q.Query) []*pkgretention.SimulationReport); ok {
+		r1 = rf(ctx, projectID, rules, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*pkgretention.SimulationReport)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, []*model.Metadata, *q.Query) error); ok {
+		r2 = rf(ctx, projectID, rules, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SimulateRetention provides a mock function with given fields: ctx, p, query
+func (_m *Controller) SimulateRetention(ctx context.Context, p *policy.Metadata, query *q.Query) (int64, []*pkgretention.SimulationReport, error) {
+	ret := _m.Called(ctx, p, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SimulateRetention")
+	}
+
+	var r0 int64
+	var r1 []*pkgretention.SimulationReport
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *policy.Metadata, *q.Query) (int64, []*pkgretention.SimulationReport, error)); ok {
+		return rf(ctx, p, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *policy.Metadata, *q.Query) int64); ok {
+		r0 = rf(ctx, p, query)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *policy.Metadata, *q.Query) []*pkgretention.SimulationReport); ok {
+		r1 = rf(ctx, p, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]*pkgretention.SimulationReport)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *policy.Metadata, *q.Query) error); ok {
+		r2 = rf(ctx, p, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // TriggerRetentionExec provides a mock function with given fields: ctx, policyID, trigger, dryRun
 func (_m *Controller) TriggerRetentionExec(ctx context.Context, policyID int64, trigger string, dryRun bool) (int64, error) {
 	ret := _m.Called(ctx, policyID, trigger, dryRun)
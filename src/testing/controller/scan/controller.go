@@ -14,6 +14,8 @@ import (
 	models "github.com/goharbor/harbor/src/pkg/allowlist/models"
 
 	scan "github.com/goharbor/harbor/src/pkg/scan/dao/scan"
+
+	time "time"
 )
 
 // Controller is an autogenerated mock type for the Controller type
@@ -81,6 +83,36 @@ func (_m *Controller) GetScanLog(ctx context.Context, art *artifact.Artifact, uu
 	return r0, r1
 }
 
+// GetScannerReport provides a mock function with given fields: ctx, _a1, registrationUUID, mimeTypes
+func (_m *Controller) GetScannerReport(ctx context.Context, _a1 *artifact.Artifact, registrationUUID string, mimeTypes []string) ([]*scan.Report, error) {
+	ret := _m.Called(ctx, _a1, registrationUUID, mimeTypes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScannerReport")
+	}
+
+	var r0 []*scan.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *artifact.Artifact, string, []string) ([]*scan.Report, error)); ok {
+		return rf(ctx, _a1, registrationUUID, mimeTypes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *artifact.Artifact, string, []string) []*scan.Report); ok {
+		r0 = rf(ctx, _a1, registrationUUID, mimeTypes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*scan.Report)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *artifact.Artifact, string, []string) error); ok {
+		r1 = rf(ctx, _a1, registrationUUID, mimeTypes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSummary provides a mock function with given fields: ctx, _a1, scanType, mimeTypes
 func (_m *Controller) GetSummary(ctx context.Context, _a1 *artifact.Artifact, scanType string, mimeTypes []string) (map[string]interface{}, error) {
 	ret := _m.Called(ctx, _a1, scanType, mimeTypes)
@@ -141,6 +173,34 @@ func (_m *Controller) GetVulnerable(ctx context.Context, _a1 *artifact.Artifact,
 	return r0, r1
 }
 
+// RescanOnDBUpdate provides a mock function with given fields: ctx, dailyLimit, pulledSince
+func (_m *Controller) RescanOnDBUpdate(ctx context.Context, dailyLimit int, pulledSince time.Time) (int, error) {
+	ret := _m.Called(ctx, dailyLimit, pulledSince)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RescanOnDBUpdate")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (int, error)); ok {
+		return rf(ctx, dailyLimit, pulledSince)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) int); ok {
+		r0 = rf(ctx, dailyLimit, pulledSince)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, dailyLimit, pulledSince)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Scan provides a mock function with given fields: ctx, _a1, options
 func (_m *Controller) Scan(ctx context.Context, _a1 *artifact.Artifact, options ...controllerscan.Option) error {
 	_va := make([]interface{}, len(options))
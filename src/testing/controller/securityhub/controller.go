@@ -18,9 +18,9 @@ type Controller struct {
 	mock.Mock
 }
 
-// CountVuls provides a mock function with given fields: ctx, scannerUUID, projectID, tuneCount, query
-func (_m *Controller) CountVuls(ctx context.Context, scannerUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, tuneCount, query)
+// CountVuls provides a mock function with given fields: ctx, projectID, tuneCount, query
+func (_m *Controller) CountVuls(ctx context.Context, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
+	ret := _m.Called(ctx, projectID, tuneCount, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CountVuls")
@@ -28,17 +28,17 @@ func (_m *Controller) CountVuls(ctx context.Context, scannerUUID string, project
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool, *q.Query) (int64, error)); ok {
-		return rf(ctx, scannerUUID, projectID, tuneCount, query)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool, *q.Query) (int64, error)); ok {
+		return rf(ctx, projectID, tuneCount, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool, *q.Query) int64); ok {
-		r0 = rf(ctx, scannerUUID, projectID, tuneCount, query)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool, *q.Query) int64); ok {
+		r0 = rf(ctx, projectID, tuneCount, query)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, bool, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, tuneCount, query)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, bool, *q.Query) error); ok {
+		r1 = rf(ctx, projectID, tuneCount, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,9 +46,9 @@ func (_m *Controller) CountVuls(ctx context.Context, scannerUUID string, project
 	return r0, r1
 }
 
-// ListVuls provides a mock function with given fields: ctx, scannerUUID, projectID, withTag, query
-func (_m *Controller) ListVuls(ctx context.Context, scannerUUID string, projectID int64, withTag bool, query *q.Query) ([]*model.VulnerabilityItem, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, withTag, query)
+// ListVuls provides a mock function with given fields: ctx, projectID, withTag, query
+func (_m *Controller) ListVuls(ctx context.Context, projectID int64, withTag bool, query *q.Query) ([]*model.VulnerabilityItem, error) {
+	ret := _m.Called(ctx, projectID, withTag, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListVuls")
@@ -56,19 +56,19 @@ func (_m *Controller) ListVuls(ctx context.Context, scannerUUID string, projectI
 
 	var r0 []*model.VulnerabilityItem
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool, *q.Query) ([]*model.VulnerabilityItem, error)); ok {
-		return rf(ctx, scannerUUID, projectID, withTag, query)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool, *q.Query) ([]*model.VulnerabilityItem, error)); ok {
+		return rf(ctx, projectID, withTag, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool, *q.Query) []*model.VulnerabilityItem); ok {
-		r0 = rf(ctx, scannerUUID, projectID, withTag, query)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool, *q.Query) []*model.VulnerabilityItem); ok {
+		r0 = rf(ctx, projectID, withTag, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.VulnerabilityItem)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, bool, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, withTag, query)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, bool, *q.Query) error); ok {
+		r1 = rf(ctx, projectID, withTag, query)
 	} else {
 		r1 = ret.Error(1)
 	}
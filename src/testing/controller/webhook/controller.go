@@ -150,6 +150,24 @@ func (_m *Controller) DeletePolicy(ctx context.Context, policyID int64) error {
 	return r0
 }
 
+// DisableSigning provides a mock function with given fields: ctx, policyID
+func (_m *Controller) DisableSigning(ctx context.Context, policyID int64) error {
+	ret := _m.Called(ctx, policyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableSigning")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, policyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetLastTriggerTime provides a mock function with given fields: ctx, eventType, policyID
 func (_m *Controller) GetLastTriggerTime(ctx context.Context, eventType string, policyID int64) (time.Time, error) {
 	ret := _m.Called(ctx, eventType, policyID)
@@ -388,6 +406,62 @@ func (_m *Controller) ListTasks(ctx context.Context, execID int64, query *q.Quer
 	return r0, r1
 }
 
+// RedeliverExecution provides a mock function with given fields: ctx, policyID, execID
+func (_m *Controller) RedeliverExecution(ctx context.Context, policyID int64, execID int64) (int64, error) {
+	ret := _m.Called(ctx, policyID, execID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeliverExecution")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (int64, error)); ok {
+		return rf(ctx, policyID, execID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int64); ok {
+		r0 = rf(ctx, policyID, execID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, policyID, execID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateSigningSecret provides a mock function with given fields: ctx, policyID, overlap
+func (_m *Controller) RotateSigningSecret(ctx context.Context, policyID int64, overlap time.Duration) (string, error) {
+	ret := _m.Called(ctx, policyID, overlap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateSigningSecret")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Duration) (string, error)); ok {
+		return rf(ctx, policyID, overlap)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Duration) string); ok {
+		r0 = rf(ctx, policyID, overlap)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Duration) error); ok {
+		r1 = rf(ctx, policyID, overlap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdatePolicy provides a mock function with given fields: ctx, policy
 func (_m *Controller) UpdatePolicy(ctx context.Context, policy *model.Policy) error {
 	ret := _m.Called(ctx, policy)
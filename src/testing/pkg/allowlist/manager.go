@@ -92,6 +92,36 @@ func (_m *Manager) GetSys(ctx context.Context) (*models.CVEAllowlist, error) {
 	return r0, r1
 }
 
+// List provides a mock function with given fields: ctx
+func (_m *Manager) List(ctx context.Context) ([]*models.CVEAllowlist, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*models.CVEAllowlist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*models.CVEAllowlist, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.CVEAllowlist); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.CVEAllowlist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Set provides a mock function with given fields: ctx, projectID, list
 func (_m *Manager) Set(ctx context.Context, projectID int64, list models.CVEAllowlist) error {
 	ret := _m.Called(ctx, projectID, list)
@@ -151,17 +151,24 @@ func (_m *DAO) List(ctx context.Context, query *q.Query) ([]*model.Policy, error
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, n
-func (_m *DAO) Update(ctx context.Context, n *model.Policy) error {
-	ret := _m.Called(ctx, n)
+// Update provides a mock function with given fields: ctx, n, props
+func (_m *DAO) Update(ctx context.Context, n *model.Policy, props ...string) error {
+	_va := make([]interface{}, len(props))
+	for _i := range props {
+		_va[_i] = props[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, n)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy) error); ok {
-		r0 = rf(ctx, n)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Policy, ...string) error); ok {
+		r0 = rf(ctx, n, props...)
 	} else {
 		r0 = ret.Error(0)
 	}
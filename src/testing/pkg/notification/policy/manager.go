@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	q "github.com/goharbor/harbor/src/lib/q"
+
+	time "time"
 )
 
 // Manager is an autogenerated mock type for the Manager type
@@ -150,6 +152,36 @@ func (_m *Manager) GetRelatedPolices(ctx context.Context, projectID int64, event
 	return r0, r1
 }
 
+// GetSigningSecrets provides a mock function with given fields: ctx, policyID
+func (_m *Manager) GetSigningSecrets(ctx context.Context, policyID int64) ([]string, error) {
+	ret := _m.Called(ctx, policyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSigningSecrets")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]string, error)); ok {
+		return rf(ctx, policyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []string); ok {
+		r0 = rf(ctx, policyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, policyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // List provides a mock function with given fields: ctx, query
 func (_m *Manager) List(ctx context.Context, query *q.Query) ([]*model.Policy, error) {
 	ret := _m.Called(ctx, query)
@@ -180,6 +212,24 @@ func (_m *Manager) List(ctx context.Context, query *q.Query) ([]*model.Policy, e
 	return r0, r1
 }
 
+// RotateSigningSecret provides a mock function with given fields: ctx, policyID, secret, overlap
+func (_m *Manager) RotateSigningSecret(ctx context.Context, policyID int64, secret string, overlap time.Duration) error {
+	ret := _m.Called(ctx, policyID, secret, overlap)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateSigningSecret")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Duration) error); ok {
+		r0 = rf(ctx, policyID, secret, overlap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, _a1
 func (_m *Manager) Update(ctx context.Context, _a1 *model.Policy) error {
 	ret := _m.Called(ctx, _a1)
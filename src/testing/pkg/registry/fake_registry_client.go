@@ -10,6 +10,8 @@ import (
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
+
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -159,6 +161,36 @@ func (_m *Client) Do(req *http.Request) (*http.Response, error) {
 	return r0, r1
 }
 
+// ListReferrers provides a mock function with given fields: repository, digest
+func (_m *Client) ListReferrers(repository string, digest string) ([]v1.Descriptor, error) {
+	ret := _m.Called(repository, digest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListReferrers")
+	}
+
+	var r0 []v1.Descriptor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]v1.Descriptor, error)); ok {
+		return rf(repository, digest)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []v1.Descriptor); ok {
+		r0 = rf(repository, digest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1.Descriptor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(repository, digest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListTags provides a mock function with given fields: repository
 func (_m *Client) ListTags(repository string) ([]string, error) {
 	ret := _m.Called(repository)
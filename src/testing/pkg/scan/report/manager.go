@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	scan "github.com/goharbor/harbor/src/pkg/scan/dao/scan"
+
+	time "time"
 )
 
 // Manager is an autogenerated mock type for the Manager type
@@ -165,6 +167,36 @@ func (_m *Manager) List(ctx context.Context, query *q.Query) ([]*scan.Report, er
 	return r0, r1
 }
 
+// ListStaleArtifactIDs provides a mock function with given fields: ctx, registrationUUID, scannedBefore, pulledSince, limit
+func (_m *Manager) ListStaleArtifactIDs(ctx context.Context, registrationUUID string, scannedBefore time.Time, pulledSince time.Time, limit int) ([]int64, error) {
+	ret := _m.Called(ctx, registrationUUID, scannedBefore, pulledSince, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStaleArtifactIDs")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) ([]int64, error)); ok {
+		return rf(ctx, registrationUUID, scannedBefore, pulledSince, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time, int) []int64); ok {
+		r0 = rf(ctx, registrationUUID, scannedBefore, pulledSince, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time, int) error); ok {
+		r1 = rf(ctx, registrationUUID, scannedBefore, pulledSince, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, r, cols
 func (_m *Manager) Update(ctx context.Context, r *scan.Report, cols ...string) error {
 	_va := make([]interface{}, len(cols))
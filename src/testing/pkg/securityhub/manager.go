@@ -18,9 +18,9 @@ type Manager struct {
 	mock.Mock
 }
 
-// DangerousArtifacts provides a mock function with given fields: ctx, scannerUUID, projectID, query
-func (_m *Manager) DangerousArtifacts(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, query)
+// DangerousArtifacts provides a mock function with given fields: ctx, scannerUUIDs, projectID, query
+func (_m *Manager) DangerousArtifacts(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.DangerousArtifact, error) {
+	ret := _m.Called(ctx, scannerUUIDs, projectID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DangerousArtifacts")
@@ -28,19 +28,19 @@ func (_m *Manager) DangerousArtifacts(ctx context.Context, scannerUUID string, p
 
 	var r0 []*model.DangerousArtifact
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) ([]*model.DangerousArtifact, error)); ok {
-		return rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) ([]*model.DangerousArtifact, error)); ok {
+		return rf(ctx, scannerUUIDs, projectID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) []*model.DangerousArtifact); ok {
-		r0 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) []*model.DangerousArtifact); ok {
+		r0 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.DangerousArtifact)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, *q.Query) error); ok {
+		r1 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -48,9 +48,9 @@ func (_m *Manager) DangerousArtifacts(ctx context.Context, scannerUUID string, p
 	return r0, r1
 }
 
-// DangerousCVEs provides a mock function with given fields: ctx, scannerUUID, projectID, query
-func (_m *Manager) DangerousCVEs(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, query)
+// DangerousCVEs provides a mock function with given fields: ctx, scannerUUIDs, projectID, query
+func (_m *Manager) DangerousCVEs(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*scan.VulnerabilityRecord, error) {
+	ret := _m.Called(ctx, scannerUUIDs, projectID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DangerousCVEs")
@@ -58,19 +58,19 @@ func (_m *Manager) DangerousCVEs(ctx context.Context, scannerUUID string, projec
 
 	var r0 []*scan.VulnerabilityRecord
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) ([]*scan.VulnerabilityRecord, error)); ok {
-		return rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) ([]*scan.VulnerabilityRecord, error)); ok {
+		return rf(ctx, scannerUUIDs, projectID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) []*scan.VulnerabilityRecord); ok {
-		r0 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) []*scan.VulnerabilityRecord); ok {
+		r0 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*scan.VulnerabilityRecord)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, *q.Query) error); ok {
+		r1 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -78,9 +78,9 @@ func (_m *Manager) DangerousCVEs(ctx context.Context, scannerUUID string, projec
 	return r0, r1
 }
 
-// ListVuls provides a mock function with given fields: ctx, scannerUUID, projectID, query
-func (_m *Manager) ListVuls(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, query)
+// ListVuls provides a mock function with given fields: ctx, scannerUUIDs, projectID, query
+func (_m *Manager) ListVuls(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) ([]*model.VulnerabilityItem, error) {
+	ret := _m.Called(ctx, scannerUUIDs, projectID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListVuls")
@@ -88,19 +88,19 @@ func (_m *Manager) ListVuls(ctx context.Context, scannerUUID string, projectID i
 
 	var r0 []*model.VulnerabilityItem
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) ([]*model.VulnerabilityItem, error)); ok {
-		return rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) ([]*model.VulnerabilityItem, error)); ok {
+		return rf(ctx, scannerUUIDs, projectID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) []*model.VulnerabilityItem); ok {
-		r0 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) []*model.VulnerabilityItem); ok {
+		r0 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.VulnerabilityItem)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, *q.Query) error); ok {
+		r1 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,9 +108,9 @@ func (_m *Manager) ListVuls(ctx context.Context, scannerUUID string, projectID i
 	return r0, r1
 }
 
-// ScannedArtifactsCount provides a mock function with given fields: ctx, scannerUUID, projectID, query
-func (_m *Manager) ScannedArtifactsCount(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (int64, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, query)
+// ScannedArtifactsCount provides a mock function with given fields: ctx, scannerUUIDs, projectID, query
+func (_m *Manager) ScannedArtifactsCount(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (int64, error) {
+	ret := _m.Called(ctx, scannerUUIDs, projectID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ScannedArtifactsCount")
@@ -118,17 +118,17 @@ func (_m *Manager) ScannedArtifactsCount(ctx context.Context, scannerUUID string
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) (int64, error)); ok {
-		return rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) (int64, error)); ok {
+		return rf(ctx, scannerUUIDs, projectID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) int64); ok {
-		r0 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) int64); ok {
+		r0 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, *q.Query) error); ok {
+		r1 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -136,9 +136,9 @@ func (_m *Manager) ScannedArtifactsCount(ctx context.Context, scannerUUID string
 	return r0, r1
 }
 
-// Summary provides a mock function with given fields: ctx, scannerUUID, projectID, query
-func (_m *Manager) Summary(ctx context.Context, scannerUUID string, projectID int64, query *q.Query) (*model.Summary, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, query)
+// Summary provides a mock function with given fields: ctx, scannerUUIDs, projectID, query
+func (_m *Manager) Summary(ctx context.Context, scannerUUIDs []string, projectID int64, query *q.Query) (*model.Summary, error) {
+	ret := _m.Called(ctx, scannerUUIDs, projectID, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Summary")
@@ -146,19 +146,19 @@ func (_m *Manager) Summary(ctx context.Context, scannerUUID string, projectID in
 
 	var r0 *model.Summary
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) (*model.Summary, error)); ok {
-		return rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) (*model.Summary, error)); ok {
+		return rf(ctx, scannerUUIDs, projectID, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *q.Query) *model.Summary); ok {
-		r0 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, *q.Query) *model.Summary); ok {
+		r0 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Summary)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, query)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, *q.Query) error); ok {
+		r1 = rf(ctx, scannerUUIDs, projectID, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -194,9 +194,9 @@ func (_m *Manager) TotalArtifactsCount(ctx context.Context, projectID int64) (in
 	return r0, r1
 }
 
-// TotalVuls provides a mock function with given fields: ctx, scannerUUID, projectID, tuneCount, query
-func (_m *Manager) TotalVuls(ctx context.Context, scannerUUID string, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
-	ret := _m.Called(ctx, scannerUUID, projectID, tuneCount, query)
+// TotalVuls provides a mock function with given fields: ctx, scannerUUIDs, projectID, tuneCount, query
+func (_m *Manager) TotalVuls(ctx context.Context, scannerUUIDs []string, projectID int64, tuneCount bool, query *q.Query) (int64, error) {
+	ret := _m.Called(ctx, scannerUUIDs, projectID, tuneCount, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TotalVuls")
@@ -204,17 +204,17 @@ func (_m *Manager) TotalVuls(ctx context.Context, scannerUUID string, projectID
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool, *q.Query) (int64, error)); ok {
-		return rf(ctx, scannerUUID, projectID, tuneCount, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, bool, *q.Query) (int64, error)); ok {
+		return rf(ctx, scannerUUIDs, projectID, tuneCount, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool, *q.Query) int64); ok {
-		r0 = rf(ctx, scannerUUID, projectID, tuneCount, query)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int64, bool, *q.Query) int64); ok {
+		r0 = rf(ctx, scannerUUIDs, projectID, tuneCount, query)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64, bool, *q.Query) error); ok {
-		r1 = rf(ctx, scannerUUID, projectID, tuneCount, query)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int64, bool, *q.Query) error); ok {
+		r1 = rf(ctx, scannerUUIDs, projectID, tuneCount, query)
 	} else {
 		r1 = ret.Error(1)
 	}